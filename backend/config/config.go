@@ -1,26 +1,68 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// MinJWTSecretBytes is the shortest signing secret accepted for HS256 tokens.
+const MinJWTSecretBytes = 32
+
+// DefaultBcryptCost is the bcrypt cost used when BCRYPT_COST is not set.
+const DefaultBcryptCost = 12
+
 type Config struct {
 	Port              string
 	DatabaseURL       string
 	StellarNetwork    string
 	HorizonURL        string
+	SorobanRPCURL     string
 	ContractID        string
 	EscrowContractID  string
 	NetworkPassphrase string
 	JWTSecret         string
 	JWTRefreshSecret  string
 
+	// Token lifetimes, set as Go durations (e.g. "15m", "168h").
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// JWTClockSkew is the leeway allowed when checking token exp, iat, and nbf
+	// claims, absorbing clock drift between issuers and validators.
+	JWTClockSkew time.Duration
+	// BcryptCost is the cost new password hashes are made with. Stored
+	// hashes below it are upgraded on the user's next successful login.
+	BcryptCost int
+	// LoginMaxAttempts is how many wrong passwords in a row lock an account
+	// for LoginLockoutDuration. Zero disables lockout.
+	LoginMaxAttempts     int
+	LoginLockoutDuration time.Duration
+	// PasswordResetTTL is how long a password reset token stays usable.
+	PasswordResetTTL time.Duration
+	// PasswordResetURL is the page password reset emails link to, with the
+	// token appended as the "token" query parameter.
+	PasswordResetURL string
+	// RequireEmailVerification refuses remittance sends from users who have
+	// not confirmed their email address.
+	RequireEmailVerification bool
+	// EmailVerificationTTL is how long an email verification link stays
+	// usable.
+	EmailVerificationTTL time.Duration
+	// EmailVerificationURL is the link verification emails carry, with the
+	// token appended as the "token" query parameter. It defaults to the
+	// API's own GET /auth/verify.
+	EmailVerificationURL string
+
 	// Fee configuration (basis points, i.e. 100 bps = 1%)
 	//
 	// NOTE: These values are intended to mirror the fee structure configured in
@@ -33,6 +75,65 @@ type Config struct {
 	NetworkFeeBps    int
 	MinFee           float64
 	MaxFee           float64
+	// MinFeeMaxRatio rejects remittances whose minimum fee would exceed this
+	// fraction of the amount (e.g. 0.5 = 50%). Zero disables the check.
+	MinFeeMaxRatio float64
+	// Corridors prices remittances by the sender's and recipient's
+	// countries, keyed "FROM-TO" with upper-case country codes. A corridor's
+	// charges are added to the fees above; unlisted corridors pay those alone.
+	Corridors map[string]Corridor
+
+	// KYCThreshold is the largest remittance amount a sender who has not
+	// passed KYC may create. Zero disables the check.
+	KYCThreshold float64
+	// TwoFactorThreshold is the largest remittance amount that may be sent
+	// without a TOTP code in the X-2FA-Code header. TwoFactorThresholds
+	// overrides it per upper-case currency code. Zero disables the check.
+	TwoFactorThreshold  float64
+	TwoFactorThresholds map[string]float64
+
+	// DailyLimits caps how much a user may send per UTC day, keyed by
+	// upper-case currency code. DailyLimitsByTier overrides it per KYC tier
+	// name. A currency with no limit is uncapped.
+	DailyLimits       map[string]float64
+	DailyLimitsByTier map[string]map[string]float64
+
+	// MinAmounts and MaxAmounts bound a single remittance, keyed by
+	// upper-case currency code. A currency with no entry is unbounded on
+	// that side.
+	MinAmounts map[string]float64
+	MaxAmounts map[string]float64
+
+	// Abuse detection: an IP failing logins against AbuseIPFailedLoginAccounts
+	// distinct accounts, an account failing AbuseAccountFailedLogins times, or
+	// an IP creating AbuseIPRegistrations accounts within AbuseWindow is banned
+	// for AbuseBanDuration. A zero threshold disables that heuristic.
+	AbuseIPFailedLoginAccounts int
+	AbuseAccountFailedLogins   int
+	AbuseIPRegistrations       int
+	AbuseWindow                time.Duration
+	AbuseBanDuration           time.Duration
+
+	// CORS: browser origins allowed to call the API, with credentials. Only
+	// an allowlist of exactly "*" admits any origin, and then without
+	// credentials. Preflight requests are answered with the configured
+	// methods and headers, cacheable for CORSMaxAge.
+	CORSAllowedOrigins []string
+	CORSAllowedMethods []string
+	CORSAllowedHeaders []string
+	CORSMaxAge         time.Duration
+
+	// FieldEncryptionKeys are the AES-256 keys, by version, that encrypt
+	// payment notes and conditions at rest; none stores them as plaintext.
+	// New values use FieldEncryptionKeyVersion, and older versions are kept
+	// so existing values still decrypt after a rotation.
+	FieldEncryptionKeys       map[int][]byte
+	FieldEncryptionKeyVersion int
+	// FieldEncryptionBackfill re-encrypts plaintext and old-version values
+	// with the current key once at startup, FieldEncryptionBackfillBatch
+	// rows at a time.
+	FieldEncryptionBackfill      bool
+	FieldEncryptionBackfillBatch int
 
 	// Database connection pool settings
 	DBMaxIdleConns    int
@@ -46,21 +147,307 @@ type Config struct {
 	SMTPPassword string
 	SMTPFrom     string
 	EmailEnabled bool
+
+	// Background worker intervals
+	InvoiceOverdueCheckInterval time.Duration
+	ConditionSweepInterval      time.Duration
+	RetentionPurgeInterval      time.Duration
+	PendingExpiryInterval       time.Duration
+	// RecurringSchedulerInterval is how often due recurring remittances are
+	// fired. Zero disables the scheduler.
+	RecurringSchedulerInterval time.Duration
+
+	// PendingExpiryAge is how long a remittance may stay pending (never
+	// submitted) before it is expired. Zero disables expiry.
+	PendingExpiryAge time.Duration
+
+	// Settlement polling confirms processing remittances on-ledger. A
+	// transaction still unknown after SettlementMaxChecks checks, spaced from
+	// SettlementBackoff and doubling, is flagged for manual review. A zero
+	// interval disables polling.
+	SettlementPollInterval time.Duration
+	SettlementMaxChecks    int
+	SettlementBackoff      time.Duration
+
+	// PaymentRetention is how long terminal payments keep their personal
+	// data before the purge worker anonymizes them. Zero disables purging.
+	PaymentRetention time.Duration
+
+	// WebhookDeliveryRetention is how long successful webhook deliveries are
+	// kept before the cleanup worker deletes them; zero disables cleanup.
+	// Undelivered ones are never deleted, and are flagged and alerted on once
+	// older than WebhookDeliveryAlertAge.
+	WebhookDeliveryRetention time.Duration
+	WebhookDeliveryAlertAge  time.Duration
+
+	// FXRates are fixed rates keyed by "BASE/QUOTE", used to evaluate
+	// rate-based release conditions.
+	FXRates map[string]float64
+
+	// SettlementAccounts maps an upper-case currency/asset code to the
+	// platform account that holds escrowed funds and pays out in it. When
+	// any are configured, currencies without an entry are rejected.
+	SettlementAccounts map[string]string
+
+	// ClaimableBalances makes CreateRemittance send funds into a claimable
+	// balance the recipient claims, instead of building an escrow payment.
+	// Settlement accounts are not used in this mode. A recipient who has not
+	// claimed within ClaimableReclaimWindow of the release time loses the
+	// balance back to the sender.
+	ClaimableBalances      bool
+	ClaimableReclaimWindow time.Duration
+
+	// SubmitSourceAllowlist, when non-empty, lists the only accounts a
+	// submitted transaction (or any of its operations) may be sourced from.
+	SubmitSourceAllowlist []string
+
+	// AssetIssuerAllowlist and AssetIssuerDenylist restrict the issuers whose
+	// credit assets may be remitted. An empty allowlist admits any issuer not
+	// on the denylist; XLM is always admitted.
+	AssetIssuerAllowlist []string
+	AssetIssuerDenylist  []string
+
+	// SupportedCurrencies, when non-empty, lists the only currencies and
+	// assets remittances and invoices may be denominated in.
+	SupportedCurrencies []SupportedCurrency
+
+	// SigningCallbackSecret authenticates external custody systems calling
+	// POST /internal/signing-callback. Empty disables the endpoint.
+	SigningCallbackSecret string
+
+	// Transaction submission: the longest a client may block on ?wait= and
+	// how often Horizon is polled while waiting.
+	SubmitMaxWait      time.Duration
+	SubmitPollInterval time.Duration
+
+	// StreamHeartbeatInterval is how often GET /remittances/:id/stream sends
+	// a comment to keep idle connections open through proxies.
+	StreamHeartbeatInterval time.Duration
+
+	// StrictCompletion makes CompleteRemittance confirm the payment's
+	// transaction succeeded on Horizon, not just that one was submitted.
+	StrictCompletion bool
+
+	// CheckRecipientTrustline warns on remittance creation when the recipient
+	// has no trustline for the credit asset being sent.
+	CheckRecipientTrustline bool
+
+	// BaseReserveXLM overrides the network base reserve read from Horizon.
+	// Zero means use the live value.
+	BaseReserveXLM float64
+
+	// BaseFee is the per-operation fee, in stroops, of the transactions the
+	// API builds. Requests may raise it up to MaxBaseFee to get past surge
+	// pricing.
+	BaseFee    int64
+	MaxBaseFee int64
+
+	// Account validation results are cached per account for AccountCacheTTL
+	// (failures for AccountCacheFailureTTL), holding at most AccountCacheSize
+	// accounts. A zero TTL disables the cache.
+	AccountCacheTTL        time.Duration
+	AccountCacheFailureTTL time.Duration
+	AccountCacheSize       int
+
+	// Each Horizon request times out after HorizonTimeout. Idempotent reads
+	// that fail with a 5xx or timeout are retried up to HorizonReadRetries
+	// times, backing off from HorizonRetryBackoff; submissions never are.
+	HorizonTimeout      time.Duration
+	HorizonReadRetries  int
+	HorizonRetryBackoff time.Duration
+
+	// VerifyStellarNetwork makes startup fail unless Horizon reports serving
+	// NetworkPassphrase. Offline environments can turn it off.
+	VerifyStellarNetwork bool
+	// DryRun replaces the Stellar client with an offline fake that checks
+	// addresses and builds envelopes but never contacts Horizon, for local
+	// development and CI. Nothing is submitted and the network check is skipped.
+	DryRun bool
+}
+
+// StellarNetworkEndpoints are the endpoints and passphrase of a Stellar network.
+type StellarNetworkEndpoints struct {
+	HorizonURL        string
+	SorobanRPCURL     string
+	NetworkPassphrase string
+}
+
+// StellarNetworks are the networks STELLAR_NETWORK may name, with the
+// defaults each implies. SDF runs no public mainnet Soroban RPC, so mainnet
+// deployments must set SOROBAN_RPC_URL to use contracts.
+var StellarNetworks = map[string]StellarNetworkEndpoints{
+	"testnet": {
+		HorizonURL:        "https://horizon-testnet.stellar.org",
+		SorobanRPCURL:     "https://soroban-testnet.stellar.org",
+		NetworkPassphrase: network.TestNetworkPassphrase,
+	},
+	"mainnet": {
+		HorizonURL:        "https://horizon.stellar.org",
+		NetworkPassphrase: network.PublicNetworkPassphrase,
+	},
+	"futurenet": {
+		HorizonURL:        "https://horizon-futurenet.stellar.org",
+		SorobanRPCURL:     "https://rpc-futurenet.stellar.org",
+		NetworkPassphrase: network.FutureNetworkPassphrase,
+	},
+}
+
+// Corridor is the extra pricing of one sender-to-recipient country pair.
+type Corridor struct {
+	// FXMarkupBps is added to the forex fee, in basis points of the amount.
+	FXMarkupBps int `json:"fx_markup_bps"`
+	// FixedFee is added to the platform fee.
+	FixedFee float64 `json:"fixed_fee"`
+}
+
+// SupportedCurrency is a currency or asset code the platform accepts. An
+// empty Issuer accepts the code from any issuer; otherwise only that issuer's
+// asset is accepted.
+type SupportedCurrency struct {
+	Code   string `json:"code"`
+	Issuer string `json:"issuer,omitempty"`
 }
 
 func LoadConfig() (*Config, error) {
 	godotenv.Load()
 
+	jwtSecret := os.Getenv("JWT_SECRET")
+	jwtRefreshSecret := os.Getenv("JWT_REFRESH_SECRET")
+	if err := validateJWTSecrets(jwtSecret, jwtRefreshSecret); err != nil {
+		return nil, err
+	}
+
+	accessTokenTTL, err := getEnvAsDuration("ACCESS_TOKEN_TTL", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	refreshTokenTTL, err := getEnvAsDuration("REFRESH_TOKEN_TTL", 7*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	jwtClockSkew, err := getEnvAsDuration("JWT_CLOCK_SKEW", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	loginLockoutDuration, err := getEnvAsDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	passwordResetTTL, err := getEnvAsDuration("PASSWORD_RESET_TTL", 30*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	emailVerificationTTL, err := getEnvAsDuration("EMAIL_VERIFICATION_TTL", 48*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	bcryptCost := getEnvAsInt("BCRYPT_COST", DefaultBcryptCost)
+	if bcryptCost < bcrypt.MinCost || bcryptCost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("BCRYPT_COST must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	fxRates, err := parseRates(os.Getenv("FX_RATES"))
+	if err != nil {
+		return nil, err
+	}
+	settlementAccounts, err := parseSettlementAccounts(os.Getenv("SETTLEMENT_ACCOUNTS"))
+	if err != nil {
+		return nil, err
+	}
+	dailyLimits, err := parseLimits("DAILY_LIMITS", os.Getenv("DAILY_LIMITS"))
+	if err != nil {
+		return nil, err
+	}
+	twoFactorThresholds, err := parseLimits("TWO_FACTOR_THRESHOLDS", os.Getenv("TWO_FACTOR_THRESHOLDS"))
+	if err != nil {
+		return nil, err
+	}
+	corridors, err := parseCorridors(os.Getenv("CORRIDOR_PRICING"))
+	if err != nil {
+		return nil, err
+	}
+	minAmounts, err := parseLimits("MIN_AMOUNTS", os.Getenv("MIN_AMOUNTS"))
+	if err != nil {
+		return nil, err
+	}
+	maxAmounts, err := parseLimits("MAX_AMOUNTS", os.Getenv("MAX_AMOUNTS"))
+	if err != nil {
+		return nil, err
+	}
+	for code, lower := range minAmounts {
+		if upper, ok := maxAmounts[code]; ok && upper < lower {
+			return nil, fmt.Errorf("MIN_AMOUNTS for %s (%v) exceeds MAX_AMOUNTS (%v)", code, lower, upper)
+		}
+	}
+	dailyLimitsByTier := map[string]map[string]float64{}
+	for _, tier := range []string{"unverified", "verified"} {
+		key := "DAILY_LIMITS_" + strings.ToUpper(tier)
+		limits, err := parseLimits(key, os.Getenv(key))
+		if err != nil {
+			return nil, err
+		}
+		if len(limits) > 0 {
+			dailyLimitsByTier[tier] = limits
+		}
+	}
+	submitSources, err := parseAccountList("SUBMIT_SOURCE_ALLOWLIST", os.Getenv("SUBMIT_SOURCE_ALLOWLIST"))
+	if err != nil {
+		return nil, err
+	}
+	issuerAllowlist, err := parseAccountList("ASSET_ISSUER_ALLOWLIST", os.Getenv("ASSET_ISSUER_ALLOWLIST"))
+	if err != nil {
+		return nil, err
+	}
+	issuerDenylist, err := parseAccountList("ASSET_ISSUER_DENYLIST", os.Getenv("ASSET_ISSUER_DENYLIST"))
+	if err != nil {
+		return nil, err
+	}
+	supportedCurrencies, err := parseSupportedCurrencies(os.Getenv("SUPPORTED_CURRENCIES"))
+	if err != nil {
+		return nil, err
+	}
+	corsOrigins, err := parseOrigins(getEnvOrDefault("CORS_ALLOWED_ORIGINS", "http://localhost:3000"))
+	if err != nil {
+		return nil, err
+	}
+	encryptionKeys, latestKeyVersion, err := parseEncryptionKeys(os.Getenv("FIELD_ENCRYPTION_KEYS"))
+	if err != nil {
+		return nil, err
+	}
+
+	stellarNetwork := strings.ToLower(getEnvOrDefault("STELLAR_NETWORK", "testnet"))
+	endpoints, err := resolveStellarNetwork(stellarNetwork, StellarNetworkEndpoints{
+		HorizonURL:        os.Getenv("HORIZON_URL"),
+		SorobanRPCURL:     os.Getenv("SOROBAN_RPC_URL"),
+		NetworkPassphrase: os.Getenv("NETWORK_PASSPHRASE"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
 		Port:              os.Getenv("PORT"),
 		DatabaseURL:       os.Getenv("DATABASE_URL"),
-		StellarNetwork:    getEnvOrDefault("STELLAR_NETWORK", "testnet"),
-		HorizonURL:        getEnvOrDefault("HORIZON_URL", "https://horizon-testnet.stellar.org"),
+		StellarNetwork:    stellarNetwork,
+		HorizonURL:        endpoints.HorizonURL,
+		SorobanRPCURL:     endpoints.SorobanRPCURL,
 		ContractID:        os.Getenv("CONTRACT_ID"),
 		EscrowContractID:  os.Getenv("ESCROW_CONTRACT_ID"),
-		NetworkPassphrase: getEnvOrDefault("NETWORK_PASSPHRASE", "Test SDF Network ; September 2015"),
-		JWTSecret:         getEnvOrDefault("JWT_SECRET", "super-secret-key-change-me"),
-		JWTRefreshSecret:  getEnvOrDefault("JWT_REFRESH_SECRET", "super-secret-refresh-key-change-me"),
+		NetworkPassphrase: endpoints.NetworkPassphrase,
+		JWTSecret:         jwtSecret,
+		JWTRefreshSecret:  jwtRefreshSecret,
+		AccessTokenTTL:    accessTokenTTL,
+		RefreshTokenTTL:   refreshTokenTTL,
+		JWTClockSkew:      jwtClockSkew,
+		BcryptCost:        bcryptCost,
+		PasswordResetTTL:  passwordResetTTL,
+		PasswordResetURL:  getEnvOrDefault("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+
+		RequireEmailVerification: getEnvOrDefault("REQUIRE_EMAIL_VERIFICATION", "true") == "true",
+		EmailVerificationTTL:     emailVerificationTTL,
+		EmailVerificationURL:     getEnvOrDefault("EMAIL_VERIFICATION_URL", "http://localhost:8080/api/v1/auth/verify"),
+
+		LoginMaxAttempts:     getEnvAsInt("LOGIN_MAX_ATTEMPTS", 5),
+		LoginLockoutDuration: loginLockoutDuration,
 
 		PlatformFeeBps:   getEnvAsInt("PLATFORM_FEE_BPS", 50),
 		ForexFeeBps:      getEnvAsInt("FOREX_FEE_BPS", 25),
@@ -68,6 +455,32 @@ func LoadConfig() (*Config, error) {
 		NetworkFeeBps:    getEnvAsInt("NETWORK_FEE_BPS", 15),
 		MinFee:           getEnvAsFloat("MIN_FEE", 0),
 		MaxFee:           getEnvAsFloat("MAX_FEE", 0),
+		MinFeeMaxRatio:   getEnvAsFloat("MIN_FEE_MAX_RATIO", 0),
+		Corridors:        corridors,
+
+		KYCThreshold:        getEnvAsFloat("KYC_THRESHOLD", 1000),
+		TwoFactorThreshold:  getEnvAsFloat("TWO_FACTOR_THRESHOLD", 0),
+		TwoFactorThresholds: twoFactorThresholds,
+		DailyLimits:         dailyLimits,
+		DailyLimitsByTier:   dailyLimitsByTier,
+		MinAmounts:          minAmounts,
+		MaxAmounts:          maxAmounts,
+
+		AbuseIPFailedLoginAccounts: getEnvAsInt("ABUSE_IP_FAILED_LOGIN_ACCOUNTS", 10),
+		AbuseAccountFailedLogins:   getEnvAsInt("ABUSE_ACCOUNT_FAILED_LOGINS", 20),
+		AbuseIPRegistrations:       getEnvAsInt("ABUSE_IP_REGISTRATIONS", 5),
+		AbuseWindow:                time.Duration(getEnvAsInt("ABUSE_WINDOW_MIN", 15)) * time.Minute,
+		AbuseBanDuration:           time.Duration(getEnvAsInt("ABUSE_BAN_DURATION_MIN", 60)) * time.Minute,
+
+		CORSAllowedOrigins: corsOrigins,
+		CORSAllowedMethods: getEnvAsList("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE,OPTIONS"),
+		CORSAllowedHeaders: getEnvAsList("CORS_ALLOWED_HEADERS", "Content-Type,Authorization,X-API-Version,Accept-Version,Idempotency-Key,X-Request-ID"),
+		CORSMaxAge:         time.Duration(getEnvAsInt("CORS_MAX_AGE_SEC", 600)) * time.Second,
+
+		FieldEncryptionKeys:          encryptionKeys,
+		FieldEncryptionKeyVersion:    getEnvAsInt("FIELD_ENCRYPTION_KEY_VERSION", latestKeyVersion),
+		FieldEncryptionBackfill:      getEnvOrDefault("FIELD_ENCRYPTION_BACKFILL", "false") == "true",
+		FieldEncryptionBackfillBatch: getEnvAsInt("FIELD_ENCRYPTION_BACKFILL_BATCH", 500),
 
 		DBMaxIdleConns:    getEnvAsInt("DB_MAX_IDLE_CONNS", 10),
 		DBMaxOpenConns:    getEnvAsInt("DB_MAX_OPEN_CONNS", 100),
@@ -79,9 +492,80 @@ func LoadConfig() (*Config, error) {
 		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
 		SMTPFrom:     getEnvOrDefault("SMTP_FROM", os.Getenv("SMTP_USER")),
 		EmailEnabled: getEnvOrDefault("EMAIL_ENABLED", "false") == "true",
+
+		InvoiceOverdueCheckInterval: time.Duration(getEnvAsInt("INVOICE_OVERDUE_CHECK_INTERVAL_MIN", 60)) * time.Minute,
+		ConditionSweepInterval:      time.Duration(getEnvAsInt("CONDITION_SWEEP_INTERVAL_MIN", 5)) * time.Minute,
+		RetentionPurgeInterval:      time.Duration(getEnvAsInt("RETENTION_PURGE_INTERVAL_MIN", 1440)) * time.Minute,
+		PendingExpiryInterval:       time.Duration(getEnvAsInt("PENDING_EXPIRY_INTERVAL_MIN", 15)) * time.Minute,
+		PendingExpiryAge:            time.Duration(getEnvAsInt("PENDING_EXPIRY_HOURS", 48)) * time.Hour,
+		RecurringSchedulerInterval:  time.Duration(getEnvAsInt("RECURRING_SCHEDULER_INTERVAL_SEC", 60)) * time.Second,
+		PaymentRetention:            time.Duration(getEnvAsInt("PAYMENT_RETENTION_DAYS", 0)) * 24 * time.Hour,
+		WebhookDeliveryRetention:    time.Duration(getEnvAsInt("WEBHOOK_DELIVERY_RETENTION_DAYS", 30)) * 24 * time.Hour,
+		WebhookDeliveryAlertAge:     time.Duration(getEnvAsInt("WEBHOOK_DELIVERY_ALERT_HOURS", 24)) * time.Hour,
+
+		SettlementPollInterval: time.Duration(getEnvAsInt("SETTLEMENT_POLL_INTERVAL_SEC", 30)) * time.Second,
+		SettlementMaxChecks:    getEnvAsInt("SETTLEMENT_MAX_CHECKS", 20),
+		SettlementBackoff:      time.Duration(getEnvAsInt("SETTLEMENT_BACKOFF_SEC", 15)) * time.Second,
+
+		FXRates:            fxRates,
+		SettlementAccounts: settlementAccounts,
+
+		ClaimableBalances:      getEnvOrDefault("CLAIMABLE_BALANCES", "false") == "true",
+		ClaimableReclaimWindow: time.Duration(getEnvAsInt("CLAIMABLE_RECLAIM_WINDOW_HOURS", 720)) * time.Hour,
+
+		SubmitSourceAllowlist: submitSources,
+		AssetIssuerAllowlist:  issuerAllowlist,
+		AssetIssuerDenylist:   issuerDenylist,
+		SupportedCurrencies:   supportedCurrencies,
+		SigningCallbackSecret: os.Getenv("SIGNING_CALLBACK_SECRET"),
+
+		SubmitMaxWait:      time.Duration(getEnvAsInt("SUBMIT_MAX_WAIT_SEC", 60)) * time.Second,
+		SubmitPollInterval: time.Duration(getEnvAsInt("SUBMIT_POLL_INTERVAL_MS", 1000)) * time.Millisecond,
+
+		StreamHeartbeatInterval: time.Duration(getEnvAsInt("STREAM_HEARTBEAT_SEC", 15)) * time.Second,
+
+		StrictCompletion:        getEnvOrDefault("STRICT_COMPLETION", "false") == "true",
+		CheckRecipientTrustline: getEnvOrDefault("CHECK_RECIPIENT_TRUSTLINE", "true") == "true",
+		BaseReserveXLM:          getEnvAsFloat("BASE_RESERVE_XLM", 0),
+		BaseFee:                 int64(getEnvAsInt("BASE_FEE_STROOPS", 100)),
+		MaxBaseFee:              int64(getEnvAsInt("MAX_BASE_FEE_STROOPS", 100000)),
+
+		AccountCacheTTL:        time.Duration(getEnvAsInt("ACCOUNT_CACHE_TTL_SEC", 60)) * time.Second,
+		AccountCacheFailureTTL: time.Duration(getEnvAsInt("ACCOUNT_CACHE_FAILURE_TTL_SEC", 5)) * time.Second,
+		AccountCacheSize:       getEnvAsInt("ACCOUNT_CACHE_SIZE", 10000),
+
+		HorizonTimeout:      time.Duration(getEnvAsInt("HORIZON_TIMEOUT_SEC", 15)) * time.Second,
+		HorizonReadRetries:  getEnvAsInt("HORIZON_READ_RETRIES", 2),
+		HorizonRetryBackoff: time.Duration(getEnvAsInt("HORIZON_RETRY_BACKOFF_MS", 250)) * time.Millisecond,
+
+		VerifyStellarNetwork: getEnvOrDefault("VERIFY_STELLAR_NETWORK", "true") == "true",
+		DryRun:               getEnvOrDefault("DRY_RUN", "false") == "true",
 	}, nil
 }
 
+// resolveStellarNetwork validates a STELLAR_NETWORK name and fills in the
+// endpoints explicit leaves empty from that network's defaults. An explicit
+// passphrase must be the named network's, so a testnet deployment cannot
+// sign for mainnet or the reverse.
+func resolveStellarNetwork(name string, explicit StellarNetworkEndpoints) (StellarNetworkEndpoints, error) {
+	defaults, ok := StellarNetworks[name]
+	if !ok {
+		return StellarNetworkEndpoints{}, fmt.Errorf("invalid STELLAR_NETWORK %q: want testnet, mainnet, or futurenet", name)
+	}
+	if explicit.NetworkPassphrase != "" && explicit.NetworkPassphrase != defaults.NetworkPassphrase {
+		return StellarNetworkEndpoints{}, fmt.Errorf("NETWORK_PASSPHRASE %q is not the %s passphrase %q", explicit.NetworkPassphrase, name, defaults.NetworkPassphrase)
+	}
+
+	resolved := defaults
+	if explicit.HorizonURL != "" {
+		resolved.HorizonURL = explicit.HorizonURL
+	}
+	if explicit.SorobanRPCURL != "" {
+		resolved.SorobanRPCURL = explicit.SorobanRPCURL
+	}
+	return resolved, nil
+}
+
 func InitDB(cfg *Config) (*gorm.DB, error) {
 	db, err := gorm.Open(postgres.Open(cfg.DatabaseURL), &gorm.Config{})
 	if err != nil {
@@ -126,3 +610,236 @@ func getEnvAsFloat(key string, defaultValue float64) float64 {
 	fmt.Sscanf(valueStr, "%f", &value)
 	return value
 }
+
+// getEnvAsList splits a comma-separated value, trimming entries and dropping
+// empty ones.
+func getEnvAsList(key, defaultValue string) []string {
+	var values []string
+	for _, entry := range strings.Split(getEnvOrDefault(key, defaultValue), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			values = append(values, entry)
+		}
+	}
+	return values
+}
+
+// validateJWTSecrets rejects missing, short, or shared token signing
+// secrets, any of which would leave tokens forgeable.
+func validateJWTSecrets(accessSecret, refreshSecret string) error {
+	if accessSecret == "" {
+		return fmt.Errorf("JWT_SECRET must be set")
+	}
+	if refreshSecret == "" {
+		return fmt.Errorf("JWT_REFRESH_SECRET must be set")
+	}
+	if len(accessSecret) < MinJWTSecretBytes {
+		return fmt.Errorf("JWT_SECRET must be at least %d bytes, got %d", MinJWTSecretBytes, len(accessSecret))
+	}
+	if len(refreshSecret) < MinJWTSecretBytes {
+		return fmt.Errorf("JWT_REFRESH_SECRET must be at least %d bytes, got %d", MinJWTSecretBytes, len(refreshSecret))
+	}
+	if accessSecret == refreshSecret {
+		return fmt.Errorf("JWT_SECRET and JWT_REFRESH_SECRET must differ")
+	}
+	return nil
+}
+
+// getEnvAsDuration parses a Go duration string such as "15m" or "168h".
+// Unlike the other helpers it reports malformed or non-positive values
+// instead of silently falling back.
+func getEnvAsDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue, nil
+	}
+	value, err := time.ParseDuration(valueStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	if value <= 0 {
+		return 0, fmt.Errorf("invalid %s: must be positive, got %s", key, valueStr)
+	}
+	return value, nil
+}
+
+// parseRates parses a comma-separated list of PAIR=RATE entries such as
+// "USD/NGN=1550,USD/KES=129.5".
+func parseRates(raw string) (map[string]float64, error) {
+	rates := map[string]float64{}
+	if strings.TrimSpace(raw) == "" {
+		return rates, nil
+	}
+	for _, entry := range strings.Split(raw, ",") {
+		pair, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || !strings.Contains(pair, "/") {
+			return nil, fmt.Errorf("invalid FX_RATES entry %q: want BASE/QUOTE=RATE", entry)
+		}
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("invalid FX_RATES rate for %s: %q", pair, value)
+		}
+		rates[strings.ToUpper(pair)] = rate
+	}
+	return rates, nil
+}
+
+// parseLimits parses a comma-separated list of CODE=AMOUNT entries such as
+// "USD=10000,XLM=50000".
+func parseLimits(key, raw string) (map[string]float64, error) {
+	limits := map[string]float64{}
+	if strings.TrimSpace(raw) == "" {
+		return limits, nil
+	}
+	for _, entry := range strings.Split(raw, ",") {
+		code, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || code == "" {
+			return nil, fmt.Errorf("invalid %s entry %q: want CODE=AMOUNT", key, entry)
+		}
+		limit, err := strconv.ParseFloat(value, 64)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid %s amount for %s: %q", key, code, value)
+		}
+		limits[strings.ToUpper(code)] = limit
+	}
+	return limits, nil
+}
+
+// parseEncryptionKeys parses a comma-separated list of VERSION:KEY entries
+// such as "1:<base64>,2:<base64>", where each key is 32 bytes encoded in
+// standard base64. It also returns the highest version.
+func parseEncryptionKeys(raw string) (map[int][]byte, int, error) {
+	keys := map[int][]byte{}
+	latest := 0
+	if strings.TrimSpace(raw) == "" {
+		return keys, latest, nil
+	}
+	for _, entry := range strings.Split(raw, ",") {
+		rawVersion, encoded, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		version, err := strconv.Atoi(rawVersion)
+		if !ok || err != nil || version <= 0 {
+			return nil, 0, fmt.Errorf("invalid FIELD_ENCRYPTION_KEYS entry: want VERSION:BASE64KEY with a positive version")
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, 0, fmt.Errorf("invalid FIELD_ENCRYPTION_KEYS key for version %d: want 32 bytes in base64", version)
+		}
+		keys[version] = key
+		if version > latest {
+			latest = version
+		}
+	}
+	return keys, latest, nil
+}
+
+// parseSettlementAccounts parses a comma-separated list of CODE=ACCOUNT
+// entries such as "EUR=GABC...,USDC=GDEF...".
+func parseSettlementAccounts(raw string) (map[string]string, error) {
+	accounts := map[string]string{}
+	if strings.TrimSpace(raw) == "" {
+		return accounts, nil
+	}
+	for _, entry := range strings.Split(raw, ",") {
+		code, account, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || code == "" {
+			return nil, fmt.Errorf("invalid SETTLEMENT_ACCOUNTS entry %q: want CODE=ACCOUNT", entry)
+		}
+		if _, err := keypair.ParseAddress(account); err != nil {
+			return nil, fmt.Errorf("invalid SETTLEMENT_ACCOUNTS account for %s: %w", code, err)
+		}
+		accounts[strings.ToUpper(code)] = account
+	}
+	return accounts, nil
+}
+
+// parseCorridors parses a comma-separated list of FROM-TO=MARKUP_BPS:FIXED_FEE
+// entries such as "US-MX=40:1.50,US-PH=120:0.99". Country codes are
+// upper-cased.
+func parseCorridors(raw string) (map[string]Corridor, error) {
+	corridors := map[string]Corridor{}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, pricing, ok := strings.Cut(entry, "=")
+		from, to, okPair := strings.Cut(key, "-")
+		markup, fixed, okPricing := strings.Cut(pricing, ":")
+		if !ok || !okPair || !okPricing || strings.TrimSpace(from) == "" || strings.TrimSpace(to) == "" {
+			return nil, fmt.Errorf("invalid CORRIDOR_PRICING entry %q: want FROM-TO=MARKUP_BPS:FIXED_FEE", entry)
+		}
+		markupBps, err := strconv.Atoi(strings.TrimSpace(markup))
+		if err != nil || markupBps < 0 {
+			return nil, fmt.Errorf("invalid CORRIDOR_PRICING markup for %s: %q", key, markup)
+		}
+		fixedFee, err := strconv.ParseFloat(strings.TrimSpace(fixed), 64)
+		if err != nil || fixedFee < 0 {
+			return nil, fmt.Errorf("invalid CORRIDOR_PRICING fixed fee for %s: %q", key, fixed)
+		}
+		key = strings.ToUpper(strings.TrimSpace(from)) + "-" + strings.ToUpper(strings.TrimSpace(to))
+		corridors[key] = Corridor{FXMarkupBps: markupBps, FixedFee: fixedFee}
+	}
+	return corridors, nil
+}
+
+// parseOrigins parses a comma-separated CORS origin allowlist such as
+// "https://app.example.com,https://admin.example.com". A wildcard must stand
+// alone, so it cannot be mixed in by accident.
+func parseOrigins(raw string) ([]string, error) {
+	var origins []string
+	for _, entry := range strings.Split(raw, ",") {
+		origin := strings.TrimRight(strings.TrimSpace(entry), "/")
+		if origin == "" {
+			continue
+		}
+		if origin != "*" && !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			return nil, fmt.Errorf("invalid CORS_ALLOWED_ORIGINS entry %q: want scheme://host[:port]", entry)
+		}
+		origins = append(origins, origin)
+	}
+	for _, origin := range origins {
+		if origin == "*" && len(origins) > 1 {
+			return nil, fmt.Errorf("invalid CORS_ALLOWED_ORIGINS: \"*\" cannot be combined with other origins")
+		}
+	}
+	return origins, nil
+}
+
+// parseAccountList parses a comma-separated list of Stellar account IDs.
+func parseAccountList(key, raw string) ([]string, error) {
+	var accounts []string
+	for _, entry := range strings.Split(raw, ",") {
+		account := strings.TrimSpace(entry)
+		if account == "" {
+			continue
+		}
+		if _, err := keypair.ParseAddress(account); err != nil {
+			return nil, fmt.Errorf("invalid %s account %q: %w", key, account, err)
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+// parseSupportedCurrencies parses a comma-separated list of CODE or
+// CODE:ISSUER entries such as "XLM,USD,USDC:GA5Z...". Codes are upper-cased;
+// issuers must be Stellar accounts.
+func parseSupportedCurrencies(raw string) ([]SupportedCurrency, error) {
+	var currencies []SupportedCurrency
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		code, issuer, hasIssuer := strings.Cut(entry, ":")
+		if code == "" {
+			return nil, fmt.Errorf("invalid SUPPORTED_CURRENCIES entry %q: want CODE or CODE:ISSUER", entry)
+		}
+		if hasIssuer {
+			if _, err := keypair.ParseAddress(issuer); err != nil {
+				return nil, fmt.Errorf("invalid SUPPORTED_CURRENCIES issuer for %s: %w", code, err)
+			}
+		}
+		currencies = append(currencies, SupportedCurrency{Code: strings.ToUpper(code), Issuer: issuer})
+	}
+	return currencies, nil
+}
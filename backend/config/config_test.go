@@ -0,0 +1,304 @@
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/network"
+	"github.com/stretchr/testify/assert"
+)
+
+// setValidSecrets sets JWT secrets that pass validation so LoadConfig can succeed.
+func setValidSecrets(t *testing.T) {
+	t.Setenv("JWT_SECRET", strings.Repeat("a", MinJWTSecretBytes))
+	t.Setenv("JWT_REFRESH_SECRET", strings.Repeat("b", MinJWTSecretBytes))
+}
+
+func TestLoadConfigTokenTTLs(t *testing.T) {
+	setValidSecrets(t)
+
+	t.Run("Defaults", func(t *testing.T) {
+		t.Setenv("ACCESS_TOKEN_TTL", "")
+		t.Setenv("REFRESH_TOKEN_TTL", "")
+
+		cfg, err := LoadConfig()
+		assert.NoError(t, err)
+		assert.Equal(t, 15*time.Minute, cfg.AccessTokenTTL)
+		assert.Equal(t, 168*time.Hour, cfg.RefreshTokenTTL)
+	})
+
+	t.Run("Valid durations", func(t *testing.T) {
+		t.Setenv("ACCESS_TOKEN_TTL", "5m")
+		t.Setenv("REFRESH_TOKEN_TTL", "720h")
+
+		cfg, err := LoadConfig()
+		assert.NoError(t, err)
+		assert.Equal(t, 5*time.Minute, cfg.AccessTokenTTL)
+		assert.Equal(t, 720*time.Hour, cfg.RefreshTokenTTL)
+	})
+
+	invalid := []struct {
+		name  string
+		key   string
+		value string
+	}{
+		{"Malformed access TTL", "ACCESS_TOKEN_TTL", "fifteen minutes"},
+		{"Missing unit", "ACCESS_TOKEN_TTL", "15"},
+		{"Malformed refresh TTL", "REFRESH_TOKEN_TTL", "7d"},
+		{"Zero refresh TTL", "REFRESH_TOKEN_TTL", "0s"},
+		{"Negative access TTL", "ACCESS_TOKEN_TTL", "-15m"},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("ACCESS_TOKEN_TTL", "")
+			t.Setenv("REFRESH_TOKEN_TTL", "")
+			t.Setenv(tc.key, tc.value)
+
+			cfg, err := LoadConfig()
+			assert.Nil(t, cfg)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tc.key)
+			}
+		})
+	}
+}
+
+func TestParseRates(t *testing.T) {
+	rates, err := parseRates("usd/ngn=1550, USD/KES=129.5")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"USD/NGN": 1550, "USD/KES": 129.5}, rates)
+
+	rates, err = parseRates("")
+	assert.NoError(t, err)
+	assert.Empty(t, rates)
+
+	for _, raw := range []string{"USDNGN=1550", "USD/NGN", "USD/NGN=abc", "USD/NGN=-1"} {
+		_, err := parseRates(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestParseSettlementAccounts(t *testing.T) {
+	eur := "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"
+	accounts, err := parseSettlementAccounts("eur=" + eur)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"EUR": eur}, accounts)
+
+	for _, raw := range []string{"EUR", "=" + eur, "EUR=not-an-account"} {
+		_, err := parseSettlementAccounts(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestParseCorridors(t *testing.T) {
+	corridors, err := parseCorridors("us-mx=40:1.50, US-PH=120:0")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Corridor{
+		"US-MX": {FXMarkupBps: 40, FixedFee: 1.5},
+		"US-PH": {FXMarkupBps: 120},
+	}, corridors)
+
+	corridors, err = parseCorridors("")
+	assert.NoError(t, err)
+	assert.Empty(t, corridors)
+
+	for _, raw := range []string{"US-MX", "USMX=40:1", "US-MX=40", "-MX=40:1", "US-MX=x:1", "US-MX=-5:1", "US-MX=40:-1"} {
+		_, err := parseCorridors(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestParseEncryptionKeys(t *testing.T) {
+	one := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	two := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32))
+	keys, latest, err := parseEncryptionKeys("2:" + two + ", 1:" + one)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, latest)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, bytes.Repeat([]byte{1}, 32), keys[1])
+
+	keys, latest, err = parseEncryptionKeys("")
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+	assert.Zero(t, latest)
+
+	short := base64.StdEncoding.EncodeToString([]byte("too short"))
+	for _, raw := range []string{one, "0:" + one, "x:" + one, "1:" + short, "1:not base64!"} {
+		_, _, err := parseEncryptionKeys(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestParseOrigins(t *testing.T) {
+	origins, err := parseOrigins("https://app.example.com/, http://localhost:3000")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://app.example.com", "http://localhost:3000"}, origins)
+
+	origins, err = parseOrigins("*")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"*"}, origins)
+
+	origins, err = parseOrigins("")
+	assert.NoError(t, err)
+	assert.Empty(t, origins)
+
+	for _, raw := range []string{"app.example.com", "*,https://app.example.com"} {
+		_, err := parseOrigins(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestParseAccountList(t *testing.T) {
+	account := "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"
+	accounts, err := parseAccountList("SUBMIT_SOURCE_ALLOWLIST", " "+account+", ")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{account}, accounts)
+
+	accounts, err = parseAccountList("SUBMIT_SOURCE_ALLOWLIST", "")
+	assert.NoError(t, err)
+	assert.Empty(t, accounts)
+
+	_, err = parseAccountList("SUBMIT_SOURCE_ALLOWLIST", account+",not-an-account")
+	assert.Error(t, err)
+}
+
+func TestLoadConfigAmountBounds(t *testing.T) {
+	setValidSecrets(t)
+
+	t.Setenv("MIN_AMOUNTS", "usd=1,XLM=5")
+	t.Setenv("MAX_AMOUNTS", "USD=10000")
+	cfg, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"USD": 1, "XLM": 5}, cfg.MinAmounts)
+	assert.Equal(t, map[string]float64{"USD": 10000}, cfg.MaxAmounts)
+
+	t.Setenv("MAX_AMOUNTS", "USD=0.5")
+	_, err = LoadConfig()
+	assert.ErrorContains(t, err, "MIN_AMOUNTS for USD")
+}
+
+func TestParseSupportedCurrencies(t *testing.T) {
+	issuer := "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"
+	currencies, err := parseSupportedCurrencies(" xlm, USD ,usdc:" + issuer)
+	assert.NoError(t, err)
+	assert.Equal(t, []SupportedCurrency{{Code: "XLM"}, {Code: "USD"}, {Code: "USDC", Issuer: issuer}}, currencies)
+
+	currencies, err = parseSupportedCurrencies("")
+	assert.NoError(t, err)
+	assert.Empty(t, currencies)
+
+	for _, raw := range []string{":" + issuer, "USDC:not-an-account", "USDC:"} {
+		_, err := parseSupportedCurrencies(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestLoadConfigBcryptCost(t *testing.T) {
+	setValidSecrets(t)
+
+	t.Run("Default", func(t *testing.T) {
+		t.Setenv("BCRYPT_COST", "")
+
+		cfg, err := LoadConfig()
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultBcryptCost, cfg.BcryptCost)
+	})
+
+	t.Run("Configured", func(t *testing.T) {
+		t.Setenv("BCRYPT_COST", "14")
+
+		cfg, err := LoadConfig()
+		assert.NoError(t, err)
+		assert.Equal(t, 14, cfg.BcryptCost)
+	})
+
+	for _, value := range []string{"3", "32", "twelve"} {
+		t.Run("Rejects "+value, func(t *testing.T) {
+			t.Setenv("BCRYPT_COST", value)
+
+			cfg, err := LoadConfig()
+			assert.Nil(t, cfg)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), "BCRYPT_COST")
+			}
+		})
+	}
+}
+
+func TestLoadConfigJWTSecrets(t *testing.T) {
+	long := strings.Repeat("s", MinJWTSecretBytes)
+	otherLong := strings.Repeat("r", MinJWTSecretBytes)
+
+	cases := []struct {
+		name    string
+		access  string
+		refresh string
+		wantErr string
+	}{
+		{"Missing access secret", "", otherLong, "JWT_SECRET must be set"},
+		{"Missing refresh secret", long, "", "JWT_REFRESH_SECRET must be set"},
+		{"Short access secret", "too-short", otherLong, "JWT_SECRET must be at least 32 bytes"},
+		{"Short refresh secret", long, "too-short", "JWT_REFRESH_SECRET must be at least 32 bytes"},
+		{"Identical secrets", long, long, "must differ"},
+		{"Valid secrets", long, otherLong, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("JWT_SECRET", tc.access)
+			t.Setenv("JWT_REFRESH_SECRET", tc.refresh)
+
+			cfg, err := LoadConfig()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.access, cfg.JWTSecret)
+				assert.Equal(t, tc.refresh, cfg.JWTRefreshSecret)
+				return
+			}
+			assert.Nil(t, cfg)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveStellarNetwork(t *testing.T) {
+	endpoints, err := resolveStellarNetwork("mainnet", StellarNetworkEndpoints{})
+	assert.NoError(t, err)
+	assert.Equal(t, StellarNetworks["mainnet"], endpoints)
+
+	endpoints, err = resolveStellarNetwork("testnet", StellarNetworkEndpoints{HorizonURL: "http://localhost:8000"})
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:8000", endpoints.HorizonURL)
+	assert.Equal(t, network.TestNetworkPassphrase, endpoints.NetworkPassphrase)
+
+	_, err = resolveStellarNetwork("testnet", StellarNetworkEndpoints{NetworkPassphrase: network.PublicNetworkPassphrase})
+	assert.ErrorContains(t, err, "NETWORK_PASSPHRASE")
+
+	_, err = resolveStellarNetwork("pubnet", StellarNetworkEndpoints{})
+	assert.ErrorContains(t, err, "STELLAR_NETWORK")
+}
+
+func TestLoadConfigStellarNetwork(t *testing.T) {
+	setValidSecrets(t)
+	t.Setenv("HORIZON_URL", "")
+	t.Setenv("SOROBAN_RPC_URL", "")
+	t.Setenv("NETWORK_PASSPHRASE", "")
+
+	t.Setenv("STELLAR_NETWORK", "Futurenet")
+	cfg, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "futurenet", cfg.StellarNetwork)
+	assert.Equal(t, network.FutureNetworkPassphrase, cfg.NetworkPassphrase)
+	assert.Equal(t, "https://horizon-futurenet.stellar.org", cfg.HorizonURL)
+	assert.True(t, cfg.VerifyStellarNetwork)
+
+	t.Setenv("STELLAR_NETWORK", "mainnet")
+	t.Setenv("NETWORK_PASSPHRASE", network.TestNetworkPassphrase)
+	cfg, err = LoadConfig()
+	assert.Nil(t, cfg)
+	assert.ErrorContains(t, err, "not the mainnet passphrase")
+}
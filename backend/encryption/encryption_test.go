@@ -0,0 +1,115 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, KeySize)
+}
+
+func newCipher(t *testing.T, current int, keys map[int][]byte) *Cipher {
+	t.Helper()
+	provider, err := NewStaticKeys(current, keys)
+	require.NoError(t, err)
+	return NewCipher(provider)
+}
+
+func TestCipherRoundTrip(t *testing.T) {
+	c := newCipher(t, 1, map[int][]byte{1: testKey(1)})
+
+	encrypted, err := c.Encrypt("rent for Jane")
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(encrypted))
+	assert.True(t, strings.HasPrefix(encrypted, "enc:v1:"))
+	assert.NotContains(t, encrypted, "rent for Jane")
+
+	decrypted, err := c.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "rent for Jane", decrypted)
+
+	again, err := c.Encrypt("rent for Jane")
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, again, "each write uses a fresh nonce")
+}
+
+func TestCipherPlaintextPassesThrough(t *testing.T) {
+	c := newCipher(t, 1, map[int][]byte{1: testKey(1)})
+	value, err := c.Decrypt("written before encryption")
+	require.NoError(t, err)
+	assert.Equal(t, "written before encryption", value)
+	assert.True(t, c.NeedsRotation("written before encryption"))
+}
+
+func TestCipherKeyRotation(t *testing.T) {
+	old := newCipher(t, 1, map[int][]byte{1: testKey(1)})
+	encrypted, err := old.Encrypt("school fees")
+	require.NoError(t, err)
+
+	rotated := newCipher(t, 2, map[int][]byte{1: testKey(1), 2: testKey(2)})
+	assert.True(t, rotated.NeedsRotation(encrypted))
+
+	decrypted, err := rotated.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "school fees", decrypted)
+
+	reencrypted, err := rotated.Encrypt(decrypted)
+	require.NoError(t, err)
+	version, ok := Version(reencrypted)
+	assert.True(t, ok)
+	assert.Equal(t, 2, version)
+	assert.False(t, rotated.NeedsRotation(reencrypted))
+
+	// Once the old key is retired its values can no longer be read.
+	retired := newCipher(t, 2, map[int][]byte{2: testKey(2)})
+	_, err = retired.Decrypt(encrypted)
+	assert.True(t, errors.Is(err, ErrUnknownKeyVersion))
+}
+
+func TestCipherRejectsTampering(t *testing.T) {
+	c := newCipher(t, 1, map[int][]byte{1: testKey(1), 2: testKey(2)})
+	encrypted, err := c.Encrypt("rent")
+	require.NoError(t, err)
+
+	t.Run("Modified ciphertext", func(t *testing.T) {
+		sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(encrypted, "enc:v1:"))
+		require.NoError(t, err)
+		sealed[len(sealed)-1] ^= 0x01
+		_, err = c.Decrypt("enc:v1:" + base64.StdEncoding.EncodeToString(sealed))
+		assert.Error(t, err)
+	})
+
+	t.Run("Relabelled key version", func(t *testing.T) {
+		_, err := c.Decrypt(strings.Replace(encrypted, "enc:v1:", "enc:v2:", 1))
+		assert.Error(t, err)
+	})
+
+	t.Run("Wrong key", func(t *testing.T) {
+		other := newCipher(t, 1, map[int][]byte{1: testKey(9)})
+		_, err := other.Decrypt(encrypted)
+		assert.Error(t, err)
+	})
+
+	t.Run("Malformed values", func(t *testing.T) {
+		for _, value := range []string{"enc:v1:!!!", "enc:vx:abc", "enc:v1:YWJj"} {
+			_, err := c.Decrypt(value)
+			assert.Error(t, err, value)
+		}
+	})
+}
+
+func TestNewStaticKeys(t *testing.T) {
+	_, err := NewStaticKeys(2, map[int][]byte{1: testKey(1)})
+	assert.Error(t, err, "the current version must have a key")
+	_, err = NewStaticKeys(1, map[int][]byte{1: []byte("short")})
+	assert.Error(t, err)
+	_, err = NewStaticKeys(0, map[int][]byte{0: testKey(1)})
+	assert.Error(t, err)
+}
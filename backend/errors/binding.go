@@ -0,0 +1,90 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes one request field that failed validation.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func init() {
+	// Report fields by their JSON names, which is what clients send.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(jsonFieldName)
+	}
+}
+
+// jsonFieldName names a field by its json tag; an empty result makes the
+// validator fall back to the Go field name.
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// NewBindingError reports a request body that could not be bound. Validation
+// failures list the offending fields as FieldErrors in details; anything else
+// (such as malformed JSON) carries the decoder's message.
+func NewBindingError(err error) *AppError {
+	var invalid validator.ValidationErrors
+	if !stderrors.As(err, &invalid) {
+		return NewValidationError("Invalid request body", err.Error()).WithKey("error.invalid_request_body")
+	}
+
+	fields := make([]FieldError, 0, len(invalid))
+	for _, fe := range invalid {
+		field := fe.Namespace()
+		// Drop the request struct's name, keeping the path within the body.
+		if i := strings.Index(field, "."); i >= 0 {
+			field = field[i+1:]
+		}
+		fields = append(fields, FieldError{
+			Field:   field,
+			Rule:    fe.Tag(),
+			Message: fieldErrorMessage(field, fe),
+		})
+	}
+	return NewValidationError("Invalid request body", fields).WithKey("error.invalid_request_body")
+}
+
+// fieldErrorMessage describes a failed rule in words a client can show next
+// to the field. Rules without a description fall back to naming the rule.
+func fieldErrorMessage(field string, fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", field)
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", field, fe.Param())
+	case "gte":
+		return fmt.Sprintf("%s must be at least %s", field, fe.Param())
+	case "min", "max":
+		bound := "at least"
+		if fe.Tag() == "max" {
+			bound = "at most"
+		}
+		switch fe.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("%s must be %s %s characters long", field, bound, fe.Param())
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return fmt.Sprintf("%s must have %s %s items", field, bound, fe.Param())
+		}
+		return fmt.Sprintf("%s must be %s %s", field, bound, fe.Param())
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", field)
+	case "url":
+		return fmt.Sprintf("%s must be a valid URL", field)
+	}
+	return fmt.Sprintf("%s failed the %q rule", field, fe.Tag())
+}
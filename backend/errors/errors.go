@@ -5,16 +5,61 @@ import (
 	"net/http"
 )
 
-// ErrorCode is a string representation of the error type
+// ErrorCode is a string representation of the error type. Every error
+// response carries one of the codes below; clients may switch on them, so
+// existing values must never change.
 type ErrorCode string
 
 const (
-	CodeInternal      ErrorCode = "INTERNAL_ERROR"
-	CodeValidation    ErrorCode = "VALIDATION_ERROR"
-	CodeNotFound      ErrorCode = "NOT_FOUND"
-	CodeUnauthorized  ErrorCode = "UNAUTHORIZED"
-	CodeForbidden     ErrorCode = "FORBIDDEN"
-	CodeConflict      ErrorCode = "CONFLICT"
+	CodeInternal         ErrorCode = "INTERNAL_ERROR"
+	CodeValidation       ErrorCode = "VALIDATION_ERROR"
+	CodeNotFound         ErrorCode = "NOT_FOUND"
+	CodeUnauthorized     ErrorCode = "UNAUTHORIZED"
+	CodeForbidden        ErrorCode = "FORBIDDEN"
+	CodeConflict         ErrorCode = "CONFLICT"
+	CodeKYCRequired      ErrorCode = "KYC_REQUIRED"
+	CodeMethodNotAllowed ErrorCode = "METHOD_NOT_ALLOWED"
+	// CodeConcurrentModification means the resource changed between being read and written.
+	CodeConcurrentModification ErrorCode = "CONCURRENT_MODIFICATION"
+	// CodeTransactionFailed means the Stellar network rejected a submitted transaction.
+	CodeTransactionFailed ErrorCode = "TRANSACTION_FAILED"
+	// CodeDailyLimitExceeded means a remittance would take the sender over their daily sending limit.
+	CodeDailyLimitExceeded ErrorCode = "DAILY_LIMIT_EXCEEDED"
+	// CodeRateLimited means the caller exceeded their request quota.
+	CodeRateLimited ErrorCode = "RATE_LIMITED"
+	// CodeUnsupportedVersion means the requested API version is unknown or
+	// does not serve the endpoint.
+	CodeUnsupportedVersion ErrorCode = "UNSUPPORTED_API_VERSION"
+	// CodeIdempotencyConflict means an Idempotency-Key was reused with a
+	// different request, or its original request is still being processed.
+	CodeIdempotencyConflict ErrorCode = "IDEMPOTENCY_CONFLICT"
+	// CodeIssuerNotAllowed means a credit asset's issuer is not on the
+	// configured allowlist, or is on the denylist.
+	CodeIssuerNotAllowed ErrorCode = "IssuerNotAllowed"
+	// CodeUnsupportedCurrency means a currency or asset is not in the
+	// configured supported set.
+	CodeUnsupportedCurrency ErrorCode = "UnsupportedCurrency"
+	// CodeSelfRemittanceNotAllowed means a remittance's recipient is its
+	// sender, by account or by user.
+	CodeSelfRemittanceNotAllowed ErrorCode = "SelfRemittanceNotAllowed"
+	// CodeTwoFactorRequired means the request needs a two-factor code, and
+	// either none was sent or the user has not enabled two-factor
+	// authentication.
+	CodeTwoFactorRequired ErrorCode = "TwoFactorRequired"
+	// CodeInvalidTwoFactorCode means a two-factor code was wrong, expired, or
+	// already used.
+	CodeInvalidTwoFactorCode ErrorCode = "InvalidTwoFactorCode"
+	// CodeEmailNotVerified means the user must confirm their email address
+	// before making the request.
+	CodeEmailNotVerified ErrorCode = "EmailNotVerified"
+	// CodeAccountLocked means the account is locked after too many failed
+	// logins; details carry locked_until.
+	CodeAccountLocked ErrorCode = "AccountLocked"
+
+	// CodeExpiredToken and CodeInvalidToken reject bearer tokens. They predate
+	// the other codes and keep their original spelling for existing clients.
+	CodeExpiredToken ErrorCode = "ExpiredToken"
+	CodeInvalidToken ErrorCode = "InvalidToken"
 )
 
 // AppError represents a standardized application error
@@ -24,6 +69,10 @@ type AppError struct {
 	HTTPStatus int         `json:"-"`
 	Details    interface{} `json:"details,omitempty"`
 	Err        error       `json:"-"` // Underlying error for logging
+	// MessageKey names the i18n catalog entry Message translates, formatted
+	// with MessageArgs. Without one the message is sent as written.
+	MessageKey  string        `json:"-"`
+	MessageArgs []interface{} `json:"-"`
 }
 
 func (e *AppError) Error() string {
@@ -33,6 +82,14 @@ func (e *AppError) Error() string {
 	return fmt.Sprintf("[%s] %s", e.Code, e.Message)
 }
 
+// WithKey marks the message as the English text of an i18n catalog entry,
+// so it can be sent in the client's language. Code is unaffected.
+func (e *AppError) WithKey(key string, args ...interface{}) *AppError {
+	e.MessageKey = key
+	e.MessageArgs = args
+	return e
+}
+
 // NewAppError creates a new AppError
 func NewAppError(status int, code ErrorCode, message string, err error, details interface{}) *AppError {
 	return &AppError{
@@ -72,3 +129,59 @@ func NewForbiddenError(message string) *AppError {
 func NewConflictError(message string) *AppError {
 	return NewAppError(http.StatusConflict, CodeConflict, message, nil, nil)
 }
+
+func NewMethodNotAllowedError(message string) *AppError {
+	return NewAppError(http.StatusMethodNotAllowed, CodeMethodNotAllowed, message, nil, nil)
+}
+
+// NewKYCRequiredError is a 403 for actions that need a verified identity.
+func NewKYCRequiredError(message string) *AppError {
+	return NewAppError(http.StatusForbidden, CodeKYCRequired, message, nil, nil)
+}
+
+// NewInvalidTwoFactorCodeError is a 403 for a two-factor code that is wrong,
+// expired, or already used.
+func NewInvalidTwoFactorCodeError(message string) *AppError {
+	return NewAppError(http.StatusForbidden, CodeInvalidTwoFactorCode, message, nil, nil)
+}
+
+// NewAccountLockedError is a 403 for a login to an account locked after too
+// many failed attempts.
+func NewAccountLockedError(message string, details interface{}) *AppError {
+	return NewAppError(http.StatusForbidden, CodeAccountLocked, message, nil, details)
+}
+
+// NewConcurrentModificationError is a 409 for a write that lost a race with another update.
+func NewConcurrentModificationError(message string) *AppError {
+	return NewAppError(http.StatusConflict, CodeConcurrentModification, message, nil, nil)
+}
+
+// NewTransactionFailedError is a 422 for a transaction the network rejected;
+// details carry the per-operation results.
+func NewTransactionFailedError(message string, details interface{}) *AppError {
+	return NewAppError(http.StatusUnprocessableEntity, CodeTransactionFailed, message, nil, details)
+}
+
+// NewDailyLimitExceededError is a 403 for a remittance that would exceed the
+// sender's daily limit; details carry the limit and what was already sent.
+func NewDailyLimitExceededError(message string, details interface{}) *AppError {
+	return NewAppError(http.StatusForbidden, CodeDailyLimitExceeded, message, nil, details)
+}
+
+// NewIssuerNotAllowedError is a 403 for a credit asset from an issuer the
+// platform does not accept.
+func NewIssuerNotAllowedError(message string, details interface{}) *AppError {
+	return NewAppError(http.StatusForbidden, CodeIssuerNotAllowed, message, nil, details)
+}
+
+// NewUnsupportedCurrencyError is a 400 for a currency or asset the platform
+// does not support; details list the supported set.
+func NewUnsupportedCurrencyError(message string, details interface{}) *AppError {
+	return NewAppError(http.StatusBadRequest, CodeUnsupportedCurrency, message, nil, details)
+}
+
+// NewSelfRemittanceError is a 400 for a remittance whose sender and
+// recipient are the same.
+func NewSelfRemittanceError(message string) *AppError {
+	return NewAppError(http.StatusBadRequest, CodeSelfRemittanceNotAllowed, message, nil, nil)
+}
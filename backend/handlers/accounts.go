@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stellar/go/keypair"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/utils"
+)
+
+type AccountHandler struct {
+	stellarClient utils.StellarClientInterface
+}
+
+func NewAccountHandler(cfg *config.Config) *AccountHandler {
+	return &AccountHandler{
+		stellarClient: utils.NewStellarClient(cfg.HorizonURL, cfg.NetworkPassphrase, utils.WithBaseReserve(cfg.BaseReserveXLM), utils.WithBaseFee(cfg.BaseFee), utils.WithAccountCache(cfg.AccountCacheTTL, cfg.AccountCacheFailureTTL, cfg.AccountCacheSize), utils.WithHorizonHTTP(cfg.HorizonTimeout, cfg.HorizonReadRetries, cfg.HorizonRetryBackoff), utils.WithDryRun(cfg.DryRun)),
+	}
+}
+
+type AccountBalancesResponse struct {
+	AccountID string                 `json:"account_id"`
+	Balances  []utils.AccountBalance `json:"balances"`
+}
+
+// GetBalances returns the asset balances held by a Stellar account so a sender
+// can check they have enough before creating a remittance.
+func (h *AccountHandler) GetBalances(c *gin.Context) {
+	address := c.Param("address")
+	if _, err := keypair.ParseAddress(address); err != nil {
+		c.Error(errors.NewValidationError("Invalid Stellar address", err.Error()))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	ctx := utils.WithRequestContext(c.Request.Context(), c.GetString("requestID"), userID)
+
+	if err := h.stellarClient.ValidateAccount(ctx, address); err != nil {
+		if utils.IsAccountNotFound(err) {
+			c.Error(errors.NewNotFoundError("Account not found").WithKey("error.account_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to validate account", err))
+		}
+		return
+	}
+
+	balances, err := h.stellarClient.GetBalances(ctx, address)
+	if err != nil {
+		if utils.IsAccountNotFound(err) {
+			c.Error(errors.NewNotFoundError("Account not found").WithKey("error.account_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch balances", err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, AccountBalancesResponse{AccountID: address, Balances: balances})
+}
+
+type CreateTrustlineRequest struct {
+	Account     string `json:"account" binding:"required"`
+	AssetCode   string `json:"asset_code" binding:"required"`
+	AssetIssuer string `json:"asset_issuer" binding:"required"`
+	// Limit caps how much of the asset the account may hold; empty means no limit.
+	Limit string `json:"limit"`
+}
+
+// CreateTrustline returns an unsigned change-trust transaction the account
+// holder signs to be able to receive a credit asset.
+func (h *AccountHandler) CreateTrustline(c *gin.Context) {
+	var req CreateTrustlineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+
+	if strings.ToUpper(req.AssetCode) == "XLM" {
+		c.Error(errors.NewValidationError("Native XLM does not need a trustline", nil))
+		return
+	}
+	if _, err := keypair.ParseAddress(req.Account); err != nil {
+		c.Error(errors.NewValidationError("Invalid Stellar address", err.Error()))
+		return
+	}
+	if _, err := keypair.ParseAddress(req.AssetIssuer); err != nil {
+		c.Error(errors.NewValidationError("Invalid asset issuer", err.Error()))
+		return
+	}
+	if req.Limit != "" {
+		if limit, err := strconv.ParseFloat(req.Limit, 64); err != nil || limit < 0 {
+			c.Error(errors.NewValidationError("Invalid limit", "limit must be a non-negative decimal amount"))
+			return
+		}
+	}
+
+	userID, _ := c.Get("userID")
+	ctx := utils.WithRequestContext(c.Request.Context(), c.GetString("requestID"), userID)
+
+	xdr, err := h.stellarClient.BuildChangeTrustTx(ctx, req.Account, req.AssetCode, req.AssetIssuer, req.Limit)
+	if err != nil {
+		if utils.IsAccountNotFound(err) {
+			c.Error(errors.NewNotFoundError("Account not found").WithKey("error.account_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to build trustline transaction", err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"account":      req.Account,
+		"asset_code":   req.AssetCode,
+		"asset_issuer": req.AssetIssuer,
+		"limit":        req.Limit,
+		"tx_envelope":  xdr,
+		"message":      "Sign and submit the transaction to open the trustline.",
+	})
+}
+
+type MergeAccountRequest struct {
+	Account     string `json:"account" binding:"required"`
+	Destination string `json:"destination" binding:"required"`
+}
+
+// MergeAccount returns an unsigned account-merge transaction that closes the
+// account and sweeps its entire XLM balance, reserve included, into
+// destination. Anything that would make the network reject the merge, such as
+// a remaining trustline or open offer, is reported up front as a 400 listing
+// every blocker.
+func (h *AccountHandler) MergeAccount(c *gin.Context) {
+	var req MergeAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+	if _, err := keypair.ParseAddress(req.Account); err != nil {
+		c.Error(errors.NewValidationError("Invalid Stellar address", err.Error()))
+		return
+	}
+	if _, err := keypair.ParseAddress(req.Destination); err != nil {
+		c.Error(errors.NewValidationError("Invalid destination", err.Error()))
+		return
+	}
+	if req.Account == req.Destination {
+		c.Error(errors.NewValidationError("Invalid destination", "an account cannot be merged into itself"))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	ctx := utils.WithRequestContext(c.Request.Context(), c.GetString("requestID"), userID)
+
+	merge, err := h.stellarClient.BuildAccountMergeTx(ctx, req.Account, req.Destination)
+	if err != nil {
+		if blockers, ok := utils.MergeBlockers(err); ok {
+			c.Error(errors.NewValidationError("Account cannot be merged", gin.H{"blockers": blockers}))
+		} else if utils.IsAccountNotFound(err) {
+			c.Error(errors.NewNotFoundError("Account not found").WithKey("error.account_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to build account merge transaction", err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"account":     req.Account,
+		"destination": req.Destination,
+		"amount":      merge.Amount,
+		"fee":         merge.Fee,
+		"tx_envelope": merge.Envelope,
+		"message":     "Sign and submit the transaction to close the account; it cannot be undone.",
+	})
+}
+
+// GetPaymentHistory returns a page of an account's on-chain payments from
+// Horizon, newest first. limit defaults to 10 and is capped at 200; pass the
+// previous page's next_cursor as cursor to page back through older payments.
+func (h *AccountHandler) GetPaymentHistory(c *gin.Context) {
+	address := c.Param("address")
+	if _, err := keypair.ParseAddress(address); err != nil {
+		c.Error(errors.NewValidationError("Invalid Stellar address", err.Error()))
+		return
+	}
+
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			c.Error(errors.NewValidationError("Invalid limit", "limit must be a positive integer"))
+			return
+		}
+		limit = n
+	}
+	cursor := c.Query("cursor")
+	if cursor != "" {
+		if _, err := strconv.ParseUint(cursor, 10, 64); err != nil {
+			c.Error(errors.NewValidationError("Invalid cursor", "cursor must be a next_cursor from a previous page"))
+			return
+		}
+	}
+
+	userID, _ := c.Get("userID")
+	ctx := utils.WithRequestContext(c.Request.Context(), c.GetString("requestID"), userID)
+
+	history, err := h.stellarClient.GetPaymentHistory(ctx, address, limit, cursor)
+	if err != nil {
+		if utils.IsAccountNotFound(err) {
+			c.Error(errors.NewNotFoundError("Account not found").WithKey("error.account_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch payment history", err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
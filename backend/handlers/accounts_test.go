@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/utils"
+)
+
+func TestGetBalances(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	funded, _ := keypair.Random()
+	missing, _ := keypair.Random()
+	issuer, _ := keypair.Random()
+
+	handler := &AccountHandler{
+		stellarClient: &MockStellarClient{
+			ValidateAccountFunc: func(accountID string) error {
+				if accountID == missing.Address() {
+					return fmt.Errorf("invalid or non-existent account: %w", utils.ErrAccountNotFound)
+				}
+				return nil
+			},
+			GetBalancesFunc: func(accountID string) ([]utils.AccountBalance, error) {
+				return []utils.AccountBalance{
+					{AssetType: "credit_alphanum4", AssetCode: "USDC", AssetIssuer: issuer.Address(), Balance: "250.0000000"},
+					{AssetType: "credit_alphanum4", AssetCode: "EURC", AssetIssuer: issuer.Address(), Balance: "12.5000000"},
+					{AssetType: "native", AssetCode: "XLM", Balance: "100.0000000"},
+				}, nil
+			},
+		},
+	}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/accounts/:address/balances", handler.GetBalances)
+
+	t.Run("Multi-asset balances", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/accounts/"+funded.Address()+"/balances", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp AccountBalancesResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.Equal(t, funded.Address(), resp.AccountID)
+		if assert.Len(t, resp.Balances, 3) {
+			assert.Equal(t, "USDC", resp.Balances[0].AssetCode)
+			assert.Equal(t, issuer.Address(), resp.Balances[0].AssetIssuer)
+			assert.Equal(t, "250.0000000", resp.Balances[0].Balance)
+			assert.Equal(t, "XLM", resp.Balances[2].AssetCode)
+			assert.Empty(t, resp.Balances[2].AssetIssuer)
+		}
+	})
+
+	t.Run("Account not found", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/accounts/"+missing.Address()+"/balances", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Malformed address", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/accounts/not-an-address/balances", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestCreateTrustline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	account, _ := keypair.Random()
+	issuer, _ := keypair.Random()
+
+	handler := &AccountHandler{
+		stellarClient: &MockStellarClient{
+			BuildChangeTrustTxFunc: func(accountID, assetCode, assetIssuer, limit string) (string, error) {
+				tx, err := utils.NewChangeTrustTx(&txnbuild.SimpleAccount{AccountID: accountID, Sequence: 1}, assetCode, assetIssuer, limit)
+				if err != nil {
+					return "", err
+				}
+				return tx.Base64()
+			},
+		},
+	}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.POST("/accounts/trustlines", handler.CreateTrustline)
+
+	post := func(req CreateTrustlineRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		httpReq, _ := http.NewRequest(http.MethodPost, "/accounts/trustlines", bytes.NewBuffer(body))
+		router.ServeHTTP(w, httpReq)
+		return w
+	}
+
+	t.Run("Returns change trust envelope", func(t *testing.T) {
+		w := post(CreateTrustlineRequest{Account: account.Address(), AssetCode: "USDC", AssetIssuer: issuer.Address(), Limit: "1000"})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var resp struct {
+			TxEnvelope string `json:"tx_envelope"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		parsed, err := txnbuild.TransactionFromXDR(resp.TxEnvelope)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		tx, _ := parsed.Transaction()
+		op, ok := tx.Operations()[0].(*txnbuild.ChangeTrust)
+		if assert.True(t, ok) {
+			assert.Equal(t, "USDC", op.Line.GetCode())
+			assert.Equal(t, issuer.Address(), op.Line.GetIssuer())
+			assert.Equal(t, "1000.0000000", op.Limit)
+		}
+	})
+
+	t.Run("Native asset rejected", func(t *testing.T) {
+		w := post(CreateTrustlineRequest{Account: account.Address(), AssetCode: "XLM", AssetIssuer: issuer.Address()})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Invalid limit rejected", func(t *testing.T) {
+		w := post(CreateTrustlineRequest{Account: account.Address(), AssetCode: "USDC", AssetIssuer: issuer.Address(), Limit: "lots"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestMergeAccount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	account, _ := keypair.Random()
+	blocked, _ := keypair.Random()
+	destination, _ := keypair.Random()
+
+	handler := &AccountHandler{
+		stellarClient: &MockStellarClient{
+			BuildAccountMergeTxFunc: func(source, dest string) (*utils.AccountMergeTx, error) {
+				if source == blocked.Address() {
+					return nil, &utils.MergeBlockedError{Blockers: []string{"open offers (2) must be cancelled"}}
+				}
+				return &utils.AccountMergeTx{Envelope: "merge_xdr", Amount: 24999900, Fee: 100}, nil
+			},
+		},
+	}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.POST("/accounts/merge", handler.MergeAccount)
+
+	post := func(req MergeAccountRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		httpReq, _ := http.NewRequest(http.MethodPost, "/accounts/merge", bytes.NewBuffer(body))
+		router.ServeHTTP(w, httpReq)
+		return w
+	}
+
+	t.Run("Returns merge envelope and swept amount", func(t *testing.T) {
+		w := post(MergeAccountRequest{Account: account.Address(), Destination: destination.Address()})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var resp struct {
+			TxEnvelope string `json:"tx_envelope"`
+			Amount     string `json:"amount"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.Equal(t, "merge_xdr", resp.TxEnvelope)
+		assert.Equal(t, "2.4999900", resp.Amount)
+	})
+
+	t.Run("Blockers are listed", func(t *testing.T) {
+		w := post(MergeAccountRequest{Account: blocked.Address(), Destination: destination.Address()})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp struct {
+			Error struct {
+				Details struct {
+					Blockers []string `json:"blockers"`
+				} `json:"details"`
+			} `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, []string{"open offers (2) must be cancelled"}, resp.Error.Details.Blockers)
+	})
+
+	t.Run("Merging into itself rejected", func(t *testing.T) {
+		w := post(MergeAccountRequest{Account: account.Address(), Destination: account.Address()})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Invalid destination rejected", func(t *testing.T) {
+		w := post(MergeAccountRequest{Account: account.Address(), Destination: "nowhere"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGetPaymentHistory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	account, _ := keypair.Random()
+	missing, _ := keypair.Random()
+
+	type call struct {
+		limit  int
+		cursor string
+	}
+	var calls []call
+	handler := &AccountHandler{
+		stellarClient: &MockStellarClient{
+			GetPaymentHistoryFunc: func(accountID string, limit int, cursor string) (*utils.PaymentHistory, error) {
+				if accountID == missing.Address() {
+					return nil, utils.ErrAccountNotFound
+				}
+				calls = append(calls, call{limit, cursor})
+				return &utils.PaymentHistory{
+					AccountID:  accountID,
+					Payments:   []utils.PaymentRecord{{ID: "200", Type: "payment", Direction: "received", AssetCode: "XLM", Amount: "5.0000000", PagingToken: "200"}},
+					NextCursor: "200",
+				}, nil
+			},
+		},
+	}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/accounts/:address/payments", handler.GetPaymentHistory)
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Cursor and limit are passed through", func(t *testing.T) {
+		w := get("/accounts/" + account.Address() + "/payments?limit=500&cursor=12345")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, call{500, "12345"}, calls[len(calls)-1])
+
+		var resp utils.PaymentHistory
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "200", resp.NextCursor)
+		assert.Len(t, resp.Payments, 1)
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		w := get("/accounts/" + account.Address() + "/payments")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, call{0, ""}, calls[len(calls)-1])
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		before := len(calls)
+		assert.Equal(t, http.StatusBadRequest, get("/accounts/not-an-address/payments").Code)
+		assert.Equal(t, http.StatusBadRequest, get("/accounts/"+account.Address()+"/payments?limit=0").Code)
+		assert.Equal(t, http.StatusBadRequest, get("/accounts/"+account.Address()+"/payments?cursor=abc").Code)
+		assert.Len(t, calls, before)
+	})
+
+	t.Run("Account not found", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, get("/accounts/"+missing.Address()+"/payments").Code)
+	})
+}
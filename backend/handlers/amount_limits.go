@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/services"
+)
+
+// formatAmount prints an amount without trailing zeros, e.g. 1 or 0.5.
+func formatAmount(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// requireAmountInRange rejects an amount outside the configured per-currency
+// minimum and maximum, reporting the applicable bounds on the context.
+func requireAmountInRange(c *gin.Context, cfg *config.Config, currency string, amount float64) bool {
+	return report(c, amountRangeError(cfg, currency, amount))
+}
+
+// amountRangeError is the error requireAmountInRange reports, or nil when
+// amount is within bounds.
+func amountRangeError(cfg *config.Config, currency string, amount float64) *errors.AppError {
+	code := strings.ToUpper(currency)
+	lower, hasMin := cfg.MinAmounts[code]
+	upper, hasMax := cfg.MaxAmounts[code]
+	if (!hasMin || amount >= lower) && (!hasMax || amount <= upper) {
+		return nil
+	}
+
+	var bounds string
+	switch {
+	case hasMin && hasMax:
+		bounds = fmt.Sprintf("between %s and %s %s", formatAmount(lower), formatAmount(upper), code)
+	case hasMin:
+		bounds = fmt.Sprintf("at least %s %s", formatAmount(lower), code)
+	default:
+		bounds = fmt.Sprintf("at most %s %s", formatAmount(upper), code)
+	}
+	details := map[string]interface{}{"currency": code, "amount": amount}
+	if hasMin {
+		details["min_amount"] = lower
+	}
+	if hasMax {
+		details["max_amount"] = upper
+	}
+	return errors.NewValidationError(fmt.Sprintf("Amount %s %s is out of range: it must be %s", formatAmount(amount), code, bounds), details)
+}
+
+// requireFeeBelowAmount rejects a remittance whose fee would take all of the
+// amount, which happens for small amounts once a minimum fee applies.
+func requireFeeBelowAmount(c *gin.Context, currency string, amount float64, fees services.FeeBreakdown) bool {
+	return report(c, feeAmountError(currency, amount, fees))
+}
+
+// feeAmountError is the error requireFeeBelowAmount reports, or nil when the
+// fee leaves some of the amount.
+func feeAmountError(currency string, amount float64, fees services.FeeBreakdown) *errors.AppError {
+	if fees.TotalFee < amount {
+		return nil
+	}
+	code := strings.ToUpper(currency)
+	return errors.NewValidationError(
+		fmt.Sprintf("Amount %s %s is too small: the fee of %s %s would meet or exceed it", formatAmount(amount), code, formatAmount(fees.TotalFee), code),
+		map[string]interface{}{"currency": code, "amount": amount, "fee": fees.TotalFee},
+	)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/services"
+)
+
+func TestRemittanceAmountBounds(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	cfg := &config.Config{
+		MinAmounts: map[string]float64{"USD": 1, "XLM": 5},
+		MaxAmounts: map[string]float64{"USD": 10000},
+		MinFee:     0.5,
+	}
+	handler := &RemittanceHandler{db: db, config: cfg, fees: services.NewFeeService(cfg)}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.POST("/remittances", handler.SendRemittance)
+
+	send := func(amount float64, currency string) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		json.NewEncoder(&buf).Encode(SendRemittanceRequest{SenderID: 1, RecipientID: 2, Amount: amount, Currency: currency})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/remittances", &buf)
+		router.ServeHTTP(w, req)
+		return w
+	}
+	errorBody := func(t *testing.T, w *httptest.ResponseRecorder) middleware.ErrorBody {
+		var resp middleware.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Error
+	}
+
+	t.Run("In range", func(t *testing.T) {
+		assert.Equal(t, http.StatusCreated, send(250, "usd").Code)
+	})
+
+	t.Run("Below the minimum", func(t *testing.T) {
+		w := send(0.5, "USD")
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		body := errorBody(t, w)
+		assert.Equal(t, "Amount 0.5 USD is out of range: it must be between 1 and 10000 USD", body.Message)
+		assert.Equal(t, map[string]interface{}{"currency": "USD", "amount": 0.5, "min_amount": 1.0, "max_amount": 10000.0}, body.Details)
+	})
+
+	t.Run("Above the maximum", func(t *testing.T) {
+		w := send(10000.01, "USD")
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "Amount 10000.01 USD is out of range: it must be between 1 and 10000 USD", errorBody(t, w).Message)
+	})
+
+	t.Run("Only a minimum configured", func(t *testing.T) {
+		w := send(2, "XLM")
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "Amount 2 XLM is out of range: it must be at least 5 XLM", errorBody(t, w).Message)
+		assert.Equal(t, http.StatusCreated, send(1e6, "XLM").Code)
+	})
+
+	t.Run("Fee would consume the amount", func(t *testing.T) {
+		w := send(0.4, "EUR")
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "Amount 0.4 EUR is too small: the fee of 0.5 EUR would meet or exceed it", errorBody(t, w).Message)
+	})
+}
@@ -45,7 +45,7 @@ func (h *AnalyticsHandler) GetVolumeMetrics(c *gin.Context) {
 		var err error
 		startDate, endDate, err = h.service.CalculateDateRange(period)
 		if err != nil {
-			c.Error(errors.NewValidationError("Invalid date range", err.Error()))
+			c.Error(errors.NewValidationError("Invalid date range", err.Error()).WithKey("error.invalid_date_range"))
 			return
 		}
 
@@ -93,7 +93,7 @@ func (h *AnalyticsHandler) GetFeeMetrics(c *gin.Context) {
 		var err error
 		startDate, endDate, err = h.service.CalculateDateRange(period)
 		if err != nil {
-			c.Error(errors.NewValidationError("Invalid date range", err.Error()))
+			c.Error(errors.NewValidationError("Invalid date range", err.Error()).WithKey("error.invalid_date_range"))
 			return
 		}
 
@@ -141,7 +141,7 @@ func (h *AnalyticsHandler) GetSuccessRate(c *gin.Context) {
 		var err error
 		startDate, endDate, err = h.service.CalculateDateRange(period)
 		if err != nil {
-			c.Error(errors.NewValidationError("Invalid date range", err.Error()))
+			c.Error(errors.NewValidationError("Invalid date range", err.Error()).WithKey("error.invalid_date_range"))
 			return
 		}
 
@@ -185,7 +185,7 @@ func (h *AnalyticsHandler) GetTopCorridors(c *gin.Context) {
 	if !customRange {
 		startDate, endDate, err = h.service.CalculateDateRange(period)
 		if err != nil {
-			c.Error(errors.NewValidationError("Invalid date range", err.Error()))
+			c.Error(errors.NewValidationError("Invalid date range", err.Error()).WithKey("error.invalid_date_range"))
 			return
 		}
 	}
@@ -260,3 +260,55 @@ func getCacheDuration(period string) time.Duration {
 		return 10 * time.Minute
 	}
 }
+
+// GetFXExposure reports the platform's outstanding exposure per currency pair
+// from non-terminal cross-currency remittances.
+func (h *AnalyticsHandler) GetFXExposure(c *gin.Context) {
+	report, err := h.service.GetFXExposure(time.Now())
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to retrieve FX exposure", err))
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// GetRemittanceReport returns the reconciliation summary finance uses: counts,
+// volume and fees per currency and counts per status for the remittances
+// created on the days from through to (YYYY-MM-DD, inclusive, UTC). Both
+// default to today.
+func (h *AnalyticsHandler) GetRemittanceReport(c *gin.Context) {
+	now := time.Now().UTC()
+	from, err := parseReportDate(c.Query("from"), now)
+	if err != nil {
+		c.Error(errors.NewValidationError("Invalid from date", err.Error()))
+		return
+	}
+	to, err := parseReportDate(c.Query("to"), now)
+	if err != nil {
+		c.Error(errors.NewValidationError("Invalid to date", err.Error()))
+		return
+	}
+	if to.Before(from) {
+		c.Error(errors.NewValidationError("Invalid date range", "from must not be after to").WithKey("error.invalid_date_range"))
+		return
+	}
+
+	report, err := h.service.GetRemittanceReport(from, to, now)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to build remittance report", err))
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// parseReportDate parses a report date, defaulting to the day of now.
+func parseReportDate(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	date, err := time.Parse(services.ReportDateFormat, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a YYYY-MM-DD date", value)
+	}
+	return date, nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/gpay-remit/middleware"
 	"github.com/yourusername/gpay-remit/models"
 	"github.com/yourusername/gpay-remit/services"
 	"gorm.io/driver/sqlite"
@@ -116,6 +117,7 @@ func TestGetVolumeMetrics(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			router := gin.New()
+			router.Use(middleware.ErrorHandler())
 			router.GET("/analytics/volume", handler.GetVolumeMetrics)
 
 			req := httptest.NewRequest(http.MethodGet, "/analytics/volume"+tt.queryParams, nil)
@@ -138,6 +140,7 @@ func TestGetFeeMetrics(t *testing.T) {
 	handler := NewAnalyticsHandler(db)
 
 	router := gin.New()
+	router.Use(middleware.ErrorHandler())
 	router.GET("/analytics/fees", handler.GetFeeMetrics)
 
 	req := httptest.NewRequest(http.MethodGet, "/analytics/fees?period=daily", nil)
@@ -165,6 +168,7 @@ func TestGetSuccessRate(t *testing.T) {
 	handler := NewAnalyticsHandler(db)
 
 	router := gin.New()
+	router.Use(middleware.ErrorHandler())
 	router.GET("/analytics/success-rate", handler.GetSuccessRate)
 
 	req := httptest.NewRequest(http.MethodGet, "/analytics/success-rate?period=daily", nil)
@@ -234,6 +238,7 @@ func TestGetTopCorridors(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			router := gin.New()
+			router.Use(middleware.ErrorHandler())
 			router.GET("/analytics/top-corridors", handler.GetTopCorridors)
 
 			req := httptest.NewRequest(http.MethodGet, "/analytics/top-corridors"+tt.queryParams, nil)
@@ -249,6 +254,43 @@ func TestGetTopCorridors(t *testing.T) {
 	}
 }
 
+func TestGetFXExposure(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupAnalyticsTestDB(t)
+
+	// The seeded completed USD/EUR and USD/GBP payments must not count.
+	inFlight := []models.Payment{
+		{SenderID: 1, Amount: 100, Currency: "USD", TargetCurrency: "EUR", QuotedRate: 0.92, ConvertedAmount: 92, Status: "processing"},
+		{SenderID: 1, Amount: 200, Currency: "USD", TargetCurrency: "EUR", QuotedRate: 0.92, ConvertedAmount: 184, Status: "pending"},
+		{SenderID: 2, Amount: 50, Currency: "EUR", TargetCurrency: "USD", QuotedRate: 1.08, ConvertedAmount: 54, Status: "needs_review"},
+		{SenderID: 2, Amount: 80, Currency: "USD", TargetCurrency: "GBP", QuotedRate: 0.8, ConvertedAmount: 64, Status: "cancelled"},
+	}
+	for i := range inFlight {
+		assert.NoError(t, db.Create(&inFlight[i]).Error)
+	}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/admin/fx-exposure", NewAnalyticsHandler(db).GetFXExposure)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/fx-exposure", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response services.FXExposureReport
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	assert.Equal(t, []services.PairExposure{
+		{SourceCurrency: "EUR", TargetCurrency: "USD", InFlightCount: 1, SourceAmount: 50, TargetAmount: 54},
+		{SourceCurrency: "USD", TargetCurrency: "EUR", QuotedCount: 1, InFlightCount: 1, SourceAmount: 300, TargetAmount: 276},
+	}, response.Pairs)
+	assert.Equal(t, []services.CurrencyExposure{
+		{Currency: "EUR", Net: -226},
+		{Currency: "USD", Net: 246},
+	}, response.Currencies)
+}
+
 func TestIsValidPeriod(t *testing.T) {
 	tests := []struct {
 		period   string
@@ -289,3 +331,35 @@ func TestGetCacheDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestGetRemittanceReport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupAnalyticsTestDB(t)
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/reports/remittances", NewAnalyticsHandler(db).GetRemittanceReport)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/reports/remittances"+query, nil))
+		return w
+	}
+
+	t.Run("Empty range returns zeros", func(t *testing.T) {
+		w := get("?from=2020-01-01&to=2020-01-31")
+		assert.Equal(t, http.StatusOK, w.Code)
+		var report services.RemittanceReport
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.Equal(t, "2020-01-01", report.From)
+		assert.Equal(t, "2020-01-31", report.To)
+		assert.Zero(t, report.TotalCount)
+		assert.Empty(t, report.Currencies)
+		assert.Zero(t, report.ByStatus["completed"])
+	})
+
+	t.Run("Rejects bad dates", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, get("?from=yesterday").Code)
+		assert.Equal(t, http.StatusBadRequest, get("?from=2024-02-01&to=2024-01-01").Code)
+	})
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/models"
+	"gorm.io/gorm"
+)
+
+// apiKeyDisplayLength is how much of a key is kept, in clear, as its prefix.
+const apiKeyDisplayLength = 12
+
+type APIKeyHandler struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyHandler(db *gorm.DB) *APIKeyHandler {
+	return &APIKeyHandler{db: db}
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CreateAPIKey issues an API key for the caller. The key is returned only
+// in this response; afterwards just its prefix is shown.
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+
+	for _, scope := range req.Scopes {
+		if !models.IsValidAPIKeyScope(scope) {
+			c.Error(errors.NewValidationError(fmt.Sprintf("Unknown scope %q", scope), map[string]interface{}{
+				"valid_scopes": models.APIKeyScopes,
+			}))
+			return
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		c.Error(errors.NewValidationError("expires_at must be in the future", nil))
+		return
+	}
+
+	secret, err := generateSecret(24)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to generate API key", err))
+		return
+	}
+	key := models.APIKeyPrefix + secret
+
+	apiKey := models.APIKey{
+		UserID:    userID.(uint),
+		Name:      req.Name,
+		Prefix:    key[:apiKeyDisplayLength],
+		KeyHash:   models.HashAPIKey(key),
+		Scopes:    strings.Join(req.Scopes, ","),
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := h.db.Create(&apiKey).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to create API key", err))
+		return
+	}
+
+	response := apiKeyResponse(&apiKey)
+	response["key"] = key // Return the key only on creation
+	c.JSON(http.StatusCreated, response)
+}
+
+// ListAPIKeys lists the caller's API keys, including revoked and expired ones
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+
+	var keys []models.APIKey
+	if err := h.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&keys).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to fetch API keys", err))
+		return
+	}
+
+	response := make([]gin.H, len(keys))
+	for i := range keys {
+		response[i] = apiKeyResponse(&keys[i])
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeAPIKey revokes one of the caller's API keys. Revoking a key twice
+// keeps the original revocation time.
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+
+	var apiKey models.APIKey
+	if err := h.db.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&apiKey).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("API key not found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch API key", err))
+		}
+		return
+	}
+
+	if apiKey.RevokedAt == nil {
+		now := time.Now()
+		if err := h.db.Model(&apiKey).Update("revoked_at", now).Error; err != nil {
+			c.Error(errors.NewInternalError("Failed to revoke API key", err))
+			return
+		}
+		apiKey.RevokedAt = &now
+	}
+
+	c.JSON(http.StatusOK, apiKeyResponse(&apiKey))
+}
+
+func apiKeyResponse(k *models.APIKey) gin.H {
+	return gin.H{
+		"id":           k.ID,
+		"name":         k.Name,
+		"prefix":       k.Prefix,
+		"scopes":       k.ScopeList(),
+		"expires_at":   k.ExpiresAt,
+		"last_used_at": k.LastUsedAt,
+		"revoked_at":   k.RevokedAt,
+		"created_at":   k.CreatedAt,
+	}
+}
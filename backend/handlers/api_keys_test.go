@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestAPIKeyManagement(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.APIKey{}))
+	handler := NewAPIKeyHandler(db)
+
+	routerFor := func(userID uint) *gin.Engine {
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", userID)
+			c.Next()
+		})
+		router.POST("/apikeys", handler.CreateAPIKey)
+		router.GET("/apikeys", handler.ListAPIKeys)
+		router.DELETE("/apikeys/:id", handler.RevokeAPIKey)
+		return router
+	}
+	request := func(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	owner := routerFor(1)
+
+	var created map[string]interface{}
+	t.Run("Create returns the key once", func(t *testing.T) {
+		w := request(owner, http.MethodPost, "/apikeys", `{"name":"PSP","scopes":["remittances:read","remittances:write"]}`)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+		key := created["key"].(string)
+		assert.True(t, strings.HasPrefix(key, models.APIKeyPrefix))
+		assert.True(t, strings.HasPrefix(key, created["prefix"].(string)))
+		assert.Equal(t, []interface{}{"remittances:read", "remittances:write"}, created["scopes"])
+
+		var stored models.APIKey
+		require.NoError(t, db.First(&stored, uint(created["id"].(float64))).Error)
+		assert.Equal(t, models.HashAPIKey(key), stored.KeyHash, "only the hash is stored")
+	})
+
+	t.Run("Invalid requests are rejected", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+		for _, body := range []string{
+			`{"name":"PSP","scopes":[]}`,
+			`{"name":"PSP","scopes":["payments:write"]}`,
+			`{"name":"PSP","scopes":["remittances:read"],"expires_at":"` + past + `"}`,
+		} {
+			w := request(owner, http.MethodPost, "/apikeys", body)
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		}
+	})
+
+	t.Run("List hides the key", func(t *testing.T) {
+		w := request(owner, http.MethodGet, "/apikeys", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), created["key"].(string))
+
+		var keys []map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &keys))
+		require.Len(t, keys, 1)
+		assert.Equal(t, "PSP", keys[0]["name"])
+
+		w = request(routerFor(2), http.MethodGet, "/apikeys", "")
+		assert.JSONEq(t, `[]`, w.Body.String())
+	})
+
+	t.Run("Only the owner can revoke", func(t *testing.T) {
+		path := fmt.Sprintf("/apikeys/%v", created["id"])
+
+		w := request(routerFor(2), http.MethodDelete, path, "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = request(owner, http.MethodDelete, path, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var revoked map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &revoked))
+		assert.NotNil(t, revoked["revoked_at"])
+
+		var stored models.APIKey
+		require.NoError(t, db.First(&stored, uint(created["id"].(float64))).Error)
+		assert.False(t, stored.Active(time.Now()))
+	})
+}
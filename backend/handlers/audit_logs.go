@@ -1,7 +1,13 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/yourusername/gpay-remit/errors"
@@ -9,6 +15,27 @@ import (
 	"gorm.io/gorm"
 )
 
+// maxAuditExportRows bounds a single CSV export.
+const maxAuditExportRows = 10000
+
+// redactedValue replaces sensitive fields in audit snapshots.
+const redactedValue = "[REDACTED]"
+
+// sensitiveAuditFields are snapshot keys hidden from everyone but superadmins.
+var sensitiveAuditFields = map[string]bool{
+	"password":      true,
+	"password_hash": true,
+	"secret":        true,
+	"secret_key":    true,
+	"source_secret": true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"api_key":       true,
+	"email":         true,
+	"phone":         true,
+}
+
 type AuditLogHandler struct {
 	db *gorm.DB
 }
@@ -29,3 +56,201 @@ func (h *AuditLogHandler) List(c *gin.Context) {
 
 	c.JSON(http.StatusOK, logs)
 }
+
+type ListAuditLogsResponse struct {
+	Data       []models.AuditLog `json:"data"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"page_size"`
+	TotalCount int64             `json:"total_count"`
+}
+
+// Search returns audit logs filtered by actor_id, action, entity_type,
+// entity_id and a from/to date range, newest first. With format=csv the
+// matching logs are exported instead of paginated. Sensitive snapshot fields
+// are redacted unless the caller is a superadmin.
+func (h *AuditLogHandler) Search(c *gin.Context) {
+	query := h.db.Model(&models.AuditLog{})
+
+	if actorID := c.Query("actor_id"); actorID != "" {
+		var id uint
+		if _, err := fmt.Sscanf(actorID, "%d", &id); err != nil {
+			c.Error(errors.NewValidationError("Invalid actor_id", "actor_id must be a user ID"))
+			return
+		}
+		query = query.Where("user_id = ?", id)
+	}
+	if action := c.Query("action"); action != "" {
+		query = query.Where("action = ?", strings.ToUpper(action))
+	}
+	if entityType := c.Query("entity_type"); entityType != "" {
+		query = query.Where("entity_type = ?", entityType)
+	}
+	if entityID := c.Query("entity_id"); entityID != "" {
+		query = query.Where("entity_id = ?", entityID)
+	}
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			c.Error(errors.NewValidationError("Invalid from date", "from must be YYYY-MM-DD"))
+			return
+		}
+		query = query.Where("created_at >= ?", t)
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			c.Error(errors.NewValidationError("Invalid to date", "to must be YYYY-MM-DD"))
+			return
+		}
+		query = query.Where("created_at < ?", t.AddDate(0, 0, 1))
+	}
+
+	role, _ := c.Get("role")
+	redact := role != "superadmin"
+
+	switch c.Query("format") {
+	case "":
+	case "csv":
+		var logs []models.AuditLog
+		if err := query.Order("created_at DESC").Limit(maxAuditExportRows).Find(&logs).Error; err != nil {
+			c.Error(errors.NewInternalError("Failed to fetch audit logs", err))
+			return
+		}
+		if redact {
+			redactAuditLogs(logs)
+		}
+		h.exportCSV(c, logs)
+		return
+	default:
+		c.Error(errors.NewValidationError("Invalid format", "format must be 'csv'"))
+		return
+	}
+
+	page := 1
+	pageSize := 20
+	fmt.Sscanf(c.Query("page"), "%d", &page)
+	fmt.Sscanf(c.Query("page_size"), "%d", &pageSize)
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to count audit logs", err))
+		return
+	}
+
+	var logs []models.AuditLog
+	if err := query.Order("created_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&logs).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to fetch audit logs", err))
+		return
+	}
+	if redact {
+		redactAuditLogs(logs)
+	}
+
+	c.JSON(http.StatusOK, ListAuditLogsResponse{
+		Data:       logs,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalCount: total,
+	})
+}
+
+func (h *AuditLogHandler) exportCSV(c *gin.Context, logs []models.AuditLog) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	header := []string{"ID", "Created At", "Actor ID", "Action", "Resource", "Entity Type", "Entity ID", "Old Status", "New Status", "IP Address", "Old Value", "New Value"}
+	if err := writer.Write(header); err != nil {
+		c.Error(errors.NewInternalError("Failed to write CSV header", err))
+		return
+	}
+
+	for _, log := range logs {
+		actorID := ""
+		if log.UserID != nil {
+			actorID = fmt.Sprintf("%d", *log.UserID)
+		}
+		row := []string{
+			fmt.Sprintf("%d", log.ID),
+			log.CreatedAt.Format("2006-01-02 15:04:05"),
+			actorID,
+			log.Action,
+			log.Resource,
+			log.EntityType,
+			log.EntityID,
+			log.OldStatus,
+			log.NewStatus,
+			log.IPAddress,
+			log.OldValue,
+			log.NewValue,
+		}
+		if err := writer.Write(row); err != nil {
+			c.Error(errors.NewInternalError("Failed to write CSV row", err))
+			return
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		c.Error(errors.NewInternalError("Failed to generate CSV", err))
+		return
+	}
+
+	filename := fmt.Sprintf("audit_logs_%s.csv", time.Now().Format("20060102_150405"))
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Data(http.StatusOK, "text/csv", buf.Bytes())
+}
+
+func redactAuditLogs(logs []models.AuditLog) {
+	for i := range logs {
+		logs[i].OldValue = redactAuditValue(logs[i].OldValue)
+		logs[i].NewValue = redactAuditValue(logs[i].NewValue)
+	}
+}
+
+// redactAuditValue masks sensitive keys anywhere in a JSON snapshot. Values
+// that are not JSON objects or arrays are returned unchanged.
+func redactAuditValue(value string) string {
+	if value == "" {
+		return value
+	}
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+		return value
+	}
+	redacted, err := json.Marshal(redactJSON(parsed))
+	if err != nil {
+		return value
+	}
+	return string(redacted)
+}
+
+func redactJSON(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, child := range t {
+			if sensitiveAuditFields[strings.ToLower(key)] {
+				t[key] = redactedValue
+			} else {
+				t[key] = redactJSON(child)
+			}
+		}
+		return t
+	case []interface{}:
+		for i, child := range t {
+			t[i] = redactJSON(child)
+		}
+		return t
+	default:
+		return v
+	}
+}
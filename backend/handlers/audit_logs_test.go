@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/services"
+)
+
+func TestSearchAuditLogs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	db.AutoMigrate(&models.AuditLog{})
+
+	actor := uint(7)
+	logs := []models.AuditLog{
+		{UserID: &actor, Action: "POST", Resource: "/api/v1/remittances/:id/complete", EntityType: "remittance", EntityID: "1", IPAddress: "127.0.0.1", NewValue: `{"id":1,"status":"completed"}`},
+		{UserID: &actor, Action: "PUT", Resource: "/api/v1/webhooks/:id", EntityType: "webhook", EntityID: "3", IPAddress: "127.0.0.1",
+			OldValue: `{"url":"https://old.example.com","secret":"whsec_old"}`,
+			NewValue: `{"url":"https://new.example.com","secret":"whsec_new","owner":{"email":"ops@example.com"}}`},
+		{Action: "POST", Resource: "/api/v1/auth/register", EntityType: "auth", IPAddress: "127.0.0.1", NewValue: `{"email":"new@example.com","password":"hunter22"}`},
+	}
+	for i := range logs {
+		db.Create(&logs[i])
+	}
+
+	handler := NewAuditLogHandler(db)
+	newRouter := func(role string) *gin.Engine {
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", uint(1))
+			c.Set("role", role)
+			c.Next()
+		})
+		router.GET("/audit-logs", handler.Search)
+		return router
+	}
+
+	search := func(role, query string) ListAuditLogsResponse {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/audit-logs"+query, nil)
+		newRouter(role).ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp ListAuditLogsResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp
+	}
+
+	t.Run("Filter by action", func(t *testing.T) {
+		resp := search("admin", "?action=put")
+		assert.Equal(t, int64(1), resp.TotalCount)
+		if assert.Len(t, resp.Data, 1) {
+			assert.Equal(t, "webhook", resp.Data[0].EntityType)
+		}
+
+		resp = search("admin", "?action=POST&actor_id=7")
+		assert.Equal(t, int64(1), resp.TotalCount)
+	})
+
+	t.Run("Filter by resource", func(t *testing.T) {
+		resp := search("admin", "?entity_type=remittance&entity_id=1")
+		assert.Equal(t, int64(1), resp.TotalCount)
+
+		resp = search("admin", "?entity_type=remittance&entity_id=2")
+		assert.Equal(t, int64(0), resp.TotalCount)
+	})
+
+	t.Run("Newest first", func(t *testing.T) {
+		resp := search("admin", "")
+		if assert.Len(t, resp.Data, 3) {
+			assert.Equal(t, logs[2].ID, resp.Data[0].ID)
+		}
+	})
+
+	t.Run("Sensitive fields redacted for admins", func(t *testing.T) {
+		resp := search("admin", "?entity_type=webhook")
+		if assert.Len(t, resp.Data, 1) {
+			assert.NotContains(t, resp.Data[0].OldValue, "whsec_old")
+			assert.NotContains(t, resp.Data[0].NewValue, "whsec_new")
+			assert.NotContains(t, resp.Data[0].NewValue, "ops@example.com")
+			assert.Contains(t, resp.Data[0].NewValue, "https://new.example.com")
+			assert.Contains(t, resp.Data[0].NewValue, redactedValue)
+		}
+	})
+
+	t.Run("Superadmins see full snapshots", func(t *testing.T) {
+		resp := search("superadmin", "?entity_type=webhook")
+		if assert.Len(t, resp.Data, 1) {
+			assert.Contains(t, resp.Data[0].NewValue, "whsec_new")
+			assert.Contains(t, resp.Data[0].NewValue, "ops@example.com")
+		}
+	})
+
+	t.Run("CSV export is redacted", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/audit-logs?format=csv&entity_type=auth", nil)
+		newRouter("admin").ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		assert.Len(t, lines, 2)
+		assert.NotContains(t, w.Body.String(), "hunter22")
+	})
+
+	t.Run("Invalid date rejected", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/audit-logs?from=yesterday", nil)
+		newRouter("admin").ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestCompleteRemittanceAuditStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	db.AutoMigrate(&models.AuditLog{})
+
+	payment := models.Payment{SenderID: 1, RecipientID: 2, Amount: 50, Currency: "USD", Status: "processing", TxHash: testTxHash}
+	db.Create(&payment)
+
+	cfg := &config.Config{}
+	handler := &RemittanceHandler{db: db, config: cfg, fees: services.NewFeeService(cfg)}
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(9))
+		c.Set("role", "admin")
+		c.Next()
+	})
+	router.Use(middleware.AuditTrail(db))
+	router.POST("/api/v1/remittances/:id/complete", handler.CompleteRemittance)
+
+	complete := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/remittances/%d/complete", payment.ID), nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := complete()
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var entries []models.AuditLog
+	db.Find(&entries)
+	if assert.Len(t, entries, 1) {
+		entry := entries[0]
+		assert.Equal(t, uint(9), *entry.UserID)
+		assert.Equal(t, "remittance", entry.EntityType)
+		assert.Equal(t, fmt.Sprint(payment.ID), entry.EntityID)
+		assert.Equal(t, "processing", entry.OldStatus)
+		assert.Equal(t, "completed", entry.NewStatus)
+	}
+
+	t.Run("Rejected action is not audited", func(t *testing.T) {
+		w := complete()
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		var count int64
+		db.Model(&models.AuditLog{}).Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Failed audit write does not fail the action", func(t *testing.T) {
+		other := models.Payment{SenderID: 1, RecipientID: 2, Amount: 5, Currency: "USD", Status: "processing", TxHash: testTxHash}
+		db.Create(&other)
+		db.Migrator().DropTable(&models.AuditLog{})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/remittances/%d/complete", other.ID), nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		db.First(&other, other.ID)
+		assert.Equal(t, "completed", other.Status)
+	})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
@@ -8,30 +9,46 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/sirupsen/logrus"
+	"github.com/stellar/go/keypair"
 	"github.com/yourusername/gpay-remit/config"
 	"github.com/yourusername/gpay-remit/errors"
 	"github.com/yourusername/gpay-remit/logger"
 	"github.com/yourusername/gpay-remit/middleware"
 	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/services"
+	"github.com/yourusername/gpay-remit/utils"
 	"gorm.io/gorm"
 )
 
 type AuthHandler struct {
 	DB  *gorm.DB
 	Cfg *config.Config
+	// Abuse, when set, is told about failed logins and registrations and
+	// blocks logins to banned accounts.
+	Abuse *middleware.AbuseDetector
+	// Mailer sends password reset and email verification emails.
+	Mailer services.AccountMailer
 }
 
 func NewAuthHandler(db *gorm.DB, cfg *config.Config) *AuthHandler {
-	return &AuthHandler{DB: db, Cfg: cfg}
+	return &AuthHandler{
+		DB:     db,
+		Cfg:    cfg,
+		Mailer: services.NewEmailService(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPassword, cfg.SMTPFrom, cfg.EmailEnabled),
+	}
 }
 
-// RegisterRequest is the request body for user registration.
+// RegisterRequest is the request body for user registration. A Stellar
+// address is generated when StellarAddress is left empty. SignedChallenge is
+// a challenge from OwnershipChallenge signed by StellarAddress; it is only
+// required when the address already has remittances waiting for it.
 type RegisterRequest struct {
-	Email          string `json:"email" binding:"required,email"`
-	Name           string `json:"name" binding:"required"`
-	Password       string `json:"password" binding:"required"`
-	StellarAddress string `json:"stellar_address" binding:"required"`
-	Country        string `json:"country"`
+	Email           string `json:"email" binding:"required,email"`
+	Name            string `json:"name" binding:"required"`
+	Password        string `json:"password" binding:"required"`
+	StellarAddress  string `json:"stellar_address"`
+	Country         string `json:"country"`
+	SignedChallenge string `json:"signed_challenge"`
 }
 
 // LoginRequest is the request body for user login.
@@ -45,15 +62,28 @@ type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
-// Register creates a new user account with a bcrypt-hashed password.
+// VerifyTwoFactorRequest is the request body for confirming a TOTP code.
+type VerifyTwoFactorRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// totpIssuer names the service in authenticator apps.
+const totpIssuer = "Gpay-Remit"
+
+// Register creates a new user account with a bcrypt-hashed password. A user
+// who brings no Stellar address gets a fresh keypair, whose secret seed is
+// kept encrypted on the user and never returned; without field encryption
+// an address must be given. Registering an address that already received
+// remittances takes over its placeholder account, and needs a signed
+// ownership challenge.
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.Error(errors.NewValidationError("Invalid request body", err.Error()))
+		c.Error(errors.NewBindingError(err))
 		return
 	}
 
-	hash, err := models.HashPassword(req.Password)
+	hash, err := models.HashPasswordWithCost(req.Password, h.bcryptCost())
 	if err != nil {
 		logger.Log.WithFields(logrus.Fields{
 			"endpoint": "/auth/register",
@@ -62,17 +92,73 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
+	var seed string
+	if req.StellarAddress == "" {
+		if !models.FieldsEncrypted() {
+			c.Error(errors.NewValidationError("stellar_address is required while field encryption is not configured", nil))
+			return
+		}
+		kp, err := keypair.Random()
+		if err != nil {
+			c.Error(errors.NewInternalError("Failed to generate Stellar address", err))
+			return
+		}
+		req.StellarAddress = kp.Address()
+		seed = kp.Seed()
+	}
+
 	user := models.User{
-		Email:          req.Email,
-		Name:           req.Name,
-		PasswordHash:   hash,
-		StellarAddress: req.StellarAddress,
-		Country:        req.Country,
+		Email:             req.Email,
+		Name:              req.Name,
+		PasswordHash:      hash,
+		StellarAddress:    req.StellarAddress,
+		StellarSecretSeed: models.EncryptedString(seed),
+		Country:           req.Country,
 	}
 
-	if err := h.DB.Create(&user).Error; err != nil {
+	// A placeholder created for a remittance to this address is taken over, so
+	// the new account keeps its ID and the remittances already sent to it.
+	// Only the holder of the address's key may do that.
+	placeholder, err := services.NewRecipientService(h.DB).Placeholder(req.StellarAddress)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to create user", err))
+		return
+	}
+	if placeholder != nil {
+		if req.SignedChallenge == "" {
+			c.Error(errors.NewForbiddenError("This Stellar address has remittances waiting; sign a challenge from /auth/stellar/challenge and send it as signed_challenge"))
+			return
+		}
+		if err := utils.VerifyOwnershipChallenge(h.Cfg.JWTSecret, req.StellarAddress, h.Cfg.NetworkPassphrase, req.SignedChallenge, time.Now()); err != nil {
+			c.Error(errors.NewForbiddenError("signed_challenge is not a current challenge signed by the Stellar address"))
+			return
+		}
+
+		result := h.DB.Model(placeholder).Where("unregistered = ?", true).Updates(map[string]interface{}{
+			"email":         user.Email,
+			"name":          user.Name,
+			"password_hash": user.PasswordHash,
+			"country":       user.Country,
+			"unregistered":  false,
+		})
+		if result.Error == nil && result.RowsAffected == 0 {
+			c.Error(errors.NewConflictError("Stellar address already registered"))
+			return
+		}
+		err = result.Error
+		if err == nil {
+			err = h.DB.First(&user, placeholder.ID).Error
+		}
+	} else {
+		err = h.DB.Create(&user).Error
+	}
+	if err != nil {
 		if strings.Contains(err.Error(), "unique") || strings.Contains(err.Error(), "duplicate") || strings.Contains(err.Error(), "UNIQUE") {
-			c.Error(errors.NewConflictError("Email already registered"))
+			if strings.Contains(err.Error(), "stellar_address") {
+				c.Error(errors.NewConflictError("Stellar address already registered"))
+			} else {
+				c.Error(errors.NewConflictError("Email already registered"))
+			}
 			return
 		}
 		logger.Log.WithFields(logrus.Fields{
@@ -82,10 +168,20 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	logger.Log.WithFields(logrus.Fields{
+	if h.Abuse != nil {
+		h.Abuse.RecordRegistration(c.ClientIP())
+	}
+
+	log := logger.Log.WithFields(logrus.Fields{
 		"user_id":  user.ID,
 		"endpoint": "/auth/register",
-	}).Info("User registered")
+	})
+	// The account is created either way; the user can ask for another link
+	// through ResendVerification.
+	if err := h.sendVerificationEmail(&user); err != nil {
+		log.WithError(err).Error("Failed to send verification email")
+	}
+	log.Info("User registered")
 
 	// Return the user object — PasswordHash is excluded via json:"-" on the model.
 	c.JSON(http.StatusCreated, user)
@@ -95,18 +191,32 @@ func (h *AuthHandler) Register(c *gin.Context) {
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.Error(errors.NewValidationError("Invalid request body", err.Error()))
+		c.Error(errors.NewBindingError(err))
 		return
 	}
 
+	if h.Abuse != nil {
+		if _, banned := h.Abuse.Banned(middleware.AccountBanKey(req.Email)); banned {
+			c.Error(errors.NewForbiddenError("Too many failed login attempts; try again later"))
+			return
+		}
+	}
+
 	var user models.User
 	if err := h.DB.Where("email = ?", req.Email).First(&user).Error; err != nil {
-		c.Error(errors.NewUnauthorizedError("Invalid credentials"))
+		h.recordFailedLogin(c, req.Email)
+		c.Error(errors.NewUnauthorizedError("Invalid credentials").WithKey("error.invalid_credentials"))
 		return
 	}
 
 	if !user.IsActive {
-		c.Error(errors.NewForbiddenError("User account is inactive"))
+		c.Error(errors.NewForbiddenError("User account is inactive").WithKey("error.account_inactive"))
+		return
+	}
+
+	now := time.Now()
+	if user.IsLocked(now) {
+		c.Error(accountLockedError(c, *user.LockedUntil))
 		return
 	}
 
@@ -115,19 +225,33 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			"user_id":  user.ID,
 			"endpoint": "/auth/login",
 		}).Warn("Failed login attempt")
-		c.Error(errors.NewUnauthorizedError("Invalid credentials"))
+		h.recordFailedLogin(c, req.Email)
+		lockedUntil, err := h.recordFailedPassword(&user, now)
+		if err != nil {
+			logger.Log.WithField("user_id", user.ID).WithError(err).Error("Failed to record failed login")
+		}
+		if lockedUntil != nil {
+			c.Error(accountLockedError(c, *lockedUntil))
+			return
+		}
+		c.Error(errors.NewUnauthorizedError("Invalid credentials").WithKey("error.invalid_credentials"))
 		return
 	}
 
-	accessToken, err := middleware.GenerateToken(user.ID, user.Role, h.Cfg.JWTSecret, 15*time.Minute)
-	if err != nil {
-		c.Error(errors.NewInternalError("Failed to generate access token", err))
-		return
+	if user.FailedLoginAttempts > 0 || user.LockedUntil != nil {
+		if err := h.DB.Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+			"failed_login_attempts": 0,
+			"locked_until":          nil,
+		}).Error; err != nil {
+			logger.Log.WithField("user_id", user.ID).WithError(err).Error("Failed to reset failed login count")
+		}
 	}
 
-	refreshToken, err := middleware.GenerateToken(user.ID, user.Role, h.Cfg.JWTRefreshSecret, 7*24*time.Hour)
+	h.upgradePasswordHash(user, req.Password)
+
+	accessToken, refreshToken, err := h.issueTokens(h.DB, &user)
 	if err != nil {
-		c.Error(errors.NewInternalError("Failed to generate refresh token", err))
+		c.Error(errors.NewInternalError("Failed to issue tokens", err))
 		return
 	}
 
@@ -142,49 +266,338 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	})
 }
 
+// bcryptCost is the cost new password hashes are made with.
+func (h *AuthHandler) bcryptCost() int {
+	if h.Cfg.BcryptCost == 0 {
+		return models.DefaultBcryptCost
+	}
+	return h.Cfg.BcryptCost
+}
+
+// upgradePasswordHash rehashes a just-verified password in the background
+// when its stored hash was made with a lower cost than the configured one.
+// The update is guarded on the old hash, so a password changed in the
+// meantime is left alone. Failures are only logged: the login has already
+// succeeded.
+func (h *AuthHandler) upgradePasswordHash(user models.User, password string) {
+	cost := h.bcryptCost()
+	if !models.NeedsRehash(user.PasswordHash, cost) {
+		return
+	}
+	go func() {
+		log := logger.Log.WithFields(logrus.Fields{
+			"user_id":  user.ID,
+			"endpoint": "/auth/login",
+		})
+		hash, err := models.RehashPassword(password, cost)
+		if err != nil {
+			log.WithError(err).Warn("Failed to rehash password")
+			return
+		}
+		err = h.DB.Model(&models.User{}).
+			Where("id = ? AND password_hash = ?", user.ID, user.PasswordHash).
+			Update("password_hash", hash).Error
+		if err != nil {
+			log.WithError(err).Warn("Failed to store upgraded password hash")
+			return
+		}
+		log.Info("Upgraded password hash cost")
+	}()
+}
+
+// recordFailedPassword counts a wrong password for user and, once
+// LoginMaxAttempts are reached in a row, locks the account for
+// LoginLockoutDuration and starts the count again. It returns the end of the
+// lock when this attempt applied one.
+func (h *AuthHandler) recordFailedPassword(user *models.User, now time.Time) (*time.Time, error) {
+	if h.Cfg.LoginMaxAttempts <= 0 {
+		return nil, nil
+	}
+
+	var lockedUntil *time.Time
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", user.ID).
+			UpdateColumn("failed_login_attempts", gorm.Expr("failed_login_attempts + 1")).Error; err != nil {
+			return err
+		}
+		var attempts []int
+		if err := tx.Model(&models.User{}).Where("id = ?", user.ID).Pluck("failed_login_attempts", &attempts).Error; err != nil {
+			return err
+		}
+		if len(attempts) == 0 || attempts[0] < h.Cfg.LoginMaxAttempts {
+			return nil
+		}
+		until := now.Add(h.Cfg.LoginLockoutDuration)
+		if err := tx.Model(&models.User{}).Where("id = ?", user.ID).UpdateColumns(map[string]interface{}{
+			"failed_login_attempts": 0,
+			"locked_until":          until,
+		}).Error; err != nil {
+			return err
+		}
+		lockedUntil = &until
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if lockedUntil != nil {
+		logger.Log.WithFields(logrus.Fields{
+			"user_id":      user.ID,
+			"locked_until": lockedUntil.Format(time.RFC3339),
+		}).Warn("Account locked after repeated failed logins")
+	}
+	return lockedUntil, nil
+}
+
+// accountLockedError reports a login to an account locked until until, with
+// a Retry-After header for clients that honour it.
+func accountLockedError(c *gin.Context, until time.Time) *errors.AppError {
+	c.Header("Retry-After", fmt.Sprintf("%d", int(time.Until(until).Seconds())+1))
+	return errors.NewAccountLockedError("Account locked after too many failed login attempts; try again later",
+		map[string]interface{}{"locked_until": until})
+}
+
+func (h *AuthHandler) recordFailedLogin(c *gin.Context, email string) {
+	if h.Abuse != nil {
+		h.Abuse.RecordFailedLogin(c.ClientIP(), email)
+	}
+}
+
 // Refresh validates a refresh token and issues new access and refresh tokens.
+// Each refresh token can be used once: it is revoked as its replacement is
+// issued. Presenting one that was already revoked is taken as a sign it was
+// stolen, and every refresh token of the user is revoked.
 func (h *AuthHandler) Refresh(c *gin.Context) {
 	var req RefreshTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.Error(errors.NewValidationError("Invalid request body", err.Error()))
+		c.Error(errors.NewBindingError(err))
 		return
 	}
 
 	claims := &middleware.Claims{}
 	token, err := jwt.ParseWithClaims(req.RefreshToken, claims, func(token *jwt.Token) (interface{}, error) {
 		return []byte(h.Cfg.JWTRefreshSecret), nil
-	})
+	}, middleware.ParserOptions(h.Cfg)...)
 
-	if err != nil || !token.Valid {
+	if err != nil || !token.Valid || claims.ID == "" {
 		c.Error(errors.NewUnauthorizedError("Invalid or expired refresh token"))
 		return
 	}
 
 	var user models.User
 	if err := h.DB.First(&user, claims.UserID).Error; err != nil {
-		c.Error(errors.NewUnauthorizedError("User not found"))
+		c.Error(errors.NewUnauthorizedError("User not found").WithKey("error.user_not_found"))
 		return
 	}
 
 	if !user.IsActive {
-		c.Error(errors.NewForbiddenError("User account is inactive"))
+		c.Error(errors.NewForbiddenError("User account is inactive").WithKey("error.account_inactive"))
+		return
+	}
+
+	var accessToken, refreshToken string
+	rotated := false
+	err = h.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.RefreshToken{}).
+			Where("jti = ? AND user_id = ? AND revoked = ?", claims.ID, user.ID, false).
+			Updates(map[string]interface{}{"revoked": true, "revoked_at": time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		rotated = true
+		var err error
+		accessToken, refreshToken, err = h.issueTokens(tx, &user)
+		return err
+	})
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to rotate refresh token", err))
+		return
+	}
+	if !rotated {
+		h.handleRefreshTokenReuse(claims.ID, user.ID)
+		c.Error(errors.NewUnauthorizedError("Invalid or expired refresh token"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+	})
+}
+
+// Logout revokes every refresh token of the authenticated user, signing
+// them out on all devices. Access tokens already issued stay valid until they
+// expire.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
 		return
 	}
 
-	accessToken, err := middleware.GenerateToken(user.ID, user.Role, h.Cfg.JWTSecret, 15*time.Minute)
+	revoked, err := h.revokeRefreshTokens(h.DB, userID.(uint))
 	if err != nil {
-		c.Error(errors.NewInternalError("Failed to generate access token", err))
+		c.Error(errors.NewInternalError("Failed to revoke refresh tokens", err))
+		return
+	}
+
+	logger.Log.WithFields(logrus.Fields{
+		"user_id":  userID,
+		"endpoint": "/auth/logout",
+	}).Info("User logged out")
+
+	c.JSON(http.StatusOK, gin.H{"revoked": revoked})
+}
+
+// issueTokens generates an access token and a refresh token for user, and
+// records the refresh token through db so it can be rotated and revoked.
+func (h *AuthHandler) issueTokens(db *gorm.DB, user *models.User) (string, string, error) {
+	accessToken, err := middleware.GenerateToken(user.ID, user.Role, h.Cfg.JWTSecret, h.Cfg.AccessTokenTTL)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	refreshToken, claims, err := middleware.GenerateRefreshToken(user.ID, user.Role, h.Cfg.JWTRefreshSecret, h.Cfg.RefreshTokenTTL)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	record := models.RefreshToken{UserID: user.ID, JTI: claims.ID, ExpiresAt: claims.ExpiresAt.Time}
+	if err := db.Create(&record).Error; err != nil {
+		return "", "", fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	return accessToken, refreshToken, nil
+}
+
+// handleRefreshTokenReuse revokes every refresh token of the user when jti
+// names one that was already revoked: either the token or its replacement is
+// in someone else's hands. A token that was never recorded is just rejected.
+func (h *AuthHandler) handleRefreshTokenReuse(jti string, userID uint) {
+	var stored models.RefreshToken
+	if err := h.DB.Where("jti = ? AND user_id = ?", jti, userID).First(&stored).Error; err != nil || !stored.Revoked {
+		return
+	}
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id":  userID,
+		"endpoint": "/auth/refresh",
+	})
+	if _, err := h.revokeRefreshTokens(h.DB, userID); err != nil {
+		log.WithError(err).Error("Failed to revoke refresh tokens after reuse")
+		return
+	}
+	log.Warn("Revoked refresh token reused; revoked all refresh tokens")
+}
+
+// revokeRefreshTokens revokes every outstanding refresh token of the user and
+// returns how many there were.
+func (h *AuthHandler) revokeRefreshTokens(db *gorm.DB, userID uint) (int64, error) {
+	result := db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked = ?", userID, false).
+		Updates(map[string]interface{}{"revoked": true, "revoked_at": time.Now()})
+	return result.RowsAffected, result.Error
+}
+
+// EnrollTwoFactor generates a TOTP secret for the authenticated user and
+// returns it with its otpauth:// URL, which authenticator apps enrol from
+// directly or through a QR code. Two-factor authentication is only enabled
+// once VerifyTwoFactor accepts a code; enrolling again before then replaces
+// the secret. Enrolment is refused unless field encryption is configured,
+// so secrets are never stored as plaintext.
+func (h *AuthHandler) EnrollTwoFactor(c *gin.Context) {
+	if !models.FieldsEncrypted() {
+		c.Error(errors.NewAppError(http.StatusServiceUnavailable, errors.CodeInternal,
+			"Two-factor authentication is unavailable until field encryption is configured", nil, nil))
+		return
+	}
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	if user.TOTPEnabled {
+		c.Error(errors.NewConflictError("Two-factor authentication is already enabled"))
 		return
 	}
 
-	refreshToken, err := middleware.GenerateToken(user.ID, user.Role, h.Cfg.JWTRefreshSecret, 7*24*time.Hour)
+	secret, err := utils.GenerateTOTPSecret()
 	if err != nil {
-		c.Error(errors.NewInternalError("Failed to generate refresh token", err))
+		c.Error(errors.NewInternalError("Failed to generate two-factor secret", err))
+		return
+	}
+	if err := h.DB.Model(&user).Updates(map[string]interface{}{
+		"totp_secret":    models.EncryptedString(secret),
+		"totp_last_step": 0,
+	}).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to store two-factor secret", err))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"access_token":  accessToken,
-		"refresh_token": refreshToken,
+		"secret":      secret,
+		"otpauth_url": utils.TOTPURL(totpIssuer, user.Email, secret),
 	})
 }
+
+// VerifyTwoFactor checks a code from the user's authenticator. The first
+// accepted code enables two-factor authentication.
+func (h *AuthHandler) VerifyTwoFactor(c *gin.Context) {
+	var req VerifyTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	if user.TOTPSecret == "" {
+		c.Error(errors.NewConflictError("Two-factor authentication has not been enrolled"))
+		return
+	}
+
+	accepted, err := middleware.ConsumeTOTPCode(h.DB, &user, req.Code, time.Now())
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to verify two-factor code", err))
+		return
+	}
+	if !accepted {
+		c.Error(errors.NewInvalidTwoFactorCodeError("Invalid or already used two-factor code"))
+		return
+	}
+
+	if !user.TOTPEnabled {
+		if err := h.DB.Model(&user).Update("totp_enabled", true).Error; err != nil {
+			c.Error(errors.NewInternalError("Failed to enable two-factor authentication", err))
+			return
+		}
+		logger.Log.WithFields(logrus.Fields{
+			"user_id":  user.ID,
+			"endpoint": "/auth/2fa/verify",
+		}).Info("Two-factor authentication enabled")
+	}
+
+	c.JSON(http.StatusOK, gin.H{"totp_enabled": true})
+}
+
+// currentUser loads the authenticated user, reporting a failure on the
+// context.
+func (h *AuthHandler) currentUser(c *gin.Context) (models.User, bool) {
+	var user models.User
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return user, false
+	}
+	if err := h.DB.First(&user, userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewUnauthorizedError("User not found").WithKey("error.user_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch user", err))
+		}
+		return user, false
+	}
+	return user, true
+}
@@ -2,15 +2,25 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/encryption"
+	"github.com/yourusername/gpay-remit/middleware"
 	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/services"
+	"github.com/yourusername/gpay-remit/utils"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func setupAuthHandler(t *testing.T) (*AuthHandler, *gin.Engine) {
@@ -18,11 +28,15 @@ func setupAuthHandler(t *testing.T) (*AuthHandler, *gin.Engine) {
 	gin.SetMode(gin.TestMode)
 	db := setupTestDB()
 	cfg := &config.Config{
-		JWTSecret:        "test-secret",
-		JWTRefreshSecret: "test-refresh-secret",
+		JWTSecret:         "test-secret",
+		JWTRefreshSecret:  "test-refresh-secret",
+		AccessTokenTTL:    15 * time.Minute,
+		RefreshTokenTTL:   7 * 24 * time.Hour,
+		NetworkPassphrase: network.TestNetworkPassphrase,
 	}
 	handler := NewAuthHandler(db, cfg)
 	router := gin.New()
+	router.Use(middleware.ErrorHandler())
 	router.POST("/auth/register", handler.Register)
 	router.POST("/auth/login", handler.Login)
 	router.POST("/auth/refresh", handler.Refresh)
@@ -30,7 +44,7 @@ func setupAuthHandler(t *testing.T) (*AuthHandler, *gin.Engine) {
 }
 
 func TestRegister(t *testing.T) {
-	_, router := setupAuthHandler(t)
+	handler, router := setupAuthHandler(t)
 
 	t.Run("Valid Registration", func(t *testing.T) {
 		body, _ := json.Marshal(map[string]string{
@@ -49,6 +63,61 @@ func TestRegister(t *testing.T) {
 		json.Unmarshal(w.Body.Bytes(), &resp)
 		assert.Equal(t, "test@example.com", resp["email"])
 		assert.Nil(t, resp["password_hash"])
+		assert.Nil(t, resp["stellar_secret_seed"])
+	})
+
+	t.Run("Generates a Stellar address when none is given", func(t *testing.T) {
+		register := func() *httptest.ResponseRecorder {
+			body, _ := json.Marshal(map[string]string{
+				"email":    "generated@example.com",
+				"name":     "Generated User",
+				"password": "Secure@123",
+			})
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/auth/register", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		w := register()
+		assert.Equal(t, http.StatusBadRequest, w.Code, "a seed is never stored unencrypted")
+
+		provider, err := encryption.NewStaticKeys(1, map[int][]byte{1: bytes.Repeat([]byte{7}, encryption.KeySize)})
+		require.NoError(t, err)
+		models.SetFieldCipher(encryption.NewCipher(provider))
+		defer models.SetFieldCipher(nil)
+
+		w = register()
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		assert.NotContains(t, w.Body.String(), "seed")
+		var resp models.User
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+		var stored models.User
+		require.NoError(t, handler.DB.First(&stored, resp.ID).Error)
+		kp, err := keypair.ParseFull(stored.StellarSecretSeed.String())
+		if assert.NoError(t, err) {
+			assert.Equal(t, kp.Address(), resp.StellarAddress)
+		}
+		var raw string
+		handler.DB.Raw("SELECT stellar_secret_seed FROM users WHERE id = ?", resp.ID).Scan(&raw)
+		assert.NotContains(t, raw, stored.StellarSecretSeed.String())
+	})
+
+	t.Run("Duplicate Stellar Address Returns 409", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]string{
+			"email":           "second@example.com",
+			"name":            "Second User",
+			"password":        "Secure@123",
+			"stellar_address": "GDQJUTQYK2MQX2VGDR2FYWLIYAQIEGXTQVTFEMGH6DNHFMHIDENFINMJTEST",
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/auth/register", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "Stellar address already registered")
 	})
 
 	t.Run("Duplicate Email Returns 409", func(t *testing.T) {
@@ -133,6 +202,92 @@ func TestRegister(t *testing.T) {
 	})
 }
 
+func TestRegisterClaimsPlaceholderRecipient(t *testing.T) {
+	handler, router := setupAuthHandler(t)
+	router.POST("/auth/stellar/challenge", handler.OwnershipChallenge)
+	owner, _ := keypair.Random()
+	address := owner.Address()
+
+	placeholder, err := services.NewRecipientService(handler.DB).Resolve(address)
+	assert.NoError(t, err)
+	payment := models.Payment{SenderID: 1, RecipientID: placeholder.ID, RecipientAccount: address, Amount: 10, Currency: "USDC", Status: "pending"}
+	handler.DB.Create(&payment)
+
+	register := func(signedChallenge string) *httptest.ResponseRecorder {
+		return serveJSON(router, http.MethodPost, "/auth/register", RegisterRequest{
+			Email:           "claim@example.com",
+			Name:            "Claiming User",
+			Password:        "Secure@123",
+			StellarAddress:  address,
+			SignedChallenge: signedChallenge,
+		})
+	}
+	challenge := func() string {
+		w := serveJSON(router, http.MethodPost, "/auth/stellar/challenge", OwnershipChallengeRequest{StellarAddress: address})
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			ChallengeXDR string `json:"challenge_xdr"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp.ChallengeXDR
+	}
+	sign := func(envelope string, kp *keypair.Full) string {
+		signed, err := utils.SignTx(context.Background(), envelope, kp.Seed(), handler.Cfg.NetworkPassphrase)
+		assert.NoError(t, err)
+		return signed
+	}
+
+	t.Run("Without proof of the key", func(t *testing.T) {
+		w := register("")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		intruder, _ := keypair.Random()
+		w = register(sign(challenge(), intruder))
+		assert.Equal(t, http.StatusForbidden, w.Code, "signed by another key")
+
+		w = register(challenge())
+		assert.Equal(t, http.StatusForbidden, w.Code, "unsigned")
+
+		expired, err := utils.BuildOwnershipChallenge(handler.Cfg.JWTSecret, address, time.Now().Add(-time.Hour), time.Now().Add(-time.Minute))
+		assert.NoError(t, err)
+		w = register(sign(expired, owner))
+		assert.Equal(t, http.StatusForbidden, w.Code, "expired")
+
+		var user models.User
+		handler.DB.First(&user, placeholder.ID)
+		assert.True(t, user.Unregistered)
+	})
+
+	t.Run("With a signed challenge", func(t *testing.T) {
+		signed := sign(challenge(), owner)
+		w := register(signed)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.Equal(t, float64(placeholder.ID), resp["id"])
+		assert.Equal(t, "claim@example.com", resp["email"])
+		assert.Nil(t, resp["unregistered"])
+
+		var user models.User
+		handler.DB.First(&user, placeholder.ID)
+		assert.False(t, user.Unregistered)
+		assert.Equal(t, "Claiming User", user.Name)
+		assert.True(t, models.ComparePassword(user.PasswordHash, "Secure@123"))
+
+		var count int64
+		handler.DB.Model(&models.User{}).Where("stellar_address = ?", address).Count(&count)
+		assert.Equal(t, int64(1), count)
+
+		var received models.Payment
+		handler.DB.First(&received, payment.ID)
+		assert.Equal(t, user.ID, received.RecipientID)
+
+		w = register(signed)
+		assert.Equal(t, http.StatusConflict, w.Code, "the challenge cannot claim the account twice")
+	})
+}
+
 func TestLogin(t *testing.T) {
 	_, router := setupAuthHandler(t)
 
@@ -220,9 +375,10 @@ func TestLogin(t *testing.T) {
 			Name:           "Inactive User",
 			PasswordHash:   hash,
 			StellarAddress: "GDQJUTQYK2MQX2VGDR2FYWLIYAQIEGXTQVTFEMGH6DNHFMHIDENFINIAC",
-			IsActive:       false,
 		}
 		handler.DB.Create(&user)
+		// is_active defaults to true, so a false zero value is not inserted.
+		handler.DB.Model(&user).Update("is_active", false)
 
 		body, _ := json.Marshal(map[string]string{
 			"email":    "inactive@example.com",
@@ -235,3 +391,325 @@ func TestLogin(t *testing.T) {
 		assert.Equal(t, http.StatusForbidden, w.Code)
 	})
 }
+
+func TestPasswordHashCost(t *testing.T) {
+	handler, router := setupAuthHandler(t)
+	handler.Cfg.BcryptCost = bcrypt.MinCost + 1
+	// The rehash runs in its own goroutine; one connection keeps it on the
+	// same in-memory database.
+	sqlDB, _ := handler.DB.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	login := func(email, password string) int {
+		body, _ := json.Marshal(LoginRequest{Email: email, Password: password})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/auth/login", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	storedCost := func(email string) int {
+		var user models.User
+		handler.DB.Where("email = ?", email).First(&user)
+		cost, _ := bcrypt.Cost([]byte(user.PasswordHash))
+		return cost
+	}
+
+	t.Run("Registration hashes at the configured cost", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]string{
+			"email":           "cost@example.com",
+			"name":            "Cost User",
+			"password":        "Secure@Cost1",
+			"stellar_address": "GDQJUTQYK2MQX2VGDR2FYWLIYAQIEGXTQVTFEMGH6DNHFMHIDENFINCOST",
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/auth/register", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, bcrypt.MinCost+1, storedCost("cost@example.com"))
+	})
+
+	t.Run("Login upgrades a cheaper hash", func(t *testing.T) {
+		// The password predates the strength rules; it is upgraded all the same.
+		hash, _ := models.RehashPassword("legacy", bcrypt.MinCost)
+		user := models.User{Email: "legacy@example.com", Name: "Legacy User", PasswordHash: hash, StellarAddress: "GLEGACY", IsActive: true}
+		handler.DB.Create(&user)
+
+		assert.Equal(t, http.StatusUnauthorized, login("legacy@example.com", "wrong"))
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, bcrypt.MinCost, storedCost("legacy@example.com"), "a failed login never rehashes")
+
+		assert.Equal(t, http.StatusOK, login("legacy@example.com", "legacy"))
+		assert.Eventually(t, func() bool {
+			return storedCost("legacy@example.com") == bcrypt.MinCost+1
+		}, time.Second, 10*time.Millisecond)
+
+		var stored models.User
+		handler.DB.First(&stored, user.ID)
+		assert.True(t, models.ComparePassword(stored.PasswordHash, "legacy"))
+		assert.Equal(t, http.StatusOK, login("legacy@example.com", "legacy"))
+	})
+}
+
+func TestRefreshClockSkew(t *testing.T) {
+	handler, router := setupAuthHandler(t)
+	handler.Cfg.JWTClockSkew = 30 * time.Second
+
+	user := models.User{Email: "skew@example.com", Name: "Skew User", PasswordHash: "x", StellarAddress: "GSKEW", IsActive: true}
+	handler.DB.Create(&user)
+
+	refresh := func(expiry time.Duration) int {
+		token, claims, _ := middleware.GenerateRefreshToken(user.ID, user.Role, handler.Cfg.JWTRefreshSecret, expiry)
+		handler.DB.Create(&models.RefreshToken{UserID: user.ID, JTI: claims.ID, ExpiresAt: claims.ExpiresAt.Time})
+		body, _ := json.Marshal(RefreshTokenRequest{RefreshToken: token})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/auth/refresh", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, refresh(-10*time.Second))
+	assert.Equal(t, http.StatusUnauthorized, refresh(-2*time.Minute))
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	handler, router := setupAuthHandler(t)
+
+	hash, _ := models.HashPassword("Secure@Rotate1")
+	user := models.User{Email: "rotate@example.com", Name: "Rotate", PasswordHash: hash, StellarAddress: "GROTATE", IsActive: true}
+	handler.DB.Create(&user)
+	router.POST("/auth/logout", func(c *gin.Context) {
+		c.Set("userID", user.ID)
+		c.Next()
+	}, handler.Logout)
+
+	post := func(path string, body interface{}) (int, map[string]interface{}) {
+		raw, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, path, bytes.NewBuffer(raw))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+	login := func() string {
+		code, resp := post("/auth/login", LoginRequest{Email: "rotate@example.com", Password: "Secure@Rotate1"})
+		assert.Equal(t, http.StatusOK, code)
+		token, _ := resp["refresh_token"].(string)
+		return token
+	}
+	refresh := func(token string) (int, string) {
+		code, resp := post("/auth/refresh", RefreshTokenRequest{RefreshToken: token})
+		next, _ := resp["refresh_token"].(string)
+		return code, next
+	}
+	active := func() int64 {
+		var count int64
+		handler.DB.Model(&models.RefreshToken{}).Where("user_id = ? AND revoked = ?", user.ID, false).Count(&count)
+		return count
+	}
+
+	t.Run("Each refresh rotates the token", func(t *testing.T) {
+		first := login()
+		code, second := refresh(first)
+		assert.Equal(t, http.StatusOK, code)
+		assert.NotEqual(t, first, second)
+
+		code, third := refresh(second)
+		assert.Equal(t, http.StatusOK, code)
+		assert.NotEmpty(t, third)
+		assert.Equal(t, int64(1), active())
+	})
+
+	t.Run("Reusing a rotated token revokes every token", func(t *testing.T) {
+		first := login()
+		code, second := refresh(first)
+		assert.Equal(t, http.StatusOK, code)
+
+		code, _ = refresh(first)
+		assert.Equal(t, http.StatusUnauthorized, code)
+		assert.Zero(t, active())
+
+		code, _ = refresh(second)
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+
+	t.Run("Unrecorded tokens are rejected", func(t *testing.T) {
+		token, _ := middleware.GenerateToken(user.ID, user.Role, handler.Cfg.JWTRefreshSecret, time.Hour)
+		code, _ := refresh(token)
+		assert.Equal(t, http.StatusUnauthorized, code)
+
+		token, _, _ = middleware.GenerateRefreshToken(user.ID, user.Role, handler.Cfg.JWTRefreshSecret, time.Hour)
+		code, _ = refresh(token)
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+
+	t.Run("Logout revokes every token", func(t *testing.T) {
+		first := login()
+		second := login()
+		assert.Equal(t, int64(2), active())
+
+		code, resp := post("/auth/logout", nil)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, float64(2), resp["revoked"])
+		assert.Zero(t, active())
+
+		code, _ = refresh(first)
+		assert.Equal(t, http.StatusUnauthorized, code)
+		code, _ = refresh(second)
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+}
+
+func TestTwoFactorEnrollment(t *testing.T) {
+	handler, router := setupAuthHandler(t)
+
+	user := models.User{Email: "totp@example.com", Name: "TOTP", PasswordHash: "x", StellarAddress: "GTOTP", IsActive: true}
+	handler.DB.Create(&user)
+	authed := func(c *gin.Context) {
+		c.Set("userID", user.ID)
+		c.Next()
+	}
+	router.POST("/auth/2fa/enroll", authed, handler.EnrollTwoFactor)
+	router.POST("/auth/2fa/verify", authed, handler.VerifyTwoFactor)
+
+	post := func(path string, body interface{}) (int, map[string]interface{}) {
+		raw, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, path, bytes.NewBuffer(raw))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+
+	code, _ := post("/auth/2fa/verify", VerifyTwoFactorRequest{Code: "123456"})
+	assert.Equal(t, http.StatusConflict, code, "nothing to verify before enrolling")
+
+	code, _ = post("/auth/2fa/enroll", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, code, "secrets are never stored unencrypted")
+	var unenrolled models.User
+	handler.DB.First(&unenrolled, user.ID)
+	assert.Empty(t, unenrolled.TOTPSecret)
+
+	provider, err := encryption.NewStaticKeys(1, map[int][]byte{1: bytes.Repeat([]byte{7}, encryption.KeySize)})
+	require.NoError(t, err)
+	models.SetFieldCipher(encryption.NewCipher(provider))
+	defer models.SetFieldCipher(nil)
+
+	code, resp := post("/auth/2fa/enroll", nil)
+	assert.Equal(t, http.StatusOK, code)
+	secret, _ := resp["secret"].(string)
+	assert.NotEmpty(t, secret)
+	assert.Contains(t, resp["otpauth_url"], "otpauth://totp/Gpay-Remit:totp@example.com?")
+
+	var stored models.User
+	handler.DB.First(&stored, user.ID)
+	assert.Equal(t, secret, stored.TOTPSecret.String())
+	assert.False(t, stored.TOTPEnabled, "enrolment alone does not enable two-factor")
+
+	wrong, _ := utils.TOTPCode(secret, time.Now().Add(-10*utils.TOTPPeriod))
+	code, _ = post("/auth/2fa/verify", VerifyTwoFactorRequest{Code: wrong})
+	assert.Equal(t, http.StatusForbidden, code)
+
+	valid, _ := utils.TOTPCode(secret, time.Now())
+	code, resp = post("/auth/2fa/verify", VerifyTwoFactorRequest{Code: valid})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, resp["totp_enabled"])
+
+	handler.DB.First(&stored, user.ID)
+	assert.True(t, stored.TOTPEnabled)
+
+	code, _ = post("/auth/2fa/enroll", nil)
+	assert.Equal(t, http.StatusConflict, code, "an enabled secret is not replaced")
+}
+
+func TestLoginAbuseBan(t *testing.T) {
+	handler, router := setupAuthHandler(t)
+	handler.Abuse = middleware.NewAbuseDetector(nil, &config.Config{
+		AbuseAccountFailedLogins: 3,
+		AbuseWindow:              time.Minute,
+		AbuseBanDuration:         time.Hour,
+	})
+
+	hash, _ := models.HashPassword("Secure@Ban1")
+	handler.DB.Create(&models.User{Email: "ban@example.com", Name: "Ban", PasswordHash: hash, StellarAddress: "GBANTEST", IsActive: true})
+
+	login := func(password string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]string{"email": "ban@example.com", "password": password})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/auth/login", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusUnauthorized, login("Wrong@Pass1").Code)
+	}
+
+	// Even the right password is refused while the account is banned.
+	assert.Equal(t, http.StatusForbidden, login("Secure@Ban1").Code)
+
+	handler.Abuse.Unban(middleware.AccountBanKey("ban@example.com"), "", nil)
+	assert.Equal(t, http.StatusOK, login("Secure@Ban1").Code)
+}
+
+func TestLoginLockout(t *testing.T) {
+	handler, router := setupAuthHandler(t)
+	handler.Cfg.LoginMaxAttempts = 3
+	handler.Cfg.LoginLockoutDuration = time.Hour
+
+	hash, _ := models.HashPassword("Secure@Lock1")
+	user := models.User{Email: "lock@example.com", Name: "Lock", PasswordHash: hash, StellarAddress: "GLOCK", IsActive: true}
+	handler.DB.Create(&user)
+
+	login := func(password string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(LoginRequest{Email: "lock@example.com", Password: password})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/auth/login", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	stored := func() models.User {
+		var u models.User
+		handler.DB.First(&u, user.ID)
+		return u
+	}
+
+	t.Run("A successful login resets the count", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, login("Wrong@Pass1").Code)
+		assert.Equal(t, http.StatusUnauthorized, login("Wrong@Pass1").Code)
+		assert.Equal(t, 2, stored().FailedLoginAttempts)
+
+		assert.Equal(t, http.StatusOK, login("Secure@Lock1").Code)
+		assert.Zero(t, stored().FailedLoginAttempts)
+	})
+
+	t.Run("Too many failures lock the account", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, login("Wrong@Pass1").Code)
+		assert.Equal(t, http.StatusUnauthorized, login("Wrong@Pass1").Code)
+
+		w := login("Wrong@Pass1")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "AccountLocked")
+		assert.Contains(t, w.Body.String(), "locked_until")
+		assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+		w = login("Secure@Lock1")
+		assert.Equal(t, http.StatusForbidden, w.Code, "the right password does not get past the lock")
+		assert.Contains(t, w.Body.String(), "AccountLocked")
+	})
+
+	t.Run("The lock expires", func(t *testing.T) {
+		handler.DB.Model(&models.User{}).Where("id = ?", user.ID).Update("locked_until", time.Now().Add(-time.Minute))
+
+		assert.Equal(t, http.StatusOK, login("Secure@Lock1").Code)
+		assert.Nil(t, stored().LockedUntil)
+	})
+}
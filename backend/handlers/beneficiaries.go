@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/validators"
+	"gorm.io/gorm"
+)
+
+type BeneficiaryHandler struct {
+	db *gorm.DB
+}
+
+func NewBeneficiaryHandler(db *gorm.DB) *BeneficiaryHandler {
+	return &BeneficiaryHandler{db: db}
+}
+
+type CreateBeneficiaryRequest struct {
+	Name           string `json:"name" binding:"required,max=255"`
+	StellarAddress string `json:"stellar_address" binding:"required"`
+	// Country is an upper-case ISO 3166-1 alpha-2 code.
+	Country           string `json:"country" binding:"required,iso3166_1_alpha2"`
+	Relationship      string `json:"relationship" binding:"omitempty,oneof=family friend business self other"`
+	BankName          string `json:"bank_name" binding:"max=255"`
+	BankAccountNumber string `json:"bank_account_number" binding:"max=64"`
+	BankCode          string `json:"bank_code" binding:"max=34"`
+}
+
+// UpdateBeneficiaryRequest changes a beneficiary. Omitted fields are left as
+// they are; the optional ones are cleared by sending an empty string.
+type UpdateBeneficiaryRequest struct {
+	Name              *string `json:"name" binding:"omitempty,max=255"`
+	StellarAddress    *string `json:"stellar_address"`
+	Country           *string `json:"country" binding:"omitempty,iso3166_1_alpha2"`
+	Relationship      *string `json:"relationship" binding:"omitempty,oneof=family friend business self other"`
+	BankName          *string `json:"bank_name" binding:"omitempty,max=255"`
+	BankAccountNumber *string `json:"bank_account_number" binding:"omitempty,max=64"`
+	BankCode          *string `json:"bank_code" binding:"omitempty,max=34"`
+}
+
+// CreateBeneficiary adds someone to the caller's address book.
+func (h *BeneficiaryHandler) CreateBeneficiary(c *gin.Context) {
+	var req CreateBeneficiaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.Error(errors.NewValidationError("Invalid name", "name cannot be empty"))
+		return
+	}
+	if err := validators.ValidateStellarAddress(req.StellarAddress); err != nil {
+		c.Error(errors.NewValidationError("Invalid Stellar address", err.Error()))
+		return
+	}
+
+	beneficiary := models.Beneficiary{
+		UserID:            userID.(uint),
+		Name:              name,
+		StellarAddress:    req.StellarAddress,
+		Country:           req.Country,
+		Relationship:      req.Relationship,
+		BankName:          strings.TrimSpace(req.BankName),
+		BankAccountNumber: models.EncryptedString(strings.TrimSpace(req.BankAccountNumber)),
+		BankCode:          strings.TrimSpace(req.BankCode),
+	}
+	if err := h.db.Create(&beneficiary).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to create beneficiary", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, beneficiaryResponse(&beneficiary))
+}
+
+// ListBeneficiaries lists the caller's beneficiaries by name.
+func (h *BeneficiaryHandler) ListBeneficiaries(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+
+	var beneficiaries []models.Beneficiary
+	if err := h.db.Where("user_id = ?", userID).Order("name ASC, id ASC").Find(&beneficiaries).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to fetch beneficiaries", err))
+		return
+	}
+
+	response := make([]gin.H, len(beneficiaries))
+	for i := range beneficiaries {
+		response[i] = beneficiaryResponse(&beneficiaries[i])
+	}
+	c.JSON(http.StatusOK, gin.H{"beneficiaries": response})
+}
+
+// GetBeneficiary returns one of the caller's beneficiaries.
+func (h *BeneficiaryHandler) GetBeneficiary(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+
+	beneficiary, appErr := findBeneficiary(h.db, userID.(uint), c.Param("id"))
+	if appErr != nil {
+		c.Error(appErr)
+		return
+	}
+	c.JSON(http.StatusOK, beneficiaryResponse(beneficiary))
+}
+
+// UpdateBeneficiary changes one of the caller's beneficiaries.
+func (h *BeneficiaryHandler) UpdateBeneficiary(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+
+	var req UpdateBeneficiaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+
+	beneficiary, appErr := findBeneficiary(h.db, userID.(uint), c.Param("id"))
+	if appErr != nil {
+		c.Error(appErr)
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			c.Error(errors.NewValidationError("Invalid name", "name cannot be empty"))
+			return
+		}
+		updates["name"] = name
+	}
+	if req.StellarAddress != nil {
+		if err := validators.ValidateStellarAddress(*req.StellarAddress); err != nil {
+			c.Error(errors.NewValidationError("Invalid Stellar address", err.Error()))
+			return
+		}
+		updates["stellar_address"] = *req.StellarAddress
+	}
+	if req.Country != nil {
+		updates["country"] = *req.Country
+	}
+	if req.Relationship != nil {
+		updates["relationship"] = *req.Relationship
+	}
+	if req.BankName != nil {
+		updates["bank_name"] = strings.TrimSpace(*req.BankName)
+	}
+	if req.BankAccountNumber != nil {
+		updates["bank_account_number"] = models.EncryptedString(strings.TrimSpace(*req.BankAccountNumber))
+	}
+	if req.BankCode != nil {
+		updates["bank_code"] = strings.TrimSpace(*req.BankCode)
+	}
+	if len(updates) == 0 {
+		c.Error(errors.NewValidationError("No fields to update", nil))
+		return
+	}
+
+	if err := h.db.Model(beneficiary).Updates(updates).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to update beneficiary", err))
+		return
+	}
+	if err := h.db.First(beneficiary, beneficiary.ID).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to fetch beneficiary", err))
+		return
+	}
+	c.JSON(http.StatusOK, beneficiaryResponse(beneficiary))
+}
+
+// DeleteBeneficiary removes one of the caller's beneficiaries. Remittances
+// already sent to them are unaffected.
+func (h *BeneficiaryHandler) DeleteBeneficiary(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+
+	beneficiary, appErr := findBeneficiary(h.db, userID.(uint), c.Param("id"))
+	if appErr != nil {
+		c.Error(appErr)
+		return
+	}
+	if err := h.db.Delete(beneficiary).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to delete beneficiary", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Beneficiary deleted successfully"})
+}
+
+// findBeneficiary loads beneficiary id from userID's address book. Other
+// users' beneficiaries are reported as not found.
+func findBeneficiary(db *gorm.DB, userID uint, id interface{}) (*models.Beneficiary, *errors.AppError) {
+	var beneficiary models.Beneficiary
+	if err := db.Where("id = ? AND user_id = ?", id, userID).First(&beneficiary).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("Beneficiary not found").WithKey("error.beneficiary_not_found")
+		}
+		return nil, errors.NewInternalError("Failed to fetch beneficiary", err)
+	}
+	return &beneficiary, nil
+}
+
+func beneficiaryResponse(b *models.Beneficiary) gin.H {
+	return gin.H{
+		"id":                  b.ID,
+		"name":                b.Name,
+		"stellar_address":     b.StellarAddress,
+		"country":             b.Country,
+		"relationship":        b.Relationship,
+		"bank_name":           b.BankName,
+		"bank_account_number": b.MaskedAccountNumber(),
+		"bank_code":           b.BankCode,
+		"created_at":          b.CreatedAt,
+		"updated_at":          b.UpdatedAt,
+	}
+}
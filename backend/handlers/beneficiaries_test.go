@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/services"
+	"gorm.io/gorm"
+)
+
+const beneficiaryAddress = "GCEZWKCA5VLDNRLN3RPRJMRZOX3Z6G5CHCGSNFHEYVXM3XOJMDS674JZ"
+
+func setupBeneficiaryRouter(db *gorm.DB, userID uint) *gin.Engine {
+	handler := NewBeneficiaryHandler(db)
+
+	router := newTestRouter(userID, "")
+	router.POST("/beneficiaries", handler.CreateBeneficiary)
+	router.GET("/beneficiaries", handler.ListBeneficiaries)
+	router.GET("/beneficiaries/:id", handler.GetBeneficiary)
+	router.PUT("/beneficiaries/:id", handler.UpdateBeneficiary)
+	router.DELETE("/beneficiaries/:id", handler.DeleteBeneficiary)
+	return router
+}
+
+func TestBeneficiaries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	db.AutoMigrate(&models.Beneficiary{})
+	owner := setupBeneficiaryRouter(db, 1)
+	stranger := setupBeneficiaryRouter(db, 2)
+
+	w := serveJSON(owner, http.MethodPost, "/beneficiaries", CreateBeneficiaryRequest{
+		Name:              " Mama ",
+		StellarAddress:    beneficiaryAddress,
+		Country:           "KE",
+		Relationship:      models.RelationshipFamily,
+		BankName:          "Equity Bank",
+		BankAccountNumber: "0123456789",
+		BankCode:          "EQBLKENA",
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.NotContains(t, w.Body.String(), "0123456789")
+
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "Mama", created["name"])
+	assert.Equal(t, "****6789", created["bank_account_number"])
+	path := fmt.Sprintf("/beneficiaries/%v", created["id"])
+
+	var stored models.Beneficiary
+	require.NoError(t, db.First(&stored).Error)
+	assert.Equal(t, models.EncryptedString("0123456789"), stored.BankAccountNumber)
+
+	t.Run("Invalid create rejected", func(t *testing.T) {
+		for name, req := range map[string]CreateBeneficiaryRequest{
+			"bad address":      {Name: "A", StellarAddress: "GSHORT", Country: "KE"},
+			"bad country":      {Name: "A", StellarAddress: beneficiaryAddress, Country: "Kenya"},
+			"bad relationship": {Name: "A", StellarAddress: beneficiaryAddress, Country: "KE", Relationship: "cousin"},
+			"blank name":       {Name: "  ", StellarAddress: beneficiaryAddress, Country: "KE"},
+		} {
+			w := serveJSON(owner, http.MethodPost, "/beneficiaries", req)
+			assert.Equal(t, http.StatusBadRequest, w.Code, name)
+		}
+	})
+
+	t.Run("Only the owner sees them", func(t *testing.T) {
+		w := serveJSON(owner, http.MethodGet, "/beneficiaries", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), beneficiaryAddress)
+
+		w = serveJSON(stranger, http.MethodGet, "/beneficiaries", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), beneficiaryAddress)
+
+		assert.Equal(t, http.StatusNotFound, serveJSON(stranger, http.MethodGet, path, nil).Code)
+		assert.Equal(t, http.StatusNotFound, serveJSON(stranger, http.MethodPut, path, map[string]string{"name": "Mine"}).Code)
+		assert.Equal(t, http.StatusNotFound, serveJSON(stranger, http.MethodDelete, path, nil).Code)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		w := serveJSON(owner, http.MethodPut, path, map[string]string{"name": "Mum", "bank_account_number": ""})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var got models.Beneficiary
+		require.NoError(t, db.First(&got, stored.ID).Error)
+		assert.Equal(t, "Mum", got.Name)
+		assert.Empty(t, got.BankAccountNumber)
+		assert.Equal(t, "EQBLKENA", got.BankCode, "omitted fields are kept")
+
+		assert.Equal(t, http.StatusBadRequest, serveJSON(owner, http.MethodPut, path, map[string]string{}).Code)
+		assert.Equal(t, http.StatusBadRequest, serveJSON(owner, http.MethodPut, path, map[string]string{"stellar_address": "nope"}).Code)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serveJSON(owner, http.MethodDelete, path, nil).Code)
+		assert.Equal(t, http.StatusNotFound, serveJSON(owner, http.MethodGet, path, nil).Code)
+	})
+}
+
+func TestSendRemittanceToBeneficiary(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	db.AutoMigrate(&models.Beneficiary{})
+	cfg := &config.Config{}
+	handler := &RemittanceHandler{db: db, config: cfg, fees: services.NewFeeService(cfg)}
+
+	sender := models.User{Email: "sender@example.com", Name: "Sender", StellarAddress: usdcIssuer}
+	db.Create(&sender)
+	beneficiary := models.Beneficiary{UserID: sender.ID, Name: "Mama", StellarAddress: beneficiaryAddress, Country: "KE"}
+	db.Create(&beneficiary)
+	other := models.Beneficiary{UserID: sender.ID + 100, Name: "Not mine", StellarAddress: otherIssuer, Country: "KE"}
+	db.Create(&other)
+
+	router := newTestRouter(sender.ID, "")
+	router.POST("/remittances", handler.SendRemittance)
+
+	send := func(body map[string]interface{}) *httptest.ResponseRecorder {
+		return serveJSON(router, http.MethodPost, "/remittances", body)
+	}
+
+	w := send(map[string]interface{}{"sender_id": sender.ID, "beneficiary_id": beneficiary.ID, "amount": 25, "currency": "USD"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var payment models.Payment
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &payment))
+	assert.Equal(t, beneficiaryAddress, payment.RecipientAccount)
+	assert.Equal(t, sender.ID, payment.SenderID)
+
+	var recipient models.User
+	require.NoError(t, db.First(&recipient, payment.RecipientID).Error)
+	assert.Equal(t, beneficiaryAddress, recipient.StellarAddress)
+	assert.True(t, recipient.Unregistered, "an unknown address gets a placeholder recipient")
+
+	t.Run("Another user's beneficiary", func(t *testing.T) {
+		w := send(map[string]interface{}{"sender_id": sender.ID, "beneficiary_id": other.ID, "amount": 25, "currency": "USD"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Own beneficiary under another sender_id", func(t *testing.T) {
+		w := send(map[string]interface{}{"sender_id": sender.ID + 1000, "beneficiary_id": beneficiary.ID, "amount": 25, "currency": "USD"})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Both recipient_id and beneficiary_id", func(t *testing.T) {
+		w := send(map[string]interface{}{"sender_id": sender.ID, "recipient_id": 2, "beneficiary_id": beneficiary.ID, "amount": 25, "currency": "USD"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Neither", func(t *testing.T) {
+		w := send(map[string]interface{}{"sender_id": sender.ID, "amount": 25, "currency": "USD"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/utils"
+	"gorm.io/gorm"
+)
+
+// ClaimRemittance builds the transaction that claims a remittance sent as a
+// claimable balance and returns it unsigned. The recipient claims it into
+// the remittance's recipient account; the sender reclaims it into the
+// sender account. Which of them the network lets claim depends on the time;
+// see utils.ClaimPredicates.
+func (h *RemittanceHandler) ClaimRemittance(c *gin.Context) {
+	var payment models.Payment
+	if err := h.db.First(&payment, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("Payment not found").WithKey("error.payment_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch payment", err))
+		}
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	id, _ := userID.(uint)
+	var claimant string
+	switch id {
+	case payment.RecipientID:
+		claimant = payment.RecipientAccount
+	case payment.SenderID:
+		claimant = payment.SenderAccount
+	default:
+		c.Error(errors.NewForbiddenError("Only the recipient or the sender can claim this remittance"))
+		return
+	}
+	if payment.ClaimableBalanceID == "" {
+		c.Error(errors.NewConflictError("Remittance was not sent as a claimable balance"))
+		return
+	}
+	switch payment.Status {
+	case "processing", "completed":
+	default:
+		c.Error(errors.NewConflictError(fmt.Sprintf("Claimable balance cannot be claimed while the remittance is %s", payment.Status)))
+		return
+	}
+
+	ctx := utils.WithRequestContext(c.Request.Context(), c.GetString("requestID"), userID)
+	envelope, err := h.stellarClient.BuildClaimClaimableBalanceTx(ctx, payment.ClaimableBalanceID, claimant)
+	if err != nil {
+		if utils.IsAccountNotFound(err) {
+			c.Error(errors.NewNotFoundError("Claimant account not found on the network"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to build claim transaction", err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"remittance_id":        payment.ID,
+		"claimable_balance_id": payment.ClaimableBalanceID,
+		"claimant":             claimant,
+		"tx_envelope":          envelope,
+	})
+}
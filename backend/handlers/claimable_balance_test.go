@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/services"
+	"github.com/yourusername/gpay-remit/utils"
+)
+
+func TestCreateRemittanceAsClaimableBalance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	releaseAfter := time.Now().Add(24 * time.Hour).Truncate(time.Second).UTC()
+	reclaimAfter := releaseAfter.Add(720 * time.Hour)
+
+	var claimAfter time.Time
+	escrowBuilt := false
+	handler := &RemittanceHandler{
+		db:     db,
+		config: &config.Config{ClaimableBalances: true},
+		stellarClient: &MockStellarClient{
+			ValidateAccountFunc: func(accountID string) error { return nil },
+			BuildEscrowTxFunc: func(sender, recipient, assetCode, issuer, amount string, memo txnbuild.Memo) (string, error) {
+				escrowBuilt = true
+				return "escrow_xdr", nil
+			},
+			BuildCreateClaimableBalanceTxFunc: func(sender, recipient, assetCode, issuer, amount string, after time.Time, memo txnbuild.Memo) (*utils.ClaimableBalanceTx, error) {
+				claimAfter = after
+				return &utils.ClaimableBalanceTx{Envelope: "claimable_xdr", BalanceID: "00000000abcd", ClaimAfter: after, ReclaimAfter: reclaimAfter}, nil
+			},
+		},
+		fees: services.NewFeeService(&config.Config{}),
+	}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.POST("/remittances/create", handler.CreateRemittance)
+
+	post := func(conditions map[string]interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(CreateRemittanceRequest{
+			SenderAccount:    "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+			RecipientAccount: "GDRXV6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+			Amount:           40,
+			AssetCode:        "XLM",
+			Conditions:       conditions,
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/remittances/create", bytes.NewBuffer(body))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Returns the balance ID", func(t *testing.T) {
+		w := post(map[string]interface{}{services.ConditionKeyReleaseAfter: releaseAfter.Format(time.RFC3339)})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.False(t, escrowBuilt)
+		assert.True(t, releaseAfter.Equal(claimAfter))
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.Equal(t, "claimable_xdr", resp["tx_envelope"])
+		assert.Equal(t, "00000000abcd", resp["claimable_balance_id"])
+
+		var payment models.Payment
+		db.First(&payment)
+		assert.Equal(t, "00000000abcd", payment.ClaimableBalanceID)
+		assert.Equal(t, "claimable_xdr", payment.TxEnvelope)
+		if assert.NotNil(t, payment.ReclaimAfter) {
+			assert.True(t, reclaimAfter.Equal(*payment.ReclaimAfter))
+		}
+	})
+
+	t.Run("Rejects a hashlock", func(t *testing.T) {
+		w := post(map[string]interface{}{services.ConditionKeyHashlock: "b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "conditions.hashlock")
+	})
+}
+
+func TestClaimRemittance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	sender := models.User{Email: "s@example.com", Name: "Sender", StellarAddress: "GSENDER", PasswordHash: "x"}
+	recipient := models.User{Email: "r@example.com", Name: "Recipient", StellarAddress: "GRECIPIENT", PasswordHash: "x"}
+	stranger := models.User{Email: "x@example.com", Name: "Stranger", StellarAddress: "GSTRANGER", PasswordHash: "x"}
+	db.Create(&sender)
+	db.Create(&recipient)
+	db.Create(&stranger)
+
+	claimable := models.Payment{SenderID: sender.ID, SenderAccount: "GSENDER", RecipientID: recipient.ID, RecipientAccount: "GRECIPIENT", Amount: 10, Currency: "XLM", Status: "processing", ClaimableBalanceID: "00000000abcd"}
+	escrowed := models.Payment{SenderID: sender.ID, RecipientID: recipient.ID, Amount: 10, Currency: "XLM", Status: "processing", EscrowID: "7"}
+	failed := models.Payment{SenderID: sender.ID, RecipientID: recipient.ID, Amount: 10, Currency: "XLM", Status: "failed", ClaimableBalanceID: "00000000ef01"}
+	db.Create(&claimable)
+	db.Create(&escrowed)
+	db.Create(&failed)
+
+	handler := &RemittanceHandler{
+		db:     db,
+		config: &config.Config{},
+		stellarClient: &MockStellarClient{
+			BuildClaimClaimableBalanceTxFunc: func(balanceID, claimant string) (string, error) {
+				if claimant == "" {
+					return "", fmt.Errorf("no claimant")
+				}
+				return "claim_xdr:" + balanceID + ":" + claimant, nil
+			},
+		},
+	}
+
+	claim := func(userID, paymentID uint) (int, map[string]interface{}) {
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", userID)
+			c.Next()
+		})
+		router.POST("/remittances/:id/claim", handler.ClaimRemittance)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/remittances/%d/claim", paymentID), nil)
+		router.ServeHTTP(w, req)
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+
+	t.Run("Recipient claims into the recipient account", func(t *testing.T) {
+		code, resp := claim(recipient.ID, claimable.ID)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "GRECIPIENT", resp["claimant"])
+		assert.Equal(t, "claim_xdr:00000000abcd:GRECIPIENT", resp["tx_envelope"])
+	})
+
+	t.Run("Sender reclaims into the sender account", func(t *testing.T) {
+		code, resp := claim(sender.ID, claimable.ID)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "GSENDER", resp["claimant"])
+	})
+
+	t.Run("Refused", func(t *testing.T) {
+		code, _ := claim(stranger.ID, claimable.ID)
+		assert.Equal(t, http.StatusForbidden, code)
+		code, _ = claim(recipient.ID, escrowed.ID)
+		assert.Equal(t, http.StatusConflict, code)
+		code, _ = claim(recipient.ID, failed.ID)
+		assert.Equal(t, http.StatusConflict, code)
+		code, _ = claim(recipient.ID, 999)
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/errors"
+)
+
+// currencySupported reports whether code, issued by issuer, is in the
+// configured supported set. Codes match case-insensitively and issuers
+// exactly; an entry without an issuer accepts any, and an empty issuer (XLM,
+// or an endpoint that takes only a currency code) matches on the code alone.
+// An empty set supports everything.
+func currencySupported(cfg *config.Config, code, issuer string) bool {
+	if len(cfg.SupportedCurrencies) == 0 {
+		return true
+	}
+	for _, supported := range cfg.SupportedCurrencies {
+		if strings.EqualFold(supported.Code, code) && (supported.Issuer == "" || issuer == "" || supported.Issuer == issuer) {
+			return true
+		}
+	}
+	return false
+}
+
+// requireSupportedCurrency rejects a currency or asset outside the configured
+// supported set, reporting on the context with the supported set in the
+// details. field names the request field being checked.
+func requireSupportedCurrency(c *gin.Context, cfg *config.Config, field, code, issuer string) bool {
+	return report(c, unsupportedCurrencyError(cfg, field, code, issuer))
+}
+
+// unsupportedCurrencyError is the error requireSupportedCurrency reports, or
+// nil when the currency is supported.
+func unsupportedCurrencyError(cfg *config.Config, field, code, issuer string) *errors.AppError {
+	if currencySupported(cfg, code, issuer) {
+		return nil
+	}
+	details := gin.H{
+		"field":     field,
+		"supported": cfg.SupportedCurrencies,
+	}
+	if issuer != "" {
+		return errors.NewUnsupportedCurrencyError(fmt.Sprintf("Asset %s issued by %s is not supported", code, issuer), details).
+			WithKey("error.asset_not_supported", code, issuer)
+	}
+	return errors.NewUnsupportedCurrencyError(fmt.Sprintf("Currency %s is not supported", code), details).
+		WithKey("error.currency_not_supported", code)
+}
+
+// ListCurrencies lists the supported currencies and assets. restricted is
+// false when none are configured, meaning any valid asset is accepted.
+func (h *RemittanceHandler) ListCurrencies(c *gin.Context) {
+	currencies := h.config.SupportedCurrencies
+	if currencies == nil {
+		currencies = []config.SupportedCurrency{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"currencies": currencies,
+		"restricted": len(currencies) > 0,
+	})
+}
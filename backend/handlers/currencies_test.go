@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/services"
+)
+
+const (
+	usdcIssuer  = "GA5ZSEJYB37JRC5AVCIA5MOP4RHTM335X2KGX3IHOJAPP5RE34K4KZVN"
+	otherIssuer = "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"
+)
+
+func TestCurrencySupported(t *testing.T) {
+	cfg := &config.Config{SupportedCurrencies: []config.SupportedCurrency{
+		{Code: "XLM"}, {Code: "USD"}, {Code: "USDC", Issuer: usdcIssuer},
+	}}
+
+	assert.True(t, currencySupported(cfg, "usd", ""), "codes match case-insensitively")
+	assert.True(t, currencySupported(cfg, "XLM", ""))
+	assert.True(t, currencySupported(cfg, "USDC", usdcIssuer))
+	assert.True(t, currencySupported(cfg, "usdc", ""), "a code-only check matches any issuer")
+	assert.False(t, currencySupported(cfg, "USDC", otherIssuer), "issuers match exactly")
+	assert.False(t, currencySupported(cfg, "USDD", ""))
+
+	assert.True(t, currencySupported(&config.Config{}, "USDD", ""), "an empty set supports everything")
+}
+
+func TestSupportedCurrencies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	cfg := &config.Config{SupportedCurrencies: []config.SupportedCurrency{
+		{Code: "USD"}, {Code: "NGN"}, {Code: "USDC", Issuer: usdcIssuer},
+	}}
+	handler := &RemittanceHandler{db: db, config: cfg, fees: services.NewFeeService(cfg)}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.POST("/remittances", handler.SendRemittance)
+	router.GET("/currencies", handler.ListCurrencies)
+
+	send := func(req SendRemittanceRequest) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		json.NewEncoder(&buf).Encode(req)
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodPost, "/remittances", &buf)
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("Supported currency", func(t *testing.T) {
+		w := send(SendRemittanceRequest{SenderID: 1, RecipientID: 2, Amount: 10, Currency: "usd"})
+		assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	})
+
+	t.Run("Unsupported currency lists the supported set", func(t *testing.T) {
+		w := send(SendRemittanceRequest{SenderID: 1, RecipientID: 2, Amount: 10, Currency: "USDD"})
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp struct {
+			Error struct {
+				Code    string `json:"code"`
+				Details struct {
+					Field     string                     `json:"field"`
+					Supported []config.SupportedCurrency `json:"supported"`
+				} `json:"details"`
+			} `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "UnsupportedCurrency", resp.Error.Code)
+		assert.Equal(t, "currency", resp.Error.Details.Field)
+		assert.Equal(t, cfg.SupportedCurrencies, resp.Error.Details.Supported)
+	})
+
+	t.Run("Unsupported target currency", func(t *testing.T) {
+		w := send(SendRemittanceRequest{SenderID: 1, RecipientID: 2, Amount: 10, Currency: "USD", TargetCurrency: "KES"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "target_currency")
+	})
+
+	t.Run("Listing", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, "/currencies", nil)
+		router.ServeHTTP(w, r)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp struct {
+			Currencies []config.SupportedCurrency `json:"currencies"`
+			Restricted bool                       `json:"restricted"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.True(t, resp.Restricted)
+		assert.Equal(t, cfg.SupportedCurrencies, resp.Currencies)
+	})
+}
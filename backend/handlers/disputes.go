@@ -0,0 +1,381 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/services"
+	"gorm.io/gorm"
+)
+
+type OpenDisputeRequest struct {
+	Reason string `json:"reason" binding:"required,max=2000"`
+}
+
+// ResolveDisputeRequest resolves an open dispute with release or refund; see
+// models.DisputeResolutionRelease and models.DisputeResolutionRefund.
+type ResolveDisputeRequest struct {
+	Resolution string `json:"resolution" binding:"required,oneof=release refund"`
+	Note       string `json:"note" binding:"max=2000"`
+}
+
+// BulkResolveDisputesRequest applies one resolution to several disputes.
+type BulkResolveDisputesRequest struct {
+	DisputeIDs []uint `json:"dispute_ids" binding:"required,min=1,max=100"`
+	Resolution string `json:"resolution" binding:"required,oneof=release refund"`
+	Note       string `json:"note" binding:"max=2000"`
+	// SkipResolved reports disputes that are already resolved as skipped
+	// instead of rejecting the whole batch.
+	SkipResolved bool `json:"skip_resolved"`
+}
+
+// Outcomes of a dispute in a bulk resolution.
+const (
+	DisputeOutcomeResolved = "resolved"
+	DisputeOutcomeSkipped  = "skipped"
+	DisputeOutcomeFailed   = "failed"
+)
+
+// DisputeResult is the outcome of one dispute in a bulk resolution.
+type DisputeResult struct {
+	DisputeID uint            `json:"dispute_id"`
+	Outcome   string          `json:"outcome"`
+	Dispute   *models.Dispute `json:"dispute,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+type ListDisputesResponse struct {
+	Data       []models.Dispute `json:"data"`
+	Page       int              `json:"page"`
+	PageSize   int              `json:"page_size"`
+	TotalCount int64            `json:"total_count"`
+}
+
+// disputableStatuses are the remittance statuses a dispute can be opened
+// from: its transaction has been submitted, so cancelling is no longer an
+// option.
+var disputableStatuses = map[string]bool{"processing": true, "completed": true}
+
+// OpenDispute lets the sender or recipient of a remittance, or an admin,
+// dispute it. The remittance is held as disputed until an admin resolves the
+// dispute.
+func (h *RemittanceHandler) OpenDispute(c *gin.Context) {
+	var req OpenDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		c.Error(errors.NewValidationError("Invalid request body", "reason must not be blank"))
+		return
+	}
+
+	var payment models.Payment
+	if err := h.db.First(&payment, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("Payment not found").WithKey("error.payment_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch payment", err))
+		}
+		return
+	}
+
+	if !isSenderOrAdmin(c, &payment) && !isRecipientOrAdmin(c, &payment) {
+		c.Error(errors.NewForbiddenError("Only the sender, the recipient, or an admin can dispute this remittance"))
+		return
+	}
+	if payment.Status == services.PaymentStatusDisputed {
+		c.Error(errors.NewConflictError("Remittance is already disputed"))
+		return
+	}
+	if !disputableStatuses[payment.Status] {
+		c.Error(errors.NewConflictError(fmt.Sprintf("Remittance cannot be disputed while it is %s", payment.Status)))
+		return
+	}
+
+	dispute := models.Dispute{
+		PaymentID:      payment.ID,
+		OpenedBy:       c.GetUint("userID"),
+		Reason:         req.Reason,
+		Status:         models.DisputeStatusOpen,
+		PreviousStatus: payment.Status,
+	}
+	middleware.SetAuditOld(c, payment)
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := payment.UpdateVersioned(tx, map[string]interface{}{"status": services.PaymentStatusDisputed}); err != nil {
+			return err
+		}
+		return tx.Create(&dispute).Error
+	})
+	if err != nil {
+		c.Error(paymentUpdateError(err, "Failed to open dispute"))
+		return
+	}
+	payment.Status = services.PaymentStatusDisputed
+	h.events.PublishPayment(payment)
+
+	logger.Log.WithFields(logrus.Fields{
+		"payment_id": payment.ID,
+		"dispute_id": dispute.ID,
+		"user_id":    dispute.OpenedBy,
+		"request_id": c.GetString("requestID"),
+	}).Info("Dispute opened")
+
+	middleware.SetAuditNew(c, payment)
+	c.JSON(http.StatusCreated, dispute)
+}
+
+// ListDisputes lists disputes for admins, newest first, optionally filtered
+// by status.
+func (h *RemittanceHandler) ListDisputes(c *gin.Context) {
+	page := 1
+	pageSize := 20
+	fmt.Sscanf(c.Query("page"), "%d", &page)
+	fmt.Sscanf(c.Query("page_size"), "%d", &pageSize)
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	query := h.db.Model(&models.Dispute{})
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var disputes []models.Dispute
+	if err := query.Order("id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&disputes).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to fetch disputes", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, ListDisputesResponse{
+		Data:       disputes,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalCount: total,
+	})
+}
+
+// ResolveDispute lets an admin resolve one open dispute.
+func (h *RemittanceHandler) ResolveDispute(c *gin.Context) {
+	var req ResolveDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+
+	var dispute models.Dispute
+	if err := h.db.First(&dispute, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("Dispute not found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch dispute", err))
+		}
+		return
+	}
+
+	note := strings.TrimSpace(req.Note)
+	middleware.SetAuditOld(c, dispute)
+	if appErr := h.resolveDispute(c, &dispute, req.Resolution, note); appErr != nil {
+		c.Error(appErr)
+		return
+	}
+	middleware.SetAuditNew(c, dispute)
+	middleware.SetAuditReason(c, note)
+	c.JSON(http.StatusOK, dispute)
+}
+
+// BulkResolveDisputes lets an admin apply one resolution to a list of
+// disputes. Each is resolved on its own, as ResolveDispute would, and the
+// response reports every dispute's outcome. The batch is rejected if any
+// dispute is already resolved, unless skip_resolved is set.
+func (h *RemittanceHandler) BulkResolveDisputes(c *gin.Context) {
+	var req BulkResolveDisputesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+	seen := make(map[uint]bool, len(req.DisputeIDs))
+	for _, id := range req.DisputeIDs {
+		if seen[id] {
+			c.Error(errors.NewValidationError("Invalid request body", fmt.Sprintf("dispute %d is listed more than once", id)))
+			return
+		}
+		seen[id] = true
+	}
+
+	var disputes []models.Dispute
+	if err := h.db.Where("id IN ?", req.DisputeIDs).Find(&disputes).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to fetch disputes", err))
+		return
+	}
+	byID := make(map[uint]*models.Dispute, len(disputes))
+	for i := range disputes {
+		byID[disputes[i].ID] = &disputes[i]
+	}
+	resolved := []uint{}
+	for _, id := range req.DisputeIDs {
+		dispute, ok := byID[id]
+		if !ok {
+			c.Error(errors.NewNotFoundError(fmt.Sprintf("Dispute %d not found", id)))
+			return
+		}
+		if dispute.Status != models.DisputeStatusOpen {
+			resolved = append(resolved, id)
+		}
+	}
+	if len(resolved) > 0 && !req.SkipResolved {
+		appErr := errors.NewConflictError("Some disputes are already resolved; set skip_resolved to resolve the rest")
+		appErr.Details = gin.H{"resolved_dispute_ids": resolved}
+		c.Error(appErr)
+		return
+	}
+
+	note := strings.TrimSpace(req.Note)
+	results := make([]DisputeResult, 0, len(req.DisputeIDs))
+	counts := map[string]int{}
+	for _, id := range req.DisputeIDs {
+		dispute := byID[id]
+		result := DisputeResult{DisputeID: id, Outcome: DisputeOutcomeResolved}
+		if dispute.Status != models.DisputeStatusOpen {
+			result.Outcome = DisputeOutcomeSkipped
+		} else {
+			before := *dispute
+			if appErr := h.resolveDispute(c, dispute, req.Resolution, note); appErr != nil {
+				result.Outcome = DisputeOutcomeFailed
+				result.Error = appErr.Message
+			} else {
+				result.Dispute = dispute
+				h.auditDisputeResolution(c, before, *dispute)
+			}
+		}
+		counts[result.Outcome]++
+		results = append(results, result)
+	}
+
+	middleware.SetAuditReason(c, note)
+	c.JSON(http.StatusOK, gin.H{
+		"results":  results,
+		"resolved": counts[DisputeOutcomeResolved],
+		"skipped":  counts[DisputeOutcomeSkipped],
+		"failed":   counts[DisputeOutcomeFailed],
+	})
+}
+
+// resolveDispute closes an open dispute with resolution and, for a release,
+// returns its remittance to the status it had before the dispute. The
+// dispute and the remittance are written in one transaction; dispute is
+// updated in place on success.
+func (h *RemittanceHandler) resolveDispute(c *gin.Context, dispute *models.Dispute, resolution, note string) *errors.AppError {
+	if dispute.Status != models.DisputeStatusOpen {
+		return errors.NewConflictError(fmt.Sprintf("Dispute %d is already resolved", dispute.ID))
+	}
+	var payment models.Payment
+	if err := h.db.First(&payment, dispute.PaymentID).Error; err != nil {
+		return errors.NewInternalError("Failed to fetch payment", err)
+	}
+	if payment.Status != services.PaymentStatusDisputed {
+		return errors.NewConflictError(fmt.Sprintf("Remittance %d is %s, not disputed", payment.ID, payment.Status))
+	}
+
+	adminID := c.GetUint("userID")
+	now := time.Now()
+	after := *dispute
+	after.Status = models.DisputeStatusResolved
+	after.Resolution = resolution
+	after.ResolutionNote = note
+	after.ResolvedBy = &adminID
+	after.ResolvedAt = &now
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		// Guard on the open status so a concurrent resolution cannot apply twice.
+		result := tx.Model(&models.Dispute{}).
+			Where("id = ? AND status = ?", dispute.ID, models.DisputeStatusOpen).
+			Updates(map[string]interface{}{
+				"status":          after.Status,
+				"resolution":      after.Resolution,
+				"resolution_note": after.ResolutionNote,
+				"resolved_by":     adminID,
+				"resolved_at":     now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.NewConflictError(fmt.Sprintf("Dispute %d was resolved concurrently", dispute.ID))
+		}
+
+		if resolution == models.DisputeResolutionRelease {
+			if err := payment.UpdateVersioned(tx, map[string]interface{}{"status": dispute.PreviousStatus}); err != nil {
+				return err
+			}
+			payment.Status = dispute.PreviousStatus
+		}
+
+		return nil
+	})
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return appErr
+		}
+		return paymentUpdateError(err, "Failed to resolve dispute")
+	}
+	*dispute = after
+	h.events.PublishPayment(payment)
+
+	logger.Log.WithFields(logrus.Fields{
+		"dispute_id": dispute.ID,
+		"payment_id": payment.ID,
+		"admin_id":   adminID,
+		"resolution": resolution,
+		"request_id": c.GetString("requestID"),
+	}).Info("Dispute resolved")
+	return nil
+}
+
+// auditDisputeResolution records one dispute of a bulk resolution in the
+// audit log. AuditTrail records the bulk request as a whole, so each dispute
+// gets its own entry here. Like AuditTrail, a failed write is logged rather
+// than surfaced, since the resolution has already been committed.
+func (h *RemittanceHandler) auditDisputeResolution(c *gin.Context, before, after models.Dispute) {
+	userID := c.GetUint("userID")
+	oldValue, _ := json.Marshal(before)
+	newValue, _ := json.Marshal(after)
+	entry := models.AuditLog{
+		UserID:     &userID,
+		Action:     "dispute.resolved",
+		Resource:   c.FullPath(),
+		EntityType: "dispute",
+		EntityID:   fmt.Sprint(after.ID),
+		OldStatus:  before.Status,
+		NewStatus:  after.Status,
+		Reason:     after.ResolutionNote,
+		OldValue:   string(oldValue),
+		NewValue:   string(newValue),
+		IPAddress:  c.ClientIP(),
+	}
+	if err := h.db.Create(&entry).Error; err != nil {
+		logger.Log.WithFields(logrus.Fields{
+			"dispute_id": after.ID,
+			"request_id": c.GetString("requestID"),
+		}).WithError(err).Error("Failed to write audit log")
+	}
+}
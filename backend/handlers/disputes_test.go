@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/services"
+	"gorm.io/gorm"
+)
+
+func setupDisputeRouter(db *gorm.DB, userID uint, role string) *gin.Engine {
+	handler := &RemittanceHandler{db: db, config: &config.Config{}}
+
+	router := newTestRouter(userID, role)
+	router.Use(middleware.AuditTrail(db))
+	router.POST("/remittances/:id/disputes", handler.OpenDispute)
+	router.GET("/disputes", middleware.RequireRole("admin"), handler.ListDisputes)
+	router.POST("/disputes/bulk-resolve", middleware.RequireRole("admin"), handler.BulkResolveDisputes)
+	router.POST("/disputes/:id/resolve", middleware.RequireRole("admin"), handler.ResolveDispute)
+	return router
+}
+
+func TestOpenDispute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	require.NoError(t, db.AutoMigrate(&models.Dispute{}, &models.AuditLog{}))
+
+	payment := models.Payment{SenderID: 1, RecipientID: 2, Amount: 10, Currency: "USD", Status: "processing"}
+	pending := models.Payment{SenderID: 1, RecipientID: 2, Amount: 10, Currency: "USD", Status: "pending"}
+	require.NoError(t, db.Create(&payment).Error)
+	require.NoError(t, db.Create(&pending).Error)
+	path := fmt.Sprintf("/remittances/%d/disputes", payment.ID)
+
+	t.Run("Only a party to the remittance can dispute it", func(t *testing.T) {
+		w := serveJSON(setupDisputeRouter(db, 3, "user"), http.MethodPost, path, OpenDisputeRequest{Reason: "never arrived"})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Unsubmitted remittance cannot be disputed", func(t *testing.T) {
+		w := serveJSON(setupDisputeRouter(db, 1, "user"), http.MethodPost, fmt.Sprintf("/remittances/%d/disputes", pending.ID), OpenDisputeRequest{Reason: "never arrived"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Recipient opens a dispute", func(t *testing.T) {
+		w := serveJSON(setupDisputeRouter(db, 2, "user"), http.MethodPost, path, OpenDisputeRequest{Reason: " never arrived "})
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var dispute models.Dispute
+		json.Unmarshal(w.Body.Bytes(), &dispute)
+		assert.Equal(t, models.DisputeStatusOpen, dispute.Status)
+		assert.Equal(t, "processing", dispute.PreviousStatus)
+		assert.Equal(t, "never arrived", dispute.Reason)
+		assert.Equal(t, uint(2), dispute.OpenedBy)
+
+		var stored models.Payment
+		db.First(&stored, payment.ID)
+		assert.Equal(t, services.PaymentStatusDisputed, stored.Status)
+	})
+
+	t.Run("Disputed remittance cannot be disputed again", func(t *testing.T) {
+		w := serveJSON(setupDisputeRouter(db, 1, "user"), http.MethodPost, path, OpenDisputeRequest{Reason: "again"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestResolveDispute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	require.NoError(t, db.AutoMigrate(&models.Dispute{}, &models.AuditLog{}))
+
+	payment := models.Payment{SenderID: 1, RecipientID: 2, Amount: 10, Currency: "USD", Status: services.PaymentStatusDisputed}
+	require.NoError(t, db.Create(&payment).Error)
+	dispute := models.Dispute{PaymentID: payment.ID, OpenedBy: 1, Reason: "wrong amount", Status: models.DisputeStatusOpen, PreviousStatus: "completed"}
+	require.NoError(t, db.Create(&dispute).Error)
+	path := fmt.Sprintf("/disputes/%d/resolve", dispute.ID)
+
+	assert.Equal(t, http.StatusForbidden, serveJSON(setupDisputeRouter(db, 1, "user"), http.MethodPost, path, ResolveDisputeRequest{Resolution: "refund"}).Code)
+	assert.Equal(t, http.StatusBadRequest, serveJSON(setupDisputeRouter(db, 9, "admin"), http.MethodPost, path, ResolveDisputeRequest{Resolution: "ignore"}).Code)
+
+	w := serveJSON(setupDisputeRouter(db, 9, "admin"), http.MethodPost, path, ResolveDisputeRequest{Resolution: "refund", Note: "sender overcharged"})
+	require.Equal(t, http.StatusOK, w.Code)
+	var resolved models.Dispute
+	json.Unmarshal(w.Body.Bytes(), &resolved)
+	assert.Equal(t, models.DisputeStatusResolved, resolved.Status)
+	assert.Equal(t, models.DisputeResolutionRefund, resolved.Resolution)
+	if assert.NotNil(t, resolved.ResolvedBy) {
+		assert.Equal(t, uint(9), *resolved.ResolvedBy)
+	}
+
+	// A refund leaves the remittance disputed for the admin to refund its escrow.
+	var stored models.Payment
+	db.First(&stored, payment.ID)
+	assert.Equal(t, services.PaymentStatusDisputed, stored.Status)
+
+	var entry models.AuditLog
+	require.NoError(t, db.Where("entity_type = ? AND entity_id = ?", "dispute", fmt.Sprint(dispute.ID)).Last(&entry).Error)
+	assert.Equal(t, models.DisputeStatusOpen, entry.OldStatus)
+	assert.Equal(t, models.DisputeStatusResolved, entry.NewStatus)
+	assert.Equal(t, "sender overcharged", entry.Reason)
+
+	assert.Equal(t, http.StatusConflict, serveJSON(setupDisputeRouter(db, 9, "admin"), http.MethodPost, path, ResolveDisputeRequest{Resolution: "release"}).Code)
+}
+
+func TestBulkResolveDisputes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	require.NoError(t, db.AutoMigrate(&models.Dispute{}, &models.AuditLog{}))
+
+	// Three open disputes, one of whose remittances has since left the
+	// disputed status, and one dispute resolved earlier.
+	var payments []models.Payment
+	var disputes []models.Dispute
+	for i, status := range []string{services.PaymentStatusDisputed, services.PaymentStatusDisputed, "completed", services.PaymentStatusDisputed} {
+		payment := models.Payment{SenderID: 1, RecipientID: 2, Amount: 10, Currency: "USD", Status: status}
+		require.NoError(t, db.Create(&payment).Error)
+		dispute := models.Dispute{PaymentID: payment.ID, OpenedBy: 1, Reason: "never arrived", Status: models.DisputeStatusOpen, PreviousStatus: "processing"}
+		if i == 3 {
+			dispute.Status = models.DisputeStatusResolved
+			dispute.Resolution = models.DisputeResolutionRefund
+		}
+		require.NoError(t, db.Create(&dispute).Error)
+		payments = append(payments, payment)
+		disputes = append(disputes, dispute)
+	}
+	ids := []uint{disputes[0].ID, disputes[1].ID, disputes[2].ID, disputes[3].ID}
+	admin := setupDisputeRouter(db, 9, "admin")
+
+	assertUnchanged := func(t *testing.T) {
+		var open int64
+		db.Model(&models.Dispute{}).Where("status = ?", models.DisputeStatusOpen).Count(&open)
+		assert.Equal(t, int64(3), open)
+	}
+
+	t.Run("Admin only", func(t *testing.T) {
+		w := serveJSON(setupDisputeRouter(db, 1, "user"), http.MethodPost, "/disputes/bulk-resolve", BulkResolveDisputesRequest{DisputeIDs: ids, Resolution: "release"})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Unknown or repeated disputes reject the batch", func(t *testing.T) {
+		w := serveJSON(admin, http.MethodPost, "/disputes/bulk-resolve", BulkResolveDisputesRequest{DisputeIDs: []uint{ids[0], 424242}, Resolution: "release"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = serveJSON(admin, http.MethodPost, "/disputes/bulk-resolve", BulkResolveDisputesRequest{DisputeIDs: []uint{ids[0], ids[0]}, Resolution: "release"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assertUnchanged(t)
+	})
+
+	t.Run("Already resolved dispute rejects the batch", func(t *testing.T) {
+		w := serveJSON(admin, http.MethodPost, "/disputes/bulk-resolve", BulkResolveDisputesRequest{DisputeIDs: ids, Resolution: "release"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		var resp struct {
+			Error struct {
+				Details struct {
+					ResolvedDisputeIDs []uint `json:"resolved_dispute_ids"`
+				} `json:"details"`
+			} `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, []uint{ids[3]}, resp.Error.Details.ResolvedDisputeIDs)
+		assertUnchanged(t)
+	})
+
+	t.Run("skip_resolved resolves the rest and reports each outcome", func(t *testing.T) {
+		w := serveJSON(admin, http.MethodPost, "/disputes/bulk-resolve", BulkResolveDisputesRequest{
+			DisputeIDs:   ids,
+			Resolution:   "release",
+			Note:         "carrier confirmed delivery",
+			SkipResolved: true,
+		})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp struct {
+			Results  []DisputeResult `json:"results"`
+			Resolved int             `json:"resolved"`
+			Skipped  int             `json:"skipped"`
+			Failed   int             `json:"failed"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 2, resp.Resolved)
+		assert.Equal(t, 1, resp.Skipped)
+		assert.Equal(t, 1, resp.Failed)
+		if assert.Len(t, resp.Results, 4) {
+			assert.Equal(t, DisputeOutcomeResolved, resp.Results[0].Outcome)
+			assert.Equal(t, DisputeOutcomeResolved, resp.Results[1].Outcome)
+			assert.Equal(t, DisputeOutcomeFailed, resp.Results[2].Outcome)
+			assert.Contains(t, resp.Results[2].Error, "not disputed")
+			assert.Equal(t, DisputeOutcomeSkipped, resp.Results[3].Outcome)
+			if assert.NotNil(t, resp.Results[0].Dispute) {
+				assert.Equal(t, "carrier confirmed delivery", resp.Results[0].Dispute.ResolutionNote)
+			}
+		}
+
+		// Released remittances return to the status they had before the dispute.
+		for i, want := range []string{"processing", "processing", "completed", services.PaymentStatusDisputed} {
+			var stored models.Payment
+			db.First(&stored, payments[i].ID)
+			assert.Equal(t, want, stored.Status)
+		}
+		var failed models.Dispute
+		db.First(&failed, ids[2])
+		assert.Equal(t, models.DisputeStatusOpen, failed.Status)
+
+		// Each resolved dispute gets its own audit entry.
+		var entries []models.AuditLog
+		db.Where("action = ?", "dispute.resolved").Order("id").Find(&entries)
+		if assert.Len(t, entries, 2) {
+			assert.Equal(t, fmt.Sprint(ids[0]), entries[0].EntityID)
+			assert.Equal(t, models.DisputeStatusOpen, entries[0].OldStatus)
+			assert.Equal(t, models.DisputeStatusResolved, entries[0].NewStatus)
+			assert.Equal(t, "carrier confirmed delivery", entries[0].Reason)
+		}
+	})
+}
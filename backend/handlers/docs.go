@@ -2,9 +2,13 @@ package handlers
 
 import (
 	_ "embed"
+	"encoding/json"
 	"net/http"
+	"sync"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gpay-remit/errors"
+	"gopkg.in/yaml.v3"
 )
 
 //go:embed openapi.yaml
@@ -23,7 +27,7 @@ const swaggerUIHTML = `<!DOCTYPE html>
   <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
   <script>
     SwaggerUIBundle({
-      url: "/api/docs/openapi.yaml",
+      url: "/openapi.json",
       dom_id: "#swagger-ui",
       presets: [SwaggerUIBundle.presets.apis, SwaggerUIBundle.SwaggerUIStandalonePreset],
       layout: "BaseLayout",
@@ -43,3 +47,31 @@ func DocsUI(c *gin.Context) {
 func DocsSpec(c *gin.Context) {
 	c.Data(http.StatusOK, "application/yaml", openapiSpec)
 }
+
+var (
+	openapiJSONOnce sync.Once
+	openapiJSON     []byte
+	openapiJSONErr  error
+)
+
+// specJSON converts the embedded YAML specification to JSON once.
+func specJSON() ([]byte, error) {
+	openapiJSONOnce.Do(func() {
+		var doc interface{}
+		if openapiJSONErr = yaml.Unmarshal(openapiSpec, &doc); openapiJSONErr != nil {
+			return
+		}
+		openapiJSON, openapiJSONErr = json.Marshal(doc)
+	})
+	return openapiJSON, openapiJSONErr
+}
+
+// DocsJSON serves the OpenAPI specification as JSON.
+func DocsJSON(c *gin.Context) {
+	spec, err := specJSON()
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to render OpenAPI specification", err))
+		return
+	}
+	c.Data(http.StatusOK, "application/json", spec)
+}
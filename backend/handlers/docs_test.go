@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fetchOpenAPISpec(t *testing.T) map[string]interface{} {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/openapi.json", DocsJSON)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+
+	var spec map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	return spec
+}
+
+// object walks nested maps of a decoded JSON document.
+func object(t *testing.T, doc map[string]interface{}, keys ...string) map[string]interface{} {
+	t.Helper()
+	for _, key := range keys {
+		next, ok := doc[key].(map[string]interface{})
+		require.Truef(t, ok, "missing object %q", key)
+		doc = next
+	}
+	return doc
+}
+
+func TestOpenAPIJSON(t *testing.T) {
+	spec := fetchOpenAPISpec(t)
+
+	version, _ := spec["openapi"].(string)
+	assert.True(t, strings.HasPrefix(version, "3."), "openapi version %q", version)
+	assert.NotEmpty(t, object(t, spec, "info")["title"])
+
+	create := object(t, spec, "paths", "/remittances/create", "post")
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"BearerAuth": []interface{}{}},
+		map[string]interface{}{"ApiKeyAuth": []interface{}{}},
+	}, create["security"])
+	assert.NotEmpty(t, object(t, create, "responses"))
+
+	bearer := object(t, spec, "components", "securitySchemes", "BearerAuth")
+	assert.Equal(t, "http", bearer["type"])
+	assert.Equal(t, "bearer", bearer["scheme"])
+	assert.Equal(t, "JWT", bearer["bearerFormat"])
+}
+
+// TestOpenAPIRequestSchemasMatchBindings keeps the documented request bodies
+// in step with the binding tags the handlers actually validate.
+func TestOpenAPIRequestSchemasMatchBindings(t *testing.T) {
+	spec := fetchOpenAPISpec(t)
+
+	tests := []struct {
+		method string
+		path   string
+		body   interface{}
+	}{
+		{"post", "/auth/register", RegisterRequest{}},
+		{"post", "/auth/stellar/challenge", OwnershipChallengeRequest{}},
+		{"post", "/auth/login", LoginRequest{}},
+		{"post", "/auth/refresh", RefreshTokenRequest{}},
+		{"post", "/auth/2fa/verify", VerifyTwoFactorRequest{}},
+		{"post", "/remittances", SendRemittanceRequest{}},
+		{"post", "/remittances/create", CreateRemittanceRequest{}},
+		{"post", "/remittances/batch", CreateBatchRemittanceRequest{}},
+		{"post", "/remittances/estimate-total", EstimateTotalRequest{}},
+		{"post", "/remittances/{id}/submit", SubmitRemittanceRequest{}},
+		{"post", "/remittances/{id}/release", ReleaseEscrowRequest{}},
+		{"post", "/remittances/{id}/release/confirm", ConfirmReleaseRequest{}},
+		{"post", "/remittances/{id}/release/signatures", AddReleaseSignatureRequest{}},
+		{"post", "/remittances/{id}/complete", CompleteRemittanceRequest{}},
+		{"post", "/remittances/{id}/force-complete", ForceCompleteRemittanceRequest{}},
+		{"post", "/remittances/{id}/disputes", OpenDisputeRequest{}},
+		{"post", "/disputes/{id}/resolve", ResolveDisputeRequest{}},
+		{"post", "/disputes/bulk-resolve", BulkResolveDisputesRequest{}},
+		{"post", "/invoices", CreateInvoiceRequest{}},
+		{"post", "/invoices/batch", CreateBatchInvoicesRequest{}},
+		{"post", "/invoices/{id}/void", VoidInvoiceRequest{}},
+		{"post", "/invoices/{id}/cancel", CancelInvoiceRequest{}},
+		{"post", "/invoices/{id}/pay", PayInvoiceRequest{}},
+		{"patch", "/users/{id}/kyc", UpdateKYCRequest{}},
+		{"patch", "/users/{id}", UpdateUserRequest{}},
+		{"put", "/users/me", UpdateProfileRequest{}},
+		{"put", "/users/me/notification-preferences", UpdateNotificationPreferencesRequest{}},
+		{"post", "/accounts/trustlines", CreateTrustlineRequest{}},
+		{"post", "/accounts/merge", MergeAccountRequest{}},
+		{"post", "/recurring-remittances", CreateRecurringRemittanceRequest{}},
+		{"post", "/webhooks", CreateWebhookRequest{}},
+		{"post", "/apikeys", CreateAPIKeyRequest{}},
+		{"post", "/auth/password/forgot", ForgotPasswordRequest{}},
+		{"post", "/auth/password/reset", ResetPasswordRequest{}},
+		{"post", "/beneficiaries", CreateBeneficiaryRequest{}},
+		{"put", "/beneficiaries/{id}", UpdateBeneficiaryRequest{}},
+		{"put", "/webhooks/{id}", UpdateWebhookRequest{}},
+		{"post", "/internal/signing-callback", SigningCallbackRequest{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.ToUpper(tt.method)+" "+tt.path, func(t *testing.T) {
+			schema := object(t, spec, "paths", tt.path, tt.method, "requestBody", "content", "application/json", "schema")
+			assertSchemaMatches(t, spec, schema, reflect.TypeOf(tt.body))
+		})
+	}
+}
+
+func resolveSchema(t *testing.T, spec, schema map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	ref, ok := schema["$ref"].(string)
+	if !ok {
+		return schema
+	}
+	name := strings.TrimPrefix(ref, "#/components/schemas/")
+	return object(t, spec, "components", "schemas", name)
+}
+
+func assertSchemaMatches(t *testing.T, spec, schema map[string]interface{}, typ reflect.Type) {
+	t.Helper()
+	schema = resolveSchema(t, spec, schema)
+	properties := object(t, schema, "properties")
+
+	required := map[string]bool{}
+	if list, ok := schema["required"].([]interface{}); ok {
+		for _, name := range list {
+			required[name.(string)] = true
+		}
+	}
+
+	fields := map[string]bool{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = true
+
+		prop, ok := properties[name].(map[string]interface{})
+		if !assert.Truef(t, ok, "%s.%s is not documented as %q", typ.Name(), field.Name, name) {
+			continue
+		}
+
+		rules := map[string]bool{}
+		for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+			rules[rule] = true
+		}
+		assert.Equalf(t, rules["required"], required[name], "required mismatch for %q", name)
+		if rules["gt=0"] {
+			minimum, _ := prop["minimum"].(float64)
+			assert.Greaterf(t, minimum, 0.0, "%q must document a positive minimum", name)
+		}
+		if rules["min=1"] {
+			assert.EqualValuesf(t, 1, prop["minItems"], "%q must document minItems 1", name)
+		}
+
+		if field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Struct {
+			assertSchemaMatches(t, spec, object(t, prop, "items"), field.Type.Elem())
+		}
+	}
+
+	for name := range properties {
+		assert.Truef(t, fields[name], "documented property %q is not bound by %s", name, typ.Name())
+	}
+}
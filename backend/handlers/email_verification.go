@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/utils"
+	"gorm.io/gorm"
+)
+
+// emailVerificationPurpose separates email verification tokens from other
+// tokens signed with the same secret.
+const emailVerificationPurpose = "email-verification"
+
+func invalidVerificationTokenError() *errors.AppError {
+	return errors.NewValidationError("Invalid or expired verification token", nil)
+}
+
+// VerifyEmail confirms the email address of the user a verification token
+// from registration or ResendVerification was sent to. The token is taken
+// from the "token" query parameter, so the emailed link can point here
+// directly.
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if !utils.VerifySignedToken(h.Cfg.JWTSecret, emailVerificationPurpose, token) {
+		c.Error(invalidVerificationTokenError())
+		return
+	}
+
+	var record models.EmailVerificationToken
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", models.HashEmailVerificationToken(token), now).
+			First(&record).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&models.EmailVerificationToken{}).
+			Where("id = ? AND used_at IS NULL", record.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Model(&models.User{}).
+			Where("id = ? AND verified_at IS NULL", record.UserID).
+			Update("verified_at", now).Error
+	})
+	if err == gorm.ErrRecordNotFound {
+		c.Error(invalidVerificationTokenError())
+		return
+	}
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to verify email", err))
+		return
+	}
+
+	logger.Log.WithFields(logrus.Fields{
+		"user_id":  record.UserID,
+		"endpoint": "/auth/verify",
+	}).Info("Email verified")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified"})
+}
+
+// ResendVerification emails the authenticated user a new verification link,
+// discarding the earlier ones.
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	if user.VerifiedAt != nil {
+		c.Error(errors.NewConflictError("Email already verified"))
+		return
+	}
+
+	if err := h.sendVerificationEmail(&user); err != nil {
+		c.Error(errors.NewInternalError("Failed to send verification email", err))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Verification email sent"})
+}
+
+// sendVerificationEmail issues a verification token for user, replacing any
+// unused ones, and emails the link to it.
+func (h *AuthHandler) sendVerificationEmail(user *models.User) error {
+	token, err := utils.NewSignedToken(h.Cfg.JWTSecret, emailVerificationPurpose)
+	if err != nil {
+		return fmt.Errorf("failed to generate verification token: %w", err)
+	}
+	verifyURL, err := linkWithToken(h.Cfg.EmailVerificationURL, token)
+	if err != nil {
+		return fmt.Errorf("invalid email verification URL: %w", err)
+	}
+
+	record := models.EmailVerificationToken{
+		UserID:    user.ID,
+		TokenHash: models.HashEmailVerificationToken(token),
+		ExpiresAt: time.Now().Add(h.Cfg.EmailVerificationTTL),
+	}
+	err = h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND used_at IS NULL", user.ID).Delete(&models.EmailVerificationToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&record).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store verification token: %w", err)
+	}
+
+	return h.Mailer.SendVerificationEmail(user, verifyURL, record.ExpiresAt)
+}
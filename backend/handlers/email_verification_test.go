@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/models"
+)
+
+func TestEmailVerification(t *testing.T) {
+	handler, router := setupAuthHandler(t)
+	handler.Cfg.EmailVerificationTTL = 48 * time.Hour
+	handler.Cfg.EmailVerificationURL = "https://api.example.com/api/v1/auth/verify"
+	mailer := &recordingAccountMailer{}
+	handler.Mailer = mailer
+	router.GET("/auth/verify", handler.VerifyEmail)
+
+	body, _ := json.Marshal(RegisterRequest{Email: "verify@example.com", Name: "Verify", Password: "Secure@123", StellarAddress: "GVERIFY"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/auth/register", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var user models.User
+	require.NoError(t, handler.DB.Where("email = ?", "verify@example.com").First(&user).Error)
+	assert.Nil(t, user.VerifiedAt)
+	router.POST("/auth/verify/resend", func(c *gin.Context) {
+		c.Set("userID", user.ID)
+		c.Next()
+	}, handler.ResendVerification)
+
+	lastToken := func() string {
+		require.NotEmpty(t, mailer.verifications)
+		link, err := url.Parse(mailer.verifications[len(mailer.verifications)-1])
+		require.NoError(t, err)
+		assert.Equal(t, "api.example.com", link.Host)
+		return link.Query().Get("token")
+	}
+	verify := func(token string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/auth/verify?token="+url.QueryEscape(token), nil)
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	resend := func() int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/auth/verify/resend", nil)
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	first := lastToken()
+	assert.Equal(t, http.StatusBadRequest, verify("forged."+first))
+
+	require.Equal(t, http.StatusAccepted, resend())
+	second := lastToken()
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, http.StatusBadRequest, verify(first), "resending discards the earlier link")
+
+	require.Equal(t, http.StatusOK, verify(second))
+	require.NoError(t, handler.DB.First(&user, user.ID).Error)
+	assert.NotNil(t, user.VerifiedAt)
+
+	assert.Equal(t, http.StatusBadRequest, verify(second), "links work once")
+	assert.Equal(t, http.StatusConflict, resend())
+}
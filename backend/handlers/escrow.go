@@ -0,0 +1,375 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/services"
+	"github.com/yourusername/gpay-remit/utils"
+	"gorm.io/gorm"
+)
+
+type ReleaseEscrowRequest struct {
+	// AssetIssuer identifies the escrowed credit asset; it is not needed for XLM.
+	AssetIssuer string `json:"asset_issuer"`
+}
+
+type ConfirmReleaseRequest struct {
+	SignedXDR string `json:"signed_xdr" binding:"required"`
+}
+
+type AddReleaseSignatureRequest struct {
+	// SignedXDR is a partially signed copy of the release envelope to add the
+	// signature to. It defaults to the envelope collected so far.
+	SignedXDR string `json:"signed_xdr"`
+	PublicKey string `json:"public_key" binding:"required"`
+	// Signature is the base64 signature of the transaction hash by PublicKey.
+	Signature string `json:"signature" binding:"required"`
+}
+
+// isRecipientOrAdmin reports whether the authenticated user receives the payment or holds the admin role.
+func isRecipientOrAdmin(c *gin.Context, payment *models.Payment) bool {
+	if role, _ := c.Get("role"); role == "admin" {
+		return true
+	}
+	userID, ok := c.Get("userID")
+	if !ok {
+		return false
+	}
+	id, ok := userID.(uint)
+	return ok && payment.RecipientID != 0 && id == payment.RecipientID
+}
+
+// loadReleasable fetches the payment named in the path and checks that the
+// caller may release it and that its escrow is still held.
+func (h *RemittanceHandler) loadReleasable(c *gin.Context) (*models.Payment, bool) {
+	var payment models.Payment
+	if err := h.db.First(&payment, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("Payment not found").WithKey("error.payment_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch payment", err))
+		}
+		return nil, false
+	}
+
+	if !isRecipientOrAdmin(c, &payment) {
+		c.Error(errors.NewForbiddenError("Only the recipient or an admin can release this escrow"))
+		return nil, false
+	}
+	if payment.EscrowID == "" {
+		c.Error(errors.NewConflictError("Remittance has no escrow to release"))
+		return nil, false
+	}
+	switch payment.Status {
+	case "processing":
+	case "completed":
+		c.Error(errors.NewConflictError("Escrow has already been released"))
+		return nil, false
+	default:
+		c.Error(errors.NewConflictError(fmt.Sprintf("Escrow cannot be released while the remittance is %s", payment.Status)))
+		return nil, false
+	}
+	return &payment, true
+}
+
+// ReleaseEscrow builds the escrow contract's release call for a processing
+// remittance and returns it unsigned. The caller signs it and hands it back
+// through ConfirmRelease, which submits it and completes the remittance.
+func (h *RemittanceHandler) ReleaseEscrow(c *gin.Context) {
+	var req ReleaseEscrowRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(errors.NewBindingError(err))
+			return
+		}
+	}
+
+	payment, ok := h.loadReleasable(c)
+	if !ok {
+		return
+	}
+	if err := utils.ValidateAsset(payment.Currency, req.AssetIssuer); err != nil {
+		c.Error(errors.NewValidationError("Invalid asset", err.Error()).WithKey("error.invalid_asset"))
+		return
+	}
+	escrowID, err := strconv.ParseUint(payment.EscrowID, 10, 64)
+	if err != nil {
+		c.Error(errors.NewInternalError("Stored escrow ID is not a contract escrow ID", err))
+		return
+	}
+	if h.config.EscrowContractID == "" {
+		c.Error(errors.NewInternalError("Escrow contract is not configured", nil))
+		return
+	}
+
+	// The contract authorizes the release against the caller's own account.
+	userID, _ := c.Get("userID")
+	var caller models.User
+	if err := h.db.First(&caller, userID).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to fetch user", err))
+		return
+	}
+	if caller.StellarAddress == "" {
+		c.Error(errors.NewConflictError("Your account has no Stellar address to authorize the release"))
+		return
+	}
+
+	ctx := utils.WithRequestContext(c.Request.Context(), c.GetString("requestID"), userID)
+	envelope, err := h.stellarClient.BuildEscrowReleaseTx(ctx, caller.StellarAddress, h.config.EscrowContractID, escrowID, payment.Currency, req.AssetIssuer)
+	if err != nil {
+		if utils.IsSimulationError(err) {
+			c.Error(errors.NewConflictError(fmt.Sprintf("Escrow contract rejected the release: %s", err.Error())))
+		} else {
+			c.Error(errors.NewInternalError("Failed to build escrow release transaction", err))
+		}
+		return
+	}
+
+	if err := payment.UpdateVersioned(h.db, map[string]interface{}{"release_tx_envelope": envelope}); err != nil {
+		c.Error(paymentUpdateError(err, "Failed to store release transaction"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"remittance_id": payment.ID,
+		"escrow_id":     payment.EscrowID,
+		"tx_envelope":   envelope,
+		"message":       "Sign the release transaction and confirm it to complete the remittance.",
+	})
+}
+
+// ConfirmRelease submits the signed release built by ReleaseEscrow and, once
+// the network accepts it, marks the remittance completed.
+func (h *RemittanceHandler) ConfirmRelease(c *gin.Context) {
+	var req ConfirmReleaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+
+	payment, ok := h.loadReleasable(c)
+	if !ok {
+		return
+	}
+	if payment.ReleaseTxEnvelope == "" {
+		c.Error(errors.NewConflictError("No release transaction has been built for this remittance"))
+		return
+	}
+
+	signed, err := utils.DecodeTransactionSummary(req.SignedXDR, h.config.NetworkPassphrase)
+	if err != nil {
+		c.Error(errors.NewValidationError("Invalid signed transaction", err.Error()).WithKey("error.invalid_signed_transaction"))
+		return
+	}
+	if !h.checkSources(c, signed) {
+		return
+	}
+	stored, err := utils.DecodeTransactionSummary(payment.ReleaseTxEnvelope, h.config.NetworkPassphrase)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to decode stored release envelope", err))
+		return
+	}
+	if signed.Hash != stored.Hash {
+		c.Error(errors.NewValidationError("Signed transaction does not match the release envelope", nil))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	ctx := utils.WithRequestContext(c.Request.Context(), c.GetString("requestID"), userID)
+	txHash, err := h.stellarClient.SubmitTransaction(ctx, req.SignedXDR)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to submit release transaction", err))
+		return
+	}
+
+	middleware.SetAuditOld(c, *payment)
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := payment.UpdateVersioned(tx, map[string]interface{}{
+			"status":          "completed",
+			"release_tx_hash": txHash,
+		}); err != nil {
+			return err
+		}
+		payment.Status = "completed"
+		payment.ReleaseTxHash = txHash
+		_, err := services.NewInvoiceService(tx).MarkPaidForPayment(payment)
+		return err
+	})
+	if err != nil {
+		c.Error(paymentUpdateError(err, "Failed to update payment"))
+		return
+	}
+
+	logger.Log.WithFields(logrus.Fields{
+		"payment_id": payment.ID,
+		"escrow_id":  payment.EscrowID,
+		"tx_hash":    txHash,
+		"request_id": c.GetString("requestID"),
+	}).Info("Escrow released")
+	h.events.PublishPayment(*payment)
+
+	middleware.SetAuditNew(c, *payment)
+	c.JSON(http.StatusOK, *payment)
+}
+
+// AddReleaseSignature adds one signer's signature to the release envelope
+// built by ReleaseEscrow, keeping the signatures already collected, so a
+// multisig escrow can gather its signers across requests before confirming.
+// Signers sign the transaction hash themselves and send only the signature.
+func (h *RemittanceHandler) AddReleaseSignature(c *gin.Context) {
+	var req AddReleaseSignatureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+
+	payment, ok := h.loadReleasable(c)
+	if !ok {
+		return
+	}
+	if payment.ReleaseTxEnvelope == "" {
+		c.Error(errors.NewConflictError("No release transaction has been built for this remittance"))
+		return
+	}
+
+	envelope := payment.ReleaseTxEnvelope
+	if req.SignedXDR != "" {
+		partial, err := utils.DecodeTransactionSummary(req.SignedXDR, h.config.NetworkPassphrase)
+		if err != nil {
+			c.Error(errors.NewValidationError("Invalid signed transaction", err.Error()).WithKey("error.invalid_signed_transaction"))
+			return
+		}
+		stored, err := utils.DecodeTransactionSummary(payment.ReleaseTxEnvelope, h.config.NetworkPassphrase)
+		if err != nil {
+			c.Error(errors.NewInternalError("Failed to decode stored release envelope", err))
+			return
+		}
+		if partial.Hash != stored.Hash {
+			c.Error(errors.NewValidationError("Signed transaction does not match the release envelope", nil))
+			return
+		}
+		envelope = req.SignedXDR
+	}
+
+	combined, err := utils.AttachSignature(envelope, req.PublicKey, req.Signature, h.config.NetworkPassphrase)
+	if err != nil {
+		if utils.IsDuplicateSignature(err) {
+			c.Error(errors.NewConflictError("The release transaction is already signed by this key"))
+		} else {
+			c.Error(errors.NewValidationError("Invalid signature", err.Error()))
+		}
+		return
+	}
+	signatures, err := utils.CountSignatures(combined)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to count signatures", err))
+		return
+	}
+
+	if err := payment.UpdateVersioned(h.db, map[string]interface{}{"release_tx_envelope": combined}); err != nil {
+		c.Error(paymentUpdateError(err, "Failed to store release transaction"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"remittance_id": payment.ID,
+		"tx_envelope":   combined,
+		"signatures":    signatures,
+	})
+}
+
+// escrowLocalStatus is the payment status that matches an on-chain escrow
+// status.
+func escrowLocalStatus(status string) string {
+	switch status {
+	case utils.EscrowStatusPending, utils.EscrowStatusFunded, utils.EscrowStatusApproved:
+		return "processing"
+	case utils.EscrowStatusReleased:
+		return "completed"
+	case utils.EscrowStatusRefunded:
+		return services.PaymentStatusRefunded
+	case utils.EscrowStatusCancelled:
+		return "cancelled"
+	case utils.EscrowStatusExpired:
+		return services.PaymentStatusExpired
+	}
+	return services.PaymentStatusNeedsReview
+}
+
+// GetEscrowStatus reads the payment's escrow from the escrow contract with a
+// read-only simulation and compares it with the local status. A divergence
+// is reported and logged but not corrected here; the release, cancel, and
+// settlement flows own status changes.
+func (h *RemittanceHandler) GetEscrowStatus(c *gin.Context) {
+	var payment models.Payment
+	if err := h.db.First(&payment, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("Payment not found").WithKey("error.payment_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch payment", err))
+		}
+		return
+	}
+	if !isSenderOrAdmin(c, &payment) && !isRecipientOrAdmin(c, &payment) {
+		c.Error(errors.NewForbiddenError("Only the sender, recipient, or an admin can view this escrow"))
+		return
+	}
+	if payment.EscrowID == "" {
+		c.Error(errors.NewNotFoundError("Remittance has no escrow"))
+		return
+	}
+	escrowID, err := strconv.ParseUint(payment.EscrowID, 10, 64)
+	if err != nil {
+		c.Error(errors.NewInternalError("Stored escrow ID is not a contract escrow ID", err))
+		return
+	}
+	if h.config.EscrowContractID == "" {
+		c.Error(errors.NewInternalError("Escrow contract is not configured", nil))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	ctx := utils.WithRequestContext(c.Request.Context(), c.GetString("requestID"), userID)
+	result, err := h.stellarClient.QueryContract(ctx, h.config.EscrowContractID, utils.EscrowGetFunction, utils.EscrowQueryArgs(escrowID)...)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to query escrow contract", err))
+		return
+	}
+	escrow, err := utils.DecodeEscrow(result)
+	if err != nil {
+		if utils.IsEscrowNotFound(err) {
+			c.Error(errors.NewNotFoundError(fmt.Sprintf("Escrow %d not found on-chain", escrowID)))
+		} else {
+			c.Error(errors.NewInternalError("Failed to decode escrow", err))
+		}
+		return
+	}
+
+	expected := escrowLocalStatus(escrow.Status)
+	inSync := expected == payment.Status
+	if !inSync {
+		logger.Log.WithFields(logrus.Fields{
+			"payment_id":     payment.ID,
+			"escrow_id":      escrowID,
+			"escrow_status":  escrow.Status,
+			"local_status":   payment.Status,
+			"expected_local": expected,
+		}).Warn("Escrow status diverges from local payment status")
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"remittance_id":         payment.ID,
+		"escrow":                escrow,
+		"local_status":          payment.Status,
+		"expected_local_status": expected,
+		"in_sync":               inSync,
+	})
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/services"
+	"github.com/yourusername/gpay-remit/utils"
+	"gorm.io/gorm"
+)
+
+type RefundEscrowRequest struct {
+	// AssetIssuer identifies the escrowed credit asset; it is not needed for XLM.
+	AssetIssuer string `json:"asset_issuer"`
+	// RefundAmount refunds part of the escrow. It defaults to everything
+	// refundable: the escrowed amount less the non-refundable fee.
+	RefundAmount float64 `json:"refund_amount" binding:"omitempty,gt=0"`
+}
+
+type ConfirmRefundRequest struct {
+	SignedXDR string `json:"signed_xdr" binding:"required"`
+}
+
+// loadRefundable fetches the payment named in the path and checks that the
+// caller may refund it and that its escrow can be refunded. Failed payments
+// are refunded by their sender or an admin; disputed ones only by the admin
+// resolving the dispute.
+func (h *RemittanceHandler) loadRefundable(c *gin.Context) (*models.Payment, bool) {
+	var payment models.Payment
+	if err := h.db.First(&payment, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("Payment not found").WithKey("error.payment_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch payment", err))
+		}
+		return nil, false
+	}
+
+	if !isSenderOrAdmin(c, &payment) {
+		c.Error(errors.NewForbiddenError("Only the sender or an admin can refund this escrow"))
+		return nil, false
+	}
+	if payment.EscrowID == "" {
+		c.Error(errors.NewConflictError("Remittance has no escrow to refund"))
+		return nil, false
+	}
+	switch payment.Status {
+	case "failed":
+	case services.PaymentStatusDisputed:
+		if role, _ := c.Get("role"); role != "admin" {
+			c.Error(errors.NewForbiddenError("Only an admin can refund a disputed remittance"))
+			return nil, false
+		}
+	case services.PaymentStatusRefunded:
+		c.Error(errors.NewConflictError("Escrow has already been refunded"))
+		return nil, false
+	default:
+		c.Error(errors.NewConflictError(fmt.Sprintf("Escrow cannot be refunded while the remittance is %s", payment.Status)))
+		return nil, false
+	}
+	return &payment, true
+}
+
+// refundReason is the reason the escrow contract records for a refund.
+func refundReason(c *gin.Context, payment *models.Payment) string {
+	if payment.Status == services.PaymentStatusDisputed {
+		return utils.EscrowRefundReasonDispute
+	}
+	if role, _ := c.Get("role"); role == "admin" {
+		return utils.EscrowRefundReasonAdminAction
+	}
+	return utils.EscrowRefundReasonSenderRequest
+}
+
+// RefundEscrow builds the escrow contract's refund call for a failed or
+// disputed remittance and returns it unsigned. The caller signs it and hands
+// it back through ConfirmRefund, which submits it and marks the remittance
+// refunded.
+func (h *RemittanceHandler) RefundEscrow(c *gin.Context) {
+	var req RefundEscrowRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(errors.NewBindingError(err))
+			return
+		}
+	}
+
+	payment, ok := h.loadRefundable(c)
+	if !ok {
+		return
+	}
+	if err := utils.ValidateAsset(payment.Currency, req.AssetIssuer); err != nil {
+		c.Error(errors.NewValidationError("Invalid asset", err.Error()).WithKey("error.invalid_asset"))
+		return
+	}
+
+	refundable, err := services.RefundableAmount(payment)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to compute refundable amount", err))
+		return
+	}
+	if refundable <= 0 {
+		c.Error(errors.NewConflictError("Nothing to refund: the non-refundable fee meets or exceeds the escrowed amount"))
+		return
+	}
+	refund := refundable
+	if req.RefundAmount > 0 {
+		if refund, err = utils.ExactAmount(req.RefundAmount); err != nil {
+			c.Error(errors.NewValidationError("Invalid refund amount", err.Error()))
+			return
+		}
+		if refund > refundable {
+			c.Error(errors.NewValidationError(
+				fmt.Sprintf("Refund amount %s %s exceeds the refundable %s %s", refund, payment.Currency, refundable, payment.Currency),
+				gin.H{"refundable_amount": refundable},
+			))
+			return
+		}
+	}
+
+	escrowID, err := strconv.ParseUint(payment.EscrowID, 10, 64)
+	if err != nil {
+		c.Error(errors.NewInternalError("Stored escrow ID is not a contract escrow ID", err))
+		return
+	}
+	if h.config.EscrowContractID == "" {
+		c.Error(errors.NewInternalError("Escrow contract is not configured", nil))
+		return
+	}
+
+	// The contract authorizes the refund against the caller's own account.
+	userID, _ := c.Get("userID")
+	var caller models.User
+	if err := h.db.First(&caller, userID).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to fetch user", err))
+		return
+	}
+	if caller.StellarAddress == "" {
+		c.Error(errors.NewConflictError("Your account has no Stellar address to authorize the refund"))
+		return
+	}
+
+	ctx := utils.WithRequestContext(c.Request.Context(), c.GetString("requestID"), userID)
+	envelope, err := h.stellarClient.BuildEscrowRefundTx(ctx, caller.StellarAddress, h.config.EscrowContractID, escrowID, payment.Currency, req.AssetIssuer, refund, refundReason(c, payment))
+	if err != nil {
+		if utils.IsSimulationError(err) {
+			c.Error(errors.NewConflictError(fmt.Sprintf("Escrow contract rejected the refund: %s", err.Error())))
+		} else {
+			c.Error(errors.NewInternalError("Failed to build escrow refund transaction", err))
+		}
+		return
+	}
+
+	if err := payment.UpdateVersioned(h.db, map[string]interface{}{
+		"refund_tx_envelope": envelope,
+		"refund_amount":      refund.Float64(),
+	}); err != nil {
+		c.Error(paymentUpdateError(err, "Failed to store refund transaction"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"remittance_id":     payment.ID,
+		"escrow_id":         payment.EscrowID,
+		"refund_amount":     refund,
+		"refundable_amount": refundable,
+		"tx_envelope":       envelope,
+		"message":           "Sign the refund transaction and confirm it to return the funds to the sender.",
+	})
+}
+
+// ConfirmRefund submits the signed refund built by RefundEscrow and, once the
+// network accepts it, marks the remittance refunded.
+func (h *RemittanceHandler) ConfirmRefund(c *gin.Context) {
+	var req ConfirmRefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+
+	payment, ok := h.loadRefundable(c)
+	if !ok {
+		return
+	}
+	if payment.RefundTxEnvelope == "" {
+		c.Error(errors.NewConflictError("No refund transaction has been built for this remittance"))
+		return
+	}
+
+	signed, err := utils.DecodeTransactionSummary(req.SignedXDR, h.config.NetworkPassphrase)
+	if err != nil {
+		c.Error(errors.NewValidationError("Invalid signed transaction", err.Error()).WithKey("error.invalid_signed_transaction"))
+		return
+	}
+	if !h.checkSources(c, signed) {
+		return
+	}
+	stored, err := utils.DecodeTransactionSummary(payment.RefundTxEnvelope, h.config.NetworkPassphrase)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to decode stored refund envelope", err))
+		return
+	}
+	if signed.Hash != stored.Hash {
+		c.Error(errors.NewValidationError("Signed transaction does not match the refund envelope", nil))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	ctx := utils.WithRequestContext(c.Request.Context(), c.GetString("requestID"), userID)
+	txHash, err := h.stellarClient.SubmitTransaction(ctx, req.SignedXDR)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to submit refund transaction", err))
+		return
+	}
+
+	middleware.SetAuditOld(c, *payment)
+	if err := payment.UpdateVersioned(h.db, map[string]interface{}{
+		"status":         services.PaymentStatusRefunded,
+		"refund_tx_hash": txHash,
+	}); err != nil {
+		c.Error(paymentUpdateError(err, "Failed to update payment"))
+		return
+	}
+	payment.Status = services.PaymentStatusRefunded
+	payment.RefundTxHash = txHash
+
+	logger.Log.WithFields(logrus.Fields{
+		"payment_id":    payment.ID,
+		"escrow_id":     payment.EscrowID,
+		"refund_amount": payment.RefundAmount,
+		"tx_hash":       txHash,
+		"request_id":    c.GetString("requestID"),
+	}).Info("Escrow refunded")
+	h.events.PublishPayment(*payment)
+
+	middleware.SetAuditNew(c, *payment)
+	c.JSON(http.StatusOK, *payment)
+}
@@ -0,0 +1,498 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/utils"
+)
+
+func TestReleaseEscrow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	cfg := &config.Config{
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		EscrowContractID:  "CESCROW",
+	}
+
+	recipientKP, _ := keypair.Random()
+	sender := models.User{Email: "sender@example.com", Name: "Sender", StellarAddress: "GSENDER", PasswordHash: "x"}
+	recipient := models.User{Email: "recipient@example.com", Name: "Recipient", StellarAddress: recipientKP.Address(), PasswordHash: "x"}
+	admin := models.User{Email: "admin@example.com", Name: "Admin", StellarAddress: "GADMIN", PasswordHash: "x", Role: "admin"}
+	require.NoError(t, db.Create(&sender).Error)
+	require.NoError(t, db.Create(&recipient).Error)
+	require.NoError(t, db.Create(&admin).Error)
+
+	// The release envelope is a real transaction so the signed copy can be matched by hash.
+	releaseTx, err := utils.NewStellarClient("https://horizon-testnet.stellar.org", cfg.NetworkPassphrase).
+		BuildPaymentTx(context.Background(), &txnbuild.SimpleAccount{AccountID: recipientKP.Address(), Sequence: 1}, recipientKP.Address(), "XLM", "", "1", nil)
+	require.NoError(t, err)
+	releaseEnvelope, _ := releaseTx.Base64()
+	signedTx, _ := releaseTx.Sign(cfg.NetworkPassphrase, recipientKP)
+	signedRelease, _ := signedTx.Base64()
+
+	var releaseCalls []string
+	mockStellar := &MockStellarClient{
+		BuildEscrowReleaseTxFunc: func(caller, contractID string, escrowID uint64, assetCode, issuer string) (string, error) {
+			releaseCalls = append(releaseCalls, fmt.Sprintf("%s/%s/%d/%s", caller, contractID, escrowID, assetCode))
+			return releaseEnvelope, nil
+		},
+		SubmitTransactionFunc: func(signedXDR string) (string, error) {
+			return "release_hash", nil
+		},
+	}
+	handler := &RemittanceHandler{db: db, config: cfg, stellarClient: mockStellar}
+
+	newRouter := func(user models.User) *gin.Engine {
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", user.ID)
+			c.Set("role", user.Role)
+			c.Next()
+		})
+		router.POST("/remittances/:id/release", handler.ReleaseEscrow)
+		router.POST("/remittances/:id/release/confirm", handler.ConfirmRelease)
+		router.POST("/remittances/:id/release/signatures", handler.AddReleaseSignature)
+		return router
+	}
+	post := func(router *gin.Engine, path string, body interface{}) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&buf).Encode(body)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, path, &buf)
+		router.ServeHTTP(w, req)
+		return w
+	}
+	newPayment := func(status, escrowID string) models.Payment {
+		payment := models.Payment{SenderID: sender.ID, RecipientID: recipient.ID, Amount: 10, Currency: "XLM", Status: status, EscrowID: escrowID}
+		require.NoError(t, db.Create(&payment).Error)
+		return payment
+	}
+
+	t.Run("Sender cannot release", func(t *testing.T) {
+		payment := newPayment("processing", "42")
+		w := post(newRouter(sender), fmt.Sprintf("/remittances/%d/release", payment.ID), nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Empty(t, releaseCalls)
+	})
+
+	t.Run("Recipient releases and confirms", func(t *testing.T) {
+		payment := newPayment("processing", "42")
+		router := newRouter(recipient)
+
+		w := post(router, fmt.Sprintf("/remittances/%d/release", payment.ID), nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), releaseEnvelope)
+		assert.Equal(t, []string{recipientKP.Address() + "/CESCROW/42/XLM"}, releaseCalls)
+
+		var stored models.Payment
+		db.First(&stored, payment.ID)
+		assert.Equal(t, "processing", stored.Status, "building the release does not complete the remittance")
+		assert.Equal(t, releaseEnvelope, stored.ReleaseTxEnvelope)
+
+		w = post(router, fmt.Sprintf("/remittances/%d/release/confirm", payment.ID), ConfirmReleaseRequest{SignedXDR: signedRelease})
+		require.Equal(t, http.StatusOK, w.Code)
+		db.First(&stored, payment.ID)
+		assert.Equal(t, "completed", stored.Status)
+		assert.Equal(t, "release_hash", stored.ReleaseTxHash)
+
+		// Releasing again is a conflict.
+		assert.Equal(t, http.StatusConflict, post(router, fmt.Sprintf("/remittances/%d/release", payment.ID), nil).Code)
+		assert.Equal(t, http.StatusConflict, post(router, fmt.Sprintf("/remittances/%d/release/confirm", payment.ID), ConfirmReleaseRequest{SignedXDR: signedRelease}).Code)
+	})
+
+	t.Run("Admin can release", func(t *testing.T) {
+		payment := newPayment("processing", "7")
+		w := post(newRouter(admin), fmt.Sprintf("/remittances/%d/release", payment.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("State guards", func(t *testing.T) {
+		router := newRouter(recipient)
+		for _, tc := range []struct {
+			status, escrowID string
+		}{
+			{"refunded", "42"},
+			{"cancelled", "42"},
+			{"pending", "42"},
+			{"processing", ""},
+		} {
+			payment := newPayment(tc.status, tc.escrowID)
+			w := post(router, fmt.Sprintf("/remittances/%d/release", payment.ID), nil)
+			assert.Equal(t, http.StatusConflict, w.Code, "status %q escrow %q", tc.status, tc.escrowID)
+		}
+	})
+
+	t.Run("Confirm without a built release", func(t *testing.T) {
+		payment := newPayment("processing", "42")
+		w := post(newRouter(recipient), fmt.Sprintf("/remittances/%d/release/confirm", payment.ID), ConfirmReleaseRequest{SignedXDR: signedRelease})
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Signatures are collected across requests", func(t *testing.T) {
+		payment := newPayment("processing", "42")
+		router := newRouter(recipient)
+		require.Equal(t, http.StatusOK, post(router, fmt.Sprintf("/remittances/%d/release", payment.ID), nil).Code)
+
+		hash, err := releaseTx.Hash(cfg.NetworkPassphrase)
+		require.NoError(t, err)
+		path := fmt.Sprintf("/remittances/%d/release/signatures", payment.ID)
+		addSignature := func(kp *keypair.Full, signedXDR string) *httptest.ResponseRecorder {
+			signature, err := kp.SignBase64(hash[:])
+			require.NoError(t, err)
+			return post(router, path, AddReleaseSignatureRequest{SignedXDR: signedXDR, PublicKey: kp.Address(), Signature: signature})
+		}
+
+		// The first signer extends a copy they already signed elsewhere.
+		cosigner, _ := keypair.Random()
+		w := addSignature(cosigner, signedRelease)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			TxEnvelope string `json:"tx_envelope"`
+			Signatures int    `json:"signatures"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 2, resp.Signatures)
+
+		// The next signer builds on the stored envelope.
+		third, _ := keypair.Random()
+		w = addSignature(third, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 3, resp.Signatures)
+
+		var stored models.Payment
+		db.First(&stored, payment.ID)
+		assert.Equal(t, resp.TxEnvelope, stored.ReleaseTxEnvelope)
+
+		assert.Equal(t, http.StatusConflict, addSignature(cosigner, "").Code, "duplicate signatures are rejected")
+
+		stranger, _ := keypair.Random()
+		w = post(router, path, AddReleaseSignatureRequest{PublicKey: stranger.Address(), Signature: "bm90IGEgc2lnbmF0dXJl"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = post(router, fmt.Sprintf("/remittances/%d/release/confirm", payment.ID), ConfirmReleaseRequest{SignedXDR: resp.TxEnvelope})
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Contract rejection is a conflict", func(t *testing.T) {
+		payment := newPayment("processing", "42")
+		mockStellar.BuildEscrowReleaseTxFunc = func(caller, contractID string, escrowID uint64, assetCode, issuer string) (string, error) {
+			return "", &utils.SimulationError{ContractID: contractID, Function: utils.EscrowReleaseFunction, Message: "HostError: Error(Contract, #5)"}
+		}
+		w := post(newRouter(recipient), fmt.Sprintf("/remittances/%d/release", payment.ID), nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "Error(Contract, #5)")
+	})
+}
+
+// escrowScVal encodes an escrow as get_escrow returns it: a struct as a map
+// keyed by field name (in sorted order), with its status as a unit enum.
+func escrowScVal(t *testing.T, sender, recipient, status string) xdr.ScVal {
+	sym := func(s string) xdr.ScVal {
+		v := xdr.ScSymbol(s)
+		return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &v}
+	}
+	u64 := func(n uint64) xdr.ScVal {
+		v := xdr.Uint64(n)
+		return xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &v}
+	}
+	u32 := func(n uint32) xdr.ScVal {
+		v := xdr.Uint32(n)
+		return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v}
+	}
+	boolean := func(b bool) xdr.ScVal { return xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &b} }
+	i128 := func(n uint64) xdr.ScVal {
+		return xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Lo: xdr.Uint64(n)}}
+	}
+	account := func(address string) xdr.ScVal {
+		var id xdr.AccountId
+		require.NoError(t, id.SetAddress(address))
+		return xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &id}}
+	}
+	structVal := func(keys []string, vals ...xdr.ScVal) xdr.ScVal {
+		m := &xdr.ScMap{}
+		for i, key := range keys {
+			*m = append(*m, xdr.ScMapEntry{Key: sym(key), Val: vals[i]})
+		}
+		return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &m}
+	}
+	statusVec := &xdr.ScVec{sym(status)}
+
+	conditions := structVal(
+		[]string{"current_approvals", "expiration_timestamp", "min_approvals", "oracle_confirmation", "recipient_approval"},
+		u32(0), u64(1735689600), u32(0), boolean(false), boolean(true),
+	)
+	return structVal(
+		[]string{"amount", "deposited_amount", "escrow_id", "recipient", "refund_timestamp", "refunded_amount", "release_conditions", "release_timestamp", "released_amount", "sender", "status"},
+		i128(250_000_000), i128(250_000_000), u64(42), account(recipient), u64(0), i128(0), conditions, u64(0), i128(0), account(sender),
+		xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &statusVec},
+	)
+}
+
+func TestGetEscrowStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	cfg := &config.Config{EscrowContractID: "CESCROW"}
+
+	senderKP, _ := keypair.Random()
+	recipientKP, _ := keypair.Random()
+	sender := models.User{Email: "sender@example.com", Name: "Sender", StellarAddress: senderKP.Address(), PasswordHash: "x"}
+	recipient := models.User{Email: "recipient@example.com", Name: "Recipient", StellarAddress: recipientKP.Address(), PasswordHash: "x"}
+	other := models.User{Email: "other@example.com", Name: "Other", StellarAddress: "GOTHER", PasswordHash: "x"}
+	require.NoError(t, db.Create(&sender).Error)
+	require.NoError(t, db.Create(&recipient).Error)
+	require.NoError(t, db.Create(&other).Error)
+
+	onChain := map[uint64]xdr.ScVal{}
+	var queries []string
+	mockStellar := &MockStellarClient{
+		QueryContractFunc: func(contractID, function string, args ...xdr.ScVal) (xdr.ScVal, error) {
+			require.Len(t, args, 1)
+			id := uint64(*args[0].U64)
+			queries = append(queries, fmt.Sprintf("%s/%s/%d", contractID, function, id))
+			if val, ok := onChain[id]; ok {
+				return val, nil
+			}
+			return xdr.ScVal{Type: xdr.ScValTypeScvVoid}, nil
+		},
+	}
+	handler := &RemittanceHandler{db: db, config: cfg, stellarClient: mockStellar}
+
+	get := func(user models.User, paymentID uint) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", user.ID)
+			c.Set("role", user.Role)
+			c.Next()
+		})
+		router.GET("/remittances/:id/escrow", handler.GetEscrowStatus)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/remittances/%d/escrow", paymentID), nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+	newPayment := func(escrowID, status string) models.Payment {
+		payment := models.Payment{SenderID: sender.ID, RecipientID: recipient.ID, Amount: 25, Currency: "XLM", Status: status, EscrowID: escrowID}
+		require.NoError(t, db.Create(&payment).Error)
+		return payment
+	}
+
+	type escrowResponse struct {
+		Escrow              utils.EscrowState `json:"escrow"`
+		LocalStatus         string            `json:"local_status"`
+		ExpectedLocalStatus string            `json:"expected_local_status"`
+		InSync              bool              `json:"in_sync"`
+	}
+
+	t.Run("Escrow matches the local status", func(t *testing.T) {
+		onChain[42] = escrowScVal(t, senderKP.Address(), recipientKP.Address(), utils.EscrowStatusFunded)
+		payment := newPayment("42", "processing")
+
+		w := get(sender, payment.ID)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp escrowResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, utils.EscrowStatusFunded, resp.Escrow.Status)
+		assert.Equal(t, "25.0000000", resp.Escrow.Amount)
+		assert.Equal(t, recipientKP.Address(), resp.Escrow.Recipient)
+		assert.True(t, resp.Escrow.ReleaseConditions.RecipientApproval)
+		assert.True(t, resp.InSync)
+		assert.Contains(t, queries, "CESCROW/get_escrow/42")
+
+		assert.Equal(t, http.StatusOK, get(recipient, payment.ID).Code)
+	})
+
+	t.Run("Divergence is reported without changing the payment", func(t *testing.T) {
+		onChain[43] = escrowScVal(t, senderKP.Address(), recipientKP.Address(), utils.EscrowStatusReleased)
+		payment := newPayment("43", "processing")
+
+		w := get(sender, payment.ID)
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp escrowResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.False(t, resp.InSync)
+		assert.Equal(t, "processing", resp.LocalStatus)
+		assert.Equal(t, "completed", resp.ExpectedLocalStatus)
+
+		var stored models.Payment
+		require.NoError(t, db.First(&stored, payment.ID).Error)
+		assert.Equal(t, "processing", stored.Status)
+	})
+
+	t.Run("Escrow missing on-chain", func(t *testing.T) {
+		payment := newPayment("99", "processing")
+		assert.Equal(t, http.StatusNotFound, get(sender, payment.ID).Code)
+	})
+
+	t.Run("Remittance without an escrow", func(t *testing.T) {
+		payment := newPayment("", "pending")
+		assert.Equal(t, http.StatusNotFound, get(sender, payment.ID).Code)
+	})
+
+	t.Run("Unrelated user", func(t *testing.T) {
+		payment := newPayment("42", "processing")
+		assert.Equal(t, http.StatusForbidden, get(other, payment.ID).Code)
+	})
+}
+
+func TestRefundEscrow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	cfg := &config.Config{
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		EscrowContractID:  "CESCROW",
+	}
+
+	senderKP, _ := keypair.Random()
+	sender := models.User{Email: "sender@example.com", Name: "Sender", StellarAddress: senderKP.Address(), PasswordHash: "x"}
+	recipient := models.User{Email: "recipient@example.com", Name: "Recipient", StellarAddress: "GRECIPIENT", PasswordHash: "x"}
+	admin := models.User{Email: "admin@example.com", Name: "Admin", StellarAddress: "GADMIN", PasswordHash: "x", Role: "admin"}
+	require.NoError(t, db.Create(&sender).Error)
+	require.NoError(t, db.Create(&recipient).Error)
+	require.NoError(t, db.Create(&admin).Error)
+
+	refundTx, err := utils.NewStellarClient("https://horizon-testnet.stellar.org", cfg.NetworkPassphrase).
+		BuildPaymentTx(context.Background(), &txnbuild.SimpleAccount{AccountID: senderKP.Address(), Sequence: 1}, senderKP.Address(), "XLM", "", "1", nil)
+	require.NoError(t, err)
+	refundEnvelope, _ := refundTx.Base64()
+	signedTx, _ := refundTx.Sign(cfg.NetworkPassphrase, senderKP)
+	signedRefund, _ := signedTx.Base64()
+
+	var refundCalls []string
+	mockStellar := &MockStellarClient{
+		BuildEscrowRefundTxFunc: func(caller, contractID string, escrowID uint64, assetCode, issuer string, amount utils.Amount, reason string) (string, error) {
+			refundCalls = append(refundCalls, fmt.Sprintf("%s/%d/%s/%s/%s", contractID, escrowID, assetCode, amount, reason))
+			return refundEnvelope, nil
+		},
+		SubmitTransactionFunc: func(signedXDR string) (string, error) {
+			return "refund_hash", nil
+		},
+	}
+	handler := &RemittanceHandler{db: db, config: cfg, stellarClient: mockStellar}
+
+	newRouter := func(user models.User) *gin.Engine {
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", user.ID)
+			c.Set("role", user.Role)
+			c.Next()
+		})
+		router.POST("/remittances/:id/refund", handler.RefundEscrow)
+		router.POST("/remittances/:id/refund/confirm", handler.ConfirmRefund)
+		return router
+	}
+	post := func(router *gin.Engine, path string, body interface{}) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&buf).Encode(body)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, path, &buf)
+		router.ServeHTTP(w, req)
+		return w
+	}
+	newPayment := func(status, escrowID string) models.Payment {
+		payment := models.Payment{
+			SenderID: sender.ID, RecipientID: recipient.ID, Amount: 10, Currency: "XLM", Status: status, EscrowID: escrowID,
+			Fee: 0.75, PlatformFee: 0.5, NetworkFee: 0.05, ComplianceFee: 0.2,
+		}
+		require.NoError(t, db.Create(&payment).Error)
+		return payment
+	}
+
+	t.Run("Full refund less the non-refundable fee", func(t *testing.T) {
+		refundCalls = nil
+		payment := newPayment("failed", "42")
+		router := newRouter(sender)
+
+		w := post(router, fmt.Sprintf("/remittances/%d/refund", payment.ID), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), `"refund_amount":"9.7500000"`)
+		assert.Equal(t, []string{"CESCROW/42/XLM/9.7500000/SenderRequest"}, refundCalls)
+
+		var stored models.Payment
+		db.First(&stored, payment.ID)
+		assert.Equal(t, "failed", stored.Status, "building the refund does not refund the remittance")
+		assert.Equal(t, refundEnvelope, stored.RefundTxEnvelope)
+		assert.Equal(t, 9.75, stored.RefundAmount)
+
+		w = post(router, fmt.Sprintf("/remittances/%d/refund/confirm", payment.ID), ConfirmRefundRequest{SignedXDR: signedRefund})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		db.First(&stored, payment.ID)
+		assert.Equal(t, "refunded", stored.Status)
+		assert.Equal(t, "refund_hash", stored.RefundTxHash)
+
+		assert.Equal(t, http.StatusConflict, post(router, fmt.Sprintf("/remittances/%d/refund", payment.ID), nil).Code)
+	})
+
+	t.Run("Partial refund", func(t *testing.T) {
+		refundCalls = nil
+		payment := newPayment("failed", "43")
+		w := post(newRouter(admin), fmt.Sprintf("/remittances/%d/refund", payment.ID), RefundEscrowRequest{RefundAmount: 4.5})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, []string{"CESCROW/43/XLM/4.5000000/AdminAction"}, refundCalls)
+	})
+
+	t.Run("Refund above the refundable amount", func(t *testing.T) {
+		refundCalls = nil
+		payment := newPayment("failed", "44")
+		w := post(newRouter(sender), fmt.Sprintf("/remittances/%d/refund", payment.ID), RefundEscrowRequest{RefundAmount: 10})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, refundCalls)
+	})
+
+	t.Run("Fee covering the escrow leaves nothing to refund", func(t *testing.T) {
+		payment := models.Payment{SenderID: sender.ID, RecipientID: recipient.ID, Amount: 0.1, Currency: "XLM", Status: "failed", EscrowID: "45", NetworkFee: 0.05, ComplianceFee: 0.2}
+		require.NoError(t, db.Create(&payment).Error)
+		assert.Equal(t, http.StatusConflict, post(newRouter(sender), fmt.Sprintf("/remittances/%d/refund", payment.ID), nil).Code)
+	})
+
+	t.Run("Never escrowed", func(t *testing.T) {
+		refundCalls = nil
+		payment := newPayment("failed", "")
+		w := post(newRouter(sender), fmt.Sprintf("/remittances/%d/refund", payment.ID), nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "no escrow")
+		assert.Empty(t, refundCalls)
+	})
+
+	t.Run("Only the sender or an admin", func(t *testing.T) {
+		payment := newPayment("failed", "46")
+		assert.Equal(t, http.StatusForbidden, post(newRouter(recipient), fmt.Sprintf("/remittances/%d/refund", payment.ID), nil).Code)
+	})
+
+	t.Run("Disputed remittances are refunded by an admin", func(t *testing.T) {
+		refundCalls = nil
+		payment := newPayment("disputed", "47")
+		assert.Equal(t, http.StatusForbidden, post(newRouter(sender), fmt.Sprintf("/remittances/%d/refund", payment.ID), nil).Code)
+		w := post(newRouter(admin), fmt.Sprintf("/remittances/%d/refund", payment.ID), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, []string{"CESCROW/47/XLM/9.7500000/Dispute"}, refundCalls)
+	})
+
+	t.Run("Processing remittances cannot be refunded", func(t *testing.T) {
+		payment := newPayment("processing", "48")
+		assert.Equal(t, http.StatusConflict, post(newRouter(sender), fmt.Sprintf("/remittances/%d/refund", payment.ID), nil).Code)
+	})
+}
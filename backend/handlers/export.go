@@ -148,7 +148,7 @@ func (h *ExportHandler) exportCSV(c *gin.Context, payments []models.Payment) {
 			fmt.Sprintf("%.4f", payment.NetworkFee),
 			payment.TxHash,
 			payment.EscrowID,
-			payment.Notes,
+			payment.Notes.String(),
 		}
 		if err := writer.Write(row); err != nil {
 			c.Error(errors.NewInternalError("Failed to write CSV row", err))
@@ -223,7 +223,7 @@ func (h *ExportHandler) exportPDF(c *gin.Context, payments []models.Payment) {
 		if len(txHash) > 10 {
 			txHash = txHash[:10] + "..."
 		}
-		notes := payment.Notes
+		notes := payment.Notes.String()
 		if len(notes) > 30 {
 			notes = notes[:30] + "..."
 		}
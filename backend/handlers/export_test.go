@@ -11,6 +11,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/gpay-remit/middleware"
 	"github.com/yourusername/gpay-remit/models"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -42,7 +43,7 @@ func seedTestPayments(db *gorm.DB, count int) []models.Payment {
 			NetworkFee:      0.5,
 			TxHash:          fmt.Sprintf("hash%d", i),
 			EscrowID:        fmt.Sprintf("escrow%d", i),
-			Notes:           fmt.Sprintf("Test payment %d", i),
+			Notes:           models.EncryptedString(fmt.Sprintf("Test payment %d", i)),
 			CreatedAt:       time.Now().Add(time.Duration(-i) * time.Hour),
 		}
 		db.Create(&payment)
@@ -58,6 +59,7 @@ func TestExportTransactionsCSV(t *testing.T) {
 
 	handler := NewExportHandler(db)
 	router := gin.New()
+	router.Use(middleware.ErrorHandler())
 	router.Use(gin.Recovery())
 	router.GET("/api/v1/transactions/export", handler.ExportTransactions)
 
@@ -94,6 +96,7 @@ func TestExportTransactionsPDF(t *testing.T) {
 
 	handler := NewExportHandler(db)
 	router := gin.New()
+	router.Use(middleware.ErrorHandler())
 	router.Use(gin.Recovery())
 	router.GET("/api/v1/transactions/export", handler.ExportTransactions)
 
@@ -132,6 +135,7 @@ func TestExportTransactionsWithFilters(t *testing.T) {
 
 	handler := NewExportHandler(db)
 	router := gin.New()
+	router.Use(middleware.ErrorHandler())
 	router.Use(gin.Recovery())
 	router.GET("/api/v1/transactions/export", handler.ExportTransactions)
 
@@ -173,6 +177,7 @@ func TestExportTransactionsInvalidFormat(t *testing.T) {
 
 	handler := NewExportHandler(db)
 	router := gin.New()
+	router.Use(middleware.ErrorHandler())
 	router.Use(gin.Recovery())
 	router.GET("/api/v1/transactions/export", handler.ExportTransactions)
 
@@ -189,6 +194,7 @@ func TestExportTransactionsNoData(t *testing.T) {
 
 	handler := NewExportHandler(db)
 	router := gin.New()
+	router.Use(middleware.ErrorHandler())
 	router.Use(gin.Recovery())
 	router.GET("/api/v1/transactions/export", handler.ExportTransactions)
 
@@ -206,6 +212,7 @@ func TestExportTransactionsPagination(t *testing.T) {
 
 	handler := NewExportHandler(db)
 	router := gin.New()
+	router.Use(middleware.ErrorHandler())
 	router.Use(gin.Recovery())
 	router.GET("/api/v1/transactions/export", handler.ExportTransactions)
 
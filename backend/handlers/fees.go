@@ -3,18 +3,26 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/yourusername/gpay-remit/errors"
 	"github.com/yourusername/gpay-remit/services"
+	"github.com/yourusername/gpay-remit/utils"
 )
 
+// fxRatesCacheKey holds the full rate listing; the pair filter is applied
+// after the cache lookup so every query shares one entry.
+const fxRatesCacheKey = "fx:rates"
+
 type FeeHandler struct {
-	fees *services.FeeService
+	fees  *services.FeeService
+	rates services.RateSource
 }
 
-func NewFeeHandler(fees *services.FeeService) *FeeHandler {
-	return &FeeHandler{fees: fees}
+func NewFeeHandler(fees *services.FeeService, rates services.RateSource) *FeeHandler {
+	return &FeeHandler{fees: fees, rates: rates}
 }
 
 func (h *FeeHandler) Calculate(c *gin.Context) {
@@ -30,6 +38,74 @@ func (h *FeeHandler) Calculate(c *gin.Context) {
 		return
 	}
 
-	breakdown := h.fees.Calculate(amount)
+	breakdown, err := h.fees.Calculate(amount)
+	if err != nil {
+		c.Error(feeCalculationError(err))
+		return
+	}
 	c.JSON(http.StatusOK, breakdown)
 }
+
+type EstimateTotalRequest struct {
+	Amount         float64 `json:"amount" binding:"required,gt=0"`
+	Currency       string  `json:"currency" binding:"required"`
+	TargetCurrency string  `json:"target_currency"`
+}
+
+// EstimateTotal returns the total the sender will be debited, broken down into
+// the amount, fees, and estimated network fee, plus what the recipient gets.
+func (h *FeeHandler) EstimateTotal(c *gin.Context) {
+	var req EstimateTotalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+
+	estimate, err := services.EstimateTotal(c.Request.Context(), h.fees, h.rates, req.Amount, req.Currency, req.TargetCurrency)
+	if err != nil {
+		if services.IsRateUnavailable(err) {
+			c.Error(errors.NewValidationError("Unsupported currency pair", err.Error()))
+		} else {
+			c.Error(feeCalculationError(err))
+		}
+		return
+	}
+	c.JSON(http.StatusOK, estimate)
+}
+
+// ListRates returns the supported currency pairs with their current rates.
+// An optional pair query (e.g. USD/NGN) narrows the list; an unsupported pair
+// yields an empty list.
+func (h *FeeHandler) ListRates(c *gin.Context) {
+	var quotes []services.RateQuote
+	if found, _ := utils.GetCached(fxRatesCacheKey, &quotes); found {
+		c.Header("X-Cache", "HIT")
+	} else {
+		var err error
+		quotes, err = services.ListRates(c.Request.Context(), h.rates)
+		if err != nil {
+			c.Error(errors.NewInternalError("Failed to list FX rates", err))
+			return
+		}
+		utils.SetCached(fxRatesCacheKey, quotes, 30*time.Second)
+		c.Header("X-Cache", "MISS")
+	}
+
+	if pair := strings.ToUpper(strings.TrimSpace(c.Query("pair"))); pair != "" {
+		filtered := make([]services.RateQuote, 0, 1)
+		for _, q := range quotes {
+			if q.Pair == pair {
+				filtered = append(filtered, q)
+			}
+		}
+		quotes = filtered
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rates": quotes, "count": len(quotes)})
+}
+
+// feeCalculationError maps a rejected fee calculation to a validation error;
+// the fee engine only fails on inputs it cannot price.
+func feeCalculationError(err error) *errors.AppError {
+	return errors.NewValidationError("Fee calculation rejected", err.Error())
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/services"
+)
+
+func TestListFXRates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{}
+	handler := NewFeeHandler(services.NewFeeService(cfg), services.StaticRateSource{
+		"USD/NGN": 1550,
+		"EUR/NGN": 1680,
+	})
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/fx/rates", handler.ListRates)
+
+	list := func(query string) []services.RateQuote {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/fx/rates"+query, nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp struct {
+			Rates []services.RateQuote `json:"rates"`
+			Count int                  `json:"count"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, len(resp.Rates), resp.Count)
+		return resp.Rates
+	}
+
+	t.Run("Returns seeded rates", func(t *testing.T) {
+		rates := list("")
+		require.Len(t, rates, 2)
+		assert.Equal(t, "EUR/NGN", rates[0].Pair)
+		assert.Equal(t, 1680.0, rates[0].Rate)
+		assert.Equal(t, "USD/NGN", rates[1].Pair)
+		assert.Equal(t, 1550.0, rates[1].Rate)
+		for _, q := range rates {
+			assert.Equal(t, "static", q.Source)
+			assert.False(t, q.UpdatedAt.IsZero())
+		}
+	})
+
+	t.Run("Filters by pair", func(t *testing.T) {
+		rates := list("?pair=usd/ngn")
+		require.Len(t, rates, 1)
+		assert.Equal(t, "USD/NGN", rates[0].Pair)
+	})
+
+	t.Run("Unsupported pair is absent", func(t *testing.T) {
+		assert.Empty(t, list("?pair=GBP/JPY"))
+		for _, q := range list("") {
+			assert.NotEqual(t, "GBP/JPY", q.Pair)
+		}
+	})
+}
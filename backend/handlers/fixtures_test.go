@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/utils"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// testTxHash is a well-formed transaction hash for payments that have been submitted.
+var testTxHash = strings.Repeat("ab", 32)
+
+// setupTestDB opens an in-memory database with the tables most handler
+// tests share; tests needing others migrate them themselves.
+func setupTestDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db.AutoMigrate(&models.Payment{}, &models.User{}, &models.Invoice{}, &models.InvoiceSequence{}, &models.RefreshToken{}, &models.EmailVerificationToken{})
+	return db
+}
+
+// MockStellarClient implements utils.StellarClientInterface by calling the
+// matching func field, so a test sets only the calls it expects.
+type MockStellarClient struct {
+	ValidateAccountFunc               func(accountID string) error
+	BuildEscrowTxFunc                 func(sender, recipient, assetCode, issuer, amount string, memo txnbuild.Memo) (string, error)
+	SubmitPaymentFunc                 func(sourceSecret, destination, assetCode, issuer, amount string) (string, error)
+	BuildPaymentTxFunc                func(sourceAccount txnbuild.Account, destination string, assetCode string, issuer string, amount string, memo txnbuild.Memo) (*txnbuild.Transaction, error)
+	SignTxFunc                        func(envelopeXDR string, secretKey string) (string, error)
+	AddSignatureFunc                  func(signedXDR string, secretKey string) (string, error)
+	BuildFeeBumpTxFunc                func(innerSignedXDR, feeAccount string, baseFee int64) (string, error)
+	BuildBatchPaymentTxFunc           func(sourceAccount string, payments []utils.BatchPayment) (string, error)
+	SubmitTransactionFunc             func(signedXDR string) (string, error)
+	GetTransactionStatusFunc          func(txHash string) (utils.TxStatus, error)
+	GetTransactionOutcomeFunc         func(txHash string) (utils.TxOutcome, error)
+	GetBalancesFunc                   func(accountID string) ([]utils.AccountBalance, error)
+	BuildChangeTrustTxFunc            func(account, assetCode, issuer, limit string) (string, error)
+	BuildAccountMergeTxFunc           func(source, destination string) (*utils.AccountMergeTx, error)
+	BuildCreateClaimableBalanceTxFunc func(sender, recipient, assetCode, issuer, amount string, claimAfter time.Time, memo txnbuild.Memo) (*utils.ClaimableBalanceTx, error)
+	BuildClaimClaimableBalanceTxFunc  func(balanceID, claimant string) (string, error)
+	GetBaseReserveFunc                func() (float64, error)
+	InvokeContractFunc                func(sourceAccount, contractID, function string, args []xdr.ScVal) (string, error)
+	BuildEscrowReleaseTxFunc          func(caller, contractID string, escrowID uint64, assetCode, issuer string) (string, error)
+	BuildEscrowRefundTxFunc           func(caller, contractID string, escrowID uint64, assetCode, issuer string, amount utils.Amount, reason string) (string, error)
+	QueryContractFunc                 func(contractID, function string, args ...xdr.ScVal) (xdr.ScVal, error)
+	GetPaymentHistoryFunc             func(accountID string, limit int, cursor string) (*utils.PaymentHistory, error)
+}
+
+func (m *MockStellarClient) ValidateAccount(ctx context.Context, accountID string) error {
+	return m.ValidateAccountFunc(accountID)
+}
+
+func (m *MockStellarClient) BuildEscrowTx(ctx context.Context, sender, recipient, assetCode, issuer, amount string, memo txnbuild.Memo) (string, error) {
+	return m.BuildEscrowTxFunc(sender, recipient, assetCode, issuer, amount, memo)
+}
+
+func (m *MockStellarClient) SubmitPayment(ctx context.Context, sourceSecret, destination, assetCode, issuer, amount string) (string, error) {
+	return m.SubmitPaymentFunc(sourceSecret, destination, assetCode, issuer, amount)
+}
+
+func (m *MockStellarClient) BuildPaymentTx(ctx context.Context, sourceAccount txnbuild.Account, destination string, assetCode string, issuer string, amount string, memo txnbuild.Memo) (*txnbuild.Transaction, error) {
+	return m.BuildPaymentTxFunc(sourceAccount, destination, assetCode, issuer, amount, memo)
+}
+
+func (m *MockStellarClient) SignTx(ctx context.Context, envelopeXDR string, secretKey string) (string, error) {
+	return m.SignTxFunc(envelopeXDR, secretKey)
+}
+
+func (m *MockStellarClient) AddSignature(ctx context.Context, signedXDR string, secretKey string) (string, error) {
+	return m.AddSignatureFunc(signedXDR, secretKey)
+}
+
+func (m *MockStellarClient) BuildFeeBumpTx(ctx context.Context, innerSignedXDR, feeAccount string, baseFee int64) (string, error) {
+	return m.BuildFeeBumpTxFunc(innerSignedXDR, feeAccount, baseFee)
+}
+
+func (m *MockStellarClient) BuildBatchPaymentTx(ctx context.Context, sourceAccount string, payments []utils.BatchPayment) (string, error) {
+	return m.BuildBatchPaymentTxFunc(sourceAccount, payments)
+}
+
+func (m *MockStellarClient) SubmitTransaction(ctx context.Context, signedXDR string) (string, error) {
+	return m.SubmitTransactionFunc(signedXDR)
+}
+
+func (m *MockStellarClient) GetTransactionOutcome(ctx context.Context, txHash string) (utils.TxOutcome, error) {
+	return m.GetTransactionOutcomeFunc(txHash)
+}
+
+func (m *MockStellarClient) GetTransactionStatus(ctx context.Context, txHash string) (utils.TxStatus, error) {
+	return m.GetTransactionStatusFunc(txHash)
+}
+
+func (m *MockStellarClient) GetBalances(ctx context.Context, accountID string) ([]utils.AccountBalance, error) {
+	return m.GetBalancesFunc(accountID)
+}
+
+func (m *MockStellarClient) BuildChangeTrustTx(ctx context.Context, account, assetCode, issuer, limit string) (string, error) {
+	return m.BuildChangeTrustTxFunc(account, assetCode, issuer, limit)
+}
+
+func (m *MockStellarClient) BuildAccountMergeTx(ctx context.Context, source, destination string) (*utils.AccountMergeTx, error) {
+	return m.BuildAccountMergeTxFunc(source, destination)
+}
+
+func (m *MockStellarClient) BuildCreateClaimableBalanceTx(ctx context.Context, sender, recipient, assetCode, issuer, amount string, claimAfter time.Time, memo txnbuild.Memo) (*utils.ClaimableBalanceTx, error) {
+	return m.BuildCreateClaimableBalanceTxFunc(sender, recipient, assetCode, issuer, amount, claimAfter, memo)
+}
+
+func (m *MockStellarClient) BuildClaimClaimableBalanceTx(ctx context.Context, balanceID, claimant string) (string, error) {
+	return m.BuildClaimClaimableBalanceTxFunc(balanceID, claimant)
+}
+
+func (m *MockStellarClient) GetBaseReserve(ctx context.Context) (float64, error) {
+	return m.GetBaseReserveFunc()
+}
+
+func (m *MockStellarClient) InvokeContract(ctx context.Context, sourceAccount, contractID, function string, args []xdr.ScVal) (string, error) {
+	return m.InvokeContractFunc(sourceAccount, contractID, function, args)
+}
+
+func (m *MockStellarClient) BuildEscrowReleaseTx(ctx context.Context, caller, contractID string, escrowID uint64, assetCode, issuer string) (string, error) {
+	return m.BuildEscrowReleaseTxFunc(caller, contractID, escrowID, assetCode, issuer)
+}
+
+func (m *MockStellarClient) BuildEscrowRefundTx(ctx context.Context, caller, contractID string, escrowID uint64, assetCode, issuer string, amount utils.Amount, reason string) (string, error) {
+	return m.BuildEscrowRefundTxFunc(caller, contractID, escrowID, assetCode, issuer, amount, reason)
+}
+
+func (m *MockStellarClient) QueryContract(ctx context.Context, contractID, function string, args ...xdr.ScVal) (xdr.ScVal, error) {
+	return m.QueryContractFunc(contractID, function, args...)
+}
+
+func (m *MockStellarClient) GetPaymentHistory(ctx context.Context, accountID string, limit int, cursor string) (*utils.PaymentHistory, error) {
+	return m.GetPaymentHistoryFunc(accountID, limit, cursor)
+}
+
+// newTestRouter returns a router that renders errors as the API does. When
+// userID is set, every request is authenticated as that user, and with role
+// too unless it is empty.
+func newTestRouter(userID uint, role string) *gin.Engine {
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	if userID != 0 {
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", userID)
+			if role != "" {
+				c.Set("role", role)
+			}
+			c.Next()
+		})
+	}
+	return router
+}
+
+// serveJSON sends a request to router, with body encoded as JSON unless it
+// is nil, and returns the recorded response.
+func serveJSON(router http.Handler, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	req, _ := http.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
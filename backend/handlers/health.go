@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -12,10 +13,16 @@ import (
 	"gorm.io/gorm"
 )
 
+// defaultCheckTimeout bounds a whole round of dependency checks so a hung
+// dependency cannot stall a load balancer probe.
+const defaultCheckTimeout = 2 * time.Second
+
 type HealthHandler struct {
 	DB          *gorm.DB
 	Cfg         *config.Config
 	RedisClient *redis.Client
+	// CheckTimeout overrides defaultCheckTimeout when positive.
+	CheckTimeout time.Duration
 }
 
 func NewHealthHandler(db *gorm.DB, cfg *config.Config) *HealthHandler {
@@ -47,17 +54,15 @@ type databaseStatus struct {
 }
 
 type healthResponse struct {
-	Status       string                      `json:"status"`
-	Service      string                      `json:"service"`
-	Timestamp    string                      `json:"timestamp"`
-	Dependencies map[string]interface{}      `json:"dependencies,omitempty"`
+	Status       string                 `json:"status"`
+	Service      string                 `json:"service"`
+	Timestamp    string                 `json:"timestamp"`
+	Dependencies map[string]interface{} `json:"dependencies,omitempty"`
 }
 
 // Health returns detailed health status including all dependencies.
 func (h *HealthHandler) Health(c *gin.Context) {
-	dbStatus := h.checkDatabase()
-	horizonStatus := h.checkHorizon()
-	redisStatus := h.checkRedis()
+	dbStatus, horizonStatus, redisStatus := h.checkAll(c.Request.Context())
 
 	overall := "healthy"
 	httpStatus := http.StatusOK
@@ -78,22 +83,54 @@ func (h *HealthHandler) Health(c *gin.Context) {
 	})
 }
 
-// Ready checks database, Horizon, and Redis — used for Kubernetes readiness probes.
+// Ready checks database, Horizon, and Redis — used for Kubernetes readiness
+// probes. Redis is optional: an unconfigured cache does not block readiness.
+// Per-dependency status is returned with both 200 and 503.
 func (h *HealthHandler) Ready(c *gin.Context) {
-	dbStatus := h.checkDatabase()
-	horizonStatus := h.checkHorizon()
-	redisStatus := h.checkRedis()
+	dbStatus, horizonStatus, redisStatus := h.checkAll(c.Request.Context())
 
-	if dbStatus.Status != "healthy" || horizonStatus.Status != "healthy" || redisStatus.Status != "healthy" {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"status":   "not_ready",
+	status := "ready"
+	httpStatus := http.StatusOK
+	if dbStatus.Status != "healthy" || horizonStatus.Status != "healthy" ||
+		(redisStatus.Status != "healthy" && redisStatus.Status != "unconfigured") {
+		status = "not_ready"
+		httpStatus = http.StatusServiceUnavailable
+	}
+
+	c.JSON(httpStatus, healthResponse{
+		Status:    status,
+		Service:   "gpay-remit-api",
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Dependencies: map[string]interface{}{
 			"database": dbStatus,
 			"horizon":  horizonStatus,
 			"redis":    redisStatus,
-		})
-		return
+		},
+	})
+}
+
+// checkAll runs every dependency check concurrently under one deadline.
+func (h *HealthHandler) checkAll(parent context.Context) (databaseStatus, dependencyStatus, dependencyStatus) {
+	timeout := h.CheckTimeout
+	if timeout <= 0 {
+		timeout = defaultCheckTimeout
 	}
-	c.JSON(http.StatusOK, gin.H{"status": "ready"})
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	var (
+		wg            sync.WaitGroup
+		dbStatus      databaseStatus
+		horizonStatus dependencyStatus
+		redisStatus   dependencyStatus
+	)
+	wg.Add(3)
+	go func() { defer wg.Done(); dbStatus = h.checkDatabase(ctx) }()
+	go func() { defer wg.Done(); horizonStatus = h.checkHorizon(ctx) }()
+	go func() { defer wg.Done(); redisStatus = h.checkRedis(ctx) }()
+	wg.Wait()
+
+	return dbStatus, horizonStatus, redisStatus
 }
 
 // Live checks only critical in-process state — used for Kubernetes liveness probes.
@@ -104,7 +141,7 @@ func (h *HealthHandler) Live(c *gin.Context) {
 	})
 }
 
-func (h *HealthHandler) checkDatabase() databaseStatus {
+func (h *HealthHandler) checkDatabase(ctx context.Context) databaseStatus {
 	if h.DB == nil {
 		return databaseStatus{dependencyStatus: dependencyStatus{Status: "unhealthy", Error: "database not configured"}}
 	}
@@ -113,8 +150,6 @@ func (h *HealthHandler) checkDatabase() databaseStatus {
 	if err != nil {
 		return databaseStatus{dependencyStatus: dependencyStatus{Status: "unhealthy", Error: err.Error()}}
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
 	if err := sqlDB.PingContext(ctx); err != nil {
 		return databaseStatus{dependencyStatus: dependencyStatus{Status: "unhealthy", Error: err.Error()}}
 	}
@@ -137,14 +172,11 @@ func collectPoolMetrics(sqlDB *sql.DB) *dbPoolMetrics {
 	}
 }
 
-func (h *HealthHandler) checkHorizon() dependencyStatus {
+func (h *HealthHandler) checkHorizon(ctx context.Context) dependencyStatus {
 	if h.Cfg == nil || h.Cfg.HorizonURL == "" {
 		return dependencyStatus{Status: "unhealthy", Error: "horizon URL not configured"}
 	}
 	start := time.Now()
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
-
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.Cfg.HorizonURL, nil)
 	if err != nil {
 		return dependencyStatus{Status: "unhealthy", Error: err.Error()}
@@ -161,14 +193,11 @@ func (h *HealthHandler) checkHorizon() dependencyStatus {
 	return dependencyStatus{Status: "healthy", Latency: time.Since(start).String()}
 }
 
-func (h *HealthHandler) checkRedis() dependencyStatus {
+func (h *HealthHandler) checkRedis(ctx context.Context) dependencyStatus {
 	if h.RedisClient == nil {
 		return dependencyStatus{Status: "unconfigured", Error: "redis client not initialized"}
 	}
 	start := time.Now()
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
-
 	if err := h.RedisClient.Ping(ctx).Err(); err != nil {
 		return dependencyStatus{Status: "unhealthy", Error: err.Error()}
 	}
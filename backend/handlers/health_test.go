@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -88,3 +89,97 @@ func TestHealthResponseFields(t *testing.T) {
 	assert.NotEmpty(t, resp["timestamp"])
 	assert.NotNil(t, resp["dependencies"])
 }
+
+func newReadyRouter(handler *HealthHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/health/ready", handler.Ready)
+	return router
+}
+
+func getReady(router *gin.Engine) (int, healthResponse) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/health/ready", nil)
+	router.ServeHTTP(w, req)
+
+	var resp healthResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	return w.Code, resp
+}
+
+func dependency(resp healthResponse, name string) map[string]interface{} {
+	dep, _ := resp.Dependencies[name].(map[string]interface{})
+	return dep
+}
+
+func TestHealthReadyAllHealthy(t *testing.T) {
+	horizon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer horizon.Close()
+
+	handler := NewHealthHandler(setupTestDB(), &config.Config{HorizonURL: horizon.URL})
+	code, resp := getReady(newReadyRouter(handler))
+
+	// Redis is not configured, which must not block readiness.
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ready", resp.Status)
+	assert.Equal(t, "healthy", dependency(resp, "database")["status"])
+	assert.Equal(t, "healthy", dependency(resp, "horizon")["status"])
+	assert.Equal(t, "unconfigured", dependency(resp, "redis")["status"])
+}
+
+func TestHealthReadyClosedDB(t *testing.T) {
+	horizon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer horizon.Close()
+
+	db := setupTestDB()
+	sqlDB, _ := db.DB()
+	sqlDB.Close()
+
+	handler := NewHealthHandler(db, &config.Config{HorizonURL: horizon.URL})
+	code, resp := getReady(newReadyRouter(handler))
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "not_ready", resp.Status)
+	assert.Equal(t, "unhealthy", dependency(resp, "database")["status"])
+	assert.NotEmpty(t, dependency(resp, "database")["error"])
+	assert.Equal(t, "healthy", dependency(resp, "horizon")["status"])
+}
+
+func TestHealthReadyHorizonFailing(t *testing.T) {
+	horizon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer horizon.Close()
+
+	handler := NewHealthHandler(setupTestDB(), &config.Config{HorizonURL: horizon.URL})
+	code, resp := getReady(newReadyRouter(handler))
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "healthy", dependency(resp, "database")["status"])
+	assert.Equal(t, "unhealthy", dependency(resp, "horizon")["status"])
+	assert.Contains(t, dependency(resp, "horizon")["error"], "502")
+}
+
+func TestHealthReadyHorizonHangs(t *testing.T) {
+	horizon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer horizon.Close()
+
+	handler := NewHealthHandler(setupTestDB(), &config.Config{HorizonURL: horizon.URL})
+	handler.CheckTimeout = 100 * time.Millisecond
+
+	start := time.Now()
+	code, resp := getReady(newReadyRouter(handler))
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unhealthy", dependency(resp, "horizon")["status"])
+}
@@ -0,0 +1,531 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/services"
+	"github.com/yourusername/gpay-remit/utils"
+)
+
+func TestGetInvoicePDF(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	handler := &RemittanceHandler{db: db, config: &config.Config{}}
+
+	issuer := models.User{Email: "issuer@example.com", Name: "Issuer Co", StellarAddress: "GISSUER", PasswordHash: "x"}
+	db.Create(&issuer)
+
+	due := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	withDue := models.Invoice{PaymentID: 1, InvoiceNo: "INV-PDF-1", IssuerID: issuer.ID, RecipientID: 99, Amount: 1234.5, Currency: "EUR", DueDate: &due, Status: "unpaid", Description: "Consulting services"}
+	noDue := models.Invoice{PaymentID: 2, InvoiceNo: "INV-PDF-2", IssuerID: issuer.ID, RecipientID: 99, Amount: 10, Currency: "NGN", Status: "unpaid"}
+	db.Create(&withDue)
+	db.Create(&noDue)
+
+	router := newTestRouter(issuer.ID, "")
+	router.GET("/invoices/:id/pdf", handler.GetInvoicePDF)
+
+	for _, invoice := range []models.Invoice{withDue, noDue} {
+		w := serveJSON(router, http.MethodGet, fmt.Sprintf("/invoices/%d/pdf", invoice.ID), nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "invoice_"+invoice.InvoiceNo+".pdf")
+		assert.True(t, bytes.HasPrefix(w.Body.Bytes(), []byte("%PDF")))
+	}
+
+	t.Run("Only the invoice parties or an admin", func(t *testing.T) {
+		path := fmt.Sprintf("/invoices/%d/pdf", withDue.ID)
+		for _, tc := range []struct {
+			name   string
+			userID uint
+			role   string
+			want   int
+		}{
+			{"recipient", 99, "", http.StatusOK},
+			{"admin", 500, "admin", http.StatusOK},
+			{"stranger", 500, "", http.StatusForbidden},
+		} {
+			r := newTestRouter(tc.userID, tc.role)
+			r.GET("/invoices/:id/pdf", handler.GetInvoicePDF)
+			w := serveJSON(r, http.MethodGet, path, nil)
+			assert.Equal(t, tc.want, w.Code, tc.name)
+		}
+	})
+
+	t.Run("Unknown invoice", func(t *testing.T) {
+		w := serveJSON(router, http.MethodGet, "/invoices/999/pdf", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestGetInvoice(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	handler := &RemittanceHandler{db: db, config: &config.Config{}}
+
+	invoice := models.Invoice{PaymentID: 1, InvoiceNo: "INV-GET-1", IssuerID: 1, RecipientID: 2, Amount: 10, Currency: "USD", Status: "unpaid"}
+	db.Create(&invoice)
+	path := fmt.Sprintf("/invoices/%d", invoice.ID)
+
+	for _, tc := range []struct {
+		name   string
+		userID uint
+		role   string
+		want   int
+	}{
+		{"issuer", 1, "", http.StatusOK},
+		{"recipient", 2, "", http.StatusOK},
+		{"admin", 3, "admin", http.StatusOK},
+		{"stranger", 3, "", http.StatusForbidden},
+	} {
+		router := newTestRouter(tc.userID, tc.role)
+		router.GET("/invoices/:id", handler.GetInvoice)
+		w := serveJSON(router, http.MethodGet, path, nil)
+		assert.Equal(t, tc.want, w.Code, tc.name)
+	}
+}
+
+func TestVerifyInvoicePDF(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	handler := &RemittanceHandler{db: db, config: &config.Config{}}
+
+	issuer := models.User{Email: "issuer@example.com", Name: "Issuer Co", PasswordHash: "x"}
+	db.Create(&issuer)
+
+	router := newTestRouter(issuer.ID, "")
+	router.GET("/invoices/:id/pdf", handler.GetInvoicePDF)
+	router.GET("/invoices/:id/verify", handler.VerifyInvoicePDF)
+
+	request := func(path string) *httptest.ResponseRecorder {
+		return serveJSON(router, http.MethodGet, path, nil)
+	}
+	verify := func(id uint) (int, map[string]interface{}) {
+		w := request(fmt.Sprintf("/invoices/%d/verify", id))
+		var body map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		return w.Code, body
+	}
+	newInvoice := func(no string) models.Invoice {
+		invoice := models.Invoice{PaymentID: 1, InvoiceNo: no, IssuerID: issuer.ID, RecipientID: 99, Amount: 100, Currency: "USD", Status: "unpaid", Description: "Consulting"}
+		if err := services.IssueInvoice(db, &invoice); err != nil {
+			t.Fatal(err)
+		}
+		db.Create(&invoice)
+		return invoice
+	}
+
+	t.Run("Untampered invoice verifies", func(t *testing.T) {
+		invoice := newInvoice("INV-VERIFY-1")
+		assert.Equal(t, "Issuer Co", invoice.IssuerName)
+		assert.Equal(t, "User #99", invoice.RecipientName)
+
+		w := request(fmt.Sprintf("/invoices/%d/pdf", invoice.ID))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, invoice.PdfHash, utils.InvoicePDFHash(w.Body.Bytes()), "the download is the document issued")
+
+		var stored models.Invoice
+		db.First(&stored, invoice.ID)
+		assert.Equal(t, invoice.PdfHash, stored.PdfHash)
+		assert.True(t, invoice.UpdatedAt.Equal(stored.UpdatedAt), "downloading does not write")
+
+		code, body := verify(invoice.ID)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, true, body["verified"])
+		assert.Equal(t, stored.PdfHash, body["computed_hash"])
+	})
+
+	t.Run("Status and profile changes keep verifying", func(t *testing.T) {
+		invoice := newInvoice("INV-VERIFY-5")
+		db.Model(&models.Invoice{}).Where("id = ?", invoice.ID).Update("status", "paid")
+		db.Model(&issuer).Updates(map[string]interface{}{"name": "Renamed Co", "email": "renamed@example.com"})
+		defer db.Model(&issuer).Updates(map[string]interface{}{"name": "Issuer Co", "email": "issuer@example.com"})
+
+		code, body := verify(invoice.ID)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, true, body["verified"])
+	})
+
+	t.Run("Mutated stored invoice fails verification", func(t *testing.T) {
+		invoice := newInvoice("INV-VERIFY-2")
+		db.Model(&models.Invoice{}).Where("id = ?", invoice.ID).UpdateColumn("amount", 1000)
+
+		code, body := verify(invoice.ID)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, false, body["verified"])
+		assert.NotEqual(t, body["stored_hash"], body["computed_hash"])
+	})
+
+	t.Run("Invoice without a recorded hash", func(t *testing.T) {
+		invoice := models.Invoice{PaymentID: 1, InvoiceNo: "INV-VERIFY-3", IssuerID: issuer.ID, RecipientID: 99, Amount: 100, Currency: "USD", Status: "unpaid"}
+		db.Create(&invoice)
+		code, _ := verify(invoice.ID)
+		assert.Equal(t, http.StatusConflict, code)
+	})
+
+	t.Run("Strangers cannot verify", func(t *testing.T) {
+		invoice := newInvoice("INV-VERIFY-4")
+
+		stranger := newTestRouter(500, "")
+		stranger.GET("/invoices/:id/verify", handler.VerifyInvoicePDF)
+		w := serveJSON(stranger, http.MethodGet, fmt.Sprintf("/invoices/%d/verify", invoice.ID), nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Unknown invoice", func(t *testing.T) {
+		code, _ := verify(999)
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+}
+
+func TestPayInvoice(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	issuerKP, _ := keypair.Random()
+	payerKP, _ := keypair.Random()
+	issuer := models.User{Email: "issuer@example.com", Name: "Issuer", StellarAddress: issuerKP.Address(), PasswordHash: "x"}
+	payer := models.User{Email: "payer@example.com", Name: "Payer", StellarAddress: payerKP.Address(), PasswordHash: "x"}
+	db.Create(&issuer)
+	db.Create(&payer)
+
+	var buildErr error
+	var escrowDestination string
+	cfg := &config.Config{}
+	handler := &RemittanceHandler{
+		db:       db,
+		config:   cfg,
+		fees:     services.NewFeeService(cfg),
+		invoices: services.NewInvoiceService(db),
+		stellarClient: &MockStellarClient{
+			ValidateAccountFunc: func(accountID string) error { return nil },
+			BuildEscrowTxFunc: func(sender, recipient, assetCode, issuer, amount string, memo txnbuild.Memo) (string, error) {
+				escrowDestination = recipient
+				if buildErr != nil {
+					return "", buildErr
+				}
+				return "invoice_xdr", nil
+			},
+		},
+	}
+
+	router := newTestRouter(payer.ID, "")
+	router.POST("/invoices/:id/pay", handler.PayInvoice)
+	issuerRouter := newTestRouter(issuer.ID, "")
+	issuerRouter.POST("/invoices/:id/void", handler.VoidInvoice)
+
+	pay := func(id uint) *httptest.ResponseRecorder {
+		return serveJSON(router, http.MethodPost, fmt.Sprintf("/invoices/%d/pay", id), PayInvoiceRequest{SenderAccount: payerKP.Address()})
+	}
+	linkedPayment := func(id uint) (models.Invoice, models.Payment) {
+		var stored models.Invoice
+		db.First(&stored, id)
+		var payment models.Payment
+		db.First(&payment, stored.PaymentID)
+		return stored, payment
+	}
+
+	invoice := models.Invoice{InvoiceNo: "INV-PAY-1", IssuerID: issuer.ID, RecipientID: payer.ID, Amount: 75, Currency: "XLM", Status: "unpaid"}
+	db.Create(&invoice)
+
+	t.Run("Failure while building the envelope rolls everything back", func(t *testing.T) {
+		buildErr = assert.AnError
+		defer func() { buildErr = nil }()
+
+		w := pay(invoice.ID)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var stored models.Invoice
+		db.First(&stored, invoice.ID)
+		assert.Equal(t, "unpaid", stored.Status)
+		assert.Equal(t, uint(0), stored.PaymentID)
+
+		var payments int64
+		db.Model(&models.Payment{}).Count(&payments)
+		assert.Equal(t, int64(0), payments)
+	})
+
+	t.Run("Remittance checks apply", func(t *testing.T) {
+		cfg.MaxAmounts = map[string]float64{"XLM": 50}
+		defer func() { cfg.MaxAmounts = nil }()
+
+		w := pay(invoice.ID)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "out of range")
+	})
+
+	t.Run("Links the invoice and leaves it open until the remittance completes", func(t *testing.T) {
+		w := pay(invoice.ID)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, issuerKP.Address(), escrowDestination)
+
+		stored, payment := linkedPayment(invoice.ID)
+		assert.Equal(t, "unpaid", stored.Status)
+		assert.Nil(t, stored.PaidAt)
+		assert.Equal(t, payer.ID, payment.SenderID)
+		assert.Equal(t, issuer.ID, payment.RecipientID)
+		assert.Equal(t, 75.0, payment.Amount)
+		assert.Equal(t, "pending", payment.Status)
+		assert.Equal(t, "invoice_xdr", payment.TxEnvelope)
+	})
+
+	t.Run("Invoice with a remittance in flight cannot be paid again", func(t *testing.T) {
+		w := pay(invoice.ID)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Invoice can be paid again after its remittance fails", func(t *testing.T) {
+		_, failed := linkedPayment(invoice.ID)
+		db.Model(&failed).Update("status", "failed")
+
+		w := pay(invoice.ID)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		stored, payment := linkedPayment(invoice.ID)
+		assert.NotEqual(t, failed.ID, payment.ID)
+
+		// Completion marks the invoice paid, as for any linked payment.
+		payment.Status = "completed"
+		db.Model(&payment).Update("status", "completed")
+		paid, err := services.NewInvoiceService(db).MarkPaidForPayment(&payment)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, paid)
+		db.First(&stored, invoice.ID)
+		assert.Equal(t, "paid", stored.Status)
+
+		assert.Equal(t, http.StatusConflict, pay(invoice.ID).Code)
+	})
+
+	t.Run("Invoice whose remittance failed can be voided", func(t *testing.T) {
+		voidable := models.Invoice{InvoiceNo: "INV-PAY-3", IssuerID: issuer.ID, RecipientID: payer.ID, Amount: 20, Currency: "XLM", Status: "unpaid"}
+		db.Create(&voidable)
+		assert.Equal(t, http.StatusCreated, pay(voidable.ID).Code)
+
+		_, payment := linkedPayment(voidable.ID)
+		db.Model(&payment).Update("status", "failed")
+
+		w := serveJSON(issuerRouter, http.MethodPost, fmt.Sprintf("/invoices/%d/void", voidable.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var stored models.Invoice
+		db.First(&stored, voidable.ID)
+		assert.Equal(t, "cancelled", stored.Status)
+	})
+
+	t.Run("Only the invoiced user may pay", func(t *testing.T) {
+		other := models.Invoice{InvoiceNo: "INV-PAY-2", IssuerID: issuer.ID, RecipientID: issuer.ID, Amount: 5, Currency: "XLM", Status: "unpaid"}
+		db.Create(&other)
+
+		w := pay(other.ID)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestCreateBatchInvoices(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	handler := &RemittanceHandler{db: db, config: &config.Config{}, invoices: services.NewInvoiceService(db)}
+
+	for i := 0; i < 3; i++ {
+		db.Create(&models.Payment{SenderID: 1, RecipientID: uint(10 + i), Amount: 25, Currency: "USD", Status: "completed", BatchID: "batch-ok"})
+	}
+	db.Create(&models.Payment{SenderID: 1, RecipientID: 20, Amount: 25, Currency: "USD", Status: "completed", BatchID: "batch-mixed"})
+	db.Create(&models.Payment{SenderID: 1, RecipientID: 21, Amount: 25, Currency: "USD", Status: "failed", BatchID: "batch-mixed"})
+
+	post := func(userID uint, role, body string) (int, map[string]interface{}) {
+		router := newTestRouter(userID, role)
+		router.POST("/invoices/batch", handler.CreateBatchInvoices)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/invoices/batch", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+
+	t.Run("Invoices every payment in a batch", func(t *testing.T) {
+		code, resp := post(1, "user", `{"batch_id":"batch-ok"}`)
+		assert.Equal(t, http.StatusCreated, code)
+		assert.Equal(t, []interface{}{"INV-00000001", "INV-00000002", "INV-00000003"}, resp["invoice_numbers"])
+		assert.Empty(t, resp["skipped_payment_ids"])
+
+		var invoices []models.Invoice
+		db.Where("payment_id IN (?)", db.Model(&models.Payment{}).Select("id").Where("batch_id = ?", "batch-ok")).Find(&invoices)
+		assert.Len(t, invoices, 3)
+		for _, invoice := range invoices {
+			assert.Equal(t, "Payout from batch batch-ok", invoice.Description)
+		}
+	})
+
+	t.Run("Repeating the request skips invoiced payments", func(t *testing.T) {
+		code, resp := post(1, "user", `{"batch_id":"batch-ok"}`)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Empty(t, resp["invoice_numbers"])
+		assert.Len(t, resp["skipped_payment_ids"], 3)
+	})
+
+	t.Run("Refused", func(t *testing.T) {
+		code, _ := post(1, "user", `{}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		code, _ = post(1, "user", `{"batch_id":"batch-ok","payment_ids":[1]}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		code, _ = post(2, "user", `{"payment_ids":[1,2]}`)
+		assert.Equal(t, http.StatusForbidden, code)
+		code, _ = post(1, "user", `{"payment_ids":[1,999]}`)
+		assert.Equal(t, http.StatusNotFound, code)
+		code, _ = post(1, "user", `{"batch_id":"missing"}`)
+		assert.Equal(t, http.StatusNotFound, code)
+		code, _ = post(9, "admin", `{"batch_id":"batch-mixed"}`)
+		assert.Equal(t, http.StatusConflict, code)
+
+		var count int64
+		db.Model(&models.Invoice{}).Count(&count)
+		assert.Equal(t, int64(3), count)
+	})
+}
+
+func TestCancelInvoice(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	handler := &RemittanceHandler{db: db, config: &config.Config{}, invoices: services.NewInvoiceService(db)}
+
+	newRouter := func(userID uint, role string) *gin.Engine {
+		router := newTestRouter(userID, role)
+		router.POST("/invoices/:id/cancel", handler.CancelInvoice)
+		return router
+	}
+
+	cancel := func(router *gin.Engine, id uint, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/invoices/%d/cancel", id), strings.NewReader(body))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Issuer cancels an unpaid invoice", func(t *testing.T) {
+		invoice := models.Invoice{InvoiceNo: "INV-CNL-1", IssuerID: 1, RecipientID: 2, Amount: 40, Currency: "USD", Status: "unpaid"}
+		db.Create(&invoice)
+
+		w := cancel(newRouter(1, "user"), invoice.ID, `{"reason":"issued in error"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			Invoice models.Invoice `json:"invoice"`
+			Warning string         `json:"warning"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "cancelled", resp.Invoice.Status)
+		assert.Empty(t, resp.Warning)
+
+		var stored models.Invoice
+		db.First(&stored, invoice.ID)
+		assert.Equal(t, "cancelled", stored.Status)
+		assert.Equal(t, "issued in error", stored.CancellationReason)
+
+		w = cancel(newRouter(1, "user"), invoice.ID, "")
+		assert.Equal(t, http.StatusConflict, w.Code, "a cancelled invoice stays cancelled")
+	})
+
+	t.Run("Pending linked payment is warned about but left pending", func(t *testing.T) {
+		payment := models.Payment{SenderID: 2, RecipientID: 1, Amount: 40, Currency: "USD", Status: "pending"}
+		db.Create(&payment)
+		invoice := models.Invoice{PaymentID: payment.ID, InvoiceNo: "INV-CNL-2", IssuerID: 1, RecipientID: 2, Amount: 40, Currency: "USD", Status: "unpaid"}
+		db.Create(&invoice)
+
+		w := cancel(newRouter(9, "admin"), invoice.ID, "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "still pending")
+
+		var stored models.Payment
+		db.First(&stored, payment.ID)
+		assert.Equal(t, "pending", stored.Status)
+	})
+
+	t.Run("Paid invoice cannot be cancelled", func(t *testing.T) {
+		invoice := models.Invoice{InvoiceNo: "INV-CNL-3", IssuerID: 1, RecipientID: 2, Amount: 40, Currency: "USD", Status: "paid"}
+		db.Create(&invoice)
+
+		w := cancel(newRouter(1, "user"), invoice.ID, "")
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		var stored models.Invoice
+		db.First(&stored, invoice.ID)
+		assert.Equal(t, "paid", stored.Status)
+	})
+
+	t.Run("Other users are forbidden", func(t *testing.T) {
+		invoice := models.Invoice{InvoiceNo: "INV-CNL-4", IssuerID: 1, RecipientID: 2, Amount: 40, Currency: "USD", Status: "unpaid"}
+		db.Create(&invoice)
+
+		w := cancel(newRouter(2, "user"), invoice.ID, "")
+		assert.Equal(t, http.StatusForbidden, w.Code, "the invoiced user is not the issuer")
+
+		var stored models.Invoice
+		db.First(&stored, invoice.ID)
+		assert.Equal(t, "unpaid", stored.Status)
+	})
+
+	t.Run("Unknown invoice", func(t *testing.T) {
+		w := cancel(newRouter(1, "user"), 99999, "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestVoidInvoice(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	handler := &RemittanceHandler{db: db, config: &config.Config{}, invoices: services.NewInvoiceService(db)}
+
+	void := func(userID uint, role string, id uint) *httptest.ResponseRecorder {
+		router := newTestRouter(userID, role)
+		router.POST("/invoices/:id/void", handler.VoidInvoice)
+		return serveJSON(router, http.MethodPost, fmt.Sprintf("/invoices/%d/void", id), nil)
+	}
+	newInvoice := func(no string) models.Invoice {
+		payment := models.Payment{SenderID: 2, RecipientID: 1, Amount: 40, Currency: "USD", Status: "failed"}
+		db.Create(&payment)
+		invoice := models.Invoice{PaymentID: payment.ID, InvoiceNo: no, IssuerID: 1, RecipientID: 2, Amount: 40, Currency: "USD", Status: "unpaid"}
+		db.Create(&invoice)
+		return invoice
+	}
+
+	t.Run("Other users are forbidden", func(t *testing.T) {
+		invoice := newInvoice("INV-VOID-1")
+
+		w := void(2, "user", invoice.ID)
+		assert.Equal(t, http.StatusForbidden, w.Code, "the invoiced user is not the issuer")
+
+		var stored models.Invoice
+		db.First(&stored, invoice.ID)
+		assert.Equal(t, "unpaid", stored.Status)
+	})
+
+	t.Run("Issuer and admin can void", func(t *testing.T) {
+		for _, tc := range []struct {
+			userID uint
+			role   string
+		}{{1, "user"}, {9, "admin"}} {
+			invoice := newInvoice(fmt.Sprintf("INV-VOID-%d", tc.userID+10))
+
+			w := void(tc.userID, tc.role, invoice.ID)
+			assert.Equal(t, http.StatusOK, w.Code, tc.role)
+
+			var stored models.Invoice
+			db.First(&stored, invoice.ID)
+			assert.Equal(t, "cancelled", stored.Status, tc.role)
+		}
+	})
+}
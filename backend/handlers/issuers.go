@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/utils"
+)
+
+func issuerPolicy(cfg *config.Config) utils.IssuerPolicy {
+	return utils.IssuerPolicy{Allowed: cfg.AssetIssuerAllowlist, Denied: cfg.AssetIssuerDenylist}
+}
+
+// requireAllowedIssuer rejects a credit asset whose issuer the configured
+// issuer policy refuses, reporting on the context. The asset must already
+// have passed utils.ValidateAsset.
+func requireAllowedIssuer(c *gin.Context, cfg *config.Config, code, issuer string) bool {
+	return report(c, issuerError(cfg, code, issuer))
+}
+
+// issuerError is the error requireAllowedIssuer reports, or nil when the
+// issuer is accepted.
+func issuerError(cfg *config.Config, code, issuer string) *errors.AppError {
+	if issuerPolicy(cfg).Permits(code, issuer) {
+		return nil
+	}
+	return errors.NewIssuerNotAllowedError(
+		fmt.Sprintf("Issuer %s is not accepted for %s", issuer, code),
+		map[string]string{"asset_code": code, "asset_issuer": issuer},
+	)
+}
+
+// GetIssuerPolicy reports the issuer allowlist and denylist applied to credit
+// assets (admin only).
+func (h *RemittanceHandler) GetIssuerPolicy(c *gin.Context) {
+	policy := issuerPolicy(h.config)
+	if policy.Allowed == nil {
+		policy.Allowed = []string{}
+	}
+	if policy.Denied == nil {
+		policy.Denied = []string{}
+	}
+	c.JSON(http.StatusOK, policy)
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stellar/go/keypair"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/utils"
+)
+
+// ownershipChallengeTTL is how long an ownership challenge can be signed and
+// used.
+const ownershipChallengeTTL = 5 * time.Minute
+
+type OwnershipChallengeRequest struct {
+	StellarAddress string `json:"stellar_address" binding:"required"`
+}
+
+// OwnershipChallenge issues a challenge transaction for a Stellar address.
+// Signing it with the address's key and sending it back as signed_challenge
+// proves ownership, which registering an address that already has
+// remittances waiting for it requires.
+func (h *AuthHandler) OwnershipChallenge(c *gin.Context) {
+	var req OwnershipChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+	if _, err := keypair.ParseAddress(req.StellarAddress); err != nil {
+		c.Error(errors.NewValidationError("Invalid Stellar address", err.Error()))
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(ownershipChallengeTTL)
+	challenge, err := utils.BuildOwnershipChallenge(h.Cfg.JWTSecret, req.StellarAddress, now, expiresAt)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to build challenge", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"challenge_xdr":      challenge,
+		"network_passphrase": h.Cfg.NetworkPassphrase,
+		"expires_at":         expiresAt.UTC().Truncate(time.Second),
+	})
+}
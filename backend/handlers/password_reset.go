@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/utils"
+	"gorm.io/gorm"
+)
+
+// passwordResetPurpose separates password reset tokens from other tokens
+// signed with the same secret.
+const passwordResetPurpose = "password-reset"
+
+// passwordResetRequested is the ForgotPassword response, the same whether or
+// not the email belongs to an account so it cannot be used to find accounts.
+const passwordResetRequested = "If an account exists for that email, a password reset link has been sent"
+
+func invalidResetTokenError() *errors.AppError {
+	return errors.NewValidationError("Invalid or expired reset token", nil)
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// ForgotPassword emails a password reset link to the account with the given
+// email. Issuing a token discards the user's earlier unused ones.
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+
+	var user models.User
+	err := h.DB.Where("email = ? AND is_active = ? AND unregistered = ?", req.Email, true, false).First(&user).Error
+	if err == gorm.ErrRecordNotFound {
+		c.JSON(http.StatusAccepted, gin.H{"message": passwordResetRequested})
+		return
+	}
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to look up user", err))
+		return
+	}
+
+	token, err := utils.NewSignedToken(h.Cfg.JWTSecret, passwordResetPurpose)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to generate reset token", err))
+		return
+	}
+	resetURL, err := linkWithToken(h.Cfg.PasswordResetURL, token)
+	if err != nil {
+		c.Error(errors.NewInternalError("Invalid password reset URL", err))
+		return
+	}
+
+	record := models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: models.HashPasswordResetToken(token),
+		ExpiresAt: time.Now().Add(h.Cfg.PasswordResetTTL),
+	}
+	err = h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND used_at IS NULL", user.ID).Delete(&models.PasswordResetToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&record).Error
+	})
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to store reset token", err))
+		return
+	}
+
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id":  user.ID,
+		"endpoint": "/auth/password/forgot",
+	})
+	if err := h.Mailer.SendPasswordResetEmail(&user, resetURL, record.ExpiresAt); err != nil {
+		// Failing the request would tell the caller the account exists.
+		log.WithError(err).Error("Failed to send password reset email")
+	} else {
+		log.Info("Password reset requested")
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": passwordResetRequested})
+}
+
+// ResetPassword sets a new password with a token from ForgotPassword. Each
+// token works once, and resetting the password signs the user out of every
+// session by revoking their refresh tokens and lifts any login lockout.
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+
+	if !utils.VerifySignedToken(h.Cfg.JWTSecret, passwordResetPurpose, req.Token) {
+		c.Error(invalidResetTokenError())
+		return
+	}
+
+	// Checked before the token is spent, so a rejected password can be retried.
+	hash, err := models.HashPasswordWithCost(req.Password, h.bcryptCost())
+	if err != nil {
+		c.Error(errors.NewValidationError("Invalid password", err.Error()))
+		return
+	}
+
+	var record models.PasswordResetToken
+	err = h.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", models.HashPasswordResetToken(req.Token), now).
+			First(&record).Error; err != nil {
+			return err
+		}
+		// Only one of two concurrent resets with the same token gets here.
+		result := tx.Model(&models.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL", record.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Model(&models.User{}).Where("id = ?", record.UserID).Updates(map[string]interface{}{
+			"password_hash":         hash,
+			"failed_login_attempts": 0,
+			"locked_until":          nil,
+		}).Error; err != nil {
+			return err
+		}
+		_, err := h.revokeRefreshTokens(tx, record.UserID)
+		return err
+	})
+	if err == gorm.ErrRecordNotFound {
+		c.Error(invalidResetTokenError())
+		return
+	}
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to reset password", err))
+		return
+	}
+
+	logger.Log.WithFields(logrus.Fields{
+		"user_id":  record.UserID,
+		"endpoint": "/auth/password/reset",
+	}).Info("Password reset")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password has been reset"})
+}
+
+// linkWithToken returns base with token added as its "token" query parameter.
+func linkWithToken(base, token string) (string, error) {
+	link, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String(), nil
+}
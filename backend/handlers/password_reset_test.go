@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/models"
+)
+
+type recordingAccountMailer struct {
+	resets        []string
+	verifications []string
+}
+
+func (m *recordingAccountMailer) SendPasswordResetEmail(user *models.User, resetURL string, expiresAt time.Time) error {
+	m.resets = append(m.resets, resetURL)
+	return nil
+}
+
+func (m *recordingAccountMailer) SendVerificationEmail(user *models.User, verifyURL string, expiresAt time.Time) error {
+	m.verifications = append(m.verifications, verifyURL)
+	return nil
+}
+
+func TestPasswordReset(t *testing.T) {
+	handler, router := setupAuthHandler(t)
+	require.NoError(t, handler.DB.AutoMigrate(&models.PasswordResetToken{}))
+	handler.Cfg.PasswordResetTTL = 30 * time.Minute
+	handler.Cfg.PasswordResetURL = "https://app.example.com/reset-password"
+	mailer := &recordingAccountMailer{}
+	handler.Mailer = mailer
+	router.POST("/auth/password/forgot", handler.ForgotPassword)
+	router.POST("/auth/password/reset", handler.ResetPassword)
+
+	hash, _ := models.HashPassword("Secure@Old1")
+	user := models.User{Email: "reset@example.com", Name: "Reset", PasswordHash: hash, StellarAddress: "GRESET", IsActive: true}
+	require.NoError(t, handler.DB.Create(&user).Error)
+	require.NoError(t, handler.DB.Create(&models.RefreshToken{UserID: user.ID, JTI: "session-before-reset", ExpiresAt: time.Now().Add(time.Hour)}).Error)
+
+	post := func(path string, body interface{}) *httptest.ResponseRecorder {
+		raw, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, path, bytes.NewBuffer(raw))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	forgot := func() string {
+		w := post("/auth/password/forgot", ForgotPasswordRequest{Email: user.Email})
+		require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+		require.NotEmpty(t, mailer.resets)
+		link, err := url.Parse(mailer.resets[len(mailer.resets)-1])
+		require.NoError(t, err)
+		assert.Equal(t, "app.example.com", link.Host)
+		return link.Query().Get("token")
+	}
+
+	t.Run("Unknown emails get the same response and no email", func(t *testing.T) {
+		w := post("/auth/password/forgot", ForgotPasswordRequest{Email: "nobody@example.com"})
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Contains(t, w.Body.String(), passwordResetRequested)
+		assert.Empty(t, mailer.resets)
+	})
+
+	t.Run("Forged tokens are rejected", func(t *testing.T) {
+		w := post("/auth/password/reset", ResetPasswordRequest{Token: "not-a-token", Password: "Secure@New1"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("A newer token replaces the earlier one", func(t *testing.T) {
+		first := forgot()
+		second := forgot()
+
+		w := post("/auth/password/reset", ResetPasswordRequest{Token: first, Password: "Secure@New1"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = post("/auth/password/reset", ResetPasswordRequest{Token: second, Password: "weak"})
+		assert.Equal(t, http.StatusBadRequest, w.Code, "weak passwords are refused")
+
+		w = post("/auth/password/reset", ResetPasswordRequest{Token: second, Password: "Secure@New1"})
+		require.Equal(t, http.StatusOK, w.Code, "a refused password does not spend the token")
+
+		var stored models.User
+		require.NoError(t, handler.DB.First(&stored, user.ID).Error)
+		assert.True(t, models.ComparePassword(stored.PasswordHash, "Secure@New1"))
+
+		var active int64
+		handler.DB.Model(&models.RefreshToken{}).Where("user_id = ? AND revoked = ?", user.ID, false).Count(&active)
+		assert.Zero(t, active, "existing sessions are signed out")
+
+		w = post("/auth/password/reset", ResetPasswordRequest{Token: second, Password: "Secure@New2"})
+		assert.Equal(t, http.StatusBadRequest, w.Code, "tokens work once")
+	})
+
+	t.Run("Expired tokens are rejected", func(t *testing.T) {
+		token := forgot()
+		require.NoError(t, handler.DB.Model(&models.PasswordResetToken{}).
+			Where("token_hash = ?", models.HashPasswordResetToken(token)).
+			Update("expires_at", time.Now().Add(-time.Minute)).Error)
+
+		w := post("/auth/password/reset", ResetPasswordRequest{Token: token, Password: "Secure@New3"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
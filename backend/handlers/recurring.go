@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/services"
+	"github.com/yourusername/gpay-remit/utils"
+	"github.com/yourusername/gpay-remit/validators"
+	"gorm.io/gorm"
+)
+
+type RecurringHandler struct {
+	db        *gorm.DB
+	config    *config.Config
+	fees      *services.FeeService
+	recurring *services.RecurringService
+}
+
+func NewRecurringHandler(db *gorm.DB, cfg *config.Config) *RecurringHandler {
+	fees := services.NewFeeService(cfg)
+	return &RecurringHandler{db: db, config: cfg, fees: fees, recurring: services.NewRecurringService(db, fees)}
+}
+
+type CreateRecurringRemittanceRequest struct {
+	RecipientAccount string  `json:"recipient_account" binding:"required"`
+	Amount           float64 `json:"amount" binding:"required,gt=0"`
+	AssetCode        string  `json:"asset_code" binding:"required"`
+	AssetIssuer      string  `json:"asset_issuer"`
+	Interval         string  `json:"interval" binding:"required"`
+	// StartAt is the first run; it defaults to now, so the first remittance
+	// is created on the scheduler's next pass.
+	StartAt *time.Time `json:"start_at"`
+}
+
+// CreateRecurringRemittance schedules a remittance from the caller to
+// recipient_account every interval (daily, weekly, or monthly).
+func (h *RecurringHandler) CreateRecurringRemittance(c *gin.Context) {
+	var req CreateRecurringRemittanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+
+	interval, err := services.ParseInterval(req.Interval)
+	if err != nil {
+		c.Error(errors.NewValidationError("Invalid interval", err.Error()))
+		return
+	}
+	if err := validators.ValidateStellarAddress(req.RecipientAccount); err != nil {
+		c.Error(errors.NewValidationError("Invalid recipient account", err.Error()))
+		return
+	}
+	if err := utils.ValidateAsset(req.AssetCode, req.AssetIssuer); err != nil {
+		c.Error(errors.NewValidationError("Invalid asset", err.Error()).WithKey("error.invalid_asset"))
+		return
+	}
+	if !requireSupportedCurrency(c, h.config, "asset_code", req.AssetCode, req.AssetIssuer) {
+		return
+	}
+	if !requireAllowedIssuer(c, h.config, req.AssetCode, req.AssetIssuer) {
+		return
+	}
+	if _, err := h.fees.Calculate(req.Amount); err != nil {
+		c.Error(feeCalculationError(err))
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+
+	now := time.Now().UTC()
+	startAt := now
+	if req.StartAt != nil {
+		if req.StartAt.Before(now.Add(-time.Minute)) {
+			c.Error(errors.NewValidationError("Invalid start_at", "start_at must not be in the past"))
+			return
+		}
+		startAt = req.StartAt.UTC()
+	}
+
+	schedule := models.RecurringRemittance{
+		SenderID:         userID.(uint),
+		RecipientAccount: req.RecipientAccount,
+		Amount:           req.Amount,
+		AssetCode:        req.AssetCode,
+		AssetIssuer:      req.AssetIssuer,
+		Interval:         interval,
+		StartAt:          startAt,
+		NextRunAt:        startAt,
+		Active:           true,
+	}
+	if err := h.db.Create(&schedule).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to create recurring remittance", err))
+		return
+	}
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// ListRecurringRemittances lists the caller's schedules, paused ones included.
+func (h *RecurringHandler) ListRecurringRemittances(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+
+	var schedules []models.RecurringRemittance
+	if err := h.db.Where("sender_id = ?", userID).Order("created_at DESC").Find(&schedules).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to fetch recurring remittances", err))
+		return
+	}
+	c.JSON(http.StatusOK, schedules)
+}
+
+// PauseRecurringRemittance stops a schedule from firing until it is resumed.
+func (h *RecurringHandler) PauseRecurringRemittance(c *gin.Context) {
+	schedule, ok := h.ownSchedule(c)
+	if !ok {
+		return
+	}
+	if err := h.db.Model(schedule).Update("active", false).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to pause recurring remittance", err))
+		return
+	}
+	schedule.Active = false
+	c.JSON(http.StatusOK, schedule)
+}
+
+// ResumeRecurringRemittance reactivates a paused schedule. Runs that fell due
+// while it was paused are skipped.
+func (h *RecurringHandler) ResumeRecurringRemittance(c *gin.Context) {
+	schedule, ok := h.ownSchedule(c)
+	if !ok {
+		return
+	}
+	if err := h.recurring.Resume(schedule, time.Now().UTC()); err != nil {
+		c.Error(errors.NewInternalError("Failed to resume recurring remittance", err))
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteRecurringRemittance removes a schedule. Remittances it already
+// created are unaffected.
+func (h *RecurringHandler) DeleteRecurringRemittance(c *gin.Context) {
+	schedule, ok := h.ownSchedule(c)
+	if !ok {
+		return
+	}
+	if err := h.db.Delete(schedule).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to delete recurring remittance", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Recurring remittance deleted successfully"})
+}
+
+// ownSchedule loads the caller's schedule named by the route, reporting on
+// the context when there is none.
+func (h *RecurringHandler) ownSchedule(c *gin.Context) (*models.RecurringRemittance, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return nil, false
+	}
+
+	var schedule models.RecurringRemittance
+	if err := h.db.Where("id = ? AND sender_id = ?", c.Param("id"), userID).First(&schedule).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("Recurring remittance not found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch recurring remittance", err))
+		}
+		return nil, false
+	}
+	return &schedule, true
+}
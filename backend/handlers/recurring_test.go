@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/models"
+)
+
+func TestRecurringRemittances(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	require.NoError(t, db.AutoMigrate(&models.RecurringRemittance{}))
+
+	handler := NewRecurringHandler(db, &config.Config{})
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.POST("/recurring-remittances", handler.CreateRecurringRemittance)
+	router.GET("/recurring-remittances", handler.ListRecurringRemittances)
+	router.POST("/recurring-remittances/:id/pause", handler.PauseRecurringRemittance)
+	router.POST("/recurring-remittances/:id/resume", handler.ResumeRecurringRemittance)
+	router.DELETE("/recurring-remittances/:id", handler.DeleteRecurringRemittance)
+
+	do := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&buf).Encode(body)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, &buf)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	recipient := "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+	valid := CreateRecurringRemittanceRequest{RecipientAccount: recipient, Amount: 200, AssetCode: "XLM", Interval: "Monthly"}
+
+	w := do(http.MethodPost, "/recurring-remittances", valid)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var schedule models.RecurringRemittance
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &schedule))
+	assert.Equal(t, "monthly", schedule.Interval)
+	assert.Equal(t, uint(1), schedule.SenderID)
+	assert.True(t, schedule.Active)
+	assert.True(t, schedule.NextRunAt.Equal(schedule.StartAt))
+
+	t.Run("Rejects an unknown interval", func(t *testing.T) {
+		bad := valid
+		bad.Interval = "hourly"
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/recurring-remittances", bad).Code)
+	})
+
+	t.Run("Lists only the caller's schedules", func(t *testing.T) {
+		db.Create(&models.RecurringRemittance{SenderID: 2, RecipientAccount: recipient, Amount: 5, AssetCode: "XLM", Interval: "daily", Active: true})
+
+		w := do(http.MethodGet, "/recurring-remittances", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var schedules []models.RecurringRemittance
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &schedules))
+		require.Len(t, schedules, 1)
+		assert.Equal(t, schedule.ID, schedules[0].ID)
+	})
+
+	t.Run("Pause and resume", func(t *testing.T) {
+		w := do(http.MethodPost, fmt.Sprintf("/recurring-remittances/%d/pause", schedule.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var stored models.RecurringRemittance
+		db.First(&stored, schedule.ID)
+		assert.False(t, stored.Active)
+
+		w = do(http.MethodPost, fmt.Sprintf("/recurring-remittances/%d/resume", schedule.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		db.First(&stored, schedule.ID)
+		assert.True(t, stored.Active)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		w := do(http.MethodDelete, fmt.Sprintf("/recurring-remittances/%d", schedule.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		w = do(http.MethodPost, fmt.Sprintf("/recurring-remittances/%d/pause", schedule.ID), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
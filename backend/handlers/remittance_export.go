@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/models"
+)
+
+// remittanceExportHeader is the CSV header row of a remittance export.
+var remittanceExportHeader = []string{"id", "date", "direction", "counterparty", "amount", "currency", "fee", "status", "tx_hash", "notes"}
+
+// remittanceExportRow is one remittance as seen by the user whose history is
+// exported: the counterparty is the other side of the payment.
+type remittanceExportRow struct {
+	ID           uint      `json:"id"`
+	Date         time.Time `json:"date"`
+	Direction    string    `json:"direction"` // sent or received
+	Counterparty string    `json:"counterparty"`
+	Amount       float64   `json:"amount"`
+	Currency     string    `json:"currency"`
+	Fee          float64   `json:"fee"`
+	Status       string    `json:"status"`
+	TxHash       string    `json:"tx_hash"`
+	Notes        string    `json:"notes"`
+}
+
+func newRemittanceExportRow(p *models.Payment, userID uint) remittanceExportRow {
+	row := remittanceExportRow{
+		ID:           p.ID,
+		Date:         p.CreatedAt.UTC(),
+		Direction:    "sent",
+		Counterparty: p.RecipientAccount,
+		Amount:       p.Amount,
+		Currency:     p.Currency,
+		Fee:          p.Fee,
+		Status:       p.Status,
+		TxHash:       p.TxHash,
+		Notes:        p.Notes.String(),
+	}
+	if p.SenderID != userID {
+		row.Direction = "received"
+		row.Counterparty = p.SenderAccount
+	}
+	return row
+}
+
+func (r remittanceExportRow) record() []string {
+	return []string{
+		strconv.FormatUint(uint64(r.ID), 10),
+		r.Date.Format(time.RFC3339),
+		r.Direction,
+		r.Counterparty,
+		strconv.FormatFloat(r.Amount, 'f', -1, 64),
+		r.Currency,
+		strconv.FormatFloat(r.Fee, 'f', -1, 64),
+		r.Status,
+		r.TxHash,
+		r.Notes,
+	}
+}
+
+// ExportRemittances streams the caller's remittance history, sent and
+// received, newest first, as CSV (the default) or as a JSON array. It takes
+// the same filters as ListRemittances but is not paginated. Admins may export
+// another user's history with user_id.
+func (h *RemittanceHandler) ExportRemittances(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		c.Error(errors.NewValidationError("Invalid format", "format must be 'csv' or 'json'"))
+		return
+	}
+
+	callerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+	userID := callerID.(uint)
+	if raw := c.Query("user_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			c.Error(errors.NewValidationError("Invalid user_id", "user_id must be a positive integer"))
+			return
+		}
+		if uint(id) != userID && c.GetString("role") != "admin" {
+			c.Error(errors.NewForbiddenError("Only admins can export another user's remittances"))
+			return
+		}
+		userID = uint(id)
+	}
+
+	filters, err := paymentFilters(c)
+	if err != nil {
+		c.Error(errors.NewValidationError("Invalid query parameters", err.Error()))
+		return
+	}
+
+	rows, err := h.db.Model(&models.Payment{}).
+		Scopes(filters).
+		Where("(sender_id = ? OR recipient_id = ?)", userID, userID).
+		Order("created_at DESC").
+		Rows()
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to fetch remittances", err))
+		return
+	}
+	defer rows.Close()
+
+	filename := fmt.Sprintf("remittances_%d_%s.%s", userID, time.Now().UTC().Format("20060102"), format)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	if format == "csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+	} else {
+		c.Header("Content-Type", "application/json; charset=utf-8")
+	}
+	c.Status(http.StatusOK)
+
+	// From here on the status is sent, so a failure can only cut the body
+	// short; it is logged rather than reported.
+	csvWriter := csv.NewWriter(c.Writer)
+	if format == "csv" {
+		csvWriter.Write(remittanceExportHeader)
+	} else {
+		c.Writer.WriteString("[")
+	}
+
+	count := 0
+	for rows.Next() {
+		var payment models.Payment
+		if err := h.db.ScanRows(rows, &payment); err != nil {
+			logger.Log.WithError(err).WithField("user_id", userID).Error("Remittance export aborted")
+			return
+		}
+		row := newRemittanceExportRow(&payment, userID)
+		if format == "csv" {
+			csvWriter.Write(row.record())
+		} else {
+			encoded, err := json.Marshal(row)
+			if err != nil {
+				logger.Log.WithError(err).WithField("user_id", userID).Error("Remittance export aborted")
+				return
+			}
+			if count > 0 {
+				c.Writer.WriteString(",")
+			}
+			c.Writer.Write(encoded)
+		}
+		count++
+		if count%100 == 0 {
+			csvWriter.Flush()
+			c.Writer.Flush()
+		}
+	}
+	if err := rows.Err(); err != nil {
+		logger.Log.WithError(err).WithField("user_id", userID).Error("Remittance export aborted")
+		return
+	}
+
+	if format == "csv" {
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			logger.Log.WithError(err).WithField("user_id", userID).Error("Remittance export aborted")
+			return
+		}
+	} else {
+		c.Writer.WriteString("]")
+	}
+	c.Writer.Flush()
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/models"
+)
+
+func TestExportRemittances(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	alice := models.User{Email: "alice@example.com", Name: "Alice", StellarAddress: "GALICE", PasswordHash: "x"}
+	bob := models.User{Email: "bob@example.com", Name: "Bob", StellarAddress: "GBOB", PasswordHash: "x"}
+	admin := models.User{Email: "admin@example.com", Name: "Admin", StellarAddress: "GADMIN", PasswordHash: "x", Role: "admin"}
+	require.NoError(t, db.Create(&alice).Error)
+	require.NoError(t, db.Create(&bob).Error)
+	require.NoError(t, db.Create(&admin).Error)
+
+	sentAt := time.Date(2024, 1, 31, 9, 15, 0, 0, time.UTC)
+	sent := models.Payment{
+		SenderID: alice.ID, SenderAccount: "GALICE", RecipientID: bob.ID, RecipientAccount: "GBOB",
+		Amount: 100, Currency: "USDC", Fee: 2.5, Status: "completed", TxHash: "hash1",
+		Notes: models.EncryptedString("Rent, \"January\"\nflat 2"), CreatedAt: sentAt,
+	}
+	received := models.Payment{
+		SenderID: bob.ID, SenderAccount: "GBOB", RecipientID: alice.ID, RecipientAccount: "GALICE",
+		Amount: 40, Currency: "XLM", Status: "pending", CreatedAt: sentAt.Add(time.Hour),
+	}
+	unrelated := models.Payment{
+		SenderID: bob.ID, SenderAccount: "GBOB", RecipientID: admin.ID, RecipientAccount: "GADMIN",
+		Amount: 5, Currency: "XLM", Status: "completed", CreatedAt: sentAt,
+	}
+	for _, p := range []*models.Payment{&sent, &received, &unrelated} {
+		require.NoError(t, db.Create(p).Error)
+	}
+
+	handler := &RemittanceHandler{db: db}
+	get := func(user models.User, query string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", user.ID)
+			c.Set("role", user.Role)
+			c.Next()
+		})
+		router.GET("/remittances/export", handler.ExportRemittances)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/remittances/export"+query, nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("CSV of the caller's history", func(t *testing.T) {
+		w := get(alice, "?format=csv")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Regexp(t, fmt.Sprintf(`^attachment; filename=remittances_%d_\d{8}\.csv$`, alice.ID), w.Header().Get("Content-Disposition"))
+
+		records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		assert.Equal(t, []string{"id", "date", "direction", "counterparty", "amount", "currency", "fee", "status", "tx_hash", "notes"}, records[0])
+		assert.Equal(t, "received", records[1][2])
+		assert.Equal(t, "GBOB", records[1][3])
+		assert.Equal(t, []string{
+			fmt.Sprint(sent.ID), "2024-01-31T09:15:00Z", "sent", "GBOB", "100", "USDC", "2.5", "completed", "hash1", "Rent, \"January\"\nflat 2",
+		}, records[2])
+		assert.Contains(t, w.Body.String(), "\"Rent, \"\"January\"\"\nflat 2\"")
+	})
+
+	t.Run("Filters apply", func(t *testing.T) {
+		w := get(alice, "?min_amount=50")
+		require.Equal(t, http.StatusOK, w.Code)
+		records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, fmt.Sprint(sent.ID), records[1][0])
+	})
+
+	t.Run("JSON variant", func(t *testing.T) {
+		w := get(alice, "?format=json")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Disposition"), ".json")
+
+		var rows []remittanceExportRow
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rows))
+		require.Len(t, rows, 2)
+		assert.Equal(t, received.ID, rows[0].ID)
+		assert.Equal(t, "Rent, \"January\"\nflat 2", rows[1].Notes)
+	})
+
+	t.Run("Empty history is an empty export", func(t *testing.T) {
+		w := get(alice, "?format=json&from=2030-01-01T00:00:00Z")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "[]", w.Body.String())
+	})
+
+	t.Run("Admin exports another user's history", func(t *testing.T) {
+		w := get(admin, fmt.Sprintf("?user_id=%d", bob.ID))
+		require.Equal(t, http.StatusOK, w.Code)
+		records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+		require.NoError(t, err)
+		assert.Len(t, records, 4)
+	})
+
+	t.Run("Users cannot export another user's history", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, get(alice, fmt.Sprintf("?user_id=%d", bob.ID)).Code)
+	})
+
+	t.Run("Invalid format", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, get(alice, "?format=pdf").Code)
+	})
+}
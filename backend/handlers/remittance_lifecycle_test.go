@@ -0,0 +1,402 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/services"
+	"github.com/yourusername/gpay-remit/utils"
+)
+
+func TestCancelRemittance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	handler := &RemittanceHandler{db: db, config: &config.Config{}}
+
+	newRouter := func(userID uint, role string) *gin.Engine {
+		router := newTestRouter(userID, role)
+		router.POST("/remittances/:id/cancel", handler.CancelRemittance)
+		return router
+	}
+
+	cancel := func(router *gin.Engine, id uint) *httptest.ResponseRecorder {
+		return serveJSON(router, http.MethodPost, fmt.Sprintf("/remittances/%d/cancel", id), nil)
+	}
+
+	t.Run("Sender cancels pending remittance", func(t *testing.T) {
+		payment := models.Payment{SenderID: 1, Amount: 10, Currency: "USD", Status: "pending"}
+		db.Create(&payment)
+
+		w := cancel(newRouter(1, "user"), payment.ID)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var stored models.Payment
+		db.First(&stored, payment.ID)
+		assert.Equal(t, "cancelled", stored.Status)
+	})
+
+	t.Run("Admin cancels whole batch", func(t *testing.T) {
+		first := models.Payment{SenderID: 1, Amount: 10, Currency: "USD", Status: "pending", BatchID: "batch-cancel"}
+		second := models.Payment{SenderID: 1, Amount: 20, Currency: "USD", Status: "pending", BatchID: "batch-cancel"}
+		db.Create(&first)
+		db.Create(&second)
+
+		w := cancel(newRouter(99, "admin"), first.ID)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var stored models.Payment
+		db.First(&stored, second.ID)
+		assert.Equal(t, "cancelled", stored.Status)
+	})
+
+	t.Run("Processing or completed remittance rejected", func(t *testing.T) {
+		for _, status := range []string{"processing", "completed"} {
+			payment := models.Payment{SenderID: 1, Amount: 10, Currency: "USD", Status: status}
+			db.Create(&payment)
+
+			w := cancel(newRouter(1, "user"), payment.ID)
+			assert.Equal(t, http.StatusConflict, w.Code)
+
+			var stored models.Payment
+			db.First(&stored, payment.ID)
+			assert.Equal(t, status, stored.Status)
+		}
+	})
+
+	t.Run("Other user forbidden", func(t *testing.T) {
+		payment := models.Payment{SenderID: 1, Amount: 10, Currency: "USD", Status: "pending"}
+		db.Create(&payment)
+
+		w := cancel(newRouter(2, "user"), payment.ID)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		var stored models.Payment
+		db.First(&stored, payment.ID)
+		assert.Equal(t, "pending", stored.Status)
+	})
+}
+
+func TestCompleteRemittanceConcurrent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	// A single connection keeps every goroutine on the same in-memory database.
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	handler := &RemittanceHandler{db: db, config: &config.Config{}}
+	router := newTestRouter(0, "")
+	router.POST("/remittances/:id/complete", handler.CompleteRemittance)
+
+	payment := models.Payment{SenderID: 1, Amount: 10, Currency: "USD", Status: "processing", TxHash: testTxHash}
+	db.Create(&payment)
+	assert.Equal(t, 1, payment.Version)
+
+	const racers = 2
+	codes := make([]int, racers)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < racers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			w := serveJSON(router, http.MethodPost, fmt.Sprintf("/remittances/%d/complete", payment.ID), nil)
+			codes[i] = w.Code
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	succeeded, conflicted := 0, 0
+	for _, code := range codes {
+		switch code {
+		case http.StatusOK:
+			succeeded++
+		case http.StatusConflict:
+			conflicted++
+		}
+	}
+	assert.Equal(t, 1, succeeded, "exactly one completion must win: %v", codes)
+	assert.Equal(t, 1, conflicted, "the loser must get a conflict: %v", codes)
+
+	var stored models.Payment
+	db.First(&stored, payment.ID)
+	assert.Equal(t, "completed", stored.Status)
+	assert.Equal(t, 2, stored.Version)
+
+	t.Run("Stale version is rejected", func(t *testing.T) {
+		stale := payment // still at version 1
+		err := stale.UpdateVersioned(db, map[string]interface{}{"status": "failed"})
+		assert.Equal(t, models.ErrConcurrentModification, err)
+
+		db.First(&stored, payment.ID)
+		assert.Equal(t, "completed", stored.Status)
+	})
+}
+
+func TestCompleteRemittanceRequiresTransaction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	db.AutoMigrate(&models.AuditLog{})
+
+	txStatus := utils.TxStatusPending
+	cfg := &config.Config{}
+	handler := &RemittanceHandler{
+		db:     db,
+		config: cfg,
+		stellarClient: &MockStellarClient{
+			GetTransactionStatusFunc: func(txHash string) (utils.TxStatus, error) { return txStatus, nil },
+		},
+	}
+	router := newTestRouter(9, "admin")
+	router.Use(middleware.AuditTrail(db))
+	router.POST("/api/v1/remittances/:id/complete", handler.CompleteRemittance)
+	router.POST("/api/v1/remittances/:id/force-complete", handler.ForceCompleteRemittance)
+
+	post := func(path string, body interface{}) *httptest.ResponseRecorder {
+		return serveJSON(router, http.MethodPost, path, body)
+	}
+	status := func(id uint) string {
+		var stored models.Payment
+		db.First(&stored, id)
+		return stored.Status
+	}
+
+	unsubmitted := models.Payment{SenderID: 1, Amount: 10, Currency: "USD", Status: "pending"}
+	db.Create(&unsubmitted)
+
+	t.Run("Without a transaction hash is rejected", func(t *testing.T) {
+		w := post(fmt.Sprintf("/api/v1/remittances/%d/complete", unsubmitted.ID), nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "pending", status(unsubmitted.ID))
+	})
+
+	t.Run("Malformed transaction hash is rejected", func(t *testing.T) {
+		bogus := models.Payment{SenderID: 1, Amount: 10, Currency: "USD", Status: "processing", TxHash: "tx_hash_123"}
+		db.Create(&bogus)
+
+		w := post(fmt.Sprintf("/api/v1/remittances/%d/complete", bogus.ID), nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "processing", status(bogus.ID))
+	})
+
+	t.Run("Strict mode requires a confirmed transaction", func(t *testing.T) {
+		cfg.StrictCompletion = true
+		defer func() { cfg.StrictCompletion = false }()
+
+		payment := models.Payment{SenderID: 1, Amount: 10, Currency: "USD", Status: "processing", TxHash: testTxHash}
+		db.Create(&payment)
+
+		w := post(fmt.Sprintf("/api/v1/remittances/%d/complete", payment.ID), nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "processing", status(payment.ID))
+
+		txStatus = utils.TxStatusSuccess
+		w = post(fmt.Sprintf("/api/v1/remittances/%d/complete", payment.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "completed", status(payment.ID))
+	})
+
+	t.Run("Force-complete requires a reason", func(t *testing.T) {
+		w := post(fmt.Sprintf("/api/v1/remittances/%d/force-complete", unsubmitted.ID), ForceCompleteRemittanceRequest{Reason: "  "})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "pending", status(unsubmitted.ID))
+	})
+
+	t.Run("Force-complete is audited with its reason", func(t *testing.T) {
+		reason := "Settled off-platform by bank transfer, ticket OPS-12"
+		w := post(fmt.Sprintf("/api/v1/remittances/%d/force-complete", unsubmitted.ID), ForceCompleteRemittanceRequest{Reason: reason})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "completed", status(unsubmitted.ID))
+
+		var entry models.AuditLog
+		err := db.Where("resource = ?", "/api/v1/remittances/:id/force-complete").First(&entry).Error
+		if assert.NoError(t, err) {
+			assert.Equal(t, uint(9), *entry.UserID)
+			assert.Equal(t, fmt.Sprint(unsubmitted.ID), entry.EntityID)
+			assert.Equal(t, "pending", entry.OldStatus)
+			assert.Equal(t, "completed", entry.NewStatus)
+			assert.Equal(t, reason, entry.Reason)
+		}
+	})
+}
+
+func TestRemittanceSlippage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	cfg := &config.Config{}
+	handler := &RemittanceHandler{
+		db:     db,
+		config: cfg,
+		fees:   services.NewFeeService(cfg),
+		rates:  services.StaticRateSource{"USD/NGN": 1550},
+	}
+
+	router := newTestRouter(1, "admin")
+	router.POST("/remittances", handler.SendRemittance)
+	router.POST("/remittances/:id/complete", handler.CompleteRemittance)
+	router.GET("/remittances/:id/slippage", handler.GetSlippage)
+
+	do := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		return serveJSON(router, method, path, body)
+	}
+
+	w := do(http.MethodPost, "/remittances", SendRemittanceRequest{SenderID: 1, RecipientID: 2, Amount: 1000, Currency: "USD", TargetCurrency: "NGN"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var payment models.Payment
+	json.Unmarshal(w.Body.Bytes(), &payment)
+	assert.Equal(t, 1550.0, payment.QuotedRate)
+	assert.Equal(t, 1550000.0, payment.ConvertedAmount)
+
+	t.Run("Not reported before completion", func(t *testing.T) {
+		w := do(http.MethodGet, fmt.Sprintf("/remittances/%d/slippage", payment.ID), nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	// Completion needs a submitted transaction.
+	submitted := func(id uint) {
+		db.Model(&models.Payment{}).Where("id = ?", id).Update("tx_hash", testTxHash)
+	}
+
+	t.Run("Matches stored quoted and executed rates", func(t *testing.T) {
+		submitted(payment.ID)
+		w := do(http.MethodPost, fmt.Sprintf("/remittances/%d/complete", payment.ID), CompleteRemittanceRequest{ExecutedRate: 1534.5})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var stored models.Payment
+		db.First(&stored, payment.ID)
+		assert.Equal(t, 1534.5, stored.ExecutedRate)
+		assert.Equal(t, 1534500.0, stored.ConvertedAmount)
+
+		w = do(http.MethodGet, fmt.Sprintf("/remittances/%d/slippage", payment.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var report services.SlippageReport
+		json.Unmarshal(w.Body.Bytes(), &report)
+
+		expected, err := services.Slippage(&stored)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, report)
+		assert.Equal(t, 1550.0, report.QuotedRate)
+		assert.Equal(t, 1534.5, report.ExecutedRate)
+		assert.Equal(t, -15500.0, report.SlippageAmount)
+		assert.Equal(t, -1.0, report.SlippagePercent)
+	})
+
+	t.Run("Completion without a rate records the current one", func(t *testing.T) {
+		w := do(http.MethodPost, "/remittances", SendRemittanceRequest{SenderID: 1, RecipientID: 2, Amount: 10, Currency: "USD", TargetCurrency: "NGN"})
+		var other models.Payment
+		json.Unmarshal(w.Body.Bytes(), &other)
+
+		submitted(other.ID)
+		assert.Equal(t, http.StatusOK, do(http.MethodPost, fmt.Sprintf("/remittances/%d/complete", other.ID), nil).Code)
+		db.First(&other, other.ID)
+		assert.Equal(t, 1550.0, other.ExecutedRate)
+	})
+
+	t.Run("Unknown pair is rejected at creation", func(t *testing.T) {
+		w := do(http.MethodPost, "/remittances", SendRemittanceRequest{SenderID: 1, RecipientID: 2, Amount: 10, Currency: "USD", TargetCurrency: "KES"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Single-currency remittance has no slippage", func(t *testing.T) {
+		single := models.Payment{SenderID: 1, Amount: 10, Currency: "USD", Status: "completed"}
+		db.Create(&single)
+		w := do(http.MethodGet, fmt.Sprintf("/remittances/%d/slippage", single.ID), nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+type mockNotifier struct {
+	sent chan utils.Message
+}
+
+func (m *mockNotifier) Notify(ctx context.Context, msg utils.Message) error {
+	m.sent <- msg
+	return nil
+}
+
+func TestCompletionNotification(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	// Notifications are dispatched from a goroutine, which must see the same
+	// in-memory database.
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	db.AutoMigrate(&models.NotificationPreference{})
+
+	sender := models.User{Email: "sender@example.com", Name: "Sender", StellarAddress: "GSENDERNOTIFY", Country: "US", EmailNotifications: true}
+	recipient := models.User{Email: "recipient@example.com", Name: "Awa", StellarAddress: "GRECIPIENTNOTIFY", Country: "SN", EmailNotifications: true}
+	db.Create(&sender)
+	db.Create(&recipient)
+
+	messenger := &mockNotifier{sent: make(chan utils.Message, 4)}
+	handler := &RemittanceHandler{
+		db:        db,
+		config:    &config.Config{},
+		notifier:  services.NewNotificationService(db, nil, nil),
+		messenger: messenger,
+	}
+
+	router := newTestRouter(sender.ID, "admin")
+	router.POST("/remittances/:id/complete", handler.CompleteRemittance)
+
+	payment := models.Payment{SenderID: sender.ID, RecipientID: recipient.ID, Amount: 250, Currency: "USDC", Status: "submitted", TxHash: testTxHash}
+	db.Create(&payment)
+
+	w := serveJSON(router, http.MethodPost, fmt.Sprintf("/remittances/%d/complete", payment.ID), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	received := map[string]utils.Message{}
+	for len(received) < 2 {
+		select {
+		case msg := <-messenger.sent:
+			received[msg.Event] = msg
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for notifications, got %v", received)
+		}
+	}
+
+	toRecipient, ok := received[utils.NotifyRemittanceReceived]
+	if assert.True(t, ok) {
+		assert.Equal(t, "recipient@example.com", toRecipient.To.Email)
+		assert.Equal(t, "fr", toRecipient.Locale)
+		assert.Contains(t, toRecipient.Subject, "250 USDC")
+		assert.Contains(t, toRecipient.Body, "Awa")
+	}
+	toSender, ok := received[utils.NotifyRemittanceCompleted]
+	if assert.True(t, ok) {
+		assert.Equal(t, "sender@example.com", toSender.To.Email)
+		assert.Equal(t, "en", toSender.Locale)
+	}
+
+	t.Run("Email preference is honoured", func(t *testing.T) {
+		assert.NoError(t, handler.notifier.SetPreference(recipient.ID, services.EventPaymentCompleted, models.ChannelEmail, false))
+
+		second := models.Payment{SenderID: sender.ID, RecipientID: recipient.ID, Amount: 10, Currency: "USDC", Status: "submitted", TxHash: testTxHash}
+		db.Create(&second)
+		w := serveJSON(router, http.MethodPost, fmt.Sprintf("/remittances/%d/complete", second.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		for i := 0; i < 2; i++ {
+			select {
+			case msg := <-messenger.sent:
+				if msg.Event == utils.NotifyRemittanceReceived {
+					assert.Empty(t, msg.To.Email)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("timed out waiting for notifications")
+			}
+		}
+	})
+}
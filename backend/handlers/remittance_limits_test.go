@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/services"
+	"github.com/yourusername/gpay-remit/utils"
+)
+
+func TestCreateRemittanceKYCGate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	verified := models.User{Email: "verified@example.com", Name: "Verified", StellarAddress: "GVERIFIED", PasswordHash: "x", KYCStatus: services.KYCStatusVerified}
+	pending := models.User{Email: "pending@example.com", Name: "Pending", StellarAddress: "GPENDING", PasswordHash: "x", KYCStatus: services.KYCStatusPending}
+	db.Create(&verified)
+	db.Create(&pending)
+
+	handler := &RemittanceHandler{
+		db:     db,
+		config: &config.Config{KYCThreshold: 500},
+		fees:   services.NewFeeService(&config.Config{}),
+		stellarClient: &MockStellarClient{
+			ValidateAccountFunc: func(accountID string) error { return nil },
+			BuildEscrowTxFunc: func(sender, recipient, assetCode, issuer, amount string, memo txnbuild.Memo) (string, error) {
+				return "base64_xdr", nil
+			},
+		},
+	}
+
+	sender, _ := keypair.Random()
+	recipient, _ := keypair.Random()
+	create := func(userID uint, amount float64) *httptest.ResponseRecorder {
+		router := newTestRouter(userID, "")
+		router.POST("/remittances/create", handler.CreateRemittance)
+
+		return serveJSON(router, http.MethodPost, "/remittances/create", CreateRemittanceRequest{
+			SenderAccount:    sender.Address(),
+			RecipientAccount: recipient.Address(),
+			Amount:           amount,
+			AssetCode:        "XLM",
+		})
+	}
+
+	t.Run("Verified user above threshold succeeds", func(t *testing.T) {
+		w := create(verified.ID, 2500)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("Pending user under threshold succeeds", func(t *testing.T) {
+		w := create(pending.ID, 500)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("Pending user over threshold blocked", func(t *testing.T) {
+		var before int64
+		db.Model(&models.Payment{}).Count(&before)
+
+		w := create(pending.ID, 500.01)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		var resp middleware.ErrorResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.Equal(t, errors.CodeKYCRequired, resp.Error.Code)
+
+		var after int64
+		db.Model(&models.Payment{}).Count(&after)
+		assert.Equal(t, before, after)
+	})
+}
+
+func TestCreateBatchRemittanceKYCGate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	pending := models.User{Email: "batch-pending@example.com", Name: "Pending", StellarAddress: "GBATCHPENDING", PasswordHash: "x", KYCStatus: services.KYCStatusPending}
+	db.Create(&pending)
+
+	handler := &RemittanceHandler{
+		db:     db,
+		config: &config.Config{KYCThreshold: 500},
+		fees:   services.NewFeeService(&config.Config{}),
+		stellarClient: &MockStellarClient{
+			ValidateAccountFunc: func(accountID string) error { return nil },
+			BuildBatchPaymentTxFunc: func(sourceAccount string, payments []utils.BatchPayment) (string, error) {
+				return "base64_xdr", nil
+			},
+		},
+	}
+	router := newTestRouter(pending.ID, "")
+	router.POST("/remittances/batch", handler.CreateBatchRemittance)
+
+	sender, _ := keypair.Random()
+	recipient, _ := keypair.Random()
+	batch := func(items ...BatchRemittanceItem) *httptest.ResponseRecorder {
+		for i := range items {
+			items[i].RecipientAccount = recipient.Address()
+		}
+		return serveJSON(router, http.MethodPost, "/remittances/batch", CreateBatchRemittanceRequest{SenderAccount: sender.Address(), Items: items})
+	}
+
+	t.Run("Items under the threshold adding up past it are blocked", func(t *testing.T) {
+		w := batch(
+			BatchRemittanceItem{Amount: 300, AssetCode: "XLM"},
+			BatchRemittanceItem{Amount: 300, AssetCode: "XLM"},
+		)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		var resp middleware.ErrorResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.Equal(t, errors.CodeKYCRequired, resp.Error.Code)
+	})
+
+	t.Run("Totals are kept per currency", func(t *testing.T) {
+		w := batch(
+			BatchRemittanceItem{Amount: 300, AssetCode: "XLM"},
+			BatchRemittanceItem{Amount: 300, AssetCode: "USDC", AssetIssuer: "GA5ZSEJYB37JRC5AVCIA5MOP4RHTM335X2KGX3IHOJAPP5RE34K4KZVN"},
+		)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+}
+
+func TestCreateRemittanceDailyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	user := models.User{Email: "limited@example.com", Name: "Limited", StellarAddress: "GLIMITED", PasswordHash: "x", KYCStatus: services.KYCStatusPending}
+	verified := models.User{Email: "limit-verified@example.com", Name: "Verified", StellarAddress: "GLIMITVERIFIED", PasswordHash: "x", KYCStatus: services.KYCStatusVerified}
+	db.Create(&user)
+	db.Create(&verified)
+
+	cfg := &config.Config{
+		DailyLimits:       map[string]float64{"XLM": 1000},
+		DailyLimitsByTier: map[string]map[string]float64{"verified": {"XLM": 5000}},
+	}
+	handler := &RemittanceHandler{
+		db:     db,
+		config: cfg,
+		fees:   services.NewFeeService(cfg),
+		stellarClient: &MockStellarClient{
+			ValidateAccountFunc: func(accountID string) error { return nil },
+			BuildEscrowTxFunc: func(sender, recipient, assetCode, issuer, amount string, memo txnbuild.Memo) (string, error) {
+				return "base64_xdr", nil
+			},
+		},
+	}
+
+	sender, _ := keypair.Random()
+	recipient, _ := keypair.Random()
+	create := func(userID uint, amount float64) *httptest.ResponseRecorder {
+		router := newTestRouter(userID, "")
+		router.POST("/remittances/create", handler.CreateRemittance)
+
+		return serveJSON(router, http.MethodPost, "/remittances/create", CreateRemittanceRequest{
+			SenderAccount:    sender.Address(),
+			RecipientAccount: recipient.Address(),
+			Amount:           amount,
+			AssetCode:        "XLM",
+		})
+	}
+
+	// Sent before today's UTC midnight, so it does not count.
+	yesterday := services.StartOfUTCDay(time.Now()).Add(-time.Minute)
+	db.Create(&models.Payment{SenderID: user.ID, Amount: 900, Currency: "XLM", Status: "completed", CreatedAt: yesterday})
+
+	var first struct {
+		RemittanceID uint `json:"remittance_id"`
+	}
+	t.Run("Up to the limit succeeds", func(t *testing.T) {
+		w := create(user.ID, 600)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		json.Unmarshal(w.Body.Bytes(), &first)
+
+		w = create(user.ID, 400)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("Past the limit is rejected", func(t *testing.T) {
+		w := create(user.ID, 0.01)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		var resp struct {
+			Error struct {
+				Code    errors.ErrorCode         `json:"code"`
+				Details services.DailyLimitError `json:"details"`
+			} `json:"error"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.Equal(t, errors.CodeDailyLimitExceeded, resp.Error.Code)
+		assert.Equal(t, services.DailyLimitError{Currency: "XLM", Limit: 1000, SentToday: 1000, Remaining: 0}, resp.Error.Details)
+	})
+
+	t.Run("Cancelled payments free up the limit", func(t *testing.T) {
+		db.Model(&models.Payment{}).Where("id = ?", first.RemittanceID).Update("status", "cancelled")
+
+		w := create(user.ID, 600)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		w = create(user.ID, 1)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Tier override applies", func(t *testing.T) {
+		w := create(verified.ID, 4000)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		w = create(verified.ID, 1001)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestSendRemittanceDailyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	user := models.User{Email: "send-limited@example.com", Name: "Limited", StellarAddress: "GSENDLIMITED", PasswordHash: "x", KYCStatus: services.KYCStatusPending}
+	other := models.User{Email: "send-other@example.com", Name: "Other", StellarAddress: "GSENDOTHER", PasswordHash: "x", KYCStatus: services.KYCStatusPending}
+	db.Create(&user)
+	db.Create(&other)
+
+	cfg := &config.Config{DailyLimits: map[string]float64{"XLM": 1000}}
+	handler := &RemittanceHandler{db: db, config: cfg, fees: services.NewFeeService(cfg)}
+
+	router := newTestRouter(user.ID, "")
+	router.POST("/remittances", handler.SendRemittance)
+
+	send := func(senderID uint, amount float64) *httptest.ResponseRecorder {
+		return serveJSON(router, http.MethodPost, "/remittances", SendRemittanceRequest{SenderID: senderID, RecipientID: 99, Amount: amount, Currency: "XLM"})
+	}
+
+	t.Run("Another user's sender_id is rejected", func(t *testing.T) {
+		w := send(other.ID, 10)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		var count int64
+		db.Model(&models.Payment{}).Count(&count)
+		assert.Zero(t, count)
+	})
+
+	t.Run("Limit is charged to the authenticated sender", func(t *testing.T) {
+		w := send(user.ID, 1000)
+		assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var payment models.Payment
+		json.Unmarshal(w.Body.Bytes(), &payment)
+		assert.Equal(t, user.ID, payment.SenderID)
+
+		w = send(user.ID, 1)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		var resp struct {
+			Error struct {
+				Code errors.ErrorCode `json:"code"`
+			} `json:"error"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.Equal(t, errors.CodeDailyLimitExceeded, resp.Error.Code)
+	})
+}
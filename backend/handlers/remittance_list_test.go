@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/encryption"
+	"github.com/yourusername/gpay-remit/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestGetRemittance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	handler := &RemittanceHandler{db: db, config: &config.Config{}}
+
+	provider, err := encryption.NewStaticKeys(1, map[int][]byte{1: bytes.Repeat([]byte{7}, encryption.KeySize)})
+	assert.NoError(t, err)
+	models.SetFieldCipher(encryption.NewCipher(provider))
+	defer models.SetFieldCipher(nil)
+
+	payment := models.Payment{SenderID: 1, RecipientID: 2, Amount: 75, Currency: "USD", Status: "pending", Notes: "for the rent"}
+	db.Create(&payment)
+
+	get := func(userID uint, role string) *httptest.ResponseRecorder {
+		router := newTestRouter(userID, role)
+		router.GET("/remittances/:id", handler.GetRemittance)
+		return serveJSON(router, http.MethodGet, fmt.Sprintf("/remittances/%d", payment.ID), nil)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		userID uint
+		role   string
+	}{{"sender", 1, ""}, {"recipient", 2, ""}, {"admin", 9, "admin"}} {
+		w := get(tc.userID, tc.role)
+		assert.Equal(t, http.StatusOK, w.Code, tc.name)
+		var got models.Payment
+		json.Unmarshal(w.Body.Bytes(), &got)
+		assert.Equal(t, "for the rent", got.Notes.String(), tc.name)
+	}
+
+	w := get(3, "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.NotContains(t, w.Body.String(), "for the rent")
+}
+
+func TestListRemittancesFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	handler := &RemittanceHandler{db: db, config: &config.Config{}}
+
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 12, 0, 0, 0, time.UTC) }
+	seed := []models.Payment{
+		{SenderID: 1, RecipientID: 2, Amount: 50, Currency: "USD", Status: "completed", Notes: "Rent for MARCH", CreatedAt: day(1)},
+		{SenderID: 1, RecipientID: 3, Amount: 250, Currency: "USD", Status: "completed", Notes: "School fees", CreatedAt: day(10)},
+		{SenderID: 4, RecipientID: 1, Amount: 900, Currency: "USD", Status: "pending", Memo: "invoice-77", CreatedAt: day(20)},
+		{SenderID: 5, RecipientID: 6, Amount: 100, Currency: "USD", Status: "pending", Notes: "rent for someone else", CreatedAt: day(10)},
+	}
+	for i := range seed {
+		db.Create(&seed[i])
+	}
+
+	list := func(role, query string) (int, []models.Payment) {
+		router := newTestRouter(1, role)
+		router.GET("/remittances", handler.ListRemittances)
+
+		w := serveJSON(router, http.MethodGet, "/remittances?"+query, nil)
+		var payments []models.Payment
+		json.Unmarshal(w.Body.Bytes(), &payments)
+		return w.Code, payments
+	}
+	ids := func(payments []models.Payment) []uint {
+		out := []uint{}
+		for _, p := range payments {
+			out = append(out, p.ID)
+		}
+		return out
+	}
+
+	t.Run("Scoped to the caller's remittances", func(t *testing.T) {
+		code, payments := list("user", "")
+		assert.Equal(t, http.StatusOK, code)
+		assert.ElementsMatch(t, []uint{seed[0].ID, seed[1].ID, seed[2].ID}, ids(payments))
+
+		_, payments = list("admin", "")
+		assert.Len(t, payments, 4)
+	})
+
+	t.Run("Date range", func(t *testing.T) {
+		code, payments := list("user", "from=2026-03-05T00:00:00Z&to=2026-03-15T00:00:00Z")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []uint{seed[1].ID}, ids(payments))
+
+		code, _ = list("user", "from=2026-03-15T00:00:00Z&to=2026-03-05T00:00:00Z")
+		assert.Equal(t, http.StatusBadRequest, code)
+
+		code, _ = list("user", "from=2026-03-05")
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("Amount range", func(t *testing.T) {
+		code, payments := list("user", "min_amount=100&max_amount=900")
+		assert.Equal(t, http.StatusOK, code)
+		assert.ElementsMatch(t, []uint{seed[1].ID, seed[2].ID}, ids(payments))
+
+		code, _ = list("user", "min_amount=500&max_amount=100")
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("Text search matches notes case-insensitively", func(t *testing.T) {
+		code, payments := list("user", "q=rent+for+march")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []uint{seed[0].ID}, ids(payments))
+
+		_, payments = list("admin", "q=RENT")
+		assert.ElementsMatch(t, []uint{seed[0].ID, seed[3].ID}, ids(payments))
+
+		_, payments = list("user", "q=INVOICE-77")
+		assert.Equal(t, []uint{seed[2].ID}, ids(payments))
+	})
+
+	t.Run("Text search matches whole words, as on Postgres", func(t *testing.T) {
+		_, payments := list("user", "q=march+rent")
+		assert.Equal(t, []uint{seed[0].ID}, ids(payments))
+
+		_, payments = list("user", "q=ren")
+		assert.Empty(t, payments)
+
+		_, payments = list("user", "q=invoice")
+		assert.Equal(t, []uint{seed[2].ID}, ids(payments))
+
+		_, payments = list("user", "q=school+rent")
+		assert.Empty(t, payments)
+
+		_, payments = list("user", "q=pending")
+		assert.Equal(t, []uint{seed[2].ID}, ids(payments))
+
+		_, payments = list("user", "q=250")
+		assert.Equal(t, []uint{seed[1].ID}, ids(payments))
+	})
+
+	t.Run("Encrypted notes are not matched as ciphertext", func(t *testing.T) {
+		provider, err := encryption.NewStaticKeys(1, map[int][]byte{1: bytes.Repeat([]byte{7}, encryption.KeySize)})
+		assert.NoError(t, err)
+		models.SetFieldCipher(encryption.NewCipher(provider))
+		defer models.SetFieldCipher(nil)
+
+		encrypted := models.Payment{SenderID: 1, RecipientID: 2, Amount: 75, Currency: "USD", Status: "pending", Notes: "gift", Memo: "gift-memo", CreatedAt: day(2)}
+		db.Create(&encrypted)
+		var stored string
+		db.Raw("SELECT notes FROM payments WHERE id = ?", encrypted.ID).Row().Scan(&stored)
+		assert.True(t, encryption.IsEncrypted(stored))
+
+		_, payments := list("user", "q="+url.QueryEscape(stored[len(stored)-12:]))
+		assert.Empty(t, payments)
+
+		_, payments = list("user", "q=gift-memo")
+		assert.Equal(t, []uint{encrypted.ID}, ids(payments))
+	})
+}
+
+func TestPaymentFiltersUseFullTextSearchOnPostgres(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=gpay"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for query, want := range map[string]string{"School+fees": "school fees", "INVOICE-77": "invoice 77"} {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request, _ = http.NewRequest(http.MethodGet, "/remittances?q="+query, nil)
+		filters, err := paymentFilters(c)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		stmt := db.Model(&models.Payment{}).Scopes(filters).Find(&[]models.Payment{}).Statement
+		assert.Contains(t, stmt.SQL.String(), "search_vector @@ plainto_tsquery('english', $1)")
+		assert.NotContains(t, stmt.SQL.String(), "LIKE")
+		assert.Equal(t, []interface{}{want}, stmt.Vars)
+	}
+}
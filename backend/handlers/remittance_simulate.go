@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stellar/go/txnbuild"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/services"
+	"github.com/yourusername/gpay-remit/utils"
+)
+
+// remittancePreflight is what CreateRemittance derives from a request before
+// anything is persisted or built.
+type remittancePreflight struct {
+	memo          txnbuild.Memo
+	memoType      string
+	stellarAmount string
+	settlement    string
+	conditions    *services.RemittanceConditions
+	fees          services.FeeBreakdown
+	feesKnown     bool
+	// blocking lists the problems that stop the remittance, in the order
+	// CreateRemittance meets them.
+	blocking []*errors.AppError
+}
+
+// conditionsError reports invalid remittance conditions with one FieldError
+// per rejected key.
+func conditionsError(err error) *errors.AppError {
+	invalid, ok := err.(*services.InvalidConditionsError)
+	if !ok {
+		return errors.NewValidationError("Invalid conditions", err.Error())
+	}
+	fields := make([]errors.FieldError, len(invalid.Fields))
+	for i, field := range invalid.Fields {
+		fields[i] = errors.FieldError{Field: "conditions." + field.Key, Rule: field.Rule, Message: field.Message}
+	}
+	return errors.NewValidationError("Invalid conditions", fields)
+}
+
+// preflightRemittance runs every check CreateRemittance applies to req for
+// the sender userID, without writing anything or building a transaction.
+// With stopEarly it returns after the first blocking problem, as creation
+// does; otherwise it collects them all for a simulation. Failures that are
+// not the request's fault, such as a database error, are returned as the
+// error and end the preflight either way.
+func (h *RemittanceHandler) preflightRemittance(ctx context.Context, userID uint, req *CreateRemittanceRequest, stopEarly bool) (*remittancePreflight, *errors.AppError) {
+	pre := &remittancePreflight{}
+	var fatal *errors.AppError
+	// block records a problem and reports whether the preflight should stop.
+	block := func(err *errors.AppError) bool {
+		if err == nil {
+			return false
+		}
+		if err.HTTPStatus >= http.StatusInternalServerError {
+			fatal = err
+			return true
+		}
+		pre.blocking = append(pre.blocking, err)
+		return stopEarly
+	}
+
+	memo, err := utils.ParseMemo(req.MemoType, req.Memo)
+	if err != nil {
+		if block(errors.NewValidationError("Invalid memo", err.Error())) {
+			return pre, fatal
+		}
+	} else if memo != nil {
+		pre.memo = memo
+		pre.memoType = strings.ToLower(req.MemoType)
+		if pre.memoType == "" {
+			pre.memoType = utils.MemoTypeText
+		}
+	}
+
+	if pre.conditions, err = services.ParseConditions(req.Conditions, time.Now()); err != nil {
+		if block(conditionsError(err)) {
+			return pre, fatal
+		}
+	} else if h.config.ClaimableBalances && pre.conditions.Escrow().Hashlock != "" {
+		// A claimable balance's predicates can only test time.
+		if block(errors.NewValidationError("Invalid conditions", []errors.FieldError{{
+			Field:   "conditions." + services.ConditionKeyHashlock,
+			Rule:    "value",
+			Message: "hashlock is not supported when remittances are sent as claimable balances",
+		}})) {
+			return pre, fatal
+		}
+	}
+
+	if err := utils.ValidateAsset(req.AssetCode, req.AssetIssuer); err != nil {
+		if block(errors.NewValidationError("Invalid asset", err.Error()).WithKey("error.invalid_asset")) {
+			return pre, fatal
+		}
+	} else {
+		// The supported set and issuer policy only apply to a well-formed asset.
+		if block(unsupportedCurrencyError(h.config, "asset_code", req.AssetCode, req.AssetIssuer)) {
+			return pre, fatal
+		}
+		if block(issuerError(h.config, req.AssetCode, req.AssetIssuer)) {
+			return pre, fatal
+		}
+	}
+	if block(amountRangeError(h.config, req.AssetCode, req.Amount)) {
+		return pre, fatal
+	}
+	if pre.stellarAmount, err = utils.StellarAmount(req.Amount); err != nil {
+		if block(errors.NewValidationError("Invalid amount", err.Error())) {
+			return pre, fatal
+		}
+	}
+
+	settlement, ok := h.settlementAccount(req.AssetCode)
+	if !ok {
+		if block(errors.NewValidationError(fmt.Sprintf("No settlement account configured for %s", req.AssetCode), nil)) {
+			return pre, fatal
+		}
+	}
+	pre.settlement = settlement
+	if block(h.baseFeeError(req.BaseFee)) {
+		return pre, fatal
+	}
+
+	if err := h.stellarClient.ValidateAccount(ctx, req.SenderAccount); err != nil {
+		if block(errors.NewValidationError("Invalid sender account", err.Error())) {
+			return pre, fatal
+		}
+	}
+	if err := h.stellarClient.ValidateAccount(ctx, req.RecipientAccount); err != nil {
+		if block(errors.NewValidationError("Invalid recipient account", err.Error())) {
+			return pre, fatal
+		}
+	}
+	if block(h.selfRemittanceError(userID, req.SenderAccount, req.RecipientAccount)) {
+		return pre, fatal
+	}
+
+	if block(h.kycError(userID, req.Amount)) {
+		return pre, fatal
+	}
+	if block(h.dailyLimitError(userID, map[string]float64{req.AssetCode: req.Amount})) {
+		return pre, fatal
+	}
+
+	from, to, err := h.corridorCountries(userID, req.RecipientAccount)
+	if err != nil {
+		block(errors.NewInternalError("Failed to resolve remittance corridor", err))
+		return pre, fatal
+	}
+	fees, err := h.fees.CalculateForCorridor(req.Amount, from, to)
+	if err != nil {
+		block(feeCalculationError(err))
+		return pre, fatal
+	}
+	pre.fees, pre.feesKnown = fees, true
+	block(feeAmountError(req.AssetCode, req.Amount, fees))
+	return pre, fatal
+}
+
+// SimulationResult previews a remittance without creating it. Blocking
+// reasons use the error envelope's body, so clients can handle them the same
+// way as the errors CreateRemittance would return.
+type SimulationResult struct {
+	WouldSucceed bool                   `json:"would_succeed"`
+	Amount       float64                `json:"amount"`
+	Currency     string                 `json:"currency"`
+	FeeBreakdown *services.FeeBreakdown `json:"fee_breakdown,omitempty"`
+	// NetAmount is what is left of Amount after fees.
+	NetAmount float64 `json:"net_amount,omitempty"`
+	// TargetCurrency, QuotedRate, and ConvertedAmount preview a conversion
+	// when target_currency is given.
+	TargetCurrency   string                 `json:"target_currency,omitempty"`
+	QuotedRate       float64                `json:"quoted_rate,omitempty"`
+	ConvertedAmount  float64                `json:"converted_amount,omitempty"`
+	TrustlineMissing bool                   `json:"trustline_missing"`
+	Warnings         []string               `json:"warnings"`
+	BlockingReasons  []middleware.ErrorBody `json:"blocking_reasons"`
+}
+
+// SimulateRemittance dry-runs CreateRemittance: it takes the same body, runs
+// the same checks, and reports the fees, net amount, and every reason the
+// remittance would be refused, without writing anything or building a
+// transaction. target_currency in the query adds a conversion quote. The
+// response is 200 whether or not the remittance would succeed.
+func (h *RemittanceHandler) SimulateRemittance(c *gin.Context) {
+	var req CreateRemittanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+	targetCurrency := strings.ToUpper(c.Query("target_currency"))
+	if targetCurrency != "" {
+		if err := utils.ValidateAssetCode(targetCurrency); err != nil {
+			c.Error(errors.NewValidationError("Invalid target currency", err.Error()))
+			return
+		}
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+	ctx := utils.WithRequestContext(c.Request.Context(), c.GetString("requestID"), userID)
+
+	pre, appErr := h.preflightRemittance(ctx, userID.(uint), &req, false)
+	if appErr != nil {
+		c.Error(appErr)
+		return
+	}
+
+	result := SimulationResult{
+		Amount:          req.Amount,
+		Currency:        strings.ToUpper(req.AssetCode),
+		Warnings:        []string{},
+		BlockingReasons: []middleware.ErrorBody{},
+	}
+	if pre.feesKnown {
+		result.FeeBreakdown = &pre.fees
+		if net, err := utils.RoundAmount(req.Amount - pre.fees.TotalFee); err == nil && net > 0 {
+			result.NetAmount = net.Float64()
+		}
+	}
+
+	if targetCurrency != "" && targetCurrency != result.Currency {
+		result.TargetCurrency = targetCurrency
+		if !currencySupported(h.config, targetCurrency, "") {
+			pre.blocking = append(pre.blocking, unsupportedCurrencyError(h.config, "target_currency", targetCurrency, ""))
+		} else if rate, err := h.quoteRate(ctx, result.Currency, targetCurrency); err != nil {
+			if !services.IsRateUnavailable(err) {
+				c.Error(errors.NewInternalError("Failed to quote FX rate", err))
+				return
+			}
+			pre.blocking = append(pre.blocking, errors.NewValidationError(fmt.Sprintf("No FX rate available for %s/%s", result.Currency, targetCurrency), nil))
+		} else {
+			result.QuotedRate = rate
+			result.ConvertedAmount = services.ConvertAmount(req.Amount, rate)
+		}
+	}
+
+	if !utils.IsNativeAsset(req.AssetCode) && utils.ValidateAsset(req.AssetCode, req.AssetIssuer) == nil {
+		missing, err := h.missingTrustline(ctx, req.RecipientAccount, req.AssetCode, req.AssetIssuer)
+		if err != nil {
+			logger.Log.WithField("recipient_account", req.RecipientAccount).WithError(err).Warn("Failed to check recipient trustline")
+			result.Warnings = append(result.Warnings, "Could not check whether the recipient has a trustline for the asset")
+		} else if missing {
+			result.TrustlineMissing = true
+			result.Warnings = append(result.Warnings, trustlineMessage(req.AssetCode, req.AssetIssuer))
+		}
+	}
+
+	for _, problem := range pre.blocking {
+		result.BlockingReasons = append(result.BlockingReasons, middleware.ErrorBody{
+			Code:    problem.Code,
+			Message: middleware.ErrorMessage(c, problem),
+			Details: problem.Details,
+		})
+	}
+	result.WouldSucceed = len(result.BlockingReasons) == 0
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/services"
+	"github.com/yourusername/gpay-remit/utils"
+)
+
+func TestSimulateRemittance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	user := models.User{Email: "simulate@example.com", Name: "Simulate", StellarAddress: "GSIMULATE", PasswordHash: "x"}
+	require.NoError(t, db.Create(&user).Error)
+
+	cfg := &config.Config{DailyLimits: map[string]float64{"XLM": 1000, "USDC": 1000}, PlatformFeeBps: 50}
+	var built int
+	handler := &RemittanceHandler{
+		db:     db,
+		config: cfg,
+		fees:   services.NewFeeService(cfg),
+		rates:  services.StaticRateSource{"USDC/NGN": 1500},
+		stellarClient: &MockStellarClient{
+			ValidateAccountFunc: func(accountID string) error { return nil },
+			GetBalancesFunc: func(accountID string) ([]utils.AccountBalance, error) {
+				return []utils.AccountBalance{{AssetType: "native", AssetCode: "XLM", Balance: "100"}}, nil
+			},
+			BuildEscrowTxFunc: func(sender, recipient, assetCode, issuer, amount string, memo txnbuild.Memo) (string, error) {
+				built++
+				return "base64_xdr", nil
+			},
+		},
+	}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", user.ID)
+		c.Next()
+	})
+	router.POST("/remittances/simulate", handler.SimulateRemittance)
+	router.POST("/remittances/create", handler.CreateRemittance)
+
+	sender, _ := keypair.Random()
+	recipient, _ := keypair.Random()
+	issuer, _ := keypair.Random()
+	post := func(path string, req CreateRemittanceRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodPost, path, bytes.NewBuffer(body))
+		router.ServeHTTP(w, r)
+		return w
+	}
+	simulate := func(path string, req CreateRemittanceRequest) SimulationResult {
+		w := post(path, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var result SimulationResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		return result
+	}
+	countPayments := func() int64 {
+		var n int64
+		db.Model(&models.Payment{}).Count(&n)
+		return n
+	}
+
+	t.Run("Would succeed", func(t *testing.T) {
+		before := countPayments()
+		result := simulate("/remittances/simulate?target_currency=NGN", CreateRemittanceRequest{
+			SenderAccount:    sender.Address(),
+			RecipientAccount: recipient.Address(),
+			Amount:           100,
+			AssetCode:        "USDC",
+			AssetIssuer:      issuer.Address(),
+		})
+
+		assert.True(t, result.WouldSucceed)
+		assert.Empty(t, result.BlockingReasons)
+		require.NotNil(t, result.FeeBreakdown)
+		assert.Greater(t, result.FeeBreakdown.TotalFee, 0.0)
+		assert.InDelta(t, 100-result.FeeBreakdown.TotalFee, result.NetAmount, 1e-7)
+		assert.Equal(t, "NGN", result.TargetCurrency)
+		assert.Equal(t, 1500.0, result.QuotedRate)
+		assert.Equal(t, 150000.0, result.ConvertedAmount)
+		assert.True(t, result.TrustlineMissing)
+		assert.Len(t, result.Warnings, 1)
+
+		assert.Equal(t, before, countPayments(), "a simulation creates no payment")
+		assert.Zero(t, built, "a simulation builds no transaction")
+	})
+
+	t.Run("Would be blocked by the daily limit", func(t *testing.T) {
+		db.Create(&models.Payment{SenderID: user.ID, Amount: 950, Currency: "XLM", Status: "completed"})
+		req := CreateRemittanceRequest{
+			SenderAccount:    sender.Address(),
+			RecipientAccount: recipient.Address(),
+			Amount:           100,
+			AssetCode:        "XLM",
+		}
+
+		result := simulate("/remittances/simulate", req)
+		assert.False(t, result.WouldSucceed)
+		require.Len(t, result.BlockingReasons, 1)
+		assert.Equal(t, errors.CodeDailyLimitExceeded, result.BlockingReasons[0].Code)
+		assert.NotNil(t, result.FeeBreakdown, "fees are still previewed")
+
+		// The real create path refuses it with the same code.
+		w := post("/remittances/create", req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		var resp middleware.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, result.BlockingReasons[0].Code, resp.Error.Code)
+		assert.Equal(t, result.BlockingReasons[0].Message, resp.Error.Message)
+	})
+
+	t.Run("Every blocking reason is listed", func(t *testing.T) {
+		result := simulate("/remittances/simulate", CreateRemittanceRequest{
+			SenderAccount:    sender.Address(),
+			RecipientAccount: recipient.Address(),
+			Amount:           100,
+			AssetCode:        "XLM",
+			Memo:             "this memo is far longer than twenty-eight bytes",
+		})
+		assert.False(t, result.WouldSucceed)
+		require.Len(t, result.BlockingReasons, 2)
+		assert.Equal(t, "Invalid memo", result.BlockingReasons[0].Message)
+		assert.Equal(t, errors.CodeDailyLimitExceeded, result.BlockingReasons[1].Code)
+	})
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/services"
+	"gorm.io/gorm"
+)
+
+// defaultStreamHeartbeat is used when no heartbeat interval is configured.
+const defaultStreamHeartbeat = 15 * time.Second
+
+// StreamRemittance streams a remittance's status changes as Server-Sent
+// Events. The first "status" event carries the current status; each later
+// one follows a transition. Idle connections get a comment every heartbeat
+// interval. The stream ends when the client disconnects. Only the sender,
+// the recipient, or an admin may watch a remittance.
+func (h *RemittanceHandler) StreamRemittance(c *gin.Context) {
+	if h.events == nil {
+		c.Error(errors.NewInternalError("Status streaming is not available", nil))
+		return
+	}
+
+	var payment models.Payment
+	if err := h.db.First(&payment, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("Payment not found").WithKey("error.payment_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch payment", err))
+		}
+		return
+	}
+	if !isSenderOrAdmin(c, &payment) {
+		if userID, _ := c.Get("userID"); userID != payment.RecipientID {
+			c.Error(errors.NewForbiddenError("Only the sender, the recipient, or an admin can watch this remittance"))
+			return
+		}
+	}
+
+	// Subscribe before reading the status sent first, so a transition in
+	// between is delivered rather than lost.
+	events, unsubscribe := h.events.Subscribe(payment.ID)
+	defer unsubscribe()
+	if err := h.db.First(&payment, payment.ID).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to fetch payment", err))
+		return
+	}
+
+	heartbeat := h.config.StreamHeartbeatInterval
+	if heartbeat <= 0 {
+		heartbeat = defaultStreamHeartbeat
+	}
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	// Stop nginx and similar proxies from buffering the stream.
+	header.Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	c.SSEvent("status", services.StatusEvent{
+		PaymentID: payment.ID,
+		Status:    payment.Status,
+		TxHash:    payment.TxHash,
+		At:        payment.UpdatedAt.UTC(),
+	})
+	c.Writer.Flush()
+
+	log := logger.Log.WithField("payment_id", payment.ID).WithField("request_id", c.GetString("requestID"))
+	log.Debug("Remittance status stream opened")
+	defer log.Debug("Remittance status stream closed")
+
+	done := c.Request.Context().Done()
+	for {
+		select {
+		case <-done:
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			c.SSEvent("status", event)
+		case <-ticker.C:
+			if _, err := c.Writer.WriteString(": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
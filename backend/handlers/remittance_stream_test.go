@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/services"
+)
+
+// readSSE reads lines from r until a complete event or comment arrives and
+// returns its event name and data; comments come back with event ":".
+func readSSE(t *testing.T, r *bufio.Reader) (event, data string) {
+	t.Helper()
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			if event != "" || data != "" {
+				return event, data
+			}
+		case strings.HasPrefix(line, ":"):
+			event = ":"
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		}
+	}
+}
+
+func TestStreamRemittance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	// Every request must see the same in-memory database.
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	events := services.NewStatusBroker()
+	handler := &RemittanceHandler{
+		db:     db,
+		config: &config.Config{StreamHeartbeatInterval: time.Hour},
+		events: events,
+	}
+
+	newServer := func(userID uint, role string, cfg *config.Config) *httptest.Server {
+		h := *handler
+		if cfg != nil {
+			h.config = cfg
+		}
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", userID)
+			c.Set("role", role)
+			c.Next()
+		})
+		router.GET("/remittances/:id/stream", h.StreamRemittance)
+		router.POST("/remittances/:id/cancel", h.CancelRemittance)
+		server := httptest.NewServer(router)
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	open := func(ctx context.Context, server *httptest.Server, id uint) *http.Response {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/remittances/%d/stream", server.URL, id), nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Pushes a status change and cleans up on disconnect", func(t *testing.T) {
+		payment := models.Payment{SenderID: 1, RecipientID: 2, Amount: 10, Currency: "USD", Status: "pending"}
+		require.NoError(t, db.Create(&payment).Error)
+		server := newServer(1, "user", nil)
+
+		ctx, disconnect := context.WithCancel(context.Background())
+		defer disconnect()
+		resp := open(ctx, server, payment.ID)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		reader := bufio.NewReader(resp.Body)
+		event, data := readSSE(t, reader)
+		assert.Equal(t, "status", event)
+		var current services.StatusEvent
+		require.NoError(t, json.Unmarshal([]byte(data), &current))
+		assert.Equal(t, payment.ID, current.PaymentID)
+		assert.Equal(t, "pending", current.Status)
+		require.Equal(t, 1, events.Subscribers(payment.ID))
+
+		cancelResp, err := http.Post(fmt.Sprintf("%s/remittances/%d/cancel", server.URL, payment.ID), "application/json", nil)
+		require.NoError(t, err)
+		cancelResp.Body.Close()
+		require.Equal(t, http.StatusOK, cancelResp.StatusCode)
+
+		event, data = readSSE(t, reader)
+		assert.Equal(t, "status", event)
+		var changed services.StatusEvent
+		require.NoError(t, json.Unmarshal([]byte(data), &changed))
+		assert.Equal(t, payment.ID, changed.PaymentID)
+		assert.Equal(t, "cancelled", changed.Status)
+
+		disconnect()
+		assert.Eventually(t, func() bool { return events.Subscribers(payment.ID) == 0 }, time.Second, 10*time.Millisecond,
+			"the subscription ends with the connection")
+	})
+
+	t.Run("Idle streams get heartbeats", func(t *testing.T) {
+		payment := models.Payment{SenderID: 1, RecipientID: 2, Amount: 10, Currency: "USD", Status: "processing"}
+		require.NoError(t, db.Create(&payment).Error)
+		server := newServer(2, "user", &config.Config{StreamHeartbeatInterval: 20 * time.Millisecond})
+
+		ctx, disconnect := context.WithCancel(context.Background())
+		defer disconnect()
+		resp := open(ctx, server, payment.ID)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode, "the recipient may watch")
+
+		reader := bufio.NewReader(resp.Body)
+		event, _ := readSSE(t, reader)
+		assert.Equal(t, "status", event)
+		event, _ = readSSE(t, reader)
+		assert.Equal(t, ":", event)
+	})
+
+	t.Run("Other users are forbidden", func(t *testing.T) {
+		payment := models.Payment{SenderID: 1, RecipientID: 2, Amount: 10, Currency: "USD", Status: "pending"}
+		require.NoError(t, db.Create(&payment).Error)
+		server := newServer(3, "user", nil)
+
+		resp := open(context.Background(), server, payment.ID)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, 0, events.Subscribers(payment.ID))
+	})
+
+	t.Run("Unknown remittance", func(t *testing.T) {
+		resp := open(context.Background(), newServer(1, "admin", nil), 99999)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
@@ -0,0 +1,609 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/utils"
+)
+
+func TestGetSigningDetails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	cfg := &config.Config{NetworkPassphrase: network.TestNetworkPassphrase}
+	handler := &RemittanceHandler{db: db, config: cfg}
+
+	sourceKP, _ := keypair.Random()
+	destKP, _ := keypair.Random()
+	tx, err := utils.NewStellarClient("https://horizon-testnet.stellar.org", cfg.NetworkPassphrase).
+		BuildPaymentTx(context.Background(), &txnbuild.SimpleAccount{AccountID: sourceKP.Address(), Sequence: 1}, destKP.Address(), "XLM", "", "25", nil)
+	assert.NoError(t, err)
+	envelope, _ := tx.Base64()
+
+	payment := models.Payment{SenderID: 1, SenderAccount: sourceKP.Address(), RecipientAccount: destKP.Address(), Amount: 25, Currency: "XLM", Status: "pending", TxEnvelope: envelope}
+	db.Create(&payment)
+
+	newRouter := func(userID uint) *gin.Engine {
+		router := newTestRouter(userID, "user")
+		router.GET("/remittances/:id/signing-details", handler.GetSigningDetails)
+		return router
+	}
+
+	t.Run("Sender sees decoded envelope", func(t *testing.T) {
+		w := serveJSON(newRouter(1), http.MethodGet, fmt.Sprintf("/remittances/%d/signing-details", payment.ID), nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			SigningDetails utils.TransactionSummary `json:"signing_details"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.Equal(t, "testnet", resp.SigningDetails.Network)
+		assert.Equal(t, sourceKP.Address(), resp.SigningDetails.SourceAccount)
+		if assert.Len(t, resp.SigningDetails.Operations, 1) {
+			assert.Equal(t, destKP.Address(), resp.SigningDetails.Operations[0].Destination)
+			assert.Equal(t, "25.0000000", resp.SigningDetails.Operations[0].Amount)
+			assert.Equal(t, "XLM", resp.SigningDetails.Operations[0].AssetCode)
+		}
+	})
+
+	t.Run("Other user forbidden", func(t *testing.T) {
+		w := serveJSON(newRouter(2), http.MethodGet, fmt.Sprintf("/remittances/%d/signing-details", payment.ID), nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+// paymentEnvelope builds an unsigned XLM payment envelope sourced from
+// source, with its operation sourced from opSource when set, and returns it
+// together with a copy signed by signers.
+func paymentEnvelope(t *testing.T, source, opSource string, signers ...*keypair.Full) (envelope, signed string) {
+	destKP, _ := keypair.Random()
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: source, Sequence: 1},
+		IncrementSequenceNum: true,
+		BaseFee:              txnbuild.MinBaseFee,
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+		Operations: []txnbuild.Operation{&txnbuild.Payment{
+			Destination:   destKP.Address(),
+			Amount:        "5",
+			Asset:         txnbuild.NativeAsset{},
+			SourceAccount: opSource,
+		}},
+	})
+	assert.NoError(t, err)
+	envelope, _ = tx.Base64()
+	tx, err = tx.Sign(network.TestNetworkPassphrase, signers...)
+	assert.NoError(t, err)
+	signed, _ = tx.Base64()
+	return envelope, signed
+}
+
+func TestSubmitRemittanceWait(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	cfg := &config.Config{
+		NetworkPassphrase:  network.TestNetworkPassphrase,
+		SubmitMaxWait:      time.Second,
+		SubmitPollInterval: 10 * time.Millisecond,
+	}
+
+	sourceKP, _ := keypair.Random()
+	destKP, _ := keypair.Random()
+
+	// newPayment stores an unsigned envelope and returns the payment with its signed counterpart.
+	newPayment := func() (models.Payment, string) {
+		tx, err := utils.NewStellarClient("https://horizon-testnet.stellar.org", cfg.NetworkPassphrase).
+			BuildPaymentTx(context.Background(), &txnbuild.SimpleAccount{AccountID: sourceKP.Address(), Sequence: 1}, destKP.Address(), "XLM", "", "5", nil)
+		assert.NoError(t, err)
+		envelope, _ := tx.Base64()
+		signedTx, err := tx.Sign(cfg.NetworkPassphrase, sourceKP)
+		assert.NoError(t, err)
+		signed, _ := signedTx.Base64()
+
+		payment := models.Payment{SenderID: 1, SenderAccount: sourceKP.Address(), RecipientAccount: destKP.Address(), Amount: 5, Currency: "XLM", Status: "pending", TxEnvelope: envelope}
+		db.Create(&payment)
+		return payment, signed
+	}
+
+	newRouter := func(stellar *MockStellarClient) *gin.Engine {
+		handler := &RemittanceHandler{db: db, config: cfg, stellarClient: stellar}
+		router := newTestRouter(1, "user")
+		router.POST("/remittances/:id/submit", handler.SubmitRemittance)
+		return router
+	}
+
+	submit := func(router *gin.Engine, id uint, signed, wait string) *httptest.ResponseRecorder {
+		return serveJSON(router, http.MethodPost, fmt.Sprintf("/remittances/%d/submit?wait=%s", id, wait), SubmitRemittanceRequest{SignedXDR: signed})
+	}
+
+	t.Run("Quickly confirmed transaction returns completed", func(t *testing.T) {
+		payment, signed := newPayment()
+		polls := 0
+		router := newRouter(&MockStellarClient{
+			SubmitTransactionFunc: func(signedXDR string) (string, error) { return "fasthash", nil },
+			GetTransactionStatusFunc: func(txHash string) (utils.TxStatus, error) {
+				polls++
+				if polls < 2 {
+					return utils.TxStatusPending, nil
+				}
+				return utils.TxStatusSuccess, nil
+			},
+		})
+
+		w := submit(router, payment.ID, signed, "500ms")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var got models.Payment
+		db.First(&got, payment.ID)
+		assert.Equal(t, "completed", got.Status)
+		assert.Equal(t, "fasthash", got.TxHash)
+	})
+
+	t.Run("Slow transaction returns processing at timeout", func(t *testing.T) {
+		payment, signed := newPayment()
+		router := newRouter(&MockStellarClient{
+			SubmitTransactionFunc: func(signedXDR string) (string, error) { return "slowhash", nil },
+			GetTransactionStatusFunc: func(txHash string) (utils.TxStatus, error) {
+				return utils.TxStatusPending, nil
+			},
+		})
+
+		start := time.Now()
+		w := submit(router, payment.ID, signed, "50ms")
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Less(t, time.Since(start), cfg.SubmitMaxWait)
+
+		var got models.Payment
+		db.First(&got, payment.ID)
+		assert.Equal(t, "processing", got.Status)
+		assert.Equal(t, "slowhash", got.TxHash)
+	})
+
+	t.Run("Wait is capped at the configured maximum", func(t *testing.T) {
+		handler := &RemittanceHandler{config: cfg}
+		wait, err := handler.submitWait("10m")
+		assert.NoError(t, err)
+		assert.Equal(t, cfg.SubmitMaxWait, wait)
+
+		_, err = handler.submitWait("soon")
+		assert.Error(t, err)
+	})
+}
+
+func TestSubmitRemittanceRejectedOperations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	cfg := &config.Config{NetworkPassphrase: network.TestNetworkPassphrase}
+
+	sourceKP, _ := keypair.Random()
+	envelope, signed := paymentEnvelope(t, sourceKP.Address(), "", sourceKP)
+	payments := []models.Payment{
+		{SenderID: 1, RecipientAccount: "GA1", Amount: 10, Currency: "XLM", Status: "pending", BatchID: "batch-1", TxEnvelope: envelope},
+		{SenderID: 1, RecipientAccount: "GA2", Amount: 20, Currency: "XLM", Status: "pending", BatchID: "batch-1", TxEnvelope: envelope},
+		{SenderID: 1, RecipientAccount: "GA3", Amount: 30, Currency: "XLM", Status: "pending", BatchID: "batch-1", TxEnvelope: envelope},
+	}
+	assert.NoError(t, db.Create(&payments).Error)
+
+	opResult := func(code xdr.PaymentResultCode) xdr.OperationResult {
+		return xdr.OperationResult{
+			Code: xdr.OperationResultCodeOpInner,
+			Tr: &xdr.OperationResultTr{
+				Type:          xdr.OperationTypePayment,
+				PaymentResult: &xdr.PaymentResult{Code: code},
+			},
+		}
+	}
+	ops := []xdr.OperationResult{
+		opResult(xdr.PaymentResultCodePaymentSuccess),
+		opResult(xdr.PaymentResultCodePaymentNoDestination),
+		opResult(xdr.PaymentResultCodePaymentSuccess),
+	}
+	resultXDR, err := xdr.MarshalBase64(xdr.TransactionResult{
+		FeeCharged: 300,
+		Result:     xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxFailed, Results: &ops},
+	})
+	assert.NoError(t, err)
+
+	mockStellar := &MockStellarClient{
+		SubmitTransactionFunc: func(signedXDR string) (string, error) {
+			return "", &utils.SubmitError{ResultXDR: resultXDR, Err: assert.AnError}
+		},
+	}
+	handler := &RemittanceHandler{db: db, config: cfg, stellarClient: mockStellar}
+	router := newTestRouter(1, "user")
+	router.POST("/remittances/:id/submit", handler.SubmitRemittance)
+
+	w := serveJSON(router, http.MethodPost, fmt.Sprintf("/remittances/%d/submit", payments[0].ID), SubmitRemittanceRequest{SignedXDR: signed})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var resp struct {
+		Error struct {
+			Code    string `json:"code"`
+			Details struct {
+				ResultCode string             `json:"result_code"`
+				Operations []OperationOutcome `json:"operations"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, string(errors.CodeTransactionFailed), resp.Error.Code)
+	assert.Equal(t, "tx_failed", resp.Error.Details.ResultCode)
+	if assert.Len(t, resp.Error.Details.Operations, 3) {
+		assert.Equal(t, payments[1].ID, resp.Error.Details.Operations[1].PaymentID)
+		assert.Equal(t, "op_no_destination", resp.Error.Details.Operations[1].Code)
+		assert.False(t, resp.Error.Details.Operations[1].Success)
+		assert.True(t, resp.Error.Details.Operations[0].Success)
+	}
+
+	wantReasons := []string{"tx_failed", "op_no_destination", "tx_failed"}
+	for i, p := range payments {
+		var stored models.Payment
+		db.First(&stored, p.ID)
+		assert.Equal(t, "failed", stored.Status)
+		assert.Equal(t, wantReasons[i], stored.FailureReason)
+		assert.Equal(t, 2, stored.Version)
+	}
+
+	t.Run("Bad sequence leaves the remittance pending", func(t *testing.T) {
+		payment := models.Payment{SenderID: 1, Amount: 5, Currency: "XLM", Status: "pending", TxEnvelope: envelope}
+		db.Create(&payment)
+		badSeq, _ := xdr.MarshalBase64(xdr.TransactionResult{
+			FeeCharged: 100,
+			Result:     xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxBadSeq},
+		})
+		mockStellar.SubmitTransactionFunc = func(signedXDR string) (string, error) {
+			return "", &utils.SubmitError{ResultXDR: badSeq, Err: assert.AnError}
+		}
+		mockStellar.GetTransactionStatusFunc = func(txHash string) (utils.TxStatus, error) {
+			return utils.TxStatusPending, nil
+		}
+
+		w := serveJSON(router, http.MethodPost, fmt.Sprintf("/remittances/%d/submit", payment.ID), SubmitRemittanceRequest{SignedXDR: signed})
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		var stored models.Payment
+		db.First(&stored, payment.ID)
+		assert.Equal(t, "pending", stored.Status)
+	})
+}
+
+func TestSubmitRemittanceTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	cfg := &config.Config{NetworkPassphrase: network.TestNetworkPassphrase}
+
+	sourceKP, _ := keypair.Random()
+	destKP, _ := keypair.Random()
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: sourceKP.Address(), Sequence: 1},
+		IncrementSequenceNum: true,
+		BaseFee:              txnbuild.MinBaseFee,
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+		Operations: []txnbuild.Operation{&txnbuild.Payment{
+			Destination: destKP.Address(),
+			Amount:      "5",
+			Asset:       txnbuild.NativeAsset{},
+		}},
+	})
+	assert.NoError(t, err)
+	envelope, _ := tx.Base64()
+	tx, err = tx.Sign(cfg.NetworkPassphrase, sourceKP)
+	assert.NoError(t, err)
+	signed, _ := tx.Base64()
+	wantHash, _ := tx.HashHex(cfg.NetworkPassphrase)
+
+	mockStellar := &MockStellarClient{
+		SubmitTransactionFunc: func(signedXDR string) (string, error) {
+			return "", fmt.Errorf("%w: horizon did not respond", utils.ErrSubmitTimeout)
+		},
+	}
+	handler := &RemittanceHandler{db: db, config: cfg, stellarClient: mockStellar}
+	router := newTestRouter(1, "user")
+	router.POST("/remittances/:id/submit", handler.SubmitRemittance)
+
+	submit := func() (*httptest.ResponseRecorder, models.Payment) {
+		payment := models.Payment{SenderID: 1, Amount: 5, Currency: "XLM", Status: "pending", TxEnvelope: envelope}
+		db.Create(&payment)
+		w := serveJSON(router, http.MethodPost, fmt.Sprintf("/remittances/%d/submit", payment.ID), SubmitRemittanceRequest{SignedXDR: signed})
+		db.First(&payment, payment.ID)
+		return w, payment
+	}
+
+	t.Run("Timeout leaves the remittance processing with its hash", func(t *testing.T) {
+		w, stored := submit()
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, "processing", stored.Status)
+		assert.Equal(t, wantHash, stored.TxHash)
+		assert.Empty(t, stored.FailureReason)
+	})
+
+	t.Run("Other submission errors leave it pending", func(t *testing.T) {
+		mockStellar.SubmitTransactionFunc = func(signedXDR string) (string, error) {
+			return "", assert.AnError
+		}
+		w, stored := submit()
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "pending", stored.Status)
+		assert.Empty(t, stored.TxHash)
+	})
+}
+
+func TestSubmitRemittanceDuplicate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	cfg := &config.Config{NetworkPassphrase: network.TestNetworkPassphrase}
+
+	sourceKP, _ := keypair.Random()
+	destKP, _ := keypair.Random()
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: sourceKP.Address(), Sequence: 1},
+		IncrementSequenceNum: true,
+		BaseFee:              txnbuild.MinBaseFee,
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+		Operations: []txnbuild.Operation{&txnbuild.Payment{
+			Destination: destKP.Address(),
+			Amount:      "5",
+			Asset:       txnbuild.NativeAsset{},
+		}},
+	})
+	assert.NoError(t, err)
+	envelope, _ := tx.Base64()
+	tx, err = tx.Sign(cfg.NetworkPassphrase, sourceKP)
+	assert.NoError(t, err)
+	signed, _ := tx.Base64()
+	wantHash, _ := tx.HashHex(cfg.NetworkPassphrase)
+
+	badSeq, _ := xdr.MarshalBase64(xdr.TransactionResult{
+		FeeCharged: 100,
+		Result:     xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxBadSeq},
+	})
+	submitted := 0
+	mockStellar := &MockStellarClient{
+		SubmitTransactionFunc: func(signedXDR string) (string, error) {
+			submitted++
+			return wantHash, nil
+		},
+	}
+	handler := &RemittanceHandler{db: db, config: cfg, stellarClient: mockStellar}
+	router := newTestRouter(1, "user")
+	router.POST("/remittances/:id/submit", handler.SubmitRemittance)
+
+	submit := func(paymentID uint) *httptest.ResponseRecorder {
+		return serveJSON(router, http.MethodPost, fmt.Sprintf("/remittances/%d/submit", paymentID), SubmitRemittanceRequest{SignedXDR: signed})
+	}
+
+	t.Run("Resubmission returns the recorded outcome", func(t *testing.T) {
+		payment := models.Payment{SenderID: 1, Amount: 5, Currency: "XLM", Status: "pending", TxEnvelope: envelope}
+		db.Create(&payment)
+
+		assert.Equal(t, http.StatusAccepted, submit(payment.ID).Code)
+		w := submit(payment.ID)
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, 1, submitted, "the duplicate must not be broadcast")
+
+		var resp models.Payment
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.Equal(t, wantHash, resp.TxHash)
+		assert.Equal(t, "processing", resp.Status)
+	})
+
+	t.Run("Resubmission of a rejected transaction reports the rejection", func(t *testing.T) {
+		submitted = 0
+		payment := models.Payment{SenderID: 1, Amount: 5, Currency: "XLM", Status: "failed", TxHash: wantHash, FailureReason: "op_underfunded", TxEnvelope: envelope}
+		db.Create(&payment)
+
+		w := submit(payment.ID)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "op_underfunded")
+		assert.Equal(t, 0, submitted)
+	})
+
+	t.Run("Bad sequence on a landed transaction is success", func(t *testing.T) {
+		mockStellar.SubmitTransactionFunc = func(signedXDR string) (string, error) {
+			return "", &utils.SubmitError{ResultXDR: badSeq, Err: assert.AnError}
+		}
+		mockStellar.GetTransactionStatusFunc = func(txHash string) (utils.TxStatus, error) {
+			if txHash == wantHash {
+				return utils.TxStatusSuccess, nil
+			}
+			return utils.TxStatusPending, nil
+		}
+		payment := models.Payment{SenderID: 1, Amount: 5, Currency: "XLM", Status: "pending", TxEnvelope: envelope}
+		db.Create(&payment)
+
+		w := submit(payment.ID)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var stored models.Payment
+		db.First(&stored, payment.ID)
+		assert.Equal(t, "completed", stored.Status)
+		assert.Equal(t, wantHash, stored.TxHash)
+		assert.Empty(t, stored.FailureReason)
+	})
+
+	t.Run("Bad sequence on a transaction that never landed is a conflict", func(t *testing.T) {
+		mockStellar.GetTransactionStatusFunc = func(txHash string) (utils.TxStatus, error) {
+			return utils.TxStatusPending, nil
+		}
+		payment := models.Payment{SenderID: 1, Amount: 5, Currency: "XLM", Status: "pending", TxEnvelope: envelope}
+		db.Create(&payment)
+
+		w := submit(payment.ID)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		var stored models.Payment
+		db.First(&stored, payment.ID)
+		assert.Equal(t, "pending", stored.Status)
+		assert.Empty(t, stored.TxHash)
+	})
+}
+
+func TestSigningCallback(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	cfg := &config.Config{NetworkPassphrase: network.TestNetworkPassphrase, SigningCallbackSecret: "custody-secret"}
+
+	sourceKP, _ := keypair.Random()
+	destKP, _ := keypair.Random()
+	tx, err := utils.NewStellarClient("https://horizon-testnet.stellar.org", cfg.NetworkPassphrase).
+		BuildPaymentTx(context.Background(), &txnbuild.SimpleAccount{AccountID: sourceKP.Address(), Sequence: 1}, destKP.Address(), "XLM", "", "5", nil)
+	assert.NoError(t, err)
+	envelope, _ := tx.Base64()
+	signedTx, err := tx.Sign(cfg.NetworkPassphrase, sourceKP)
+	assert.NoError(t, err)
+	signed, _ := signedTx.Base64()
+
+	payment := models.Payment{SenderID: 1, SenderAccount: sourceKP.Address(), RecipientAccount: destKP.Address(), Amount: 5, Currency: "XLM", Status: "pending", TxEnvelope: envelope}
+	db.Create(&payment)
+
+	submitted := 0
+	handler := &RemittanceHandler{db: db, config: cfg, stellarClient: &MockStellarClient{
+		SubmitTransactionFunc: func(signedXDR string) (string, error) {
+			submitted++
+			return "custody_tx_hash", nil
+		},
+	}}
+	router := newTestRouter(0, "")
+	router.POST("/internal/signing-callback",
+		middleware.SharedSecretAuth("X-Signing-Callback-Secret", cfg.SigningCallbackSecret),
+		handler.SigningCallback)
+
+	callback := func(secret string, signedXDR string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(SigningCallbackRequest{RemittanceID: payment.ID, SignedXDR: signedXDR})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/internal/signing-callback", bytes.NewBuffer(body))
+		if secret != "" {
+			req.Header.Set("X-Signing-Callback-Secret", secret)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Unauthenticated callback is rejected", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, callback("", signed).Code)
+		assert.Equal(t, http.StatusUnauthorized, callback("wrong-secret", signed).Code)
+
+		var stored models.Payment
+		db.First(&stored, payment.ID)
+		assert.Equal(t, "pending", stored.Status)
+		assert.Equal(t, 0, submitted)
+	})
+
+	t.Run("Envelope that does not match is rejected", func(t *testing.T) {
+		otherTx, _ := utils.NewStellarClient("https://horizon-testnet.stellar.org", cfg.NetworkPassphrase).
+			BuildPaymentTx(context.Background(), &txnbuild.SimpleAccount{AccountID: sourceKP.Address(), Sequence: 1}, destKP.Address(), "XLM", "", "6", nil)
+		otherSigned, _ := otherTx.Sign(cfg.NetworkPassphrase, sourceKP)
+		other, _ := otherSigned.Base64()
+
+		assert.Equal(t, http.StatusBadRequest, callback("custody-secret", other).Code)
+		assert.Equal(t, 0, submitted)
+	})
+
+	t.Run("Envelope not signed by the sender is rejected", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, callback("custody-secret", envelope).Code)
+
+		otherKP, _ := keypair.Random()
+		wrongTx, _ := tx.Sign(cfg.NetworkPassphrase, otherKP)
+		wrong, _ := wrongTx.Base64()
+		w := callback("custody-secret", wrong)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "not signed by the sender")
+		assert.Equal(t, 0, submitted)
+	})
+
+	t.Run("Valid callback advances the payment", func(t *testing.T) {
+		w := callback("custody-secret", signed)
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, 1, submitted)
+
+		var stored models.Payment
+		db.First(&stored, payment.ID)
+		assert.Equal(t, "processing", stored.Status)
+		assert.Equal(t, "custody_tx_hash", stored.TxHash)
+
+		// A repeated callback does not submit twice.
+		assert.Equal(t, http.StatusConflict, callback("custody-secret", signed).Code)
+		assert.Equal(t, 1, submitted)
+	})
+}
+
+func TestSubmitRemittanceSourceAllowlist(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	allowedKP, _ := keypair.Random()
+	otherKP, _ := keypair.Random()
+	cfg := &config.Config{
+		NetworkPassphrase:     network.TestNetworkPassphrase,
+		SubmitSourceAllowlist: []string{allowedKP.Address()},
+	}
+
+	submitted := 0
+	handler := &RemittanceHandler{db: db, config: cfg, stellarClient: &MockStellarClient{
+		SubmitTransactionFunc: func(signedXDR string) (string, error) {
+			submitted++
+			return "allowed_hash", nil
+		},
+	}}
+	router := newTestRouter(1, "user")
+	router.POST("/remittances/:id/submit", handler.SubmitRemittance)
+
+	submit := func(envelope, signed string) (*httptest.ResponseRecorder, models.Payment) {
+		payment := models.Payment{SenderID: 1, Amount: 5, Currency: "XLM", Status: "pending", TxEnvelope: envelope}
+		db.Create(&payment)
+		w := serveJSON(router, http.MethodPost, fmt.Sprintf("/remittances/%d/submit", payment.ID), SubmitRemittanceRequest{SignedXDR: signed})
+		db.First(&payment, payment.ID)
+		return w, payment
+	}
+
+	t.Run("Envelope from a non-allowlisted source is rejected", func(t *testing.T) {
+		w, payment := submit(paymentEnvelope(t, otherKP.Address(), "", otherKP))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), otherKP.Address())
+		assert.Equal(t, "pending", payment.Status)
+		assert.Equal(t, 0, submitted)
+	})
+
+	t.Run("Operation sourced outside the allowlist is rejected", func(t *testing.T) {
+		w, _ := submit(paymentEnvelope(t, allowedKP.Address(), otherKP.Address(), allowedKP, otherKP))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, 0, submitted)
+	})
+
+	t.Run("Allowlisted source passes", func(t *testing.T) {
+		w, payment := submit(paymentEnvelope(t, allowedKP.Address(), "", allowedKP))
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, "processing", payment.Status)
+		assert.Equal(t, 1, submitted)
+	})
+
+	t.Run("Remittance without a stored envelope is rejected", func(t *testing.T) {
+		_, signed := paymentEnvelope(t, allowedKP.Address(), "", allowedKP)
+		w, payment := submit("", signed)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "pending", payment.Status)
+		assert.Equal(t, 1, submitted)
+	})
+
+	t.Run("Signed copy of another envelope is rejected", func(t *testing.T) {
+		envelope, _ := paymentEnvelope(t, allowedKP.Address(), "", allowedKP)
+		_, signed := paymentEnvelope(t, allowedKP.Address(), "", allowedKP)
+		w, payment := submit(envelope, signed)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "pending", payment.Status)
+		assert.Equal(t, 1, submitted)
+	})
+}
@@ -1,19 +1,27 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 	"github.com/yourusername/gpay-remit/config"
 	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/logger"
 	"github.com/yourusername/gpay-remit/middleware"
 	"github.com/yourusername/gpay-remit/models"
 	"github.com/yourusername/gpay-remit/services"
 	"github.com/yourusername/gpay-remit/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type RemittanceHandler struct {
@@ -21,16 +29,31 @@ type RemittanceHandler struct {
 	config        *config.Config
 	stellarClient utils.StellarClientInterface
 	fees          *services.FeeService
-	emailService  *services.EmailService
+	notifier      *services.NotificationService
+	// messenger sends templated lifecycle emails and SMS; nil disables them.
+	messenger utils.Notifier
+	invoices  *services.InvoiceService
+	// rates quotes cross-currency remittances; nil disables live quotes.
+	rates services.RateSource
+	// events carries status changes to StreamRemittance subscribers; nil
+	// disables the stream.
+	events *services.StatusBroker
 }
 
 func NewRemittanceHandler(db *gorm.DB, cfg *config.Config) *RemittanceHandler {
+	emails := services.NewEmailService(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPassword, cfg.SMTPFrom, cfg.EmailEnabled)
 	return &RemittanceHandler{
 		db:            db,
 		config:        cfg,
-		stellarClient: utils.NewStellarClient(cfg.HorizonURL, cfg.NetworkPassphrase),
+		stellarClient: utils.NewStellarClient(cfg.HorizonURL, cfg.NetworkPassphrase, utils.WithBaseReserve(cfg.BaseReserveXLM), utils.WithSorobanRPC(cfg.SorobanRPCURL), utils.WithBaseFee(cfg.BaseFee), utils.WithAccountCache(cfg.AccountCacheTTL, cfg.AccountCacheFailureTTL, cfg.AccountCacheSize), utils.WithHorizonHTTP(cfg.HorizonTimeout, cfg.HorizonReadRetries, cfg.HorizonRetryBackoff), utils.WithReclaimWindow(cfg.ClaimableReclaimWindow), utils.WithDryRun(cfg.DryRun)),
 		fees:          services.NewFeeService(cfg),
-		emailService:  services.NewEmailService(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPassword, cfg.SMTPFrom, cfg.EmailEnabled),
+		// Payment emails go out through the messenger, which localizes them,
+		// so the notification service only handles in-app and webhooks.
+		notifier:  services.NewNotificationService(db, nil, services.NewWebhookDeliveryService(db)),
+		messenger: utils.NewMultiNotifier(utils.NewEmailNotifier(emails), utils.NewSMSNotifier()),
+		invoices:  services.NewInvoiceService(db),
+		rates:     services.StaticRateSource(cfg.FXRates),
+		events:    services.PaymentStatusEvents,
 	}
 }
 
@@ -60,18 +83,28 @@ func Paginate(c *gin.Context) func(db *gorm.DB) *gorm.DB {
 }
 
 type CreateRemittanceRequest struct {
-	SenderAccount   string                 `json:"sender_account" binding:"required"`
-	RecipientAccount string                `json:"recipient_account" binding:"required"`
-	Amount          float64                `json:"amount" binding:"required,gt=0"`
-	AssetCode       string                 `json:"asset_code" binding:"required"`
-	AssetIssuer     string                 `json:"asset_issuer"`
-	Conditions      map[string]interface{} `json:"conditions"`
-	Notes           string                 `json:"notes"`
+	SenderAccount    string                 `json:"sender_account" binding:"required"`
+	RecipientAccount string                 `json:"recipient_account" binding:"required"`
+	Amount           float64                `json:"amount" binding:"required,gt=0"`
+	AssetCode        string                 `json:"asset_code" binding:"required"`
+	AssetIssuer      string                 `json:"asset_issuer"`
+	Conditions       map[string]interface{} `json:"conditions"`
+	Notes            string                 `json:"notes"`
+	// Memo is attached to the Stellar transaction. MemoType is text (default,
+	// at most 28 bytes), id (unsigned 64-bit integer), or hash (64 hex characters).
+	Memo     string `json:"memo"`
+	MemoType string `json:"memo_type"`
+	// BaseFee overrides the configured per-operation fee, in stroops, e.g.
+	// during surge pricing. It may not exceed the configured maximum.
+	BaseFee int64 `json:"base_fee" binding:"omitempty,gte=100"`
 }
 
 type SendRemittanceRequest struct {
-	SenderID       uint    `json:"sender_id" binding:"required"`
-	RecipientID    uint    `json:"recipient_id" binding:"required"`
+	SenderID    uint `json:"sender_id" binding:"required"`
+	RecipientID uint `json:"recipient_id" binding:"required_without=BeneficiaryID"`
+	// BeneficiaryID sends to one of the caller's saved beneficiaries instead
+	// of RecipientID.
+	BeneficiaryID  *uint   `json:"beneficiary_id"`
 	Amount         float64 `json:"amount" binding:"required,gt=0"`
 	Currency       string  `json:"currency" binding:"required"`
 	TargetCurrency string  `json:"target_currency"`
@@ -81,24 +114,117 @@ type SendRemittanceRequest struct {
 func (h *RemittanceHandler) SendRemittance(c *gin.Context) {
 	var req SendRemittanceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.Error(errors.NewValidationError("Invalid request body", err.Error()))
+		c.Error(errors.NewBindingError(err))
 		return
 	}
 
-	feeBreakdown := h.fees.Calculate(req.Amount)
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+	// The payment, its daily limit and any beneficiary all belong to the
+	// caller; sender_id only confirms who that is.
+	senderID := userID.(uint)
+	if req.SenderID != senderID {
+		c.Error(errors.NewForbiddenError("sender_id does not match the authenticated user"))
+		return
+	}
+
+	var recipientAccount string
+	if req.BeneficiaryID != nil {
+		if req.RecipientID != 0 {
+			c.Error(errors.NewValidationError("Specify recipient_id or beneficiary_id, not both", nil))
+			return
+		}
+		beneficiary, appErr := findBeneficiary(h.db, senderID, *req.BeneficiaryID)
+		if appErr != nil {
+			c.Error(appErr)
+			return
+		}
+		recipient, err := services.NewRecipientService(h.db).Resolve(beneficiary.StellarAddress)
+		if err != nil {
+			c.Error(errors.NewInternalError("Failed to resolve recipient", err))
+			return
+		}
+		req.RecipientID = recipient.ID
+		recipientAccount = beneficiary.StellarAddress
+	}
+
+	if senderID == req.RecipientID {
+		c.Error(errors.NewSelfRemittanceError("Sender and recipient are the same user"))
+		return
+	}
+
+	// This endpoint records a ledger entry by currency and takes no issuer, so
+	// only the code itself can be checked here.
+	if err := utils.ValidateAssetCode(req.Currency); err != nil {
+		c.Error(errors.NewValidationError("Invalid currency", err.Error()))
+		return
+	}
+	if req.TargetCurrency != "" {
+		if err := utils.ValidateAssetCode(req.TargetCurrency); err != nil {
+			c.Error(errors.NewValidationError("Invalid target currency", err.Error()))
+			return
+		}
+	}
+	if !requireSupportedCurrency(c, h.config, "currency", req.Currency, "") {
+		return
+	}
+	if req.TargetCurrency != "" && !requireSupportedCurrency(c, h.config, "target_currency", req.TargetCurrency, "") {
+		return
+	}
+	if !requireAmountInRange(c, h.config, req.Currency, req.Amount) {
+		return
+	}
+
+	settlement, ok := h.settlementAccount(req.Currency)
+	if !ok {
+		c.Error(errors.NewValidationError(fmt.Sprintf("No settlement account configured for %s", req.Currency), nil))
+		return
+	}
+	if !h.requireDailyLimit(c, senderID, map[string]float64{req.Currency: req.Amount}) {
+		return
+	}
+
+	feeBreakdown, err := h.fees.Calculate(req.Amount)
+	if err != nil {
+		c.Error(feeCalculationError(err))
+		return
+	}
+	if !requireFeeBelowAmount(c, req.Currency, req.Amount, feeBreakdown) {
+		return
+	}
 	payment := models.Payment{
-		SenderID:       req.SenderID,
-		RecipientID:    req.RecipientID,
-		Amount:         req.Amount,
-		Currency:       req.Currency,
-		TargetCurrency: req.TargetCurrency,
-		Status:         "pending",
-		Fee:            feeBreakdown.TotalFee,
-		PlatformFee:    feeBreakdown.PlatformFee,
-		ForexFee:       feeBreakdown.ForexFee,
-		ComplianceFee:  feeBreakdown.ComplianceFee,
-		NetworkFee:     feeBreakdown.NetworkFee,
-		Notes:          req.Notes,
+		SenderID:          senderID,
+		RecipientID:       req.RecipientID,
+		RecipientAccount:  recipientAccount,
+		Amount:            req.Amount,
+		Currency:          req.Currency,
+		TargetCurrency:    req.TargetCurrency,
+		Status:            "pending",
+		SettlementAccount: settlement,
+		Fee:               feeBreakdown.TotalFee,
+		PlatformFee:       feeBreakdown.PlatformFee,
+		ForexFee:          feeBreakdown.ForexFee,
+		ComplianceFee:     feeBreakdown.ComplianceFee,
+		NetworkFee:        feeBreakdown.NetworkFee,
+		Notes:             models.EncryptedString(req.Notes),
+	}
+
+	// Record the quote so the executed rate can later be compared against it.
+	if services.IsCrossCurrency(&payment) {
+		rate, err := h.quoteRate(c.Request.Context(), payment.Currency, payment.TargetCurrency)
+		if err != nil {
+			if services.IsRateUnavailable(err) {
+				c.Error(errors.NewValidationError(fmt.Sprintf("No FX rate available for %s/%s", payment.Currency, payment.TargetCurrency), nil))
+			} else {
+				c.Error(errors.NewInternalError("Failed to quote FX rate", err))
+			}
+			return
+		}
+		payment.QuotedRate = rate
+		payment.ConvertedAmount = services.ConvertAmount(payment.Amount, rate)
 	}
 
 	if err := h.db.Create(&payment).Error; err != nil {
@@ -108,6 +234,7 @@ func (h *RemittanceHandler) SendRemittance(c *gin.Context) {
 
 	// Set response for idempotency caching
 	middleware.SetIdempotencyResponse(c, payment)
+	middleware.SetAuditNew(c, payment)
 
 	c.JSON(http.StatusCreated, payment)
 }
@@ -115,51 +242,69 @@ func (h *RemittanceHandler) SendRemittance(c *gin.Context) {
 func (h *RemittanceHandler) CreateRemittance(c *gin.Context) {
 	var req CreateRemittanceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.Error(errors.NewValidationError("Invalid request body", err.Error()))
+		c.Error(errors.NewBindingError(err))
 		return
 	}
 
-	ctx := utils.WithRequestContext(c.Request.Context(), c.GetString("requestID"), nil)
+	// Auth: Extract sender user ID from context (set by JWT middleware)
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+	ctx := utils.WithRequestContext(c.Request.Context(), c.GetString("requestID"), userID)
 
-	// Validate Stellar accounts
-	if err := h.stellarClient.ValidateAccount(ctx, req.SenderAccount); err != nil {
-		c.Error(errors.NewValidationError("Invalid sender account", err.Error()))
+	// The checks are shared with SimulateRemittance so a simulation never
+	// disagrees with the real thing.
+	pre, appErr := h.preflightRemittance(ctx, userID.(uint), &req, true)
+	if appErr != nil {
+		c.Error(appErr)
 		return
 	}
-	if err := h.stellarClient.ValidateAccount(ctx, req.RecipientAccount); err != nil {
-		c.Error(errors.NewValidationError("Invalid recipient account", err.Error()))
+	if len(pre.blocking) > 0 {
+		c.Error(pre.blocking[0])
 		return
 	}
+	memo, memoType, stellarAmount, settlement, feeBreakdown := pre.memo, pre.memoType, pre.stellarAmount, pre.settlement, pre.fees
+	if req.BaseFee > 0 {
+		ctx = utils.WithRequestBaseFee(ctx, req.BaseFee)
+	}
+	ctx = utils.WithEscrowConditions(ctx, pre.conditions.Escrow())
 
-	// Auth: Extract sender user ID from context (set by JWT middleware)
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.Error(errors.NewUnauthorizedError("Unauthorized"))
+	// Recipients who have not signed up get a placeholder user so the
+	// remittance shows up in their list once they register with the address.
+	recipient, err := services.NewRecipientService(h.db).Resolve(req.RecipientAccount)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to resolve recipient", err))
 		return
 	}
-	ctx = utils.WithRequestContext(ctx, c.GetString("requestID"), userID)
 
-	// For simplicity, we'll assume the recipient user exists or we just store the account
-	// In a real app, we'd lookup or create the recipient user.
-	// For now, we'll just set RecipientID to 0 if not found, or use a placeholder.
+	conditionsJSON, _ := json.Marshal(pre.conditions)
 
-	conditionsJSON, _ := json.Marshal(req.Conditions)
-
-	feeBreakdown := h.fees.Calculate(req.Amount)
 	payment := models.Payment{
-		SenderID:         userID.(uint),
-		SenderAccount:    req.SenderAccount,
-		RecipientAccount: req.RecipientAccount,
-		Amount:           req.Amount,
-		Currency:         req.AssetCode,
-		Status:           "pending",
-		Fee:              feeBreakdown.TotalFee,
-		PlatformFee:      feeBreakdown.PlatformFee,
-		ForexFee:         feeBreakdown.ForexFee,
-		ComplianceFee:    feeBreakdown.ComplianceFee,
-		NetworkFee:       feeBreakdown.NetworkFee,
-		Conditions:       string(conditionsJSON),
-		Notes:            req.Notes,
+		SenderID:          userID.(uint),
+		SenderAccount:     req.SenderAccount,
+		RecipientID:       recipient.ID,
+		RecipientAccount:  req.RecipientAccount,
+		Amount:            req.Amount,
+		Currency:          req.AssetCode,
+		Status:            "pending",
+		Fee:               feeBreakdown.TotalFee,
+		PlatformFee:       feeBreakdown.PlatformFee,
+		ForexFee:          feeBreakdown.ForexFee,
+		ComplianceFee:     feeBreakdown.ComplianceFee,
+		NetworkFee:        feeBreakdown.NetworkFee,
+		Corridor:          feeBreakdown.Corridor,
+		Conditions:        models.EncryptedString(conditionsJSON),
+		Notes:             models.EncryptedString(req.Notes),
+		Memo:              req.Memo,
+		MemoType:          memoType,
+		SettlementAccount: settlement,
+	}
+
+	if h.config.ClaimableBalances {
+		// The recipient claims the balance directly; nothing is routed.
+		payment.SettlementAccount = ""
 	}
 
 	// DB Save
@@ -168,19 +313,58 @@ func (h *RemittanceHandler) CreateRemittance(c *gin.Context) {
 		return
 	}
 
-	// Stellar Integration: Build escrow transaction envelope
-	xdr, err := h.stellarClient.BuildEscrowTx(
-		ctx,
-		req.SenderAccount,
-		req.RecipientAccount,
-		req.AssetCode,
-		req.AssetIssuer,
-		fmt.Sprintf("%.7f", req.Amount),
-	)
-	if err != nil {
-		c.Error(errors.NewInternalError("Failed to build Stellar transaction", err))
+	var xdr string
+	updates := map[string]interface{}{}
+	if h.config.ClaimableBalances {
+		claimable, err := h.stellarClient.BuildCreateClaimableBalanceTx(
+			ctx,
+			req.SenderAccount,
+			req.RecipientAccount,
+			req.AssetCode,
+			req.AssetIssuer,
+			stellarAmount,
+			pre.conditions.Escrow().ReleaseAfter,
+			memo,
+		)
+		if err != nil {
+			c.Error(errors.NewInternalError("Failed to build Stellar transaction", err))
+			return
+		}
+		xdr = claimable.Envelope
+		payment.ClaimableBalanceID, payment.ReclaimAfter = claimable.BalanceID, &claimable.ReclaimAfter
+		updates["claimable_balance_id"], updates["reclaim_after"] = payment.ClaimableBalanceID, payment.ReclaimAfter
+	} else {
+		// Routed remittances are escrowed in the currency's settlement account,
+		// which later pays the recipient out.
+		escrowDestination := req.RecipientAccount
+		if settlement != "" {
+			escrowDestination = settlement
+		}
+
+		// Stellar Integration: Build escrow transaction envelope
+		xdr, err = h.stellarClient.BuildEscrowTx(
+			ctx,
+			req.SenderAccount,
+			escrowDestination,
+			req.AssetCode,
+			req.AssetIssuer,
+			stellarAmount,
+			memo,
+		)
+		if err != nil {
+			c.Error(errors.NewInternalError("Failed to build Stellar transaction", err))
+			return
+		}
+	}
+
+	payment.TxEnvelope = xdr
+	updates["tx_envelope"] = xdr
+	if err := payment.UpdateVersioned(h.db, updates); err != nil {
+		c.Error(errors.NewInternalError("Failed to store transaction envelope", err))
 		return
 	}
+	middleware.SetAuditNew(c, payment)
+	h.dispatch(payment.SenderID, utils.NotifyRemittanceCreated, utils.NotifyRemittanceCreated, payment, "")
 
 	response := gin.H{
 		"remittance_id": payment.ID,
@@ -189,6 +373,13 @@ func (h *RemittanceHandler) CreateRemittance(c *gin.Context) {
 		"tx_envelope":   xdr,
 		"message":       "Remittance initiated successfully. Please sign and submit the transaction.",
 	}
+	if payment.ClaimableBalanceID != "" {
+		response["claimable_balance_id"] = payment.ClaimableBalanceID
+		response["reclaim_after"] = payment.ReclaimAfter
+	}
+	if warning := h.trustlineWarning(ctx, req.RecipientAccount, req.AssetCode, req.AssetIssuer); warning != "" {
+		response["warning"] = warning
+	}
 
 	// Set response for idempotency caching
 	middleware.SetIdempotencyResponse(c, response)
@@ -196,132 +387,1959 @@ func (h *RemittanceHandler) CreateRemittance(c *gin.Context) {
 	c.JSON(http.StatusCreated, response)
 }
 
-func (h *RemittanceHandler) GetRemittance(c *gin.Context) {
-	id := c.Param("id")
-	var payment models.Payment
+type BatchRemittanceItem struct {
+	RecipientAccount string  `json:"recipient_account" binding:"required"`
+	Amount           float64 `json:"amount" binding:"required,gt=0"`
+	AssetCode        string  `json:"asset_code" binding:"required"`
+	AssetIssuer      string  `json:"asset_issuer"`
+	Notes            string  `json:"notes"`
+}
 
-	if err := h.db.First(&payment, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.Error(errors.NewNotFoundError("Payment not found"))
-		} else {
-			c.Error(errors.NewInternalError("Failed to fetch payment", err))
+type CreateBatchRemittanceRequest struct {
+	SenderAccount string                `json:"sender_account" binding:"required"`
+	Items         []BatchRemittanceItem `json:"items" binding:"required,min=1,dive"`
+	// BaseFee overrides the configured per-operation fee, in stroops.
+	BaseFee int64 `json:"base_fee" binding:"omitempty,gte=100"`
+}
+
+// checkBaseFee rejects a per-request base fee above the configured maximum.
+func (h *RemittanceHandler) checkBaseFee(c *gin.Context, baseFee int64) bool {
+	return report(c, h.baseFeeError(baseFee))
+}
+
+// baseFeeError is the error checkBaseFee reports, or nil when baseFee is
+// allowed.
+func (h *RemittanceHandler) baseFeeError(baseFee int64) *errors.AppError {
+	if baseFee > 0 && h.config.MaxBaseFee > 0 && baseFee > h.config.MaxBaseFee {
+		return errors.NewValidationError(
+			fmt.Sprintf("base_fee may not exceed %d stroops", h.config.MaxBaseFee),
+			map[string]interface{}{"base_fee": baseFee, "max_base_fee": h.config.MaxBaseFee},
+		)
+	}
+	return nil
+}
+
+// CreateBatchRemittance builds a single Stellar transaction paying out to
+// every line item and records one payment per item under a shared batch ID.
+func (h *RemittanceHandler) CreateBatchRemittance(c *gin.Context) {
+	var req CreateBatchRemittanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+
+	if len(req.Items) > utils.MaxOperationsPerTx {
+		c.Error(errors.NewValidationError(
+			fmt.Sprintf("Batch exceeds the maximum of %d operations per transaction", utils.MaxOperationsPerTx),
+			fmt.Sprintf("batch contains %d items", len(req.Items)),
+		))
+		return
+	}
+
+	invalidAssets := map[string]string{}
+	for i, item := range req.Items {
+		if err := utils.ValidateAsset(item.AssetCode, item.AssetIssuer); err != nil {
+			invalidAssets[fmt.Sprintf("items[%d].asset_code", i)] = err.Error()
+		}
+	}
+	if len(invalidAssets) > 0 {
+		c.Error(errors.NewValidationError("Invalid asset in batch", invalidAssets))
+		return
+	}
+	unsupported := map[string]string{}
+	for i, item := range req.Items {
+		if !currencySupported(h.config, item.AssetCode, item.AssetIssuer) {
+			unsupported[fmt.Sprintf("items[%d].asset_code", i)] = item.AssetCode
+		}
+	}
+	if len(unsupported) > 0 {
+		c.Error(errors.NewUnsupportedCurrencyError("Unsupported asset in batch", gin.H{
+			"items":     unsupported,
+			"supported": h.config.SupportedCurrencies,
+		}))
+		return
+	}
+	refusedIssuers := map[string]string{}
+	policy := issuerPolicy(h.config)
+	for i, item := range req.Items {
+		if !policy.Permits(item.AssetCode, item.AssetIssuer) {
+			refusedIssuers[fmt.Sprintf("items[%d].asset_issuer", i)] = item.AssetIssuer
+		}
+	}
+	if len(refusedIssuers) > 0 {
+		c.Error(errors.NewIssuerNotAllowedError("Issuer not accepted in batch", refusedIssuers))
+		return
+	}
+	stellarAmounts := make([]string, len(req.Items))
+	invalidAmounts := map[string]string{}
+	for i, item := range req.Items {
+		amount, err := utils.StellarAmount(item.Amount)
+		if err != nil {
+			invalidAmounts[fmt.Sprintf("items[%d].amount", i)] = err.Error()
 		}
+		stellarAmounts[i] = amount
+	}
+	if len(invalidAmounts) > 0 {
+		c.Error(errors.NewValidationError("Invalid amount in batch", invalidAmounts))
+		return
+	}
+	if !h.checkBaseFee(c, req.BaseFee) {
 		return
 	}
 
-	c.JSON(http.StatusOK, payment)
-}
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+	ctx := utils.WithRequestContext(c.Request.Context(), c.GetString("requestID"), userID)
+	if req.BaseFee > 0 {
+		ctx = utils.WithRequestBaseFee(ctx, req.BaseFee)
+	}
 
-func (h *RemittanceHandler) ListRemittances(c *gin.Context) {
-	var payments []models.Payment
+	// The KYC threshold applies to what the batch sends in each currency, so
+	// splitting a large payment into small items does not get around it.
+	batchTotals := map[string]float64{}
+	for _, item := range req.Items {
+		batchTotals[strings.ToUpper(item.AssetCode)] += item.Amount
+	}
+	largest := 0.0
+	for _, total := range batchTotals {
+		if total > largest {
+			largest = total
+		}
+	}
+	if !h.requireKYC(c, userID.(uint), largest) {
+		return
+	}
+	if !h.requireDailyLimit(c, userID.(uint), batchTotals) {
+		return
+	}
 
-	// Cache key based on query params
-	cacheKey := fmt.Sprintf("payments:list:%s:%s", c.Query("page"), c.Query("page_size"))
-	
-	// Try cache
-	if found, _ := utils.GetCached(cacheKey, &payments); found {
-		c.Header("X-Cache", "HIT")
-		c.JSON(http.StatusOK, payments)
+	// Validate every account before building anything so a bad recipient rejects the whole batch.
+	if err := h.stellarClient.ValidateAccount(ctx, req.SenderAccount); err != nil {
+		c.Error(errors.NewValidationError("Invalid sender account", err.Error()))
+		return
+	}
+	invalid := map[string]string{}
+	for i, item := range req.Items {
+		if err := h.stellarClient.ValidateAccount(ctx, item.RecipientAccount); err != nil {
+			invalid[fmt.Sprintf("items[%d].recipient_account", i)] = err.Error()
+		}
+	}
+	if len(invalid) > 0 {
+		c.Error(errors.NewValidationError("Invalid recipient account in batch", invalid))
 		return
 	}
 
-	// DB query with pagination
-	if err := h.db.Scopes(Paginate(c)).Order("created_at DESC").Find(&payments).Error; err != nil {
-		c.Error(errors.NewInternalError("Failed to fetch payments", err))
+	batchID := uuid.New().String()
+	ops := make([]utils.BatchPayment, 0, len(req.Items))
+	payments := make([]models.Payment, 0, len(req.Items))
+	for i, item := range req.Items {
+		feeBreakdown, err := h.fees.Calculate(item.Amount)
+		if err != nil {
+			c.Error(feeCalculationError(err))
+			return
+		}
+		ops = append(ops, utils.BatchPayment{
+			Destination: item.RecipientAccount,
+			AssetCode:   item.AssetCode,
+			Issuer:      item.AssetIssuer,
+			Amount:      stellarAmounts[i],
+		})
+		payments = append(payments, models.Payment{
+			SenderID:         userID.(uint),
+			SenderAccount:    req.SenderAccount,
+			RecipientAccount: item.RecipientAccount,
+			Amount:           item.Amount,
+			Currency:         item.AssetCode,
+			Status:           "pending",
+			BatchID:          batchID,
+			Fee:              feeBreakdown.TotalFee,
+			PlatformFee:      feeBreakdown.PlatformFee,
+			ForexFee:         feeBreakdown.ForexFee,
+			ComplianceFee:    feeBreakdown.ComplianceFee,
+			NetworkFee:       feeBreakdown.NetworkFee,
+			Notes:            models.EncryptedString(item.Notes),
+		})
+	}
+
+	xdr, err := h.stellarClient.BuildBatchPaymentTx(ctx, req.SenderAccount, ops)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to build Stellar transaction", err))
 		return
 	}
 
-	// Set cache for 30 seconds
-	utils.SetCached(cacheKey, payments, 30*time.Second)
+	for i := range payments {
+		payments[i].TxEnvelope = xdr
+	}
+	if err := h.db.Create(&payments).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to create remittance records", err))
+		return
+	}
 
-	c.Header("X-Cache", "MISS")
-	c.JSON(http.StatusOK, payments)
+	ids := make([]uint, len(payments))
+	for i, p := range payments {
+		ids[i] = p.ID
+	}
+
+	response := gin.H{
+		"batch_id":        batchID,
+		"remittance_ids":  ids,
+		"operation_count": len(ops),
+		"status":          "pending",
+		"tx_envelope":     xdr,
+		"message":         "Batch remittance initiated successfully. Please sign and submit the transaction.",
+	}
+
+	// Set response for idempotency caching
+	middleware.SetIdempotencyResponse(c, response)
+
+	c.JSON(http.StatusCreated, response)
 }
 
-func (h *RemittanceHandler) CompleteRemittance(c *gin.Context) {
+// trustlineWarning returns a warning when the recipient cannot receive the
+// credit asset because it has no trustline. Lookup failures are logged and
+// ignored so they never block remittance creation.
+func (h *RemittanceHandler) trustlineWarning(ctx context.Context, recipient, assetCode, issuer string) string {
+	if !h.config.CheckRecipientTrustline {
+		return ""
+	}
+	if strings.ToUpper(assetCode) == "XLM" || assetCode == "" {
+		return ""
+	}
+
+	missing, err := h.missingTrustline(ctx, recipient, assetCode, issuer)
+	if err != nil {
+		logger.Log.WithField("recipient_account", recipient).WithError(err).Warn("Failed to check recipient trustline")
+		return ""
+	}
+	if !missing {
+		return ""
+	}
+	return trustlineMessage(assetCode, issuer)
+}
+
+// missingTrustline reports whether recipient lacks a trustline for the
+// credit asset.
+func (h *RemittanceHandler) missingTrustline(ctx context.Context, recipient, assetCode, issuer string) (bool, error) {
+	balances, err := h.stellarClient.GetBalances(ctx, recipient)
+	if err != nil {
+		return false, err
+	}
+	return !utils.HasTrustline(balances, assetCode, issuer), nil
+}
+
+func trustlineMessage(assetCode, issuer string) string {
+	return fmt.Sprintf("Recipient account has no trustline for %s:%s; the payment will fail until the recipient opens one", assetCode, issuer)
+}
+
+func (h *RemittanceHandler) GetRemittance(c *gin.Context) {
 	id := c.Param("id")
 	var payment models.Payment
 
 	if err := h.db.First(&payment, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.Error(errors.NewNotFoundError("Payment not found"))
+			c.Error(errors.NewNotFoundError("Payment not found").WithKey("error.payment_not_found"))
 		} else {
 			c.Error(errors.NewInternalError("Failed to fetch payment", err))
 		}
 		return
 	}
-
-	middleware.SetAuditOld(c, payment)
-	payment.Status = "completed"
-	if err := h.db.Save(&payment).Error; err != nil {
-		c.Error(errors.NewInternalError("Failed to update payment", err))
+	// Notes and conditions are decrypted on load, so only the parties see them.
+	if !isSenderOrAdmin(c, &payment) && !isRecipientOrAdmin(c, &payment) {
+		c.Error(errors.NewForbiddenError("Only the sender, the recipient, or an admin can view this remittance"))
 		return
 	}
 
-	// Send email notification to sender
-	var sender models.User
-	if err := h.db.First(&sender, payment.SenderID).Error; err == nil {
-		go h.emailService.SendPaymentCompletedEmail(&sender, &payment)
-	}
-
-	middleware.SetAuditNew(c, payment)
 	c.JSON(http.StatusOK, payment)
 }
 
-type CreateInvoiceRequest struct {
-	PaymentID   uint    `json:"payment_id" binding:"required"`
-	IssuerID    uint    `json:"issuer_id" binding:"required"`
-	RecipientID uint    `json:"recipient_id" binding:"required"`
-	Amount      float64 `json:"amount" binding:"required,gt=0"`
-	Currency    string  `json:"currency" binding:"required"`
-	Description string  `json:"description"`
-}
+// GetSigningDetails decodes the stored transaction envelope for a remittance
+// so a wallet can show the user exactly what they are about to sign.
+func (h *RemittanceHandler) GetSigningDetails(c *gin.Context) {
+	id := c.Param("id")
+	var payment models.Payment
 
-func (h *RemittanceHandler) CreateInvoice(c *gin.Context) {
-	var req CreateInvoiceRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.Error(errors.NewValidationError("Invalid request body", err.Error()))
+	if err := h.db.First(&payment, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("Payment not found").WithKey("error.payment_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch payment", err))
+		}
 		return
 	}
 
-	invoiceNo := fmt.Sprintf("INV-%d-%d", time.Now().Unix(), req.PaymentID)
+	if !isSenderOrAdmin(c, &payment) {
+		c.Error(errors.NewForbiddenError("Only the sender or an admin can view signing details"))
+		return
+	}
 
-	invoice := models.Invoice{
-		PaymentID:   req.PaymentID,
-		InvoiceNo:   invoiceNo,
-		IssuerID:    req.IssuerID,
-		RecipientID: req.RecipientID,
-		Amount:      req.Amount,
-		Currency:    req.Currency,
-		Description: req.Description,
-		Status:      "unpaid",
+	if payment.TxEnvelope == "" {
+		c.Error(errors.NewNotFoundError("No transaction envelope stored for this payment"))
+		return
 	}
 
-	if err := h.db.Create(&invoice).Error; err != nil {
-		c.Error(errors.NewInternalError("Failed to create invoice", err))
+	summary, err := utils.DecodeTransactionSummary(payment.TxEnvelope, h.config.NetworkPassphrase)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to decode transaction envelope", err))
 		return
 	}
 
-	// Set response for idempotency caching
-	middleware.SetIdempotencyResponse(c, invoice)
+	c.JSON(http.StatusOK, gin.H{
+		"remittance_id":   payment.ID,
+		"status":          payment.Status,
+		"tx_envelope":     payment.TxEnvelope,
+		"signing_details": summary,
+	})
+}
 
-	c.JSON(http.StatusCreated, invoice)
+// requireKYC rejects remittances above the configured threshold unless the
+// sender's KYC is verified, reporting the failure on the context.
+func (h *RemittanceHandler) requireKYC(c *gin.Context, userID uint, amount float64) bool {
+	return report(c, h.kycError(userID, amount))
 }
 
-func (h *RemittanceHandler) GetInvoice(c *gin.Context) {
-	id := c.Param("id")
-	var invoice models.Invoice
+// kycError is the error requireKYC reports, or nil when the sender may send
+// amount.
+func (h *RemittanceHandler) kycError(userID uint, amount float64) *errors.AppError {
+	if h.config.KYCThreshold <= 0 || amount <= h.config.KYCThreshold {
+		return nil
+	}
 
-	if err := h.db.Preload("Payment").First(&invoice, id).Error; err != nil {
+	var sender models.User
+	if err := h.db.First(&sender, userID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.Error(errors.NewNotFoundError("Invoice not found"))
-		} else {
-			c.Error(errors.NewInternalError("Failed to fetch invoice", err))
+			return errors.NewUnauthorizedError("User not found").WithKey("error.user_not_found")
 		}
-		return
+		return errors.NewInternalError("Failed to fetch user", err)
+	}
+	if sender.KYCStatus != services.KYCStatusVerified {
+		return errors.NewKYCRequiredError(fmt.Sprintf("KYC verification is required for remittances above %.2f", h.config.KYCThreshold))
 	}
+	return nil
+}
 
-	c.JSON(http.StatusOK, invoice)
+// corridorCountries returns the country of the sender userID and of the
+// user owning recipientAccount, each empty when unknown, so the remittance
+// can be priced for its corridor.
+func (h *RemittanceHandler) corridorCountries(userID uint, recipientAccount string) (from, to string, err error) {
+	var sender, recipient models.User
+	if err := h.db.Select("country").First(&sender, userID).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return "", "", err
+	}
+	if err := h.db.Select("country").Where("stellar_address = ?", recipientAccount).First(&recipient).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return "", "", err
+	}
+	return sender.Country, recipient.Country, nil
+}
+
+// requireDailyLimit rejects a remittance that would take the sender over
+// their daily limit in any of the currencies of amounts, reporting the
+// failure on the context.
+func (h *RemittanceHandler) requireDailyLimit(c *gin.Context, userID uint, amounts map[string]float64) bool {
+	return report(c, h.dailyLimitError(userID, amounts))
+}
+
+// dailyLimitError is the error requireDailyLimit reports, or nil when every
+// amount fits within the sender's limits.
+func (h *RemittanceHandler) dailyLimitError(userID uint, amounts map[string]float64) *errors.AppError {
+	if len(h.config.DailyLimits) == 0 && len(h.config.DailyLimitsByTier) == 0 {
+		return nil
+	}
+
+	var sender models.User
+	if err := h.db.First(&sender, userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.NewUnauthorizedError("User not found").WithKey("error.user_not_found")
+		}
+		return errors.NewInternalError("Failed to fetch user", err)
+	}
+
+	limits := services.NewDailyLimitService(h.db, h.config)
+	now := time.Now()
+	for currency, amount := range amounts {
+		err := limits.Check(&sender, currency, amount, now)
+		if err == nil {
+			continue
+		}
+		if exceeded, ok := services.AsDailyLimitError(err); ok {
+			return errors.NewDailyLimitExceededError(
+				fmt.Sprintf("Daily sending limit of %.2f %s exceeded", exceeded.Limit, exceeded.Currency), exceeded)
+		}
+		return errors.NewInternalError("Failed to check daily limit", err)
+	}
+	return nil
+}
+
+// sameAccount reports whether two Stellar addresses name the same account,
+// ignoring case and surrounding whitespace.
+func sameAccount(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// selfRemittanceError refuses a remittance from userID whose recipient
+// account is the sender account or is registered to userID.
+func (h *RemittanceHandler) selfRemittanceError(userID uint, senderAccount, recipientAccount string) *errors.AppError {
+	if sameAccount(senderAccount, recipientAccount) {
+		return errors.NewSelfRemittanceError("Sender and recipient accounts are the same")
+	}
+	var count int64
+	err := h.db.Model(&models.User{}).
+		Where("id = ? AND UPPER(stellar_address) = ?", userID, strings.ToUpper(strings.TrimSpace(recipientAccount))).
+		Count(&count).Error
+	if err != nil {
+		return errors.NewInternalError("Failed to check recipient", err)
+	}
+	if count > 0 {
+		return errors.NewSelfRemittanceError("Recipient account belongs to the sender")
+	}
+	return nil
+}
+
+// settlementAccount returns the platform account configured for the
+// currency. With no settlement accounts configured routing is disabled and it
+// returns "" and true; otherwise an unconfigured currency returns false.
+func (h *RemittanceHandler) settlementAccount(currency string) (string, bool) {
+	if len(h.config.SettlementAccounts) == 0 {
+		return "", true
+	}
+	account, ok := h.config.SettlementAccounts[strings.ToUpper(currency)]
+	return account, ok
+}
+
+// isSenderOrAdmin reports whether the authenticated user sent the payment or holds the admin role.
+func isSenderOrAdmin(c *gin.Context, payment *models.Payment) bool {
+	if role, _ := c.Get("role"); role == "admin" {
+		return true
+	}
+	userID, ok := c.Get("userID")
+	if !ok {
+		return false
+	}
+	id, ok := userID.(uint)
+	return ok && id == payment.SenderID
+}
+
+type SubmitRemittanceRequest struct {
+	SignedXDR string `json:"signed_xdr" binding:"required"`
+}
+
+// SubmitRemittance submits the signed envelope of a pending remittance to
+// Horizon and moves it to "processing". With ?wait=<duration> (e.g. 30s) it
+// blocks until the transaction is confirmed or the wait, capped at
+// SubmitMaxWait, runs out; on timeout the remittance is still "processing".
+// If Horizon itself times out the outcome is unknown, so the remittance is
+// also left "processing" under its transaction hash for reconciliation.
+func (h *RemittanceHandler) SubmitRemittance(c *gin.Context) {
+	var req SubmitRemittanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+
+	wait, err := h.submitWait(c.Query("wait"))
+	if err != nil {
+		c.Error(errors.NewValidationError("Invalid wait duration", err.Error()))
+		return
+	}
+
+	id := c.Param("id")
+	var payment models.Payment
+	if err := h.db.First(&payment, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("Payment not found").WithKey("error.payment_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch payment", err))
+		}
+		return
+	}
+
+	if !isSenderOrAdmin(c, &payment) {
+		c.Error(errors.NewForbiddenError("Only the sender or an admin can submit this remittance"))
+		return
+	}
+	h.submitSigned(c, &payment, req.SignedXDR, wait)
+}
+
+type SigningCallbackRequest struct {
+	RemittanceID uint   `json:"remittance_id" binding:"required"`
+	SignedXDR    string `json:"signed_xdr" binding:"required"`
+}
+
+// SigningCallback is called by an external custody system once it has signed
+// a remittance's envelope. It applies the same checks as SubmitRemittance
+// and submits without waiting, so the remittance is normally left
+// "processing". Callers are authenticated by SigningCallbackAuth rather than
+// a user token.
+func (h *RemittanceHandler) SigningCallback(c *gin.Context) {
+	var req SigningCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+
+	var payment models.Payment
+	if err := h.db.First(&payment, req.RemittanceID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("Payment not found").WithKey("error.payment_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch payment", err))
+		}
+		return
+	}
+
+	if payment.TxEnvelope == "" {
+		c.Error(errors.NewConflictError("Remittance has no envelope awaiting a signature"))
+		return
+	}
+
+	logger.Log.WithFields(logrus.Fields{
+		"payment_id": payment.ID,
+		"request_id": c.GetString("requestID"),
+	}).Info("Signing callback received")
+
+	h.submitSigned(c, &payment, req.SignedXDR, 0)
+}
+
+// submitSigned checks that signedXDR is a signed copy of the pending
+// payment's envelope, submits it, and writes the resulting payment. A
+// resubmission of the transaction already submitted for the payment is
+// answered from the recorded outcome without broadcasting it again.
+func (h *RemittanceHandler) submitSigned(c *gin.Context, payment *models.Payment, signedXDR string, wait time.Duration) {
+	var signedHash string
+	if summary, err := utils.DecodeTransactionSummary(signedXDR, h.config.NetworkPassphrase); err == nil {
+		signedHash = summary.Hash
+	}
+	if h.replaySubmission(c, payment, signedHash) {
+		return
+	}
+	if payment.Status != "pending" {
+		c.Error(errors.NewConflictError(fmt.Sprintf("Remittance is already %s", payment.Status)))
+		return
+	}
+
+	// Only the envelope built for this remittance may be submitted for it;
+	// anything else could move other funds under its record.
+	if payment.TxEnvelope == "" {
+		c.Error(errors.NewConflictError("Remittance has no envelope awaiting a signature"))
+		return
+	}
+	signed, err := utils.DecodeTransactionSummary(signedXDR, h.config.NetworkPassphrase)
+	if err != nil {
+		c.Error(errors.NewValidationError("Invalid signed transaction", err.Error()).WithKey("error.invalid_signed_transaction"))
+		return
+	}
+	if !h.checkSources(c, signed) {
+		return
+	}
+
+	// Signatures are not part of the hash, so a signed copy of the stored envelope hashes identically.
+	stored, err := utils.DecodeTransactionSummary(payment.TxEnvelope, h.config.NetworkPassphrase)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to decode stored transaction envelope", err))
+		return
+	}
+	if signed.Hash != stored.Hash {
+		c.Error(errors.NewValidationError("Signed transaction does not match the remittance envelope", nil))
+		return
+	}
+
+	// The sender's funds only move on the sender's signature, so an envelope
+	// without it would be rejected by the network anyway. Payments recorded
+	// without a sender account are paid from the envelope's source.
+	sender := payment.SenderAccount
+	if sender == "" {
+		sender = stored.SourceAccount
+	}
+	verified, err := utils.VerifySignatures(signedXDR, h.config.NetworkPassphrase, []string{sender})
+	if err != nil {
+		c.Error(errors.NewValidationError("Invalid signed transaction", err.Error()).WithKey("error.invalid_signed_transaction"))
+		return
+	}
+	if !verified {
+		c.Error(errors.NewValidationError("Transaction is not signed by the sender account", nil))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	ctx := utils.WithRequestContext(c.Request.Context(), c.GetString("requestID"), userID)
+
+	landed := false
+	txHash, err := h.stellarClient.SubmitTransaction(ctx, signedXDR)
+	if err != nil && utils.IsBadSequence(err) && signedHash != "" {
+		// An earlier submission of this transaction may have landed without
+		// being recorded, e.g. when its response was lost. Its sequence
+		// number is then used up, so the network rejects it as bad_seq.
+		txStatus, statusErr := h.stellarClient.GetTransactionStatus(ctx, signedHash)
+		if statusErr != nil {
+			c.Error(errors.NewInternalError("Failed to look up rejected transaction", statusErr))
+			return
+		}
+		if txStatus == utils.TxStatusSuccess {
+			logger.Log.WithFields(logrus.Fields{
+				"payment_id": payment.ID,
+				"tx_hash":    signedHash,
+				"request_id": c.GetString("requestID"),
+			}).Info("Resubmitted transaction had already landed")
+			txHash, err, landed = signedHash, nil, true
+		}
+	}
+	if err != nil {
+		if result, ok := rejectedOperations(err); ok {
+			h.failOperations(c, payment, signedHash, result)
+			return
+		}
+		if utils.IsBadSequence(err) {
+			// Not applied, so nothing was sent; the payment stays pending.
+			c.Error(errors.NewConflictError("Transaction sequence number is no longer valid; rebuild the envelope and sign it again"))
+			return
+		}
+		if !utils.IsSubmitTimeout(err) {
+			c.Error(errors.NewInternalError("Failed to submit transaction", err))
+			return
+		}
+		// The transaction may still be applied, so failing it could lead to a
+		// double send. Record it as processing under its hash and let
+		// settlement reconciliation find the real outcome.
+		if signedHash == "" {
+			c.Error(errors.NewInternalError("Failed to submit transaction", err))
+			return
+		}
+		txHash = signedHash
+		logger.Log.WithFields(logrus.Fields{
+			"payment_id": payment.ID,
+			"tx_hash":    txHash,
+			"request_id": c.GetString("requestID"),
+		}).WithError(err).Warn("Transaction submission timed out; leaving it to reconciliation")
+	}
+
+	middleware.SetAuditOld(c, *payment)
+	if err := h.updateVersioned(payment, map[string]interface{}{
+		"status":  "processing",
+		"tx_hash": txHash,
+	}); err != nil {
+		if err == models.ErrConcurrentModification {
+			// A concurrent submission of the same envelope got there first.
+			var current models.Payment
+			if h.db.First(&current, payment.ID).Error == nil && h.replaySubmission(c, &current, txHash) {
+				return
+			}
+		}
+		c.Error(paymentUpdateError(err, "Failed to update payment"))
+		return
+	}
+	payment.Status = "processing"
+	payment.TxHash = txHash
+	h.publishBatchStatus(payment)
+
+	if landed {
+		if err := h.settleSubmitted(payment, utils.TxStatusSuccess); err != nil {
+			c.Error(paymentUpdateError(err, "Failed to update payment"))
+			return
+		}
+		h.publishBatchStatus(payment)
+	} else if wait > 0 {
+		interval := h.config.SubmitPollInterval
+		if interval <= 0 {
+			interval = time.Second
+		}
+		waitCtx, cancel := context.WithTimeout(ctx, wait)
+		txStatus, err := utils.WaitForTransaction(waitCtx, h.stellarClient, txHash, interval)
+		cancel()
+		if err != nil {
+			// The transaction is already submitted; report it as processing rather than failing the request.
+			logger.Log.WithFields(logrus.Fields{
+				"payment_id": payment.ID,
+				"tx_hash":    txHash,
+			}).WithError(err).Warn("Failed to poll transaction status")
+		} else if txStatus != utils.TxStatusPending {
+			if err := h.settleSubmitted(payment, txStatus); err != nil {
+				c.Error(paymentUpdateError(err, "Failed to update payment"))
+				return
+			}
+			h.publishBatchStatus(payment)
+		}
+	}
+
+	middleware.SetAuditNew(c, *payment)
+
+	status := http.StatusOK
+	if payment.Status == "processing" {
+		status = http.StatusAccepted
+	}
+	c.JSON(status, *payment)
+}
+
+// replaySubmission answers a resubmission of the transaction already
+// submitted for payment with the recorded outcome, and reports whether it
+// did. A pending payment has no recorded outcome, so its transaction may be
+// submitted again.
+func (h *RemittanceHandler) replaySubmission(c *gin.Context, payment *models.Payment, txHash string) bool {
+	if txHash == "" || payment.Status == "pending" || payment.TxHash != txHash {
+		return false
+	}
+	logger.Log.WithFields(logrus.Fields{
+		"payment_id": payment.ID,
+		"tx_hash":    txHash,
+		"status":     payment.Status,
+		"request_id": c.GetString("requestID"),
+	}).Info("Duplicate submission answered from the recorded outcome")
+
+	if payment.Status == "failed" {
+		c.Error(errors.NewTransactionFailedError("Transaction was rejected by the network", gin.H{
+			"remittance_id":  payment.ID,
+			"failure_reason": payment.FailureReason,
+		}))
+		return true
+	}
+	status := http.StatusOK
+	if payment.Status == "processing" {
+		status = http.StatusAccepted
+	}
+	c.JSON(status, *payment)
+	return true
+}
+
+// checkSources rejects envelopes sourced, at the transaction or operation
+// level, from an account outside the submit allowlist. An empty allowlist
+// allows any source.
+func (h *RemittanceHandler) checkSources(c *gin.Context, summary *utils.TransactionSummary) bool {
+	allowlist := h.config.SubmitSourceAllowlist
+	if len(allowlist) == 0 {
+		return true
+	}
+	for _, source := range summary.SourceAccounts() {
+		allowed := false
+		for _, account := range allowlist {
+			if source == account {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			logger.Log.WithFields(logrus.Fields{
+				"source_account": source,
+				"request_id":     c.GetString("requestID"),
+			}).Warn("Rejected transaction from a source outside the allowlist")
+			c.Error(errors.NewForbiddenError(fmt.Sprintf("Transaction source %s is not an allowed signing account", source)))
+			return false
+		}
+	}
+	return true
+}
+
+// OperationOutcome is the result of one payment's operation in a transaction
+// the network rejected.
+type OperationOutcome struct {
+	PaymentID uint `json:"payment_id"`
+	utils.OperationResult
+}
+
+// rejectedOperations returns the per-operation results of a submission the
+// network rejected, when Horizon reported them.
+func rejectedOperations(err error) (*utils.TransactionResult, bool) {
+	resultXDR, ok := utils.SubmitResultXDR(err)
+	if !ok {
+		return nil, false
+	}
+	result, parseErr := utils.ParseTransactionResult(resultXDR)
+	if parseErr != nil || len(result.Operations) == 0 {
+		return nil, false
+	}
+	return result, true
+}
+
+// failOperations records a rejected transaction, by its hash txHash, against
+// the payments it carried. Stellar applies transactions atomically, so every
+// payment fails, but each one's failure_reason names its own operation's
+// result code, or the transaction code when its operation was not the one at
+// fault.
+func (h *RemittanceHandler) failOperations(c *gin.Context, payment *models.Payment, txHash string, result *utils.TransactionResult) {
+	middleware.SetAuditOld(c, *payment)
+
+	var outcomes []OperationOutcome
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		// Batch operations are built in the order the payments were created.
+		var payments []models.Payment
+		if err := tx.Scopes(batchScope(payment)).Order("id").Find(&payments).Error; err != nil {
+			return err
+		}
+		if len(payments) != len(result.Operations) {
+			return fmt.Errorf("transaction result has %d operations for %d payments", len(result.Operations), len(payments))
+		}
+
+		outcomes = make([]OperationOutcome, len(payments))
+		for i := range payments {
+			op := result.Operations[i]
+			reason := op.Code
+			if op.Success {
+				reason = result.Code
+			}
+			if err := payments[i].UpdateVersioned(tx, map[string]interface{}{
+				"status":         "failed",
+				"failure_reason": reason,
+				"tx_hash":        txHash,
+			}); err != nil {
+				return err
+			}
+			if payments[i].ID == payment.ID {
+				payment.Status = "failed"
+				payment.FailureReason = reason
+				payment.TxHash = txHash
+				payment.Version = payments[i].Version
+			}
+			outcomes[i] = OperationOutcome{PaymentID: payments[i].ID, OperationResult: op}
+		}
+		return nil
+	})
+	if err != nil {
+		c.Error(paymentUpdateError(err, "Failed to record transaction result"))
+		return
+	}
+
+	logger.Log.WithFields(logrus.Fields{
+		"payment_id":  payment.ID,
+		"batch_id":    payment.BatchID,
+		"result_code": result.Code,
+		"request_id":  c.GetString("requestID"),
+	}).Warn("Transaction rejected by the network")
+	h.publishBatchStatus(payment)
+	h.notify(payment.SenderID, services.EventPaymentFailed, *payment, payment.FailureReason)
+	h.dispatch(payment.SenderID, utils.NotifyRemittanceFailed, services.EventPaymentFailed, *payment, payment.FailureReason)
+
+	middleware.SetAuditNew(c, *payment)
+	c.Error(errors.NewTransactionFailedError("Transaction was rejected by the network", gin.H{
+		"remittance_id": payment.ID,
+		"result_code":   result.Code,
+		"operations":    outcomes,
+	}))
+}
+
+// submitWait parses the ?wait= parameter, accepting a Go duration or a plain
+// number of seconds, and caps it at the configured maximum.
+func (h *RemittanceHandler) submitWait(raw string) (time.Duration, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	wait, err := time.ParseDuration(raw)
+	if err != nil {
+		seconds, convErr := strconv.Atoi(raw)
+		if convErr != nil {
+			return 0, err
+		}
+		wait = time.Duration(seconds) * time.Second
+	}
+	if wait < 0 {
+		return 0, fmt.Errorf("wait must not be negative")
+	}
+	if wait > h.config.SubmitMaxWait {
+		wait = h.config.SubmitMaxWait
+	}
+	return wait, nil
+}
+
+// settleSubmitted records the final on-ledger outcome for a submitted
+// remittance and every payment sharing its transaction.
+func (h *RemittanceHandler) settleSubmitted(payment *models.Payment, txStatus utils.TxStatus) error {
+	status := "completed"
+	if txStatus == utils.TxStatusFailed {
+		status = "failed"
+	}
+
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		var payments []models.Payment
+		if err := tx.Scopes(batchScope(payment)).Find(&payments).Error; err != nil {
+			return err
+		}
+		for i := range payments {
+			if err := payments[i].UpdateVersioned(tx, map[string]interface{}{"status": status}); err != nil {
+				return err
+			}
+			payments[i].Status = status
+			if _, err := services.NewInvoiceService(tx).MarkPaidForPayment(&payments[i]); err != nil {
+				return err
+			}
+			if payments[i].ID == payment.ID {
+				payment.Version = payments[i].Version
+			}
+		}
+		payment.Status = status
+		return nil
+	})
+}
+
+// updateVersioned applies updates to payment under its version guard and,
+// for batch remittances, to every other payment in the batch in the same
+// transaction. A stale payment yields models.ErrConcurrentModification.
+func (h *RemittanceHandler) updateVersioned(payment *models.Payment, updates map[string]interface{}) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		if err := payment.UpdateVersioned(tx, updates); err != nil {
+			return err
+		}
+		if payment.BatchID == "" {
+			return nil
+		}
+		return tx.Model(&models.Payment{}).
+			Where("batch_id = ? AND id <> ?", payment.BatchID, payment.ID).
+			Updates(updates).Error
+	})
+}
+
+// publishBatchStatus announces payment's new status to stream subscribers
+// and, for batch remittances, the same status for every other payment in the
+// batch, which changed with it. Call it only after the change is committed.
+func (h *RemittanceHandler) publishBatchStatus(payment *models.Payment) {
+	h.events.PublishPayment(*payment)
+	if h.events == nil || payment.BatchID == "" {
+		return
+	}
+	var peers []uint
+	if err := h.db.Model(&models.Payment{}).
+		Where("batch_id = ? AND id <> ?", payment.BatchID, payment.ID).
+		Pluck("id", &peers).Error; err != nil {
+		logger.Log.WithField("batch_id", payment.BatchID).WithError(err).Warn("Failed to load batch for status events")
+		return
+	}
+	for _, id := range peers {
+		h.events.Publish(services.StatusEvent{PaymentID: id, Status: payment.Status, TxHash: payment.TxHash})
+	}
+}
+
+// report records err on the context and reports whether there was none, so a
+// check returning an *errors.AppError can gate a handler.
+func report(c *gin.Context, err *errors.AppError) bool {
+	if err == nil {
+		return true
+	}
+	c.Error(err)
+	return false
+}
+
+// paymentUpdateError maps a failed payment write to a 409 when it lost a
+// race with another update and to a 500 otherwise.
+func paymentUpdateError(err error, message string) *errors.AppError {
+	if err == models.ErrConcurrentModification {
+		return errors.NewConcurrentModificationError("Remittance was modified by another request; reload it and retry")
+	}
+	return errors.NewInternalError(message, err)
+}
+
+// batchScope matches the payment itself or, for batch remittances, every
+// payment created in the same batch transaction.
+func batchScope(payment *models.Payment) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if payment.BatchID != "" {
+			return db.Where("batch_id = ?", payment.BatchID)
+		}
+		return db.Where("id = ?", payment.ID)
+	}
+}
+
+// CancelRemittance cancels a remittance whose transaction was never
+// submitted. The payment is kept with status "cancelled" rather than deleted;
+// for batch remittances every payment in the batch is cancelled, since they
+// share one transaction.
+func (h *RemittanceHandler) CancelRemittance(c *gin.Context) {
+	id := c.Param("id")
+	var payment models.Payment
+	if err := h.db.First(&payment, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("Payment not found").WithKey("error.payment_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch payment", err))
+		}
+		return
+	}
+
+	if !isSenderOrAdmin(c, &payment) {
+		c.Error(errors.NewForbiddenError("Only the sender or an admin can cancel this remittance"))
+		return
+	}
+	if payment.Status != "pending" {
+		c.Error(errors.NewConflictError(fmt.Sprintf("Only pending remittances can be cancelled; this remittance is %s", payment.Status)))
+		return
+	}
+
+	middleware.SetAuditOld(c, payment)
+	// The version guard keeps a concurrent submit from being overwritten.
+	if err := h.updateVersioned(&payment, map[string]interface{}{"status": "cancelled"}); err != nil {
+		c.Error(paymentUpdateError(err, "Failed to cancel payment"))
+		return
+	}
+	payment.Status = "cancelled"
+	h.publishBatchStatus(&payment)
+
+	middleware.SetAuditNew(c, payment)
+	c.JSON(http.StatusOK, payment)
+}
+
+// paymentFilters builds a scope from the list query parameters: q matches
+// payments whose notes, memo, accounts, currency, or status contain every
+// word of it as a whole word, case-insensitively (notes only while they are
+// stored as plaintext); from and to (RFC3339) bound created_at; min_amount
+// and max_amount bound the amount. Postgres matches through the
+// search_vector full-text index, which also folds English word endings;
+// other dialects compare the words with LIKE.
+func paymentFilters(c *gin.Context) (func(db *gorm.DB) *gorm.DB, error) {
+	var from, to *time.Time
+	for _, bound := range []struct {
+		name string
+		dst  **time.Time
+	}{{"from", &from}, {"to", &to}} {
+		raw := c.Query(bound.name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be an RFC3339 timestamp", bound.name)
+		}
+		*bound.dst = &t
+	}
+	if from != nil && to != nil && from.After(*to) {
+		return nil, fmt.Errorf("from must not be after to")
+	}
+
+	var minAmount, maxAmount *float64
+	for _, bound := range []struct {
+		name string
+		dst  **float64
+	}{{"min_amount", &minAmount}, {"max_amount", &maxAmount}} {
+		raw := c.Query(bound.name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("%s must be a non-negative number", bound.name)
+		}
+		*bound.dst = &v
+	}
+	if minAmount != nil && maxAmount != nil && *minAmount > *maxAmount {
+		return nil, fmt.Errorf("min_amount must not exceed max_amount")
+	}
+
+	words := searchWords(c.Query("q"))
+
+	return func(db *gorm.DB) *gorm.DB {
+		if len(words) > 0 && db.Dialector.Name() == "postgres" {
+			// The search_vector trigger already leaves encrypted notes out.
+			db = db.Where("search_vector @@ plainto_tsquery('english', ?)", strings.Join(words, " "))
+		} else if len(words) > 0 {
+			columns := []string{"memo", "sender_account", "recipient_account", "currency", "status"}
+			// Encrypted notes are ciphertext in the database and cannot be matched there.
+			if !models.FieldsEncrypted() {
+				columns = append(columns, "notes")
+			}
+			for _, word := range words {
+				db = db.Where(wordMatch(columns, word))
+			}
+		}
+		if from != nil {
+			db = db.Where("created_at >= ?", *from)
+		}
+		if to != nil {
+			db = db.Where("created_at <= ?", *to)
+		}
+		if minAmount != nil {
+			db = db.Where("amount >= ?", *minAmount)
+		}
+		if maxAmount != nil {
+			db = db.Where("amount <= ?", *maxAmount)
+		}
+		return db
+	}, nil
+}
+
+// searchSeparators split words in the columns matched by wordMatch; the
+// Postgres text search parser also breaks words on each of them.
+const searchSeparators = "-_.,:;/\\!?()[]'\"#@+&*%="
+
+// searchWords splits q into lower-case words of letters and digits, the way
+// plainto_tsquery does, so that "INVOICE-77" looks for "invoice" and "77".
+func searchWords(q string) []string {
+	return strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// wordMatch matches rows where word appears as a whole word in one of
+// columns, or, for a number, equals the amount. Each column is lower-cased
+// and its separators turned into spaces, so LIKE '% word %' finds whole words
+// only. word holds only letters and digits, so it needs no escaping.
+func wordMatch(columns []string, word string) clause.Expression {
+	like := "% " + word + " %"
+	var exprs []clause.Expression
+	for _, column := range columns {
+		normalized := "LOWER(COALESCE(" + column + ", ''))"
+		for _, sep := range searchSeparators {
+			normalized = "REPLACE(" + normalized + ", '" + strings.ReplaceAll(string(sep), "'", "''") + "', ' ')"
+		}
+		exprs = append(exprs, clause.Expr{SQL: "(' ' || " + normalized + " || ' ') LIKE ?", Vars: []interface{}{like}})
+	}
+	if amount, err := strconv.ParseFloat(word, 64); err == nil {
+		exprs = append(exprs, clause.Eq{Column: clause.Column{Name: "amount"}, Value: amount})
+	}
+	return clause.Or(exprs...)
+}
+
+// ListRemittances lists the caller's remittances, sent or received, newest
+// first; admins see every remittance. See paymentFilters for the search
+// parameters.
+func (h *RemittanceHandler) ListRemittances(c *gin.Context) {
+	var payments []models.Payment
+
+	filters, err := paymentFilters(c)
+	if err != nil {
+		c.Error(errors.NewValidationError("Invalid query parameters", err.Error()))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+	scope := func(db *gorm.DB) *gorm.DB {
+		if role == "admin" {
+			return db
+		}
+		return db.Where("(sender_id = ? OR recipient_id = ?)", userID, userID)
+	}
+
+	// Cache key based on the caller and the (canonically ordered) query params
+	cacheKey := fmt.Sprintf("payments:list:%v:%v:%s", userID, role, c.Request.URL.Query().Encode())
+
+	// Try cache
+	if found, _ := utils.GetCached(cacheKey, &payments); found {
+		c.Header("X-Cache", "HIT")
+		c.JSON(http.StatusOK, payments)
+		return
+	}
+
+	// DB query with pagination
+	if err := h.db.Scopes(scope, filters, Paginate(c)).Order("created_at DESC").Find(&payments).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to fetch payments", err))
+		return
+	}
+
+	// Set cache for 30 seconds
+	utils.SetCached(cacheKey, payments, 30*time.Second)
+
+	c.Header("X-Cache", "MISS")
+	c.JSON(http.StatusOK, payments)
+}
+
+type CompleteRemittanceRequest struct {
+	// ExecutedRate is the rate the conversion actually settled at. When
+	// omitted for a cross-currency remittance, the current rate is recorded.
+	ExecutedRate float64 `json:"executed_rate" binding:"omitempty,gt=0"`
+}
+
+// CompleteRemittance marks a submitted remittance completed. It needs the
+// transaction hash recorded on submission and, with StrictCompletion, a
+// successful transaction on Horizon; otherwise it is a 409 and an admin has
+// to use ForceCompleteRemittance.
+func (h *RemittanceHandler) CompleteRemittance(c *gin.Context) {
+	var req CompleteRemittanceRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(errors.NewBindingError(err))
+			return
+		}
+	}
+
+	payment, ok := h.completablePayment(c)
+	if !ok {
+		return
+	}
+
+	if err := utils.ValidateTxHash(payment.TxHash); err != nil {
+		c.Error(errors.NewConflictError("Remittance has no submitted transaction to confirm"))
+		return
+	}
+	if h.config.StrictCompletion {
+		ctx := utils.WithRequestContext(c.Request.Context(), c.GetString("requestID"), nil)
+		status, err := h.stellarClient.GetTransactionStatus(ctx, payment.TxHash)
+		if err != nil {
+			c.Error(errors.NewInternalError("Failed to confirm transaction", err))
+			return
+		}
+		if status != utils.TxStatusSuccess {
+			c.Error(errors.NewConflictError(fmt.Sprintf("Transaction is %s on the network, not confirmed", status)))
+			return
+		}
+	}
+
+	h.completePayment(c, payment, req.ExecutedRate)
+}
+
+type ForceCompleteRemittanceRequest struct {
+	// Reason explains why the remittance is completed without a confirmed
+	// transaction; it is kept in the audit log.
+	Reason       string  `json:"reason" binding:"required"`
+	ExecutedRate float64 `json:"executed_rate" binding:"omitempty,gt=0"`
+}
+
+// ForceCompleteRemittance lets an admin complete a remittance whose
+// transaction cannot be confirmed, e.g. one settled off-platform. The reason
+// is required and recorded in the audit log.
+func (h *RemittanceHandler) ForceCompleteRemittance(c *gin.Context) {
+	var req ForceCompleteRemittanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		c.Error(errors.NewValidationError("Invalid request body", "reason must not be blank"))
+		return
+	}
+
+	payment, ok := h.completablePayment(c)
+	if !ok {
+		return
+	}
+
+	adminID, _ := c.Get("userID")
+	logger.Log.WithFields(logrus.Fields{
+		"payment_id": payment.ID,
+		"admin_id":   adminID,
+		"tx_hash":    payment.TxHash,
+		"reason":     req.Reason,
+		"request_id": c.GetString("requestID"),
+	}).Warn("Remittance force-completed")
+
+	middleware.SetAuditReason(c, req.Reason)
+	h.completePayment(c, payment, req.ExecutedRate)
+}
+
+// completablePayment loads the remittance named by the route, reporting on
+// the context when it is missing or already in a terminal status.
+func (h *RemittanceHandler) completablePayment(c *gin.Context) (*models.Payment, bool) {
+	var payment models.Payment
+	if err := h.db.First(&payment, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("Payment not found").WithKey("error.payment_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch payment", err))
+		}
+		return nil, false
+	}
+
+	switch payment.Status {
+	case "completed", "failed", "cancelled", services.PaymentStatusExpired:
+		c.Error(errors.NewConflictError(fmt.Sprintf("Remittance is already %s", payment.Status)))
+		return nil, false
+	}
+	return &payment, true
+}
+
+// completePayment moves payment to completed, recording the executed rate
+// of a cross-currency remittance and marking its invoice paid.
+func (h *RemittanceHandler) completePayment(c *gin.Context, payment *models.Payment, executedRate float64) {
+	updates := map[string]interface{}{"status": "completed"}
+	if services.IsCrossCurrency(payment) {
+		if rate := h.executedRate(c, payment, executedRate); rate > 0 {
+			updates["executed_rate"] = rate
+			updates["converted_amount"] = services.ConvertAmount(payment.Amount, rate)
+		}
+	}
+
+	middleware.SetAuditOld(c, *payment)
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := payment.UpdateVersioned(tx, updates); err != nil {
+			return err
+		}
+		payment.Status = "completed"
+		if rate, ok := updates["executed_rate"].(float64); ok {
+			payment.ExecutedRate = rate
+			payment.ConvertedAmount = updates["converted_amount"].(float64)
+		}
+		_, err := services.NewInvoiceService(tx).MarkPaidForPayment(payment)
+		return err
+	})
+	if err != nil {
+		c.Error(paymentUpdateError(err, "Failed to update payment"))
+		return
+	}
+	h.events.PublishPayment(*payment)
+
+	h.notify(payment.SenderID, services.EventPaymentCompleted, *payment, "")
+	h.dispatch(payment.SenderID, utils.NotifyRemittanceCompleted, services.EventPaymentCompleted, *payment, "")
+	h.dispatch(payment.RecipientID, utils.NotifyRemittanceReceived, services.EventPaymentCompleted, *payment, "")
+
+	middleware.SetAuditNew(c, *payment)
+	c.JSON(http.StatusOK, payment)
+}
+
+// notify sends userID a notification about payment in the background, over
+// the channels their preferences allow.
+func (h *RemittanceHandler) notify(userID uint, event string, payment models.Payment, reason string) {
+	if h.notifier == nil {
+		return
+	}
+	go func() {
+		var user models.User
+		if err := h.db.First(&user, userID).Error; err != nil {
+			return
+		}
+		if _, err := h.notifier.NotifyPayment(&user, event, &payment, reason); err != nil {
+			logger.Log.WithFields(logrus.Fields{
+				"payment_id": payment.ID,
+				"event":      event,
+			}).WithError(err).Warn("Failed to send notification")
+		}
+	}()
+}
+
+// dispatch sends userID the lifecycle message for event in their locale, in
+// the background. Email follows the user's preference for prefEvent, and
+// placeholder recipients have no address to email. Delivery failures are
+// logged and never fail the request.
+func (h *RemittanceHandler) dispatch(userID uint, event, prefEvent string, payment models.Payment, reason string) {
+	if h.messenger == nil {
+		return
+	}
+	go func() {
+		log := logger.Log.WithFields(logrus.Fields{
+			"payment_id": payment.ID,
+			"user_id":    userID,
+			"event":      event,
+		})
+		var user models.User
+		if err := h.db.First(&user, userID).Error; err != nil {
+			log.WithError(err).Warn("Failed to load notification recipient")
+			return
+		}
+
+		to := utils.NotificationRecipient{Name: user.Name}
+		if !user.Unregistered {
+			emailEnabled := user.EmailNotifications
+			if h.notifier != nil {
+				channels, err := h.notifier.Channels(&user, prefEvent)
+				if err != nil {
+					log.WithError(err).Warn("Failed to load notification preferences")
+					return
+				}
+				emailEnabled = channels[models.ChannelEmail]
+			}
+			if emailEnabled {
+				to.Email = user.Email
+			}
+		}
+
+		msg, err := utils.RenderNotification(event, utils.NotificationLocale(user.Country, user.DefaultCurrency), to, utils.NotificationData{
+			Name:         user.Name,
+			RemittanceID: payment.ID,
+			Amount:       strconv.FormatFloat(payment.Amount, 'f', -1, 64),
+			Currency:     payment.Currency,
+			Reason:       reason,
+		})
+		if err != nil {
+			log.WithError(err).Error("Failed to render notification")
+			return
+		}
+		if err := h.messenger.Notify(context.Background(), msg); err != nil {
+			log.WithError(err).Warn("Failed to send notification")
+		}
+	}()
+}
+
+// quoteRate returns the current TargetCurrency-per-Currency rate.
+func (h *RemittanceHandler) quoteRate(ctx context.Context, from, to string) (float64, error) {
+	if h.rates == nil {
+		return 0, fmt.Errorf("%w: %s/%s", services.ErrRateUnavailable, from, to)
+	}
+	return services.ConversionRate(ctx, h.rates, from, to)
+}
+
+// executedRate picks the rate to record on completion: the one supplied by
+// the caller, else the current rate. Completion is never blocked on a missing
+// rate; the slippage report is simply unavailable for that payment.
+func (h *RemittanceHandler) executedRate(c *gin.Context, payment *models.Payment, supplied float64) float64 {
+	if supplied > 0 {
+		return supplied
+	}
+	rate, err := h.quoteRate(c.Request.Context(), payment.Currency, payment.TargetCurrency)
+	if err != nil {
+		logger.Log.WithFields(logrus.Fields{
+			"payment_id": payment.ID,
+			"request_id": c.GetString("requestID"),
+		}).WithError(err).Warn("No executed rate recorded for cross-currency remittance")
+		return 0
+	}
+	return rate
+}
+
+// GetSlippage compares the quoted and executed rates of a completed
+// cross-currency remittance.
+func (h *RemittanceHandler) GetSlippage(c *gin.Context) {
+	var payment models.Payment
+	if err := h.db.First(&payment, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("Payment not found").WithKey("error.payment_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch payment", err))
+		}
+		return
+	}
+
+	if !isSenderOrAdmin(c, &payment) {
+		c.Error(errors.NewForbiddenError("Only the sender or an admin can view this remittance"))
+		return
+	}
+	if payment.Status != "completed" {
+		c.Error(errors.NewConflictError(fmt.Sprintf("Slippage is only reported for completed remittances; this one is %s", payment.Status)))
+		return
+	}
+
+	report, err := services.Slippage(&payment)
+	if err != nil {
+		switch err {
+		case services.ErrNoConversion:
+			c.Error(errors.NewConflictError("Remittance has no currency conversion"))
+		case services.ErrRatesNotRecorded:
+			c.Error(errors.NewConflictError("Quoted or executed rate was not recorded for this remittance"))
+		default:
+			c.Error(errors.NewInternalError("Failed to compute slippage", err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+type CreateInvoiceRequest struct {
+	PaymentID   uint    `json:"payment_id" binding:"required"`
+	IssuerID    uint    `json:"issuer_id" binding:"required"`
+	RecipientID uint    `json:"recipient_id" binding:"required"`
+	Amount      float64 `json:"amount" binding:"required,gt=0"`
+	Currency    string  `json:"currency" binding:"required"`
+	Description string  `json:"description"`
+}
+
+func (h *RemittanceHandler) CreateInvoice(c *gin.Context) {
+	var req CreateInvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+
+	if _, err := utils.ExactAmount(req.Amount); err != nil {
+		c.Error(errors.NewValidationError("Invalid amount", err.Error()))
+		return
+	}
+	if err := utils.ValidateAssetCode(req.Currency); err != nil {
+		c.Error(errors.NewValidationError("Invalid currency", err.Error()))
+		return
+	}
+	if !requireSupportedCurrency(c, h.config, "currency", req.Currency, "") {
+		return
+	}
+
+	invoiceNo := fmt.Sprintf("INV-%d-%d", time.Now().Unix(), req.PaymentID)
+
+	invoice := models.Invoice{
+		PaymentID:   req.PaymentID,
+		InvoiceNo:   invoiceNo,
+		IssuerID:    req.IssuerID,
+		RecipientID: req.RecipientID,
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		Description: req.Description,
+		Status:      "unpaid",
+	}
+	if err := services.IssueInvoice(h.db, &invoice); err != nil {
+		c.Error(errors.NewInternalError("Failed to issue invoice", err))
+		return
+	}
+
+	if err := h.db.Create(&invoice).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to create invoice", err))
+		return
+	}
+
+	// Set response for idempotency caching
+	middleware.SetIdempotencyResponse(c, invoice)
+
+	c.JSON(http.StatusCreated, invoice)
+}
+
+// CreateBatchInvoicesRequest selects the payments to invoice: every payment
+// in a batch, or an explicit list. Exactly one of the two is required.
+type CreateBatchInvoicesRequest struct {
+	BatchID     string     `json:"batch_id" binding:"omitempty,max=36"`
+	PaymentIDs  []uint     `json:"payment_ids" binding:"omitempty,max=100"`
+	Description string     `json:"description"`
+	DueDate     *time.Time `json:"due_date"`
+}
+
+// CreateBatchInvoices issues one invoice per completed payment, numbered
+// consecutively from the shared invoice sequence and created all-or-nothing.
+// Only the payments' sender, or an admin, can invoice them. Payments that
+// already have an invoice are skipped, so the request can be repeated.
+func (h *RemittanceHandler) CreateBatchInvoices(c *gin.Context) {
+	var req CreateBatchInvoicesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+	if (req.BatchID == "") == (len(req.PaymentIDs) == 0) {
+		c.Error(errors.NewValidationError("Invalid request", "exactly one of batch_id or payment_ids is required"))
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+
+	var payments []models.Payment
+	query := h.db.Order("id")
+	if req.BatchID != "" {
+		query = query.Where("batch_id = ?", req.BatchID)
+	} else {
+		query = query.Where("id IN ?", req.PaymentIDs)
+	}
+	if err := query.Find(&payments).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to fetch payments", err))
+		return
+	}
+	if req.BatchID != "" && len(payments) == 0 {
+		c.Error(errors.NewNotFoundError("Batch not found"))
+		return
+	}
+	found := make(map[uint]bool, len(payments))
+	for _, payment := range payments {
+		found[payment.ID] = true
+	}
+	for _, id := range req.PaymentIDs {
+		if !found[id] {
+			c.Error(errors.NewNotFoundError(fmt.Sprintf("Payment %d not found", id)))
+			return
+		}
+	}
+
+	role, _ := c.Get("role")
+	for _, payment := range payments {
+		if role != "admin" && payment.SenderID != userID.(uint) {
+			c.Error(errors.NewForbiddenError("Only the sender or an admin can invoice these payments"))
+			return
+		}
+		if payment.Status != "completed" {
+			c.Error(errors.NewConflictError(fmt.Sprintf("Payment %d is %s; only completed payments can be invoiced", payment.ID, payment.Status)))
+			return
+		}
+	}
+
+	description := req.Description
+	if description == "" && req.BatchID != "" {
+		description = fmt.Sprintf("Payout from batch %s", req.BatchID)
+	}
+	result, err := h.invoices.GenerateForPayments(payments, description, req.DueDate)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to create invoices", err))
+		return
+	}
+
+	response := gin.H{
+		"invoice_numbers":     result.InvoiceNumbers(),
+		"invoices":            result.Invoices,
+		"skipped_payment_ids": result.Skipped,
+	}
+	middleware.SetIdempotencyResponse(c, response)
+
+	status := http.StatusCreated
+	if len(result.Invoices) == 0 {
+		status = http.StatusOK
+	}
+	c.JSON(status, response)
+}
+
+func (h *RemittanceHandler) GetInvoice(c *gin.Context) {
+	id := c.Param("id")
+	var invoice models.Invoice
+
+	if err := h.db.Preload("Payment").First(&invoice, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("Invoice not found").WithKey("error.invoice_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch invoice", err))
+		}
+		return
+	}
+	if !isInvoicePartyOrAdmin(c, &invoice) {
+		c.Error(errors.NewForbiddenError("Only the invoice issuer, recipient or an admin can view this invoice"))
+		return
+	}
+
+	c.JSON(http.StatusOK, invoice)
+}
+
+// isInvoicePartyOrAdmin reports whether the caller issued or was billed the
+// invoice, or is an admin.
+func isInvoicePartyOrAdmin(c *gin.Context, invoice *models.Invoice) bool {
+	if role, _ := c.Get("role"); role == "admin" {
+		return true
+	}
+	userID, ok := c.Get("userID")
+	if !ok {
+		return false
+	}
+	id, ok := userID.(uint)
+	return ok && (id == invoice.IssuerID || id == invoice.RecipientID)
+}
+
+// GetInvoicePDF renders the invoice as the PDF issued with it and streams it
+// to the client.
+func (h *RemittanceHandler) GetInvoicePDF(c *gin.Context) {
+	id := c.Param("id")
+	var invoice models.Invoice
+
+	if err := h.db.First(&invoice, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("Invoice not found").WithKey("error.invoice_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch invoice", err))
+		}
+		return
+	}
+	if !isInvoicePartyOrAdmin(c, &invoice) {
+		c.Error(errors.NewForbiddenError("Only the invoice issuer, recipient or an admin can view this invoice"))
+		return
+	}
+
+	pdfBytes, err := utils.RenderInvoicePDF(&invoice)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to generate invoice PDF", err))
+		return
+	}
+
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=invoice_%s.pdf", invoice.InvoiceNo))
+	c.Data(http.StatusOK, "application/pdf", pdfBytes)
+}
+
+// VerifyInvoicePDF regenerates the invoice PDF from the stored invoice and
+// checks it against the hash recorded when the invoice was issued. A
+// mismatch means the invoice record no longer matches the document handed out.
+func (h *RemittanceHandler) VerifyInvoicePDF(c *gin.Context) {
+	var invoice models.Invoice
+	if err := h.db.First(&invoice, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("Invoice not found").WithKey("error.invoice_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch invoice", err))
+		}
+		return
+	}
+	if !isInvoicePartyOrAdmin(c, &invoice) {
+		c.Error(errors.NewForbiddenError("Only the invoice issuer, recipient or an admin can verify this invoice"))
+		return
+	}
+	if invoice.PdfHash == "" {
+		c.Error(errors.NewConflictError("No PDF hash was recorded for this invoice"))
+		return
+	}
+
+	pdfBytes, err := utils.RenderInvoicePDF(&invoice)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to generate invoice PDF", err))
+		return
+	}
+	computed := utils.InvoicePDFHash(pdfBytes)
+
+	verified := computed == invoice.PdfHash
+	if !verified {
+		logger.Log.WithFields(logrus.Fields{
+			"invoice_id": invoice.ID,
+			"request_id": c.GetString("requestID"),
+		}).Warn("Invoice PDF hash mismatch")
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"invoice_id":    invoice.ID,
+		"verified":      verified,
+		"stored_hash":   invoice.PdfHash,
+		"computed_hash": computed,
+	})
+}
+
+type VoidInvoiceRequest struct {
+	Reason string `json:"reason"`
+}
+
+// VoidInvoice lets the invoice's issuer, or an admin, cancel an open invoice
+// whose linked payment has failed, so it does not sit "unpaid" indefinitely.
+func (h *RemittanceHandler) VoidInvoice(c *gin.Context) {
+	var req VoidInvoiceRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(errors.NewBindingError(err))
+			return
+		}
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+
+	id := c.Param("id")
+	var invoice models.Invoice
+	if err := h.db.First(&invoice, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("Invoice not found").WithKey("error.invoice_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch invoice", err))
+		}
+		return
+	}
+	if role, _ := c.Get("role"); role != "admin" && invoice.IssuerID != userID.(uint) {
+		c.Error(errors.NewForbiddenError("Only the invoice issuer or an admin can void this invoice"))
+		return
+	}
+
+	if invoice.Status == "paid" || invoice.Status == "cancelled" {
+		c.Error(errors.NewConflictError(fmt.Sprintf("Invoice is already %s", invoice.Status)))
+		return
+	}
+
+	middleware.SetAuditOld(c, invoice)
+	if _, err := h.invoices.VoidForFailedPayment(invoice.PaymentID, req.Reason); err != nil {
+		if err == services.ErrPaymentNotFailed {
+			c.Error(errors.NewConflictError("Only invoices linked to a failed payment can be voided"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to void invoice", err))
+		}
+		return
+	}
+
+	if err := h.db.Preload("Payment").First(&invoice, invoice.ID).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to fetch invoice", err))
+		return
+	}
+
+	middleware.SetAuditNew(c, invoice)
+	c.JSON(http.StatusOK, invoice)
+}
+
+type CancelInvoiceRequest struct {
+	Reason string `json:"reason" binding:"omitempty,max=255"`
+}
+
+// CancelInvoice lets the invoice's issuer, or an admin, cancel an invoice
+// that has not been paid. A linked remittance is left as it is; if it is
+// still pending the response carries a warning, since it may yet be sent.
+func (h *RemittanceHandler) CancelInvoice(c *gin.Context) {
+	var req CancelInvoiceRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(errors.NewBindingError(err))
+			return
+		}
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+
+	var invoice models.Invoice
+	if err := h.db.First(&invoice, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("Invoice not found").WithKey("error.invoice_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch invoice", err))
+		}
+		return
+	}
+	if role, _ := c.Get("role"); role != "admin" && invoice.IssuerID != userID.(uint) {
+		c.Error(errors.NewForbiddenError("Only the invoice issuer or an admin can cancel this invoice"))
+		return
+	}
+	if invoice.Status == "paid" || invoice.Status == "cancelled" {
+		c.Error(errors.NewConflictError(fmt.Sprintf("Invoice is already %s", invoice.Status)))
+		return
+	}
+
+	middleware.SetAuditOld(c, invoice)
+	if err := h.invoices.Cancel(&invoice, req.Reason); err != nil {
+		if err == services.ErrInvoiceNotOpen {
+			c.Error(errors.NewConflictError("Invoice was paid or cancelled concurrently"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to cancel invoice", err))
+		}
+		return
+	}
+	middleware.SetAuditNew(c, invoice)
+
+	response := gin.H{"invoice": invoice}
+	if invoice.PaymentID != 0 {
+		var payment models.Payment
+		if err := h.db.First(&payment, invoice.PaymentID).Error; err != nil && err != gorm.ErrRecordNotFound {
+			logger.Log.WithField("invoice_id", invoice.ID).WithError(err).Warn("Failed to check linked payment")
+		} else if err == nil && payment.Status == "pending" {
+			response["warning"] = fmt.Sprintf("Linked remittance #%d is still pending and was not cancelled; cancel it separately if it should not be sent", payment.ID)
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+type PayInvoiceRequest struct {
+	// SenderAccount is the payer's Stellar account that will sign the envelope.
+	SenderAccount string `json:"sender_account" binding:"required"`
+	// AssetIssuer issues the invoice currency; required unless it is XLM.
+	AssetIssuer string `json:"asset_issuer"`
+}
+
+// PayInvoice creates the remittance that pays an open invoice and links the
+// invoice to it. The remittance passes the same checks as CreateRemittance.
+// The payment, the link, and the escrow envelope are written atomically: if
+// any step fails, nothing is written. The invoice stays open until the
+// remittance completes, when MarkPaidForPayment marks it paid; if the
+// remittance fails instead, the invoice can be voided or paid again.
+func (h *RemittanceHandler) PayInvoice(c *gin.Context) {
+	var req PayInvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+
+	var invoice models.Invoice
+	if err := h.db.First(&invoice, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("Invoice not found").WithKey("error.invoice_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch invoice", err))
+		}
+		return
+	}
+	if invoice.RecipientID != userID.(uint) {
+		c.Error(errors.NewForbiddenError("Only the invoiced user can pay this invoice"))
+		return
+	}
+	if invoice.Status != "unpaid" && invoice.Status != "overdue" {
+		c.Error(errors.NewConflictError(fmt.Sprintf("Invoice is already %s", invoice.Status)))
+		return
+	}
+	if invoice.PaymentID != 0 {
+		var linked models.Payment
+		if err := h.db.First(&linked, invoice.PaymentID).Error; err != nil && err != gorm.ErrRecordNotFound {
+			c.Error(errors.NewInternalError("Failed to fetch linked payment", err))
+			return
+		} else if err == nil && (linked.Status == "pending" || linked.Status == "processing") {
+			c.Error(errors.NewConflictError(fmt.Sprintf("Invoice is already being paid by remittance #%d", linked.ID)))
+			return
+		}
+	}
+
+	var issuer models.User
+	if err := h.db.First(&issuer, invoice.IssuerID).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to fetch invoice issuer", err))
+		return
+	}
+	if issuer.StellarAddress == "" {
+		c.Error(errors.NewConflictError("Invoice issuer has no Stellar account to receive payment"))
+		return
+	}
+
+	ctx := utils.WithRequestContext(c.Request.Context(), c.GetString("requestID"), userID)
+	remittance := CreateRemittanceRequest{
+		SenderAccount:    req.SenderAccount,
+		RecipientAccount: issuer.StellarAddress,
+		Amount:           invoice.Amount,
+		AssetCode:        invoice.Currency,
+		AssetIssuer:      req.AssetIssuer,
+	}
+	pre, appErr := h.preflightRemittance(ctx, userID.(uint), &remittance, true)
+	if appErr != nil {
+		c.Error(appErr)
+		return
+	}
+	if len(pre.blocking) > 0 {
+		c.Error(pre.blocking[0])
+		return
+	}
+	feeBreakdown := pre.fees
+
+	escrowDestination := issuer.StellarAddress
+	if pre.settlement != "" {
+		escrowDestination = pre.settlement
+	}
+
+	middleware.SetAuditOld(c, invoice)
+	previousPaymentID := invoice.PaymentID
+	var payment models.Payment
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		payment = models.Payment{
+			SenderID:          userID.(uint),
+			SenderAccount:     req.SenderAccount,
+			RecipientID:       invoice.IssuerID,
+			RecipientAccount:  issuer.StellarAddress,
+			Amount:            invoice.Amount,
+			Currency:          invoice.Currency,
+			Status:            "pending",
+			Fee:               feeBreakdown.TotalFee,
+			PlatformFee:       feeBreakdown.PlatformFee,
+			ForexFee:          feeBreakdown.ForexFee,
+			ComplianceFee:     feeBreakdown.ComplianceFee,
+			NetworkFee:        feeBreakdown.NetworkFee,
+			Corridor:          feeBreakdown.Corridor,
+			Notes:             models.EncryptedString(fmt.Sprintf("Payment for invoice %s", invoice.InvoiceNo)),
+			SettlementAccount: pre.settlement,
+		}
+		if err := tx.Create(&payment).Error; err != nil {
+			return err
+		}
+
+		// Guard on the open statuses and the previous link so two concurrent
+		// payments cannot both be linked to the invoice.
+		result := tx.Model(&invoice).
+			Where("status IN ? AND payment_id = ?", []string{"unpaid", "overdue"}, previousPaymentID).
+			Update("payment_id", payment.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.NewConflictError("Invoice was paid concurrently")
+		}
+		invoice.PaymentID = payment.ID
+
+		xdr, err := h.stellarClient.BuildEscrowTx(ctx, req.SenderAccount, escrowDestination, invoice.Currency, req.AssetIssuer, pre.stellarAmount, nil)
+		if err != nil {
+			return errors.NewInternalError("Failed to build Stellar transaction", err)
+		}
+		payment.TxEnvelope = xdr
+		return payment.UpdateVersioned(tx, map[string]interface{}{"tx_envelope": xdr})
+	})
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.Error(appErr)
+		} else {
+			c.Error(errors.NewInternalError("Failed to pay invoice", err))
+		}
+		return
+	}
+
+	middleware.SetAuditNew(c, invoice)
+	response := gin.H{
+		"invoice":       invoice,
+		"remittance_id": payment.ID,
+		"status":        payment.Status,
+		"fee_breakdown": feeBreakdown,
+		"tx_envelope":   payment.TxEnvelope,
+	}
+	middleware.SetIdempotencyResponse(c, response)
+	c.JSON(http.StatusCreated, response)
 }
 
 type ListInvoicesResponse struct {
@@ -334,7 +2352,7 @@ type ListInvoicesResponse struct {
 func (h *RemittanceHandler) ListInvoices(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.Error(errors.NewUnauthorizedError("Unauthorized"))
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
 		return
 	}
 
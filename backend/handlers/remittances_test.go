@@ -1,70 +1,46 @@
 package handlers
 
 import (
-	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
 	"github.com/stretchr/testify/assert"
 	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/middleware"
 	"github.com/yourusername/gpay-remit/models"
-	"github.com/stellar/go/txnbuild"
-	"gorm.io/driver/sqlite"
-	"gorm.io/gorm"
+	"github.com/yourusername/gpay-remit/services"
+	"github.com/yourusername/gpay-remit/utils"
 )
 
-func setupTestDB() *gorm.DB {
-	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-	db.AutoMigrate(&models.Payment{}, &models.User{})
-	return db
-}
-
-type MockStellarClient struct {
-	ValidateAccountFunc func(accountID string) error
-	BuildEscrowTxFunc   func(sender, recipient, assetCode, issuer, amount string) (string, error)
-	SubmitPaymentFunc   func(sourceSecret, destination, assetCode, issuer, amount string) (string, error)
-	BuildPaymentTxFunc  func(sourceAccount txnbuild.Account, destination string, assetCode string, issuer string, amount string) (*txnbuild.Transaction, error)
-	SignTxFunc          func(envelopeXDR string, secretKey string) (string, error)
-}
-
-func (m *MockStellarClient) ValidateAccount(accountID string) error {
-	return m.ValidateAccountFunc(accountID)
-}
-
-func (m *MockStellarClient) BuildEscrowTx(sender, recipient, assetCode, issuer, amount string) (string, error) {
-	return m.BuildEscrowTxFunc(sender, recipient, assetCode, issuer, amount)
-}
-
-func (m *MockStellarClient) SubmitPayment(sourceSecret, destination, assetCode, issuer, amount string) (string, error) {
-	return m.SubmitPaymentFunc(sourceSecret, destination, assetCode, issuer, amount)
-}
-
-func (m *MockStellarClient) BuildPaymentTx(sourceAccount txnbuild.Account, destination string, assetCode string, issuer string, amount string) (*txnbuild.Transaction, error) {
-	return m.BuildPaymentTxFunc(sourceAccount, destination, assetCode, issuer, amount)
-}
-
-func (m *MockStellarClient) SignTx(envelopeXDR string, secretKey string) (string, error) {
-	return m.SignTxFunc(envelopeXDR, secretKey)
-}
-
-
 func TestCreateRemittance(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	db := setupTestDB()
+	issuer, _ := keypair.Random()
+	db.Create(&models.User{Email: "alice@example.com", Name: "Alice", StellarAddress: "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X", PasswordHash: "x"})
 	mockStellar := &MockStellarClient{
 		ValidateAccountFunc: func(accountID string) error { return nil },
-		BuildEscrowTxFunc:   func(sender, recipient, assetCode, issuer, amount string) (string, error) { return "base64_xdr", nil },
+		BuildEscrowTxFunc: func(sender, recipient, assetCode, issuer, amount string, memo txnbuild.Memo) (string, error) {
+			return "base64_xdr", nil
+		},
 	}
 	handler := &RemittanceHandler{
 		db:            db,
 		config:        &config.Config{},
 		stellarClient: mockStellar,
+		fees:          services.NewFeeService(&config.Config{}),
 	}
 
 	router := gin.Default()
+	router.Use(middleware.ErrorHandler())
 	router.Use(func(c *gin.Context) {
 		c.Set("userID", uint(1))
 		c.Next()
@@ -73,16 +49,14 @@ func TestCreateRemittance(t *testing.T) {
 
 	t.Run("Valid Request", func(t *testing.T) {
 		reqBody := CreateRemittanceRequest{
-			SenderAccount:   "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
-			RecipientAccount: "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
-			Amount:          100.50,
-			AssetCode:       "USDC",
-			Conditions:      map[string]interface{}{"note": "test"},
+			SenderAccount:    "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+			RecipientAccount: "GDRXV6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+			Amount:           100.50,
+			AssetCode:        "USDC",
+			AssetIssuer:      issuer.Address(),
+			Conditions:       map[string]interface{}{"note": "test"},
 		}
-		body, _ := json.Marshal(reqBody)
-		w := httptest.NewRecorder()
-		req, _ := http.NewRequest("POST", "/remittances/create", bytes.NewBuffer(body))
-		router.ServeHTTP(w, req)
+		w := serveJSON(router, http.MethodPost, "/remittances/create", reqBody)
 
 		assert.Equal(t, http.StatusCreated, w.Code)
 		assert.Contains(t, w.Body.String(), "base64_xdr")
@@ -91,19 +65,18 @@ func TestCreateRemittance(t *testing.T) {
 		db.First(&payment)
 		assert.Equal(t, 100.50, payment.Amount)
 		assert.Equal(t, "USDC", payment.Currency)
+		assert.NotZero(t, payment.RecipientID)
 	})
 
 	t.Run("Invalid Amount", func(t *testing.T) {
 		reqBody := CreateRemittanceRequest{
 			SenderAccount:    "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
-			RecipientAccount: "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+			RecipientAccount: "GDRXV6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
 			Amount:           -10,
 			AssetCode:        "USDC",
+			AssetIssuer:      issuer.Address(),
 		}
-		body, _ := json.Marshal(reqBody)
-		w := httptest.NewRecorder()
-		req, _ := http.NewRequest("POST", "/remittances/create", bytes.NewBuffer(body))
-		router.ServeHTTP(w, req)
+		w := serveJSON(router, http.MethodPost, "/remittances/create", reqBody)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
@@ -111,60 +84,200 @@ func TestCreateRemittance(t *testing.T) {
 	t.Run("Zero Amount", func(t *testing.T) {
 		reqBody := CreateRemittanceRequest{
 			SenderAccount:    "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
-			RecipientAccount: "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+			RecipientAccount: "GDRXV6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
 			Amount:           0,
 			AssetCode:        "USDC",
+			AssetIssuer:      issuer.Address(),
 		}
-		body, _ := json.Marshal(reqBody)
-		w := httptest.NewRecorder()
-		req, _ := http.NewRequest("POST", "/remittances/create", bytes.NewBuffer(body))
-		router.ServeHTTP(w, req)
+		w := serveJSON(router, http.MethodPost, "/remittances/create", reqBody)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
+	t.Run("Binding Errors List The Bad Fields", func(t *testing.T) {
+		post := func(body map[string]interface{}) []errors.FieldError {
+			w := serveJSON(router, http.MethodPost, "/remittances/create", body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var resp struct {
+				Error struct {
+					Code    errors.ErrorCode    `json:"code"`
+					Details []errors.FieldError `json:"details"`
+				} `json:"error"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, errors.CodeValidation, resp.Error.Code)
+			return resp.Error.Details
+		}
+		body := map[string]interface{}{
+			"sender_account":    "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+			"recipient_account": "GDRXV6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+			"asset_code":        "USDC",
+		}
+
+		assert.Equal(t, []errors.FieldError{{Field: "amount", Rule: "required", Message: "amount is required"}}, post(body))
+
+		body["amount"] = -5
+		assert.Equal(t, []errors.FieldError{{Field: "amount", Rule: "gt", Message: "amount must be greater than 0"}}, post(body))
+	})
+
+	t.Run("Conditions Are Checked Against The Schema", func(t *testing.T) {
+		post := func(conditions map[string]interface{}) *httptest.ResponseRecorder {
+			return serveJSON(router, http.MethodPost, "/remittances/create", CreateRemittanceRequest{
+				SenderAccount:    "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+				RecipientAccount: "GDRXV6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+				Amount:           10,
+				AssetCode:        "USDC",
+				AssetIssuer:      issuer.Address(),
+				Conditions:       conditions,
+			})
+		}
+
+		w := post(map[string]interface{}{"colour": "blue", "recipient_approval": "yes"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var resp struct {
+			Error struct {
+				Details []errors.FieldError `json:"details"`
+			} `json:"error"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, []errors.FieldError{
+			{Field: "conditions.colour", Rule: "unknown", Message: "colour is not a supported condition"},
+			{Field: "conditions.recipient_approval", Rule: "type", Message: "recipient_approval must be true or false"},
+		}, resp.Error.Details)
+
+		w = post(map[string]interface{}{"recipient_approval": true, "note": "school fees"})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var created struct {
+			RemittanceID uint `json:"remittance_id"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &created)
+		var payment models.Payment
+		db.First(&payment, created.RemittanceID)
+		assert.JSONEq(t, `{"recipient_approval":true,"note":"school fees"}`, payment.Conditions.String())
+	})
+
+	t.Run("Invalid Conditions", func(t *testing.T) {
+		for name, conditions := range map[string]map[string]interface{}{
+			"unknown key":          {"deliver_by": "tomorrow"},
+			"release_after passed": {"release_after": time.Now().Add(-time.Hour).Format(time.RFC3339)},
+			"malformed hashlock":   {"hashlock": "not-a-hash"},
+		} {
+			reqBody := CreateRemittanceRequest{
+				SenderAccount:    "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+				RecipientAccount: "GDRXV6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+				Amount:           10,
+				AssetCode:        "USDC",
+				AssetIssuer:      issuer.Address(),
+				Conditions:       conditions,
+			}
+			w := serveJSON(router, http.MethodPost, "/remittances/create", reqBody)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, name)
+		}
+	})
+
+	t.Run("Base Fee Override Limits", func(t *testing.T) {
+		handler.config = &config.Config{MaxBaseFee: 1000}
+		defer func() { handler.config = &config.Config{} }()
+
+		for fee, want := range map[int64]int{50: http.StatusBadRequest, 5000: http.StatusBadRequest, 1000: http.StatusCreated} {
+			reqBody := CreateRemittanceRequest{
+				SenderAccount:    "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+				RecipientAccount: "GDRXV6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+				Amount:           10,
+				AssetCode:        "USDC",
+				AssetIssuer:      issuer.Address(),
+				BaseFee:          fee,
+			}
+			w := serveJSON(router, http.MethodPost, "/remittances/create", reqBody)
+
+			assert.Equal(t, want, w.Code, "base_fee %d", fee)
+		}
+	})
+
+	t.Run("Amount Beyond Stroop Precision", func(t *testing.T) {
+		reqBody := CreateRemittanceRequest{
+			SenderAccount:    "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+			RecipientAccount: "GDRXV6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+			Amount:           10.123456789,
+			AssetCode:        "USDC",
+			AssetIssuer:      issuer.Address(),
+		}
+		w := serveJSON(router, http.MethodPost, "/remittances/create", reqBody)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid amount")
+	})
+
 	t.Run("Missing Asset Code", func(t *testing.T) {
 		reqBody := map[string]interface{}{
 			"sender_account":    "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
-			"recipient_account": "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+			"recipient_account": "GDRXV6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
 			"amount":            100,
 		}
-		body, _ := json.Marshal(reqBody)
-		w := httptest.NewRecorder()
-		req, _ := http.NewRequest("POST", "/remittances/create", bytes.NewBuffer(body))
-		router.ServeHTTP(w, req)
+		w := serveJSON(router, http.MethodPost, "/remittances/create", reqBody)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Asset Code Too Long", func(t *testing.T) {
+		reqBody := CreateRemittanceRequest{
+			SenderAccount:    "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+			RecipientAccount: "GDRXV6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+			Amount:           100,
+			AssetCode:        "ABCDEFGHIJKLMNOPQRST",
+			AssetIssuer:      issuer.Address(),
+		}
+		w := serveJSON(router, http.MethodPost, "/remittances/create", reqBody)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "maximum is 12")
+	})
+
+	t.Run("Credit Asset Without Issuer", func(t *testing.T) {
+		var before int64
+		db.Model(&models.Payment{}).Count(&before)
+
+		reqBody := CreateRemittanceRequest{
+			SenderAccount:    "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+			RecipientAccount: "GDRXV6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+			Amount:           100,
+			AssetCode:        "USDC",
+		}
+		w := serveJSON(router, http.MethodPost, "/remittances/create", reqBody)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "requires an issuer")
+
+		var after int64
+		db.Model(&models.Payment{}).Count(&after)
+		assert.Equal(t, before, after)
 	})
 
 	t.Run("Stellar Client Failure", func(t *testing.T) {
 		failHandler := &RemittanceHandler{
 			db:     db,
 			config: &config.Config{},
+			fees:   services.NewFeeService(&config.Config{}),
 			stellarClient: &MockStellarClient{
 				ValidateAccountFunc: func(accountID string) error { return nil },
-				BuildEscrowTxFunc: func(sender, recipient, assetCode, issuer, amount string) (string, error) {
+				BuildEscrowTxFunc: func(sender, recipient, assetCode, issuer, amount string, memo txnbuild.Memo) (string, error) {
 					return "", assert.AnError
 				},
 			},
 		}
-		failRouter := gin.New()
-		failRouter.Use(func(c *gin.Context) {
-			c.Set("userID", uint(1))
-			c.Next()
-		})
+		failRouter := newTestRouter(1, "")
 		failRouter.POST("/remittances/create", failHandler.CreateRemittance)
 
 		reqBody := CreateRemittanceRequest{
 			SenderAccount:    "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
-			RecipientAccount: "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+			RecipientAccount: "GDRXV6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
 			Amount:           50,
 			AssetCode:        "USDC",
+			AssetIssuer:      issuer.Address(),
 		}
-		body, _ := json.Marshal(reqBody)
-		w := httptest.NewRecorder()
-		req, _ := http.NewRequest("POST", "/remittances/create", bytes.NewBuffer(body))
-		failRouter.ServeHTTP(w, req)
+		w := serveJSON(failRouter, http.MethodPost, "/remittances/create", reqBody)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 	})
@@ -172,15 +285,554 @@ func TestCreateRemittance(t *testing.T) {
 	t.Run("Large Amount", func(t *testing.T) {
 		reqBody := CreateRemittanceRequest{
 			SenderAccount:    "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
-			RecipientAccount: "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+			RecipientAccount: "GDRXV6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
 			Amount:           999999999.99,
 			AssetCode:        "USDC",
+			AssetIssuer:      issuer.Address(),
+		}
+		w := serveJSON(router, http.MethodPost, "/remittances/create", reqBody)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+}
+
+func TestCreateBatchRemittance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	sender, _ := keypair.Random()
+	recipients := make([]string, 3)
+	for i := range recipients {
+		kp, _ := keypair.Random()
+		recipients[i] = kp.Address()
+	}
+	unknown, _ := keypair.Random()
+
+	var builtOps []utils.BatchPayment
+	handler := &RemittanceHandler{
+		db:     db,
+		config: &config.Config{},
+		fees:   services.NewFeeService(&config.Config{}),
+		stellarClient: &MockStellarClient{
+			ValidateAccountFunc: func(accountID string) error {
+				if accountID == unknown.Address() {
+					return fmt.Errorf("account not found")
+				}
+				return nil
+			},
+			BuildBatchPaymentTxFunc: func(sourceAccount string, payments []utils.BatchPayment) (string, error) {
+				builtOps = payments
+				return "batch_xdr", nil
+			},
+		},
+	}
+
+	router := newTestRouter(1, "")
+	router.POST("/remittances/batch", handler.CreateBatchRemittance)
+
+	post := func(req CreateBatchRemittanceRequest) *httptest.ResponseRecorder {
+		return serveJSON(router, http.MethodPost, "/remittances/batch", req)
+	}
+
+	t.Run("Valid three item batch", func(t *testing.T) {
+		req := CreateBatchRemittanceRequest{SenderAccount: sender.Address()}
+		for i, recipient := range recipients {
+			req.Items = append(req.Items, BatchRemittanceItem{RecipientAccount: recipient, Amount: float64(10 * (i + 1)), AssetCode: "XLM"})
 		}
-		body, _ := json.Marshal(reqBody)
-		w := httptest.NewRecorder()
-		req, _ := http.NewRequest("POST", "/remittances/create", bytes.NewBuffer(body))
-		router.ServeHTTP(w, req)
 
+		w := post(req)
 		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var resp struct {
+			BatchID       string `json:"batch_id"`
+			RemittanceIDs []uint `json:"remittance_ids"`
+			TxEnvelope    string `json:"tx_envelope"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NotEmpty(t, resp.BatchID)
+		assert.Len(t, resp.RemittanceIDs, 3)
+		assert.Equal(t, "batch_xdr", resp.TxEnvelope)
+		if assert.Len(t, builtOps, 3) {
+			assert.Equal(t, recipients[2], builtOps[2].Destination)
+			assert.Equal(t, "30.0000000", builtOps[2].Amount)
+		}
+
+		var payments []models.Payment
+		db.Where("batch_id = ?", resp.BatchID).Order("id").Find(&payments)
+		if assert.Len(t, payments, 3) {
+			assert.Equal(t, recipients[0], payments[0].RecipientAccount)
+			assert.Equal(t, "pending", payments[0].Status)
+		}
+	})
+
+	t.Run("Invalid recipient rejects whole batch", func(t *testing.T) {
+		var before int64
+		db.Model(&models.Payment{}).Count(&before)
+		builtOps = nil
+
+		w := post(CreateBatchRemittanceRequest{
+			SenderAccount: sender.Address(),
+			Items: []BatchRemittanceItem{
+				{RecipientAccount: recipients[0], Amount: 10, AssetCode: "XLM"},
+				{RecipientAccount: unknown.Address(), Amount: 20, AssetCode: "XLM"},
+				{RecipientAccount: recipients[1], Amount: 30, AssetCode: "XLM"},
+			},
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "items[1].recipient_account")
+		assert.Nil(t, builtOps)
+
+		var after int64
+		db.Model(&models.Payment{}).Count(&after)
+		assert.Equal(t, before, after)
+	})
+
+	t.Run("Too many operations", func(t *testing.T) {
+		req := CreateBatchRemittanceRequest{SenderAccount: sender.Address()}
+		for i := 0; i <= utils.MaxOperationsPerTx; i++ {
+			req.Items = append(req.Items, BatchRemittanceItem{RecipientAccount: recipients[0], Amount: 1, AssetCode: "XLM"})
+		}
+
+		w := post(req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "maximum of 100 operations")
+	})
+}
+
+func TestCreateRemittanceCorridorPricing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	sender := models.User{Email: "us@example.com", Name: "Sender", Country: "US", StellarAddress: "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X", PasswordHash: "x"}
+	recipient := models.User{Email: "mx@example.com", Name: "Recipient", Country: "MX", StellarAddress: "GDRXV6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X", PasswordHash: "x"}
+	db.Create(&sender)
+	db.Create(&recipient)
+
+	feeCfg := &config.Config{
+		PlatformFeeBps: 50,
+		ForexFeeBps:    25,
+		Corridors:      map[string]config.Corridor{"US-MX": {FXMarkupBps: 40, FixedFee: 1.5}},
+	}
+	handler := &RemittanceHandler{
+		db:     db,
+		config: &config.Config{},
+		stellarClient: &MockStellarClient{
+			ValidateAccountFunc: func(accountID string) error { return nil },
+			BuildEscrowTxFunc: func(sender, recipient, assetCode, issuer, amount string, memo txnbuild.Memo) (string, error) {
+				return "base64_xdr", nil
+			},
+		},
+		fees: services.NewFeeService(feeCfg),
+	}
+	router := newTestRouter(sender.ID, "")
+	router.POST("/remittances/create", handler.CreateRemittance)
+
+	create := func(recipientAccount string) models.Payment {
+		w := serveJSON(router, http.MethodPost, "/remittances/create", CreateRemittanceRequest{
+			SenderAccount:    sender.StellarAddress,
+			RecipientAccount: recipientAccount,
+			Amount:           1000,
+			AssetCode:        "XLM",
+		})
+		assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var resp struct {
+			RemittanceID uint `json:"remittance_id"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		var payment models.Payment
+		db.First(&payment, resp.RemittanceID)
+		return payment
+	}
+
+	t.Run("Configured corridor", func(t *testing.T) {
+		payment := create(recipient.StellarAddress)
+		assert.Equal(t, "US-MX", payment.Corridor)
+		// Base 5.00 platform and 2.50 forex, plus 1.50 fixed and 4.00 markup.
+		assert.Equal(t, 6.5, payment.PlatformFee)
+		assert.Equal(t, 6.5, payment.ForexFee)
+		assert.Equal(t, 13.0, payment.Fee)
+	})
+
+	t.Run("Unknown recipient falls back to the default", func(t *testing.T) {
+		payment := create("GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7")
+		assert.Equal(t, services.DefaultCorridor, payment.Corridor)
+		assert.Equal(t, 7.5, payment.Fee)
+	})
+}
+
+func TestCreateRemittanceResolvesRecipient(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	issuer, _ := keypair.Random()
+	sender, _ := keypair.Random()
+	registered, _ := keypair.Random()
+	newcomer, _ := keypair.Random()
+	db.Create(&models.User{Email: "alice@example.com", Name: "Alice", StellarAddress: sender.Address(), PasswordHash: "x"})
+
+	handler := &RemittanceHandler{
+		db:     db,
+		config: &config.Config{},
+		fees:   services.NewFeeService(&config.Config{}),
+		stellarClient: &MockStellarClient{
+			ValidateAccountFunc: func(accountID string) error { return nil },
+			BuildEscrowTxFunc: func(sender, recipient, assetCode, issuer, amount string, memo txnbuild.Memo) (string, error) {
+				return "base64_xdr", nil
+			},
+		},
+	}
+	router := newTestRouter(1, "")
+	router.POST("/remittances/create", handler.CreateRemittance)
+
+	create := func(recipient string) models.Payment {
+		w := serveJSON(router, http.MethodPost, "/remittances/create", CreateRemittanceRequest{
+			SenderAccount:    sender.Address(),
+			RecipientAccount: recipient,
+			Amount:           10,
+			AssetCode:        "USDC",
+			AssetIssuer:      issuer.Address(),
+		})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		var payment models.Payment
+		db.First(&payment, uint(resp["remittance_id"].(float64)))
+		return payment
+	}
+
+	t.Run("Existing recipient", func(t *testing.T) {
+		user := models.User{Email: "bob@example.com", Name: "Bob", StellarAddress: registered.Address(), PasswordHash: "x"}
+		db.Create(&user)
+
+		payment := create(registered.Address())
+		assert.Equal(t, user.ID, payment.RecipientID)
+	})
+
+	t.Run("Unknown recipient gets a placeholder", func(t *testing.T) {
+		payment := create(newcomer.Address())
+		assert.NotZero(t, payment.RecipientID)
+
+		var placeholder models.User
+		db.First(&placeholder, payment.RecipientID)
+		assert.True(t, placeholder.Unregistered)
+		assert.Equal(t, newcomer.Address(), placeholder.StellarAddress)
+
+		// Later remittances to the same address reuse the placeholder.
+		assert.Equal(t, payment.RecipientID, create(newcomer.Address()).RecipientID)
+	})
+}
+
+func TestSelfRemittanceRefused(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	issuer, _ := keypair.Random()
+	sender, _ := keypair.Random()
+	wallet, _ := keypair.Random()
+	recipient, _ := keypair.Random()
+	user := models.User{Email: "self@example.com", Name: "Self", StellarAddress: wallet.Address(), PasswordHash: "x"}
+	db.Create(&user)
+
+	handler := &RemittanceHandler{
+		db:     db,
+		config: &config.Config{},
+		fees:   services.NewFeeService(&config.Config{}),
+		stellarClient: &MockStellarClient{
+			ValidateAccountFunc: func(accountID string) error { return nil },
+			BuildEscrowTxFunc: func(sender, recipient, assetCode, issuer, amount string, memo txnbuild.Memo) (string, error) {
+				return "base64_xdr", nil
+			},
+		},
+	}
+	router := newTestRouter(user.ID, "")
+	router.POST("/remittances", handler.SendRemittance)
+	router.POST("/remittances/create", handler.CreateRemittance)
+
+	post := func(path string, body interface{}) *httptest.ResponseRecorder {
+		return serveJSON(router, http.MethodPost, path, body)
+	}
+	create := func(senderAccount, recipientAccount string) *httptest.ResponseRecorder {
+		return post("/remittances/create", CreateRemittanceRequest{
+			SenderAccount:    senderAccount,
+			RecipientAccount: recipientAccount,
+			Amount:           10,
+			AssetCode:        "USDC",
+			AssetIssuer:      issuer.Address(),
+		})
+	}
+	assertRefused := func(t *testing.T, w *httptest.ResponseRecorder) {
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var resp middleware.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, errors.CodeSelfRemittanceNotAllowed, resp.Error.Code)
+	}
+
+	t.Run("Same account after normalizing", func(t *testing.T) {
+		assertRefused(t, create(sender.Address(), " "+strings.ToLower(sender.Address())+" "))
+	})
+
+	t.Run("Recipient account belongs to the sender", func(t *testing.T) {
+		assertRefused(t, create(sender.Address(), wallet.Address()))
+	})
+
+	t.Run("Same user by ID", func(t *testing.T) {
+		assertRefused(t, post("/remittances", SendRemittanceRequest{SenderID: user.ID, RecipientID: user.ID, Amount: 10, Currency: "USD"}))
+	})
+
+	t.Run("Distinct sender and recipient", func(t *testing.T) {
+		assert.Equal(t, http.StatusCreated, create(sender.Address(), recipient.Address()).Code)
+		assert.Equal(t, http.StatusCreated, post("/remittances", SendRemittanceRequest{SenderID: user.ID, RecipientID: user.ID + 1, Amount: 10, Currency: "USD"}).Code)
+
+		var count int64
+		db.Model(&models.Payment{}).Count(&count)
+		assert.Equal(t, int64(2), count)
+	})
+}
+
+func TestCreateRemittanceTrustlineWarning(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	sender, _ := keypair.Random()
+	issuer, _ := keypair.Random()
+	withTrustline, _ := keypair.Random()
+	withoutTrustline, _ := keypair.Random()
+
+	handler := &RemittanceHandler{
+		db:     db,
+		config: &config.Config{CheckRecipientTrustline: true},
+		fees:   services.NewFeeService(&config.Config{}),
+		stellarClient: &MockStellarClient{
+			ValidateAccountFunc: func(accountID string) error { return nil },
+			BuildEscrowTxFunc: func(sender, recipient, assetCode, issuer, amount string, memo txnbuild.Memo) (string, error) {
+				return "base64_xdr", nil
+			},
+			GetBalancesFunc: func(accountID string) ([]utils.AccountBalance, error) {
+				balances := []utils.AccountBalance{{AssetType: "native", AssetCode: "XLM", Balance: "5.0000000"}}
+				if accountID == withTrustline.Address() {
+					balances = append(balances, utils.AccountBalance{AssetType: "credit_alphanum4", AssetCode: "USDC", AssetIssuer: issuer.Address(), Balance: "0.0000000"})
+				}
+				return balances, nil
+			},
+		},
+	}
+
+	router := newTestRouter(1, "")
+	router.POST("/remittances/create", handler.CreateRemittance)
+
+	create := func(recipient string) map[string]interface{} {
+		w := serveJSON(router, http.MethodPost, "/remittances/create", CreateRemittanceRequest{
+			SenderAccount:    sender.Address(),
+			RecipientAccount: recipient,
+			Amount:           10,
+			AssetCode:        "USDC",
+			AssetIssuer:      issuer.Address(),
+		})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp
+	}
+
+	assert.Contains(t, create(withoutTrustline.Address())["warning"], "no trustline for USDC")
+	assert.NotContains(t, create(withTrustline.Address()), "warning")
+}
+
+func TestCreateRemittanceMemo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	var builtMemo txnbuild.Memo
+	handler := &RemittanceHandler{
+		db:     db,
+		config: &config.Config{},
+		fees:   services.NewFeeService(&config.Config{}),
+		stellarClient: &MockStellarClient{
+			ValidateAccountFunc: func(accountID string) error { return nil },
+			BuildEscrowTxFunc: func(sender, recipient, assetCode, issuer, amount string, memo txnbuild.Memo) (string, error) {
+				builtMemo = memo
+				return "base64_xdr", nil
+			},
+		},
+	}
+
+	router := newTestRouter(1, "")
+	router.POST("/remittances/create", handler.CreateRemittance)
+
+	sender, _ := keypair.Random()
+	recipient, _ := keypair.Random()
+	db.Create(&models.User{Email: "alice@example.com", Name: "Alice", StellarAddress: sender.Address(), PasswordHash: "x"})
+	create := func(memoType, memo string) *httptest.ResponseRecorder {
+		return serveJSON(router, http.MethodPost, "/remittances/create", CreateRemittanceRequest{
+			SenderAccount:    sender.Address(),
+			RecipientAccount: recipient.Address(),
+			Amount:           10,
+			AssetCode:        "XLM",
+			Memo:             memo,
+			MemoType:         memoType,
+		})
+	}
+
+	t.Run("Text memo propagated and stored", func(t *testing.T) {
+		w := create("", "INV-42")
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, txnbuild.MemoText("INV-42"), builtMemo)
+
+		var payment models.Payment
+		db.Last(&payment)
+		assert.Equal(t, "INV-42", payment.Memo)
+		assert.Equal(t, "text", payment.MemoType)
+	})
+
+	t.Run("ID memo propagated", func(t *testing.T) {
+		w := create("id", "987654321")
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, txnbuild.MemoID(987654321), builtMemo)
+	})
+
+	t.Run("Text memo over 28 bytes rejected", func(t *testing.T) {
+		w := create("text", "this memo is definitely longer than 28 bytes")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Invalid id memo rejected", func(t *testing.T) {
+		w := create("id", "-5")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestCreateRemittanceSettlementRouting(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	eurAccount, _ := keypair.Random()
+	usdcAccount, _ := keypair.Random()
+	cfg := &config.Config{SettlementAccounts: map[string]string{
+		"EUR":  eurAccount.Address(),
+		"USDC": usdcAccount.Address(),
+	}}
+
+	var escrowDestination string
+	handler := &RemittanceHandler{
+		db:     db,
+		config: cfg,
+		fees:   services.NewFeeService(&config.Config{}),
+		stellarClient: &MockStellarClient{
+			ValidateAccountFunc: func(accountID string) error { return nil },
+			BuildEscrowTxFunc: func(sender, recipient, assetCode, issuer, amount string, memo txnbuild.Memo) (string, error) {
+				escrowDestination = recipient
+				return "base64_xdr", nil
+			},
+		},
+	}
+
+	router := newTestRouter(1, "")
+	router.POST("/remittances/create", handler.CreateRemittance)
+
+	sender, _ := keypair.Random()
+	recipient, _ := keypair.Random()
+	issuer, _ := keypair.Random()
+	create := func(assetCode string) *httptest.ResponseRecorder {
+		return serveJSON(router, http.MethodPost, "/remittances/create", CreateRemittanceRequest{
+			SenderAccount:    sender.Address(),
+			RecipientAccount: recipient.Address(),
+			Amount:           100,
+			AssetCode:        assetCode,
+			AssetIssuer:      issuer.Address(),
+		})
+	}
+
+	t.Run("EUR remittance uses the EUR settlement account", func(t *testing.T) {
+		w := create("EUR")
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, eurAccount.Address(), escrowDestination)
+
+		var payment models.Payment
+		db.Last(&payment)
+		assert.Equal(t, eurAccount.Address(), payment.SettlementAccount)
+	})
+
+	t.Run("Unconfigured currency rejected", func(t *testing.T) {
+		var before int64
+		db.Model(&models.Payment{}).Count(&before)
+
+		w := create("GBP")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "No settlement account configured for GBP")
+
+		var after int64
+		db.Model(&models.Payment{}).Count(&after)
+		assert.Equal(t, before, after)
+	})
+}
+
+func TestCreateRemittanceIssuerPolicy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	trusted, _ := keypair.Random()
+	denied, _ := keypair.Random()
+	unlisted, _ := keypair.Random()
+	db.Create(&models.User{Email: "alice@example.com", Name: "Alice", StellarAddress: "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X", PasswordHash: "x"})
+	cfg := &config.Config{
+		AssetIssuerAllowlist: []string{trusted.Address(), denied.Address()},
+		AssetIssuerDenylist:  []string{denied.Address()},
+	}
+	handler := &RemittanceHandler{
+		db:     db,
+		config: cfg,
+		stellarClient: &MockStellarClient{
+			ValidateAccountFunc: func(accountID string) error { return nil },
+			BuildEscrowTxFunc: func(sender, recipient, assetCode, issuer, amount string, memo txnbuild.Memo) (string, error) {
+				return "base64_xdr", nil
+			},
+		},
+		fees: services.NewFeeService(cfg),
+	}
+
+	router := newTestRouter(1, "")
+	router.POST("/remittances/create", handler.CreateRemittance)
+	router.GET("/admin/asset-issuers", handler.GetIssuerPolicy)
+
+	create := func(assetCode, issuer string) *httptest.ResponseRecorder {
+		return serveJSON(router, http.MethodPost, "/remittances/create", CreateRemittanceRequest{
+			SenderAccount:    "GCO7V6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+			RecipientAccount: "GDRXV6V6VZ5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X6Z5X",
+			Amount:           10,
+			AssetCode:        assetCode,
+			AssetIssuer:      issuer,
+		})
+	}
+	assertRefused := func(t *testing.T, w *httptest.ResponseRecorder) {
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		var resp middleware.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, errors.CodeIssuerNotAllowed, resp.Error.Code)
+	}
+
+	t.Run("Allowed issuer", func(t *testing.T) {
+		assert.Equal(t, http.StatusCreated, create("USDC", trusted.Address()).Code)
+	})
+
+	t.Run("Denied issuer", func(t *testing.T) {
+		assertRefused(t, create("USDC", denied.Address()))
+	})
+
+	t.Run("Issuer missing from allowlist", func(t *testing.T) {
+		assertRefused(t, create("USDC", unlisted.Address()))
+	})
+
+	t.Run("XLM bypasses the lists", func(t *testing.T) {
+		assert.Equal(t, http.StatusCreated, create("XLM", "").Code)
+	})
+
+	t.Run("Admin can read the policy", func(t *testing.T) {
+		w := serveJSON(router, http.MethodGet, "/admin/asset-issuers", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var policy utils.IssuerPolicy
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &policy))
+		assert.Equal(t, cfg.AssetIssuerAllowlist, policy.Allowed)
+		assert.Equal(t, cfg.AssetIssuerDenylist, policy.Denied)
 	})
 }
@@ -8,6 +8,7 @@ import (
 
     "github.com/gin-gonic/gin"
     "github.com/yourusername/gpay-remit/config"
+    "github.com/yourusername/gpay-remit/encryption"
     "github.com/yourusername/gpay-remit/errors"
     "github.com/yourusername/gpay-remit/models"
     "gorm.io/gorm"
@@ -69,15 +70,32 @@ func (h *SearchHandler) SearchTransactions(c *gin.Context) {
 
         sql := fmt.Sprintf(`SELECT id, sender_id, recipient_id, amount, currency, status, notes, created_at, ts_headline('english', notes, plainto_tsquery(?), 'StartSel=<em>, StopSel=</em>') AS notes_highlight FROM payments WHERE search_vector @@ plainto_tsquery(?) ORDER BY %s %s LIMIT ? OFFSET ?`, sortBy, sortOrder)
         h.db.Raw(sql, tsQuery, tsQuery, pageSize, offset).Scan(&rows)
+        // Notes encrypted at rest are decrypted here and cannot be highlighted.
+        for _, row := range rows {
+            var notes models.EncryptedString
+            if err := notes.Scan(row["notes"]); err != nil {
+                c.Error(errors.NewInternalError("Failed to decrypt notes", err))
+                return
+            }
+            if encryption.IsEncrypted(fmt.Sprintf("%s", row["notes"])) {
+                row["notes_highlight"] = notes.String()
+            }
+            row["notes"] = notes.String()
+        }
     } else {
         // Fallback: simple LIKE search and amount equality if numeric
         like := "%%%s%%"
         likeQ := fmt.Sprintf(like, q)
+        match, args := "notes LIKE ? OR currency LIKE ? OR status LIKE ?", []interface{}{likeQ, likeQ, likeQ}
+        // Encrypted notes are ciphertext in the database and cannot be matched there.
+        if models.FieldsEncrypted() {
+            match, args = "currency LIKE ? OR status LIKE ?", []interface{}{likeQ, likeQ}
+        }
         // count
-        h.db.Model(&models.Payment{}).Where("notes LIKE ? OR currency LIKE ? OR status LIKE ?", likeQ, likeQ, likeQ).Count(&total)
+        h.db.Model(&models.Payment{}).Where(match, args...).Count(&total)
 
         // query
-        base := h.db.Model(&models.Payment{}).Select("id, sender_id, recipient_id, amount, currency, status, notes, created_at").Where("notes LIKE ? OR currency LIKE ? OR status LIKE ?", likeQ, likeQ, likeQ)
+        base := h.db.Model(&models.Payment{}).Select("id, sender_id, recipient_id, amount, currency, status, notes, created_at").Where(match, args...)
         if amt, err := strconv.ParseFloat(q, 64); err == nil {
             base = base.Or("amount = ?", amt)
         }
@@ -89,7 +107,7 @@ func (h *SearchHandler) SearchTransactions(c *gin.Context) {
         }
         // build rows with highlight
         for _, p := range payments {
-            notes := p.Notes
+            notes := p.Notes.String()
             highlight := notes
             if q != "" {
                 highlight = strings.Replace(strings.ToLower(notes), strings.ToLower(q), "<em>"+q+"</em>", -1)
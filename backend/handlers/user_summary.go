@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/services"
+	"github.com/yourusername/gpay-remit/utils"
+	"gorm.io/gorm"
+)
+
+// UserSummary is everything the dashboard shows a user, in one response.
+type UserSummary struct {
+	ID             uint                `json:"id"`
+	Name           string              `json:"name"`
+	Email          string              `json:"email"`
+	Country        string              `json:"country"`
+	StellarAddress string              `json:"stellar_address"`
+	KYC            services.KYCSummary `json:"kyc"`
+	services.UserActivity
+	// XLMBalance is the user's XLM balance on the network. It is omitted,
+	// and BalanceUnavailable set, when Horizon cannot be reached.
+	XLMBalance         string `json:"xlm_balance,omitempty"`
+	BalanceUnavailable bool   `json:"balance_unavailable"`
+}
+
+// GetMySummary returns the caller's dashboard: profile, KYC status,
+// remittance counts and monthly totals, pending invoices, and XLM balance.
+func (h *RemittanceHandler) GetMySummary(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+
+	var user models.User
+	if err := h.db.First(&user, userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("User not found").WithKey("error.user_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch user", err))
+		}
+		return
+	}
+
+	activity, err := services.NewAnalyticsService(h.db).GetUserActivity(user.ID, time.Now())
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to summarize activity", err))
+		return
+	}
+
+	summary := UserSummary{
+		ID:             user.ID,
+		Name:           user.Name,
+		Email:          user.Email,
+		Country:        user.Country,
+		StellarAddress: user.StellarAddress,
+		KYC:            services.SummarizeKYC(&user),
+		UserActivity:   *activity,
+	}
+
+	ctx := utils.WithRequestContext(c.Request.Context(), c.GetString("requestID"), userID)
+	balances, err := h.stellarClient.GetBalances(ctx, user.StellarAddress)
+	switch {
+	case err == nil:
+		summary.XLMBalance = "0"
+		for _, balance := range balances {
+			if balance.AssetType == "native" {
+				summary.XLMBalance = balance.Balance
+			}
+		}
+	case utils.IsAccountNotFound(err):
+		// The account has not been funded yet.
+		summary.XLMBalance = "0"
+	default:
+		logger.Log.WithField("user_id", user.ID).WithError(err).Warn("XLM balance unavailable for summary")
+		summary.BalanceUnavailable = true
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
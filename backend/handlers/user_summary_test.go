@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/services"
+	"github.com/yourusername/gpay-remit/utils"
+)
+
+func TestGetMySummary(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+
+	user := models.User{Email: "dash@example.com", Name: "Dash", StellarAddress: "GDASH", Country: "KE", PasswordHash: "x", KYCStatus: services.KYCStatusVerified}
+	other := models.User{Email: "other@example.com", Name: "Other", StellarAddress: "GOTHER", PasswordHash: "x"}
+	db.Create(&user)
+	db.Create(&other)
+
+	now := time.Now()
+	lastMonth := services.StartOfUTCMonth(now).Add(-time.Hour)
+	db.Create(&[]models.Payment{
+		{SenderID: user.ID, RecipientID: other.ID, Amount: 100, Currency: "USD", Status: "completed", CreatedAt: now},
+		{SenderID: user.ID, RecipientID: other.ID, Amount: 50.25, Currency: "usd", Status: "pending", CreatedAt: now},
+		{SenderID: user.ID, RecipientID: other.ID, Amount: 30, Currency: "EUR", Status: "processing", CreatedAt: now},
+		// Failed remittances and last month's are counted by status but not totalled.
+		{SenderID: user.ID, RecipientID: other.ID, Amount: 999, Currency: "USD", Status: "failed", CreatedAt: now},
+		{SenderID: user.ID, RecipientID: other.ID, Amount: 400, Currency: "USD", Status: "completed", CreatedAt: lastMonth},
+		// Received: only completed ones count towards the monthly total.
+		{SenderID: other.ID, RecipientID: user.ID, Amount: 75, Currency: "USD", Status: "completed", CreatedAt: now},
+		{SenderID: other.ID, RecipientID: user.ID, Amount: 20, Currency: "USD", Status: "pending", CreatedAt: now},
+		// Someone else's remittance is not the user's.
+		{SenderID: other.ID, RecipientID: other.ID + 1, Amount: 10, Currency: "USD", Status: "completed", CreatedAt: now},
+	})
+	db.Create(&[]models.Invoice{
+		{PaymentID: 1, InvoiceNo: "INV-1", IssuerID: other.ID, RecipientID: user.ID, Amount: 10, Currency: "USD", Status: "unpaid"},
+		{PaymentID: 2, InvoiceNo: "INV-2", IssuerID: other.ID, RecipientID: user.ID, Amount: 10, Currency: "USD", Status: "overdue"},
+		{PaymentID: 3, InvoiceNo: "INV-3", IssuerID: other.ID, RecipientID: user.ID, Amount: 10, Currency: "USD", Status: "paid"},
+		{PaymentID: 4, InvoiceNo: "INV-4", IssuerID: user.ID, RecipientID: other.ID, Amount: 10, Currency: "USD", Status: "unpaid"},
+	})
+
+	horizonDown := false
+	handler := &RemittanceHandler{
+		db:     db,
+		config: &config.Config{},
+		stellarClient: &MockStellarClient{
+			GetBalancesFunc: func(accountID string) ([]utils.AccountBalance, error) {
+				if horizonDown {
+					return nil, fmt.Errorf("horizon unreachable")
+				}
+				return []utils.AccountBalance{
+					{AssetType: "credit_alphanum4", AssetCode: "USDC", Balance: "12.0000000"},
+					{AssetType: "native", AssetCode: "XLM", Balance: "42.5000000"},
+				}, nil
+			},
+		},
+	}
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", user.ID)
+		c.Next()
+	})
+	router.GET("/users/me/summary", handler.GetMySummary)
+
+	get := func() (int, UserSummary) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/users/me/summary", nil)
+		router.ServeHTTP(w, req)
+		var summary UserSummary
+		json.Unmarshal(w.Body.Bytes(), &summary)
+		return w.Code, summary
+	}
+
+	t.Run("Counts and monthly totals", func(t *testing.T) {
+		code, summary := get()
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, user.ID, summary.ID)
+		assert.Equal(t, "Dash", summary.Name)
+		assert.Equal(t, services.KYCStatusVerified, summary.KYC.Status)
+		assert.Equal(t, map[string]int64{"completed": 3, "pending": 2, "processing": 1, "failed": 1}, summary.RemittancesByStatus)
+		assert.Equal(t, map[string]float64{"USD": 150.25, "EUR": 30}, summary.SentThisMonth)
+		assert.Equal(t, map[string]float64{"USD": 75}, summary.ReceivedThisMonth)
+		assert.Equal(t, int64(2), summary.PendingInvoices)
+		assert.Equal(t, "42.5000000", summary.XLMBalance)
+		assert.False(t, summary.BalanceUnavailable)
+	})
+
+	t.Run("Horizon unreachable", func(t *testing.T) {
+		horizonDown = true
+		defer func() { horizonDown = false }()
+
+		code, summary := get()
+		assert.Equal(t, http.StatusOK, code)
+		assert.True(t, summary.BalanceUnavailable)
+		assert.Empty(t, summary.XLMBalance)
+		assert.Equal(t, int64(2), summary.PendingInvoices)
+	})
+}
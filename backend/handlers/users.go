@@ -0,0 +1,559 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/services"
+	"github.com/yourusername/gpay-remit/utils"
+	"gorm.io/gorm"
+)
+
+type UserHandler struct {
+	db     *gorm.DB
+	config *config.Config
+}
+
+func NewUserHandler(db *gorm.DB, cfg *config.Config) *UserHandler {
+	return &UserHandler{db: db, config: cfg}
+}
+
+// currentUser loads the authenticated user, reporting any failure on the context.
+func (h *UserHandler) currentUser(c *gin.Context) (*models.User, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return nil, false
+	}
+
+	var user models.User
+	if err := h.db.First(&user, userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("User not found").WithKey("error.user_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch user", err))
+		}
+		return nil, false
+	}
+	return &user, true
+}
+
+type ListUsersResponse struct {
+	Data       []models.User `json:"data"`
+	Page       int           `json:"page"`
+	PageSize   int           `json:"page_size"`
+	TotalCount int64         `json:"total_count"`
+}
+
+// ListUsers lets an admin page through users, optionally filtered by role,
+// kyc_status, and country, oldest first.
+func (h *UserHandler) ListUsers(c *gin.Context) {
+	query := h.db.Model(&models.User{})
+	if role := c.Query("role"); role != "" {
+		query = query.Where("role = ?", role)
+	}
+	if status := c.Query("kyc_status"); status != "" {
+		query = query.Where("kyc_status = ?", status)
+	}
+	if country := c.Query("country"); country != "" {
+		query = query.Where("country = ?", strings.ToUpper(country))
+	}
+
+	page := 1
+	pageSize := 20
+	fmt.Sscanf(c.Query("page"), "%d", &page)
+	fmt.Sscanf(c.Query("page_size"), "%d", &pageSize)
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to count users", err))
+		return
+	}
+
+	var users []models.User
+	if err := query.Order("id ASC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&users).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to fetch users", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, ListUsersResponse{
+		Data:       users,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalCount: total,
+	})
+}
+
+type UpdateUserRequest struct {
+	IsActive *bool   `json:"is_active"`
+	Role     *string `json:"role"`
+}
+
+// guardSuperadmin refuses changes to a superadmin's account from anyone but
+// another superadmin, so an admin cannot deactivate, demote, or otherwise
+// alter the accounts above them.
+func guardSuperadmin(c *gin.Context, user *models.User) bool {
+	if user.Role == models.RoleSuperadmin && c.GetString("role") != models.RoleSuperadmin {
+		c.Error(errors.NewForbiddenError("Only a superadmin can change a superadmin's account"))
+		return false
+	}
+	return true
+}
+
+// UpdateUser lets an admin activate or deactivate a user and change their
+// role. Deactivation takes effect on the user's next request, since
+// JwtAuthMiddleware rejects tokens of inactive users. Admins cannot change
+// their own account, so they cannot lock themselves out, and only a
+// superadmin may grant the superadmin role or change a superadmin's account.
+func (h *UserHandler) UpdateUser(c *gin.Context) {
+	var req UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+	if req.IsActive == nil && req.Role == nil {
+		c.Error(errors.NewValidationError("Nothing to update", "provide is_active and/or role"))
+		return
+	}
+	if req.Role != nil && !models.IsValidRole(*req.Role) {
+		c.Error(errors.NewValidationError("Invalid role", fmt.Sprintf("role must be %q, %q, %q, or %q", models.RoleUser, models.RoleCompliance, models.RoleAdmin, models.RoleSuperadmin)))
+		return
+	}
+
+	var user models.User
+	if err := h.db.First(&user, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("User not found").WithKey("error.user_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch user", err))
+		}
+		return
+	}
+
+	adminID, _ := c.Get("userID")
+	if id, ok := adminID.(uint); ok && id == user.ID {
+		c.Error(errors.NewForbiddenError("Admins cannot change their own status or role"))
+		return
+	}
+	if !guardSuperadmin(c, &user) {
+		return
+	}
+	if req.Role != nil && *req.Role == models.RoleSuperadmin && c.GetString("role") != models.RoleSuperadmin {
+		c.Error(errors.NewForbiddenError("Only a superadmin can grant the superadmin role"))
+		return
+	}
+
+	middleware.SetAuditOld(c, user)
+	updates := map[string]interface{}{}
+	if req.IsActive != nil {
+		user.IsActive = *req.IsActive
+		updates["is_active"] = user.IsActive
+	}
+	if req.Role != nil {
+		user.Role = *req.Role
+		updates["role"] = user.Role
+	}
+	if err := h.db.Model(&user).Updates(updates).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to update user", err))
+		return
+	}
+
+	logger.Log.WithFields(logrus.Fields{
+		"admin_id":   adminID,
+		"user_id":    user.ID,
+		"is_active":  user.IsActive,
+		"role":       user.Role,
+		"request_id": c.GetString("requestID"),
+	}).Info("User updated")
+
+	middleware.SetAuditNew(c, user)
+	c.JSON(http.StatusOK, user)
+}
+
+// GetMyKYC returns the caller's KYC status, tier, limits, and the
+// requirements they still need to meet.
+func (h *UserHandler) GetMyKYC(c *gin.Context) {
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, services.SummarizeKYC(user))
+}
+
+type UpdateKYCRequest struct {
+	// Status is "verified" or "rejected".
+	Status string `json:"status" binding:"required"`
+}
+
+// UpdateKYC lets an admin record the outcome of a user's identity
+// verification. Verifying stamps KYCVerifiedAt; rejecting clears it.
+func (h *UserHandler) UpdateKYC(c *gin.Context) {
+	var req UpdateKYCRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+	if req.Status != services.KYCStatusVerified && req.Status != services.KYCStatusRejected {
+		c.Error(errors.NewValidationError("Invalid KYC status", "status must be \"verified\" or \"rejected\""))
+		return
+	}
+
+	var user models.User
+	if err := h.db.First(&user, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("User not found").WithKey("error.user_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch user", err))
+		}
+		return
+	}
+	if !guardSuperadmin(c, &user) {
+		return
+	}
+
+	middleware.SetAuditOld(c, user)
+	previous := user.KYCStatus
+	user.KYCStatus = req.Status
+	user.KYCVerifiedAt = nil
+	if req.Status == services.KYCStatusVerified {
+		now := time.Now()
+		user.KYCVerifiedAt = &now
+	}
+	if err := h.db.Model(&user).Updates(map[string]interface{}{
+		"kyc_status":      user.KYCStatus,
+		"kyc_verified_at": user.KYCVerifiedAt,
+	}).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to update KYC status", err))
+		return
+	}
+
+	adminID, _ := c.Get("userID")
+	logger.Log.WithFields(logrus.Fields{
+		"admin_id":        adminID,
+		"user_id":         user.ID,
+		"previous_status": previous,
+		"kyc_status":      user.KYCStatus,
+		"request_id":      c.GetString("requestID"),
+	}).Info("KYC status updated")
+
+	middleware.SetAuditNew(c, user)
+	middleware.SetAuditStatus(c, previous, user.KYCStatus)
+	c.JSON(http.StatusOK, user)
+}
+
+// UnlockUser lifts a login lockout and clears the user's failed login count
+// (admin only).
+func (h *UserHandler) UnlockUser(c *gin.Context) {
+	var user models.User
+	if err := h.db.First(&user, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("User not found").WithKey("error.user_not_found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch user", err))
+		}
+		return
+	}
+	if !guardSuperadmin(c, &user) {
+		return
+	}
+
+	middleware.SetAuditOld(c, user)
+	wasLocked := user.IsLocked(time.Now())
+	if err := h.db.Model(&user).Updates(map[string]interface{}{
+		"failed_login_attempts": 0,
+		"locked_until":          nil,
+	}).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to unlock user", err))
+		return
+	}
+	user.FailedLoginAttempts = 0
+	user.LockedUntil = nil
+
+	adminID, _ := c.Get("userID")
+	logger.Log.WithFields(logrus.Fields{
+		"admin_id":   adminID,
+		"user_id":    user.ID,
+		"was_locked": wasLocked,
+		"request_id": c.GetString("requestID"),
+	}).Info("User unlocked")
+
+	middleware.SetAuditNew(c, user)
+	c.JSON(http.StatusOK, user)
+}
+
+// ListMyNotifications returns the caller's in-app notifications, newest first.
+func (h *UserHandler) ListMyNotifications(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+
+	query := h.db.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if c.Query("unread") == "true" {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var notifications []models.Notification
+	if err := query.Order("created_at DESC, id DESC").Scopes(Paginate(c)).Find(&notifications).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to fetch notifications", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+// MarkNotificationRead marks one of the caller's notifications as read.
+func (h *UserHandler) MarkNotificationRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
+		return
+	}
+
+	var notification models.Notification
+	if err := h.db.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&notification).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.Error(errors.NewNotFoundError("Notification not found"))
+		} else {
+			c.Error(errors.NewInternalError("Failed to fetch notification", err))
+		}
+		return
+	}
+
+	if notification.ReadAt == nil {
+		now := time.Now()
+		if err := h.db.Model(&notification).Update("read_at", now).Error; err != nil {
+			c.Error(errors.NewInternalError("Failed to update notification", err))
+			return
+		}
+		notification.ReadAt = &now
+	}
+
+	c.JSON(http.StatusOK, notification)
+}
+
+// GetMyNotificationPreferences returns, for every event and channel, whether
+// the caller is notified, with defaults filled in.
+func (h *UserHandler) GetMyNotificationPreferences(c *gin.Context) {
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	prefs, err := services.NewNotificationService(h.db, nil, nil).Preferences(user)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to fetch notification preferences", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"preferences": prefs})
+}
+
+type NotificationPreferenceUpdate struct {
+	Event   string `json:"event" binding:"required"`
+	Channel string `json:"channel" binding:"required"`
+	Enabled *bool  `json:"enabled" binding:"required"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceUpdate `json:"preferences" binding:"required,min=1,dive"`
+}
+
+// UpdateMyNotificationPreferences turns channels on or off per event for the
+// caller and returns the resulting preferences.
+func (h *UserHandler) UpdateMyNotificationPreferences(c *gin.Context) {
+	var req UpdateNotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+	if !validNotificationPreferences(c, req.Preferences) {
+		return
+	}
+
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	notifier := services.NewNotificationService(h.db, nil, nil)
+	for _, pref := range req.Preferences {
+		if err := notifier.SetPreference(user.ID, pref.Event, pref.Channel, *pref.Enabled); err != nil {
+			c.Error(errors.NewInternalError("Failed to update notification preferences", err))
+			return
+		}
+	}
+
+	prefs, err := notifier.Preferences(user)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to fetch notification preferences", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"preferences": prefs})
+}
+
+// validNotificationPreferences rejects updates naming an unknown event or
+// channel, reporting on the context.
+func validNotificationPreferences(c *gin.Context, prefs []NotificationPreferenceUpdate) bool {
+	for _, pref := range prefs {
+		if !services.IsNotificationEvent(pref.Event) {
+			c.Error(errors.NewValidationError("Invalid notification event", pref.Event))
+			return false
+		}
+		if !services.IsNotificationChannel(pref.Channel) {
+			c.Error(errors.NewValidationError("Invalid notification channel", pref.Channel))
+			return false
+		}
+	}
+	return true
+}
+
+// ProfileResponse is the caller's own profile. It leaves out credentials,
+// account administration fields, and KYC, which GET /users/me/kyc reports.
+type ProfileResponse struct {
+	ID                      uint                            `json:"id"`
+	Email                   string                          `json:"email"`
+	Name                    string                          `json:"name"`
+	StellarAddress          string                          `json:"stellar_address"`
+	Country                 string                          `json:"country"`
+	DefaultCurrency         string                          `json:"default_currency"`
+	EmailNotifications      bool                            `json:"email_notifications"`
+	EmailVerified           bool                            `json:"email_verified"`
+	TOTPEnabled             bool                            `json:"totp_enabled"`
+	CreatedAt               time.Time                       `json:"created_at"`
+	NotificationPreferences []models.NotificationPreference `json:"notification_preferences"`
+}
+
+// UpdateProfileRequest changes the caller's profile. Omitted fields are left
+// as they are.
+type UpdateProfileRequest struct {
+	Name *string `json:"name" binding:"omitempty,max=255"`
+	// DefaultCurrency must be in SUPPORTED_CURRENCIES when that is set.
+	DefaultCurrency *string `json:"default_currency" binding:"omitempty,max=10"`
+	// Country is an upper-case ISO 3166-1 alpha-2 code.
+	Country                 *string                        `json:"country" binding:"omitempty,iso3166_1_alpha2"`
+	EmailNotifications      *bool                          `json:"email_notifications"`
+	NotificationPreferences []NotificationPreferenceUpdate `json:"notification_preferences" binding:"omitempty,dive"`
+}
+
+// GetMyProfile returns the caller's profile and notification preferences.
+func (h *UserHandler) GetMyProfile(c *gin.Context) {
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	h.respondWithProfile(c, user)
+}
+
+// UpdateMyProfile changes the caller's name, default currency, country,
+// email notification switch, and per-event notification preferences, all or
+// none, and returns the resulting profile.
+func (h *UserHandler) UpdateMyProfile(c *gin.Context) {
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.NewBindingError(err))
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			c.Error(errors.NewValidationError("Invalid name", "name cannot be empty"))
+			return
+		}
+		updates["name"] = name
+	}
+	if req.DefaultCurrency != nil {
+		currency := strings.ToUpper(strings.TrimSpace(*req.DefaultCurrency))
+		if err := utils.ValidateAssetCode(currency); err != nil {
+			c.Error(errors.NewValidationError("Invalid default currency", err.Error()))
+			return
+		}
+		if !requireSupportedCurrency(c, h.config, "default_currency", currency, "") {
+			return
+		}
+		updates["default_currency"] = currency
+	}
+	if req.Country != nil {
+		updates["country"] = *req.Country
+	}
+	if req.EmailNotifications != nil {
+		updates["email_notifications"] = *req.EmailNotifications
+	}
+	if !validNotificationPreferences(c, req.NotificationPreferences) {
+		return
+	}
+
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if len(updates) > 0 {
+			if err := tx.Model(user).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		notifier := services.NewNotificationService(tx, nil, nil)
+		for _, pref := range req.NotificationPreferences {
+			if err := notifier.SetPreference(user.ID, pref.Event, pref.Channel, *pref.Enabled); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to update profile", err))
+		return
+	}
+
+	if err := h.db.First(user, user.ID).Error; err != nil {
+		c.Error(errors.NewInternalError("Failed to fetch user", err))
+		return
+	}
+	h.respondWithProfile(c, user)
+}
+
+func (h *UserHandler) respondWithProfile(c *gin.Context, user *models.User) {
+	prefs, err := services.NewNotificationService(h.db, nil, nil).Preferences(user)
+	if err != nil {
+		c.Error(errors.NewInternalError("Failed to fetch notification preferences", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, ProfileResponse{
+		ID:                      user.ID,
+		Email:                   user.Email,
+		Name:                    user.Name,
+		StellarAddress:          user.StellarAddress,
+		Country:                 user.Country,
+		DefaultCurrency:         user.DefaultCurrency,
+		EmailNotifications:      user.EmailNotifications,
+		EmailVerified:           user.VerifiedAt != nil,
+		TOTPEnabled:             user.TOTPEnabled,
+		CreatedAt:               user.CreatedAt,
+		NotificationPreferences: prefs,
+	})
+}
@@ -0,0 +1,478 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/services"
+)
+
+func TestGetMyKYC(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	handler := NewUserHandler(db, &config.Config{})
+
+	verifiedAt := time.Now().Add(-24 * time.Hour)
+	pending := models.User{Email: "pending@example.com", Name: "Pending", StellarAddress: "GPENDING", PasswordHash: "x", KYCStatus: "pending"}
+	verified := models.User{Email: "verified@example.com", Name: "Verified", StellarAddress: "GVERIFIED", PasswordHash: "x", Country: "NG", KYCStatus: "verified", KYCVerifiedAt: &verifiedAt}
+	db.Create(&pending)
+	db.Create(&verified)
+
+	get := func(userID uint) (int, services.KYCSummary) {
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", userID)
+			c.Next()
+		})
+		router.GET("/users/me/kyc", handler.GetMyKYC)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/users/me/kyc", nil)
+		router.ServeHTTP(w, req)
+
+		var summary services.KYCSummary
+		json.Unmarshal(w.Body.Bytes(), &summary)
+		return w.Code, summary
+	}
+
+	t.Run("Pending user sees outstanding requirements", func(t *testing.T) {
+		code, summary := get(pending.ID)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "pending", summary.Status)
+		assert.Nil(t, summary.VerifiedAt)
+		assert.Equal(t, services.UnverifiedTier.Name, summary.Tier.Name)
+		assert.False(t, summary.CanSend)
+
+		codes := []string{}
+		for _, r := range summary.Requirements {
+			codes = append(codes, r.Code)
+		}
+		assert.ElementsMatch(t, []string{"country_of_residence", "identity_document", "proof_of_address"}, codes)
+	})
+
+	t.Run("Verified user sees none", func(t *testing.T) {
+		code, summary := get(verified.ID)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "verified", summary.Status)
+		assert.NotNil(t, summary.VerifiedAt)
+		assert.Equal(t, services.VerifiedTier.Name, summary.Tier.Name)
+		assert.True(t, summary.CanSend)
+		assert.Empty(t, summary.Requirements)
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		code, _ := get(999)
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+}
+
+func TestUpdateKYC(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	handler := NewUserHandler(db, &config.Config{})
+
+	verifiedAt := time.Now().Add(-time.Hour)
+	pending := models.User{Email: "kyc-pending@example.com", Name: "Pending", StellarAddress: "GKYCPENDING", PasswordHash: "x", KYCStatus: "pending"}
+	verified := models.User{Email: "kyc-verified@example.com", Name: "Verified", StellarAddress: "GKYCVERIFIED", PasswordHash: "x", KYCStatus: "verified", KYCVerifiedAt: &verifiedAt}
+	db.Create(&pending)
+	db.Create(&verified)
+
+	patch := func(role string, id uint, status string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", uint(999))
+			c.Set("role", role)
+			c.Next()
+		})
+		router.PATCH("/users/:id/kyc", middleware.RequireRole("admin"), handler.UpdateKYC)
+
+		body, _ := json.Marshal(UpdateKYCRequest{Status: status})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPatch, fmt.Sprintf("/users/%d/kyc", id), bytes.NewBuffer(body))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Admin verifies user", func(t *testing.T) {
+		w := patch("admin", pending.ID, "verified")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var got models.User
+		db.First(&got, pending.ID)
+		assert.Equal(t, "verified", got.KYCStatus)
+		if assert.NotNil(t, got.KYCVerifiedAt) {
+			assert.WithinDuration(t, time.Now(), *got.KYCVerifiedAt, time.Minute)
+		}
+	})
+
+	t.Run("Admin rejects user", func(t *testing.T) {
+		w := patch("admin", verified.ID, "rejected")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var got models.User
+		db.First(&got, verified.ID)
+		assert.Equal(t, "rejected", got.KYCStatus)
+		assert.Nil(t, got.KYCVerifiedAt)
+	})
+
+	t.Run("Invalid status rejected", func(t *testing.T) {
+		w := patch("admin", pending.ID, "approved")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		w := patch("admin", 424242, "verified")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Superadmin's KYC is left to superadmins", func(t *testing.T) {
+		super := models.User{Email: "kyc-super@example.com", Name: "Super", StellarAddress: "GKYCSUPER", PasswordHash: "x", Role: "superadmin", KYCStatus: "verified", KYCVerifiedAt: &verifiedAt}
+		db.Create(&super)
+
+		w := patch("admin", super.ID, "rejected")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		var got models.User
+		db.First(&got, super.ID)
+		assert.Equal(t, "verified", got.KYCStatus)
+	})
+
+	t.Run("Non-admin forbidden", func(t *testing.T) {
+		w := patch("user", pending.ID, "rejected")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		var got models.User
+		db.First(&got, pending.ID)
+		assert.Equal(t, "verified", got.KYCStatus)
+	})
+}
+
+func TestListUsers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	handler := NewUserHandler(db, &config.Config{})
+
+	db.Create(&models.User{Email: "list-ng@example.com", Name: "NG", StellarAddress: "GLISTNG", PasswordHash: "x", Country: "NG", KYCStatus: "verified"})
+	db.Create(&models.User{Email: "list-ke@example.com", Name: "KE", StellarAddress: "GLISTKE", PasswordHash: "x", Country: "KE", KYCStatus: "pending"})
+	db.Create(&models.User{Email: "list-admin@example.com", Name: "Admin", StellarAddress: "GLISTADMIN", PasswordHash: "x", Role: "admin", Country: "NG", KYCStatus: "verified"})
+
+	list := func(role, query string) (int, ListUsersResponse) {
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", uint(999))
+			c.Set("role", role)
+			c.Next()
+		})
+		router.GET("/users", middleware.RequireRole("admin", "superadmin"), handler.ListUsers)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/users"+query, nil)
+		router.ServeHTTP(w, req)
+
+		var resp ListUsersResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+
+	t.Run("Lists all users", func(t *testing.T) {
+		code, resp := list("admin", "")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, int64(3), resp.TotalCount)
+		assert.Len(t, resp.Data, 3)
+	})
+
+	t.Run("Filters combine", func(t *testing.T) {
+		code, resp := list("admin", "?country=ng&kyc_status=verified&role=user")
+		assert.Equal(t, http.StatusOK, code)
+		if assert.Len(t, resp.Data, 1) {
+			assert.Equal(t, "list-ng@example.com", resp.Data[0].Email)
+		}
+	})
+
+	t.Run("Paginates", func(t *testing.T) {
+		code, resp := list("admin", "?page=2&page_size=2")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, int64(3), resp.TotalCount)
+		assert.Equal(t, 2, resp.Page)
+		if assert.Len(t, resp.Data, 1) {
+			assert.Equal(t, "list-admin@example.com", resp.Data[0].Email)
+		}
+	})
+
+	t.Run("Non-admin forbidden", func(t *testing.T) {
+		code, _ := list("user", "")
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+}
+
+func TestUpdateUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	handler := NewUserHandler(db, &config.Config{})
+	cfg := &config.Config{JWTSecret: "test-secret"}
+
+	admin := models.User{Email: "update-admin@example.com", Name: "Admin", StellarAddress: "GUPDATEADMIN", PasswordHash: "x", Role: "admin", IsActive: true}
+	user := models.User{Email: "update-user@example.com", Name: "User", StellarAddress: "GUPDATEUSER", PasswordHash: "x", Role: "user", IsActive: true}
+	db.Create(&admin)
+	db.Create(&user)
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	protected := router.Group("/")
+	protected.Use(middleware.JwtAuthMiddleware(cfg, db))
+	protected.GET("/users/me/kyc", handler.GetMyKYC)
+	protected.PATCH("/users/:id", middleware.RequireRole("admin", "superadmin"), handler.UpdateUser)
+
+	token := func(u models.User) string {
+		tok, err := middleware.GenerateToken(u.ID, u.Role, cfg.JWTSecret, time.Hour)
+		require.NoError(t, err)
+		return tok
+	}
+	adminToken, userToken := token(admin), token(user)
+
+	do := func(method, path, tok string, body interface{}) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&buf).Encode(body)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, &buf)
+		req.Header.Set("Authorization", "Bearer "+tok)
+		router.ServeHTTP(w, req)
+		return w
+	}
+	patch := func(tok string, id uint, body interface{}) *httptest.ResponseRecorder {
+		return do(http.MethodPatch, fmt.Sprintf("/users/%d", id), tok, body)
+	}
+
+	t.Run("Deactivation rejects the user's existing token", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, do(http.MethodGet, "/users/me/kyc", userToken, nil).Code)
+
+		w := patch(adminToken, user.ID, map[string]interface{}{"is_active": false})
+		require.Equal(t, http.StatusOK, w.Code)
+		var got models.User
+		db.First(&got, user.ID)
+		assert.False(t, got.IsActive)
+
+		assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/users/me/kyc", userToken, nil).Code)
+
+		w = patch(adminToken, user.ID, map[string]interface{}{"is_active": true})
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, http.StatusOK, do(http.MethodGet, "/users/me/kyc", userToken, nil).Code)
+	})
+
+	t.Run("Admins cannot deactivate themselves", func(t *testing.T) {
+		w := patch(adminToken, admin.ID, map[string]interface{}{"is_active": false})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		var got models.User
+		db.First(&got, admin.ID)
+		assert.True(t, got.IsActive)
+	})
+
+	t.Run("Role change takes effect on existing tokens", func(t *testing.T) {
+		w := patch(adminToken, user.ID, map[string]interface{}{"role": "admin"})
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, http.StatusNotFound, patch(userToken, 424242, map[string]interface{}{"role": "user"}).Code,
+			"the promoted user's old token passes the admin check")
+
+		w = patch(adminToken, user.ID, map[string]interface{}{"role": "user"})
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, http.StatusForbidden, patch(userToken, admin.ID, map[string]interface{}{"is_active": false}).Code)
+	})
+
+	t.Run("Invalid role rejected", func(t *testing.T) {
+		w := patch(adminToken, user.ID, map[string]interface{}{"role": "owner"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Only superadmins grant superadmin", func(t *testing.T) {
+		w := patch(adminToken, user.ID, map[string]interface{}{"role": "superadmin"})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		var got models.User
+		db.First(&got, user.ID)
+		assert.Equal(t, "user", got.Role)
+	})
+
+	t.Run("Only superadmins change a superadmin's account", func(t *testing.T) {
+		super := models.User{Email: "update-super@example.com", Name: "Super", StellarAddress: "GUPDATESUPER", PasswordHash: "x", Role: "superadmin", IsActive: true}
+		other := models.User{Email: "update-super2@example.com", Name: "Super 2", StellarAddress: "GUPDATESUPER2", PasswordHash: "x", Role: "superadmin", IsActive: true}
+		db.Create(&super)
+		db.Create(&other)
+
+		for _, body := range []map[string]interface{}{
+			{"is_active": false},
+			{"role": "user"},
+			{"role": "superadmin", "is_active": false},
+		} {
+			w := patch(adminToken, super.ID, body)
+			assert.Equal(t, http.StatusForbidden, w.Code, "%v", body)
+		}
+		var got models.User
+		db.First(&got, super.ID)
+		assert.True(t, got.IsActive)
+		assert.Equal(t, "superadmin", got.Role)
+
+		w := patch(token(other), super.ID, map[string]interface{}{"is_active": false})
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Empty update rejected", func(t *testing.T) {
+		w := patch(adminToken, user.ID, map[string]interface{}{})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		w := patch(adminToken, 424242, map[string]interface{}{"is_active": false})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestUnlockUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	handler := NewUserHandler(db, &config.Config{})
+
+	lockedUntil := time.Now().Add(time.Hour)
+	locked := models.User{Email: "locked@example.com", Name: "Locked", StellarAddress: "GLOCKED", PasswordHash: "x", FailedLoginAttempts: 2, LockedUntil: &lockedUntil}
+	db.Create(&locked)
+
+	unlock := func(role string, id uint) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", uint(999))
+			c.Set("role", role)
+			c.Next()
+		})
+		router.POST("/admin/users/:id/unlock", middleware.RequireRole("admin", "superadmin"), handler.UnlockUser)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/admin/users/%d/unlock", id), nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusForbidden, unlock("user", locked.ID).Code)
+	assert.Equal(t, http.StatusNotFound, unlock("admin", 12345).Code)
+
+	lockedSuper := models.User{Email: "locked-super@example.com", Name: "Locked Super", StellarAddress: "GLOCKEDSUPER", PasswordHash: "x", Role: "superadmin", LockedUntil: &lockedUntil}
+	db.Create(&lockedSuper)
+	assert.Equal(t, http.StatusForbidden, unlock("admin", lockedSuper.ID).Code)
+	assert.Equal(t, http.StatusOK, unlock("superadmin", lockedSuper.ID).Code)
+
+	w := unlock("admin", locked.ID)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "locked_until")
+
+	var got models.User
+	db.First(&got, locked.ID)
+	assert.Nil(t, got.LockedUntil)
+	assert.Zero(t, got.FailedLoginAttempts)
+	assert.False(t, got.IsLocked(time.Now()))
+}
+
+func TestMyProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB()
+	db.AutoMigrate(&models.NotificationPreference{})
+	cfg := &config.Config{SupportedCurrencies: []config.SupportedCurrency{{Code: "USD"}, {Code: "EUR"}}}
+	handler := NewUserHandler(db, cfg)
+
+	user := models.User{Email: "profile@example.com", Name: "Ada", StellarAddress: "GPROFILE", PasswordHash: "secret-hash", Country: "US", DefaultCurrency: "USD", EmailNotifications: true, KYCStatus: "approved"}
+	db.Create(&user)
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", user.ID)
+		c.Next()
+	})
+	router.GET("/users/me", handler.GetMyProfile)
+	router.PUT("/users/me", handler.UpdateMyProfile)
+
+	put := func(body map[string]interface{}) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, "/users/me", bytes.NewBuffer(payload))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Get omits credentials and KYC", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/users/me", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "secret-hash")
+		assert.NotContains(t, w.Body.String(), "kyc")
+
+		var resp ProfileResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "Ada", resp.Name)
+		assert.Equal(t, "US", resp.Country)
+		assert.Equal(t, "USD", resp.DefaultCurrency)
+		assert.NotEmpty(t, resp.NotificationPreferences)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		w := put(map[string]interface{}{
+			"name":                "  Ada Lovelace ",
+			"default_currency":    "eur",
+			"country":             "GB",
+			"email_notifications": false,
+			"notification_preferences": []map[string]interface{}{
+				{"event": services.EventPaymentFailed, "channel": models.ChannelInApp, "enabled": false},
+			},
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp ProfileResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "Ada Lovelace", resp.Name)
+		assert.Equal(t, "EUR", resp.DefaultCurrency)
+		assert.Equal(t, "GB", resp.Country)
+		assert.False(t, resp.EmailNotifications)
+
+		var pref models.NotificationPreference
+		require.NoError(t, db.Where("user_id = ? AND event = ? AND channel = ?", user.ID, services.EventPaymentFailed, models.ChannelInApp).First(&pref).Error)
+		assert.False(t, pref.Enabled)
+	})
+
+	t.Run("Invalid fields rejected", func(t *testing.T) {
+		for name, body := range map[string]map[string]interface{}{
+			"blank name":           {"name": "   "},
+			"unsupported currency": {"default_currency": "JPY"},
+			"malformed currency":   {"default_currency": "U$D"},
+			"unknown country":      {"country": "XX"},
+			"lower-case country":   {"country": "gb"},
+			"unknown pref event":   {"notification_preferences": []map[string]interface{}{{"event": "nope", "channel": models.ChannelEmail, "enabled": true}}},
+			"unknown pref channel": {"notification_preferences": []map[string]interface{}{{"event": services.EventPaymentFailed, "channel": "sms", "enabled": true}}},
+			"pref missing enabled": {"notification_preferences": []map[string]interface{}{{"event": services.EventPaymentFailed, "channel": models.ChannelEmail}}},
+		} {
+			w := put(body)
+			assert.Equal(t, http.StatusBadRequest, w.Code, name)
+		}
+
+		var got models.User
+		db.First(&got, user.ID)
+		assert.Equal(t, "Ada Lovelace", got.Name)
+		assert.Equal(t, "EUR", got.DefaultCurrency)
+	})
+}
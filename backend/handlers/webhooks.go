@@ -42,13 +42,13 @@ type UpdateWebhookRequest struct {
 func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
 	var req CreateWebhookRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.Error(errors.NewValidationError("Invalid request body", err.Error()))
+		c.Error(errors.NewBindingError(err))
 		return
 	}
 
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.Error(errors.NewUnauthorizedError("Unauthorized"))
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
 		return
 	}
 
@@ -90,7 +90,7 @@ func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
 func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.Error(errors.NewUnauthorizedError("Unauthorized"))
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
 		return
 	}
 
@@ -120,7 +120,7 @@ func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
 func (h *WebhookHandler) GetWebhook(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.Error(errors.NewUnauthorizedError("Unauthorized"))
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
 		return
 	}
 
@@ -129,7 +129,7 @@ func (h *WebhookHandler) GetWebhook(c *gin.Context) {
 
 	if err := h.db.Where("id = ? AND user_id = ?", id, userID).First(&webhook).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.Error(errors.NewNotFoundError("Webhook not found"))
+			c.Error(errors.NewNotFoundError("Webhook not found").WithKey("error.webhook_not_found"))
 		} else {
 			c.Error(errors.NewInternalError("Failed to fetch webhook", err))
 		}
@@ -152,21 +152,21 @@ func (h *WebhookHandler) GetWebhook(c *gin.Context) {
 func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.Error(errors.NewUnauthorizedError("Unauthorized"))
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
 		return
 	}
 
 	id := c.Param("id")
 	var req UpdateWebhookRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.Error(errors.NewValidationError("Invalid request body", err.Error()))
+		c.Error(errors.NewBindingError(err))
 		return
 	}
 
 	var webhook models.Webhook
 	if err := h.db.Where("id = ? AND user_id = ?", id, userID).First(&webhook).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.Error(errors.NewNotFoundError("Webhook not found"))
+			c.Error(errors.NewNotFoundError("Webhook not found").WithKey("error.webhook_not_found"))
 		} else {
 			c.Error(errors.NewInternalError("Failed to fetch webhook", err))
 		}
@@ -208,7 +208,7 @@ func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
 func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.Error(errors.NewUnauthorizedError("Unauthorized"))
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
 		return
 	}
 
@@ -217,7 +217,7 @@ func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
 
 	if err := h.db.Where("id = ? AND user_id = ?", id, userID).First(&webhook).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.Error(errors.NewNotFoundError("Webhook not found"))
+			c.Error(errors.NewNotFoundError("Webhook not found").WithKey("error.webhook_not_found"))
 		} else {
 			c.Error(errors.NewInternalError("Failed to fetch webhook", err))
 		}
@@ -236,7 +236,7 @@ func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
 func (h *WebhookHandler) GetWebhookDeliveries(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.Error(errors.NewUnauthorizedError("Unauthorized"))
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
 		return
 	}
 
@@ -246,7 +246,7 @@ func (h *WebhookHandler) GetWebhookDeliveries(c *gin.Context) {
 	// Verify webhook belongs to user
 	if err := h.db.Where("id = ? AND user_id = ?", id, userID).First(&webhook).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.Error(errors.NewNotFoundError("Webhook not found"))
+			c.Error(errors.NewNotFoundError("Webhook not found").WithKey("error.webhook_not_found"))
 		} else {
 			c.Error(errors.NewInternalError("Failed to fetch webhook", err))
 		}
@@ -270,7 +270,7 @@ func (h *WebhookHandler) GetWebhookDeliveries(c *gin.Context) {
 func (h *WebhookHandler) RetryWebhookDelivery(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.Error(errors.NewUnauthorizedError("Unauthorized"))
+		c.Error(errors.NewUnauthorizedError("Unauthorized").WithKey("error.unauthorized"))
 		return
 	}
 
@@ -290,7 +290,7 @@ func (h *WebhookHandler) RetryWebhookDelivery(c *gin.Context) {
 	var webhook models.Webhook
 	if err := h.db.Where("id = ? AND user_id = ?", delivery.WebhookID, userID).First(&webhook).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.Error(errors.NewNotFoundError("Webhook not found"))
+			c.Error(errors.NewNotFoundError("Webhook not found").WithKey("error.webhook_not_found"))
 		} else {
 			c.Error(errors.NewInternalError("Failed to fetch webhook", err))
 		}
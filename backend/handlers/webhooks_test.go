@@ -9,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/gpay-remit/middleware"
 	"github.com/yourusername/gpay-remit/models"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -26,6 +27,7 @@ func TestCreateWebhook(t *testing.T) {
 	handler := NewWebhookHandler(db)
 
 	router := gin.New()
+	router.Use(middleware.ErrorHandler())
 	router.Use(func(c *gin.Context) {
 		c.Set("userID", uint(1))
 		c.Next()
@@ -75,6 +77,7 @@ func TestListWebhooks(t *testing.T) {
 	})
 
 	router := gin.New()
+	router.Use(middleware.ErrorHandler())
 	router.Use(func(c *gin.Context) {
 		c.Set("userID", uint(1))
 		c.Next()
@@ -108,6 +111,7 @@ func TestGetWebhook(t *testing.T) {
 	db.Create(&webhook)
 
 	router := gin.New()
+	router.Use(middleware.ErrorHandler())
 	router.Use(func(c *gin.Context) {
 		c.Set("userID", uint(1))
 		c.Next()
@@ -141,6 +145,7 @@ func TestUpdateWebhook(t *testing.T) {
 	db.Create(&webhook)
 
 	router := gin.New()
+	router.Use(middleware.ErrorHandler())
 	router.Use(func(c *gin.Context) {
 		c.Set("userID", uint(1))
 		c.Next()
@@ -182,6 +187,7 @@ func TestDeleteWebhook(t *testing.T) {
 	db.Create(&webhook)
 
 	router := gin.New()
+	router.Use(middleware.ErrorHandler())
 	router.Use(func(c *gin.Context) {
 		c.Set("userID", uint(1))
 		c.Next()
@@ -225,6 +231,7 @@ func TestGetWebhookDeliveries(t *testing.T) {
 	db.Create(&delivery)
 
 	router := gin.New()
+	router.Use(middleware.ErrorHandler())
 	router.Use(func(c *gin.Context) {
 		c.Set("userID", uint(1))
 		c.Next()
@@ -250,6 +257,7 @@ func TestCreateWebhook_InvalidURL(t *testing.T) {
 	handler := NewWebhookHandler(db)
 
 	router := gin.New()
+	router.Use(middleware.ErrorHandler())
 	router.Use(func(c *gin.Context) {
 		c.Set("userID", uint(1))
 		c.Next()
@@ -276,6 +284,7 @@ func TestGetWebhook_NotFound(t *testing.T) {
 	handler := NewWebhookHandler(db)
 
 	router := gin.New()
+	router.Use(middleware.ErrorHandler())
 	router.Use(func(c *gin.Context) {
 		c.Set("userID", uint(1))
 		c.Next()
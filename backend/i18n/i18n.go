@@ -0,0 +1,172 @@
+// Package i18n resolves user-facing messages from per-language catalogs
+// embedded from locales/<language>.json. Each catalog maps a message key to
+// a fmt format string. Lookups fall back from a regional tag to its base
+// language and then to English, so an unsupported language or a key missing
+// from a translation still yields a message.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage is the language every key is defined in and the one used
+// when nothing better matches.
+const DefaultLanguage = "en"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalogs maps a language to its messages, keyed by message key.
+var catalogs = loadCatalogs()
+
+func loadCatalogs() map[string]map[string]string {
+	files, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: failed to read locales: %v", err))
+	}
+	loaded := make(map[string]map[string]string, len(files))
+	for _, f := range files {
+		raw, err := localeFiles.ReadFile(path.Join("locales", f.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: failed to read %s: %v", f.Name(), err))
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(raw, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog %s: %v", f.Name(), err))
+		}
+		loaded[strings.TrimSuffix(f.Name(), ".json")] = messages
+	}
+	if _, ok := loaded[DefaultLanguage]; !ok {
+		panic("i18n: no catalog for the default language")
+	}
+	return loaded
+}
+
+// Languages lists the languages with a catalog, sorted.
+func Languages() []string {
+	languages := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Supported reports whether lang, or its base language, has a catalog.
+func Supported(lang string) bool {
+	_, ok := catalogs[normalize(lang)]
+	return ok
+}
+
+// normalize reduces a language tag such as "es-MX" or "ES_mx" to the
+// catalog name it is served from ("es"). A tag without a catalog of its own
+// is returned as its base language.
+func normalize(lang string) string {
+	lang = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+	if _, ok := catalogs[lang]; ok {
+		return lang
+	}
+	if i := strings.Index(lang, "-"); i > 0 {
+		return lang[:i]
+	}
+	return lang
+}
+
+// Lookup formats the message for key in lang with args. A key missing from
+// lang's catalog falls back to English; ok is false only when no catalog has
+// the key.
+func Lookup(lang, key string, args ...interface{}) (message string, ok bool) {
+	format, ok := catalogs[normalize(lang)][key]
+	if !ok {
+		format, ok = catalogs[DefaultLanguage][key]
+	}
+	if !ok {
+		return "", false
+	}
+	if len(args) == 0 {
+		return format, true
+	}
+	return fmt.Sprintf(format, args...), true
+}
+
+// T is Lookup for messages that must always render something: a key no
+// catalog has comes back as the key itself.
+func T(lang, key string, args ...interface{}) string {
+	if message, ok := Lookup(lang, key, args...); ok {
+		return message
+	}
+	return key
+}
+
+// MatchAcceptLanguage picks the supported language an Accept-Language header
+// prefers most, honouring q-values and falling back from a regional tag to
+// its base language. It returns DefaultLanguage when the header is empty or
+// names nothing supported.
+func MatchAcceptLanguage(header string) string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				parsed = 0
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{tag: tag, q: q})
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	for _, t := range tags {
+		if lang := normalize(t.tag); Supported(lang) {
+			return lang
+		}
+	}
+	return DefaultLanguage
+}
+
+// countryLanguages maps ISO 3166-1 alpha-2 country codes to the supported
+// language most of their users read. Countries not listed get English.
+var countryLanguages = map[string]string{
+	// Spanish
+	"AR": "es", "BO": "es", "CL": "es", "CO": "es", "CR": "es", "CU": "es",
+	"DO": "es", "EC": "es", "ES": "es", "GQ": "es", "GT": "es", "HN": "es",
+	"MX": "es", "NI": "es", "PA": "es", "PE": "es", "PR": "es", "PY": "es",
+	"SV": "es", "UY": "es", "VE": "es",
+	// French
+	"BF": "fr", "BI": "fr", "BJ": "fr", "CD": "fr", "CF": "fr", "CG": "fr",
+	"CI": "fr", "CM": "fr", "DJ": "fr", "FR": "fr", "GA": "fr", "GN": "fr",
+	"HT": "fr", "MC": "fr", "MG": "fr", "ML": "fr", "NE": "fr", "SN": "fr",
+	"TD": "fr", "TG": "fr",
+}
+
+// LanguageForCountry is the default language for users in country, an ISO
+// 3166-1 alpha-2 code. Unknown or empty countries get DefaultLanguage.
+func LanguageForCountry(country string) string {
+	if lang, ok := countryLanguages[strings.ToUpper(strings.TrimSpace(country))]; ok && Supported(lang) {
+		return lang
+	}
+	return DefaultLanguage
+}
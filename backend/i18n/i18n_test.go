@@ -0,0 +1,63 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogsMatchEnglish(t *testing.T) {
+	verbs := regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+	for lang, messages := range catalogs {
+		for key, message := range messages {
+			english, ok := catalogs[DefaultLanguage][key]
+			if !assert.True(t, ok, "%s has key %q that English lacks", lang, key) {
+				continue
+			}
+			assert.Equal(t, verbs.FindAllString(english, -1), verbs.FindAllString(message, -1), "%s: %s", lang, key)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	assert.Equal(t, "Pago no encontrado", T("es", "error.payment_not_found"))
+	assert.Equal(t, "Pago no encontrado", T("es-AR", "error.payment_not_found"), "regional tags use the base language")
+	assert.Equal(t, "La moneda NGN no es compatible", T("es", "error.currency_not_supported", "NGN"))
+	assert.Equal(t, "Payment not found", T("de", "error.payment_not_found"), "unsupported languages get English")
+
+	// A key missing from a translation falls back to English.
+	catalogs[DefaultLanguage]["test.only_english"] = "Only in English"
+	defer delete(catalogs[DefaultLanguage], "test.only_english")
+	assert.Equal(t, "Only in English", T("es", "test.only_english"))
+
+	_, ok := Lookup("es", "test.missing")
+	assert.False(t, ok)
+	assert.Equal(t, "test.missing", T("es", "test.missing"), "a key no catalog has renders as itself")
+}
+
+func TestMatchAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "en"},
+		{"es", "es"},
+		{"es-MX,es;q=0.9,en;q=0.8", "es"},
+		{"de-DE,fr;q=0.7,en;q=0.8", "en"},
+		{"de-DE,fr;q=0.7", "fr"},
+		{"fr;q=0,es", "es"},
+		{"ja,*;q=0.5", "en"},
+		{"FR-ca", "fr"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, MatchAcceptLanguage(tt.header), tt.header)
+	}
+}
+
+func TestLanguageForCountry(t *testing.T) {
+	assert.Equal(t, "es", LanguageForCountry("MX"))
+	assert.Equal(t, "fr", LanguageForCountry("sn"))
+	assert.Equal(t, "en", LanguageForCountry("NG"))
+	assert.Equal(t, "en", LanguageForCountry(""))
+}
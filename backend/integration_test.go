@@ -67,7 +67,7 @@ func setupTestDB(t *testing.T) (*gorm.DB, func()) {
 		return sqlDB.Ping() == nil
 	}, 30*time.Second, 500*time.Millisecond)
 
-	require.NoError(t, db.AutoMigrate(&models.User{}, &models.Payment{}, &models.Invoice{}, &models.Webhook{}, &models.WebhookDelivery{}, &models.IdempotencyRecord{}))
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.Payment{}, &models.Invoice{}, &models.Webhook{}, &models.WebhookDelivery{}, &models.IdempotencyRecord{}, &models.RefreshToken{}, &models.EmailVerificationToken{}))
 
 	return db, cleanup
 }
@@ -92,7 +92,7 @@ func setupRouter(db *gorm.DB) *gin.Engine {
 	router.POST("/api/v1/auth/login", authHandler.Login)
 
 	protected := router.Group("/api/v1")
-	protected.Use(middleware.JwtAuthMiddleware(cfg))
+	protected.Use(middleware.JwtAuthMiddleware(cfg, db))
 	remittanceHandler := handlers.NewRemittanceHandler(db, cfg)
 	protected.POST("/remittances", remittanceHandler.SendRemittance)
 	protected.GET("/remittances", remittanceHandler.ListRemittances)
@@ -13,10 +13,13 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/encryption"
 	"github.com/yourusername/gpay-remit/handlers"
 	"github.com/yourusername/gpay-remit/logger"
 	"github.com/yourusername/gpay-remit/middleware"
+	"github.com/yourusername/gpay-remit/models"
 	"github.com/yourusername/gpay-remit/services"
+	"github.com/yourusername/gpay-remit/utils"
 	"github.com/yourusername/gpay-remit/workers"
 )
 
@@ -32,6 +35,25 @@ func main() {
 		logger.Log.WithField("error", err).Fatal("Failed to load config")
 	}
 
+	if len(cfg.FieldEncryptionKeys) > 0 {
+		keys, err := encryption.NewStaticKeys(cfg.FieldEncryptionKeyVersion, cfg.FieldEncryptionKeys)
+		if err != nil {
+			logger.Log.WithField("error", err).Fatal("Invalid field encryption keys")
+		}
+		models.SetFieldCipher(encryption.NewCipher(keys))
+		logger.Log.WithField("key_version", cfg.FieldEncryptionKeyVersion).Info("Field encryption at rest enabled")
+	}
+
+	if cfg.DryRun {
+		logger.Log.Warn("DRY_RUN is set: Horizon is never contacted and no transaction is submitted")
+	} else if cfg.VerifyStellarNetwork {
+		if err := utils.VerifyNetwork(context.Background(), cfg.HorizonURL, cfg.NetworkPassphrase,
+			utils.WithHorizonHTTP(cfg.HorizonTimeout, cfg.HorizonReadRetries, cfg.HorizonRetryBackoff)); err != nil {
+			logger.Log.WithField("error", err).Fatal("Stellar network check failed")
+		}
+		logger.Log.WithField("stellar_network", cfg.StellarNetwork).Info("Horizon serves the configured Stellar network")
+	}
+
 	db, err := config.InitDB(cfg)
 	if err != nil {
 		logger.Log.WithField("error", err).Fatal("Failed to connect to database")
@@ -40,59 +62,128 @@ func main() {
 	router := gin.New()
 	router.Use(gin.Recovery())
 	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.Language())
 	router.Use(middleware.RequestLogger())
 	router.Use(middleware.ErrorHandler())
 	router.Use(middleware.VersionMiddleware())
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(middleware.NoRouteHandler())
+	router.NoMethod(middleware.NoMethodHandler())
 
-	router.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Version, Accept-Version")
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-		c.Next()
-	})
+	abuseDetector := middleware.NewAbuseDetector(db, cfg)
+	router.Use(middleware.AbuseGuard(abuseDetector))
+
+	router.Use(middleware.CORS(cfg))
 
 	healthHandler := handlers.NewHealthHandler(db, cfg)
 	router.GET("/health", healthHandler.Health)
 	router.GET("/health/ready", healthHandler.Ready)
 	router.GET("/health/live", healthHandler.Live)
 
+	signingCallbackHandler := handlers.NewRemittanceHandler(db, cfg)
+	router.POST("/internal/signing-callback",
+		middleware.SharedSecretAuth("X-Signing-Callback-Secret", cfg.SigningCallbackSecret),
+		middleware.AuditTrail(db),
+		signingCallbackHandler.SigningCallback)
+
 	router.GET("/api/docs", handlers.DocsUI)
 	router.GET("/api/docs/openapi.yaml", handlers.DocsSpec)
+	router.GET("/openapi.json", handlers.DocsJSON)
+	router.GET("/docs", handlers.DocsUI)
 
 	api := router.Group("/api/v1")
 	{
 		authHandler := handlers.NewAuthHandler(db, cfg)
+		authHandler.Abuse = abuseDetector
 		api.POST("/auth/register", authHandler.Register)
+		api.POST("/auth/stellar/challenge", authHandler.OwnershipChallenge)
 		api.POST("/auth/login", authHandler.Login)
 		api.POST("/auth/refresh", authHandler.Refresh)
+		api.POST("/auth/password/forgot", authHandler.ForgotPassword)
+		api.POST("/auth/password/reset", authHandler.ResetPassword)
+		api.GET("/auth/verify", authHandler.VerifyEmail)
 
 		api.POST("/users", authHandler.Register)
 
 		protected := api.Group("/")
-		protected.Use(middleware.JwtAuthMiddleware(cfg))
+		protected.Use(middleware.ApiKeyAuthMiddleware(db))
+		protected.Use(middleware.JwtAuthMiddleware(cfg, db))
 		protected.Use(middleware.AuditTrail(db))
 		{
+			protected.POST("/auth/logout", authHandler.Logout)
+			protected.POST("/auth/2fa/enroll", authHandler.EnrollTwoFactor)
+			protected.POST("/auth/2fa/verify", authHandler.VerifyTwoFactor)
+			protected.POST("/auth/verify/resend", authHandler.ResendVerification)
+
 			remittanceHandler := handlers.NewRemittanceHandler(db, cfg)
-			protected.POST("/remittances/create", remittanceHandler.CreateRemittance)
-			protected.POST("/remittances", remittanceHandler.SendRemittance)
+			requireVerifiedEmail := middleware.RequireVerifiedEmail(db, cfg.RequireEmailVerification)
+			twoFactor := middleware.TwoFactorThresholds{Default: cfg.TwoFactorThreshold, ByCurrency: cfg.TwoFactorThresholds}
+			protected.POST("/remittances/create", requireVerifiedEmail, middleware.RequireTwoFactorAbove(db, twoFactor, middleware.RemittanceAmount("asset_code")), remittanceHandler.CreateRemittance)
+			protected.POST("/remittances/simulate", remittanceHandler.SimulateRemittance)
+			protected.POST("/remittances", requireVerifiedEmail, middleware.RequireTwoFactorAbove(db, twoFactor, middleware.RemittanceAmount("currency")), remittanceHandler.SendRemittance)
+			protected.POST("/remittances/batch", requireVerifiedEmail, middleware.RequireTwoFactorAbove(db, twoFactor, middleware.BatchAmounts), remittanceHandler.CreateBatchRemittance)
 			protected.GET("/remittances/:id", remittanceHandler.GetRemittance)
+			protected.GET("/remittances/:id/stream", remittanceHandler.StreamRemittance)
+			protected.GET("/remittances/:id/signing-details", remittanceHandler.GetSigningDetails)
+			protected.GET("/remittances/:id/slippage", remittanceHandler.GetSlippage)
+			protected.POST("/remittances/:id/submit", remittanceHandler.SubmitRemittance)
+			protected.POST("/remittances/:id/cancel", remittanceHandler.CancelRemittance)
+			protected.GET("/remittances/:id/escrow", remittanceHandler.GetEscrowStatus)
+			protected.POST("/remittances/:id/release", remittanceHandler.ReleaseEscrow)
+			protected.POST("/remittances/:id/release/confirm", remittanceHandler.ConfirmRelease)
+			protected.POST("/remittances/:id/refund", remittanceHandler.RefundEscrow)
+			protected.POST("/remittances/:id/claim", remittanceHandler.ClaimRemittance)
+			protected.POST("/remittances/:id/refund/confirm", remittanceHandler.ConfirmRefund)
+			protected.POST("/remittances/:id/release/signatures", remittanceHandler.AddReleaseSignature)
 			protected.GET("/remittances", remittanceHandler.ListRemittances)
-			protected.POST("/remittances/:id/complete", middleware.RequireRole("admin"), remittanceHandler.CompleteRemittance)
+			protected.GET("/remittances/export", remittanceHandler.ExportRemittances)
+			protected.POST("/remittances/:id/complete", middleware.RequirePermission(db, models.PermissionRemittanceComplete), remittanceHandler.CompleteRemittance)
+			protected.POST("/remittances/:id/force-complete", middleware.RequireRole("admin"), remittanceHandler.ForceCompleteRemittance)
+			protected.POST("/remittances/:id/disputes", remittanceHandler.OpenDispute)
+			protected.GET("/disputes", middleware.RequireRole("admin"), remittanceHandler.ListDisputes)
+			protected.POST("/disputes/bulk-resolve", middleware.RequireRole("admin"), remittanceHandler.BulkResolveDisputes)
+			protected.POST("/disputes/:id/resolve", middleware.RequireRole("admin"), remittanceHandler.ResolveDispute)
 
 			protected.POST("/invoices", remittanceHandler.CreateInvoice)
+			protected.POST("/invoices/batch", remittanceHandler.CreateBatchInvoices)
 			protected.GET("/invoices", remittanceHandler.ListInvoices)
 			protected.GET("/invoices/:id", remittanceHandler.GetInvoice)
+			protected.GET("/invoices/:id/pdf", remittanceHandler.GetInvoicePDF)
+			protected.GET("/invoices/:id/verify", remittanceHandler.VerifyInvoicePDF)
+			protected.POST("/invoices/:id/void", remittanceHandler.VoidInvoice)
+			protected.POST("/invoices/:id/cancel", remittanceHandler.CancelInvoice)
+			protected.POST("/invoices/:id/pay", requireVerifiedEmail, middleware.RequireTwoFactorAbove(db, twoFactor, middleware.InvoiceAmount(db)), remittanceHandler.PayInvoice)
+
+			userHandler := handlers.NewUserHandler(db, cfg)
+			protected.GET("/users/me", userHandler.GetMyProfile)
+			protected.PUT("/users/me", userHandler.UpdateMyProfile)
+			protected.GET("/users/me/kyc", userHandler.GetMyKYC)
+			protected.GET("/users/me/summary", remittanceHandler.GetMySummary)
+			protected.GET("/users/me/notifications", userHandler.ListMyNotifications)
+			protected.POST("/users/me/notifications/:id/read", userHandler.MarkNotificationRead)
+			protected.GET("/users/me/notification-preferences", userHandler.GetMyNotificationPreferences)
+			protected.PUT("/users/me/notification-preferences", userHandler.UpdateMyNotificationPreferences)
+			protected.PATCH("/users/:id/kyc", middleware.RequireRole("admin"), userHandler.UpdateKYC)
+			protected.GET("/users", middleware.RequireRole("admin", "superadmin"), userHandler.ListUsers)
+			protected.PATCH("/users/:id", middleware.RequireRole("admin", "superadmin"), userHandler.UpdateUser)
+			protected.POST("/admin/users/:id/unlock", middleware.RequireRole("admin", "superadmin"), userHandler.UnlockUser)
+
+			accountHandler := handlers.NewAccountHandler(cfg)
+			protected.GET("/accounts/:address/balances", accountHandler.GetBalances)
+			protected.GET("/accounts/:address/payments", accountHandler.GetPaymentHistory)
+			protected.POST("/accounts/trustlines", accountHandler.CreateTrustline)
+			protected.POST("/accounts/merge", accountHandler.MergeAccount)
 
 			feeService := services.NewFeeService(cfg)
-			feeHandler := handlers.NewFeeHandler(feeService)
+			feeHandler := handlers.NewFeeHandler(feeService, services.StaticRateSource(cfg.FXRates))
 			protected.GET("/fees/calculate", feeHandler.Calculate)
+			protected.POST("/remittances/estimate-total", feeHandler.EstimateTotal)
+			protected.GET("/fx/rates", feeHandler.ListRates)
 
 			auditHandler := handlers.NewAuditLogHandler(db)
 			protected.GET("/audit/logs", middleware.RequireRole("admin"), auditHandler.List)
+			protected.GET("/audit-logs", middleware.RequireRole("admin", "superadmin"), auditHandler.Search)
+			protected.GET("/audit", middleware.RequireRole("admin", "superadmin"), auditHandler.Search)
 
 			exportHandler := handlers.NewExportHandler(db)
 			protected.GET("/transactions/export", exportHandler.ExportTransactions)
@@ -100,6 +191,24 @@ func main() {
 			// Admin rate limit management endpoints
 			protected.POST("/admin/rate-limit/reset", middleware.RequireRole("admin"), middleware.AdminResetRateLimit(cfg))
 			protected.GET("/admin/rate-limit/view", middleware.RequireRole("admin"), middleware.AdminViewRateLimits(cfg))
+			protected.GET("/admin/abuse/bans", middleware.RequireRole("admin"), middleware.AdminListBans(abuseDetector))
+			protected.GET("/admin/asset-issuers", middleware.RequireRole("admin"), remittanceHandler.GetIssuerPolicy)
+			protected.GET("/currencies", remittanceHandler.ListCurrencies)
+			protected.POST("/admin/abuse/unban", middleware.RequireRole("admin"), middleware.AdminUnban(abuseDetector))
+
+			// API key endpoints
+			apiKeyHandler := handlers.NewAPIKeyHandler(db)
+			protected.POST("/apikeys", apiKeyHandler.CreateAPIKey)
+			protected.GET("/apikeys", apiKeyHandler.ListAPIKeys)
+			protected.DELETE("/apikeys/:id", apiKeyHandler.RevokeAPIKey)
+
+			// Beneficiary (address book) endpoints
+			beneficiaryHandler := handlers.NewBeneficiaryHandler(db)
+			protected.POST("/beneficiaries", beneficiaryHandler.CreateBeneficiary)
+			protected.GET("/beneficiaries", beneficiaryHandler.ListBeneficiaries)
+			protected.GET("/beneficiaries/:id", beneficiaryHandler.GetBeneficiary)
+			protected.PUT("/beneficiaries/:id", beneficiaryHandler.UpdateBeneficiary)
+			protected.DELETE("/beneficiaries/:id", beneficiaryHandler.DeleteBeneficiary)
 
 			// Webhook endpoints
 			webhookHandler := handlers.NewWebhookHandler(db)
@@ -111,11 +220,21 @@ func main() {
 			protected.GET("/webhooks/:id/deliveries", webhookHandler.GetWebhookDeliveries)
 			protected.POST("/webhooks/deliveries/:delivery_id/retry", webhookHandler.RetryWebhookDelivery)
 
+			// Recurring remittance endpoints
+			recurringHandler := handlers.NewRecurringHandler(db, cfg)
+			protected.POST("/recurring-remittances", recurringHandler.CreateRecurringRemittance)
+			protected.GET("/recurring-remittances", recurringHandler.ListRecurringRemittances)
+			protected.POST("/recurring-remittances/:id/pause", recurringHandler.PauseRecurringRemittance)
+			protected.POST("/recurring-remittances/:id/resume", recurringHandler.ResumeRecurringRemittance)
+			protected.DELETE("/recurring-remittances/:id", recurringHandler.DeleteRecurringRemittance)
+
 			analyticsHandler := handlers.NewAnalyticsHandler(db)
 			protected.GET("/analytics/volume", middleware.RequireRole("admin"), analyticsHandler.GetVolumeMetrics)
 			protected.GET("/analytics/fees", middleware.RequireRole("admin"), analyticsHandler.GetFeeMetrics)
 			protected.GET("/analytics/success-rate", middleware.RequireRole("admin"), analyticsHandler.GetSuccessRate)
 			protected.GET("/analytics/top-corridors", middleware.RequireRole("admin"), analyticsHandler.GetTopCorridors)
+			protected.GET("/admin/fx-exposure", middleware.RequireRole("admin"), analyticsHandler.GetFXExposure)
+			protected.GET("/reports/remittances", middleware.RequireRole("admin"), analyticsHandler.GetRemittanceReport)
 		}
 	}
 
@@ -123,39 +242,119 @@ func main() {
 	api2.Use(middleware.RequireVersion("v2"))
 	{
 		authHandler := handlers.NewAuthHandler(db, cfg)
+		authHandler.Abuse = abuseDetector
 		api2.POST("/auth/register", authHandler.Register)
+		api2.POST("/auth/stellar/challenge", authHandler.OwnershipChallenge)
 		api2.POST("/auth/login", authHandler.Login)
 		api2.POST("/auth/refresh", authHandler.Refresh)
+		api2.POST("/auth/password/forgot", authHandler.ForgotPassword)
+		api2.POST("/auth/password/reset", authHandler.ResetPassword)
+		api2.GET("/auth/verify", authHandler.VerifyEmail)
 
 		api2.POST("/users", authHandler.Register)
 
 		protected := api2.Group("/")
-		protected.Use(middleware.JwtAuthMiddleware(cfg))
+		protected.Use(middleware.ApiKeyAuthMiddleware(db))
+		protected.Use(middleware.JwtAuthMiddleware(cfg, db))
 		protected.Use(middleware.AuditTrail(db))
 		{
+			protected.POST("/auth/logout", authHandler.Logout)
+			protected.POST("/auth/2fa/enroll", authHandler.EnrollTwoFactor)
+			protected.POST("/auth/2fa/verify", authHandler.VerifyTwoFactor)
+			protected.POST("/auth/verify/resend", authHandler.ResendVerification)
+
 			remittanceHandler := handlers.NewRemittanceHandler(db, cfg)
-			protected.POST("/remittances/create", remittanceHandler.CreateRemittance)
-			protected.POST("/remittances", remittanceHandler.SendRemittance)
+			requireVerifiedEmail := middleware.RequireVerifiedEmail(db, cfg.RequireEmailVerification)
+			twoFactor := middleware.TwoFactorThresholds{Default: cfg.TwoFactorThreshold, ByCurrency: cfg.TwoFactorThresholds}
+			protected.POST("/remittances/create", requireVerifiedEmail, middleware.RequireTwoFactorAbove(db, twoFactor, middleware.RemittanceAmount("asset_code")), remittanceHandler.CreateRemittance)
+			protected.POST("/remittances/simulate", remittanceHandler.SimulateRemittance)
+			protected.POST("/remittances", requireVerifiedEmail, middleware.RequireTwoFactorAbove(db, twoFactor, middleware.RemittanceAmount("currency")), remittanceHandler.SendRemittance)
+			protected.POST("/remittances/batch", requireVerifiedEmail, middleware.RequireTwoFactorAbove(db, twoFactor, middleware.BatchAmounts), remittanceHandler.CreateBatchRemittance)
 			protected.GET("/remittances/:id", remittanceHandler.GetRemittance)
+			protected.GET("/remittances/:id/stream", remittanceHandler.StreamRemittance)
+			protected.GET("/remittances/:id/signing-details", remittanceHandler.GetSigningDetails)
+			protected.GET("/remittances/:id/slippage", remittanceHandler.GetSlippage)
+			protected.POST("/remittances/:id/submit", remittanceHandler.SubmitRemittance)
+			protected.POST("/remittances/:id/cancel", remittanceHandler.CancelRemittance)
+			protected.GET("/remittances/:id/escrow", remittanceHandler.GetEscrowStatus)
+			protected.POST("/remittances/:id/release", remittanceHandler.ReleaseEscrow)
+			protected.POST("/remittances/:id/release/confirm", remittanceHandler.ConfirmRelease)
+			protected.POST("/remittances/:id/refund", remittanceHandler.RefundEscrow)
+			protected.POST("/remittances/:id/claim", remittanceHandler.ClaimRemittance)
+			protected.POST("/remittances/:id/refund/confirm", remittanceHandler.ConfirmRefund)
+			protected.POST("/remittances/:id/release/signatures", remittanceHandler.AddReleaseSignature)
 			protected.GET("/remittances", remittanceHandler.ListRemittances)
-			protected.POST("/remittances/:id/complete", middleware.RequireRole("admin"), remittanceHandler.CompleteRemittance)
+			protected.GET("/remittances/export", remittanceHandler.ExportRemittances)
+			protected.POST("/remittances/:id/complete", middleware.RequirePermission(db, models.PermissionRemittanceComplete), remittanceHandler.CompleteRemittance)
+			protected.POST("/remittances/:id/force-complete", middleware.RequireRole("admin"), remittanceHandler.ForceCompleteRemittance)
+			protected.POST("/remittances/:id/disputes", remittanceHandler.OpenDispute)
+			protected.GET("/disputes", middleware.RequireRole("admin"), remittanceHandler.ListDisputes)
+			protected.POST("/disputes/bulk-resolve", middleware.RequireRole("admin"), remittanceHandler.BulkResolveDisputes)
+			protected.POST("/disputes/:id/resolve", middleware.RequireRole("admin"), remittanceHandler.ResolveDispute)
 
 			protected.POST("/invoices", remittanceHandler.CreateInvoice)
+			protected.POST("/invoices/batch", remittanceHandler.CreateBatchInvoices)
 			protected.GET("/invoices", remittanceHandler.ListInvoices)
 			protected.GET("/invoices/:id", remittanceHandler.GetInvoice)
+			protected.GET("/invoices/:id/pdf", remittanceHandler.GetInvoicePDF)
+			protected.GET("/invoices/:id/verify", remittanceHandler.VerifyInvoicePDF)
+			protected.POST("/invoices/:id/void", remittanceHandler.VoidInvoice)
+			protected.POST("/invoices/:id/cancel", remittanceHandler.CancelInvoice)
+			protected.POST("/invoices/:id/pay", requireVerifiedEmail, middleware.RequireTwoFactorAbove(db, twoFactor, middleware.InvoiceAmount(db)), remittanceHandler.PayInvoice)
+
+			userHandler := handlers.NewUserHandler(db, cfg)
+			protected.GET("/users/me", userHandler.GetMyProfile)
+			protected.PUT("/users/me", userHandler.UpdateMyProfile)
+			protected.GET("/users/me/kyc", userHandler.GetMyKYC)
+			protected.GET("/users/me/summary", remittanceHandler.GetMySummary)
+			protected.GET("/users/me/notifications", userHandler.ListMyNotifications)
+			protected.POST("/users/me/notifications/:id/read", userHandler.MarkNotificationRead)
+			protected.GET("/users/me/notification-preferences", userHandler.GetMyNotificationPreferences)
+			protected.PUT("/users/me/notification-preferences", userHandler.UpdateMyNotificationPreferences)
+			protected.PATCH("/users/:id/kyc", middleware.RequireRole("admin"), userHandler.UpdateKYC)
+			protected.GET("/users", middleware.RequireRole("admin", "superadmin"), userHandler.ListUsers)
+			protected.PATCH("/users/:id", middleware.RequireRole("admin", "superadmin"), userHandler.UpdateUser)
+			protected.POST("/admin/users/:id/unlock", middleware.RequireRole("admin", "superadmin"), userHandler.UnlockUser)
+
+			accountHandler := handlers.NewAccountHandler(cfg)
+			protected.GET("/accounts/:address/balances", accountHandler.GetBalances)
+			protected.GET("/accounts/:address/payments", accountHandler.GetPaymentHistory)
+			protected.POST("/accounts/trustlines", accountHandler.CreateTrustline)
+			protected.POST("/accounts/merge", accountHandler.MergeAccount)
 
 			feeService := services.NewFeeService(cfg)
-			feeHandler := handlers.NewFeeHandler(feeService)
+			feeHandler := handlers.NewFeeHandler(feeService, services.StaticRateSource(cfg.FXRates))
 			protected.GET("/fees/calculate", feeHandler.Calculate)
+			protected.POST("/remittances/estimate-total", feeHandler.EstimateTotal)
+			protected.GET("/fx/rates", feeHandler.ListRates)
 
 			auditHandler := handlers.NewAuditLogHandler(db)
 			protected.GET("/audit/logs", middleware.RequireRole("admin"), auditHandler.List)
+			protected.GET("/audit-logs", middleware.RequireRole("admin", "superadmin"), auditHandler.Search)
+			protected.GET("/audit", middleware.RequireRole("admin", "superadmin"), auditHandler.Search)
 
 			exportHandler := handlers.NewExportHandler(db)
 			protected.GET("/transactions/export", exportHandler.ExportTransactions)
 
 			protected.POST("/admin/rate-limit/reset", middleware.RequireRole("admin"), middleware.AdminResetRateLimit(cfg))
 			protected.GET("/admin/rate-limit/view", middleware.RequireRole("admin"), middleware.AdminViewRateLimits(cfg))
+			protected.GET("/admin/abuse/bans", middleware.RequireRole("admin"), middleware.AdminListBans(abuseDetector))
+			protected.GET("/admin/asset-issuers", middleware.RequireRole("admin"), remittanceHandler.GetIssuerPolicy)
+			protected.GET("/currencies", remittanceHandler.ListCurrencies)
+			protected.POST("/admin/abuse/unban", middleware.RequireRole("admin"), middleware.AdminUnban(abuseDetector))
+
+			// API key endpoints
+			apiKeyHandler := handlers.NewAPIKeyHandler(db)
+			protected.POST("/apikeys", apiKeyHandler.CreateAPIKey)
+			protected.GET("/apikeys", apiKeyHandler.ListAPIKeys)
+			protected.DELETE("/apikeys/:id", apiKeyHandler.RevokeAPIKey)
+
+			beneficiaryHandler := handlers.NewBeneficiaryHandler(db)
+			protected.POST("/beneficiaries", beneficiaryHandler.CreateBeneficiary)
+			protected.GET("/beneficiaries", beneficiaryHandler.ListBeneficiaries)
+			protected.GET("/beneficiaries/:id", beneficiaryHandler.GetBeneficiary)
+			protected.PUT("/beneficiaries/:id", beneficiaryHandler.UpdateBeneficiary)
+			protected.DELETE("/beneficiaries/:id", beneficiaryHandler.DeleteBeneficiary)
 
 			webhookHandler := handlers.NewWebhookHandler(db)
 			protected.POST("/webhooks", webhookHandler.CreateWebhook)
@@ -166,11 +365,21 @@ func main() {
 			protected.GET("/webhooks/:id/deliveries", webhookHandler.GetWebhookDeliveries)
 			protected.POST("/webhooks/deliveries/:delivery_id/retry", webhookHandler.RetryWebhookDelivery)
 
+			// Recurring remittance endpoints
+			recurringHandler := handlers.NewRecurringHandler(db, cfg)
+			protected.POST("/recurring-remittances", recurringHandler.CreateRecurringRemittance)
+			protected.GET("/recurring-remittances", recurringHandler.ListRecurringRemittances)
+			protected.POST("/recurring-remittances/:id/pause", recurringHandler.PauseRecurringRemittance)
+			protected.POST("/recurring-remittances/:id/resume", recurringHandler.ResumeRecurringRemittance)
+			protected.DELETE("/recurring-remittances/:id", recurringHandler.DeleteRecurringRemittance)
+
 			analyticsHandler := handlers.NewAnalyticsHandler(db)
 			protected.GET("/analytics/volume", middleware.RequireRole("admin"), analyticsHandler.GetVolumeMetrics)
 			protected.GET("/analytics/fees", middleware.RequireRole("admin"), analyticsHandler.GetFeeMetrics)
 			protected.GET("/analytics/success-rate", middleware.RequireRole("admin"), analyticsHandler.GetSuccessRate)
 			protected.GET("/analytics/top-corridors", middleware.RequireRole("admin"), analyticsHandler.GetTopCorridors)
+			protected.GET("/admin/fx-exposure", middleware.RequireRole("admin"), analyticsHandler.GetFXExposure)
+			protected.GET("/reports/remittances", middleware.RequireRole("admin"), analyticsHandler.GetRemittanceReport)
 		}
 	}
 
@@ -182,6 +391,16 @@ func main() {
 	baseCtx, cancelWorkers := context.WithCancel(context.Background())
 	var wg sync.WaitGroup
 	workers.StartMonitor(baseCtx, &wg)
+	if len(cfg.FieldEncryptionKeys) > 0 && cfg.FieldEncryptionBackfill {
+		workers.StartEncryptionBackfill(baseCtx, &wg, db, cfg.FieldEncryptionBackfillBatch)
+	}
+	workers.StartInvoiceOverdueWorker(baseCtx, &wg, db, cfg.InvoiceOverdueCheckInterval)
+	workers.StartConditionSweeper(baseCtx, &wg, db, services.StaticRateSource(cfg.FXRates), cfg.ConditionSweepInterval)
+	workers.StartRetentionPurger(baseCtx, &wg, db, cfg.PaymentRetention, cfg.RetentionPurgeInterval)
+	workers.StartDeliveryCleanup(baseCtx, &wg, db, cfg.WebhookDeliveryRetention, cfg.WebhookDeliveryAlertAge, cfg.RetentionPurgeInterval)
+	workers.StartPendingExpiryWorker(baseCtx, &wg, db, cfg.PendingExpiryAge, cfg.PendingExpiryInterval)
+	workers.StartRecurringScheduler(baseCtx, &wg, db, services.NewFeeService(cfg), cfg.RecurringSchedulerInterval)
+	workers.StartSettlementPoller(baseCtx, &wg, db, utils.NewStellarClient(cfg.HorizonURL, cfg.NetworkPassphrase, utils.WithHorizonHTTP(cfg.HorizonTimeout, cfg.HorizonReadRetries, cfg.HorizonRetryBackoff), utils.WithDryRun(cfg.DryRun)), cfg.SettlementPollInterval, cfg.SettlementMaxChecks, cfg.SettlementBackoff)
 
 	errCh := make(chan error, 1)
 	go func() {
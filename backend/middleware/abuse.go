@@ -0,0 +1,264 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/models"
+	"gorm.io/gorm"
+)
+
+// Ban reasons recorded when a heuristic trips.
+const (
+	BanReasonFailedLoginsFromIP  = "failed logins across many accounts"
+	BanReasonFailedLoginsAccount = "repeated failed logins"
+	BanReasonRapidRegistration   = "rapid account creation"
+)
+
+// Ban is a temporary block on an IP ("ip:<addr>") or account ("account:<email>").
+type Ban struct {
+	Key       string    `json:"key"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+	Until     time.Time `json:"until"`
+}
+
+// IPBanKey and AccountBanKey build the keys bans are stored under.
+func IPBanKey(ip string) string { return "ip:" + ip }
+
+func AccountBanKey(email string) string { return "account:" + strings.ToLower(email) }
+
+// abuseCounter counts events, or distinct subjects when seen is used, within
+// a fixed window.
+type abuseCounter struct {
+	windowStart time.Time
+	count       int
+	seen        map[string]struct{}
+}
+
+// AbuseDetector applies heuristics that go beyond rate limiting, such as one
+// IP failing logins against many accounts or creating accounts in quick
+// succession, and temporarily bans the offending IP or account. Bans are
+// kept in memory and recorded in the audit log.
+type AbuseDetector struct {
+	mu  sync.Mutex
+	db  *gorm.DB
+	cfg *config.Config
+	now func() time.Time
+
+	ipLoginAccounts map[string]*abuseCounter
+	accountFailures map[string]*abuseCounter
+	registrations   map[string]*abuseCounter
+	bans            map[string]Ban
+}
+
+// NewAbuseDetector creates a detector using the thresholds in cfg. db may be
+// nil, in which case ban events are only logged.
+func NewAbuseDetector(db *gorm.DB, cfg *config.Config) *AbuseDetector {
+	return &AbuseDetector{
+		db:              db,
+		cfg:             cfg,
+		now:             time.Now,
+		ipLoginAccounts: make(map[string]*abuseCounter),
+		accountFailures: make(map[string]*abuseCounter),
+		registrations:   make(map[string]*abuseCounter),
+		bans:            make(map[string]Ban),
+	}
+}
+
+// count adds subject (or one event when subject is empty) to the counter for
+// key and returns the total within the current window.
+func (d *AbuseDetector) count(counters map[string]*abuseCounter, key, subject string) int {
+	now := d.now()
+	counter, ok := counters[key]
+	if !ok || now.Sub(counter.windowStart) > d.cfg.AbuseWindow {
+		counter = &abuseCounter{windowStart: now, seen: make(map[string]struct{})}
+		counters[key] = counter
+	}
+	if subject == "" {
+		counter.count++
+		return counter.count
+	}
+	counter.seen[subject] = struct{}{}
+	return len(counter.seen)
+}
+
+// RecordFailedLogin counts a failed login for email from ip, banning the IP
+// once it has failed against too many distinct accounts and the account once
+// it has failed too often.
+func (d *AbuseDetector) RecordFailedLogin(ip, email string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	email = strings.ToLower(email)
+	if limit := d.cfg.AbuseIPFailedLoginAccounts; limit > 0 {
+		if d.count(d.ipLoginAccounts, ip, email) >= limit {
+			d.ban(IPBanKey(ip), BanReasonFailedLoginsFromIP, ip)
+			delete(d.ipLoginAccounts, ip)
+		}
+	}
+	if limit := d.cfg.AbuseAccountFailedLogins; limit > 0 {
+		if d.count(d.accountFailures, email, "") >= limit {
+			d.ban(AccountBanKey(email), BanReasonFailedLoginsAccount, ip)
+			delete(d.accountFailures, email)
+		}
+	}
+}
+
+// RecordRegistration counts an account created from ip and bans the IP once
+// it creates too many within the window.
+func (d *AbuseDetector) RecordRegistration(ip string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if limit := d.cfg.AbuseIPRegistrations; limit > 0 {
+		if d.count(d.registrations, ip, "") >= limit {
+			d.ban(IPBanKey(ip), BanReasonRapidRegistration, ip)
+			delete(d.registrations, ip)
+		}
+	}
+}
+
+// ban must be called with d.mu held.
+func (d *AbuseDetector) ban(key, reason, ip string) {
+	now := d.now()
+	b := Ban{Key: key, Reason: reason, CreatedAt: now, Until: now.Add(d.cfg.AbuseBanDuration)}
+	d.bans[key] = b
+
+	logger.Log.WithFields(logrus.Fields{
+		"key":    key,
+		"reason": reason,
+		"until":  b.Until.Format(time.RFC3339),
+	}).Warn("Temporary ban applied")
+	d.record("abuse.ban", key, b, ip, nil)
+}
+
+// Banned returns the active ban for key, if any. Expired bans are dropped.
+func (d *AbuseDetector) Banned(key string) (Ban, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	b, ok := d.bans[key]
+	if !ok {
+		return Ban{}, false
+	}
+	if !d.now().Before(b.Until) {
+		delete(d.bans, key)
+		return Ban{}, false
+	}
+	return b, true
+}
+
+// Unban lifts the ban on key, reporting whether one was active.
+func (d *AbuseDetector) Unban(key, ip string, adminID *uint) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	b, ok := d.bans[key]
+	if !ok || !d.now().Before(b.Until) {
+		delete(d.bans, key)
+		return false
+	}
+	delete(d.bans, key)
+	d.record("abuse.unban", key, b, ip, adminID)
+	return true
+}
+
+// Bans returns the active bans ordered by key.
+func (d *AbuseDetector) Bans() []Ban {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	bans := make([]Ban, 0, len(d.bans))
+	for key, b := range d.bans {
+		if !now.Before(b.Until) {
+			delete(d.bans, key)
+			continue
+		}
+		bans = append(bans, b)
+	}
+	sort.Slice(bans, func(i, j int) bool { return bans[i].Key < bans[j].Key })
+	return bans
+}
+
+// record appends a ban event to the audit log.
+func (d *AbuseDetector) record(action, key string, b Ban, ip string, userID *uint) {
+	if d.db == nil {
+		return
+	}
+	entityType, entityID := key, ""
+	if i := strings.Index(key, ":"); i >= 0 {
+		entityType, entityID = key[:i], key[i+1:]
+	}
+	value, _ := json.Marshal(b)
+	entry := models.AuditLog{
+		UserID:     userID,
+		Action:     action,
+		Resource:   key,
+		EntityType: entityType,
+		EntityID:   entityID,
+		NewValue:   string(value),
+		IPAddress:  ip,
+	}
+	if err := d.db.Create(&entry).Error; err != nil {
+		logger.Log.WithField("error", err).Error("Failed to record abuse event")
+	}
+}
+
+// AbuseGuard rejects requests from banned IPs. It relies on ErrorHandler
+// being registered earlier to render the error.
+func AbuseGuard(detector *AbuseDetector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if b, banned := detector.Banned(IPBanKey(c.ClientIP())); banned {
+			c.Header("Retry-After", fmt.Sprintf("%d", int(time.Until(b.Until).Seconds())))
+			c.Error(errors.NewForbiddenError("Too many suspicious requests; try again later"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// AdminListBans returns the active bans (admin endpoint handler)
+func AdminListBans(detector *AbuseDetector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"bans": detector.Bans()})
+	}
+}
+
+// AdminUnban lifts a ban given as ?key=ip:<addr> or ?key=account:<email> (admin endpoint handler)
+func AdminUnban(detector *AbuseDetector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.Query("key")
+		if key == "" {
+			RespondError(c, http.StatusBadRequest, errors.CodeValidation, "key is required")
+			return
+		}
+		if strings.HasPrefix(key, "account:") {
+			key = AccountBanKey(strings.TrimPrefix(key, "account:"))
+		}
+
+		var adminID *uint
+		if v, ok := c.Get("userID"); ok {
+			if id, ok := v.(uint); ok {
+				adminID = &id
+			}
+		}
+		if !detector.Unban(key, c.ClientIP(), adminID) {
+			RespondError(c, http.StatusNotFound, errors.CodeNotFound, fmt.Sprintf("No active ban for %s", key))
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Ban lifted for %s", key)})
+	}
+}
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTestAbuseDetector(t *testing.T) (*AbuseDetector, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.AuditLog{}))
+
+	cfg := &config.Config{
+		AbuseIPFailedLoginAccounts: 3,
+		AbuseAccountFailedLogins:   5,
+		AbuseIPRegistrations:       2,
+		AbuseWindow:                time.Minute,
+		AbuseBanDuration:           time.Hour,
+	}
+	return NewAbuseDetector(db, cfg), db
+}
+
+func TestAbuseDetectorFailedLoginsAcrossAccounts(t *testing.T) {
+	detector, db := newTestAbuseDetector(t)
+
+	detector.RecordFailedLogin("10.0.0.1", "a@example.com")
+	detector.RecordFailedLogin("10.0.0.1", "b@example.com")
+	// Repeating an account does not count towards the distinct-account heuristic.
+	detector.RecordFailedLogin("10.0.0.1", "b@example.com")
+	_, banned := detector.Banned(IPBanKey("10.0.0.1"))
+	assert.False(t, banned)
+
+	detector.RecordFailedLogin("10.0.0.1", "c@example.com")
+	b, banned := detector.Banned(IPBanKey("10.0.0.1"))
+	assert.True(t, banned)
+	assert.Equal(t, BanReasonFailedLoginsFromIP, b.Reason)
+
+	_, banned = detector.Banned(IPBanKey("10.0.0.2"))
+	assert.False(t, banned, "other IPs are unaffected")
+
+	var event models.AuditLog
+	require.NoError(t, db.Where("action = ?", "abuse.ban").First(&event).Error)
+	assert.Equal(t, "ip", event.EntityType)
+	assert.Equal(t, "10.0.0.1", event.EntityID)
+}
+
+func TestAbuseDetectorFailedLoginsOnAccount(t *testing.T) {
+	detector, _ := newTestAbuseDetector(t)
+
+	for i := 0; i < 5; i++ {
+		detector.RecordFailedLogin(fmt.Sprintf("10.0.1.%d", i), "Victim@example.com")
+	}
+
+	_, banned := detector.Banned(AccountBanKey("victim@example.com"))
+	assert.True(t, banned)
+}
+
+func TestAbuseDetectorRapidRegistration(t *testing.T) {
+	detector, _ := newTestAbuseDetector(t)
+	now := time.Now()
+	detector.now = func() time.Time { return now }
+
+	detector.RecordRegistration("10.0.2.1")
+	// Outside the window the count starts over.
+	now = now.Add(2 * time.Minute)
+	detector.RecordRegistration("10.0.2.1")
+	_, banned := detector.Banned(IPBanKey("10.0.2.1"))
+	assert.False(t, banned)
+
+	detector.RecordRegistration("10.0.2.1")
+	_, banned = detector.Banned(IPBanKey("10.0.2.1"))
+	assert.True(t, banned)
+
+	// Bans expire on their own.
+	now = now.Add(2 * time.Hour)
+	_, banned = detector.Banned(IPBanKey("10.0.2.1"))
+	assert.False(t, banned)
+}
+
+func TestAbuseGuardAndUnban(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	detector, db := newTestAbuseDetector(t)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.Use(AbuseGuard(detector))
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, nil)
+		req.RemoteAddr = "192.0.2.10:1234"
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, request(http.MethodGet, "/ping").Code)
+
+	detector.RecordRegistration("192.0.2.10")
+	detector.RecordRegistration("192.0.2.10")
+
+	w := request(http.MethodGet, "/ping")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "FORBIDDEN")
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	// The admin calls from another IP, so the unban route is not guarded here.
+	adminRouter := gin.New()
+	adminRouter.POST("/admin/abuse/unban", func(c *gin.Context) {
+		c.Set("userID", uint(7))
+		c.Next()
+	}, AdminUnban(detector))
+
+	unban := func(key string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/admin/abuse/unban?key="+key, nil)
+		adminRouter.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, unban("ip:192.0.2.10").Code)
+	assert.Equal(t, http.StatusOK, request(http.MethodGet, "/ping").Code)
+	assert.Equal(t, http.StatusNotFound, unban("ip:192.0.2.10").Code)
+
+	var event models.AuditLog
+	require.NoError(t, db.Where("action = ?", "abuse.unban").First(&event).Error)
+	require.NotNil(t, event.UserID)
+	assert.Equal(t, uint(7), *event.UserID)
+}
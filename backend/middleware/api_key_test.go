@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestApiKeyAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.APIKey{}))
+
+	cfg := &config.Config{JWTSecret: "test-secret"}
+
+	user := models.User{Email: "psp@example.com", Name: "PSP", StellarAddress: "GPSP", PasswordHash: "x", Role: "user", IsActive: true}
+	inactive := models.User{Email: "gone@example.com", Name: "Gone", StellarAddress: "GGONE", PasswordHash: "x", Role: "user"}
+	admin := models.User{Email: "ops@example.com", Name: "Ops", StellarAddress: "GOPS", PasswordHash: "x", Role: "admin", IsActive: true}
+	require.NoError(t, db.Create(&user).Error)
+	require.NoError(t, db.Create(&inactive).Error)
+	require.NoError(t, db.Create(&admin).Error)
+	require.NoError(t, db.Model(&inactive).Update("is_active", false).Error)
+
+	past := time.Now().Add(-time.Hour)
+	createKey := func(key string, userID uint, scopes string, expiresAt, revokedAt *time.Time) models.APIKey {
+		apiKey := models.APIKey{UserID: userID, Name: key, Prefix: key[:8], KeyHash: models.HashAPIKey(key), Scopes: scopes, ExpiresAt: expiresAt, RevokedAt: revokedAt}
+		require.NoError(t, db.Create(&apiKey).Error)
+		return apiKey
+	}
+	readKey := createKey("gpk_read", user.ID, models.ScopeRemittancesRead, nil, nil)
+	createKey("gpk_write", user.ID, models.ScopeRemittancesWrite, nil, nil)
+	createKey("gpk_expired", user.ID, models.ScopeRemittancesRead, &past, nil)
+	createKey("gpk_revoked", user.ID, models.ScopeRemittancesRead, nil, &past)
+	createKey("gpk_inactive", inactive.ID, models.ScopeRemittancesRead, nil, nil)
+	createKey("gpk_admin", admin.ID, models.ScopeRemittancesWrite, nil, nil)
+
+	router := gin.New()
+	api := router.Group("/api/v1")
+	api.Use(ApiKeyAuthMiddleware(db), JwtAuthMiddleware(cfg, nil))
+	identity := func(c *gin.Context) {
+		_, viaKey := c.Get(APIKeyIDKey)
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetUint("userID"), "role": c.GetString("role"), "api_key": viaKey})
+	}
+	api.GET("/remittances/:id", identity)
+	api.POST("/remittances/create", identity)
+	api.POST("/apikeys", identity)
+	api.POST("/remittances/:id/force-complete", RequireRole("admin"), identity)
+
+	request := func(method, path, key, token string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, nil)
+		if key != "" {
+			req.Header.Set(APIKeyHeader, key)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("A key authenticates as its owner", func(t *testing.T) {
+		w := request(http.MethodGet, "/api/v1/remittances/7", "gpk_read", "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.JSONEq(t, `{"user_id":1,"role":"user","api_key":true}`, w.Body.String())
+
+		var stored models.APIKey
+		require.NoError(t, db.First(&stored, readKey.ID).Error)
+		assert.NotNil(t, stored.LastUsedAt)
+	})
+
+	t.Run("Keys only open routes their scopes cover", func(t *testing.T) {
+		w := request(http.MethodPost, "/api/v1/remittances/create", "gpk_read", "")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = request(http.MethodPost, "/api/v1/remittances/create", "gpk_write", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = request(http.MethodGet, "/api/v1/remittances/7", "gpk_write", "")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = request(http.MethodPost, "/api/v1/apikeys", "gpk_write", "")
+		assert.Equal(t, http.StatusForbidden, w.Code, "keys cannot manage keys")
+	})
+
+	t.Run("A key never carries its owner's admin role", func(t *testing.T) {
+		w := request(http.MethodPost, "/api/v1/remittances/create", "gpk_admin", "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.JSONEq(t, `{"user_id":3,"role":"user","api_key":true}`, w.Body.String())
+
+		w = request(http.MethodPost, "/api/v1/remittances/9/force-complete", "gpk_admin", "")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Unknown, expired and revoked keys are refused", func(t *testing.T) {
+		for _, key := range []string{"gpk_unknown", "gpk_expired", "gpk_revoked", "gpk_inactive"} {
+			w := request(http.MethodGet, "/api/v1/remittances/7", key, "")
+			assert.Equal(t, http.StatusUnauthorized, w.Code, key)
+		}
+	})
+
+	t.Run("Requests without a key fall back to JWT", func(t *testing.T) {
+		token, err := GenerateToken(42, "admin", cfg.JWTSecret, time.Hour)
+		require.NoError(t, err)
+
+		w := request(http.MethodGet, "/api/v1/remittances/7", "", token)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"user_id":42,"role":"admin","api_key":false}`, w.Body.String())
+
+		w = request(http.MethodGet, "/api/v1/remittances/7", "", "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
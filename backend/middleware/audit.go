@@ -5,15 +5,21 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/yourusername/gpay-remit/logger"
 	"github.com/yourusername/gpay-remit/models"
 	"gorm.io/gorm"
 )
 
 const (
-	auditOldKey = "audit_old"
-	auditNewKey = "audit_new"
+	auditOldKey       = "audit_old"
+	auditNewKey       = "audit_new"
+	auditOldStatusKey = "audit_old_status"
+	auditNewStatusKey = "audit_new_status"
+	auditReasonKey    = "audit_reason"
 )
 
 // SetAuditOld allows handlers to set a structured "before" snapshot.
@@ -36,6 +42,39 @@ func SetAuditNew(c *gin.Context, v interface{}) {
 	}
 }
 
+// SetAuditStatus records the before/after status explicitly, for resources
+// whose snapshot has no top-level "status" field (e.g. a user's kyc_status).
+func SetAuditStatus(c *gin.Context, before, after string) {
+	c.Set(auditOldStatusKey, before)
+	c.Set(auditNewStatusKey, after)
+}
+
+// SetAuditReason records the justification given for the action.
+func SetAuditReason(c *gin.Context, reason string) {
+	c.Set(auditReasonKey, reason)
+}
+
+// snapshotField returns a top-level scalar field of a JSON snapshot as a
+// string, or "" when the snapshot has no such field.
+func snapshotField(snapshot, field string) string {
+	if snapshot == "" {
+		return ""
+	}
+	dec := json.NewDecoder(strings.NewReader(snapshot))
+	dec.UseNumber()
+	var fields map[string]interface{}
+	if err := dec.Decode(&fields); err != nil {
+		return ""
+	}
+	switch v := fields[field].(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	}
+	return ""
+}
+
 func normalizeJSONB(s string) string {
 	if s == "" {
 		return ""
@@ -51,6 +90,20 @@ func normalizeJSONB(s string) string {
 	return string(enc)
 }
 
+// auditEntityType derives the entity type from a route path by taking the first
+// segment after the API version prefix and singularising it, e.g.
+// "/api/v1/invoices/:id/void" -> "invoice".
+func auditEntityType(resource string) string {
+	segments := strings.Split(strings.Trim(resource, "/"), "/")
+	for i, segment := range segments {
+		if segment == "api" || (i == 1 && strings.HasPrefix(segment, "v")) {
+			continue
+		}
+		return strings.TrimSuffix(segment, "s")
+	}
+	return ""
+}
+
 // AuditTrail logs successful, state-changing requests (POST/PUT/PATCH/DELETE)
 // into an append-only audit_logs table.
 func AuditTrail(db *gorm.DB) gin.HandlerFunc {
@@ -69,6 +122,11 @@ func AuditTrail(db *gorm.DB) gin.HandlerFunc {
 		if c.IsAborted() {
 			return
 		}
+		// Errors are rendered by ErrorHandler after this returns, so the
+		// writer may still report 200 for a rejected request.
+		if len(c.Errors) > 0 {
+			return
+		}
 		if c.Writer == nil || c.Writer.Status() < 200 || c.Writer.Status() >= 400 {
 			return
 		}
@@ -99,15 +157,41 @@ func AuditTrail(db *gorm.DB) gin.HandlerFunc {
 			newStr = string(requestBody)
 		}
 
-		log := models.AuditLog{
-			UserID:    userID,
-			Action:    method,
-			Resource:  resource,
-			OldValue:  normalizeJSONB(oldStr),
-			NewValue:  normalizeJSONB(newStr),
-			IPAddress: c.ClientIP(),
+		oldStatus := c.GetString(auditOldStatusKey)
+		newStatus := c.GetString(auditNewStatusKey)
+		if _, explicit := c.Get(auditNewStatusKey); !explicit {
+			oldStatus = snapshotField(oldStr, "status")
+			newStatus = snapshotField(newStr, "status")
 		}
 
-		_ = db.Create(&log).Error
+		// Creates have no :id in the route; take it from the created record.
+		entityID := c.Param("id")
+		if entityID == "" {
+			entityID = snapshotField(newStr, "id")
+		}
+
+		entry := models.AuditLog{
+			UserID:     userID,
+			Action:     method,
+			Resource:   resource,
+			EntityType: auditEntityType(resource),
+			EntityID:   entityID,
+			OldStatus:  oldStatus,
+			NewStatus:  newStatus,
+			Reason:     c.GetString(auditReasonKey),
+			OldValue:   normalizeJSONB(oldStr),
+			NewValue:   normalizeJSONB(newStr),
+			IPAddress:  c.ClientIP(),
+		}
+
+		// The request has already succeeded; a failed audit write is logged
+		// rather than surfaced.
+		if err := db.Create(&entry).Error; err != nil {
+			logger.Log.WithFields(logrus.Fields{
+				"resource":   resource,
+				"entity_id":  entityID,
+				"request_id": c.GetString("requestID"),
+			}).WithError(err).Error("Failed to write audit log")
+		}
 	}
 }
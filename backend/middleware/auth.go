@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"errors"
 	"net/http"
 	"strings"
@@ -8,7 +9,11 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/yourusername/gpay-remit/config"
+	apperrors "github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/models"
+	"gorm.io/gorm"
 )
 
 // Claims represents the JWT claims
@@ -34,20 +39,60 @@ func GenerateToken(userID uint, role string, secret string, expiry time.Duration
 	return token.SignedString([]byte(secret))
 }
 
-// JwtAuthMiddleware validates the JWT token and sets user info in the context
-func JwtAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
+// GenerateRefreshToken creates a refresh token carrying a fresh JWT ID, and
+// returns its claims so the token can be recorded and later revoked.
+func GenerateRefreshToken(userID uint, role string, secret string, expiry time.Duration) (string, *Claims, error) {
+	now := time.Now()
+	claims := &Claims{
+		UserID: userID,
+		Role:   role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		return "", nil, err
+	}
+	return token, claims, nil
+}
+
+// ParserOptions returns the options used to validate every token this
+// service issues: exp, iat, and nbf are checked with cfg.JWTClockSkew of
+// leeway so small clock differences between servers are tolerated.
+func ParserOptions(cfg *config.Config) []jwt.ParserOption {
+	return []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(cfg.JWTClockSkew),
+	}
+}
+
+// JwtAuthMiddleware validates the JWT token and sets user info in the context.
+// With a db, the token's user must still exist and be active, so deactivating
+// an account revokes its outstanding tokens at once, and the user's current
+// role replaces the one the token was issued with. A nil db trusts the claims.
+// Requests already authenticated by ApiKeyAuthMiddleware pass through.
+func JwtAuthMiddleware(cfg *config.Config, db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// ApiKeyAuthMiddleware has already authenticated the request.
+		if _, ok := c.Get(APIKeyIDKey); ok {
+			c.Next()
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
-			c.Abort()
+			RespondError(c, http.StatusUnauthorized, apperrors.CodeUnauthorized, Localize(c, "error.auth_header_required"))
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
-			c.Abort()
+			RespondError(c, http.StatusUnauthorized, apperrors.CodeUnauthorized, Localize(c, "error.auth_header_invalid"))
 			return
 		}
 
@@ -59,27 +104,43 @@ func JwtAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 				return nil, errors.New("unexpected signing method")
 			}
 			return []byte(cfg.JWTSecret), nil
-		})
+		}, ParserOptions(cfg)...)
 
 		if err != nil {
 			if errors.Is(err, jwt.ErrTokenExpired) {
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has expired", "code": "ExpiredToken"})
+				RespondError(c, http.StatusUnauthorized, apperrors.CodeExpiredToken, Localize(c, "error.token_expired"))
 			} else {
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token", "code": "InvalidToken"})
+				RespondError(c, http.StatusUnauthorized, apperrors.CodeInvalidToken, Localize(c, "error.token_invalid"))
 			}
-			c.Abort()
 			return
 		}
 
 		if !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token", "code": "InvalidToken"})
-			c.Abort()
+			RespondError(c, http.StatusUnauthorized, apperrors.CodeInvalidToken, Localize(c, "error.token_invalid"))
 			return
 		}
 
+		role := claims.Role
+		if db != nil {
+			var user models.User
+			if err := db.Select("id", "role", "is_active").First(&user, claims.UserID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					RespondError(c, http.StatusUnauthorized, apperrors.CodeInvalidToken, Localize(c, "error.token_invalid"))
+				} else {
+					RespondError(c, http.StatusInternalServerError, apperrors.CodeInternal, "Failed to load user")
+				}
+				return
+			}
+			if !user.IsActive {
+				RespondError(c, http.StatusUnauthorized, apperrors.CodeUnauthorized, Localize(c, "error.account_inactive"))
+				return
+			}
+			role = user.Role
+		}
+
 		// Set user information in context
 		c.Set("userID", claims.UserID)
-		c.Set("role", claims.Role)
+		c.Set("role", role)
 
 		c.Next()
 	}
@@ -90,15 +151,13 @@ func RequireRole(roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userRole, exists := c.Get("role")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User role not found in context"})
-			c.Abort()
+			RespondError(c, http.StatusUnauthorized, apperrors.CodeUnauthorized, "User role not found in context")
 			return
 		}
 
 		roleStr, ok := userRole.(string)
 		if !ok {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid role type in context"})
-			c.Abort()
+			RespondError(c, http.StatusInternalServerError, apperrors.CodeInternal, "Invalid role type in context")
 			return
 		}
 
@@ -111,11 +170,24 @@ func RequireRole(roles ...string) gin.HandlerFunc {
 		}
 
 		if !hasRole {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: insufficient permissions"})
-			c.Abort()
+			RespondError(c, http.StatusForbidden, apperrors.CodeForbidden, Localize(c, "error.insufficient_permissions"))
 			return
 		}
 
 		c.Next()
 	}
 }
+
+// SharedSecretAuth authenticates machine-to-machine callers that present
+// secret in the given header. An empty secret rejects every request, so the
+// route stays closed until it is configured.
+func SharedSecretAuth(header, secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := c.GetHeader(header)
+		if secret == "" || provided == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(secret)) != 1 {
+			RespondError(c, http.StatusUnauthorized, apperrors.CodeUnauthorized, "Invalid or missing "+header)
+			return
+		}
+		c.Next()
+	}
+}
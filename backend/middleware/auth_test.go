@@ -1,14 +1,20 @@
 package middleware
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 )
 
 func TestJwtAuthMiddleware(t *testing.T) {
@@ -60,7 +66,7 @@ func TestJwtAuthMiddleware(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			router := gin.New()
-			router.Use(JwtAuthMiddleware(cfg))
+			router.Use(JwtAuthMiddleware(cfg, nil))
 			router.GET("/test", func(c *gin.Context) {
 				role, _ := c.Get("role")
 				c.JSON(http.StatusOK, gin.H{"role": role})
@@ -75,13 +81,74 @@ func TestJwtAuthMiddleware(t *testing.T) {
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
-			if tt.expectedCode != "" {
-				assert.Contains(t, w.Body.String(), tt.expectedCode)
+			if tt.expectedStatus != http.StatusOK {
+				var resp ErrorResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.NotEmpty(t, resp.Error.Message)
+				if tt.expectedCode != "" {
+					assert.Equal(t, tt.expectedCode, string(resp.Error.Code))
+				}
 			}
 		})
 	}
 }
 
+func TestJwtAuthMiddlewareClockSkew(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{
+		JWTSecret:    "test-secret",
+		JWTClockSkew: 30 * time.Second,
+	}
+
+	router := gin.New()
+	router.Use(JwtAuthMiddleware(cfg, nil))
+	router.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := func(token string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	// signed issues a token whose claims are shifted by the given offsets from now.
+	signed := func(issued, notBefore, expires time.Duration) string {
+		now := time.Now()
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+			UserID: 1,
+			Role:   "user",
+			RegisteredClaims: jwt.RegisteredClaims{
+				IssuedAt:  jwt.NewNumericDate(now.Add(issued)),
+				NotBefore: jwt.NewNumericDate(now.Add(notBefore)),
+				ExpiresAt: jwt.NewNumericDate(now.Add(expires)),
+			},
+		})
+		s, _ := token.SignedString([]byte(cfg.JWTSecret))
+		return s
+	}
+
+	t.Run("Expired within the leeway is accepted", func(t *testing.T) {
+		w := request(signed(-time.Hour, -time.Hour, -10*time.Second))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Expired beyond the leeway is rejected", func(t *testing.T) {
+		w := request(signed(-time.Hour, -time.Hour, -2*time.Minute))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "ExpiredToken")
+	})
+
+	t.Run("Issued slightly in the future is accepted", func(t *testing.T) {
+		w := request(signed(10*time.Second, 10*time.Second, time.Hour))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Not valid until beyond the leeway is rejected", func(t *testing.T) {
+		w := request(signed(2*time.Minute, 2*time.Minute, time.Hour))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
 func TestRequireRole(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -144,3 +211,47 @@ func TestRequireRole(t *testing.T) {
 		})
 	}
 }
+
+func TestJwtAuthMiddlewareActiveUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.User{}))
+	cfg := &config.Config{JWTSecret: "test-secret"}
+
+	active := models.User{Email: "active@example.com", Name: "Active", StellarAddress: "GACTIVE", PasswordHash: "x", Role: "admin", IsActive: true}
+	inactive := models.User{Email: "inactive@example.com", Name: "Inactive", StellarAddress: "GINACTIVE", PasswordHash: "x", IsActive: true}
+	require.NoError(t, db.Create(&active).Error)
+	require.NoError(t, db.Create(&inactive).Error)
+	require.NoError(t, db.Model(&inactive).Update("is_active", false).Error)
+
+	router := gin.New()
+	router.Use(JwtAuthMiddleware(cfg, db))
+	router.GET("/test", func(c *gin.Context) {
+		role, _ := c.Get("role")
+		c.JSON(http.StatusOK, gin.H{"role": role})
+	})
+
+	request := func(userID uint, role string) *httptest.ResponseRecorder {
+		token, _ := GenerateToken(userID, role, cfg.JWTSecret, time.Hour)
+		req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Active user gets their current role", func(t *testing.T) {
+		w := request(active.ID, "user")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"role":"admin"}`, w.Body.String())
+	})
+
+	t.Run("Inactive user is rejected", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, request(inactive.ID, "user").Code)
+	})
+
+	t.Run("Deleted user is rejected", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, request(424242, "user").Code)
+	})
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gpay-remit/config"
+)
+
+// CORS answers cross-origin requests from the origins in
+// cfg.CORSAllowedOrigins. An allowed origin is echoed back with credentials
+// permitted; other origins get no CORS headers, so browsers block them. Only
+// an allowlist of exactly "*" answers with a wildcard, which browsers never
+// combine with credentials. Preflight requests are answered here with the
+// configured methods and headers.
+func CORS(cfg *config.Config) gin.HandlerFunc {
+	allowed := make(map[string]struct{}, len(cfg.CORSAllowedOrigins))
+	for _, origin := range cfg.CORSAllowedOrigins {
+		allowed[origin] = struct{}{}
+	}
+	_, wildcard := allowed["*"]
+	methods := strings.Join(cfg.CORSAllowedMethods, ", ")
+	headers := strings.Join(cfg.CORSAllowedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.CORSMaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		if !wildcard {
+			// Responses differ per origin, so caches must key on it.
+			c.Writer.Header().Add("Vary", "Origin")
+		}
+		_, ok := allowed[origin]
+		if !ok && !wildcard {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if wildcard {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		if preflight {
+			c.Header("Access-Control-Allow-Methods", methods)
+			c.Header("Access-Control-Allow-Headers", headers)
+			if cfg.CORSMaxAge > 0 {
+				c.Header("Access-Control-Max-Age", maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/gpay-remit/config"
+)
+
+func newCORSRouter(origins ...string) *gin.Engine {
+	cfg := &config.Config{
+		CORSAllowedOrigins: origins,
+		CORSAllowedMethods: []string{"GET", "POST", "OPTIONS"},
+		CORSAllowedHeaders: []string{"Content-Type", "Authorization"},
+		CORSMaxAge:         10 * time.Minute,
+	}
+	router := gin.New()
+	router.Use(CORS(cfg))
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func corsRequest(router *gin.Engine, method, origin string, headers map[string]string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, "/ping", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newCORSRouter("https://app.example.com")
+
+	t.Run("Allowed origin is echoed with credentials", func(t *testing.T) {
+		w := corsRequest(router, http.MethodGet, "https://app.example.com", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Equal(t, "Origin", w.Header().Get("Vary"))
+	})
+
+	t.Run("Disallowed origin gets no CORS headers", func(t *testing.T) {
+		w := corsRequest(router, http.MethodGet, "https://evil.example.com", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	})
+
+	t.Run("Preflight from an allowed origin", func(t *testing.T) {
+		w := corsRequest(router, http.MethodOptions, "https://app.example.com", map[string]string{
+			"Access-Control-Request-Method":  "POST",
+			"Access-Control-Request-Headers": "Authorization",
+		})
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "GET, POST, OPTIONS", w.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "Content-Type, Authorization", w.Header().Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("Preflight from a disallowed origin is rejected", func(t *testing.T) {
+		w := corsRequest(router, http.MethodOptions, "https://evil.example.com", map[string]string{
+			"Access-Control-Request-Method": "POST",
+		})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Methods"))
+	})
+
+	t.Run("Requests without an origin are untouched", func(t *testing.T) {
+		w := corsRequest(router, http.MethodGet, "", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+func TestCORSWildcard(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newCORSRouter("*")
+
+	w := corsRequest(router, http.MethodGet, "https://anywhere.example.com", nil)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/models"
+	"gorm.io/gorm"
+)
+
+// RequireVerifiedEmail refuses requests from users who have not confirmed
+// their email address with a 403 EmailNotVerified. It is disabled when
+// enabled is false.
+func RequireVerifiedEmail(db *gorm.DB, enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled {
+			c.Next()
+			return
+		}
+
+		var user models.User
+		if err := db.Select("id", "verified_at").First(&user, c.GetUint("userID")).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				RespondError(c, http.StatusUnauthorized, apperrors.CodeUnauthorized, Localize(c, "error.user_not_found"))
+			} else {
+				RespondError(c, http.StatusInternalServerError, apperrors.CodeInternal, "Failed to load user")
+			}
+			return
+		}
+		if user.VerifiedAt == nil {
+			RespondError(c, http.StatusForbidden, apperrors.CodeEmailNotVerified,
+				"Verify your email address before sending remittances; POST /auth/verify/resend sends a new link")
+			return
+		}
+
+		c.Next()
+	}
+}
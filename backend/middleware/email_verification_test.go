@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRequireVerifiedEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.User{}))
+
+	now := time.Now()
+	verified := models.User{Email: "verified@example.com", Name: "Verified", StellarAddress: "GVERIFIED", PasswordHash: "x", IsActive: true, VerifiedAt: &now}
+	unverified := models.User{Email: "unverified@example.com", Name: "Unverified", StellarAddress: "GUNVERIFIED", PasswordHash: "x", IsActive: true}
+	require.NoError(t, db.Create(&verified).Error)
+	require.NoError(t, db.Create(&unverified).Error)
+
+	request := func(userID uint, enabled bool) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", userID)
+			c.Next()
+		})
+		router.POST("/remittances/create", RequireVerifiedEmail(db, enabled), func(c *gin.Context) {
+			c.Status(http.StatusCreated)
+		})
+
+		req, _ := http.NewRequest(http.MethodPost, "/remittances/create", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusCreated, request(verified.ID, true).Code)
+
+	w := request(unverified.ID, true)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "EmailNotVerified")
+
+	assert.Equal(t, http.StatusCreated, request(unverified.ID, false).Code, "the check can be disabled")
+	assert.Equal(t, http.StatusUnauthorized, request(9999, true).Code)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/i18n"
 )
 
 // RequestIDMiddleware adds a unique request ID to each request
@@ -24,13 +25,61 @@ func RequestIDMiddleware() gin.HandlerFunc {
 	}
 }
 
-// ErrorResponse is the standardized JSON error response
+// NoRouteHandler reports unknown paths in the standard error envelope.
+// It relies on ErrorHandler being registered globally to render the error.
+func NoRouteHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Error(errors.NewNotFoundError(fmt.Sprintf("No route matches %s %s", c.Request.Method, c.Request.URL.Path)).
+			WithKey("error.no_route", c.Request.Method, c.Request.URL.Path))
+	}
+}
+
+// NoMethodHandler reports a known path requested with an unsupported method.
+// The engine must have HandleMethodNotAllowed enabled for it to be used.
+func NoMethodHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Error(errors.NewMethodNotAllowedError(fmt.Sprintf("Method %s is not allowed on %s", c.Request.Method, c.Request.URL.Path)).
+			WithKey("error.method_not_allowed", c.Request.Method, c.Request.URL.Path))
+	}
+}
+
+// ErrorResponse is the envelope every error response is sent in.
 type ErrorResponse struct {
-	Error struct {
-		Code    errors.ErrorCode `json:"code"`
-		Message string           `json:"message"`
-		Details interface{}      `json:"details,omitempty"`
-	} `json:"error"`
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody describes the error; Code is one of the errors.Code* constants.
+type ErrorBody struct {
+	Code    errors.ErrorCode `json:"code"`
+	Message string           `json:"message"`
+	Details interface{}      `json:"details,omitempty"`
+}
+
+// RespondError writes an error envelope and aborts the request. Handlers
+// should report errors with c.Error so ErrorHandler renders them; this is for
+// middleware that must respond without relying on ErrorHandler.
+func RespondError(c *gin.Context, status int, code errors.ErrorCode, message string) {
+	RespondErrorWithDetails(c, status, code, message, nil)
+}
+
+// RespondErrorWithDetails is RespondError with machine-readable details.
+func RespondErrorWithDetails(c *gin.Context, status int, code errors.ErrorCode, message string, details interface{}) {
+	c.AbortWithStatusJSON(status, ErrorResponse{Error: ErrorBody{Code: code, Message: message, Details: details}})
+}
+
+// ErrorMessage is the message sent to the client for appErr: internal
+// details are hidden behind a generic message for 5xx errors, and keyed
+// messages are sent in the request's language. The code is never translated.
+func ErrorMessage(c *gin.Context, appErr *errors.AppError) string {
+	if appErr.HTTPStatus >= 500 {
+		return Localize(c, "error.internal")
+	}
+	if appErr.MessageKey != "" {
+		if localized, ok := i18n.Lookup(RequestLanguage(c), appErr.MessageKey, appErr.MessageArgs...); ok {
+			return localized
+		}
+	}
+	return appErr.Message
 }
 
 // ErrorHandler handles panics and standardized error responses
@@ -53,11 +102,7 @@ func ErrorHandler() gin.HandlerFunc {
 				}).Error("Panic recovered")
 
 				// Standard 500 response
-				resp := ErrorResponse{}
-				resp.Error.Code = errors.CodeInternal
-				resp.Error.Message = "An internal server error occurred"
-				
-				c.AbortWithStatusJSON(http.StatusInternalServerError, resp)
+				RespondError(c, http.StatusInternalServerError, errors.CodeInternal, Localize(c, "error.internal"))
 			}
 		}()
 
@@ -99,20 +144,9 @@ func ErrorHandler() gin.HandlerFunc {
 				logrus.WithFields(logFields).Warn(appErr.Message)
 			}
 
-			// Prepare response - hide internal details for 500 errors
-			message := appErr.Message
-			if appErr.HTTPStatus >= 500 {
-				message = "An internal server error occurred"
-			}
-
-			resp := ErrorResponse{}
-			resp.Error.Code = appErr.Code
-			resp.Error.Message = message
-			resp.Error.Details = appErr.Details
-
 			// If response was already written, we can't change it
 			if !c.Writer.Written() {
-				c.JSON(appErr.HTTPStatus, resp)
+				RespondErrorWithDetails(c, appErr.HTTPStatus, appErr.Code, ErrorMessage(c, appErr), appErr.Details)
 			}
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -75,3 +76,126 @@ func TestErrorHandler(t *testing.T) {
 		assert.Equal(t, "An internal server error occurred", resp.Error.Message)
 	})
 }
+
+func TestNoRouteAndNoMethodHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(NoRouteHandler())
+	router.NoMethod(NoMethodHandler())
+	router.GET("/remittances", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	t.Run("Unknown path", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/does-not-exist", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+
+		var resp ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, errors.CodeNotFound, resp.Error.Code)
+		assert.Contains(t, resp.Error.Message, "/does-not-exist")
+	})
+
+	t.Run("Wrong method", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/remittances", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+		var resp ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, errors.CodeMethodNotAllowed, resp.Error.Code)
+		assert.Contains(t, resp.Error.Message, "DELETE")
+	})
+}
+
+func TestErrorHandlerBindingError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type bindRequest struct {
+		Amount float64 `json:"amount" binding:"required,gt=0"`
+		Email  string  `json:"email" binding:"required,email"`
+	}
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.POST("/bind", func(c *gin.Context) {
+		var req bindRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(errors.NewBindingError(err))
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/bind", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Validation failure lists the fields", func(t *testing.T) {
+		w := post(`{"amount": -5, "email": "not-an-email"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp struct {
+			Error struct {
+				Code    errors.ErrorCode    `json:"code"`
+				Message string              `json:"message"`
+				Details []errors.FieldError `json:"details"`
+			} `json:"error"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, errors.CodeValidation, resp.Error.Code)
+		assert.Equal(t, "Invalid request body", resp.Error.Message)
+		if assert.Len(t, resp.Error.Details, 2) {
+			assert.Equal(t, "amount", resp.Error.Details[0].Field)
+			assert.Equal(t, "gt", resp.Error.Details[0].Rule)
+			assert.Equal(t, "amount must be greater than 0", resp.Error.Details[0].Message)
+			assert.Equal(t, "email", resp.Error.Details[1].Field)
+			assert.Equal(t, "email", resp.Error.Details[1].Rule)
+			assert.Equal(t, "email must be a valid email address", resp.Error.Details[1].Message)
+		}
+	})
+
+	t.Run("Malformed JSON is a plain validation error", func(t *testing.T) {
+		w := post(`{"amount":`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, errors.CodeValidation, resp.Error.Code)
+		assert.IsType(t, "", resp.Error.Details)
+	})
+}
+
+func TestRespondError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/limited", func(c *gin.Context) {
+		RespondErrorWithDetails(c, http.StatusTooManyRequests, errors.CodeRateLimited, "rate limit exceeded", gin.H{"retry_after": 30})
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/limited", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	var resp ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, errors.CodeRateLimited, resp.Error.Code)
+	assert.Equal(t, "rate limit exceeded", resp.Error.Message)
+	assert.Equal(t, map[string]interface{}{"retry_after": float64(30)}, resp.Error.Details)
+}
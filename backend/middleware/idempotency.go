@@ -1,7 +1,6 @@
 package middleware
 
 import (
-	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -13,6 +12,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gpay-remit/errors"
 	"github.com/yourusername/gpay-remit/models"
 	"gorm.io/gorm"
 )
@@ -54,26 +54,20 @@ func IdempotencyMiddleware(db *gorm.DB, config ...IdempotencyConfig) gin.Handler
 		// Get idempotency key from header
 		idempotencyKey := c.GetHeader("Idempotency-Key")
 		if idempotencyKey == "" {
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"error": "Idempotency-Key header is required for this request",
-			})
+			RespondError(c, http.StatusBadRequest, errors.CodeValidation, "Idempotency-Key header is required for this request")
 			return
 		}
 
 		// Validate idempotency key format
 		if err := validateIdempotencyKey(idempotencyKey); err != nil {
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"error": err.Error(),
-			})
+			RespondError(c, http.StatusBadRequest, errors.CodeValidation, err.Error())
 			return
 		}
 
 		// Read request body for hashing
 		bodyBytes, err := io.ReadAll(c.Request.Body)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"error": "Failed to read request body",
-			})
+			RespondError(c, http.StatusBadRequest, errors.CodeValidation, "Failed to read request body")
 			return
 		}
 
@@ -91,8 +85,7 @@ func IdempotencyMiddleware(db *gorm.DB, config ...IdempotencyConfig) gin.Handler
 			// Record exists - check if it's the same request
 			if existingRecord.RequestHash != requestHash {
 				// Different request body with same key - return 409
-				c.AbortWithStatusJSON(http.StatusConflict, gin.H{
-					"error":         "Idempotency key already used with different request body",
+				RespondErrorWithDetails(c, http.StatusConflict, errors.CodeIdempotencyConflict, "Idempotency key already used with different request body", gin.H{
 					"existing_hash": existingRecord.RequestHash,
 					"new_hash":      requestHash,
 				})
@@ -144,9 +137,7 @@ func IdempotencyMiddleware(db *gorm.DB, config ...IdempotencyConfig) gin.Handler
 		}
 
 		if err := db.Create(&record).Error; err != nil {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to create idempotency record",
-			})
+			RespondError(c, http.StatusInternalServerError, errors.CodeInternal, "Failed to create idempotency record")
 			return
 		}
 
@@ -246,9 +237,7 @@ func handleConcurrentRequest(c *gin.Context, key string, record *models.Idempote
 
 		// Check timeout
 		if time.Since(startTime) > maxWaitTime {
-			c.AbortWithStatusJSON(http.StatusRequestTimeout, gin.H{
-				"error": "Request with same idempotency key is still processing",
-			})
+			RespondError(c, http.StatusRequestTimeout, errors.CodeIdempotencyConflict, "Request with same idempotency key is still processing")
 			return
 		}
 
@@ -272,12 +261,9 @@ func updateIdempotencyRecord(c *gin.Context, key string, db *gorm.DB) {
 
 	// Get response body
 	responseBody := ""
-	if rw, ok := c.Writer.(*gin.ResponseWriter); ok {
-		// Try to capture response from context if stored
-		if val, exists := c.Get("idempotency_response"); exists {
-			if resp, ok := val.(string); ok {
-				responseBody = resp
-			}
+	if val, exists := c.Get("idempotency_response"); exists {
+		if resp, ok := val.(string); ok {
+			responseBody = resp
 		}
 	}
 
@@ -285,7 +271,8 @@ func updateIdempotencyRecord(c *gin.Context, key string, db *gorm.DB) {
 	record.Status = "completed"
 	record.ResponseStatus = statusCode
 	record.ResponseBody = responseBody
-	record.CompletedAt = time.Now()
+	now := time.Now()
+	record.CompletedAt = &now
 
 	db.Save(&record)
 
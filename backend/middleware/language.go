@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gpay-remit/i18n"
+)
+
+// Language resolves the client's preferred language from Accept-Language
+// and stores it in the context for handlers and ErrorHandler. Responses name
+// the language they were rendered in.
+func Language() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := i18n.MatchAcceptLanguage(c.GetHeader("Accept-Language"))
+		c.Set("language", lang)
+		c.Header("Content-Language", lang)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		c.Next()
+	}
+}
+
+// RequestLanguage is the language resolved by Language, or, on routes it
+// does not run on, the one Accept-Language prefers.
+func RequestLanguage(c *gin.Context) string {
+	if lang := c.GetString("language"); lang != "" {
+		return lang
+	}
+	return i18n.MatchAcceptLanguage(c.GetHeader("Accept-Language"))
+}
+
+// Localize renders the catalog message key in the request's language.
+func Localize(c *gin.Context, key string, args ...interface{}) string {
+	return i18n.T(RequestLanguage(c), key, args...)
+}
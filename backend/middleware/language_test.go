@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/errors"
+)
+
+func TestLocalizedErrorMessages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.Use(Language())
+	router.GET("/payments/:id", func(c *gin.Context) {
+		c.Error(errors.NewNotFoundError("Payment not found").WithKey("error.payment_not_found"))
+	})
+	router.GET("/unkeyed", func(c *gin.Context) {
+		c.Error(errors.NewConflictError("Escrow has already been released"))
+	})
+	router.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	get := func(path, acceptLanguage string) (*httptest.ResponseRecorder, ErrorResponse) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		router.ServeHTTP(w, req)
+		var resp ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w, resp
+	}
+
+	t.Run("Same code, message in the requested language", func(t *testing.T) {
+		w, en := get("/payments/1", "en")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "en", w.Header().Get("Content-Language"))
+		_, es := get("/payments/1", "es-MX,es;q=0.9")
+
+		assert.Equal(t, errors.CodeNotFound, en.Error.Code)
+		assert.Equal(t, en.Error.Code, es.Error.Code)
+		assert.Equal(t, "Payment not found", en.Error.Message)
+		assert.Equal(t, "Pago no encontrado", es.Error.Message)
+	})
+
+	t.Run("Unsupported language falls back to English", func(t *testing.T) {
+		_, resp := get("/payments/1", "de-DE,ja;q=0.5")
+		assert.Equal(t, "Payment not found", resp.Error.Message)
+	})
+
+	t.Run("Messages without a key are sent as written", func(t *testing.T) {
+		_, resp := get("/unkeyed", "es")
+		assert.Equal(t, errors.CodeConflict, resp.Error.Code)
+		assert.Equal(t, "Escrow has already been released", resp.Error.Message)
+	})
+
+	t.Run("Internal errors are localized", func(t *testing.T) {
+		w, resp := get("/panic", "fr")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, errors.CodeInternal, resp.Error.Code)
+		assert.Equal(t, "Une erreur interne du serveur s'est produite", resp.Error.Message)
+	})
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/models"
+	"gorm.io/gorm"
+)
+
+// RequirePermission allows the request only when the caller's role has been
+// granted permission in role_permissions. Unlike with RequireRole, who may
+// pass is data rather than code, so a role such as compliance can be given
+// part of what admins may do.
+func RequirePermission(db *gorm.DB, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		if role == "" {
+			RespondError(c, http.StatusUnauthorized, apperrors.CodeUnauthorized, "User role not found in context")
+			return
+		}
+
+		granted, err := HasPermission(db, role, permission)
+		if err != nil {
+			RespondError(c, http.StatusInternalServerError, apperrors.CodeInternal, "Failed to check permissions")
+			return
+		}
+		if !granted {
+			RespondError(c, http.StatusForbidden, apperrors.CodeForbidden, Localize(c, "error.insufficient_permissions"))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// HasPermission reports whether role has been granted permission.
+func HasPermission(db *gorm.DB, role, permission string) (bool, error) {
+	var count int64
+	err := db.Model(&models.RolePermission{}).
+		Joins("JOIN permissions ON permissions.id = role_permissions.permission_id").
+		Where("role_permissions.role = ? AND permissions.name = ?", role, permission).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Permission{}, &models.RolePermission{}))
+
+	complete := models.Permission{Name: models.PermissionRemittanceComplete}
+	require.NoError(t, db.Create(&complete).Error)
+	for _, role := range []string{models.RoleAdmin, models.RoleCompliance} {
+		require.NoError(t, db.Create(&models.RolePermission{Role: role, PermissionID: complete.ID}).Error)
+	}
+
+	request := func(role, permission string) int {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			if role != "" {
+				c.Set("role", role)
+			}
+			c.Next()
+		})
+		router.POST("/remittances/:id/complete", RequirePermission(db, permission), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		req, _ := http.NewRequest(http.MethodPost, "/remittances/1/complete", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request(models.RoleAdmin, models.PermissionRemittanceComplete))
+	assert.Equal(t, http.StatusOK, request(models.RoleCompliance, models.PermissionRemittanceComplete), "compliance officers hold the granted subset")
+	assert.Equal(t, http.StatusForbidden, request(models.RoleUser, models.PermissionRemittanceComplete))
+	assert.Equal(t, http.StatusForbidden, request(models.RoleCompliance, "remittance:force_complete"), "ungranted permissions are refused")
+	assert.Equal(t, http.StatusUnauthorized, request("", models.PermissionRemittanceComplete))
+}
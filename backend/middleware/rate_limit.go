@@ -9,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/errors"
 )
 
 // RateLimiter stores rate limit information per user
@@ -178,8 +179,7 @@ func RateLimitMiddleware(cfg *config.Config) gin.HandlerFunc {
 		if !allowed {
 			retryAfter := int(time.Until(resetAt).Seconds())
 			c.Header("Retry-After", strconv.Itoa(retryAfter))
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"error":       "rate limit exceeded",
+			RespondErrorWithDetails(c, http.StatusTooManyRequests, errors.CodeRateLimited, "rate limit exceeded", gin.H{
 				"retry_after": retryAfter,
 				"reset_at":    resetAt.Format(time.RFC3339),
 			})
@@ -209,7 +209,7 @@ func AdminResetRateLimit(cfg *config.Config) gin.HandlerFunc {
 		endpoint := c.Query("endpoint")
 		
 		if userID == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+			RespondError(c, http.StatusBadRequest, errors.CodeValidation, "user_id is required")
 			return
 		}
 
@@ -0,0 +1,216 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/yourusername/gpay-remit/errors"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/utils"
+	"gorm.io/gorm"
+)
+
+// TwoFactorHeader carries the TOTP code on requests that need one.
+const TwoFactorHeader = "X-2FA-Code"
+
+// TwoFactorThresholds is the largest amount that may be sent without a TOTP
+// code, per upper-case currency code. Currencies not in ByCurrency use
+// Default. A threshold of zero or below disables the check for its currency.
+type TwoFactorThresholds struct {
+	Default    float64
+	ByCurrency map[string]float64
+}
+
+// For returns the threshold for currency.
+func (t TwoFactorThresholds) For(currency string) float64 {
+	if threshold, ok := t.ByCurrency[strings.ToUpper(currency)]; ok {
+		return threshold
+	}
+	return t.Default
+}
+
+// enabled reports whether any currency has a threshold.
+func (t TwoFactorThresholds) enabled() bool {
+	if t.Default > 0 {
+		return true
+	}
+	for _, threshold := range t.ByCurrency {
+		if threshold > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// TwoFactorAmounts returns what a request sends, as totals keyed by
+// upper-case currency code, given its raw body. A body it cannot make sense
+// of yields no amounts and is left for the handler to reject; an error fails
+// the request.
+type TwoFactorAmounts func(c *gin.Context, body []byte) (map[string]float64, error)
+
+// RemittanceAmount reads a single remittance's amount from the body's
+// "amount" field and its currency from currencyField.
+func RemittanceAmount(currencyField string) TwoFactorAmounts {
+	return func(c *gin.Context, body []byte) (map[string]float64, error) {
+		var payload map[string]json.RawMessage
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, nil
+		}
+		var amount float64
+		var currency string
+		if err := json.Unmarshal(payload["amount"], &amount); err != nil {
+			return nil, nil
+		}
+		json.Unmarshal(payload[currencyField], &currency)
+		return map[string]float64{strings.ToUpper(currency): amount}, nil
+	}
+}
+
+// BatchAmounts totals a batch's items per currency, so a large payment
+// split into small items still needs a code.
+func BatchAmounts(c *gin.Context, body []byte) (map[string]float64, error) {
+	var payload struct {
+		Items []struct {
+			Amount    float64 `json:"amount"`
+			AssetCode string  `json:"asset_code"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, nil
+	}
+	totals := map[string]float64{}
+	for _, item := range payload.Items {
+		totals[strings.ToUpper(item.AssetCode)] += item.Amount
+	}
+	return totals, nil
+}
+
+// InvoiceAmount reads the amount due on the invoice named by the id path
+// parameter.
+func InvoiceAmount(db *gorm.DB) TwoFactorAmounts {
+	return func(c *gin.Context, body []byte) (map[string]float64, error) {
+		var invoice models.Invoice
+		if err := db.Select("amount", "currency").First(&invoice, c.Param("id")).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return map[string]float64{strings.ToUpper(invoice.Currency): invoice.Amount}, nil
+	}
+}
+
+// RequireTwoFactorAbove requires a TOTP code in the X-2FA-Code header on
+// requests that send more than the threshold for a currency, as read by
+// amounts. The user must have enabled two-factor authentication, and each
+// code is accepted only once.
+func RequireTwoFactorAbove(db *gorm.DB, thresholds TwoFactorThresholds, amounts TwoFactorAmounts) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !thresholds.enabled() {
+			c.Next()
+			return
+		}
+
+		var body []byte
+		if c.Request.Body != nil {
+			var err error
+			body, err = io.ReadAll(c.Request.Body)
+			if err != nil {
+				RespondError(c, http.StatusBadRequest, apperrors.CodeValidation, "Failed to read request body")
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		totals, err := amounts(c, body)
+		if err != nil {
+			RespondError(c, http.StatusInternalServerError, apperrors.CodeInternal, "Failed to read remittance amount")
+			return
+		}
+		currencies := make([]string, 0, len(totals))
+		for currency := range totals {
+			currencies = append(currencies, currency)
+		}
+		sort.Strings(currencies)
+		var above string
+		threshold := 0.0
+		for _, currency := range currencies {
+			if limit := thresholds.For(currency); limit > 0 && totals[currency] > limit {
+				above, threshold = currency, limit
+				break
+			}
+		}
+		if threshold == 0 {
+			c.Next()
+			return
+		}
+		limit := strings.TrimSpace(fmt.Sprintf("%.2f %s", threshold, above))
+
+		var user models.User
+		if err := db.First(&user, c.GetUint("userID")).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				RespondError(c, http.StatusUnauthorized, apperrors.CodeUnauthorized, Localize(c, "error.user_not_found"))
+			} else {
+				RespondError(c, http.StatusInternalServerError, apperrors.CodeInternal, "Failed to load user")
+			}
+			return
+		}
+		if !user.TOTPEnabled {
+			RespondError(c, http.StatusForbidden, apperrors.CodeTwoFactorRequired,
+				fmt.Sprintf("Two-factor authentication must be enabled for remittances above %s", limit))
+			return
+		}
+		code := c.GetHeader(TwoFactorHeader)
+		if code == "" {
+			RespondError(c, http.StatusForbidden, apperrors.CodeTwoFactorRequired,
+				fmt.Sprintf("A two-factor code in the %s header is required for remittances above %s", TwoFactorHeader, limit))
+			return
+		}
+
+		ok, err := ConsumeTOTPCode(db, &user, code, time.Now())
+		if err != nil {
+			RespondError(c, http.StatusInternalServerError, apperrors.CodeInternal, "Failed to verify two-factor code")
+			return
+		}
+		if !ok {
+			RespondError(c, http.StatusForbidden, apperrors.CodeInvalidTwoFactorCode, "Invalid or already used two-factor code")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ConsumeTOTPCode checks code against the user's TOTP secret and, when it
+// matches, records its time step so that neither it nor an earlier code can
+// be used again. The update is conditional on the recorded step, so two
+// requests racing with the same code cannot both succeed. It reports whether
+// the code was accepted.
+func ConsumeTOTPCode(db *gorm.DB, user *models.User, code string, now time.Time) (bool, error) {
+	if user.TOTPSecret == "" {
+		return false, nil
+	}
+	step, ok := utils.ValidateTOTP(user.TOTPSecret.String(), code, now)
+	if !ok || step <= user.TOTPLastStep {
+		return false, nil
+	}
+
+	result := db.Model(&models.User{}).
+		Where("id = ? AND totp_last_step < ?", user.ID, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	user.TOTPLastStep = step
+	return true, nil
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/utils"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRequireTwoFactorAbove(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.User{}))
+
+	secret, err := utils.GenerateTOTPSecret()
+	require.NoError(t, err)
+	enrolled := models.User{Email: "2fa@example.com", Name: "Enrolled", StellarAddress: "G2FA", PasswordHash: "x", IsActive: true, TOTPSecret: models.EncryptedString(secret), TOTPEnabled: true}
+	plain := models.User{Email: "plain@example.com", Name: "Plain", StellarAddress: "GPLAIN", PasswordHash: "x", IsActive: true}
+	require.NoError(t, db.Create(&enrolled).Error)
+	require.NoError(t, db.Create(&plain).Error)
+
+	request := func(userID uint, body, code string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", userID)
+			c.Next()
+		})
+		thresholds := TwoFactorThresholds{Default: 1000, ByCurrency: map[string]float64{"NGN": 1000000, "XLM": 0}}
+		router.POST("/remittances/create", RequireTwoFactorAbove(db, thresholds, RemittanceAmount("asset_code")), func(c *gin.Context) {
+			raw, _ := io.ReadAll(c.Request.Body)
+			c.String(http.StatusCreated, string(raw))
+		})
+
+		req, _ := http.NewRequest(http.MethodPost, "/remittances/create", strings.NewReader(body))
+		if code != "" {
+			req.Header.Set(TwoFactorHeader, code)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Amounts up to the threshold need no code", func(t *testing.T) {
+		w := request(plain.ID, `{"amount":1000}`, "")
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, `{"amount":1000}`, w.Body.String())
+	})
+
+	t.Run("Thresholds are per currency", func(t *testing.T) {
+		w := request(plain.ID, `{"amount":50000,"asset_code":"ngn"}`, "")
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w = request(plain.ID, `{"amount":1500000,"asset_code":"NGN"}`, "")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "1000000.00 NGN")
+
+		w = request(plain.ID, `{"amount":50000,"asset_code":"XLM"}`, "")
+		assert.Equal(t, http.StatusCreated, w.Code, "a zero threshold disables the check for its currency")
+	})
+
+	t.Run("Users without two-factor are refused above the threshold", func(t *testing.T) {
+		w := request(plain.ID, `{"amount":1500}`, "123456")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "TwoFactorRequired")
+	})
+
+	t.Run("A missing code is refused", func(t *testing.T) {
+		w := request(enrolled.ID, `{"amount":1500}`, "")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "TwoFactorRequired")
+	})
+
+	t.Run("A valid code passes once", func(t *testing.T) {
+		code, err := utils.TOTPCode(secret, time.Now())
+		require.NoError(t, err)
+
+		w := request(enrolled.ID, `{"amount":1500}`, code)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, `{"amount":1500}`, w.Body.String(), "the handler still reads the body")
+
+		w = request(enrolled.ID, `{"amount":1500}`, code)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "InvalidTwoFactorCode")
+	})
+
+	t.Run("A wrong code is refused", func(t *testing.T) {
+		code, err := utils.TOTPCode(secret, time.Now().Add(-10*utils.TOTPPeriod))
+		require.NoError(t, err)
+
+		w := request(enrolled.ID, `{"amount":1500}`, code)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "InvalidTwoFactorCode")
+	})
+}
+
+func TestTwoFactorAmounts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Invoice{}))
+	invoice := models.Invoice{InvoiceNo: "INV-2FA-1", IssuerID: 1, RecipientID: 2, Amount: 2500, Currency: "usdc", Status: "unpaid"}
+	require.NoError(t, db.Create(&invoice).Error)
+
+	read := func(amounts TwoFactorAmounts, path, body string) map[string]float64 {
+		var got map[string]float64
+		router := gin.New()
+		router.POST("/invoices/:id/pay", func(c *gin.Context) {
+			got, err = amounts(c, []byte(body))
+			require.NoError(t, err)
+		})
+		req, _ := http.NewRequest(http.MethodPost, path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+		return got
+	}
+
+	t.Run("Send remittance", func(t *testing.T) {
+		got := read(RemittanceAmount("currency"), "/invoices/1/pay", `{"amount":12.5,"currency":"usd"}`)
+		assert.Equal(t, map[string]float64{"USD": 12.5}, got)
+		assert.Nil(t, read(RemittanceAmount("currency"), "/invoices/1/pay", `not json`))
+	})
+
+	t.Run("Batch items are totalled per currency", func(t *testing.T) {
+		body := `{"items":[{"amount":600,"asset_code":"XLM"},{"amount":600,"asset_code":"xlm"},{"amount":5,"asset_code":"USDC"}]}`
+		assert.Equal(t, map[string]float64{"XLM": 1200, "USDC": 5}, read(BatchAmounts, "/invoices/1/pay", body))
+	})
+
+	t.Run("Invoice amount comes from the invoice", func(t *testing.T) {
+		got := read(InvoiceAmount(db), fmt.Sprintf("/invoices/%d/pay", invoice.ID), "")
+		assert.Equal(t, map[string]float64{"USDC": 2500}, got)
+		assert.Nil(t, read(InvoiceAmount(db), "/invoices/999/pay", ""))
+	})
+}
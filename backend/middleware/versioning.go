@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gpay-remit/errors"
 )
 
 const (
@@ -30,11 +31,9 @@ func VersionMiddleware() gin.HandlerFunc {
 		}
 
 		if !isValidVersion(requestedVersion) {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Invalid API version",
+			RespondErrorWithDetails(c, http.StatusBadRequest, errors.CodeUnsupportedVersion, "Invalid API version", gin.H{
 				"supported_versions": []string{"v1", "v2"},
 			})
-			c.Abort()
 			return
 		}
 
@@ -118,12 +117,10 @@ func RequireVersion(allowedVersions ...string) gin.HandlerFunc {
 		}
 
 		if !allowed {
-			c.JSON(http.StatusNotAcceptable, gin.H{
-				"error": "This endpoint is not available in the requested API version",
-				"current_version": currentVersion,
+			RespondErrorWithDetails(c, http.StatusNotAcceptable, errors.CodeUnsupportedVersion, "This endpoint is not available in the requested API version", gin.H{
+				"current_version":   currentVersion,
 				"required_versions": allowedVersions,
 			})
-			c.Abort()
 			return
 		}
 
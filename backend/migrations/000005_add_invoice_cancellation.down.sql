@@ -0,0 +1,3 @@
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS cancellation_reason,
+    DROP COLUMN IF EXISTS cancelled_at;
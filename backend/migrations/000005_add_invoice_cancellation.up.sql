@@ -0,0 +1,3 @@
+ALTER TABLE invoices
+    ADD COLUMN IF NOT EXISTS cancellation_reason VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMPTZ;
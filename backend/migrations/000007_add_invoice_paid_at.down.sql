@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_invoices_status_due_date;
+ALTER TABLE invoices DROP COLUMN IF EXISTS paid_at;
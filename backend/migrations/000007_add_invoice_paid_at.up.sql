@@ -0,0 +1,2 @@
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS paid_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_invoices_status_due_date ON invoices(status, due_date);
@@ -0,0 +1,2 @@
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS batch_id VARCHAR(36);
+CREATE INDEX IF NOT EXISTS idx_payments_batch_id ON payments(batch_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_audit_logs_entity_type;
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS entity_id;
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS entity_type;
@@ -0,0 +1,2 @@
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS memo VARCHAR(64);
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS memo_type VARCHAR(10);
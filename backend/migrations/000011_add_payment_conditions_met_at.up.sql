@@ -0,0 +1 @@
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS conditions_met_at TIMESTAMPTZ;
@@ -0,0 +1 @@
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS settlement_account VARCHAR(56);
@@ -0,0 +1 @@
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS purged_at TIMESTAMPTZ;
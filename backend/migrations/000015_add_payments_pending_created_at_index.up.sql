@@ -0,0 +1,2 @@
+-- Serves the pending-expiry scan without touching non-pending rows.
+CREATE INDEX IF NOT EXISTS idx_payments_pending_created_at ON payments (created_at) WHERE status = 'pending';
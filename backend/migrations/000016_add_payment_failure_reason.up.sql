@@ -0,0 +1 @@
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS failure_reason VARCHAR(64);
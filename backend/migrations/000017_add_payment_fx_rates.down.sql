@@ -0,0 +1,2 @@
+ALTER TABLE payments DROP COLUMN IF EXISTS executed_rate;
+ALTER TABLE payments DROP COLUMN IF EXISTS quoted_rate;
@@ -0,0 +1,2 @@
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS quoted_rate DECIMAL DEFAULT 0;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS executed_rate DECIMAL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE payments DROP COLUMN IF EXISTS release_tx_hash;
+ALTER TABLE payments DROP COLUMN IF EXISTS release_tx_envelope;
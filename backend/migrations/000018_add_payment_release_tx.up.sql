@@ -0,0 +1,2 @@
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS release_tx_envelope TEXT;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS release_tx_hash VARCHAR(64);
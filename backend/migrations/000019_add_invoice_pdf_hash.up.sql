@@ -0,0 +1 @@
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS pdf_hash VARCHAR(64);
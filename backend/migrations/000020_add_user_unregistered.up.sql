@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS unregistered BOOLEAN DEFAULT FALSE;
+CREATE INDEX IF NOT EXISTS idx_users_unregistered ON users(unregistered);
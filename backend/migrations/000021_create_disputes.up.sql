@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS disputes (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    payment_id BIGINT NOT NULL,
+    opened_by BIGINT NOT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    previous_status VARCHAR(20) NOT NULL,
+    resolution VARCHAR(20),
+    resolution_note TEXT,
+    resolved_by BIGINT,
+    resolved_at TIMESTAMPTZ,
+    CONSTRAINT fk_payment FOREIGN KEY (payment_id) REFERENCES payments(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_disputes_payment_id ON disputes(payment_id);
+CREATE INDEX IF NOT EXISTS idx_disputes_status ON disputes(status);
+
+-- A remittance has at most one open dispute.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_disputes_open_payment ON disputes(payment_id) WHERE status = 'open';
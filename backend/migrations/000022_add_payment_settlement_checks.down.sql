@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_payments_next_settlement_check_at;
+ALTER TABLE payments DROP COLUMN IF EXISTS next_settlement_check_at;
+ALTER TABLE payments DROP COLUMN IF EXISTS settlement_checks;
+ALTER TABLE payments DROP COLUMN IF EXISTS result_codes;
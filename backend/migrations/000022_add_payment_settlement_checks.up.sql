@@ -0,0 +1,4 @@
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS result_codes VARCHAR(255);
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS settlement_checks INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS next_settlement_check_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_payments_next_settlement_check_at ON payments(next_settlement_check_at);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_audit_logs_entity;
+
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS new_status;
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS old_status;
@@ -0,0 +1,4 @@
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS old_status VARCHAR(32);
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS new_status VARCHAR(32);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity ON audit_logs(entity_type, entity_id);
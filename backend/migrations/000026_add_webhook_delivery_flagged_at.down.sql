@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_flagged_at;
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS flagged_at;
@@ -0,0 +1,2 @@
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS flagged_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_flagged_at ON webhook_deliveries(flagged_at);
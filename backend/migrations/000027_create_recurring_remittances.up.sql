@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS recurring_remittances (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    sender_id INTEGER NOT NULL,
+    recipient_account VARCHAR(56) NOT NULL,
+    amount DECIMAL NOT NULL,
+    asset_code VARCHAR(12) NOT NULL,
+    asset_issuer VARCHAR(56),
+    run_interval VARCHAR(10) NOT NULL,
+    start_at TIMESTAMP NOT NULL,
+    occurrence INTEGER NOT NULL DEFAULT 0,
+    next_run_at TIMESTAMP NOT NULL,
+    last_run_at TIMESTAMP,
+    last_payment_id INTEGER,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    deactivated_reason VARCHAR(255),
+    CONSTRAINT fk_sender FOREIGN KEY (sender_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_recurring_remittances_sender_id ON recurring_remittances(sender_id);
+CREATE INDEX idx_recurring_remittances_deleted_at ON recurring_remittances(deleted_at);
+-- The scheduler scans active schedules by next run.
+CREATE INDEX idx_recurring_remittances_due ON recurring_remittances(next_run_at) WHERE active = TRUE AND deleted_at IS NULL;
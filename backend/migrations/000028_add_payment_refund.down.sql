@@ -0,0 +1,3 @@
+ALTER TABLE payments DROP COLUMN IF EXISTS refund_tx_hash;
+ALTER TABLE payments DROP COLUMN IF EXISTS refund_amount;
+ALTER TABLE payments DROP COLUMN IF EXISTS refund_tx_envelope;
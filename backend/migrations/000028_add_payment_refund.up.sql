@@ -0,0 +1,3 @@
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS refund_tx_envelope TEXT;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS refund_amount DECIMAL DEFAULT 0;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS refund_tx_hash VARCHAR(64);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_payments_claimable_balance_id;
+ALTER TABLE payments DROP COLUMN IF EXISTS reclaim_after;
+ALTER TABLE payments DROP COLUMN IF EXISTS claimable_balance_id;
@@ -0,0 +1,3 @@
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS claimable_balance_id VARCHAR(72);
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS reclaim_after TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_payments_claimable_balance_id ON payments(claimable_balance_id);
@@ -0,0 +1 @@
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS corridor VARCHAR(16);
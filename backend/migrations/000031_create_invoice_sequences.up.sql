@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+    name VARCHAR(50) PRIMARY KEY,
+    value BIGINT NOT NULL DEFAULT 0
+);
+
+INSERT INTO invoice_sequences (name, value) VALUES ('invoice', 0) ON CONFLICT (name) DO NOTHING;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS permissions (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(255)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_permissions_name ON permissions(name);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR(20) NOT NULL,
+    permission_id BIGINT NOT NULL,
+    PRIMARY KEY (role, permission_id),
+    CONSTRAINT fk_permission FOREIGN KEY (permission_id) REFERENCES permissions(id) ON DELETE CASCADE
+);
+
+INSERT INTO permissions (name, description) VALUES
+    ('remittance:complete', 'Mark a submitted remittance as completed')
+ON CONFLICT (name) DO NOTHING;
+
+-- Admins keep what RequireRole("admin") gave them. Superadmins, who outrank
+-- them, and compliance officers are granted the same.
+INSERT INTO role_permissions (role, permission_id)
+SELECT r.role, p.id
+FROM permissions p
+CROSS JOIN (VALUES ('admin'), ('superadmin'), ('compliance')) AS r(role)
+WHERE p.name = 'remittance:complete'
+ON CONFLICT DO NOTHING;
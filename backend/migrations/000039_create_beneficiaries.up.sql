@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS beneficiaries (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    user_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    stellar_address VARCHAR(56) NOT NULL,
+    country VARCHAR(2),
+    relationship VARCHAR(20),
+    bank_name VARCHAR(255),
+    bank_account_number TEXT,
+    bank_code VARCHAR(34),
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_beneficiaries_user_id ON beneficiaries(user_id);
+CREATE INDEX IF NOT EXISTS idx_beneficiaries_deleted_at ON beneficiaries(deleted_at);
@@ -0,0 +1,8 @@
+CREATE OR REPLACE FUNCTION payments_search_vector_update() RETURNS trigger AS $$
+BEGIN
+  NEW.search_vector := to_tsvector('english', coalesce(NEW.notes,'') || ' ' || coalesce(NEW.currency,'') || ' ' || coalesce(NEW.status,'') || ' ' || coalesce(NEW.amount::text,''));
+  RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+UPDATE payments SET search_vector = to_tsvector('english', coalesce(notes,'') || ' ' || coalesce(currency,'') || ' ' || coalesce(status,'') || ' ' || coalesce(amount::text,''));
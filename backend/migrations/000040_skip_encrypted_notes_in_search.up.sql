@@ -0,0 +1,10 @@
+-- Encrypted notes are ciphertext; indexing them would only match base64 fragments.
+-- Index memo and both accounts too, so the remittance list can search them.
+CREATE OR REPLACE FUNCTION payments_search_vector_update() RETURNS trigger AS $$
+BEGIN
+  NEW.search_vector := to_tsvector('english', (CASE WHEN NEW.notes LIKE 'enc:v%' THEN '' ELSE coalesce(NEW.notes,'') END) || ' ' || coalesce(NEW.memo,'') || ' ' || coalesce(NEW.sender_account,'') || ' ' || coalesce(NEW.recipient_account,'') || ' ' || coalesce(NEW.currency,'') || ' ' || coalesce(NEW.status,'') || ' ' || coalesce(NEW.amount::text,''));
+  RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+UPDATE payments SET search_vector = to_tsvector('english', (CASE WHEN notes LIKE 'enc:v%' THEN '' ELSE coalesce(notes,'') END) || ' ' || coalesce(memo,'') || ' ' || coalesce(sender_account,'') || ' ' || coalesce(recipient_account,'') || ' ' || coalesce(currency,'') || ' ' || coalesce(status,'') || ' ' || coalesce(amount::text,''));
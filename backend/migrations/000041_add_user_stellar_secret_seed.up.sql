@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS stellar_secret_seed TEXT;
@@ -0,0 +1,6 @@
+ALTER TABLE invoices DROP COLUMN IF EXISTS recipient_stellar_address;
+ALTER TABLE invoices DROP COLUMN IF EXISTS recipient_email;
+ALTER TABLE invoices DROP COLUMN IF EXISTS recipient_name;
+ALTER TABLE invoices DROP COLUMN IF EXISTS issuer_stellar_address;
+ALTER TABLE invoices DROP COLUMN IF EXISTS issuer_email;
+ALTER TABLE invoices DROP COLUMN IF EXISTS issuer_name;
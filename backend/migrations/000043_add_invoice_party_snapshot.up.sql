@@ -0,0 +1,16 @@
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS issuer_name VARCHAR(255);
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS issuer_email VARCHAR(255);
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS issuer_stellar_address VARCHAR(56);
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS recipient_name VARCHAR(255);
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS recipient_email VARCHAR(255);
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS recipient_stellar_address VARCHAR(56);
+
+-- Existing invoices take their parties' current details.
+UPDATE invoices SET issuer_name = users.name, issuer_email = users.email, issuer_stellar_address = users.stellar_address
+FROM users WHERE users.id = invoices.issuer_id;
+UPDATE invoices SET recipient_name = users.name, recipient_email = users.email, recipient_stellar_address = users.stellar_address
+FROM users WHERE users.id = invoices.recipient_id;
+
+-- Hashes recorded on download covered the invoice status and live profiles,
+-- so they cannot be reproduced from the snapshot.
+UPDATE invoices SET pdf_hash = NULL;
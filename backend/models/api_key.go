@@ -0,0 +1,86 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// API key scopes. A scope "<resource>:read" opens the GET routes under that
+// resource and "<resource>:write" the others.
+const (
+	ScopeRemittancesRead  = "remittances:read"
+	ScopeRemittancesWrite = "remittances:write"
+)
+
+// APIKeyScopes lists the scopes an API key may be granted.
+var APIKeyScopes = []string{ScopeRemittancesRead, ScopeRemittancesWrite}
+
+// APIKeyPrefix starts every API key, so a leaked key is easy to recognise.
+const APIKeyPrefix = "gpk_"
+
+// APIKey lets a user's integrations call the API without a JWT. Only a hash
+// of the key is stored; the key itself is shown once, when it is created.
+type APIKey struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserID    uint      `gorm:"index;not null" json:"user_id"`
+	Name      string    `gorm:"size:100;not null" json:"name"`
+	// Prefix is the start of the key, shown so its owner can tell keys apart.
+	Prefix string `gorm:"size:16;not null" json:"prefix"`
+	// KeyHash is the hex SHA-256 of the key.
+	KeyHash string `gorm:"uniqueIndex;size:64;not null" json:"-"`
+	// Scopes is a comma-separated list of the scopes the key grants.
+	Scopes     string     `gorm:"size:255;not null" json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+// TableName overrides the table name.
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// HashAPIKey returns the hash an API key is stored and looked up by. Keys
+// are long and random, so a fast hash is enough.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsValidAPIKeyScope reports whether scope is one an API key may be granted.
+func IsValidAPIKeyScope(scope string) bool {
+	for _, s := range APIKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ScopeList returns the key's scopes.
+func (k *APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+// HasScope reports whether the key grants scope.
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.ScopeList() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Active reports whether the key can be used at now: it has not been revoked
+// and has not expired.
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
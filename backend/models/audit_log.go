@@ -14,6 +14,20 @@ type AuditLog struct {
 	OldValue  string  `gorm:"type:jsonb" json:"old_value,omitempty"`
 	NewValue  string  `gorm:"type:jsonb" json:"new_value,omitempty"`
 	IPAddress string  `gorm:"size:64;not null" json:"ip_address"`
+
+	// EntityType and EntityID identify the record acted on, derived from the route
+	// (e.g. "remittance" and "42" for POST /api/v1/remittances/42/complete).
+	EntityType string `gorm:"size:50;index" json:"entity_type,omitempty"`
+	EntityID   string `gorm:"size:64" json:"entity_id,omitempty"`
+
+	// OldStatus and NewStatus are the entity's status before and after the
+	// action, e.g. "processing" -> "completed", when it has one.
+	OldStatus string `gorm:"size:32" json:"old_status,omitempty"`
+	NewStatus string `gorm:"size:32" json:"new_status,omitempty"`
+
+	// Reason is the justification an admin gave for an exceptional action,
+	// such as force-completing a remittance.
+	Reason string `gorm:"type:text" json:"reason,omitempty"`
 }
 
 func (AuditLog) TableName() string {
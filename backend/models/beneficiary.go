@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Relationships a beneficiary can have to the user who saved them.
+const (
+	RelationshipFamily   = "family"
+	RelationshipFriend   = "friend"
+	RelationshipBusiness = "business"
+	RelationshipSelf     = "self"
+	RelationshipOther    = "other"
+)
+
+// Beneficiary is an entry in a user's address book: someone they send to
+// often, with the Stellar address remittances go to and, for payouts to a
+// bank, the account details.
+type Beneficiary struct {
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+	UserID         uint           `gorm:"index;not null" json:"-"`
+	Name           string         `gorm:"size:255;not null" json:"name"`
+	StellarAddress string         `gorm:"size:56;not null" json:"stellar_address"`
+	Country        string         `gorm:"size:2" json:"country"`
+	Relationship   string         `gorm:"size:20" json:"relationship,omitempty"`
+	BankName       string         `gorm:"size:255" json:"bank_name,omitempty"`
+	// BankAccountNumber is encrypted at rest and only shown masked.
+	BankAccountNumber EncryptedString `gorm:"type:text" json:"-"`
+	// BankCode identifies the branch: a SWIFT/BIC, sort, or routing code.
+	BankCode string `gorm:"size:34" json:"bank_code,omitempty"`
+}
+
+// TableName overrides the table name
+func (Beneficiary) TableName() string {
+	return "beneficiaries"
+}
+
+// MaskedAccountNumber returns the bank account number with all but its last
+// four characters hidden, or "" when there is none. Numbers too short to
+// keep any digits are hidden entirely.
+func (b *Beneficiary) MaskedAccountNumber() string {
+	number := string(b.BankAccountNumber)
+	switch {
+	case number == "":
+		return ""
+	case len(number) <= 4:
+		return "****"
+	}
+	return "****" + number[len(number)-4:]
+}
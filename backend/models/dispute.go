@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Dispute statuses.
+const (
+	DisputeStatusOpen     = "open"
+	DisputeStatusResolved = "resolved"
+)
+
+// Dispute resolutions. Release returns the remittance to the status it had
+// when the dispute was opened; refund leaves it disputed so an admin can
+// refund its escrow to the sender.
+const (
+	DisputeResolutionRelease = "release"
+	DisputeResolutionRefund  = "refund"
+)
+
+// Dispute is a challenge to a remittance raised by one of its parties. While
+// it is open the remittance is held in the disputed status.
+type Dispute struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	PaymentID uint      `gorm:"index;not null" json:"remittance_id"`
+	OpenedBy  uint      `gorm:"not null" json:"opened_by"`
+	Reason    string    `gorm:"type:text;not null" json:"reason"`
+	Status    string    `gorm:"index;size:20;not null" json:"status"`
+	// PreviousStatus is the remittance's status when the dispute was opened.
+	PreviousStatus string     `gorm:"size:20;not null" json:"previous_status"`
+	Resolution     string     `gorm:"size:20" json:"resolution,omitempty"`
+	ResolutionNote string     `gorm:"type:text" json:"resolution_note,omitempty"`
+	ResolvedBy     *uint      `json:"resolved_by,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+}
+
+// TableName overrides the table name
+func (Dispute) TableName() string {
+	return "disputes"
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// EmailVerificationToken records an email verification token sent to a user.
+// Only a hash of the token is stored, and UsedAt is set when it is redeemed.
+type EmailVerificationToken struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uint      `gorm:"index;not null" json:"user_id"`
+	// TokenHash is the hex SHA-256 of the token.
+	TokenHash string     `gorm:"uniqueIndex;size:64;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+}
+
+// TableName overrides the table name.
+func (EmailVerificationToken) TableName() string {
+	return "email_verification_tokens"
+}
+
+// HashEmailVerificationToken returns the hash an email verification token is
+// stored and looked up by.
+func HashEmailVerificationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/yourusername/gpay-remit/encryption"
+	"gorm.io/gorm"
+)
+
+// fieldCipher encrypts EncryptedString columns; nil stores them as plaintext.
+var fieldCipher *encryption.Cipher
+
+// SetFieldCipher sets the cipher used for EncryptedString columns. It is
+// called once at startup, before the database is used.
+func SetFieldCipher(c *encryption.Cipher) {
+	fieldCipher = c
+}
+
+// FieldsEncrypted reports whether EncryptedString columns are stored
+// encrypted, in which case the database cannot match on their contents.
+func FieldsEncrypted() bool {
+	return fieldCipher != nil
+}
+
+// EncryptedString is a text column encrypted at rest when a field cipher is
+// set, and decrypted transparently when read. Values written before
+// encryption was enabled are read back as they are.
+type EncryptedString string
+
+func (s EncryptedString) String() string {
+	return string(s)
+}
+
+// Value encrypts the string for storage.
+func (s EncryptedString) Value() (driver.Value, error) {
+	if fieldCipher == nil || s == "" {
+		return string(s), nil
+	}
+	return fieldCipher.Encrypt(string(s))
+}
+
+// Scan decrypts a stored value. An encrypted value that cannot be decrypted
+// is an error rather than being returned as ciphertext.
+func (s *EncryptedString) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case nil:
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into EncryptedString", value)
+	}
+
+	if !encryption.IsEncrypted(raw) {
+		*s = EncryptedString(raw)
+		return nil
+	}
+	if fieldCipher == nil {
+		return fmt.Errorf("value is encrypted but no field encryption key is configured")
+	}
+	plaintext, err := fieldCipher.Decrypt(raw)
+	if err != nil {
+		return err
+	}
+	*s = EncryptedString(plaintext)
+	return nil
+}
+
+// encryptedPaymentColumns are the payment columns stored as EncryptedString.
+var encryptedPaymentColumns = []string{"notes", "conditions"}
+
+// ReencryptPayments rewrites payment notes and conditions that are stored as
+// plaintext, or under a key version other than the current one, so that
+// every value ends up encrypted with the current key. It works through the
+// table in batches of batchSize and returns how many values it rewrote. Each
+// value is only replaced if it is unchanged since it was read, so concurrent
+// writes are never overwritten. It does nothing without a field cipher.
+func ReencryptPayments(ctx context.Context, db *gorm.DB, batchSize int) (int, error) {
+	if fieldCipher == nil {
+		return 0, nil
+	}
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	type storedFields struct {
+		ID         uint
+		Notes      sql.NullString
+		Conditions sql.NullString
+	}
+	rewritten := 0
+	var lastID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return rewritten, err
+		}
+		// Scanning into plain strings reads the stored form, not the
+		// decrypted one.
+		var batch []storedFields
+		if err := db.WithContext(ctx).Unscoped().Model(&Payment{}).
+			Select("id", "notes", "conditions").
+			Where("id > ?", lastID).Order("id").Limit(batchSize).
+			Scan(&batch).Error; err != nil {
+			return rewritten, err
+		}
+		if len(batch) == 0 {
+			return rewritten, nil
+		}
+
+		for _, row := range batch {
+			for i, stored := range []sql.NullString{row.Notes, row.Conditions} {
+				if !stored.Valid || stored.String == "" || !fieldCipher.NeedsRotation(stored.String) {
+					continue
+				}
+				plaintext, err := fieldCipher.Decrypt(stored.String)
+				if err != nil {
+					return rewritten, fmt.Errorf("payment %d %s: %w", row.ID, encryptedPaymentColumns[i], err)
+				}
+				column := encryptedPaymentColumns[i]
+				result := db.WithContext(ctx).Unscoped().Model(&Payment{}).
+					Where("id = ? AND "+column+" = ?", row.ID, stored.String).
+					UpdateColumn(column, EncryptedString(plaintext))
+				if result.Error != nil {
+					return rewritten, result.Error
+				}
+				rewritten += int(result.RowsAffected)
+			}
+		}
+		lastID = batch[len(batch)-1].ID
+	}
+}
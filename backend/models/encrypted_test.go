@@ -0,0 +1,158 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/encryption"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func useTestCipher(t *testing.T, current int, keys map[int][]byte) {
+	t.Helper()
+	provider, err := encryption.NewStaticKeys(current, keys)
+	require.NoError(t, err)
+	SetFieldCipher(encryption.NewCipher(provider))
+	t.Cleanup(func() { SetFieldCipher(nil) })
+}
+
+func TestPaymentFieldsEncryptedAtRest(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Payment{}))
+
+	keyOne := bytes.Repeat([]byte{1}, encryption.KeySize)
+	useTestCipher(t, 1, map[int][]byte{1: keyOne})
+
+	payment := Payment{Amount: 10, Currency: "USD", Status: "pending", Notes: "rent for Jane", Conditions: `{"note":"private"}`}
+	require.NoError(t, db.Create(&payment).Error)
+	assert.Equal(t, EncryptedString("rent for Jane"), payment.Notes, "the in-memory value stays plaintext")
+
+	readRaw := func(id uint) (notes, conditions string) {
+		row := db.Raw("SELECT notes, conditions FROM payments WHERE id = ?", id).Row()
+		require.NoError(t, row.Scan(&notes, &conditions))
+		return notes, conditions
+	}
+
+	t.Run("Stored values are ciphertext", func(t *testing.T) {
+		notes, conditions := readRaw(payment.ID)
+		assert.True(t, encryption.IsEncrypted(notes))
+		assert.True(t, encryption.IsEncrypted(conditions))
+		assert.NotContains(t, notes, "Jane")
+		assert.NotContains(t, conditions, "private")
+	})
+
+	t.Run("Values round-trip on read", func(t *testing.T) {
+		var got Payment
+		require.NoError(t, db.First(&got, payment.ID).Error)
+		assert.Equal(t, "rent for Jane", got.Notes.String())
+		assert.Equal(t, `{"note":"private"}`, got.Conditions.String())
+	})
+
+	t.Run("Map updates are encrypted", func(t *testing.T) {
+		require.NoError(t, payment.UpdateVersioned(db, map[string]interface{}{"conditions": EncryptedString(`{"note":"updated"}`)}))
+		_, conditions := readRaw(payment.ID)
+		assert.True(t, encryption.IsEncrypted(conditions))
+
+		var got Payment
+		require.NoError(t, db.First(&got, payment.ID).Error)
+		assert.Equal(t, `{"note":"updated"}`, got.Conditions.String())
+	})
+
+	t.Run("Old key versions still decrypt after rotation", func(t *testing.T) {
+		useTestCipher(t, 2, map[int][]byte{1: keyOne, 2: bytes.Repeat([]byte{2}, encryption.KeySize)})
+
+		var got Payment
+		require.NoError(t, db.First(&got, payment.ID).Error)
+		assert.Equal(t, "rent for Jane", got.Notes.String())
+
+		rotated := Payment{Amount: 5, Currency: "USD", Status: "pending", Notes: "school fees"}
+		require.NoError(t, db.Create(&rotated).Error)
+		notes, _ := readRaw(rotated.ID)
+		version, _ := encryption.Version(notes)
+		assert.Equal(t, 2, version)
+	})
+
+	t.Run("Encrypted values fail to read without a key", func(t *testing.T) {
+		SetFieldCipher(nil)
+		var got Payment
+		assert.Error(t, db.First(&got, payment.ID).Error)
+	})
+}
+
+func TestLegacyPlaintextFields(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Payment{}))
+
+	// Written before encryption was enabled.
+	legacy := Payment{Amount: 10, Currency: "USD", Status: "completed", Notes: "legacy note", Conditions: `{"note":"legacy"}`}
+	require.NoError(t, db.Create(&legacy).Error)
+
+	keyOne := bytes.Repeat([]byte{1}, encryption.KeySize)
+	useTestCipher(t, 1, map[int][]byte{1: keyOne})
+	oldVersion := Payment{Amount: 20, Currency: "USD", Status: "pending", Notes: "first key"}
+	require.NoError(t, db.Create(&oldVersion).Error)
+	useTestCipher(t, 2, map[int][]byte{1: keyOne, 2: bytes.Repeat([]byte{2}, encryption.KeySize)})
+
+	readRaw := func(id uint) (notes, conditions string) {
+		row := db.Raw("SELECT notes, conditions FROM payments WHERE id = ?", id).Row()
+		require.NoError(t, row.Scan(&notes, &conditions))
+		return notes, conditions
+	}
+
+	t.Run("Plaintext is read as is with encryption enabled", func(t *testing.T) {
+		var got Payment
+		require.NoError(t, db.First(&got, legacy.ID).Error)
+		assert.Equal(t, "legacy note", got.Notes.String())
+		assert.Equal(t, `{"note":"legacy"}`, got.Conditions.String())
+	})
+
+	t.Run("Backfill encrypts plaintext and old key versions", func(t *testing.T) {
+		count, err := ReencryptPayments(context.Background(), db, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 3, count, "legacy notes and conditions, and the old-version note")
+
+		for _, id := range []uint{legacy.ID, oldVersion.ID} {
+			notes, _ := readRaw(id)
+			version, ok := encryption.Version(notes)
+			assert.True(t, ok)
+			assert.Equal(t, 2, version)
+		}
+		_, conditions := readRaw(oldVersion.ID)
+		assert.Empty(t, conditions, "empty values are left alone")
+
+		var got Payment
+		require.NoError(t, db.First(&got, legacy.ID).Error)
+		assert.Equal(t, "legacy note", got.Notes.String())
+		assert.Equal(t, `{"note":"legacy"}`, got.Conditions.String())
+
+		count, err = ReencryptPayments(context.Background(), db, 1)
+		require.NoError(t, err)
+		assert.Zero(t, count, "a second pass has nothing to do")
+	})
+
+	t.Run("Tampered values fail the backfill", func(t *testing.T) {
+		notes, _ := readRaw(legacy.ID)
+		tampered := notes[:len(notes)-4] + "AAA="
+		require.NoError(t, db.Exec("UPDATE payments SET notes = ? WHERE id = ?", tampered, legacy.ID).Error)
+		useTestCipher(t, 3, map[int][]byte{1: keyOne, 2: bytes.Repeat([]byte{2}, encryption.KeySize), 3: bytes.Repeat([]byte{3}, encryption.KeySize)})
+
+		_, err := ReencryptPayments(context.Background(), db, 10)
+		assert.Error(t, err)
+
+		var got Payment
+		assert.Error(t, db.First(&got, legacy.ID).Error)
+	})
+
+	t.Run("Nothing to do without a cipher", func(t *testing.T) {
+		SetFieldCipher(nil)
+		count, err := ReencryptPayments(context.Background(), db, 10)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+}
@@ -22,9 +22,37 @@ type Invoice struct {
 	Status      string         `gorm:"index;size:20;default:'unpaid'" json:"status"` // unpaid, paid, overdue, cancelled
 	Description string         `gorm:"type:text" json:"description"`
 	PdfURL      string         `gorm:"size:500" json:"pdf_url"`
+	PaidAt      *time.Time     `json:"paid_at,omitempty"`
+	// CancellationReason explains why a cancelled invoice was voided.
+	CancellationReason string     `gorm:"size:255" json:"cancellation_reason,omitempty"`
+	CancelledAt        *time.Time `json:"cancelled_at,omitempty"`
+	// PdfHash is the SHA-256 of the PDF issued with the invoice, recorded
+	// when the invoice is created.
+	PdfHash string `gorm:"size:64" json:"pdf_hash,omitempty"`
+	// The issuer's and recipient's details as printed on the PDF, copied from
+	// their profiles when the invoice is issued so later edits leave the
+	// document unchanged.
+	IssuerName              string `gorm:"size:255" json:"issuer_name,omitempty"`
+	IssuerEmail             string `gorm:"size:255" json:"issuer_email,omitempty"`
+	IssuerStellarAddress    string `gorm:"size:56" json:"issuer_stellar_address,omitempty"`
+	RecipientName           string `gorm:"size:255" json:"recipient_name,omitempty"`
+	RecipientEmail          string `gorm:"size:255" json:"recipient_email,omitempty"`
+	RecipientStellarAddress string `gorm:"size:56" json:"recipient_stellar_address,omitempty"`
 }
 
 // TableName overrides the table name
 func (Invoice) TableName() string {
 	return "invoices"
 }
+
+// InvoiceSequence is a named counter that invoice numbers are drawn from.
+// Value is the last number handed out.
+type InvoiceSequence struct {
+	Name  string `gorm:"primaryKey;size:50"`
+	Value int64  `gorm:"not null;default:0"`
+}
+
+// TableName overrides the table name
+func (InvoiceSequence) TableName() string {
+	return "invoice_sequences"
+}
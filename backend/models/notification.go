@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Notification channels a user can be reached on.
+const (
+	ChannelInApp   = "in_app"
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+)
+
+// NotificationChannels lists every channel, in dispatch order.
+var NotificationChannels = []string{ChannelInApp, ChannelEmail, ChannelWebhook}
+
+// Notification is an in-app message shown to a user.
+type Notification struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	UserID    uint           `gorm:"index;not null" json:"user_id"`
+	Event     string         `gorm:"size:100;not null" json:"event"`
+	Title     string         `gorm:"size:255;not null" json:"title"`
+	PaymentID *uint          `gorm:"index" json:"payment_id,omitempty"`
+	ReadAt    *time.Time     `json:"read_at"`
+}
+
+// NotificationPreference records whether a user wants an event delivered on a
+// channel. A missing row means the channel's default applies.
+type NotificationPreference struct {
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserID    uint      `gorm:"uniqueIndex:idx_notification_pref;not null" json:"-"`
+	Event     string    `gorm:"uniqueIndex:idx_notification_pref;size:100;not null" json:"event"`
+	Channel   string    `gorm:"uniqueIndex:idx_notification_pref;size:20;not null" json:"channel"`
+	Enabled   bool      `gorm:"not null" json:"enabled"`
+}
+
+// TableName overrides the table name
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// TableName overrides the table name
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
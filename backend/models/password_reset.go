@@ -0,0 +1,32 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// PasswordResetToken records a password reset token sent to a user. Only a
+// hash of the token is stored, and UsedAt is set when it is redeemed, so each
+// token resets the password at most once.
+type PasswordResetToken struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uint      `gorm:"index;not null" json:"user_id"`
+	// TokenHash is the hex SHA-256 of the token.
+	TokenHash string     `gorm:"uniqueIndex;size:64;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+}
+
+// TableName overrides the table name.
+func (PasswordResetToken) TableName() string {
+	return "password_reset_tokens"
+}
+
+// HashPasswordResetToken returns the hash a password reset token is stored
+// and looked up by.
+func HashPasswordResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
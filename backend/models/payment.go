@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -8,31 +9,79 @@ import (
 )
 
 type Payment struct {
-	ID              uint           `gorm:"primaryKey" json:"id"`
-	CreatedAt       time.Time      `json:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at"`
-	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
-	SenderID        uint           `gorm:"index;not null" json:"sender_id"`
-	SenderAccount   string         `gorm:"size:56" json:"sender_account"`
-	RecipientID     uint           `gorm:"index;not null" json:"recipient_id"`
-	RecipientAccount string        `gorm:"size:56" json:"recipient_account"`
-	Amount          float64        `gorm:"not null" json:"amount"`
-	Currency        string         `gorm:"size:10;not null" json:"currency"`
-	TargetCurrency  string         `gorm:"size:10" json:"target_currency"`
-	ConvertedAmount float64        `json:"converted_amount"`
-	Status          string         `gorm:"index;size:20;default:'pending'" json:"status"` // pending, processing, completed, failed
-	TxHash          string         `gorm:"index;size:255" json:"tx_hash"`
-	ContractID      string         `gorm:"size:255" json:"contract_id"`
-	EscrowID        string         `gorm:"index;size:255" json:"escrow_id"`
+	ID               uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+	SenderID         uint           `gorm:"index;not null" json:"sender_id"`
+	SenderAccount    string         `gorm:"size:56" json:"sender_account"`
+	RecipientID      uint           `gorm:"index;not null" json:"recipient_id"`
+	RecipientAccount string         `gorm:"size:56" json:"recipient_account"`
+	Amount           float64        `gorm:"not null" json:"amount"`
+	Currency         string         `gorm:"size:10;not null" json:"currency"`
+	TargetCurrency   string         `gorm:"size:10" json:"target_currency"`
+	ConvertedAmount  float64        `json:"converted_amount"`
+	Status           string         `gorm:"index;size:20;default:'pending'" json:"status"` // pending, processing, completed, failed, cancelled, expired, refunded
+	TxHash           string         `gorm:"index;size:255" json:"tx_hash"`
+	ContractID       string         `gorm:"size:255" json:"contract_id"`
+	EscrowID         string         `gorm:"index;size:255" json:"escrow_id"`
+	// ClaimableBalanceID is the claimable balance holding the funds when the
+	// remittance was sent as one. The recipient may claim it from the
+	// conditions' release time until ReclaimAfter, the sender from then on.
+	ClaimableBalanceID string     `gorm:"index;size:72" json:"claimable_balance_id,omitempty"`
+	ReclaimAfter       *time.Time `json:"reclaim_after,omitempty"`
+	// Memo is attached to the Stellar transaction for reconciliation; MemoType is text, id, or hash.
+	Memo     string `gorm:"size:64" json:"memo,omitempty"`
+	MemoType string `gorm:"size:10" json:"memo_type,omitempty"`
+	// BatchID links payments that were created together in a single batch transaction.
+	BatchID string `gorm:"index;size:36" json:"batch_id,omitempty"`
+	// SettlementAccount is the platform account that escrows the funds and pays the recipient out.
+	SettlementAccount string `gorm:"size:56" json:"settlement_account,omitempty"`
+	// TxEnvelope is the unsigned transaction envelope (base64 XDR) handed to the sender for signing.
+	TxEnvelope string `gorm:"type:text" json:"-"`
 	// Fee is the total of all fee components.
-	Fee           float64 `gorm:"default:0" json:"fee"`
-	PlatformFee   float64 `gorm:"default:0" json:"platform_fee"`
-	ForexFee      float64 `gorm:"default:0" json:"forex_fee"`
-	ComplianceFee float64 `gorm:"default:0" json:"compliance_fee"`
-	NetworkFee    float64 `gorm:"default:0" json:"network_fee"`
-	Conditions      string         `gorm:"type:text" json:"conditions"` // JSON blob of conditions
-	Notes           string         `gorm:"type:text" json:"notes"`
-	SearchVector    string         `gorm:"type:tsvector" json:"-"`
+	Fee           float64         `gorm:"default:0" json:"fee"`
+	PlatformFee   float64         `gorm:"default:0" json:"platform_fee"`
+	ForexFee      float64         `gorm:"default:0" json:"forex_fee"`
+	ComplianceFee float64         `gorm:"default:0" json:"compliance_fee"`
+	NetworkFee    float64         `gorm:"default:0" json:"network_fee"`
+	Conditions    EncryptedString `gorm:"type:text" json:"conditions"` // JSON blob of conditions, encrypted at rest
+	// Corridor is the country corridor the fees were priced for, e.g. "US-MX",
+	// or "default" when the pair has no corridor pricing.
+	Corridor string `gorm:"size:16" json:"corridor,omitempty"`
+	// ConditionsMetAt is set by the condition sweeper once every release condition holds.
+	ConditionsMetAt *time.Time `json:"conditions_met_at,omitempty"`
+	// FailureReason is the Horizon result code of this payment's failed operation, or the
+	// transaction result code when a different operation in the same transaction failed.
+	FailureReason string `gorm:"size:64" json:"failure_reason,omitempty"`
+	// QuotedRate is the TargetCurrency-per-Currency rate quoted at creation and
+	// ExecutedRate the rate the conversion completed at; both are zero for
+	// single-currency payments.
+	QuotedRate   float64 `json:"quoted_rate,omitempty"`
+	ExecutedRate float64 `json:"executed_rate,omitempty"`
+	// ReleaseTxEnvelope is the unsigned escrow release call awaiting the
+	// releasing party's signature; ReleaseTxHash is set once it is submitted.
+	ReleaseTxEnvelope string `gorm:"type:text" json:"-"`
+	ReleaseTxHash     string `gorm:"size:64" json:"release_tx_hash,omitempty"`
+	// RefundTxEnvelope is the unsigned escrow refund call awaiting the
+	// refunding party's signature, for RefundAmount; RefundTxHash is set once
+	// it is submitted.
+	RefundTxEnvelope string  `gorm:"type:text" json:"-"`
+	RefundAmount     float64 `gorm:"default:0" json:"refund_amount,omitempty"`
+	RefundTxHash     string  `gorm:"size:64" json:"refund_tx_hash,omitempty"`
+	// ResultCodes holds the Horizon result codes of a submitted transaction that
+	// failed on-ledger, e.g. "tx_failed: op_underfunded".
+	ResultCodes string `gorm:"size:255" json:"result_codes,omitempty"`
+	// SettlementChecks counts unresolved settlement lookups for a processing
+	// payment; NextSettlementCheckAt is when the poller looks again.
+	SettlementChecks      int        `gorm:"not null;default:0" json:"-"`
+	NextSettlementCheckAt *time.Time `gorm:"index" json:"-"`
+	// PurgedAt is set once the retention purge has erased this payment's personal data.
+	PurgedAt *time.Time      `json:"purged_at,omitempty"`
+	Notes    EncryptedString `gorm:"type:text" json:"notes"`
+	// Version is incremented by every versioned update; see UpdateVersioned.
+	Version      int    `gorm:"not null;default:1" json:"version"`
+	SearchVector string `gorm:"type:tsvector" json:"-"`
 }
 
 // TableName overrides the table name
@@ -40,6 +89,35 @@ func (Payment) TableName() string {
 	return "payments"
 }
 
+// BeforeCreate starts new payments at version 1 so the value held in memory
+// matches the stored row without a reload.
+func (p *Payment) BeforeCreate(tx *gorm.DB) error {
+	if p.Version == 0 {
+		p.Version = 1
+	}
+	return nil
+}
+
+// ErrConcurrentModification is returned by UpdateVersioned when the payment
+// was changed by someone else after it was read.
+var ErrConcurrentModification = errors.New("payment was modified concurrently")
+
+// UpdateVersioned applies updates only if the stored row still has the
+// version p was read at, and increments the version. A stale p yields
+// ErrConcurrentModification and leaves the row untouched.
+func (p *Payment) UpdateVersioned(db *gorm.DB, updates map[string]interface{}) error {
+	updates["version"] = gorm.Expr("version + 1")
+	result := db.Model(&Payment{}).Where("id = ? AND version = ?", p.ID, p.Version).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrConcurrentModification
+	}
+	p.Version++
+	return nil
+}
+
 // SearchableText returns a concatenated text used for searching/highlighting
 func (p *Payment) SearchableText() string {
 	return fmt.Sprintf("%v %s %s %s", p.Amount, p.Currency, p.Status, p.Notes)
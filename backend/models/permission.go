@@ -0,0 +1,31 @@
+package models
+
+// Permissions checked by RequirePermission. Each names an action a role can
+// be granted without the rest of the admin role.
+const (
+	PermissionRemittanceComplete = "remittance:complete"
+)
+
+// Permission is a named action that roles are granted through RolePermission.
+type Permission struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Name        string `gorm:"uniqueIndex;size:100;not null" json:"name"`
+	Description string `gorm:"size:255" json:"description"`
+}
+
+// TableName overrides the table name.
+func (Permission) TableName() string {
+	return "permissions"
+}
+
+// RolePermission grants a permission to every user holding Role.
+type RolePermission struct {
+	Role         string     `gorm:"primaryKey;size:20" json:"role"`
+	PermissionID uint       `gorm:"primaryKey" json:"permission_id"`
+	Permission   Permission `gorm:"constraint:OnDelete:CASCADE" json:"permission"`
+}
+
+// TableName overrides the table name.
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
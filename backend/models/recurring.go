@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RecurringRemittance is a schedule that creates a pending remittance from
+// SenderID to RecipientAccount every Interval. Occurrences are counted from
+// StartAt so monthly schedules keep their day of the month.
+type RecurringRemittance struct {
+	ID               uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+	SenderID         uint           `gorm:"index;not null" json:"sender_id"`
+	RecipientAccount string         `gorm:"size:56;not null" json:"recipient_account"`
+	Amount           float64        `gorm:"not null" json:"amount"`
+	AssetCode        string         `gorm:"size:12;not null" json:"asset_code"`
+	AssetIssuer      string         `gorm:"size:56" json:"asset_issuer,omitempty"`
+	Interval         string         `gorm:"column:run_interval;size:10;not null" json:"interval"` // daily, weekly, monthly
+	StartAt          time.Time      `gorm:"not null" json:"start_at"`
+	// Occurrence is the index, counted from StartAt, of the run due at
+	// NextRunAt. The scheduler only fires a run if it still matches, so a
+	// run is never fired twice.
+	Occurrence int        `gorm:"not null;default:0" json:"-"`
+	NextRunAt  time.Time  `gorm:"index;not null" json:"next_run_at"`
+	LastRunAt  *time.Time `json:"last_run_at,omitempty"`
+	// LastPaymentID is the remittance created by the most recent run.
+	LastPaymentID *uint `json:"last_payment_id,omitempty"`
+	Active        bool  `gorm:"index;not null;default:true" json:"active"`
+	// DeactivatedReason explains why the scheduler, rather than the user,
+	// stopped the schedule.
+	DeactivatedReason string `gorm:"size:255" json:"deactivated_reason,omitempty"`
+}
+
+// TableName overrides the table name
+func (RecurringRemittance) TableName() string {
+	return "recurring_remittances"
+}
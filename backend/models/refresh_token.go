@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// RefreshToken records an issued refresh token by its JWT ID, so it can be
+// used at most once and revoked before it expires.
+type RefreshToken struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserID    uint      `gorm:"index;not null" json:"user_id"`
+	JTI       string    `gorm:"column:jti;uniqueIndex;size:36;not null" json:"jti"`
+	ExpiresAt time.Time `gorm:"index;not null" json:"expires_at"`
+	// Revoked is set when the token is rotated on refresh or the user logs out.
+	Revoked   bool       `gorm:"index;default:false" json:"revoked"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// TableName overrides the table name.
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
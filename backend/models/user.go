@@ -9,6 +9,24 @@ import (
 	"gorm.io/gorm"
 )
 
+// User roles, from least to most privileged. A compliance officer holds only
+// the admin permissions granted to RoleCompliance in role_permissions.
+const (
+	RoleUser       = "user"
+	RoleCompliance = "compliance"
+	RoleAdmin      = "admin"
+	RoleSuperadmin = "superadmin"
+)
+
+// IsValidRole reports whether role is one a user may hold.
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleUser, RoleCompliance, RoleAdmin, RoleSuperadmin:
+		return true
+	}
+	return false
+}
+
 type User struct {
 	ID                  uint           `gorm:"primaryKey" json:"id"`
 	CreatedAt           time.Time      `json:"created_at"`
@@ -18,13 +36,35 @@ type User struct {
 	Name                string         `gorm:"size:255;not null" json:"name"`
 	StellarAddress      string         `gorm:"uniqueIndex;size:56;not null" json:"stellar_address"`
 	PasswordHash        string         `gorm:"size:255;not null" json:"-"`
-	Role                string         `gorm:"size:20;default:'user'" json:"role"`
+	Role                string         `gorm:"size:20;default:'user'" json:"role"` // user, admin, superadmin
 	Country             string         `gorm:"size:2" json:"country"`
 	KYCStatus           string         `gorm:"size:20;default:'pending'" json:"kyc_status"`
 	KYCVerifiedAt       *time.Time     `json:"kyc_verified_at"`
 	IsActive            bool           `gorm:"index;default:true" json:"is_active"`
 	DefaultCurrency     string         `gorm:"size:10;default:'USD'" json:"default_currency"`
 	EmailNotifications  bool           `gorm:"default:true" json:"email_notifications"`
+	// Unregistered marks a placeholder created for a remittance recipient who
+	// has not signed up yet; registering with the same address claims it.
+	Unregistered bool `gorm:"index;default:false" json:"unregistered,omitempty"`
+	// TOTPSecret is the two-factor authenticator secret, set on enrolment.
+	// TOTPEnabled is set once a code from it has been verified, and
+	// TOTPLastStep is the time step of the last code accepted, so no code is
+	// accepted twice.
+	TOTPSecret   EncryptedString `gorm:"type:text" json:"-"`
+	TOTPEnabled  bool            `gorm:"default:false" json:"totp_enabled"`
+	TOTPLastStep int64           `gorm:"default:0" json:"-"`
+	// StellarSecretSeed is the secret seed of a Stellar address generated
+	// on registration. It never leaves the server; users who bring their own
+	// address keep their seed to themselves and leave this empty.
+	StellarSecretSeed EncryptedString `gorm:"type:text" json:"-"`
+	// VerifiedAt is when the user confirmed their email address through the
+	// link sent on registration. Remittances cannot be sent before then.
+	VerifiedAt *time.Time `json:"verified_at"`
+	// FailedLoginAttempts counts wrong passwords since the last successful
+	// login or lockout. Reaching the configured limit locks the account
+	// until LockedUntil.
+	FailedLoginAttempts int        `gorm:"default:0" json:"-"`
+	LockedUntil         *time.Time `json:"locked_until,omitempty"`
 }
 
 // TableName overrides the table name.
@@ -32,6 +72,11 @@ func (User) TableName() string {
 	return "users"
 }
 
+// IsLocked reports whether the account is locked out of logging in at now.
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
+
 // ValidatePasswordStrength enforces minimum password requirements before hashing.
 func ValidatePasswordStrength(password string) error {
 	if len(password) < 8 {
@@ -65,18 +110,42 @@ func ValidatePasswordStrength(password string) error {
 	return nil
 }
 
-// HashPassword validates password strength then hashes it using bcrypt with cost 12.
+// DefaultBcryptCost is the bcrypt cost HashPassword uses.
+const DefaultBcryptCost = 12
+
+// HashPassword validates password strength then hashes it using bcrypt with
+// DefaultBcryptCost.
 func HashPassword(password string) (string, error) {
+	return HashPasswordWithCost(password, DefaultBcryptCost)
+}
+
+// HashPasswordWithCost validates password strength then hashes it using
+// bcrypt with the given cost.
+func HashPasswordWithCost(password string, cost int) (string, error) {
 	if err := ValidatePasswordStrength(password); err != nil {
 		return "", err
 	}
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), 12)
+	return RehashPassword(password, cost)
+}
+
+// RehashPassword hashes a password that has already been verified against
+// its stored hash. It skips the strength check, so passwords set before the
+// current rules can still be upgraded to a higher cost.
+func RehashPassword(password string, cost int) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), cost)
 	if err != nil {
 		return "", err
 	}
 	return string(hash), nil
 }
 
+// NeedsRehash reports whether a stored bcrypt hash was made with a lower cost
+// than the one given. A hash that cannot be parsed is left alone.
+func NeedsRehash(hash string, cost int) bool {
+	current, err := bcrypt.Cost([]byte(hash))
+	return err == nil && current < cost
+}
+
 // ComparePassword reports whether the plaintext password matches the stored bcrypt hash.
 func ComparePassword(hash, password string) bool {
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
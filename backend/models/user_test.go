@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestHashPassword_GeneratesHash(t *testing.T) {
@@ -30,6 +31,35 @@ func TestHashPassword_WeakPasswordRejected(t *testing.T) {
 	assert.Contains(t, strings.ToLower(err.Error()), "password")
 }
 
+func TestHashPasswordWithCost(t *testing.T) {
+	hash, err := HashPasswordWithCost("Secure@Pass1", bcrypt.MinCost)
+	require.NoError(t, err)
+	cost, err := bcrypt.Cost([]byte(hash))
+	require.NoError(t, err)
+	assert.Equal(t, bcrypt.MinCost, cost)
+	assert.True(t, ComparePassword(hash, "Secure@Pass1"))
+
+	hash, err = HashPassword("Secure@Pass1")
+	require.NoError(t, err)
+	cost, err = bcrypt.Cost([]byte(hash))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultBcryptCost, cost)
+
+	_, err = HashPasswordWithCost("weak", bcrypt.MinCost)
+	assert.Error(t, err)
+}
+
+func TestNeedsRehash(t *testing.T) {
+	// Passwords set before the strength rules can still be rehashed.
+	hash, err := RehashPassword("legacy", bcrypt.MinCost)
+	require.NoError(t, err)
+
+	assert.True(t, NeedsRehash(hash, bcrypt.MinCost+1))
+	assert.False(t, NeedsRehash(hash, bcrypt.MinCost))
+	assert.False(t, NeedsRehash(hash, bcrypt.MinCost-1))
+	assert.False(t, NeedsRehash("not-a-bcrypt-hash", DefaultBcryptCost))
+}
+
 func TestComparePassword_Valid(t *testing.T) {
 	hash, err := HashPassword("Secure@Pass1")
 	require.NoError(t, err)
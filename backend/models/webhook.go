@@ -34,6 +34,8 @@ type WebhookDelivery struct {
 	AttemptCount  int            `gorm:"default:0" json:"attempt_count"`
 	NextRetryAt   *time.Time     `json:"next_retry_at"`
 	CompletedAt   *time.Time     `json:"completed_at"`
+	// FlaggedAt is set once an undelivered delivery outlives the alert age.
+	FlaggedAt *time.Time `gorm:"index" json:"flagged_at,omitempty"`
 }
 
 // TableName overrides the table name
@@ -0,0 +1,413 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/go/strkey"
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/utils"
+	"gorm.io/gorm"
+)
+
+// Release condition types accepted under the "release" key of a payment's conditions.
+const (
+	ConditionRateAbove = "rate_above"
+	ConditionRateBelow = "rate_below"
+	// ConditionSustained wraps another condition and only holds once that
+	// condition has been continuously satisfied for Duration.
+	ConditionSustained = "sustained"
+)
+
+// Keys accepted in a remittance's conditions. "release" holds the
+// off-chain conditions the sweeper evaluates; the others are enforced
+// on-ledger by the escrow transaction.
+const (
+	ConditionKeyRelease = "release"
+	// ConditionKeyReleaseAfter is an RFC 3339 time before which the escrow
+	// transaction is invalid.
+	ConditionKeyReleaseAfter = "release_after"
+	// ConditionKeyHashlock is the hex SHA-256 hash of a preimage that must be
+	// revealed for the escrow transaction to be accepted.
+	ConditionKeyHashlock = "hashlock"
+	// ConditionKeyRecipientApproval is true when the recipient must approve release.
+	ConditionKeyRecipientApproval = "recipient_approval"
+	// ConditionKeyOracle is the Stellar account attesting to off-chain conditions.
+	ConditionKeyOracle = "oracle"
+	// ConditionKeyNote is a free-text annotation with no effect on release.
+	ConditionKeyNote = "note"
+)
+
+// ErrInvalidCondition is returned for a release condition the sweeper cannot evaluate.
+var ErrInvalidCondition = errors.New("invalid release condition")
+
+// ReleaseCondition is a single entry in the "release" list of Payment.Conditions, e.g.
+//
+//	{"type": "sustained", "duration": "24h",
+//	 "condition": {"type": "rate_above", "pair": "USD/NGN", "threshold": 1500}}
+type ReleaseCondition struct {
+	Type      string            `json:"type"`
+	Pair      string            `json:"pair,omitempty"`
+	Threshold float64           `json:"threshold,omitempty"`
+	Duration  string            `json:"duration,omitempty"`
+	Condition *ReleaseCondition `json:"condition,omitempty"`
+	// FirstSatisfiedAt is maintained by the sweeper for sustained conditions
+	// and cleared as soon as the wrapped condition stops holding.
+	FirstSatisfiedAt *time.Time `json:"first_satisfied_at,omitempty"`
+}
+
+// ParseReleaseConditions extracts the release conditions from a payment's
+// conditions blob. A blob without a "release" key has none.
+func ParseReleaseConditions(blob string) ([]ReleaseCondition, error) {
+	if blob == "" || blob == "null" {
+		return nil, nil
+	}
+	var parsed struct {
+		Release []ReleaseCondition `json:"release"`
+	}
+	if err := json.Unmarshal([]byte(blob), &parsed); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCondition, err)
+	}
+	return parsed.Release, nil
+}
+
+// RemittanceConditions is the schema of a remittance's conditions. Every
+// key is optional; the stored conditions blob is this struct, normalized.
+type RemittanceConditions struct {
+	// Release lists the off-chain conditions the sweeper evaluates.
+	Release []ReleaseCondition `json:"release,omitempty"`
+	// ReleaseAfter and Hashlock are enforced on-ledger by the escrow
+	// transaction; see utils.EscrowConditions.
+	ReleaseAfter *time.Time `json:"release_after,omitempty"`
+	Hashlock     string     `json:"hashlock,omitempty"`
+	// RecipientApproval records that the recipient must approve release.
+	RecipientApproval bool `json:"recipient_approval,omitempty"`
+	// Oracle is the Stellar account attesting that off-chain conditions hold.
+	Oracle string `json:"oracle,omitempty"`
+	Note   string `json:"note,omitempty"`
+}
+
+// Escrow returns the conditions the escrow transaction enforces.
+func (c *RemittanceConditions) Escrow() utils.EscrowConditions {
+	if c == nil {
+		return utils.EscrowConditions{}
+	}
+	escrow := utils.EscrowConditions{Hashlock: c.Hashlock}
+	if c.ReleaseAfter != nil {
+		escrow.ReleaseAfter = *c.ReleaseAfter
+	}
+	return escrow
+}
+
+// ConditionFieldError describes why one key of a remittance's conditions
+// was rejected. Rule is "unknown", "type", or "value".
+type ConditionFieldError struct {
+	Key     string
+	Rule    string
+	Message string
+}
+
+// InvalidConditionsError lists every rejected key of a remittance's
+// conditions. It matches ErrInvalidCondition under errors.Is.
+type InvalidConditionsError struct {
+	Fields []ConditionFieldError
+}
+
+func (e *InvalidConditionsError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Message
+	}
+	return fmt.Sprintf("%v: %s", ErrInvalidCondition, strings.Join(messages, "; "))
+}
+
+func (e *InvalidConditionsError) Unwrap() error {
+	return ErrInvalidCondition
+}
+
+// ParseConditions validates a remittance's conditions against
+// RemittanceConditions at now and returns them normalized, or nil when there
+// are none. Unknown keys and mistyped values are rejected rather than stored
+// and ignored, all of them reported together in an *InvalidConditionsError.
+func ParseConditions(conditions map[string]interface{}, now time.Time) (*RemittanceConditions, error) {
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(conditions))
+	for key := range conditions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parsed := &RemittanceConditions{}
+	var invalid []ConditionFieldError
+	reject := func(key, rule, format string, args ...interface{}) {
+		invalid = append(invalid, ConditionFieldError{Key: key, Rule: rule, Message: key + " " + fmt.Sprintf(format, args...)})
+	}
+	for _, key := range keys {
+		value := conditions[key]
+		switch key {
+		case ConditionKeyRelease:
+			release, err := parseReleaseList(value)
+			if err != nil {
+				reject(key, "value", "%v", err)
+				continue
+			}
+			parsed.Release = release
+		case ConditionKeyReleaseAfter:
+			raw, ok := value.(string)
+			if !ok {
+				reject(key, "type", "must be an RFC 3339 timestamp string")
+				continue
+			}
+			releaseAfter, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				reject(key, "value", "must be an RFC 3339 timestamp such as 2030-01-02T15:04:05Z")
+				continue
+			}
+			if !releaseAfter.After(now) {
+				reject(key, "value", "must be in the future")
+				continue
+			}
+			releaseAfter = releaseAfter.UTC()
+			parsed.ReleaseAfter = &releaseAfter
+		case ConditionKeyHashlock:
+			raw, ok := value.(string)
+			if !ok {
+				reject(key, "type", "must be a hex string")
+				continue
+			}
+			if _, err := utils.ParseHashlock(raw); err != nil {
+				reject(key, "value", "must be a SHA-256 hash as 64 hex characters")
+				continue
+			}
+			parsed.Hashlock = strings.ToLower(raw)
+		case ConditionKeyRecipientApproval:
+			approval, ok := value.(bool)
+			if !ok {
+				reject(key, "type", "must be true or false")
+				continue
+			}
+			parsed.RecipientApproval = approval
+		case ConditionKeyOracle:
+			raw, ok := value.(string)
+			if !ok {
+				reject(key, "type", "must be a Stellar account address string")
+				continue
+			}
+			if !strkey.IsValidEd25519PublicKey(raw) {
+				reject(key, "value", "must be a Stellar account address (G...)")
+				continue
+			}
+			parsed.Oracle = raw
+		case ConditionKeyNote:
+			note, ok := value.(string)
+			if !ok {
+				reject(key, "type", "must be a string")
+				continue
+			}
+			parsed.Note = note
+		default:
+			reject(key, "unknown", "is not a supported condition")
+		}
+	}
+	if len(invalid) > 0 {
+		return nil, &InvalidConditionsError{Fields: invalid}
+	}
+	return parsed, nil
+}
+
+// parseReleaseList decodes and checks the "release" list, rejecting fields
+// and types the sweeper does not know.
+func parseReleaseList(value interface{}) ([]ReleaseCondition, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	var release []ReleaseCondition
+	if err := decoder.Decode(&release); err != nil {
+		return nil, fmt.Errorf("must be a list of release conditions: %v", err)
+	}
+	for i := range release {
+		if err := validateReleaseCondition(&release[i]); err != nil {
+			return nil, fmt.Errorf("entry %d: %v", i, err)
+		}
+	}
+	return release, nil
+}
+
+// validateReleaseCondition checks that the sweeper can evaluate condition,
+// clearing the sweeper's own bookkeeping so a client cannot preset it.
+func validateReleaseCondition(condition *ReleaseCondition) error {
+	condition.FirstSatisfiedAt = nil
+	switch condition.Type {
+	case ConditionRateAbove, ConditionRateBelow:
+		if condition.Pair == "" {
+			return fmt.Errorf("%s needs a pair such as USD/NGN", condition.Type)
+		}
+		if condition.Duration != "" || condition.Condition != nil {
+			return fmt.Errorf("%s takes only a pair and a threshold", condition.Type)
+		}
+	case ConditionSustained:
+		if duration, err := time.ParseDuration(condition.Duration); err != nil || duration <= 0 {
+			return fmt.Errorf("sustained needs a positive duration such as 24h")
+		}
+		if condition.Condition == nil {
+			return fmt.Errorf("sustained needs a condition to sustain")
+		}
+		return validateReleaseCondition(condition.Condition)
+	default:
+		return fmt.Errorf("unknown type %q", condition.Type)
+	}
+	return nil
+}
+
+// withReleaseConditions writes the release conditions back into the blob,
+// leaving any other keys untouched.
+func withReleaseConditions(blob string, conditions []ReleaseCondition) (string, error) {
+	fields := map[string]json.RawMessage{}
+	if blob != "" && blob != "null" {
+		if err := json.Unmarshal([]byte(blob), &fields); err != nil {
+			return "", err
+		}
+	}
+	release, err := json.Marshal(conditions)
+	if err != nil {
+		return "", err
+	}
+	fields["release"] = release
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+type ConditionService struct {
+	db    *gorm.DB
+	rates RateSource
+}
+
+func NewConditionService(db *gorm.DB, rates RateSource) *ConditionService {
+	return &ConditionService{db: db, rates: rates}
+}
+
+// Evaluate reports whether every condition holds at now. Sustained conditions
+// have their FirstSatisfiedAt updated in place, so callers must persist them.
+func (s *ConditionService) Evaluate(ctx context.Context, conditions []ReleaseCondition, now time.Time) (bool, error) {
+	met := true
+	// Evaluate every condition, even after one fails, so all sustained clocks stay current.
+	for i := range conditions {
+		ok, err := s.evaluate(ctx, &conditions[i], now)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			met = false
+		}
+	}
+	return met, nil
+}
+
+func (s *ConditionService) evaluate(ctx context.Context, condition *ReleaseCondition, now time.Time) (bool, error) {
+	switch condition.Type {
+	case ConditionRateAbove, ConditionRateBelow:
+		rate, err := s.rates.Rate(ctx, condition.Pair)
+		if err != nil {
+			return false, err
+		}
+		if condition.Type == ConditionRateAbove {
+			return rate > condition.Threshold, nil
+		}
+		return rate < condition.Threshold, nil
+	case ConditionSustained:
+		if condition.Condition == nil {
+			return false, fmt.Errorf("%w: sustained condition has no inner condition", ErrInvalidCondition)
+		}
+		duration, err := time.ParseDuration(condition.Duration)
+		if err != nil || duration <= 0 {
+			return false, fmt.Errorf("%w: sustained duration %q", ErrInvalidCondition, condition.Duration)
+		}
+		ok, err := s.evaluate(ctx, condition.Condition, now)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			condition.FirstSatisfiedAt = nil
+			return false, nil
+		}
+		if condition.FirstSatisfiedAt == nil {
+			satisfiedAt := now
+			condition.FirstSatisfiedAt = &satisfiedAt
+		}
+		return now.Sub(*condition.FirstSatisfiedAt) >= duration, nil
+	default:
+		return false, fmt.Errorf("%w: unknown type %q", ErrInvalidCondition, condition.Type)
+	}
+}
+
+// Sweep re-evaluates the release conditions of processing payments and
+// stamps ConditionsMetAt on those whose conditions all hold. It returns the
+// number of payments that became releasable. Payments whose conditions
+// cannot be evaluated are logged and retried on the next sweep.
+func (s *ConditionService) Sweep(ctx context.Context, now time.Time) (int, error) {
+	var payments []models.Payment
+	if err := s.db.Where("status = ? AND conditions_met_at IS NULL AND conditions LIKE ?", "processing", `%"release"%`).
+		Find(&payments).Error; err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for i := range payments {
+		payment := &payments[i]
+		log := logger.Log.WithFields(logrus.Fields{"payment_id": payment.ID})
+
+		conditions, err := ParseReleaseConditions(payment.Conditions.String())
+		if err != nil {
+			log.WithError(err).Warn("Skipping payment with unreadable release conditions")
+			continue
+		}
+		if len(conditions) == 0 {
+			continue
+		}
+
+		met, err := s.Evaluate(ctx, conditions, now)
+		if err != nil {
+			log.WithError(err).Warn("Failed to evaluate release conditions")
+			continue
+		}
+
+		blob, err := withReleaseConditions(payment.Conditions.String(), conditions)
+		if err != nil {
+			log.WithError(err).Warn("Failed to encode release conditions")
+			continue
+		}
+		updates := map[string]interface{}{"conditions": models.EncryptedString(blob)}
+		if met {
+			updates["conditions_met_at"] = now
+		}
+		if err := payment.UpdateVersioned(s.db, updates); err != nil {
+			if err == models.ErrConcurrentModification {
+				// The payment changed under us, e.g. it was cancelled; the next sweep re-reads it.
+				log.Info("Payment changed during sweep; retrying next sweep")
+				continue
+			}
+			return released, err
+		}
+		if met {
+			released++
+			log.Info("Release conditions met")
+		}
+	}
+	return released, nil
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/utils"
+)
+
+func TestSustainedConditionRelease(t *testing.T) {
+	db := setupTestDB(t)
+	rates := StaticRateSource{"USD/NGN": 1600}
+	service := NewConditionService(db, rates)
+
+	conditions := `{"note":"keep","release":[{"type":"sustained","duration":"24h","condition":{"type":"rate_above","pair":"USD/NGN","threshold":1500}}]}`
+	payment := models.Payment{SenderID: 1, RecipientID: 2, Amount: 100, Currency: "USD", Status: "processing", Conditions: models.EncryptedString(conditions)}
+	require.NoError(t, db.Create(&payment).Error)
+
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	count, err := service.Sweep(ctx, start)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count, "a condition that has only just become true must not release")
+
+	t.Run("Briefly satisfied condition does not release", func(t *testing.T) {
+		// Dip below the threshold for one sweep, which resets the clock.
+		rates["USD/NGN"] = 1400
+		count, err := service.Sweep(ctx, start.Add(12*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+
+		rates["USD/NGN"] = 1600
+		count, err = service.Sweep(ctx, start.Add(25*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 0, count, "24h from the first satisfaction but only 13h since the dip")
+
+		var got models.Payment
+		db.First(&got, payment.ID)
+		assert.Nil(t, got.ConditionsMetAt)
+		parsed, err := ParseReleaseConditions(got.Conditions.String())
+		require.NoError(t, err)
+		require.Len(t, parsed, 1)
+		require.NotNil(t, parsed[0].FirstSatisfiedAt)
+		assert.True(t, parsed[0].FirstSatisfiedAt.Equal(start.Add(25*time.Hour)))
+		assert.Contains(t, got.Conditions.String(), `"note":"keep"`)
+	})
+
+	t.Run("Condition held for the full duration releases", func(t *testing.T) {
+		count, err := service.Sweep(ctx, start.Add(49*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		var got models.Payment
+		db.First(&got, payment.ID)
+		require.NotNil(t, got.ConditionsMetAt)
+		assert.True(t, got.ConditionsMetAt.Equal(start.Add(49*time.Hour)))
+
+		// Released payments are not swept again.
+		count, err = service.Sweep(ctx, start.Add(50*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+}
+
+func TestEvaluateReleaseConditions(t *testing.T) {
+	service := NewConditionService(nil, StaticRateSource{"USD/KES": 130})
+	now := time.Now()
+
+	met, err := service.Evaluate(context.Background(), []ReleaseCondition{
+		{Type: ConditionRateAbove, Pair: "usd/kes", Threshold: 120},
+		{Type: ConditionRateBelow, Pair: "USD/KES", Threshold: 140},
+	}, now)
+	assert.NoError(t, err)
+	assert.True(t, met)
+
+	met, err = service.Evaluate(context.Background(), []ReleaseCondition{
+		{Type: ConditionRateAbove, Pair: "USD/KES", Threshold: 150},
+	}, now)
+	assert.NoError(t, err)
+	assert.False(t, met)
+
+	_, err = service.Evaluate(context.Background(), []ReleaseCondition{{Type: "moon_phase"}}, now)
+	assert.ErrorIs(t, err, ErrInvalidCondition)
+
+	_, err = service.Evaluate(context.Background(), []ReleaseCondition{
+		{Type: ConditionSustained, Duration: "soon", Condition: &ReleaseCondition{Type: ConditionRateAbove, Pair: "USD/KES"}},
+	}, now)
+	assert.ErrorIs(t, err, ErrInvalidCondition)
+
+	_, err = service.Evaluate(context.Background(), []ReleaseCondition{
+		{Type: ConditionRateAbove, Pair: "EUR/GHS", Threshold: 1},
+	}, now)
+	assert.ErrorIs(t, err, ErrRateUnavailable)
+}
+
+func TestParseConditions(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	hashlock := strings.Repeat("AB", 32)
+	oracle := "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"
+
+	t.Run("Valid conditions are normalized", func(t *testing.T) {
+		parsed, err := ParseConditions(map[string]interface{}{
+			"note":               "rent",
+			"release_after":      "2026-02-01T01:00:00+01:00",
+			"hashlock":           hashlock,
+			"recipient_approval": true,
+			"oracle":             oracle,
+			"release": []interface{}{map[string]interface{}{
+				"type": "sustained", "duration": "24h",
+				"condition":          map[string]interface{}{"type": "rate_above", "pair": "USD/NGN", "threshold": 1500},
+				"first_satisfied_at": "2025-01-01T00:00:00Z",
+			}},
+		}, now)
+		require.NoError(t, err)
+
+		releaseAfter := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+		assert.Equal(t, utils.EscrowConditions{ReleaseAfter: releaseAfter, Hashlock: strings.ToLower(hashlock)}, parsed.Escrow())
+
+		blob, err := json.Marshal(parsed)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"release": [{"type": "sustained", "duration": "24h", "condition": {"type": "rate_above", "pair": "USD/NGN", "threshold": 1500}}],
+			"release_after": "2026-02-01T00:00:00Z",
+			"hashlock": "`+strings.ToLower(hashlock)+`",
+			"recipient_approval": true,
+			"oracle": "`+oracle+`",
+			"note": "rent"
+		}`, string(blob), "the blob is normalized and the sweeper's bookkeeping cleared")
+
+		release, err := ParseReleaseConditions(string(blob))
+		require.NoError(t, err)
+		assert.Len(t, release, 1)
+	})
+
+	t.Run("No conditions", func(t *testing.T) {
+		parsed, err := ParseConditions(nil, now)
+		require.NoError(t, err)
+		assert.Nil(t, parsed)
+		assert.Zero(t, parsed.Escrow())
+	})
+
+	t.Run("Every bad key is reported", func(t *testing.T) {
+		_, err := ParseConditions(map[string]interface{}{
+			"deliver_by":         "tomorrow",
+			"recipient_approval": "yes",
+			"note":               "fine",
+		}, now)
+		assert.ErrorIs(t, err, ErrInvalidCondition)
+		var invalid *InvalidConditionsError
+		require.ErrorAs(t, err, &invalid)
+		assert.Equal(t, []ConditionFieldError{
+			{Key: "deliver_by", Rule: "unknown", Message: "deliver_by is not a supported condition"},
+			{Key: "recipient_approval", Rule: "type", Message: "recipient_approval must be true or false"},
+		}, invalid.Fields)
+	})
+
+	for name, conditions := range map[string]map[string]interface{}{
+		"release_after in past":    {"release_after": "2025-12-31T23:59:59Z"},
+		"release_after not a time": {"release_after": "next week"},
+		"release_after not string": {"release_after": 1767225600},
+		"short hashlock":           {"hashlock": "abcd"},
+		"non-hex hashlock":         {"hashlock": strings.Repeat("zz", 32)},
+		"oracle not an account":    {"oracle": "SBADSEED"},
+		"oracle not a string":      {"oracle": true},
+		"release not a list":       {"release": "soon"},
+		"release unknown type":     {"release": []interface{}{map[string]interface{}{"type": "moon_phase"}}},
+		"release unknown field":    {"release": []interface{}{map[string]interface{}{"type": "rate_above", "pair": "USD/NGN", "treshold": 1}}},
+		"sustained without inner":  {"release": []interface{}{map[string]interface{}{"type": "sustained", "duration": "1h"}}},
+		"note not a string":        {"note": 5},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseConditions(conditions, now)
+			assert.ErrorIs(t, err, ErrInvalidCondition)
+		})
+	}
+}
@@ -0,0 +1,5 @@
+package services
+
+// PaymentStatusDisputed marks a payment held for an admin to resolve a
+// dispute over it.
+const PaymentStatusDisputed = "disputed"
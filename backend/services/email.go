@@ -8,6 +8,7 @@ import (
 	"net/smtp"
 	"time"
 
+	"github.com/yourusername/gpay-remit/i18n"
 	"github.com/yourusername/gpay-remit/models"
 )
 
@@ -20,6 +21,12 @@ type EmailService struct {
 	enabled      bool
 }
 
+// AccountMailer sends account security emails. *EmailService satisfies it.
+type AccountMailer interface {
+	SendPasswordResetEmail(user *models.User, resetURL string, expiresAt time.Time) error
+	SendVerificationEmail(user *models.User, verifyURL string, expiresAt time.Time) error
+}
+
 type EmailTemplate struct {
 	Subject string
 	Body    string
@@ -116,6 +123,16 @@ func (s *EmailService) SendEmail(to, subject, body string) error {
 	return nil
 }
 
+// templateFuncs gives email templates a t function that renders catalog
+// messages in lang, the recipient's language.
+func templateFuncs(lang string) template.FuncMap {
+	return template.FuncMap{
+		"t": func(key string, args ...interface{}) string {
+			return i18n.T(lang, key, args...)
+		},
+	}
+}
+
 // SendPaymentCompletedEmail sends notification when payment is completed
 func (s *EmailService) SendPaymentCompletedEmail(user *models.User, payment *models.Payment) error {
 	if !user.EmailNotifications {
@@ -140,52 +157,53 @@ func (s *EmailService) SendPaymentCompletedEmail(user *models.User, payment *mod
 <body>
     <div class="container">
         <div class="header">
-            <h1>Payment Completed ✓</h1>
+            <h1>{{t "email.payment_completed.heading"}}</h1>
         </div>
         <div class="content">
-            <p>Hello {{.UserName}},</p>
-            <p>Your payment has been completed successfully!</p>
+            <p>{{t "email.greeting" .UserName}}</p>
+            <p>{{t "email.payment_completed.intro"}}</p>
             
             <div class="details">
-                <h3>Payment Details</h3>
+                <h3>{{t "email.payment_details"}}</h3>
                 <div class="detail-row">
-                    <span class="label">Payment ID:</span>
+                    <span class="label">{{t "email.label.payment_id"}}</span>
                     <span>{{.PaymentID}}</span>
                 </div>
                 <div class="detail-row">
-                    <span class="label">Amount:</span>
+                    <span class="label">{{t "email.label.amount"}}</span>
                     <span>{{.Amount}} {{.Currency}}</span>
                 </div>
                 <div class="detail-row">
-                    <span class="label">Recipient:</span>
+                    <span class="label">{{t "email.label.recipient"}}</span>
                     <span>{{.RecipientAccount}}</span>
                 </div>
                 <div class="detail-row">
-                    <span class="label">Fee:</span>
+                    <span class="label">{{t "email.label.fee"}}</span>
                     <span>{{.Fee}} {{.Currency}}</span>
                 </div>
                 <div class="detail-row">
-                    <span class="label">Status:</span>
+                    <span class="label">{{t "email.label.status"}}</span>
                     <span style="color: #4CAF50; font-weight: bold;">{{.Status}}</span>
                 </div>
                 <div class="detail-row">
-                    <span class="label">Date:</span>
+                    <span class="label">{{t "email.label.date"}}</span>
                     <span>{{.Date}}</span>
                 </div>
             </div>
             
-            <p>Thank you for using GPay-Remit!</p>
+            <p>{{t "email.payment_completed.thanks"}}</p>
         </div>
         <div class="footer">
-            <p>This is an automated email. Please do not reply.</p>
-            <p>To manage your email preferences, visit your account settings.</p>
+            <p>{{t "email.footer.automated"}}</p>
+            <p>{{t "email.footer.preferences"}}</p>
         </div>
     </div>
 </body>
 </html>
 `
 
-	t, err := template.New("payment_completed").Parse(tmpl)
+	lang := i18n.LanguageForCountry(user.Country)
+	t, err := template.New("payment_completed").Funcs(templateFuncs(lang)).Parse(tmpl)
 	if err != nil {
 		return fmt.Errorf("failed to parse template: %w", err)
 	}
@@ -206,7 +224,7 @@ func (s *EmailService) SendPaymentCompletedEmail(user *models.User, payment *mod
 		return fmt.Errorf("failed to execute template: %w", err)
 	}
 
-	subject := fmt.Sprintf("Payment #%d Completed Successfully", payment.ID)
+	subject := i18n.T(lang, "email.payment_completed.subject", payment.ID)
 	return s.SendEmail(user.Email, subject, body.String())
 }
 
@@ -237,57 +255,58 @@ func (s *EmailService) SendEscrowExpirationWarningEmail(user *models.User, payme
 <body>
     <div class="container">
         <div class="header">
-            <h1>⚠️ Escrow Expiration Warning</h1>
+            <h1>{{t "email.escrow_expiring.heading"}}</h1>
         </div>
         <div class="content">
-            <p>Hello {{.UserName}},</p>
+            <p>{{t "email.greeting" .UserName}}</p>
             
             <div class="warning">
-                <strong>Action Required:</strong> Your escrow payment is set to expire in <strong>{{.HoursRemaining}} hours</strong>.
+                <strong>{{t "email.escrow_expiring.notice_label"}}</strong> {{t "email.escrow_expiring.notice"}} <strong>{{t "email.hours" .HoursRemaining}}</strong>.
             </div>
             
-            <p>Please take action before the escrow expires to avoid losing your funds.</p>
+            <p>{{t "email.escrow_expiring.take_action"}}</p>
             
             <div class="details">
-                <h3>Payment Details</h3>
+                <h3>{{t "email.payment_details"}}</h3>
                 <div class="detail-row">
-                    <span class="label">Payment ID:</span>
+                    <span class="label">{{t "email.label.payment_id"}}</span>
                     <span>{{.PaymentID}}</span>
                 </div>
                 <div class="detail-row">
-                    <span class="label">Amount:</span>
+                    <span class="label">{{t "email.label.amount"}}</span>
                     <span>{{.Amount}} {{.Currency}}</span>
                 </div>
                 <div class="detail-row">
-                    <span class="label">Recipient:</span>
+                    <span class="label">{{t "email.label.recipient"}}</span>
                     <span>{{.RecipientAccount}}</span>
                 </div>
                 <div class="detail-row">
-                    <span class="label">Escrow ID:</span>
+                    <span class="label">{{t "email.label.escrow_id"}}</span>
                     <span>{{.EscrowID}}</span>
                 </div>
                 <div class="detail-row">
-                    <span class="label">Time Remaining:</span>
-                    <span style="color: #FF9800; font-weight: bold;">{{.HoursRemaining}} hours</span>
+                    <span class="label">{{t "email.label.time_remaining"}}</span>
+                    <span style="color: #FF9800; font-weight: bold;">{{t "email.hours" .HoursRemaining}}</span>
                 </div>
             </div>
             
             <div class="cta">
-                <a href="#" class="button">View Payment Details</a>
+                <a href="#" class="button">{{t "email.escrow_expiring.view_details"}}</a>
             </div>
             
-            <p>If you have any questions or need assistance, please contact our support team.</p>
+            <p>{{t "email.escrow_expiring.support"}}</p>
         </div>
         <div class="footer">
-            <p>This is an automated email. Please do not reply.</p>
-            <p>To manage your email preferences, visit your account settings.</p>
+            <p>{{t "email.footer.automated"}}</p>
+            <p>{{t "email.footer.preferences"}}</p>
         </div>
     </div>
 </body>
 </html>
 `
 
-	t, err := template.New("escrow_expiration").Parse(tmpl)
+	lang := i18n.LanguageForCountry(user.Country)
+	t, err := template.New("escrow_expiration").Funcs(templateFuncs(lang)).Parse(tmpl)
 	if err != nil {
 		return fmt.Errorf("failed to parse template: %w", err)
 	}
@@ -307,7 +326,7 @@ func (s *EmailService) SendEscrowExpirationWarningEmail(user *models.User, payme
 		return fmt.Errorf("failed to execute template: %w", err)
 	}
 
-	subject := fmt.Sprintf("⚠️ Escrow Expiring Soon - Payment #%d", payment.ID)
+	subject := i18n.T(lang, "email.escrow_expiring.subject", payment.ID)
 	return s.SendEmail(user.Email, subject, body.String())
 }
 
@@ -336,49 +355,50 @@ func (s *EmailService) SendPaymentFailedEmail(user *models.User, payment *models
 <body>
     <div class="container">
         <div class="header">
-            <h1>Payment Failed ✗</h1>
+            <h1>{{t "email.payment_failed.heading"}}</h1>
         </div>
         <div class="content">
-            <p>Hello {{.UserName}},</p>
+            <p>{{t "email.greeting" .UserName}}</p>
             
             <div class="error">
-                <strong>Payment Failed:</strong> Your payment could not be completed.
+                <strong>{{t "email.payment_failed.notice_label"}}</strong> {{t "email.payment_failed.notice"}}
             </div>
             
-            <p><strong>Reason:</strong> {{.Reason}}</p>
+            <p><strong>{{t "email.label.reason"}}</strong> {{.Reason}}</p>
             
             <div class="details">
-                <h3>Payment Details</h3>
+                <h3>{{t "email.payment_details"}}</h3>
                 <div class="detail-row">
-                    <span class="label">Payment ID:</span>
+                    <span class="label">{{t "email.label.payment_id"}}</span>
                     <span>{{.PaymentID}}</span>
                 </div>
                 <div class="detail-row">
-                    <span class="label">Amount:</span>
+                    <span class="label">{{t "email.label.amount"}}</span>
                     <span>{{.Amount}} {{.Currency}}</span>
                 </div>
                 <div class="detail-row">
-                    <span class="label">Recipient:</span>
+                    <span class="label">{{t "email.label.recipient"}}</span>
                     <span>{{.RecipientAccount}}</span>
                 </div>
                 <div class="detail-row">
-                    <span class="label">Date:</span>
+                    <span class="label">{{t "email.label.date"}}</span>
                     <span>{{.Date}}</span>
                 </div>
             </div>
             
-            <p>You can try again or contact support if you need assistance.</p>
+            <p>{{t "email.payment_failed.retry"}}</p>
         </div>
         <div class="footer">
-            <p>This is an automated email. Please do not reply.</p>
-            <p>To manage your email preferences, visit your account settings.</p>
+            <p>{{t "email.footer.automated"}}</p>
+            <p>{{t "email.footer.preferences"}}</p>
         </div>
     </div>
 </body>
 </html>
 `
 
-	t, err := template.New("payment_failed").Parse(tmpl)
+	lang := i18n.LanguageForCountry(user.Country)
+	t, err := template.New("payment_failed").Funcs(templateFuncs(lang)).Parse(tmpl)
 	if err != nil {
 		return fmt.Errorf("failed to parse template: %w", err)
 	}
@@ -398,6 +418,120 @@ func (s *EmailService) SendPaymentFailedEmail(user *models.User, payment *models
 		return fmt.Errorf("failed to execute template: %w", err)
 	}
 
-	subject := fmt.Sprintf("Payment #%d Failed", payment.ID)
+	subject := i18n.T(lang, "email.payment_failed.subject", payment.ID)
+	return s.SendEmail(user.Email, subject, body.String())
+}
+
+// SendPasswordResetEmail sends a password reset link. It is sent even to
+// users who have opted out of email notifications.
+func (s *EmailService) SendPasswordResetEmail(user *models.User, resetURL string, expiresAt time.Time) error {
+	tmpl := `
+<!DOCTYPE html>
+<html>
+<head>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #2196F3; color: white; padding: 20px; text-align: center; }
+        .content { padding: 20px; background-color: #f9f9f9; }
+        .button { display: inline-block; padding: 12px 24px; background-color: #2196F3; color: white; text-decoration: none; border-radius: 5px; margin: 15px 0; }
+        .footer { text-align: center; padding: 20px; font-size: 12px; color: #777; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>{{t "email.password_reset.heading"}}</h1>
+        </div>
+        <div class="content">
+            <p>{{t "email.greeting" .UserName}}</p>
+            <p>{{t "email.password_reset.intro"}}</p>
+            <p><a href="{{.ResetURL}}" class="button">{{t "email.password_reset.button"}}</a></p>
+            <p>{{t "email.password_reset.expiry" .ExpiresAt}}</p>
+            <p>{{t "email.password_reset.ignore"}}</p>
+        </div>
+        <div class="footer">
+            <p>{{t "email.footer.automated"}}</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+	lang := i18n.LanguageForCountry(user.Country)
+	t, err := template.New("password_reset").Funcs(templateFuncs(lang)).Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	data := map[string]interface{}{
+		"UserName":  user.Name,
+		"ResetURL":  resetURL,
+		"ExpiresAt": expiresAt.UTC().Format("2006-01-02 15:04 MST"),
+	}
+
+	var body bytes.Buffer
+	if err := t.Execute(&body, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	subject := i18n.T(lang, "email.password_reset.subject")
+	return s.SendEmail(user.Email, subject, body.String())
+}
+
+// SendVerificationEmail sends the link that confirms the user's email
+// address. It is sent even to users who have opted out of email
+// notifications.
+func (s *EmailService) SendVerificationEmail(user *models.User, verifyURL string, expiresAt time.Time) error {
+	tmpl := `
+<!DOCTYPE html>
+<html>
+<head>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #4CAF50; color: white; padding: 20px; text-align: center; }
+        .content { padding: 20px; background-color: #f9f9f9; }
+        .button { display: inline-block; padding: 12px 24px; background-color: #4CAF50; color: white; text-decoration: none; border-radius: 5px; margin: 15px 0; }
+        .footer { text-align: center; padding: 20px; font-size: 12px; color: #777; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>{{t "email.verify_email.heading"}}</h1>
+        </div>
+        <div class="content">
+            <p>{{t "email.greeting" .UserName}}</p>
+            <p>{{t "email.verify_email.intro"}}</p>
+            <p><a href="{{.VerifyURL}}" class="button">{{t "email.verify_email.button"}}</a></p>
+            <p>{{t "email.verify_email.expiry" .ExpiresAt}}</p>
+        </div>
+        <div class="footer">
+            <p>{{t "email.footer.automated"}}</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+	lang := i18n.LanguageForCountry(user.Country)
+	t, err := template.New("verify_email").Funcs(templateFuncs(lang)).Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	data := map[string]interface{}{
+		"UserName":  user.Name,
+		"VerifyURL": verifyURL,
+		"ExpiresAt": expiresAt.UTC().Format("2006-01-02 15:04 MST"),
+	}
+
+	var body bytes.Buffer
+	if err := t.Execute(&body, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	subject := i18n.T(lang, "email.verify_email.subject")
 	return s.SendEmail(user.Email, subject, body.String())
 }
@@ -138,4 +138,10 @@ func TestEmailTemplateGeneration(t *testing.T) {
 	
 	err = service.SendPaymentFailedEmail(user, payment, "Network error")
 	assert.NoError(t, err)
+	
+	err = service.SendPasswordResetEmail(user, "https://example.com/reset-password?token=abc", time.Now().Add(30*time.Minute))
+	assert.NoError(t, err)
+	
+	err = service.SendVerificationEmail(user, "https://example.com/api/v1/auth/verify?token=abc", time.Now().Add(48*time.Hour))
+	assert.NoError(t, err)
 }
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go/txnbuild"
+)
+
+// stroopsPerXLM converts Stellar fee units to XLM.
+const stroopsPerXLM = 1e7
+
+// TotalEstimate is the full cost of a remittance before it is created. All
+// amounts other than LedgerFeeXLM and ConvertedAmount are in Currency, and
+// TotalDebit = Amount + Fees.TotalFee + LedgerFee.
+type TotalEstimate struct {
+	Amount         float64 `json:"amount"`
+	Currency       string  `json:"currency"`
+	TargetCurrency string  `json:"target_currency"`
+	// Rate is units of TargetCurrency per unit of Currency.
+	Rate            float64      `json:"rate"`
+	ConvertedAmount float64      `json:"converted_amount"`
+	Fees            FeeBreakdown `json:"fees"`
+	// LedgerFeeXLM is the estimated Stellar transaction fee; LedgerFee is the
+	// same fee converted into Currency.
+	LedgerFeeXLM float64 `json:"ledger_fee_xlm"`
+	LedgerFee    float64 `json:"ledger_fee"`
+	TotalDebit   float64 `json:"total_debit"`
+}
+
+// EstimateTotal composes the fee engine, FX rates, and the Stellar network fee
+// for a single-operation transaction into one breakdown and grand total.
+func EstimateTotal(ctx context.Context, fees *FeeService, rates RateSource, amount float64, currency, targetCurrency string) (TotalEstimate, error) {
+	currency = strings.ToUpper(currency)
+	targetCurrency = strings.ToUpper(targetCurrency)
+	if targetCurrency == "" {
+		targetCurrency = currency
+	}
+
+	breakdown, err := fees.Calculate(amount)
+	if err != nil {
+		return TotalEstimate{}, err
+	}
+
+	rate, err := conversionRate(ctx, rates, currency, targetCurrency)
+	if err != nil {
+		return TotalEstimate{}, err
+	}
+
+	ledgerFeeXLM := float64(txnbuild.MinBaseFee) / stroopsPerXLM
+	xlmRate, err := conversionRate(ctx, rates, "XLM", currency)
+	if err != nil {
+		return TotalEstimate{}, err
+	}
+	ledgerFee := roundMoney(ledgerFeeXLM * xlmRate)
+
+	estimate := TotalEstimate{
+		Amount:          amount,
+		Currency:        currency,
+		TargetCurrency:  targetCurrency,
+		Rate:            rate,
+		ConvertedAmount: roundMoney(amount * rate),
+		Fees:            breakdown,
+		LedgerFeeXLM:    ledgerFeeXLM,
+		LedgerFee:       ledgerFee,
+		TotalDebit:      roundMoney(amount + breakdown.TotalFee + ledgerFee),
+	}
+	if err := checkMoney("total debit", estimate.TotalDebit); err != nil {
+		return TotalEstimate{}, err
+	}
+	return estimate, nil
+}
+
+// conversionRate returns units of "to" per unit of "from", using the direct
+// pair when known and otherwise inverting the reverse pair.
+func conversionRate(ctx context.Context, rates RateSource, from, to string) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+	rate, err := rates.Rate(ctx, from+"/"+to)
+	if err == nil {
+		return rate, nil
+	}
+	inverse, inverseErr := rates.Rate(ctx, to+"/"+from)
+	if inverseErr != nil || inverse <= 0 {
+		return 0, fmt.Errorf("%w: %s/%s", ErrRateUnavailable, from, to)
+	}
+	return 1 / inverse, nil
+}
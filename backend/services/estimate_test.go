@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateTotal_CrossCurrency(t *testing.T) {
+	// XLM is priced through the inverse pair, so the ledger fee is converted as well.
+	rates := StaticRateSource{"USD/NGN": 1550, "USD/XLM": 0.0001}
+
+	estimate, err := EstimateTotal(context.Background(), newTestFeeService(0, 0), rates, 1000, "usd", "ngn")
+	require.NoError(t, err)
+
+	assert.Equal(t, "USD", estimate.Currency)
+	assert.Equal(t, "NGN", estimate.TargetCurrency)
+	assert.Equal(t, 1550.0, estimate.Rate)
+	assert.Equal(t, 1550000.0, estimate.ConvertedAmount)
+	assert.Equal(t, 10.0, estimate.Fees.TotalFee)
+	assert.Equal(t, 0.00001, estimate.LedgerFeeXLM)
+	assert.Equal(t, 0.1, estimate.LedgerFee)
+
+	fees := estimate.Fees.PlatformFee + estimate.Fees.ForexFee + estimate.Fees.ComplianceFee + estimate.Fees.NetworkFee
+	assert.InDelta(t, estimate.Fees.TotalFee, fees, 1e-9)
+	assert.InDelta(t, estimate.Amount+estimate.Fees.TotalFee+estimate.LedgerFee, estimate.TotalDebit, 1e-9)
+	assert.Equal(t, 1010.1, estimate.TotalDebit)
+}
+
+func TestEstimateTotal_SameCurrency(t *testing.T) {
+	estimate, err := EstimateTotal(context.Background(), newTestFeeService(0, 0), StaticRateSource{}, 200, "XLM", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "XLM", estimate.TargetCurrency)
+	assert.Equal(t, 1.0, estimate.Rate)
+	assert.Equal(t, 200.0, estimate.ConvertedAmount)
+	assert.Equal(t, 202.0, estimate.TotalDebit)
+}
+
+func TestEstimateTotal_UnsupportedPair(t *testing.T) {
+	_, err := EstimateTotal(context.Background(), newTestFeeService(0, 0), StaticRateSource{"USD/XLM": 8}, 100, "USD", "GHS")
+	assert.True(t, IsRateUnavailable(err))
+}
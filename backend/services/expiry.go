@@ -0,0 +1,73 @@
+package services
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/models"
+	"gorm.io/gorm"
+)
+
+// PaymentStatusExpired marks a pending payment that was never submitted
+// before the expiry age.
+const PaymentStatusExpired = "expired"
+
+// EventPaymentExpired is the webhook event emitted for each expired payment.
+const EventPaymentExpired = "payment.expired"
+
+type ExpiryService struct {
+	db       *gorm.DB
+	webhooks *WebhookDeliveryService
+}
+
+// NewExpiryService creates an expiry service. webhooks may be nil, in which
+// case transitions are only logged.
+func NewExpiryService(db *gorm.DB, webhooks *WebhookDeliveryService) *ExpiryService {
+	return &ExpiryService{db: db, webhooks: webhooks}
+}
+
+// ExpireStale moves pending payments created before cutoff to expired and
+// returns those it transitioned. The scan is served by the partial index on
+// pending payments' created_at. A payment that changes concurrently (e.g. is
+// submitted) is left alone.
+func (s *ExpiryService) ExpireStale(cutoff time.Time) ([]models.Payment, error) {
+	var stale []models.Payment
+	if err := s.db.Where("status = ? AND created_at < ?", "pending", cutoff).
+		Order("created_at").
+		Find(&stale).Error; err != nil {
+		return nil, err
+	}
+
+	expired := make([]models.Payment, 0, len(stale))
+	for i := range stale {
+		payment := &stale[i]
+		if err := payment.UpdateVersioned(s.db, map[string]interface{}{"status": PaymentStatusExpired}); err != nil {
+			if err == models.ErrConcurrentModification {
+				continue
+			}
+			return expired, err
+		}
+		payment.Status = PaymentStatusExpired
+		expired = append(expired, *payment)
+		PaymentStatusEvents.PublishPayment(*payment)
+
+		logger.Log.WithFields(logrus.Fields{
+			"payment_id": payment.ID,
+			"sender_id":  payment.SenderID,
+			"created_at": payment.CreatedAt.Format(time.RFC3339),
+		}).Info("Pending payment expired")
+		if s.webhooks != nil {
+			if err := s.webhooks.TriggerWebhook(EventPaymentExpired, map[string]interface{}{
+				"payment_id": payment.ID,
+				"sender_id":  payment.SenderID,
+				"amount":     payment.Amount,
+				"currency":   payment.Currency,
+				"created_at": payment.CreatedAt,
+			}); err != nil {
+				logger.Log.WithField("payment_id", payment.ID).WithError(err).Warn("Failed to trigger payment.expired webhook")
+			}
+		}
+	}
+	return expired, nil
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/models"
+)
+
+func TestExpireStale(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+
+	old := models.Payment{SenderID: 1, RecipientID: 2, Amount: 10, Currency: "USD", Status: "pending", CreatedAt: now.Add(-72 * time.Hour)}
+	fresh := models.Payment{SenderID: 1, RecipientID: 2, Amount: 20, Currency: "USD", Status: "pending", CreatedAt: now.Add(-time.Hour)}
+	oldProcessing := models.Payment{SenderID: 1, RecipientID: 2, Amount: 30, Currency: "USD", Status: "processing", CreatedAt: now.Add(-72 * time.Hour)}
+	require.NoError(t, db.Create(&old).Error)
+	require.NoError(t, db.Create(&fresh).Error)
+	require.NoError(t, db.Create(&oldProcessing).Error)
+
+	expired, err := NewExpiryService(db, nil).ExpireStale(now.Add(-48 * time.Hour))
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	assert.Equal(t, old.ID, expired[0].ID)
+
+	statusOf := func(id uint) string {
+		var p models.Payment
+		require.NoError(t, db.First(&p, id).Error)
+		return p.Status
+	}
+	assert.Equal(t, PaymentStatusExpired, statusOf(old.ID))
+	assert.Equal(t, "pending", statusOf(fresh.ID))
+	assert.Equal(t, "processing", statusOf(oldProcessing.ID))
+
+	// A second pass finds nothing new.
+	expired, err = NewExpiryService(db, nil).ExpireStale(now.Add(-48 * time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, expired)
+}
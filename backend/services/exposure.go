@@ -0,0 +1,104 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/gpay-remit/models"
+)
+
+// PairExposure totals the outstanding cross-currency remittances for one
+// currency pair. Quoted payments are pending ones holding a locked rate that
+// have not been submitted yet; in-flight payments have been submitted but not
+// settled.
+type PairExposure struct {
+	SourceCurrency string  `json:"source_currency"`
+	TargetCurrency string  `json:"target_currency"`
+	QuotedCount    int64   `json:"quoted_count"`
+	InFlightCount  int64   `json:"in_flight_count"`
+	SourceAmount   float64 `json:"source_amount"`
+	TargetAmount   float64 `json:"target_amount"`
+}
+
+// CurrencyExposure is the platform's net position in one currency across all
+// pairs: source amounts it will receive less converted amounts it owes.
+// Negative means the platform is short the currency.
+type CurrencyExposure struct {
+	Currency string  `json:"currency"`
+	Net      float64 `json:"net"`
+}
+
+type FXExposureReport struct {
+	GeneratedAt time.Time          `json:"generated_at"`
+	Pairs       []PairExposure     `json:"pairs"`
+	Currencies  []CurrencyExposure `json:"currencies"`
+}
+
+// exposureStatuses are the non-terminal statuses whose converted amount is
+// still owed.
+var exposureStatuses = []string{"pending", "processing", PaymentStatusNeedsReview}
+
+// GetFXExposure sums the converted amounts of non-terminal cross-currency
+// remittances by currency pair, at the rate each one was quoted.
+func (s *AnalyticsService) GetFXExposure(now time.Time) (*FXExposureReport, error) {
+	var payments []models.Payment
+	if err := s.db.Select("status", "amount", "currency", "target_currency", "converted_amount").
+		Where("status IN ? AND target_currency <> '' AND target_currency <> currency", exposureStatuses).
+		Find(&payments).Error; err != nil {
+		return nil, fmt.Errorf("failed to get fx exposure: %w", err)
+	}
+
+	pairs := map[string]*PairExposure{}
+	net := map[string]float64{}
+	for i := range payments {
+		payment := &payments[i]
+		if !IsCrossCurrency(payment) {
+			continue
+		}
+		source := strings.ToUpper(payment.Currency)
+		target := strings.ToUpper(payment.TargetCurrency)
+
+		key := source + "/" + target
+		pair, ok := pairs[key]
+		if !ok {
+			pair = &PairExposure{SourceCurrency: source, TargetCurrency: target}
+			pairs[key] = pair
+		}
+		if payment.Status == "pending" {
+			pair.QuotedCount++
+		} else {
+			pair.InFlightCount++
+		}
+		pair.SourceAmount += payment.Amount
+		pair.TargetAmount += payment.ConvertedAmount
+		net[source] += payment.Amount
+		net[target] -= payment.ConvertedAmount
+	}
+
+	report := &FXExposureReport{
+		GeneratedAt: now,
+		Pairs:       make([]PairExposure, 0, len(pairs)),
+		Currencies:  make([]CurrencyExposure, 0, len(net)),
+	}
+	for _, pair := range pairs {
+		pair.SourceAmount = roundMoney(pair.SourceAmount)
+		pair.TargetAmount = roundMoney(pair.TargetAmount)
+		report.Pairs = append(report.Pairs, *pair)
+	}
+	sort.Slice(report.Pairs, func(i, j int) bool {
+		a, b := report.Pairs[i], report.Pairs[j]
+		if a.SourceCurrency != b.SourceCurrency {
+			return a.SourceCurrency < b.SourceCurrency
+		}
+		return a.TargetCurrency < b.TargetCurrency
+	})
+	for currency, amount := range net {
+		report.Currencies = append(report.Currencies, CurrencyExposure{Currency: currency, Net: roundMoney(amount)})
+	}
+	sort.Slice(report.Currencies, func(i, j int) bool {
+		return report.Currencies[i].Currency < report.Currencies[j].Currency
+	})
+	return report, nil
+}
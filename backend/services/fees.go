@@ -1,19 +1,45 @@
 package services
 
 import (
+	"errors"
+	"fmt"
 	"math"
+	"strings"
 
 	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/logger"
 )
 
+// ErrFeeFloorExceedsAmount is returned when the minimum fee would consume more
+// of the remittance than the configured MinFeeMaxRatio allows.
+var ErrFeeFloorExceedsAmount = errors.New("minimum fee exceeds the allowed share of the amount")
+
+// ErrInvalidMonetaryValue is returned when an amount, rate, or intermediate
+// result is NaN, infinite, negative, or too large to settle on Stellar.
+var ErrInvalidMonetaryValue = errors.New("invalid monetary value")
+
+// MaxAmount is the largest amount representable on Stellar, which stores
+// amounts as int64 stroops (1e-7 units).
+const MaxAmount = 922337203685.4775807
+
 type FeeBreakdown struct {
 	PlatformFee   float64 `json:"platform_fee"`
 	ForexFee      float64 `json:"forex_fee"`
 	ComplianceFee float64 `json:"compliance_fee"`
 	NetworkFee    float64 `json:"network_fee"`
 	TotalFee      float64 `json:"total_fee"`
+	// FloorApplied reports whether the total was raised to the minimum fee.
+	FloorApplied bool `json:"floor_applied,omitempty"`
+	// Corridor is the country corridor the fees were priced for, or
+	// DefaultCorridor when its pair has no pricing of its own. It is empty
+	// for fees calculated without a corridor.
+	Corridor string `json:"corridor,omitempty"`
 }
 
+// DefaultCorridor is reported for a remittance between countries that have
+// no corridor pricing configured; it pays the base fees alone.
+const DefaultCorridor = "default"
+
 type FeeService struct {
 	cfg *config.Config
 }
@@ -33,37 +59,146 @@ func roundMoney(v float64) float64 {
 
 // Calculate returns a fee breakdown. Fee config is intended to mirror the on-chain
 // escrow contract fee structure (PaymentEscrow).
-func (s *FeeService) Calculate(amount float64) FeeBreakdown {
+//
+// The minimum fee floor is applied after rounding so that a percentage fee
+// which rounds below the floor still carries the full floor amount.
+func (s *FeeService) Calculate(amount float64) (FeeBreakdown, error) {
+	if err := checkAmount("amount", amount); err != nil {
+		return FeeBreakdown{}, err
+	}
+
 	platform := bps(amount, s.cfg.PlatformFeeBps)
 	forex := bps(amount, s.cfg.ForexFeeBps)
 	compliance := bps(amount, s.cfg.ComplianceFeeBps)
 	network := bps(amount, s.cfg.NetworkFeeBps)
 
 	total := platform + forex + compliance + network
-
-	if s.cfg.MinFee > 0 && total < s.cfg.MinFee {
-		total = s.cfg.MinFee
+	if err := checkFees(platform, forex, compliance, network, total); err != nil {
+		return FeeBreakdown{}, err
 	}
+
 	if s.cfg.MaxFee > 0 && total > s.cfg.MaxFee {
+		scaleComponents(s.cfg.MaxFee/total, &platform, &forex, &compliance, &network)
 		total = s.cfg.MaxFee
 	}
 
-	// When min/max clamps apply, preserve relative components but keep total stable.
-	// If no clamp applied, the sum equals total already.
-	sum := platform + forex + compliance + network
-	if sum > 0 && total != sum {
-		ratio := total / sum
-		platform *= ratio
-		forex *= ratio
-		compliance *= ratio
-		network *= ratio
-	}
-
-	return FeeBreakdown{
+	breakdown := FeeBreakdown{
 		PlatformFee:   roundMoney(platform),
 		ForexFee:      roundMoney(forex),
 		ComplianceFee: roundMoney(compliance),
 		NetworkFee:    roundMoney(network),
 		TotalFee:      roundMoney(total),
 	}
+
+	floor := math.Ceil(s.cfg.MinFee*100) / 100
+	if floor > 0 && breakdown.TotalFee < floor {
+		if s.cfg.MinFeeMaxRatio > 0 && floor > amount*s.cfg.MinFeeMaxRatio {
+			return FeeBreakdown{}, fmt.Errorf("%w: fee %.2f on amount %.2f", ErrFeeFloorExceedsAmount, floor, amount)
+		}
+
+		// Preserve the relative split of the components; the platform fee absorbs
+		// any rounding remainder so the components always sum to the total.
+		sum := platform + forex + compliance + network
+		if sum > 0 {
+			scaleComponents(floor/sum, &platform, &forex, &compliance, &network)
+			breakdown.ForexFee = roundMoney(forex)
+			breakdown.ComplianceFee = roundMoney(compliance)
+			breakdown.NetworkFee = roundMoney(network)
+		}
+		breakdown.PlatformFee = roundMoney(floor - breakdown.ForexFee - breakdown.ComplianceFee - breakdown.NetworkFee)
+		breakdown.TotalFee = floor
+		breakdown.FloorApplied = true
+	}
+
+	if err := checkFees(breakdown.PlatformFee, breakdown.ForexFee, breakdown.ComplianceFee, breakdown.NetworkFee, breakdown.TotalFee); err != nil {
+		return FeeBreakdown{}, err
+	}
+
+	return breakdown, nil
+}
+
+// CorridorKey names the corridor from one country to another, e.g. "US-MX".
+func CorridorKey(from, to string) string {
+	return strings.ToUpper(strings.TrimSpace(from)) + "-" + strings.ToUpper(strings.TrimSpace(to))
+}
+
+// CalculateForCorridor returns Calculate's breakdown for amount with the
+// pricing of the corridor between the sender's and recipient's countries
+// layered on top: its FX markup is added to the forex fee and its fixed fee
+// to the platform fee, after the base fee's floor and cap. A pair with no
+// corridor configured, including one where either country is unknown, falls
+// back to the base fees and is reported as DefaultCorridor.
+func (s *FeeService) CalculateForCorridor(amount float64, from, to string) (FeeBreakdown, error) {
+	breakdown, err := s.Calculate(amount)
+	if err != nil {
+		return FeeBreakdown{}, err
+	}
+
+	key := CorridorKey(from, to)
+	corridor, ok := s.cfg.Corridors[key]
+	if !ok {
+		log := logger.Log.WithField("corridor", key)
+		if len(s.cfg.Corridors) > 0 {
+			log.Info("No pricing configured for corridor; using the default fees")
+		} else {
+			log.Debug("Corridor pricing is not configured; using the default fees")
+		}
+		breakdown.Corridor = DefaultCorridor
+		return breakdown, nil
+	}
+
+	markup := roundMoney(bps(amount, corridor.FXMarkupBps))
+	fixed := roundMoney(corridor.FixedFee)
+	breakdown.ForexFee = roundMoney(breakdown.ForexFee + markup)
+	breakdown.PlatformFee = roundMoney(breakdown.PlatformFee + fixed)
+	breakdown.TotalFee = roundMoney(breakdown.TotalFee + markup + fixed)
+	breakdown.Corridor = key
+	if err := checkFees(breakdown.PlatformFee, breakdown.ForexFee, breakdown.ComplianceFee, breakdown.NetworkFee, breakdown.TotalFee); err != nil {
+		return FeeBreakdown{}, err
+	}
+	return breakdown, nil
+}
+
+func scaleComponents(ratio float64, components ...*float64) {
+	for _, c := range components {
+		*c *= ratio
+	}
+}
+
+// checkAmount rejects amounts that cannot be stored or settled: non-finite,
+// non-positive, or beyond what fits in int64 stroops.
+func checkAmount(name string, v float64) error {
+	if err := checkMoney(name, v); err != nil {
+		return err
+	}
+	if v == 0 {
+		return fmt.Errorf("%w: %s must be greater than zero", ErrInvalidMonetaryValue, name)
+	}
+	if v > MaxAmount {
+		return fmt.Errorf("%w: %s exceeds the maximum of %.7f", ErrInvalidMonetaryValue, name, MaxAmount)
+	}
+	return nil
+}
+
+// checkFees validates the fee components and total, in that order.
+func checkFees(platform, forex, compliance, network, total float64) error {
+	names := []string{"platform fee", "forex fee", "compliance fee", "network fee", "total fee"}
+	for i, v := range []float64{platform, forex, compliance, network, total} {
+		if err := checkMoney(names[i], v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkMoney rejects a value that is NaN, infinite, or negative, so a corrupt
+// intermediate result is never stored.
+func checkMoney(name string, v float64) error {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return fmt.Errorf("%w: %s is not a finite number", ErrInvalidMonetaryValue, name)
+	}
+	if v < 0 {
+		return fmt.Errorf("%w: %s is negative", ErrInvalidMonetaryValue, name)
+	}
+	return nil
 }
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/gpay-remit/config"
+)
+
+func newTestFeeService(minFee, maxRatio float64) *FeeService {
+	return NewFeeService(&config.Config{
+		PlatformFeeBps:   50,
+		ForexFeeBps:      25,
+		ComplianceFeeBps: 10,
+		NetworkFeeBps:    15,
+		MinFee:           minFee,
+		MinFeeMaxRatio:   maxRatio,
+	})
+}
+
+func TestCalculate_PercentageFee(t *testing.T) {
+	breakdown, err := newTestFeeService(0, 0).Calculate(1000)
+	assert.NoError(t, err)
+	assert.Equal(t, 10.0, breakdown.TotalFee)
+	assert.Equal(t, 5.0, breakdown.PlatformFee)
+	assert.False(t, breakdown.FloorApplied)
+}
+
+func TestCalculate_SmallAmountRaisedToFloor(t *testing.T) {
+	// 1% of 0.40 rounds to 0.00 — the floor must still apply in full.
+	breakdown, err := newTestFeeService(0.5, 0).Calculate(0.40)
+	assert.NoError(t, err)
+	assert.True(t, breakdown.FloorApplied)
+	assert.Equal(t, 0.5, breakdown.TotalFee)
+
+	sum := breakdown.PlatformFee + breakdown.ForexFee + breakdown.ComplianceFee + breakdown.NetworkFee
+	assert.InDelta(t, breakdown.TotalFee, sum, 1e-9)
+}
+
+func TestCalculate_FloorRoundsUpToCents(t *testing.T) {
+	breakdown, err := newTestFeeService(0.004, 0).Calculate(0.10)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.01, breakdown.TotalFee)
+}
+
+func TestCalculate_FloorExceedingRatioRejected(t *testing.T) {
+	_, err := newTestFeeService(1, 0.5).Calculate(1.5)
+	assert.ErrorIs(t, err, ErrFeeFloorExceedsAmount)
+
+	breakdown, err := newTestFeeService(1, 0.5).Calculate(2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, breakdown.TotalFee)
+}
+
+func TestCalculate_RejectsInvalidAmounts(t *testing.T) {
+	service := newTestFeeService(0.5, 0)
+	for name, amount := range map[string]float64{
+		"NaN":          math.NaN(),
+		"+Inf":         math.Inf(1),
+		"-Inf":         math.Inf(-1),
+		"negative":     -100,
+		"zero":         0,
+		"max float":    math.MaxFloat64,
+		"above stroop": MaxAmount * 2,
+	} {
+		t.Run(name, func(t *testing.T) {
+			breakdown, err := service.Calculate(amount)
+			assert.ErrorIs(t, err, ErrInvalidMonetaryValue)
+			assert.Equal(t, FeeBreakdown{}, breakdown)
+		})
+	}
+}
+
+func TestCalculate_MaxAmountAccepted(t *testing.T) {
+	breakdown, err := newTestFeeService(0, 0).Calculate(MaxAmount)
+	assert.NoError(t, err)
+	assert.False(t, math.IsInf(breakdown.TotalFee, 0))
+	assert.Greater(t, breakdown.TotalFee, 0.0)
+}
+
+func TestCalculate_RejectsNegativeFeeRates(t *testing.T) {
+	service := NewFeeService(&config.Config{PlatformFeeBps: -500, ForexFeeBps: 25})
+	_, err := service.Calculate(1000)
+	assert.ErrorIs(t, err, ErrInvalidMonetaryValue)
+}
+
+func newCorridorFeeService() *FeeService {
+	service := newTestFeeService(0, 0)
+	service.cfg.Corridors = map[string]config.Corridor{
+		"US-MX": {FXMarkupBps: 40, FixedFee: 1.5},
+		"US-PH": {FXMarkupBps: 120},
+	}
+	return service
+}
+
+func TestCalculateForCorridor_ConfiguredCorridor(t *testing.T) {
+	base, err := newTestFeeService(0, 0).Calculate(1000)
+	assert.NoError(t, err)
+
+	breakdown, err := newCorridorFeeService().CalculateForCorridor(1000, "us", " mx")
+	assert.NoError(t, err)
+	assert.Equal(t, "US-MX", breakdown.Corridor)
+	// 40 bps of 1000 is 4.00 on top of the 2.50 forex fee; the fixed 1.50 is
+	// added to the platform fee.
+	assert.Equal(t, base.ForexFee+4, breakdown.ForexFee)
+	assert.Equal(t, base.PlatformFee+1.5, breakdown.PlatformFee)
+	assert.Equal(t, base.ComplianceFee, breakdown.ComplianceFee)
+	assert.Equal(t, base.TotalFee+5.5, breakdown.TotalFee)
+}
+
+func TestCalculateForCorridor_MarkupMath(t *testing.T) {
+	// 120 bps of 333.33 is 3.99996, which rounds to 4.00.
+	breakdown, err := newCorridorFeeService().CalculateForCorridor(333.33, "US", "PH")
+	assert.NoError(t, err)
+	base, _ := newTestFeeService(0, 0).Calculate(333.33)
+	assert.InDelta(t, base.ForexFee+4, breakdown.ForexFee, 1e-9)
+	assert.Equal(t, base.PlatformFee, breakdown.PlatformFee)
+
+	sum := breakdown.PlatformFee + breakdown.ForexFee + breakdown.ComplianceFee + breakdown.NetworkFee
+	assert.InDelta(t, breakdown.TotalFee, sum, 0.011)
+}
+
+func TestCalculateForCorridor_DefaultFallback(t *testing.T) {
+	base, _ := newTestFeeService(0, 0).Calculate(1000)
+	for name, pair := range map[string][2]string{
+		"unlisted corridor":         {"MX", "US"},
+		"unknown recipient country": {"US", ""},
+	} {
+		breakdown, err := newCorridorFeeService().CalculateForCorridor(1000, pair[0], pair[1])
+		assert.NoError(t, err, name)
+		assert.Equal(t, DefaultCorridor, breakdown.Corridor, name)
+		assert.Equal(t, base.TotalFee, breakdown.TotalFee, name)
+	}
+}
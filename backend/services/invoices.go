@@ -0,0 +1,316 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrPaymentNotFailed is returned when voiding is requested for a payment that
+// has not failed.
+var ErrPaymentNotFailed = errors.New("linked payment has not failed")
+
+// DefaultVoidReason is recorded when an invoice is voided without an explicit reason.
+const DefaultVoidReason = "linked payment failed"
+
+// ErrInvoiceNotOpen is returned when cancelling an invoice that is no longer
+// unpaid or overdue.
+var ErrInvoiceNotOpen = errors.New("invoice is not open")
+
+// DefaultCancelReason is recorded when an invoice is cancelled without an
+// explicit reason.
+const DefaultCancelReason = "cancelled by issuer"
+
+// ErrPaymentNotCompleted is returned when invoicing a payment that has not
+// completed.
+var ErrPaymentNotCompleted = errors.New("payment has not completed")
+
+// InvoiceSequenceName names the sequence that generated invoice numbers are
+// drawn from.
+const InvoiceSequenceName = "invoice"
+
+type InvoiceService struct {
+	db *gorm.DB
+}
+
+func NewInvoiceService(db *gorm.DB) *InvoiceService {
+	return &InvoiceService{db: db}
+}
+
+// IssueInvoice prepares a new invoice's document before it is created: it
+// copies the issuer's and recipient's current details onto the invoice and
+// records the hash of the PDF they render to, so VerifyInvoicePDF can later
+// tell whether the invoice still matches the document issued.
+func IssueInvoice(tx *gorm.DB, invoice *models.Invoice) error {
+	var err error
+	invoice.IssuerName, invoice.IssuerEmail, invoice.IssuerStellarAddress, err = invoiceParty(tx, invoice.IssuerID)
+	if err != nil {
+		return err
+	}
+	invoice.RecipientName, invoice.RecipientEmail, invoice.RecipientStellarAddress, err = invoiceParty(tx, invoice.RecipientID)
+	if err != nil {
+		return err
+	}
+
+	// The PDF prints the issue date, so fix it now rather than leave it to
+	// the insert.
+	if invoice.CreatedAt.IsZero() {
+		invoice.CreatedAt = time.Now()
+	}
+	pdf, err := utils.RenderInvoicePDF(invoice)
+	if err != nil {
+		return err
+	}
+	invoice.PdfHash = utils.InvoicePDFHash(pdf)
+	return nil
+}
+
+// invoiceParty returns the name, email and Stellar address of an invoice
+// participant, falling back to the bare user ID if the user does not exist.
+func invoiceParty(tx *gorm.DB, userID uint) (name, email, address string, err error) {
+	var user models.User
+	if err := tx.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Sprintf("User #%d", userID), "", "", nil
+		}
+		return "", "", "", fmt.Errorf("failed to load invoice party %d: %w", userID, err)
+	}
+	return user.Name, user.Email, user.StellarAddress, nil
+}
+
+// VoidForFailedPayment cancels every still-open invoice linked to the given
+// payment, provided the payment has failed. It returns the number of invoices voided.
+func (s *InvoiceService) VoidForFailedPayment(paymentID uint, reason string) (int64, error) {
+	var payment models.Payment
+	if err := s.db.First(&payment, paymentID).Error; err != nil {
+		return 0, fmt.Errorf("failed to load payment: %w", err)
+	}
+	if payment.Status != "failed" {
+		return 0, ErrPaymentNotFailed
+	}
+
+	if reason == "" {
+		reason = DefaultVoidReason
+	}
+
+	now := time.Now()
+	result := s.db.Model(&models.Invoice{}).
+		Where("payment_id = ? AND status IN ?", paymentID, []string{"unpaid", "overdue"}).
+		Updates(map[string]interface{}{
+			"status":              "cancelled",
+			"cancellation_reason": reason,
+			"cancelled_at":        now,
+		})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to void invoices: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// Cancel moves an open invoice to cancelled. The update is guarded on the
+// open statuses, so an invoice paid concurrently is left alone and
+// ErrInvoiceNotOpen is returned. A cancelled invoice is never marked paid,
+// since every path that pays one requires it to be open.
+func (s *InvoiceService) Cancel(invoice *models.Invoice, reason string) error {
+	if reason == "" {
+		reason = DefaultCancelReason
+	}
+
+	now := time.Now()
+	result := s.db.Model(&models.Invoice{}).
+		Where("id = ? AND status IN ?", invoice.ID, []string{"unpaid", "overdue"}).
+		Updates(map[string]interface{}{
+			"status":              "cancelled",
+			"cancellation_reason": reason,
+			"cancelled_at":        now,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to cancel invoice: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvoiceNotOpen
+	}
+
+	invoice.Status = "cancelled"
+	invoice.CancellationReason = reason
+	invoice.CancelledAt = &now
+	return nil
+}
+
+// MarkPaidForPayment marks open invoices linked to a completed payment as paid.
+// An invoice is only settled when the payment covers its full amount in the
+// invoice currency; partial payments leave it open. It returns the number of
+// invoices marked paid.
+func (s *InvoiceService) MarkPaidForPayment(payment *models.Payment) (int, error) {
+	if payment.Status != "completed" {
+		return 0, nil
+	}
+
+	var invoices []models.Invoice
+	if err := s.db.Where("payment_id = ? AND status IN ?", payment.ID, []string{"unpaid", "overdue"}).
+		Find(&invoices).Error; err != nil {
+		return 0, fmt.Errorf("failed to fetch linked invoices: %w", err)
+	}
+
+	now := time.Now()
+	paid := 0
+	for _, invoice := range invoices {
+		if !paymentCoversInvoice(payment, &invoice) {
+			continue
+		}
+		if err := s.db.Model(&invoice).Updates(map[string]interface{}{
+			"status":  "paid",
+			"paid_at": now,
+		}).Error; err != nil {
+			return paid, fmt.Errorf("failed to mark invoice %d paid: %w", invoice.ID, err)
+		}
+		paid++
+	}
+	return paid, nil
+}
+
+// MarkOverdue flags unpaid invoices whose due date has passed. It returns the
+// number of invoices transitioned.
+func (s *InvoiceService) MarkOverdue(now time.Time) (int64, error) {
+	result := s.db.Model(&models.Invoice{}).
+		Where("status = ? AND due_date IS NOT NULL AND due_date < ?", "unpaid", now).
+		Update("status", "overdue")
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to mark overdue invoices: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// BatchInvoiceResult reports the outcome of GenerateForPayments.
+type BatchInvoiceResult struct {
+	Invoices []models.Invoice
+	// Skipped lists the payments left alone because they already had an
+	// invoice.
+	Skipped []uint
+}
+
+// InvoiceNumbers returns the numbers of the generated invoices, in order.
+func (r *BatchInvoiceResult) InvoiceNumbers() []string {
+	numbers := make([]string, len(r.Invoices))
+	for i, invoice := range r.Invoices {
+		numbers[i] = invoice.InvoiceNo
+	}
+	return numbers
+}
+
+// GenerateForPayments issues one unpaid invoice per completed payment, billed
+// by the sender to the recipient for the payment amount, all sharing the
+// description and due date. Numbers are drawn consecutively from the shared
+// invoice sequence. Payments that already have an invoice are skipped, so
+// repeating a call is harmless.
+//
+// Everything happens in one transaction: if any invoice cannot be created,
+// none are and the sequence is left as it was. The sequence row is locked
+// before existing invoices are looked up, so concurrent calls run one after
+// the other and never draw the same numbers or invoice the same payment twice.
+func (s *InvoiceService) GenerateForPayments(payments []models.Payment, description string, dueDate *time.Time) (*BatchInvoiceResult, error) {
+	ids := make([]uint, len(payments))
+	for i, payment := range payments {
+		if payment.Status != "completed" {
+			return nil, fmt.Errorf("payment %d: %w", payment.ID, ErrPaymentNotCompleted)
+		}
+		ids[i] = payment.ID
+	}
+
+	result := &BatchInvoiceResult{Invoices: []models.Invoice{}, Skipped: []uint{}}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		seq, err := lockInvoiceSequence(tx, InvoiceSequenceName)
+		if err != nil {
+			return err
+		}
+
+		var invoiced []uint
+		if err := tx.Model(&models.Invoice{}).Where("payment_id IN ?", ids).
+			Pluck("payment_id", &invoiced).Error; err != nil {
+			return fmt.Errorf("failed to fetch existing invoices: %w", err)
+		}
+		skip := make(map[uint]bool, len(invoiced))
+		for _, id := range invoiced {
+			skip[id] = true
+		}
+
+		next := seq.Value
+		for _, payment := range payments {
+			if skip[payment.ID] {
+				result.Skipped = append(result.Skipped, payment.ID)
+				continue
+			}
+			skip[payment.ID] = true
+
+			next++
+			invoice := models.Invoice{
+				PaymentID:   payment.ID,
+				InvoiceNo:   invoiceNumber(next),
+				IssuerID:    payment.SenderID,
+				RecipientID: payment.RecipientID,
+				Amount:      payment.Amount,
+				Currency:    payment.Currency,
+				DueDate:     dueDate,
+				Status:      "unpaid",
+				Description: description,
+			}
+			if err := IssueInvoice(tx, &invoice); err != nil {
+				return fmt.Errorf("failed to issue invoice for payment %d: %w", payment.ID, err)
+			}
+			if err := tx.Create(&invoice).Error; err != nil {
+				return fmt.Errorf("failed to create invoice for payment %d: %w", payment.ID, err)
+			}
+			result.Invoices = append(result.Invoices, invoice)
+		}
+
+		if next == seq.Value {
+			return nil
+		}
+		if err := tx.Model(seq).Update("value", next).Error; err != nil {
+			return fmt.Errorf("failed to advance invoice sequence: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// lockInvoiceSequence loads the named sequence, creating it if the schema has
+// not been seeded, and holds its row lock until the transaction ends.
+func lockInvoiceSequence(tx *gorm.DB, name string) (*models.InvoiceSequence, error) {
+	seq := models.InvoiceSequence{Name: name}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&seq).Error; err != nil {
+		return nil, fmt.Errorf("failed to create invoice sequence: %w", err)
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&seq, "name = ?", name).Error; err != nil {
+		return nil, fmt.Errorf("failed to lock invoice sequence: %w", err)
+	}
+	return &seq, nil
+}
+
+// invoiceNumber formats the nth number of the invoice sequence.
+func invoiceNumber(n int64) string {
+	return fmt.Sprintf("INV-%08d", n)
+}
+
+// paymentCoversInvoice reports whether the settled amount of a payment, in the
+// invoice currency, is at least the invoiced amount.
+func paymentCoversInvoice(payment *models.Payment, invoice *models.Invoice) bool {
+	switch invoice.Currency {
+	case payment.Currency:
+		return payment.Amount >= invoice.Amount
+	case payment.TargetCurrency:
+		return payment.ConvertedAmount >= invoice.Amount
+	default:
+		return false
+	}
+}
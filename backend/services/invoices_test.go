@@ -0,0 +1,243 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/models"
+)
+
+func TestVoidForFailedPayment(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Invoice{}))
+
+	failed := models.Payment{SenderID: 1, RecipientID: 2, Amount: 100, Currency: "USD", Status: "failed"}
+	other := models.Payment{SenderID: 1, RecipientID: 3, Amount: 50, Currency: "USD", Status: "pending"}
+	require.NoError(t, db.Create(&failed).Error)
+	require.NoError(t, db.Create(&other).Error)
+
+	linked := models.Invoice{PaymentID: failed.ID, InvoiceNo: "INV-1", IssuerID: 1, RecipientID: 2, Amount: 100, Currency: "USD", Status: "unpaid"}
+	unrelated := models.Invoice{PaymentID: other.ID, InvoiceNo: "INV-2", IssuerID: 1, RecipientID: 3, Amount: 50, Currency: "USD", Status: "unpaid"}
+	require.NoError(t, db.Create(&linked).Error)
+	require.NoError(t, db.Create(&unrelated).Error)
+
+	service := NewInvoiceService(db)
+
+	t.Run("Failed payment voids linked invoice", func(t *testing.T) {
+		count, err := service.VoidForFailedPayment(failed.ID, "")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		var got models.Invoice
+		db.First(&got, linked.ID)
+		assert.Equal(t, "cancelled", got.Status)
+		assert.Equal(t, DefaultVoidReason, got.CancellationReason)
+		assert.NotNil(t, got.CancelledAt)
+	})
+
+	t.Run("Unrelated invoice untouched", func(t *testing.T) {
+		var got models.Invoice
+		db.First(&got, unrelated.ID)
+		assert.Equal(t, "unpaid", got.Status)
+		assert.Empty(t, got.CancellationReason)
+	})
+
+	t.Run("Non-failed payment rejected", func(t *testing.T) {
+		_, err := service.VoidForFailedPayment(other.ID, "manual")
+		assert.ErrorIs(t, err, ErrPaymentNotFailed)
+	})
+}
+
+func TestCancelInvoice(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Invoice{}))
+	service := NewInvoiceService(db)
+
+	t.Run("Open invoice is cancelled and can no longer be paid", func(t *testing.T) {
+		payment := models.Payment{SenderID: 2, RecipientID: 1, Amount: 100, Currency: "USD", Status: "completed"}
+		require.NoError(t, db.Create(&payment).Error)
+		invoice := models.Invoice{PaymentID: payment.ID, InvoiceNo: "INV-CANCEL-1", IssuerID: 1, RecipientID: 2, Amount: 100, Currency: "USD", Status: "overdue"}
+		require.NoError(t, db.Create(&invoice).Error)
+
+		require.NoError(t, service.Cancel(&invoice, ""))
+		assert.Equal(t, "cancelled", invoice.Status)
+		assert.Equal(t, DefaultCancelReason, invoice.CancellationReason)
+
+		paid, err := service.MarkPaidForPayment(&payment)
+		require.NoError(t, err)
+		assert.Zero(t, paid)
+		var got models.Invoice
+		db.First(&got, invoice.ID)
+		assert.Equal(t, "cancelled", got.Status)
+		assert.NotNil(t, got.CancelledAt)
+	})
+
+	t.Run("Paid invoice is left alone", func(t *testing.T) {
+		invoice := models.Invoice{InvoiceNo: "INV-CANCEL-2", IssuerID: 1, RecipientID: 2, Amount: 10, Currency: "USD", Status: "paid"}
+		require.NoError(t, db.Create(&invoice).Error)
+
+		assert.ErrorIs(t, service.Cancel(&invoice, "too late"), ErrInvoiceNotOpen)
+		var got models.Invoice
+		db.First(&got, invoice.ID)
+		assert.Equal(t, "paid", got.Status)
+		assert.Empty(t, got.CancellationReason)
+	})
+}
+
+func TestMarkPaidForPayment(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Invoice{}))
+
+	full := models.Payment{SenderID: 1, RecipientID: 2, Amount: 100, Currency: "USD", Status: "completed"}
+	partial := models.Payment{SenderID: 1, RecipientID: 2, Amount: 40, Currency: "USD", Status: "completed"}
+	require.NoError(t, db.Create(&full).Error)
+	require.NoError(t, db.Create(&partial).Error)
+
+	fullInvoice := models.Invoice{PaymentID: full.ID, InvoiceNo: "INV-FULL", IssuerID: 2, RecipientID: 1, Amount: 100, Currency: "USD", Status: "unpaid"}
+	partialInvoice := models.Invoice{PaymentID: partial.ID, InvoiceNo: "INV-PARTIAL", IssuerID: 2, RecipientID: 1, Amount: 100, Currency: "USD", Status: "unpaid"}
+	require.NoError(t, db.Create(&fullInvoice).Error)
+	require.NoError(t, db.Create(&partialInvoice).Error)
+
+	service := NewInvoiceService(db)
+
+	t.Run("Completed payment marks invoice paid", func(t *testing.T) {
+		count, err := service.MarkPaidForPayment(&full)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		var got models.Invoice
+		db.First(&got, fullInvoice.ID)
+		assert.Equal(t, "paid", got.Status)
+		assert.NotNil(t, got.PaidAt)
+	})
+
+	t.Run("Partial payment leaves invoice open", func(t *testing.T) {
+		count, err := service.MarkPaidForPayment(&partial)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+
+		var got models.Invoice
+		db.First(&got, partialInvoice.ID)
+		assert.Equal(t, "unpaid", got.Status)
+		assert.Nil(t, got.PaidAt)
+	})
+}
+
+func TestMarkOverdue(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Invoice{}))
+
+	now := time.Now()
+	past := now.Add(-24 * time.Hour)
+	future := now.Add(24 * time.Hour)
+	invoices := []models.Invoice{
+		{PaymentID: 1, InvoiceNo: "INV-PAST", IssuerID: 1, RecipientID: 2, Amount: 10, Currency: "USD", Status: "unpaid", DueDate: &past},
+		{PaymentID: 2, InvoiceNo: "INV-FUTURE", IssuerID: 1, RecipientID: 2, Amount: 10, Currency: "USD", Status: "unpaid", DueDate: &future},
+		{PaymentID: 3, InvoiceNo: "INV-NODUE", IssuerID: 1, RecipientID: 2, Amount: 10, Currency: "USD", Status: "unpaid"},
+		{PaymentID: 4, InvoiceNo: "INV-PAID", IssuerID: 1, RecipientID: 2, Amount: 10, Currency: "USD", Status: "paid", DueDate: &past},
+	}
+	for i := range invoices {
+		require.NoError(t, db.Create(&invoices[i]).Error)
+	}
+
+	count, err := NewInvoiceService(db).MarkOverdue(now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	expected := []string{"overdue", "unpaid", "unpaid", "paid"}
+	for i, invoice := range invoices {
+		var got models.Invoice
+		db.First(&got, invoice.ID)
+		assert.Equal(t, expected[i], got.Status, invoice.InvoiceNo)
+	}
+}
+
+func TestGenerateForPayments(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Invoice{}, &models.InvoiceSequence{}, &models.User{}))
+	sender := models.User{Email: "sender@example.com", Name: "Sender Ltd", StellarAddress: "GSENDER", PasswordHash: "x"}
+	require.NoError(t, db.Create(&sender).Error)
+
+	payments := make([]models.Payment, 5)
+	for i := range payments {
+		payments[i] = models.Payment{SenderID: sender.ID, RecipientID: uint(10 + i), Amount: float64(100 * (i + 1)), Currency: "USD", Status: "completed", BatchID: "batch-1"}
+		require.NoError(t, db.Create(&payments[i]).Error)
+	}
+	service := NewInvoiceService(db)
+	due := time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second)
+
+	sequence := func() int64 {
+		var seq models.InvoiceSequence
+		db.First(&seq, "name = ?", InvoiceSequenceName)
+		return seq.Value
+	}
+
+	t.Run("Invoices three payments with consecutive numbers", func(t *testing.T) {
+		result, err := service.GenerateForPayments(payments[:3], "March payouts", &due)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"INV-00000001", "INV-00000002", "INV-00000003"}, result.InvoiceNumbers())
+		assert.Empty(t, result.Skipped)
+		assert.Equal(t, int64(3), sequence())
+
+		var got []models.Invoice
+		db.Order("id").Find(&got)
+		require.Len(t, got, 3)
+		for i, invoice := range got {
+			assert.Equal(t, payments[i].ID, invoice.PaymentID)
+			assert.Equal(t, sender.ID, invoice.IssuerID)
+			assert.Equal(t, payments[i].RecipientID, invoice.RecipientID)
+			assert.Equal(t, payments[i].Amount, invoice.Amount)
+			assert.Equal(t, "March payouts", invoice.Description)
+			assert.Equal(t, "unpaid", invoice.Status)
+			assert.Equal(t, "Sender Ltd", invoice.IssuerName)
+			assert.Equal(t, "GSENDER", invoice.IssuerStellarAddress)
+			assert.Equal(t, fmt.Sprintf("User #%d", payments[i].RecipientID), invoice.RecipientName)
+			assert.Len(t, invoice.PdfHash, 64)
+			if assert.NotNil(t, invoice.DueDate) {
+				assert.True(t, due.Equal(*invoice.DueDate))
+			}
+		}
+	})
+
+	t.Run("Skips payments already invoiced", func(t *testing.T) {
+		result, err := service.GenerateForPayments(payments[:4], "March payouts", nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"INV-00000004"}, result.InvoiceNumbers())
+		assert.Equal(t, []uint{payments[0].ID, payments[1].ID, payments[2].ID}, result.Skipped)
+
+		result, err = service.GenerateForPayments(payments[:4], "March payouts", nil)
+		require.NoError(t, err)
+		assert.Empty(t, result.Invoices)
+		assert.Len(t, result.Skipped, 4)
+		assert.Equal(t, int64(4), sequence())
+	})
+
+	t.Run("Rolls back when an invoice cannot be created", func(t *testing.T) {
+		// Another invoice already holds the number the second payment would get.
+		other := models.Payment{SenderID: 2, RecipientID: 3, Amount: 5, Currency: "USD", Status: "completed"}
+		require.NoError(t, db.Create(&other).Error)
+		require.NoError(t, db.Create(&models.Invoice{PaymentID: other.ID, InvoiceNo: "INV-00000006", IssuerID: 2, RecipientID: 3, Amount: 5, Currency: "USD"}).Error)
+
+		extra := models.Payment{SenderID: 1, RecipientID: 20, Amount: 60, Currency: "USD", Status: "completed"}
+		require.NoError(t, db.Create(&extra).Error)
+
+		_, err := service.GenerateForPayments([]models.Payment{payments[4], extra}, "March payouts", nil)
+		assert.Error(t, err)
+
+		var count int64
+		db.Model(&models.Invoice{}).Where("payment_id IN ?", []uint{payments[4].ID, extra.ID}).Count(&count)
+		assert.Zero(t, count)
+		assert.Equal(t, int64(4), sequence())
+	})
+
+	t.Run("Rejects a payment that has not completed", func(t *testing.T) {
+		pending := models.Payment{SenderID: 1, RecipientID: 30, Amount: 5, Currency: "USD", Status: "pending"}
+		require.NoError(t, db.Create(&pending).Error)
+
+		_, err := service.GenerateForPayments([]models.Payment{pending}, "", nil)
+		assert.ErrorIs(t, err, ErrPaymentNotCompleted)
+	})
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"time"
+
+	"github.com/yourusername/gpay-remit/models"
+)
+
+// KYC statuses stored on models.User.KYCStatus.
+const (
+	KYCStatusPending  = "pending"
+	KYCStatusVerified = "verified"
+	KYCStatusRejected = "rejected"
+)
+
+// KYCRequirement is something the user still has to provide before their
+// account can move to a higher tier.
+type KYCRequirement struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+// KYCLimits are the sending limits for a tier, in the user's default currency.
+// Zero means sending is not allowed at that tier.
+type KYCLimits struct {
+	PerTransaction float64 `json:"per_transaction"`
+	Daily          float64 `json:"daily"`
+}
+
+// KYCTier describes what a user at a given verification level may do.
+type KYCTier struct {
+	Name   string    `json:"name"`
+	Level  int       `json:"level"`
+	Limits KYCLimits `json:"limits"`
+}
+
+var (
+	// UnverifiedTier applies to users whose identity has not been verified.
+	UnverifiedTier = KYCTier{Name: "unverified", Level: 0}
+	// VerifiedTier applies once identity verification has been approved.
+	VerifiedTier = KYCTier{Name: "verified", Level: 1, Limits: KYCLimits{PerTransaction: 10000, Daily: 50000}}
+)
+
+// KYCSummary is the user-facing view of a user's verification state.
+type KYCSummary struct {
+	Status       string           `json:"kyc_status"`
+	VerifiedAt   *time.Time       `json:"kyc_verified_at"`
+	Tier         KYCTier          `json:"tier"`
+	CanSend      bool             `json:"can_send"`
+	Requirements []KYCRequirement `json:"requirements"`
+}
+
+// SummarizeKYC derives the user's tier and the requirements still
+// outstanding from their KYC status.
+func SummarizeKYC(user *models.User) KYCSummary {
+	summary := KYCSummary{
+		Status:       user.KYCStatus,
+		VerifiedAt:   user.KYCVerifiedAt,
+		Tier:         UnverifiedTier,
+		Requirements: []KYCRequirement{},
+	}
+
+	switch user.KYCStatus {
+	case KYCStatusVerified:
+		summary.Tier = VerifiedTier
+	case KYCStatusRejected:
+		summary.Requirements = append(summary.Requirements, KYCRequirement{
+			Code:        "resubmit_documents",
+			Description: "Your previous verification was rejected. Resubmit a valid government-issued ID and proof of address.",
+		})
+	default:
+		if user.Country == "" {
+			summary.Requirements = append(summary.Requirements, KYCRequirement{
+				Code:        "country_of_residence",
+				Description: "Set your country of residence.",
+			})
+		}
+		summary.Requirements = append(summary.Requirements,
+			KYCRequirement{Code: "identity_document", Description: "Upload a government-issued photo ID."},
+			KYCRequirement{Code: "proof_of_address", Description: "Upload a utility bill or bank statement from the last 3 months."},
+		)
+	}
+
+	summary.CanSend = summary.Tier.Limits.PerTransaction > 0
+	return summary
+}
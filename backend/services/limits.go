@@ -0,0 +1,117 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/utils"
+	"gorm.io/gorm"
+)
+
+// ErrDailyLimitExceeded is wrapped by the error Check returns when a
+// remittance would take the sender over their daily limit.
+var ErrDailyLimitExceeded = errors.New("daily limit exceeded")
+
+// uncountedStatuses are payments that never moved money and so do not count
+// toward the daily limit.
+var uncountedStatuses = []string{"failed", "cancelled", PaymentStatusExpired, PaymentStatusRefunded}
+
+// DailyLimitError reports the limit a remittance would exceed.
+type DailyLimitError struct {
+	Currency  string  `json:"currency"`
+	Limit     float64 `json:"limit"`
+	SentToday float64 `json:"sent_today"`
+	Remaining float64 `json:"remaining"`
+}
+
+func (e *DailyLimitError) Error() string {
+	return fmt.Sprintf("%s: %.2f of %.2f %s already sent today", ErrDailyLimitExceeded, e.SentToday, e.Limit, e.Currency)
+}
+
+func (e *DailyLimitError) Unwrap() error {
+	return ErrDailyLimitExceeded
+}
+
+// AsDailyLimitError returns the *DailyLimitError in err's chain, if any.
+func AsDailyLimitError(err error) (*DailyLimitError, bool) {
+	var exceeded *DailyLimitError
+	ok := errors.As(err, &exceeded)
+	return exceeded, ok
+}
+
+type DailyLimitService struct {
+	db     *gorm.DB
+	limits map[string]float64
+	tiers  map[string]map[string]float64
+}
+
+// NewDailyLimitService creates a limit service from the configured default
+// and per-tier daily limits.
+func NewDailyLimitService(db *gorm.DB, cfg *config.Config) *DailyLimitService {
+	return &DailyLimitService{db: db, limits: cfg.DailyLimits, tiers: cfg.DailyLimitsByTier}
+}
+
+// Limit returns the daily limit in currency for a user at tier, and false
+// when that currency is uncapped.
+func (s *DailyLimitService) Limit(tier, currency string) (float64, bool) {
+	currency = strings.ToUpper(currency)
+	if limit, ok := s.tiers[tier][currency]; ok {
+		return limit, true
+	}
+	limit, ok := s.limits[currency]
+	return limit, ok
+}
+
+// SentToday sums what userID has sent in currency since the start of now's
+// UTC day, excluding payments that failed, were cancelled, or expired.
+func (s *DailyLimitService) SentToday(userID uint, currency string, now time.Time) (float64, error) {
+	var total float64
+	err := s.db.Model(&models.Payment{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("sender_id = ? AND UPPER(currency) = ? AND status NOT IN ? AND created_at >= ?",
+			userID, strings.ToUpper(currency), uncountedStatuses, StartOfUTCDay(now)).
+		Scan(&total).Error
+	return total, err
+}
+
+// Check returns a *DailyLimitError when sending amount in currency would take
+// user over their daily limit.
+func (s *DailyLimitService) Check(user *models.User, currency string, amount float64, now time.Time) error {
+	limit, ok := s.Limit(SummarizeKYC(user).Tier.Name, currency)
+	if !ok {
+		return nil
+	}
+	sent, err := s.SentToday(user.ID, currency, now)
+	if err != nil {
+		return err
+	}
+	// Compare in stroops so float sums such as 0.1+0.2 do not trip the limit.
+	total, err := utils.RoundAmount(sent + amount)
+	if err != nil {
+		return err
+	}
+	capped, err := utils.RoundAmount(limit)
+	if err != nil {
+		return err
+	}
+	if total > capped {
+		return &DailyLimitError{
+			Currency:  strings.ToUpper(currency),
+			Limit:     limit,
+			SentToday: roundMoney(sent),
+			Remaining: roundMoney(math.Max(limit-sent, 0)),
+		}
+	}
+	return nil
+}
+
+// StartOfUTCDay returns midnight UTC of the day containing t.
+func StartOfUTCDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
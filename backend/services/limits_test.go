@@ -0,0 +1,81 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/models"
+)
+
+func TestStartOfUTCDay(t *testing.T) {
+	// 01:30 on the 2nd in UTC+3 is still the 1st in UTC.
+	zone := time.FixedZone("UTC+3", 3*60*60)
+	got := StartOfUTCDay(time.Date(2026, 3, 2, 1, 30, 0, 0, zone))
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), got)
+}
+
+func TestDailyLimitService(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+	today := StartOfUTCDay(now)
+
+	user := &models.User{ID: 1, KYCStatus: KYCStatusPending}
+	limits := NewDailyLimitService(db, &config.Config{
+		DailyLimits:       map[string]float64{"USD": 100, "XLM": 1000},
+		DailyLimitsByTier: map[string]map[string]float64{"verified": {"USD": 500}},
+	})
+
+	payments := []models.Payment{
+		{SenderID: 1, Amount: 40, Currency: "USD", Status: "completed", CreatedAt: today.Add(time.Minute)},
+		{SenderID: 1, Amount: 20, Currency: "usd", Status: "pending", CreatedAt: today.Add(time.Minute)},
+		{SenderID: 1, Amount: 50, Currency: "USD", Status: "failed", CreatedAt: today.Add(time.Minute)},
+		{SenderID: 1, Amount: 50, Currency: "USD", Status: "cancelled", CreatedAt: today.Add(time.Minute)},
+		{SenderID: 1, Amount: 50, Currency: "USD", Status: PaymentStatusExpired, CreatedAt: today.Add(time.Minute)},
+		{SenderID: 1, Amount: 50, Currency: "USD", Status: "completed", CreatedAt: today.Add(-time.Minute)},
+		{SenderID: 1, Amount: 500, Currency: "XLM", Status: "completed", CreatedAt: today.Add(time.Minute)},
+		{SenderID: 2, Amount: 90, Currency: "USD", Status: "completed", CreatedAt: today.Add(time.Minute)},
+	}
+	for i := range payments {
+		require.NoError(t, db.Create(&payments[i]).Error)
+	}
+
+	t.Run("Counts only today's non-failed payments in the currency", func(t *testing.T) {
+		sent, err := limits.SentToday(1, "USD", now)
+		require.NoError(t, err)
+		assert.Equal(t, 60.0, sent)
+	})
+
+	t.Run("Up to the limit is allowed", func(t *testing.T) {
+		assert.NoError(t, limits.Check(user, "USD", 40, now))
+	})
+
+	t.Run("Past the limit is rejected", func(t *testing.T) {
+		err := limits.Check(user, "USD", 40.01, now)
+		exceeded, ok := AsDailyLimitError(err)
+		require.True(t, ok)
+		assert.Equal(t, &DailyLimitError{Currency: "USD", Limit: 100, SentToday: 60, Remaining: 40}, exceeded)
+		assert.ErrorIs(t, err, ErrDailyLimitExceeded)
+	})
+
+	t.Run("Limits are per currency", func(t *testing.T) {
+		assert.NoError(t, limits.Check(user, "XLM", 500, now))
+		assert.Error(t, limits.Check(user, "XLM", 500.5, now))
+	})
+
+	t.Run("Uncapped currency is allowed", func(t *testing.T) {
+		assert.NoError(t, limits.Check(user, "EUR", 1e9, now))
+	})
+
+	t.Run("Tier override replaces the default", func(t *testing.T) {
+		verified := &models.User{ID: 1, KYCStatus: KYCStatusVerified}
+		assert.NoError(t, limits.Check(verified, "USD", 440, now))
+		assert.Error(t, limits.Check(verified, "USD", 441, now))
+
+		limit, ok := limits.Limit("verified", "xlm")
+		assert.True(t, ok)
+		assert.Equal(t, 1000.0, limit)
+	})
+}
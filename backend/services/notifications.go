@@ -0,0 +1,197 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/yourusername/gpay-remit/i18n"
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Notification events users can set preferences for.
+const (
+	EventPaymentCompleted = "payment.completed"
+	EventPaymentFailed    = "payment.failed"
+)
+
+// NotificationEvents lists the events that accept preferences.
+var NotificationEvents = []string{EventPaymentCompleted, EventPaymentFailed, EventPaymentExpired}
+
+// PaymentMailer sends payment emails. *EmailService satisfies it.
+type PaymentMailer interface {
+	SendPaymentCompletedEmail(user *models.User, payment *models.Payment) error
+	SendPaymentFailedEmail(user *models.User, payment *models.Payment, reason string) error
+}
+
+type NotificationService struct {
+	db       *gorm.DB
+	mailer   PaymentMailer
+	webhooks *WebhookDeliveryService
+}
+
+// NewNotificationService creates a notification dispatcher. mailer and
+// webhooks may be nil, in which case that channel is skipped.
+func NewNotificationService(db *gorm.DB, mailer PaymentMailer, webhooks *WebhookDeliveryService) *NotificationService {
+	return &NotificationService{db: db, mailer: mailer, webhooks: webhooks}
+}
+
+// IsNotificationEvent reports whether event accepts preferences.
+func IsNotificationEvent(event string) bool {
+	for _, e := range NotificationEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotificationChannel reports whether channel is a known channel.
+func IsNotificationChannel(channel string) bool {
+	for _, ch := range models.NotificationChannels {
+		if ch == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// Channels returns whether each channel is enabled for event. Without a
+// stored preference in-app and webhook are on, and email follows the user's
+// email_notifications setting.
+func (s *NotificationService) Channels(user *models.User, event string) (map[string]bool, error) {
+	enabled := map[string]bool{
+		models.ChannelInApp:   true,
+		models.ChannelEmail:   user.EmailNotifications,
+		models.ChannelWebhook: true,
+	}
+
+	var prefs []models.NotificationPreference
+	if err := s.db.Where("user_id = ? AND event = ?", user.ID, event).Find(&prefs).Error; err != nil {
+		return nil, err
+	}
+	for _, pref := range prefs {
+		enabled[pref.Channel] = pref.Enabled
+	}
+	return enabled, nil
+}
+
+// Preferences returns the effective setting of every event and channel for user.
+func (s *NotificationService) Preferences(user *models.User) ([]models.NotificationPreference, error) {
+	prefs := make([]models.NotificationPreference, 0, len(NotificationEvents)*len(models.NotificationChannels))
+	for _, event := range NotificationEvents {
+		enabled, err := s.Channels(user, event)
+		if err != nil {
+			return nil, err
+		}
+		for _, channel := range models.NotificationChannels {
+			prefs = append(prefs, models.NotificationPreference{
+				UserID:  user.ID,
+				Event:   event,
+				Channel: channel,
+				Enabled: enabled[channel],
+			})
+		}
+	}
+	return prefs, nil
+}
+
+// SetPreference stores whether user wants event delivered on channel.
+func (s *NotificationService) SetPreference(userID uint, event, channel string, enabled bool) error {
+	if !IsNotificationEvent(event) {
+		return fmt.Errorf("unknown notification event %q", event)
+	}
+	if !IsNotificationChannel(channel) {
+		return fmt.Errorf("unknown notification channel %q", channel)
+	}
+	pref := models.NotificationPreference{UserID: userID, Event: event, Channel: channel, Enabled: enabled}
+	return s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "event"}, {Name: "channel"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+	}).Create(&pref).Error
+}
+
+// NotifyPayment tells user about event on payment over every channel they
+// have enabled and returns the channels it was delivered on. reason is
+// included in failure emails. A channel that fails is logged and skipped so
+// the others still go out.
+func (s *NotificationService) NotifyPayment(user *models.User, event string, payment *models.Payment, reason string) ([]string, error) {
+	enabled, err := s.Channels(user, event)
+	if err != nil {
+		return nil, err
+	}
+	log := logger.Log.WithFields(logrus.Fields{
+		"user_id":    user.ID,
+		"payment_id": payment.ID,
+		"event":      event,
+	})
+
+	var delivered []string
+	for _, channel := range models.NotificationChannels {
+		if !enabled[channel] {
+			continue
+		}
+		var err error
+		switch channel {
+		case models.ChannelInApp:
+			err = s.db.Create(&models.Notification{
+				UserID:    user.ID,
+				Event:     event,
+				Title:     notificationTitle(i18n.LanguageForCountry(user.Country), event, payment),
+				PaymentID: &payment.ID,
+			}).Error
+		case models.ChannelEmail:
+			if s.mailer == nil || !hasEmailTemplate(event) {
+				continue
+			}
+			err = s.sendEmail(user, event, payment, reason)
+		case models.ChannelWebhook:
+			if s.webhooks == nil {
+				continue
+			}
+			var queued int
+			queued, err = s.webhooks.TriggerUserWebhook(user.ID, event, map[string]interface{}{
+				"payment_id": payment.ID,
+				"status":     payment.Status,
+				"amount":     payment.Amount,
+				"currency":   payment.Currency,
+			})
+			if err == nil && queued == 0 {
+				// The user has no webhook subscribed to this event.
+				continue
+			}
+		}
+		if err != nil {
+			log.WithField("channel", channel).WithError(err).Warn("Failed to deliver notification")
+			continue
+		}
+		delivered = append(delivered, channel)
+	}
+	return delivered, nil
+}
+
+func hasEmailTemplate(event string) bool {
+	return event == EventPaymentCompleted || event == EventPaymentFailed
+}
+
+func (s *NotificationService) sendEmail(user *models.User, event string, payment *models.Payment, reason string) error {
+	if event == EventPaymentFailed {
+		return s.mailer.SendPaymentFailedEmail(user, payment, reason)
+	}
+	return s.mailer.SendPaymentCompletedEmail(user, payment)
+}
+
+// notificationTitle is the in-app title for event on payment, in lang.
+func notificationTitle(lang, event string, payment *models.Payment) string {
+	switch event {
+	case EventPaymentCompleted:
+		return i18n.T(lang, "notification.payment_completed.title", payment.ID)
+	case EventPaymentFailed:
+		return i18n.T(lang, "notification.payment_failed.title", payment.ID)
+	case EventPaymentExpired:
+		return i18n.T(lang, "notification.payment_expired.title", payment.ID)
+	}
+	return i18n.T(lang, "notification.payment_updated.title", payment.ID)
+}
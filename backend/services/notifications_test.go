@@ -0,0 +1,106 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/models"
+)
+
+type recordingMailer struct {
+	completed []uint
+	failed    []uint
+}
+
+func (m *recordingMailer) SendPaymentCompletedEmail(user *models.User, payment *models.Payment) error {
+	m.completed = append(m.completed, payment.ID)
+	return nil
+}
+
+func (m *recordingMailer) SendPaymentFailedEmail(user *models.User, payment *models.Payment, reason string) error {
+	m.failed = append(m.failed, payment.ID)
+	return nil
+}
+
+func TestNotifyPaymentRespectsPreferences(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.Notification{}, &models.NotificationPreference{}, &models.Webhook{}, &models.WebhookDelivery{}))
+
+	user := models.User{Email: "prefs@example.com", Name: "Prefs", StellarAddress: "GPREFS", PasswordHash: "x", EmailNotifications: true}
+	require.NoError(t, db.Create(&user).Error)
+	payment := models.Payment{SenderID: user.ID, Amount: 10, Currency: "USD", Status: "completed"}
+	require.NoError(t, db.Create(&payment).Error)
+
+	mailer := &recordingMailer{}
+	notifier := NewNotificationService(db, mailer, NewWebhookDeliveryService(db))
+
+	t.Run("Defaults deliver in-app and email", func(t *testing.T) {
+		delivered, err := notifier.NotifyPayment(&user, EventPaymentFailed, &payment, "op_underfunded")
+		require.NoError(t, err)
+		assert.Equal(t, []string{models.ChannelInApp, models.ChannelEmail}, delivered)
+		assert.Equal(t, []uint{payment.ID}, mailer.failed)
+	})
+
+	t.Run("Email opt-out leaves only in-app", func(t *testing.T) {
+		require.NoError(t, notifier.SetPreference(user.ID, EventPaymentCompleted, models.ChannelEmail, false))
+
+		delivered, err := notifier.NotifyPayment(&user, EventPaymentCompleted, &payment, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{models.ChannelInApp}, delivered)
+		assert.Empty(t, mailer.completed)
+
+		var notifications []models.Notification
+		db.Where("user_id = ? AND event = ?", user.ID, EventPaymentCompleted).Find(&notifications)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, payment.ID, *notifications[0].PaymentID)
+	})
+
+	t.Run("Preferences can be re-enabled", func(t *testing.T) {
+		require.NoError(t, notifier.SetPreference(user.ID, EventPaymentCompleted, models.ChannelEmail, true))
+
+		channels, err := notifier.Channels(&user, EventPaymentCompleted)
+		require.NoError(t, err)
+		assert.True(t, channels[models.ChannelEmail])
+
+		var count int64
+		db.Model(&models.NotificationPreference{}).Where("user_id = ?", user.ID).Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Unknown event or channel is rejected", func(t *testing.T) {
+		assert.Error(t, notifier.SetPreference(user.ID, "payment.teleported", models.ChannelEmail, false))
+		assert.Error(t, notifier.SetPreference(user.ID, EventPaymentCompleted, "sms", false))
+	})
+}
+
+func TestNotificationPreferencesDefaults(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.NotificationPreference{}))
+
+	user := models.User{ID: 7, EmailNotifications: false}
+	prefs, err := NewNotificationService(db, nil, nil).Preferences(&user)
+	require.NoError(t, err)
+	require.Len(t, prefs, len(NotificationEvents)*len(models.NotificationChannels))
+	for _, pref := range prefs {
+		assert.Equal(t, pref.Channel != models.ChannelEmail, pref.Enabled, "%s/%s", pref.Event, pref.Channel)
+	}
+}
+
+func TestNotificationTitleFollowsCountryLanguage(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.Notification{}, &models.NotificationPreference{}))
+
+	user := models.User{Email: "mx@example.com", Name: "MX", StellarAddress: "GMX", PasswordHash: "x", Country: "MX"}
+	require.NoError(t, db.Create(&user).Error)
+	payment := models.Payment{SenderID: user.ID, Amount: 10, Currency: "MXN", Status: "completed"}
+	require.NoError(t, db.Create(&payment).Error)
+
+	_, err := NewNotificationService(db, nil, nil).NotifyPayment(&user, EventPaymentCompleted, &payment, "")
+	require.NoError(t, err)
+
+	var notification models.Notification
+	require.NoError(t, db.Where("user_id = ?", user.ID).First(&notification).Error)
+	assert.Equal(t, fmt.Sprintf("Pago #%d completado", payment.ID), notification.Title)
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrRateUnavailable is returned when no rate is known for a currency pair.
+var ErrRateUnavailable = errors.New("rate unavailable")
+
+// IsRateUnavailable reports whether err stems from a missing FX rate.
+func IsRateUnavailable(err error) bool {
+	return errors.Is(err, ErrRateUnavailable)
+}
+
+// RateSource supplies the current FX rate for a pair written as "BASE/QUOTE".
+type RateSource interface {
+	Rate(ctx context.Context, pair string) (float64, error)
+}
+
+// RateQuote is a rate as listed to clients, stamped with when it was read
+// and which source supplied it.
+type RateQuote struct {
+	Pair      string    `json:"pair"`
+	Rate      float64   `json:"rate"`
+	Source    string    `json:"source"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RateLister is implemented by rate sources that can enumerate the pairs they
+// support.
+type RateLister interface {
+	ListRates(ctx context.Context) ([]RateQuote, error)
+}
+
+// ListRates returns every pair the source supports, sorted by pair. Sources
+// that cannot enumerate their pairs list nothing.
+func ListRates(ctx context.Context, rates RateSource) ([]RateQuote, error) {
+	lister, ok := rates.(RateLister)
+	if !ok {
+		return []RateQuote{}, nil
+	}
+	quotes, err := lister.ListRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(quotes, func(i, j int) bool { return quotes[i].Pair < quotes[j].Pair })
+	return quotes, nil
+}
+
+// StaticRateSource serves fixed rates, keyed by upper-case pair, such as
+// those configured through FX_RATES.
+type StaticRateSource map[string]float64
+
+func (s StaticRateSource) Rate(ctx context.Context, pair string) (float64, error) {
+	rate, ok := s[strings.ToUpper(pair)]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrRateUnavailable, pair)
+	}
+	return rate, nil
+}
+
+// ListRates lists the configured pairs. Static rates have no upstream
+// timestamp, so each quote is stamped with the time it was read.
+func (s StaticRateSource) ListRates(ctx context.Context) ([]RateQuote, error) {
+	now := time.Now().UTC()
+	quotes := make([]RateQuote, 0, len(s))
+	for pair, rate := range s {
+		quotes = append(quotes, RateQuote{Pair: strings.ToUpper(pair), Rate: rate, Source: "static", UpdatedAt: now})
+	}
+	return quotes, nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/gpay-remit/models"
+	"gorm.io/gorm"
+)
+
+// placeholderEmailDomain is the reserved domain used for the email address of
+// placeholder recipients, which must be unique but is never contacted.
+const placeholderEmailDomain = "unregistered.invalid"
+
+type RecipientService struct {
+	db *gorm.DB
+}
+
+func NewRecipientService(db *gorm.DB) *RecipientService {
+	return &RecipientService{db: db}
+}
+
+// Resolve returns the user owning the Stellar address, creating an
+// unregistered placeholder user when nobody has claimed it yet so that
+// remittances to the address always have a recipient.
+func (s *RecipientService) Resolve(address string) (*models.User, error) {
+	user, err := s.findByAddress(address)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to look up recipient: %w", err)
+	}
+
+	placeholder := models.User{
+		Email:          PlaceholderEmail(address),
+		Name:           "Unregistered recipient",
+		StellarAddress: address,
+		Unregistered:   true,
+	}
+	if err := s.db.Create(&placeholder).Error; err != nil {
+		// A concurrent request may have created the user first.
+		if user, findErr := s.findByAddress(address); findErr == nil {
+			return user, nil
+		}
+		return nil, fmt.Errorf("failed to create placeholder recipient: %w", err)
+	}
+	return &placeholder, nil
+}
+
+// Placeholder returns the unregistered placeholder for address, or nil when
+// there is none. Registration uses it to take over the placeholder's record.
+func (s *RecipientService) Placeholder(address string) (*models.User, error) {
+	var user models.User
+	err := s.db.Where("stellar_address = ? AND unregistered = ?", address, true).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up placeholder recipient: %w", err)
+	}
+	return &user, nil
+}
+
+// PlaceholderEmail is the synthetic email stored on a placeholder recipient.
+func PlaceholderEmail(address string) string {
+	return strings.ToLower(address) + "@" + placeholderEmailDomain
+}
+
+func (s *RecipientService) findByAddress(address string) (*models.User, error) {
+	var user models.User
+	if err := s.db.Where("stellar_address = ?", address).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/models"
+)
+
+func TestRecipientServiceResolve(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.User{}))
+	service := NewRecipientService(db)
+
+	existing := models.User{Email: "alice@example.com", Name: "Alice", StellarAddress: "GALICE", PasswordHash: "x"}
+	require.NoError(t, db.Create(&existing).Error)
+
+	t.Run("Existing user is returned", func(t *testing.T) {
+		user, err := service.Resolve("GALICE")
+		require.NoError(t, err)
+		assert.Equal(t, existing.ID, user.ID)
+		assert.False(t, user.Unregistered)
+	})
+
+	t.Run("Unknown address gets a placeholder", func(t *testing.T) {
+		user, err := service.Resolve("GNEWCOMER")
+		require.NoError(t, err)
+		assert.NotZero(t, user.ID)
+		assert.True(t, user.Unregistered)
+		assert.Equal(t, PlaceholderEmail("GNEWCOMER"), user.Email)
+
+		again, err := service.Resolve("GNEWCOMER")
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, again.ID)
+
+		placeholder, err := service.Placeholder("GNEWCOMER")
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, placeholder.ID)
+	})
+
+	t.Run("Registered users are not placeholders", func(t *testing.T) {
+		placeholder, err := service.Placeholder("GALICE")
+		require.NoError(t, err)
+		assert.Nil(t, placeholder)
+	})
+}
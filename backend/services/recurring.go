@@ -0,0 +1,229 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/models"
+	"gorm.io/gorm"
+)
+
+// Recurring remittance intervals.
+const (
+	IntervalDaily   = "daily"
+	IntervalWeekly  = "weekly"
+	IntervalMonthly = "monthly"
+)
+
+// errRunSuperseded rolls back a run whose schedule was fired, paused, or
+// deleted since it was loaded.
+var errRunSuperseded = errors.New("recurring run superseded")
+
+// ParseInterval normalizes a recurring interval, rejecting unknown ones.
+func ParseInterval(interval string) (string, error) {
+	switch normalized := strings.ToLower(strings.TrimSpace(interval)); normalized {
+	case IntervalDaily, IntervalWeekly, IntervalMonthly:
+		return normalized, nil
+	}
+	return "", fmt.Errorf("unknown interval %q: want daily, weekly, or monthly", interval)
+}
+
+// Occurrence returns the time of the nth run (counting from 0) of a schedule
+// starting at start. Monthly runs keep start's day of the month, falling on
+// the month's last day when it is shorter.
+func Occurrence(start time.Time, interval string, n int) time.Time {
+	switch interval {
+	case IntervalDaily:
+		return start.AddDate(0, 0, n)
+	case IntervalWeekly:
+		return start.AddDate(0, 0, 7*n)
+	}
+	firstOfMonth := time.Date(start.Year(), start.Month()+time.Month(n), 1, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	day := start.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
+
+// NextOccurrence returns the index and time of the first run of the schedule
+// after now, starting the search from occurrence n. Runs missed while the
+// scheduler was down are skipped rather than fired in a burst.
+func NextOccurrence(start time.Time, interval string, n int, now time.Time) (int, time.Time) {
+	next := Occurrence(start, interval, n)
+	for !next.After(now) {
+		n++
+		next = Occurrence(start, interval, n)
+	}
+	return n, next
+}
+
+type RecurringService struct {
+	db   *gorm.DB
+	fees *FeeService
+}
+
+func NewRecurringService(db *gorm.DB, fees *FeeService) *RecurringService {
+	return &RecurringService{db: db, fees: fees}
+}
+
+// Due returns the active schedules whose next run is at or before now.
+func (s *RecurringService) Due(now time.Time) ([]models.RecurringRemittance, error) {
+	var due []models.RecurringRemittance
+	if err := s.db.Where("active = ? AND next_run_at <= ?", true, now).
+		Order("next_run_at").
+		Find(&due).Error; err != nil {
+		return nil, err
+	}
+	return due, nil
+}
+
+// RunDue fires every due schedule and returns the remittances created. A
+// schedule that fails is logged and retried on the next pass without holding
+// up the others.
+func (s *RecurringService) RunDue(now time.Time) ([]models.Payment, error) {
+	due, err := s.Due(now)
+	if err != nil {
+		return nil, err
+	}
+	var created []models.Payment
+	for i := range due {
+		payment, err := s.Run(&due[i], now)
+		if err != nil {
+			logger.Log.WithField("recurring_id", due[i].ID).WithError(err).Error("Failed to run recurring remittance")
+			continue
+		}
+		if payment != nil {
+			created = append(created, *payment)
+		}
+	}
+	return created, nil
+}
+
+// Resume reactivates a paused schedule. Runs that fell due while it was
+// paused are skipped; the next one is the first after now.
+func (s *RecurringService) Resume(schedule *models.RecurringRemittance, now time.Time) error {
+	occurrence, nextRun := NextOccurrence(schedule.StartAt, schedule.Interval, schedule.Occurrence, now)
+	updates := map[string]interface{}{
+		"active":             true,
+		"deactivated_reason": "",
+		"occurrence":         occurrence,
+		"next_run_at":        nextRun,
+	}
+	if err := s.db.Model(schedule).Updates(updates).Error; err != nil {
+		return err
+	}
+	schedule.Active = true
+	schedule.DeactivatedReason = ""
+	schedule.Occurrence = occurrence
+	schedule.NextRunAt = nextRun
+	return nil
+}
+
+// Run creates the pending remittance for schedule's due occurrence and
+// advances it to the next occurrence after now, in one transaction. It
+// returns nil without error when the run was already fired (e.g. by a
+// scheduler that crashed after committing) or the schedule was deactivated,
+// including because its sender is no longer active.
+func (s *RecurringService) Run(schedule *models.RecurringRemittance, now time.Time) (*models.Payment, error) {
+	log := logger.Log.WithFields(logrus.Fields{
+		"recurring_id": schedule.ID,
+		"sender_id":    schedule.SenderID,
+		"occurrence":   schedule.Occurrence,
+	})
+
+	var sender models.User
+	if err := s.db.First(&sender, schedule.SenderID).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		return nil, s.deactivate(schedule, "sender account no longer exists", log)
+	}
+	if !sender.IsActive {
+		return nil, s.deactivate(schedule, "sender account is inactive", log)
+	}
+
+	breakdown, err := s.fees.Calculate(schedule.Amount)
+	if err != nil {
+		return nil, s.deactivate(schedule, fmt.Sprintf("fee calculation rejected: %v", err), log)
+	}
+
+	occurrence, nextRun := NextOccurrence(schedule.StartAt, schedule.Interval, schedule.Occurrence+1, now)
+	var payment models.Payment
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		recipient, err := NewRecipientService(tx).Resolve(schedule.RecipientAccount)
+		if err != nil {
+			return err
+		}
+		payment = models.Payment{
+			SenderID:         sender.ID,
+			SenderAccount:    sender.StellarAddress,
+			RecipientID:      recipient.ID,
+			RecipientAccount: schedule.RecipientAccount,
+			Amount:           schedule.Amount,
+			Currency:         schedule.AssetCode,
+			Status:           "pending",
+			Fee:              breakdown.TotalFee,
+			PlatformFee:      breakdown.PlatformFee,
+			ForexFee:         breakdown.ForexFee,
+			ComplianceFee:    breakdown.ComplianceFee,
+			NetworkFee:       breakdown.NetworkFee,
+			Notes:            models.EncryptedString(fmt.Sprintf("Scheduled by recurring remittance #%d", schedule.ID)),
+		}
+		if err := tx.Create(&payment).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.RecurringRemittance{}).
+			Where("id = ? AND occurrence = ? AND active = ?", schedule.ID, schedule.Occurrence, true).
+			Updates(map[string]interface{}{
+				"occurrence":      occurrence,
+				"next_run_at":     nextRun,
+				"last_run_at":     now,
+				"last_payment_id": payment.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errRunSuperseded
+		}
+		return nil
+	})
+	if errors.Is(err, errRunSuperseded) {
+		log.Info("Recurring remittance already fired or stopped; skipping")
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.Occurrence = occurrence
+	schedule.NextRunAt = nextRun
+	schedule.LastRunAt = &now
+	schedule.LastPaymentID = &payment.ID
+	log.WithField("payment_id", payment.ID).WithField("next_run_at", nextRun.Format(time.RFC3339)).Info("Recurring remittance fired")
+	return &payment, nil
+}
+
+// deactivate stops schedule, recording why, unless it changed since it was
+// loaded.
+func (s *RecurringService) deactivate(schedule *models.RecurringRemittance, reason string, log *logrus.Entry) error {
+	result := s.db.Model(&models.RecurringRemittance{}).
+		Where("id = ? AND occurrence = ? AND active = ?", schedule.ID, schedule.Occurrence, true).
+		Updates(map[string]interface{}{"active": false, "deactivated_reason": reason})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		schedule.Active = false
+		schedule.DeactivatedReason = reason
+		log.WithField("reason", reason).Warn("Recurring remittance deactivated")
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/config"
+	"github.com/yourusername/gpay-remit/models"
+)
+
+func TestParseInterval(t *testing.T) {
+	for _, in := range []string{"daily", "Weekly", " MONTHLY "} {
+		_, err := ParseInterval(in)
+		assert.NoError(t, err, in)
+	}
+	interval, _ := ParseInterval("Weekly")
+	assert.Equal(t, IntervalWeekly, interval)
+
+	for _, in := range []string{"", "hourly", "yearly", "1d"} {
+		_, err := ParseInterval(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestOccurrence(t *testing.T) {
+	start := time.Date(2024, 1, 31, 9, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, start, Occurrence(start, IntervalDaily, 0))
+	assert.Equal(t, time.Date(2024, 2, 2, 9, 30, 0, 0, time.UTC), Occurrence(start, IntervalDaily, 2))
+	assert.Equal(t, time.Date(2024, 2, 14, 9, 30, 0, 0, time.UTC), Occurrence(start, IntervalWeekly, 2))
+
+	// Monthly runs keep the 31st, clamped to shorter months without drifting.
+	assert.Equal(t, time.Date(2024, 2, 29, 9, 30, 0, 0, time.UTC), Occurrence(start, IntervalMonthly, 1))
+	assert.Equal(t, time.Date(2024, 3, 31, 9, 30, 0, 0, time.UTC), Occurrence(start, IntervalMonthly, 2))
+	assert.Equal(t, time.Date(2024, 4, 30, 9, 30, 0, 0, time.UTC), Occurrence(start, IntervalMonthly, 3))
+	assert.Equal(t, time.Date(2025, 1, 31, 9, 30, 0, 0, time.UTC), Occurrence(start, IntervalMonthly, 12))
+}
+
+func TestNextOccurrence(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	n, next := NextOccurrence(start, IntervalDaily, 1, start.Add(time.Hour))
+	assert.Equal(t, 1, n)
+	assert.Equal(t, start.AddDate(0, 0, 1), next)
+
+	// Missed runs are skipped: ten days down yields the first run after now.
+	n, next = NextOccurrence(start, IntervalDaily, 1, start.AddDate(0, 0, 10).Add(time.Hour))
+	assert.Equal(t, 11, n)
+	assert.Equal(t, start.AddDate(0, 0, 11), next)
+
+	// A run due exactly now is not in the future.
+	n, _ = NextOccurrence(start, IntervalWeekly, 1, start.AddDate(0, 0, 7))
+	assert.Equal(t, 2, n)
+}
+
+func TestRecurringService(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.RecurringRemittance{}))
+	service := NewRecurringService(db, NewFeeService(&config.Config{}))
+
+	sender := models.User{Email: "sender@example.com", Name: "Sender", StellarAddress: "GSENDERRECURRING", IsActive: true}
+	require.NoError(t, db.Create(&sender).Error)
+
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	newSchedule := func(senderID uint) *models.RecurringRemittance {
+		schedule := &models.RecurringRemittance{
+			SenderID:         senderID,
+			RecipientAccount: "GRECIPIENTRECURRING",
+			Amount:           100,
+			AssetCode:        "USDC",
+			Interval:         IntervalMonthly,
+			StartAt:          start,
+			NextRunAt:        start,
+			Active:           true,
+		}
+		require.NoError(t, db.Create(schedule).Error)
+		return schedule
+	}
+
+	t.Run("Due detection", func(t *testing.T) {
+		schedule := newSchedule(sender.ID)
+		defer db.Unscoped().Delete(schedule)
+
+		due, err := service.Due(start.Add(-time.Minute))
+		require.NoError(t, err)
+		assert.Empty(t, due, "not due before next_run_at")
+
+		due, err = service.Due(start)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, schedule.ID, due[0].ID)
+
+		require.NoError(t, db.Model(schedule).Update("active", false).Error)
+		due, err = service.Due(start.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, due, "paused schedules are not due")
+	})
+
+	t.Run("Run creates a payment and advances next run", func(t *testing.T) {
+		schedule := newSchedule(sender.ID)
+		defer db.Unscoped().Delete(schedule)
+
+		now := start.Add(time.Minute)
+		payment, err := service.Run(schedule, now)
+		require.NoError(t, err)
+		require.NotNil(t, payment)
+		assert.Equal(t, "pending", payment.Status)
+		assert.Equal(t, sender.ID, payment.SenderID)
+		assert.Equal(t, "GSENDERRECURRING", payment.SenderAccount)
+		assert.Equal(t, "GRECIPIENTRECURRING", payment.RecipientAccount)
+		assert.NotZero(t, payment.RecipientID)
+		assert.Equal(t, 100.0, payment.Amount)
+		assert.Equal(t, "USDC", payment.Currency)
+
+		var stored models.RecurringRemittance
+		require.NoError(t, db.First(&stored, schedule.ID).Error)
+		assert.Equal(t, 1, stored.Occurrence)
+		assert.True(t, stored.NextRunAt.Equal(time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)))
+		require.NotNil(t, stored.LastPaymentID)
+		assert.Equal(t, payment.ID, *stored.LastPaymentID)
+
+		due, err := service.Due(now)
+		require.NoError(t, err)
+		assert.Empty(t, due)
+	})
+
+	t.Run("A run is never fired twice", func(t *testing.T) {
+		schedule := newSchedule(sender.ID)
+		defer db.Unscoped().Delete(schedule)
+
+		// A second scheduler, or one restarted mid-pass, holding the same
+		// due schedule must not create another payment.
+		stale := *schedule
+		first, err := service.Run(schedule, start)
+		require.NoError(t, err)
+		require.NotNil(t, first)
+
+		second, err := service.Run(&stale, start)
+		require.NoError(t, err)
+		assert.Nil(t, second)
+
+		var count int64
+		db.Model(&models.Payment{}).Where("sender_id = ? AND amount = ?", sender.ID, 100.0).Where("id >= ?", first.ID).Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Inactive sender deactivates the schedule", func(t *testing.T) {
+		inactive := models.User{Email: "gone@example.com", Name: "Gone", StellarAddress: "GINACTIVERECURRING", IsActive: true}
+		require.NoError(t, db.Create(&inactive).Error)
+		require.NoError(t, db.Model(&inactive).Update("is_active", false).Error)
+		schedule := newSchedule(inactive.ID)
+
+		created, err := service.RunDue(start.Add(time.Minute))
+		require.NoError(t, err)
+		for _, p := range created {
+			assert.NotEqual(t, inactive.ID, p.SenderID)
+		}
+
+		var stored models.RecurringRemittance
+		require.NoError(t, db.First(&stored, schedule.ID).Error)
+		assert.False(t, stored.Active)
+		assert.Equal(t, "sender account is inactive", stored.DeactivatedReason)
+		assert.Nil(t, stored.LastPaymentID)
+	})
+
+	t.Run("Resume skips runs missed while paused", func(t *testing.T) {
+		schedule := newSchedule(sender.ID)
+		defer db.Unscoped().Delete(schedule)
+		require.NoError(t, db.Model(schedule).Update("active", false).Error)
+
+		now := time.Date(2024, 8, 15, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, service.Resume(schedule, now))
+
+		var stored models.RecurringRemittance
+		require.NoError(t, db.First(&stored, schedule.ID).Error)
+		assert.True(t, stored.Active)
+		assert.Equal(t, 4, stored.Occurrence)
+		assert.True(t, stored.NextRunAt.Equal(time.Date(2024, 9, 1, 8, 0, 0, 0, time.UTC)))
+	})
+}
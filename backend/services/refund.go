@@ -0,0 +1,49 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/utils"
+)
+
+// PaymentStatusRefunded marks a payment whose escrow was returned, in full or
+// in part, to the sender.
+const PaymentStatusRefunded = "refunded"
+
+// NonRefundableFee is the part of a payment's fee that is not returned on a
+// refund: the network fee, already spent on-chain, and the compliance fee,
+// for screening that has already been done.
+func NonRefundableFee(payment *models.Payment) (utils.Amount, error) {
+	network, err := utils.RoundAmount(payment.NetworkFee)
+	if err != nil {
+		return 0, fmt.Errorf("invalid network fee: %w", err)
+	}
+	compliance, err := utils.RoundAmount(payment.ComplianceFee)
+	if err != nil {
+		return 0, fmt.Errorf("invalid compliance fee: %w", err)
+	}
+	return network.Add(compliance)
+}
+
+// RefundableAmount is the most of a payment's escrowed amount that can be
+// returned to the sender: the amount less NonRefundableFee. It is never
+// negative; a fee that meets or exceeds the amount leaves nothing to refund.
+func RefundableAmount(payment *models.Payment) (utils.Amount, error) {
+	escrowed, err := utils.RoundAmount(payment.Amount)
+	if err != nil {
+		return 0, fmt.Errorf("invalid payment amount: %w", err)
+	}
+	fee, err := NonRefundableFee(payment)
+	if err != nil {
+		return 0, err
+	}
+	refundable, err := escrowed.Sub(fee)
+	if err != nil {
+		return 0, err
+	}
+	if refundable < 0 {
+		return 0, nil
+	}
+	return refundable, nil
+}
@@ -0,0 +1,20 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/utils"
+)
+
+func TestRefundableAmount(t *testing.T) {
+	refundable, err := RefundableAmount(&models.Payment{Amount: 10, PlatformFee: 0.5, NetworkFee: 0.05, ComplianceFee: 0.2})
+	require.NoError(t, err)
+	assert.Equal(t, "9.7500000", refundable.String(), "platform and forex fees are refunded")
+
+	refundable, err = RefundableAmount(&models.Payment{Amount: 0.1, NetworkFee: 0.05, ComplianceFee: 0.2})
+	require.NoError(t, err)
+	assert.Equal(t, utils.Amount(0), refundable, "a fee above the amount never produces a negative refund")
+}
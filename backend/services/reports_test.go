@@ -0,0 +1,69 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/models"
+)
+
+func TestGetRemittanceReport(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewAnalyticsService(db)
+
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	payments := []models.Payment{
+		{SenderID: 1, Amount: 100, Currency: "USDC", Fee: 2, Status: "completed", CreatedAt: day.Add(9 * time.Hour)},
+		{SenderID: 1, Amount: 200, Currency: "usdc", Fee: 4, Status: "completed", CreatedAt: day.Add(23 * time.Hour)},
+		{SenderID: 2, Amount: 50, Currency: "USDC", Fee: 1, Status: "failed", CreatedAt: day.Add(10 * time.Hour)},
+		{SenderID: 2, Amount: 1000, Currency: "NGN", Fee: 15, Status: "pending", CreatedAt: day.AddDate(0, 0, 1).Add(time.Hour)},
+		{SenderID: 3, Amount: 75, Currency: "EURC", Fee: 1.5, Status: "completed", CreatedAt: day.AddDate(0, 0, 1).Add(2 * time.Hour)},
+		// Outside the range on either side.
+		{SenderID: 3, Amount: 999, Currency: "USDC", Fee: 9, Status: "completed", CreatedAt: day.Add(-time.Minute)},
+		{SenderID: 3, Amount: 999, Currency: "USDC", Fee: 9, Status: "completed", CreatedAt: day.AddDate(0, 0, 2)},
+	}
+	require.NoError(t, db.Create(&payments).Error)
+
+	now := time.Date(2024, 3, 12, 8, 0, 0, 0, time.UTC)
+
+	t.Run("Aggregates over the range", func(t *testing.T) {
+		report, err := service.GetRemittanceReport(day, day.AddDate(0, 0, 1), now)
+		require.NoError(t, err)
+
+		assert.Equal(t, "2024-03-10", report.From)
+		assert.Equal(t, "2024-03-11", report.To)
+		assert.Equal(t, int64(5), report.TotalCount)
+		assert.Equal(t, []CurrencyReport{
+			{Currency: "EURC", Count: 1, Volume: 75, CompletedCount: 1, CompletedVolume: 75, Fees: 1.5},
+			{Currency: "NGN", Count: 1, Volume: 1000},
+			{Currency: "USDC", Count: 3, Volume: 350, CompletedCount: 2, CompletedVolume: 300, Fees: 6},
+		}, report.Currencies)
+		assert.Equal(t, int64(3), report.ByStatus["completed"])
+		assert.Equal(t, int64(1), report.ByStatus["failed"])
+		assert.Equal(t, int64(1), report.ByStatus["pending"])
+		assert.Equal(t, int64(0), report.ByStatus["cancelled"])
+	})
+
+	t.Run("Single day", func(t *testing.T) {
+		report, err := service.GetRemittanceReport(day, day, now)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), report.TotalCount)
+		require.Len(t, report.Currencies, 1)
+		assert.Equal(t, 6.0, report.Currencies[0].Fees)
+	})
+
+	t.Run("Empty range reports zeros", func(t *testing.T) {
+		empty := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		report, err := service.GetRemittanceReport(empty, empty, now)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), report.TotalCount)
+		assert.NotNil(t, report.Currencies)
+		assert.Empty(t, report.Currencies)
+		assert.Len(t, report.ByStatus, len(reportStatuses))
+		for status, count := range report.ByStatus {
+			assert.Zero(t, count, status)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"time"
+
+	"github.com/yourusername/gpay-remit/models"
+	"gorm.io/gorm"
+)
+
+// terminalPaymentStatuses are the statuses a payment never leaves, and so
+// the only ones eligible for purging.
+var terminalPaymentStatuses = []string{"completed", "failed", "cancelled", PaymentStatusExpired, PaymentStatusRefunded}
+
+type RetentionService struct {
+	db *gorm.DB
+}
+
+func NewRetentionService(db *gorm.DB) *RetentionService {
+	return &RetentionService{db: db}
+}
+
+// PurgePayments anonymizes terminal payments last updated before cutoff. The
+// ledger summary (parties' user IDs, amounts, fees, currencies, status, and
+// transaction hash) is kept so aggregates and reconciliation still work;
+// account addresses, memos, notes, conditions, and the signed envelope are
+// erased. Purged payments are stamped with PurgedAt and skipped thereafter.
+// It returns the number of payments purged.
+func (s *RetentionService) PurgePayments(cutoff, now time.Time) (int64, error) {
+	result := s.db.Unscoped().Model(&models.Payment{}).
+		Where("status IN ? AND updated_at < ? AND purged_at IS NULL", terminalPaymentStatuses, cutoff).
+		Updates(map[string]interface{}{
+			"sender_account":    "",
+			"recipient_account": "",
+			"memo":              "",
+			"memo_type":         "",
+			"notes":             "",
+			"conditions":        "",
+			"tx_envelope":       "",
+			"purged_at":         now,
+			"version":           gorm.Expr("version + 1"),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// deliveryCleanupBatch bounds how many deliveries one delete statement
+// removes, so a large backlog is cleared in short transactions.
+const deliveryCleanupBatch = 500
+
+// PurgeDeliveredWebhooks deletes successful webhook deliveries completed
+// before cutoff, in batches. Pending and failed deliveries are never deleted.
+// It is safe to rerun after a partial failure. It returns the number of
+// deliveries deleted.
+func (s *RetentionService) PurgeDeliveredWebhooks(cutoff time.Time) (int64, error) {
+	var total int64
+	for {
+		var ids []uint
+		if err := s.db.Unscoped().Model(&models.WebhookDelivery{}).
+			Where("status = ? AND completed_at < ?", "success", cutoff).
+			Order("id").Limit(deliveryCleanupBatch).
+			Pluck("id", &ids).Error; err != nil {
+			return total, err
+		}
+		if len(ids) == 0 {
+			return total, nil
+		}
+		result := s.db.Unscoped().Where("id IN ?", ids).Delete(&models.WebhookDelivery{})
+		if result.Error != nil {
+			return total, result.Error
+		}
+		total += result.RowsAffected
+		if len(ids) < deliveryCleanupBatch {
+			return total, nil
+		}
+	}
+}
+
+// FlagStaleDeliveries stamps FlaggedAt on undelivered webhook deliveries
+// created before cutoff that are not flagged yet, and returns how many it
+// flagged.
+func (s *RetentionService) FlagStaleDeliveries(cutoff, now time.Time) (int64, error) {
+	result := s.db.Model(&models.WebhookDelivery{}).
+		Where("status <> ? AND created_at < ? AND flagged_at IS NULL", "success", cutoff).
+		Update("flagged_at", now)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/models"
+)
+
+func TestPurgePayments(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+	retention := 90 * 24 * time.Hour
+
+	newPayment := func(status string, updatedAt time.Time) models.Payment {
+		p := models.Payment{
+			SenderID: 1, RecipientID: 2, Amount: 100, Currency: "USD", Status: status,
+			SenderAccount: "GSENDER", RecipientAccount: "GRECIPIENT",
+			Memo: "invoice 42", MemoType: "text", Notes: "rent for Jane", TxHash: "abc123", Fee: 1,
+		}
+		require.NoError(t, db.Create(&p).Error)
+		require.NoError(t, db.Model(&p).UpdateColumn("updated_at", updatedAt).Error)
+		return p
+	}
+	old := newPayment("completed", now.Add(-100*24*time.Hour))
+	recent := newPayment("completed", now.Add(-10*24*time.Hour))
+	oldPending := newPayment("pending", now.Add(-100*24*time.Hour))
+
+	service := NewRetentionService(db)
+	count, err := service.PurgePayments(now.Add(-retention), now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	t.Run("Old terminal payment is anonymized", func(t *testing.T) {
+		var got models.Payment
+		require.NoError(t, db.First(&got, old.ID).Error)
+		assert.NotNil(t, got.PurgedAt)
+		assert.Empty(t, got.SenderAccount)
+		assert.Empty(t, got.RecipientAccount)
+		assert.Empty(t, got.Memo)
+		assert.Empty(t, got.Notes)
+		// The ledger summary survives.
+		assert.Equal(t, 100.0, got.Amount)
+		assert.Equal(t, 1.0, got.Fee)
+		assert.Equal(t, "USD", got.Currency)
+		assert.Equal(t, "abc123", got.TxHash)
+		assert.Equal(t, uint(1), got.SenderID)
+	})
+
+	t.Run("Recent payment is kept", func(t *testing.T) {
+		var got models.Payment
+		require.NoError(t, db.First(&got, recent.ID).Error)
+		assert.Nil(t, got.PurgedAt)
+		assert.Equal(t, "GSENDER", got.SenderAccount)
+		assert.Equal(t, "rent for Jane", got.Notes.String())
+	})
+
+	t.Run("Non-terminal payment is kept", func(t *testing.T) {
+		var got models.Payment
+		require.NoError(t, db.First(&got, oldPending.ID).Error)
+		assert.Nil(t, got.PurgedAt)
+		assert.Equal(t, "GRECIPIENT", got.RecipientAccount)
+	})
+
+	t.Run("Purged payments are not purged again", func(t *testing.T) {
+		count, err := service.PurgePayments(now.Add(time.Hour), now)
+		require.NoError(t, err)
+		// Only the recent payment is newly eligible.
+		assert.Equal(t, int64(1), count)
+	})
+}
+
+func TestWebhookDeliveryCleanup(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.WebhookDelivery{}))
+	now := time.Now()
+
+	newDelivery := func(status string, age time.Duration) models.WebhookDelivery {
+		at := now.Add(-age)
+		d := models.WebhookDelivery{WebhookID: 1, Event: "payment.completed", Payload: "{}", Status: status}
+		if status != "pending" {
+			d.CompletedAt = &at
+		}
+		require.NoError(t, db.Create(&d).Error)
+		require.NoError(t, db.Model(&d).UpdateColumn("created_at", at).Error)
+		return d
+	}
+	oldDelivered := newDelivery("success", 40*24*time.Hour)
+	recentDelivered := newDelivery("success", 2*24*time.Hour)
+	oldPending := newDelivery("pending", 40*24*time.Hour)
+	oldFailed := newDelivery("failed", 40*24*time.Hour)
+	recentPending := newDelivery("pending", time.Hour)
+
+	service := NewRetentionService(db)
+
+	t.Run("Old delivered events are removed", func(t *testing.T) {
+		count, err := service.PurgeDeliveredWebhooks(now.Add(-30 * 24 * time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		var remaining int64
+		db.Unscoped().Model(&models.WebhookDelivery{}).Where("id = ?", oldDelivered.ID).Count(&remaining)
+		assert.Zero(t, remaining)
+		assert.NoError(t, db.First(&models.WebhookDelivery{}, recentDelivered.ID).Error)
+	})
+
+	t.Run("Cleanup is safe to rerun", func(t *testing.T) {
+		count, err := service.PurgeDeliveredWebhooks(now.Add(-30 * 24 * time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("Undelivered events are retained and flagged", func(t *testing.T) {
+		count, err := service.PurgeDeliveredWebhooks(now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count, "only the recent delivered event is removed")
+
+		flagged, err := service.FlagStaleDeliveries(now.Add(-24*time.Hour), now)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), flagged)
+
+		for _, id := range []uint{oldPending.ID, oldFailed.ID} {
+			var got models.WebhookDelivery
+			require.NoError(t, db.First(&got, id).Error)
+			assert.NotNil(t, got.FlaggedAt)
+		}
+		var got models.WebhookDelivery
+		require.NoError(t, db.First(&got, recentPending.ID).Error)
+		assert.Nil(t, got.FlaggedAt)
+	})
+
+	t.Run("Flagged events are not flagged again", func(t *testing.T) {
+		flagged, err := service.FlagStaleDeliveries(now.Add(-24*time.Hour), now)
+		require.NoError(t, err)
+		assert.Zero(t, flagged)
+	})
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/utils"
+	"gorm.io/gorm"
+)
+
+// PaymentStatusNeedsReview marks a processing payment whose transaction could
+// not be confirmed on-ledger within the allowed number of checks.
+const PaymentStatusNeedsReview = "needs_review"
+
+// maxSettlementBackoff caps the delay between settlement checks.
+const maxSettlementBackoff = time.Hour
+
+// TxLookup fetches the on-ledger outcome of a submitted transaction.
+// *utils.StellarClient satisfies it.
+type TxLookup interface {
+	GetTransactionOutcome(ctx context.Context, txHash string) (utils.TxOutcome, error)
+}
+
+// SettlementResult is what a settlement check did to a payment.
+type SettlementResult string
+
+const (
+	// SettlementPending means the transaction is not in a ledger yet and will be checked again.
+	SettlementPending     SettlementResult = "pending"
+	SettlementCompleted   SettlementResult = "completed"
+	SettlementFailed      SettlementResult = "failed"
+	SettlementNeedsReview SettlementResult = "needs_review"
+)
+
+type SettlementService struct {
+	db          *gorm.DB
+	lookup      TxLookup
+	maxAttempts int
+	backoff     time.Duration
+	// events receives each payment's new status once it is committed.
+	events *StatusBroker
+}
+
+// NewSettlementService creates a settlement service that gives up on a
+// transaction after maxAttempts unresolved checks, waiting backoff after the
+// first and doubling the wait after each later one.
+func NewSettlementService(db *gorm.DB, lookup TxLookup, maxAttempts int, backoff time.Duration) *SettlementService {
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	return &SettlementService{db: db, lookup: lookup, maxAttempts: maxAttempts, backoff: backoff, events: PaymentStatusEvents}
+}
+
+// ReconcileDue checks every processing payment whose next check is due and
+// returns how many reached each result. Payments sharing a transaction are
+// settled together, so each transaction is looked up once.
+func (s *SettlementService) ReconcileDue(ctx context.Context, now time.Time) (map[SettlementResult]int, error) {
+	var due []models.Payment
+	if err := s.db.Where("status = ? AND tx_hash <> '' AND (next_settlement_check_at IS NULL OR next_settlement_check_at <= ?)", "processing", now).
+		Order("id").
+		Find(&due).Error; err != nil {
+		return nil, err
+	}
+
+	counts := map[SettlementResult]int{}
+	seen := map[string]bool{}
+	for i := range due {
+		payment := &due[i]
+		if seen[payment.TxHash] {
+			continue
+		}
+		seen[payment.TxHash] = true
+
+		result, err := s.Reconcile(ctx, payment, now)
+		if err != nil {
+			if err == models.ErrConcurrentModification {
+				continue
+			}
+			return counts, err
+		}
+		counts[result]++
+	}
+	return counts, nil
+}
+
+// Reconcile looks up the transaction of a processing payment and applies the
+// outcome to every processing payment submitted in it: a successful
+// transaction completes them, a failed one fails them with its result codes.
+// While the transaction is unknown (or the lookup errors) the payment is
+// rescheduled with exponential backoff, and after maxAttempts such checks it
+// is moved to needs_review for an operator.
+func (s *SettlementService) Reconcile(ctx context.Context, payment *models.Payment, now time.Time) (SettlementResult, error) {
+	log := logger.Log.WithFields(logrus.Fields{
+		"payment_id": payment.ID,
+		"tx_hash":    payment.TxHash,
+	})
+
+	outcome, err := s.lookup.GetTransactionOutcome(ctx, payment.TxHash)
+	if err != nil {
+		log.WithError(err).Warn("Settlement check failed")
+		outcome = utils.TxOutcome{Status: utils.TxStatusPending}
+	}
+
+	var result SettlementResult
+	var updates map[string]interface{}
+	switch outcome.Status {
+	case utils.TxStatusSuccess:
+		result = SettlementCompleted
+		updates = map[string]interface{}{"status": "completed"}
+	case utils.TxStatusFailed:
+		result = SettlementFailed
+		updates = map[string]interface{}{"status": "failed"}
+		if outcome.Result != nil {
+			updates["result_codes"] = outcome.Result.String()
+		}
+	default:
+		checks := payment.SettlementChecks + 1
+		if checks >= s.maxAttempts {
+			result = SettlementNeedsReview
+			updates = map[string]interface{}{"status": PaymentStatusNeedsReview, "settlement_checks": checks}
+		} else {
+			result = SettlementPending
+			updates = map[string]interface{}{
+				"settlement_checks":        checks,
+				"next_settlement_check_at": now.Add(s.delay(checks)),
+			}
+		}
+	}
+
+	var payments []models.Payment
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tx_hash = ? AND status = ?", payment.TxHash, "processing").Find(&payments).Error; err != nil {
+			return err
+		}
+		for i := range payments {
+			// The payment being reconciled keeps the version the caller read,
+			// so a concurrent change to it aborts the whole transaction.
+			if payments[i].ID == payment.ID {
+				payments[i].Version = payment.Version
+			}
+			if err := payments[i].UpdateVersioned(tx, updates); err != nil {
+				return err
+			}
+			if status, ok := updates["status"].(string); ok {
+				payments[i].Status = status
+			}
+			if result != SettlementCompleted {
+				continue
+			}
+			if _, err := NewInvoiceService(tx).MarkPaidForPayment(&payments[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if result != SettlementPending {
+		s.events.PublishPayment(payments...)
+	}
+
+	switch result {
+	case SettlementPending:
+		log.WithField("checks", updates["settlement_checks"]).Debug("Transaction not settled yet")
+	case SettlementNeedsReview:
+		log.WithField("checks", updates["settlement_checks"]).Warn("Transaction settlement unconfirmed; payment needs review")
+	case SettlementFailed:
+		log.WithField("result_codes", updates["result_codes"]).Warn("Transaction failed on-ledger")
+	default:
+		log.Info("Transaction settled")
+	}
+	return result, nil
+}
+
+// delay is the wait after the given number of unresolved checks: backoff,
+// then doubling, capped at maxSettlementBackoff.
+func (s *SettlementService) delay(checks int) time.Duration {
+	d := s.backoff
+	for i := 1; i < checks && d < maxSettlementBackoff; i++ {
+		d *= 2
+	}
+	if d > maxSettlementBackoff {
+		d = maxSettlementBackoff
+	}
+	return d
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/models"
+	"github.com/yourusername/gpay-remit/utils"
+)
+
+type fakeTxLookup struct {
+	outcomes map[string]utils.TxOutcome
+	err      error
+	calls    int
+}
+
+func (f *fakeTxLookup) GetTransactionOutcome(ctx context.Context, txHash string) (utils.TxOutcome, error) {
+	f.calls++
+	if f.err != nil {
+		return utils.TxOutcome{}, f.err
+	}
+	if outcome, ok := f.outcomes[txHash]; ok {
+		return outcome, nil
+	}
+	return utils.TxOutcome{Status: utils.TxStatusPending}, nil
+}
+
+func TestSettlementReconcile(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Invoice{}))
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	lookup := &fakeTxLookup{outcomes: map[string]utils.TxOutcome{
+		"success-hash": {Status: utils.TxStatusSuccess},
+		"failed-hash": {Status: utils.TxStatusFailed, Result: &utils.TransactionResult{
+			Code:       "tx_failed",
+			Operations: []utils.OperationResult{{Index: 0, Code: "op_underfunded"}},
+		}},
+	}}
+	service := NewSettlementService(db, lookup, 3, 10*time.Second)
+
+	processing := func(hash string) *models.Payment {
+		payment := &models.Payment{SenderID: 1, RecipientID: 2, Amount: 100, Currency: "USD", Status: "processing", TxHash: hash}
+		require.NoError(t, db.Create(payment).Error)
+		return payment
+	}
+	reload := func(payment *models.Payment) models.Payment {
+		var got models.Payment
+		db.First(&got, payment.ID)
+		return got
+	}
+
+	t.Run("Successful transaction completes every payment in it", func(t *testing.T) {
+		payment := processing("success-hash")
+		sibling := processing("success-hash")
+		invoice := models.Invoice{PaymentID: payment.ID, InvoiceNo: "INV-SETTLE-1", IssuerID: 2, RecipientID: 1, Amount: 100, Currency: "USD", Status: "unpaid"}
+		require.NoError(t, db.Create(&invoice).Error)
+
+		result, err := service.Reconcile(context.Background(), payment, now)
+		require.NoError(t, err)
+		assert.Equal(t, SettlementCompleted, result)
+		assert.Equal(t, "completed", reload(payment).Status)
+		assert.Equal(t, "completed", reload(sibling).Status)
+
+		db.First(&invoice, invoice.ID)
+		assert.Equal(t, "paid", invoice.Status)
+	})
+
+	t.Run("Failed transaction records result codes", func(t *testing.T) {
+		payment := processing("failed-hash")
+
+		result, err := service.Reconcile(context.Background(), payment, now)
+		require.NoError(t, err)
+		assert.Equal(t, SettlementFailed, result)
+		got := reload(payment)
+		assert.Equal(t, "failed", got.Status)
+		assert.Equal(t, "tx_failed: op_underfunded", got.ResultCodes)
+	})
+
+	t.Run("Unknown transaction backs off then needs review", func(t *testing.T) {
+		payment := processing("unknown-hash")
+
+		result, err := service.Reconcile(context.Background(), payment, now)
+		require.NoError(t, err)
+		assert.Equal(t, SettlementPending, result)
+		got := reload(payment)
+		assert.Equal(t, "processing", got.Status)
+		assert.Equal(t, 1, got.SettlementChecks)
+		require.NotNil(t, got.NextSettlementCheckAt)
+		assert.WithinDuration(t, now.Add(10*time.Second), *got.NextSettlementCheckAt, time.Second)
+
+		result, err = service.Reconcile(context.Background(), &got, now)
+		require.NoError(t, err)
+		assert.Equal(t, SettlementPending, result)
+		got = reload(payment)
+		assert.WithinDuration(t, now.Add(20*time.Second), *got.NextSettlementCheckAt, time.Second)
+
+		result, err = service.Reconcile(context.Background(), &got, now)
+		require.NoError(t, err)
+		assert.Equal(t, SettlementNeedsReview, result)
+		got = reload(payment)
+		assert.Equal(t, PaymentStatusNeedsReview, got.Status)
+		assert.Equal(t, 3, got.SettlementChecks)
+	})
+
+	t.Run("Lookup errors count as unresolved checks", func(t *testing.T) {
+		payment := processing("error-hash")
+		failing := NewSettlementService(db, &fakeTxLookup{err: assert.AnError}, 1, time.Second)
+
+		result, err := failing.Reconcile(context.Background(), payment, now)
+		require.NoError(t, err)
+		assert.Equal(t, SettlementNeedsReview, result)
+		assert.Equal(t, PaymentStatusNeedsReview, reload(payment).Status)
+	})
+}
+
+func TestSettlementReconcileDue(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Invoice{}))
+	now := time.Now()
+	later := now.Add(time.Hour)
+
+	batch := []models.Payment{
+		{SenderID: 1, Amount: 10, Currency: "USD", Status: "processing", TxHash: "batch-hash", BatchID: "b1"},
+		{SenderID: 1, Amount: 20, Currency: "USD", Status: "processing", TxHash: "batch-hash", BatchID: "b1"},
+	}
+	notDue := models.Payment{SenderID: 1, Amount: 30, Currency: "USD", Status: "processing", TxHash: "later-hash", NextSettlementCheckAt: &later}
+	pending := models.Payment{SenderID: 1, Amount: 40, Currency: "USD", Status: "pending"}
+	for _, p := range []*models.Payment{&batch[0], &batch[1], &notDue, &pending} {
+		require.NoError(t, db.Create(p).Error)
+	}
+
+	lookup := &fakeTxLookup{outcomes: map[string]utils.TxOutcome{"batch-hash": {Status: utils.TxStatusSuccess}}}
+	counts, err := NewSettlementService(db, lookup, 5, time.Second).ReconcileDue(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, counts[SettlementCompleted])
+	assert.Equal(t, 1, lookup.calls, "a shared transaction is looked up once and payments not yet due are skipped")
+
+	var statuses []string
+	db.Model(&models.Payment{}).Order("id").Pluck("status", &statuses)
+	assert.Equal(t, []string{"completed", "completed", "processing", "pending"}, statuses)
+}
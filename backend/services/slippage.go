@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/yourusername/gpay-remit/models"
+)
+
+var (
+	// ErrNoConversion is returned for payments that do not change currency.
+	ErrNoConversion = errors.New("payment has no currency conversion")
+	// ErrRatesNotRecorded is returned when a payment lacks a quoted or executed rate.
+	ErrRatesNotRecorded = errors.New("quoted or executed rate not recorded")
+)
+
+// SlippageReport compares the rate quoted when a remittance was created with
+// the rate it executed at. Amounts are in TargetCurrency; a negative
+// SlippageAmount means the recipient received less than quoted.
+type SlippageReport struct {
+	RemittanceID    uint    `json:"remittance_id"`
+	Amount          float64 `json:"amount"`
+	Currency        string  `json:"currency"`
+	TargetCurrency  string  `json:"target_currency"`
+	QuotedRate      float64 `json:"quoted_rate"`
+	ExecutedRate    float64 `json:"executed_rate"`
+	QuotedAmount    float64 `json:"quoted_amount"`
+	ExecutedAmount  float64 `json:"executed_amount"`
+	SlippageAmount  float64 `json:"slippage_amount"`
+	SlippagePercent float64 `json:"slippage_percent"`
+}
+
+// IsCrossCurrency reports whether payment converts into another currency.
+func IsCrossCurrency(payment *models.Payment) bool {
+	return payment.TargetCurrency != "" && !strings.EqualFold(payment.TargetCurrency, payment.Currency)
+}
+
+// ConversionRate returns units of "to" per unit of "from", using the direct
+// pair when known and otherwise inverting the reverse pair.
+func ConversionRate(ctx context.Context, rates RateSource, from, to string) (float64, error) {
+	return conversionRate(ctx, rates, strings.ToUpper(from), strings.ToUpper(to))
+}
+
+// ConvertAmount applies rate to amount, rounded like every other money value.
+func ConvertAmount(amount, rate float64) float64 {
+	return roundMoney(amount * rate)
+}
+
+// Slippage builds the slippage report for a cross-currency payment from its
+// stored quoted and executed rates.
+func Slippage(payment *models.Payment) (SlippageReport, error) {
+	if !IsCrossCurrency(payment) {
+		return SlippageReport{}, ErrNoConversion
+	}
+	if payment.QuotedRate <= 0 || payment.ExecutedRate <= 0 {
+		return SlippageReport{}, ErrRatesNotRecorded
+	}
+
+	quoted := ConvertAmount(payment.Amount, payment.QuotedRate)
+	executed := ConvertAmount(payment.Amount, payment.ExecutedRate)
+	percent := (payment.ExecutedRate - payment.QuotedRate) / payment.QuotedRate * 100
+	return SlippageReport{
+		RemittanceID:    payment.ID,
+		Amount:          payment.Amount,
+		Currency:        payment.Currency,
+		TargetCurrency:  payment.TargetCurrency,
+		QuotedRate:      payment.QuotedRate,
+		ExecutedRate:    payment.ExecutedRate,
+		QuotedAmount:    quoted,
+		ExecutedAmount:  executed,
+		SlippageAmount:  roundMoney(executed - quoted),
+		SlippagePercent: math.Round(percent*10000) / 10000,
+	}, nil
+}
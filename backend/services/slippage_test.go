@@ -0,0 +1,46 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/gpay-remit/models"
+)
+
+func TestSlippage(t *testing.T) {
+	payment := &models.Payment{
+		Amount:         1000,
+		Currency:       "USD",
+		TargetCurrency: "NGN",
+		QuotedRate:     1550,
+		ExecutedRate:   1534.5,
+	}
+	payment.ID = 7
+
+	report, err := Slippage(payment)
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), report.RemittanceID)
+	assert.Equal(t, 1550000.0, report.QuotedAmount)
+	assert.Equal(t, 1534500.0, report.ExecutedAmount)
+	assert.Equal(t, -15500.0, report.SlippageAmount)
+	assert.Equal(t, -1.0, report.SlippagePercent)
+
+	// A better-than-quoted execution is positive slippage.
+	payment.ExecutedRate = 1553.1
+	report, err = Slippage(payment)
+	require.NoError(t, err)
+	assert.Equal(t, 3100.0, report.SlippageAmount)
+	assert.Equal(t, 0.2, report.SlippagePercent)
+}
+
+func TestSlippageUnavailable(t *testing.T) {
+	_, err := Slippage(&models.Payment{Amount: 10, Currency: "USD", TargetCurrency: "usd", QuotedRate: 1, ExecutedRate: 1})
+	assert.Equal(t, ErrNoConversion, err)
+
+	_, err = Slippage(&models.Payment{Amount: 10, Currency: "USD", QuotedRate: 1, ExecutedRate: 1})
+	assert.Equal(t, ErrNoConversion, err)
+
+	_, err = Slippage(&models.Payment{Amount: 10, Currency: "USD", TargetCurrency: "EUR", QuotedRate: 0.92})
+	assert.Equal(t, ErrRatesNotRecorded, err)
+}
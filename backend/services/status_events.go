@@ -0,0 +1,102 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/models"
+)
+
+// statusSubscriberBuffer is how many events a subscriber may fall behind by
+// before further events for it are dropped.
+const statusSubscriberBuffer = 16
+
+// StatusEvent reports that a payment moved to a new status.
+type StatusEvent struct {
+	PaymentID uint      `json:"payment_id"`
+	Status    string    `json:"status"`
+	TxHash    string    `json:"tx_hash,omitempty"`
+	At        time.Time `json:"at"`
+}
+
+// StatusBroker fans payment status changes out to in-process subscribers,
+// such as the remittance event stream. It is not durable: an event published
+// while nobody is subscribed to the payment is discarded.
+type StatusBroker struct {
+	mu   sync.Mutex
+	subs map[uint]map[chan StatusEvent]struct{}
+}
+
+// NewStatusBroker creates an empty broker.
+func NewStatusBroker() *StatusBroker {
+	return &StatusBroker{subs: make(map[uint]map[chan StatusEvent]struct{})}
+}
+
+// PaymentStatusEvents is the process-wide broker the remittance handlers and
+// background workers publish to.
+var PaymentStatusEvents = NewStatusBroker()
+
+// Subscribe returns a channel of status events for paymentID and a function
+// that cancels the subscription and closes the channel. The cancel function
+// must be called once the caller stops reading; it is safe to call more than
+// once.
+func (b *StatusBroker) Subscribe(paymentID uint) (<-chan StatusEvent, func()) {
+	ch := make(chan StatusEvent, statusSubscriberBuffer)
+
+	b.mu.Lock()
+	if b.subs[paymentID] == nil {
+		b.subs[paymentID] = make(map[chan StatusEvent]struct{})
+	}
+	b.subs[paymentID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs[paymentID], ch)
+			if len(b.subs[paymentID]) == 0 {
+				delete(b.subs, paymentID)
+			}
+			close(ch)
+		})
+	}
+}
+
+// Publish delivers event to every subscriber of its payment without
+// blocking; a subscriber whose buffer is full misses the event. Publishing to
+// a nil broker does nothing.
+func (b *StatusBroker) Publish(event StatusEvent) {
+	if b == nil {
+		return
+	}
+	if event.At.IsZero() {
+		event.At = time.Now().UTC()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs[event.PaymentID] {
+		select {
+		case ch <- event:
+		default:
+			logger.Log.WithField("payment_id", event.PaymentID).Warn("Dropped status event for slow subscriber")
+		}
+	}
+}
+
+// PublishPayment publishes the current status of each payment.
+func (b *StatusBroker) PublishPayment(payments ...models.Payment) {
+	for _, p := range payments {
+		b.Publish(StatusEvent{PaymentID: p.ID, Status: p.Status, TxHash: p.TxHash})
+	}
+}
+
+// Subscribers returns how many subscriptions are open for paymentID.
+func (b *StatusBroker) Subscribers(paymentID uint) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs[paymentID])
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusBroker(t *testing.T) {
+	broker := NewStatusBroker()
+	first, cancelFirst := broker.Subscribe(1)
+	second, cancelSecond := broker.Subscribe(1)
+	other, cancelOther := broker.Subscribe(2)
+	defer cancelSecond()
+	defer cancelOther()
+
+	broker.Publish(StatusEvent{PaymentID: 1, Status: "processing"})
+	assert.Equal(t, "processing", (<-first).Status)
+	assert.Equal(t, "processing", (<-second).Status)
+	assert.Empty(t, other)
+
+	cancelFirst()
+	cancelFirst()
+	_, ok := <-first
+	assert.False(t, ok, "cancelling closes the channel")
+	assert.Equal(t, 1, broker.Subscribers(1))
+	broker.Publish(StatusEvent{PaymentID: 1, Status: "completed"})
+	assert.Equal(t, "completed", (<-second).Status)
+
+	var nilBroker *StatusBroker
+	assert.NotPanics(t, func() { nilBroker.Publish(StatusEvent{PaymentID: 1}) })
+}
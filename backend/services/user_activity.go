@@ -0,0 +1,94 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourusername/gpay-remit/models"
+)
+
+// UserActivity summarizes one user's remittances and invoices for their
+// dashboard.
+type UserActivity struct {
+	// RemittancesByStatus counts the remittances the user sent or received.
+	RemittancesByStatus map[string]int64 `json:"remittances_by_status"`
+	// SentThisMonth totals, by currency, what the user has sent since the
+	// start of the UTC month, counting remittances as the daily limit does.
+	SentThisMonth map[string]float64 `json:"sent_this_month"`
+	// ReceivedThisMonth totals, by currency, the completed remittances the
+	// user received since the start of the UTC month.
+	ReceivedThisMonth map[string]float64 `json:"received_this_month"`
+	// PendingInvoices counts the unpaid or overdue invoices addressed to the user.
+	PendingInvoices int64 `json:"pending_invoices"`
+}
+
+// StartOfUTCMonth is midnight UTC on the first day of t's UTC month.
+func StartOfUTCMonth(t time.Time) time.Time {
+	y, m, _ := t.UTC().Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// GetUserActivity summarizes userID's activity as of now with one grouped
+// query per figure.
+func (s *AnalyticsService) GetUserActivity(userID uint, now time.Time) (*UserActivity, error) {
+	activity := &UserActivity{
+		RemittancesByStatus: map[string]int64{},
+		SentThisMonth:       map[string]float64{},
+		ReceivedThisMonth:   map[string]float64{},
+	}
+
+	var statuses []struct {
+		Status string
+		Count  int64
+	}
+	if err := s.db.Model(&models.Payment{}).
+		Select("status, COUNT(*) AS count").
+		Where("sender_id = ? OR recipient_id = ?", userID, userID).
+		Group("status").
+		Scan(&statuses).Error; err != nil {
+		return nil, fmt.Errorf("failed to count remittances: %w", err)
+	}
+	for _, row := range statuses {
+		activity.RemittancesByStatus[row.Status] = row.Count
+	}
+
+	monthStart := StartOfUTCMonth(now)
+	sent, err := s.monthlyTotals(monthStart, "sender_id = ? AND status NOT IN ?", userID, uncountedStatuses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to total sent remittances: %w", err)
+	}
+	received, err := s.monthlyTotals(monthStart, "recipient_id = ? AND status = ?", userID, "completed")
+	if err != nil {
+		return nil, fmt.Errorf("failed to total received remittances: %w", err)
+	}
+	activity.SentThisMonth, activity.ReceivedThisMonth = sent, received
+
+	if err := s.db.Model(&models.Invoice{}).
+		Where("recipient_id = ? AND status IN ?", userID, []string{"unpaid", "overdue"}).
+		Count(&activity.PendingInvoices).Error; err != nil {
+		return nil, fmt.Errorf("failed to count pending invoices: %w", err)
+	}
+	return activity, nil
+}
+
+// monthlyTotals sums the amounts of the payments matching query created
+// since monthStart, by currency.
+func (s *AnalyticsService) monthlyTotals(monthStart time.Time, query string, args ...interface{}) (map[string]float64, error) {
+	var rows []struct {
+		Currency string
+		Total    float64
+	}
+	if err := s.db.Model(&models.Payment{}).
+		Select("UPPER(currency) AS currency, COALESCE(SUM(amount), 0) AS total").
+		Where(query, args...).
+		Where("created_at >= ?", monthStart).
+		Group("UPPER(currency)").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	totals := make(map[string]float64, len(rows))
+	for _, row := range rows {
+		totals[row.Currency] = roundMoney(row.Total)
+	}
+	return totals, nil
+}
@@ -39,12 +39,26 @@ func NewWebhookDeliveryService(db *gorm.DB) *WebhookDeliveryService {
 
 // TriggerWebhook triggers webhooks for a specific event
 func (s *WebhookDeliveryService) TriggerWebhook(event string, data map[string]interface{}) error {
+	_, err := s.trigger(s.db, event, data)
+	return err
+}
+
+// TriggerUserWebhook triggers only the given user's webhooks for an event and
+// returns how many were triggered
+func (s *WebhookDeliveryService) TriggerUserWebhook(userID uint, event string, data map[string]interface{}) (int, error) {
+	return s.trigger(s.db.Where("user_id = ?", userID), event, data)
+}
+
+// trigger queues the event for every active webhook matched by query that is
+// subscribed to it and returns how many deliveries it queued.
+func (s *WebhookDeliveryService) trigger(query *gorm.DB, event string, data map[string]interface{}) (int, error) {
 	// Find all active webhooks subscribed to this event
 	var webhooks []models.Webhook
-	if err := s.db.Where("is_active = ?", true).Find(&webhooks).Error; err != nil {
-		return fmt.Errorf("failed to fetch webhooks: %w", err)
+	if err := query.Where("is_active = ?", true).Find(&webhooks).Error; err != nil {
+		return 0, fmt.Errorf("failed to fetch webhooks: %w", err)
 	}
 
+	queued := 0
 	for _, webhook := range webhooks {
 		// Check if webhook is subscribed to this event
 		events := strings.Split(webhook.Events, ",")
@@ -86,11 +100,12 @@ func (s *WebhookDeliveryService) TriggerWebhook(event string, data map[string]in
 			continue
 		}
 
+		queued++
 		// Deliver asynchronously
 		go s.DeliverWebhook(&webhook, &delivery)
 	}
 
-	return nil
+	return queued, nil
 }
 
 // DeliverWebhook delivers a webhook with retry logic
@@ -138,7 +153,7 @@ func (s *WebhookDeliveryService) DeliverWebhook(webhook *models.Webhook, deliver
 		logger.Log.WithField("webhook_id", webhook.ID).
 			WithField("delivery_id", delivery.ID).
 			WithField("attempt", attempt+1).
-			WithError(fmt.Errorf(errMsg)).
+			WithError(fmt.Errorf("%s", errMsg)).
 			Warn("Webhook delivery failed, will retry")
 	}
 
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// accountCache remembers recent ValidateAccount results so repeated checks of
+// the same account skip Horizon. Failures expire sooner than successes so a
+// transient error or a not-yet-funded account is retried quickly. It holds at
+// most size entries, evicting the least recently used.
+type accountCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	failureTTL time.Duration
+	size       int
+	entries    map[string]*list.Element
+	order      *list.List // front is most recently used
+}
+
+type accountCacheEntry struct {
+	accountID string
+	err       error
+	expires   time.Time
+}
+
+func newAccountCache(ttl, failureTTL time.Duration, size int) *accountCache {
+	return &accountCache{
+		ttl:        ttl,
+		failureTTL: failureTTL,
+		size:       size,
+		entries:    make(map[string]*list.Element, size),
+		order:      list.New(),
+	}
+}
+
+// get returns the cached validation result for accountID; ok is false when
+// there is no fresh entry.
+func (c *accountCache) get(accountID string, now time.Time) (ok bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, found := c.entries[accountID]
+	if !found {
+		return false, nil
+	}
+	entry := elem.Value.(*accountCacheEntry)
+	if !now.Before(entry.expires) {
+		c.order.Remove(elem)
+		delete(c.entries, accountID)
+		return false, nil
+	}
+	c.order.MoveToFront(elem)
+	return true, entry.err
+}
+
+// put records a validation result. Failures are skipped when failureTTL is
+// not positive.
+func (c *accountCache) put(accountID string, err error, now time.Time) {
+	ttl := c.ttl
+	if err != nil {
+		ttl = c.failureTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &accountCacheEntry{accountID: accountID, err: err, expires: now.Add(ttl)}
+	if elem, ok := c.entries[accountID]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[accountID] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*accountCacheEntry).accountID)
+	}
+}
+
+// WithAccountCache caches ValidateAccount results per account for ttl, and
+// failures for failureTTL, keeping at most size accounts. A non-positive ttl
+// or size disables the cache.
+func WithAccountCache(ttl, failureTTL time.Duration, size int) ClientOption {
+	return func(s *StellarClient) {
+		if ttl <= 0 || size <= 0 {
+			s.accounts = nil
+			return
+		}
+		s.accounts = newAccountCache(ttl, failureTTL, size)
+	}
+}
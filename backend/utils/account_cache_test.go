@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/network"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	cachedFundedAccount  = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+	cachedMissingAccount = "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"
+)
+
+// newAccountTestServer serves /accounts/{id}, reporting cachedMissingAccount
+// as not found, and counts the requests it receives.
+func newAccountTestServer(t *testing.T, hits *int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		accountID := strings.TrimPrefix(r.URL.Path, "/accounts/")
+		w.Header().Set("Content-Type", "application/json")
+		if accountID == cachedMissingAccount {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"type":"https://stellar.org/horizon-errors/not_found","title":"Resource Missing","status":404}`)
+			return
+		}
+		fmt.Fprintf(w, `{"id":%q,"account_id":%q,"sequence":"1"}`, accountID, accountID)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestValidateAccountCached(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Second validation within the TTL skips Horizon", func(t *testing.T) {
+		var hits int32
+		server := newAccountTestServer(t, &hits)
+		client := NewStellarClient(server.URL, network.TestNetworkPassphrase, WithAccountCache(time.Minute, time.Second, 10))
+
+		assert.NoError(t, client.ValidateAccount(ctx, cachedFundedAccount))
+		assert.NoError(t, client.ValidateAccount(ctx, cachedFundedAccount))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+	})
+
+	t.Run("Failures are cached", func(t *testing.T) {
+		var hits int32
+		server := newAccountTestServer(t, &hits)
+		client := NewStellarClient(server.URL, network.TestNetworkPassphrase, WithAccountCache(time.Minute, time.Second, 10))
+
+		err := client.ValidateAccount(ctx, cachedMissingAccount)
+		assert.True(t, errors.Is(err, ErrAccountNotFound))
+		err = client.ValidateAccount(ctx, cachedMissingAccount)
+		assert.True(t, errors.Is(err, ErrAccountNotFound))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+	})
+
+	t.Run("Disabled cache always asks Horizon", func(t *testing.T) {
+		var hits int32
+		server := newAccountTestServer(t, &hits)
+		client := NewStellarClient(server.URL, network.TestNetworkPassphrase, WithAccountCache(0, time.Second, 10))
+		assert.Nil(t, client.(*StellarClient).accounts)
+
+		assert.NoError(t, client.ValidateAccount(ctx, cachedFundedAccount))
+		assert.NoError(t, client.ValidateAccount(ctx, cachedFundedAccount))
+		assert.Equal(t, int32(2), atomic.LoadInt32(&hits))
+	})
+}
+
+func TestAccountCache(t *testing.T) {
+	now := time.Now()
+	errMissing := errors.New("missing")
+
+	t.Run("Failures expire sooner than successes", func(t *testing.T) {
+		cache := newAccountCache(time.Minute, 5*time.Second, 10)
+		cache.put("funded", nil, now)
+		cache.put("missing", errMissing, now)
+
+		later := now.Add(10 * time.Second)
+		ok, err := cache.get("funded", later)
+		assert.True(t, ok)
+		assert.NoError(t, err)
+		ok, _ = cache.get("missing", later)
+		assert.False(t, ok)
+
+		ok, _ = cache.get("funded", now.Add(time.Minute))
+		assert.False(t, ok)
+	})
+
+	t.Run("Zero failure TTL caches only successes", func(t *testing.T) {
+		cache := newAccountCache(time.Minute, 0, 10)
+		cache.put("missing", errMissing, now)
+		ok, _ := cache.get("missing", now)
+		assert.False(t, ok)
+	})
+
+	t.Run("Least recently used account is evicted", func(t *testing.T) {
+		cache := newAccountCache(time.Minute, time.Minute, 2)
+		cache.put("a", nil, now)
+		cache.put("b", nil, now)
+		cache.get("a", now)
+		cache.put("c", nil, now)
+
+		ok, _ := cache.get("b", now)
+		assert.False(t, ok)
+		ok, _ = cache.get("a", now)
+		assert.True(t, ok)
+		ok, _ = cache.get("c", now)
+		assert.True(t, ok)
+		assert.Equal(t, 2, cache.order.Len())
+	})
+}
@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/txnbuild"
+)
+
+// ErrMergeIntoSelf is returned for an account merge whose destination is its source.
+var ErrMergeIntoSelf = errors.New("cannot merge an account into itself")
+
+// MergeBlockedError lists why the network would reject merging an account,
+// each as a sentence the account holder can act on.
+type MergeBlockedError struct {
+	Blockers []string
+}
+
+func (e *MergeBlockedError) Error() string {
+	return "account cannot be merged: " + strings.Join(e.Blockers, "; ")
+}
+
+// MergeBlockers returns the blockers err lists if it is a *MergeBlockedError.
+func MergeBlockers(err error) ([]string, bool) {
+	var blocked *MergeBlockedError
+	if errors.As(err, &blocked) {
+		return blocked.Blockers, true
+	}
+	return nil, false
+}
+
+// AccountMergeTx is an unsigned transaction sweeping an account into another.
+type AccountMergeTx struct {
+	Envelope string `json:"tx_envelope"`
+	// Amount is the XLM the destination receives: the source's whole balance,
+	// its reserve included since the account ceases to exist, less the fee.
+	Amount Amount `json:"amount"`
+	Fee    Amount `json:"fee"`
+}
+
+// BuildAccountMergeTx builds an unsigned transaction merging source into
+// destination, which receives source's entire XLM balance. The network only
+// merges an account with no subentries that sponsors nothing into one that
+// exists, so those are checked first and reported together as a
+// *MergeBlockedError.
+func (s *StellarClient) BuildAccountMergeTx(ctx context.Context, source string, destination string) (*AccountMergeTx, error) {
+	log := logWithContext(ctx, "build_account_merge_tx").WithFields(logrus.Fields{
+		"source":      source,
+		"destination": destination,
+	})
+	log.Info("Building account merge transaction")
+
+	if source == destination {
+		return nil, ErrMergeIntoSelf
+	}
+
+	sourceAccount, err := s.accountDetail(ctx, "build_account_merge_tx", source)
+	if err != nil {
+		log.WithError(err).Error("Failed to load source account")
+		if horizonclient.IsNotFoundError(err) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to load account: %w", err)
+	}
+
+	fee := s.baseFee(ctx)
+	blockers := mergeBlockers(sourceAccount, Amount(fee))
+	if _, err := s.accountDetail(ctx, "build_account_merge_tx", destination); err != nil {
+		if !horizonclient.IsNotFoundError(err) {
+			log.WithError(err).Error("Failed to load destination account")
+			return nil, fmt.Errorf("failed to load destination account: %w", err)
+		}
+		blockers = append(blockers, fmt.Sprintf("destination account %s does not exist", destination))
+	}
+	if len(blockers) > 0 {
+		return nil, &MergeBlockedError{Blockers: blockers}
+	}
+
+	tx, err := txnbuild.NewTransaction(
+		txnbuild.TransactionParams{
+			SourceAccount:        &sourceAccount,
+			IncrementSequenceNum: true,
+			BaseFee:              fee,
+			Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+			Operations:           []txnbuild.Operation{&txnbuild.AccountMerge{Destination: destination}},
+		},
+	)
+	if err != nil {
+		log.WithError(err).Error("Failed to build account merge transaction")
+		return nil, fmt.Errorf("failed to build account merge transaction: %w", err)
+	}
+	envelope, err := tx.Base64()
+	if err != nil {
+		log.WithError(err).Error("Failed to encode transaction to XDR")
+		return nil, fmt.Errorf("failed to encode transaction to XDR: %w", err)
+	}
+
+	native, _ := nativeBalance(sourceAccount)
+	return &AccountMergeTx{Envelope: envelope, Amount: native - Amount(fee), Fee: Amount(fee)}, nil
+}
+
+// mergeBlockers lists what stops account from being merged when the
+// transaction costs fee.
+func mergeBlockers(account horizon.Account, fee Amount) []string {
+	var blockers []string
+
+	trustlines := 0
+	for _, balance := range account.Balances {
+		if balance.Type == "native" {
+			continue
+		}
+		trustlines++
+		name := balance.Code + ":" + balance.Issuer
+		if balance.Type == "liquidity_pool_shares" {
+			name = "liquidity pool " + balance.LiquidityPoolId
+			// Pool share trustlines count as two subentries.
+			trustlines++
+		}
+		if amount, err := ParseAmount(balance.Balance); err == nil && amount == 0 {
+			blockers = append(blockers, fmt.Sprintf("trustline to %s must be removed", name))
+		} else {
+			blockers = append(blockers, fmt.Sprintf("holds %s %s, which must be sent or sold and its trustline removed", balance.Balance, name))
+		}
+	}
+
+	signers := 0
+	for _, signer := range account.Signers {
+		if signer.Key != account.AccountID {
+			signers++
+		}
+	}
+	if signers > 0 {
+		blockers = append(blockers, fmt.Sprintf("additional signers (%d) must be removed", signers))
+	}
+	if len(account.Data) > 0 {
+		blockers = append(blockers, fmt.Sprintf("data entries (%d) must be removed", len(account.Data)))
+	}
+	if other := int(account.SubentryCount) - trustlines - signers - len(account.Data); other > 0 {
+		blockers = append(blockers, fmt.Sprintf("open offers (%d) must be cancelled", other))
+	}
+	if account.NumSponsoring > 0 {
+		blockers = append(blockers, fmt.Sprintf("sponsorships of other entries' reserves (%d) must be revoked or transferred", account.NumSponsoring))
+	}
+
+	if native, ok := nativeBalance(account); !ok || native < fee {
+		blockers = append(blockers, fmt.Sprintf("XLM balance does not cover the %s XLM fee", fee))
+	}
+	return blockers
+}
+
+// nativeBalance is account's XLM balance.
+func nativeBalance(account horizon.Account) (Amount, bool) {
+	for _, balance := range account.Balances {
+		if balance.Type == "native" {
+			amount, err := ParseAmount(balance.Balance)
+			return amount, err == nil
+		}
+	}
+	return 0, false
+}
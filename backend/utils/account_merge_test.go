@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/base"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMergeTestServer serves the given accounts from /accounts/{id}; any
+// other account is missing.
+func newMergeTestServer(t *testing.T, accounts ...horizon.Account) *httptest.Server {
+	t.Helper()
+	byID := map[string]horizon.Account{}
+	for _, account := range accounts {
+		byID[account.AccountID] = account
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		account, ok := byID[strings.TrimPrefix(r.URL.Path, "/accounts/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"type":"https://stellar.org/horizon-errors/not_found","title":"Resource Missing","status":404}`))
+			return
+		}
+		json.NewEncoder(w).Encode(account)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func mergeTestAccount(id, xlm string) horizon.Account {
+	return horizon.Account{
+		ID:        id,
+		AccountID: id,
+		Sequence:  1,
+		Balances:  []horizon.Balance{{Balance: xlm, Asset: base.Asset{Type: "native"}}},
+		Signers:   []horizon.Signer{{Key: id, Weight: 1, Type: "ed25519_public_key"}},
+	}
+}
+
+func TestBuildAccountMergeTx(t *testing.T) {
+	ctx := context.Background()
+	source, _ := keypair.Random()
+	destination, _ := keypair.Random()
+	issuer, _ := keypair.Random()
+
+	t.Run("Builds a merge into the destination", func(t *testing.T) {
+		server := newMergeTestServer(t, mergeTestAccount(source.Address(), "2.5000000"), mergeTestAccount(destination.Address(), "10.0000000"))
+		client := NewStellarClient(server.URL, network.TestNetworkPassphrase)
+
+		merge, err := client.BuildAccountMergeTx(ctx, source.Address(), destination.Address())
+		require.NoError(t, err)
+		assert.Equal(t, "2.4999900", merge.Amount.String(), "the whole balance, reserve included, less the fee")
+		assert.Equal(t, Amount(txnbuild.MinBaseFee), merge.Fee)
+
+		parsed, err := txnbuild.TransactionFromXDR(merge.Envelope)
+		require.NoError(t, err)
+		tx, ok := parsed.Transaction()
+		require.True(t, ok)
+		assert.Equal(t, source.Address(), tx.SourceAccount().AccountID)
+		require.Len(t, tx.Operations(), 1)
+		op, ok := tx.Operations()[0].(*txnbuild.AccountMerge)
+		require.True(t, ok, "expected an AccountMerge operation")
+		assert.Equal(t, destination.Address(), op.Destination)
+	})
+
+	t.Run("Merging into itself is refused", func(t *testing.T) {
+		server := newMergeTestServer(t, mergeTestAccount(source.Address(), "2.5000000"))
+		client := NewStellarClient(server.URL, network.TestNetworkPassphrase)
+
+		_, err := client.BuildAccountMergeTx(ctx, source.Address(), source.Address())
+		assert.ErrorIs(t, err, ErrMergeIntoSelf)
+	})
+
+	t.Run("Every blocker is listed", func(t *testing.T) {
+		account := mergeTestAccount(source.Address(), "0.0000050")
+		account.Balances = append(account.Balances,
+			horizon.Balance{Balance: "12.5000000", Asset: base.Asset{Type: "credit_alphanum4", Code: "USDC", Issuer: issuer.Address()}},
+			horizon.Balance{Balance: "0.0000000", Asset: base.Asset{Type: "credit_alphanum4", Code: "EURC", Issuer: issuer.Address()}},
+		)
+		account.Signers = append(account.Signers, horizon.Signer{Key: issuer.Address(), Weight: 1, Type: "ed25519_public_key"})
+		account.Data = map[string]string{"config": "dmFsdWU="}
+		account.SubentryCount = 2 + 1 + 1 + 1 // trustlines, signer, data entry, offer
+		account.NumSponsoring = 1
+		server := newMergeTestServer(t, account)
+		client := NewStellarClient(server.URL, network.TestNetworkPassphrase)
+
+		_, err := client.BuildAccountMergeTx(ctx, source.Address(), destination.Address())
+		blockers, ok := MergeBlockers(err)
+		require.True(t, ok, "got %v", err)
+		assert.Equal(t, []string{
+			"holds 12.5000000 USDC:" + issuer.Address() + ", which must be sent or sold and its trustline removed",
+			"trustline to EURC:" + issuer.Address() + " must be removed",
+			"additional signers (1) must be removed",
+			"data entries (1) must be removed",
+			"open offers (1) must be cancelled",
+			"sponsorships of other entries' reserves (1) must be revoked or transferred",
+			"XLM balance does not cover the 0.0000100 XLM fee",
+			"destination account " + destination.Address() + " does not exist",
+		}, blockers)
+	})
+
+	t.Run("Missing source", func(t *testing.T) {
+		server := newMergeTestServer(t)
+		client := NewStellarClient(server.URL, network.TestNetworkPassphrase)
+
+		_, err := client.BuildAccountMergeTx(ctx, source.Address(), destination.Address())
+		assert.True(t, IsAccountNotFound(err))
+	})
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go/keypair"
+)
+
+// Protocol limits on credit asset codes: 1-4 characters for alphanum4
+// assets, 5-12 for alphanum12.
+const (
+	MaxAlphanum4CodeLength  = 4
+	MaxAlphanum12CodeLength = 12
+)
+
+// ErrInvalidAsset is returned when an asset code or issuer breaks Stellar's rules.
+var ErrInvalidAsset = errors.New("invalid asset")
+
+// IsNativeAsset reports whether code refers to lumens, which have no issuer.
+func IsNativeAsset(code string) bool {
+	return strings.ToUpper(code) == "XLM"
+}
+
+// ValidateAssetCode checks that code is 1-12 ASCII letters and digits. It
+// does not check the issuer; use ValidateAsset for anything that reaches the
+// ledger.
+func ValidateAssetCode(code string) error {
+	if code == "" {
+		return fmt.Errorf("%w: asset code is required", ErrInvalidAsset)
+	}
+	if len(code) > MaxAlphanum12CodeLength {
+		return fmt.Errorf("%w: asset code %q is %d characters, maximum is %d", ErrInvalidAsset, code, len(code), MaxAlphanum12CodeLength)
+	}
+	for _, r := range code {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return fmt.Errorf("%w: asset code %q must be alphanumeric", ErrInvalidAsset, code)
+		}
+	}
+	return nil
+}
+
+// ValidateAsset checks an asset as it would be passed to the transaction
+// builder: XLM needs no issuer, and every other code must be a valid
+// alphanum4 or alphanum12 code with a valid issuer account.
+func ValidateAsset(code, issuer string) error {
+	if err := ValidateAssetCode(code); err != nil {
+		return err
+	}
+	if IsNativeAsset(code) {
+		return nil
+	}
+	if issuer == "" {
+		return fmt.Errorf("%w: asset %s requires an issuer", ErrInvalidAsset, code)
+	}
+	if _, err := keypair.ParseAddress(issuer); err != nil {
+		return fmt.Errorf("%w: issuer %q is not a valid Stellar account", ErrInvalidAsset, issuer)
+	}
+	return nil
+}
+
+// IssuerPolicy restricts which issuers' credit assets are accepted. An empty
+// Allowed list admits every issuer not in Denied. Issuers are compared
+// exactly, as Stellar addresses are case-sensitive.
+type IssuerPolicy struct {
+	Allowed []string `json:"allowlist"`
+	Denied  []string `json:"denylist"`
+}
+
+// Permits reports whether the asset code issued by issuer is accepted. XLM is
+// always accepted.
+func (p IssuerPolicy) Permits(code, issuer string) bool {
+	if IsNativeAsset(code) {
+		return true
+	}
+	for _, denied := range p.Denied {
+		if issuer == denied {
+			return false
+		}
+	}
+	if len(p.Allowed) == 0 {
+		return true
+	}
+	for _, allowed := range p.Allowed {
+		if issuer == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAsset(t *testing.T) {
+	issuerKP, _ := keypair.Random()
+	issuer := issuerKP.Address()
+	seedKP, _ := keypair.Random()
+
+	tests := []struct {
+		name    string
+		code    string
+		issuer  string
+		wantErr bool
+	}{
+		{"native XLM without issuer", "XLM", "", false},
+		{"lower-case native", "xlm", "", false},
+		{"alphanum4", "USDC", issuer, false},
+		{"single character alphanum4", "A", issuer, false},
+		{"alphanum12", "EUROCOIN2024", issuer, false},
+		{"too-long code", "ABCDEFGHIJKLMNOPQRST", issuer, true},
+		{"empty code", "", issuer, true},
+		{"non-alphanumeric code", "US-D", issuer, true},
+		{"credit asset missing issuer", "USDC", "", true},
+		{"malformed issuer", "USDC", "GNOTANACCOUNT", true},
+		{"secret seed as issuer", "USDC", seedKP.Seed(), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAsset(tt.code, tt.issuer)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidAsset)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestIssuerPolicyPermits(t *testing.T) {
+	trustedKP, _ := keypair.Random()
+	trusted := trustedKP.Address()
+	untrustedKP, _ := keypair.Random()
+	untrusted := untrustedKP.Address()
+
+	t.Run("Empty allowlist allows all", func(t *testing.T) {
+		assert.True(t, IssuerPolicy{}.Permits("USDC", untrusted))
+	})
+
+	t.Run("Allowlist", func(t *testing.T) {
+		policy := IssuerPolicy{Allowed: []string{trusted}}
+		assert.True(t, policy.Permits("USDC", trusted))
+		assert.False(t, policy.Permits("USDC", untrusted))
+		// Addresses are case-sensitive.
+		assert.False(t, policy.Permits("USDC", strings.ToLower(trusted)))
+	})
+
+	t.Run("Denylist wins over allowlist", func(t *testing.T) {
+		policy := IssuerPolicy{Allowed: []string{trusted, untrusted}, Denied: []string{untrusted}}
+		assert.True(t, policy.Permits("USDC", trusted))
+		assert.False(t, policy.Permits("USDC", untrusted))
+	})
+
+	t.Run("XLM is always permitted", func(t *testing.T) {
+		policy := IssuerPolicy{Allowed: []string{trusted}, Denied: []string{untrusted}}
+		assert.True(t, policy.Permits("XLM", ""))
+		assert.True(t, policy.Permits("xlm", untrusted))
+	})
+}
@@ -0,0 +1,184 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// DefaultReclaimWindow is how long after a claimable balance becomes
+// claimable its sender must wait to take it back, unless the client is
+// configured with WithReclaimWindow.
+const DefaultReclaimWindow = 30 * 24 * time.Hour
+
+// WithReclaimWindow sets how long after a claimable balance becomes claimable
+// the recipient has to claim it before the sender can reclaim it. Zero or
+// less keeps DefaultReclaimWindow.
+func WithReclaimWindow(window time.Duration) ClientOption {
+	return func(s *StellarClient) {
+		s.reclaimWindow = window
+	}
+}
+
+// ClaimableBalanceTx is an unsigned transaction creating a claimable balance.
+// BalanceID is known before submission because it derives from the source
+// account and sequence number, so the recipient can be told what to claim.
+type ClaimableBalanceTx struct {
+	Envelope  string `json:"tx_envelope"`
+	BalanceID string `json:"balance_id"`
+	// ClaimAfter is when the recipient may start claiming; zero means at once.
+	ClaimAfter time.Time `json:"claim_after"`
+	// ReclaimAfter is when the recipient's window closes and the sender may
+	// take the balance back.
+	ReclaimAfter time.Time `json:"reclaim_after"`
+}
+
+// ClaimPredicates returns the predicates under which the recipient and the
+// sender of a claimable balance may claim it. The recipient may claim from
+// claimAfter (at once when it is zero) until reclaimAfter:
+//
+//	recipient: AND(NOT(before claimAfter), before reclaimAfter)
+//
+// and the sender before claimAfter, cancelling the send, or from
+// reclaimAfter, when the recipient never claimed:
+//
+//	sender: OR(before claimAfter, NOT(before reclaimAfter))
+//
+// The two are complements, so at any moment exactly one party can claim.
+func ClaimPredicates(claimAfter, reclaimAfter time.Time) (recipient, sender xdr.ClaimPredicate) {
+	reclaimOpen := txnbuild.NotPredicate(txnbuild.BeforeAbsoluteTimePredicate(reclaimAfter.Unix()))
+	recipientWindowOpen := txnbuild.BeforeAbsoluteTimePredicate(reclaimAfter.Unix())
+	if claimAfter.IsZero() {
+		return recipientWindowOpen, reclaimOpen
+	}
+	beforeClaim := txnbuild.BeforeAbsoluteTimePredicate(claimAfter.Unix())
+	recipient = txnbuild.AndPredicate(txnbuild.NotPredicate(beforeClaim), recipientWindowOpen)
+	sender = txnbuild.OrPredicate(beforeClaim, reclaimOpen)
+	return recipient, sender
+}
+
+// reclaimAfter is when the sender may reclaim a balance claimable from
+// claimAfter, or from now when claimAfter is zero or already past.
+func (s *StellarClient) reclaimAfter(claimAfter, now time.Time) time.Time {
+	window := s.reclaimWindow
+	if window <= 0 {
+		window = DefaultReclaimWindow
+	}
+	if claimAfter.Before(now) {
+		claimAfter = now
+	}
+	return claimAfter.Add(window)
+}
+
+// BuildCreateClaimableBalanceTx builds an unsigned transaction moving amount
+// from sender into a claimable balance that recipient may claim from
+// claimAfter and sender may reclaim once the reclaim window has passed; see
+// ClaimPredicates. memo may be nil.
+func (s *StellarClient) BuildCreateClaimableBalanceTx(ctx context.Context, sender, recipient, assetCode, issuer, amount string, claimAfter time.Time, memo txnbuild.Memo) (*ClaimableBalanceTx, error) {
+	log := logWithContext(ctx, "build_create_claimable_balance_tx").WithFields(logrus.Fields{
+		"sender":     sender,
+		"recipient":  recipient,
+		"asset_code": assetCode,
+	})
+	log.Info("Building claimable balance transaction")
+
+	sourceAccount, err := s.accountDetail(ctx, "build_create_claimable_balance_tx", sender)
+	if err != nil {
+		log.WithError(err).Error("Failed to load source account")
+		if horizonclient.IsNotFoundError(err) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to load source account: %w", err)
+	}
+	return newClaimableBalanceTx(&sourceAccount, recipient, assetCode, issuer, amount, claimAfter, s.reclaimAfter(claimAfter, time.Now()), s.baseFee(ctx), memo)
+}
+
+// newClaimableBalanceTx builds the transaction BuildCreateClaimableBalanceTx
+// returns from an already loaded source account.
+func newClaimableBalanceTx(source txnbuild.Account, recipient, assetCode, issuer, amount string, claimAfter, reclaimAfter time.Time, baseFee int64, memo txnbuild.Memo) (*ClaimableBalanceTx, error) {
+	var asset txnbuild.Asset = txnbuild.NativeAsset{}
+	if !IsNativeAsset(assetCode) {
+		asset = txnbuild.CreditAsset{Code: assetCode, Issuer: issuer}
+	}
+	recipientPredicate, senderPredicate := ClaimPredicates(claimAfter, reclaimAfter)
+
+	tx, err := txnbuild.NewTransaction(
+		txnbuild.TransactionParams{
+			SourceAccount:        source,
+			IncrementSequenceNum: true,
+			BaseFee:              baseFee,
+			Memo:                 memo,
+			Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+			Operations: []txnbuild.Operation{
+				&txnbuild.CreateClaimableBalance{
+					Amount: amount,
+					Asset:  asset,
+					Destinations: []txnbuild.Claimant{
+						txnbuild.NewClaimant(recipient, &recipientPredicate),
+						txnbuild.NewClaimant(source.GetAccountID(), &senderPredicate),
+					},
+				},
+			},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build claimable balance transaction: %w", err)
+	}
+	balanceID, err := tx.ClaimableBalanceID(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive claimable balance ID: %w", err)
+	}
+	envelope, err := tx.Base64()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transaction to XDR: %w", err)
+	}
+	return &ClaimableBalanceTx{Envelope: envelope, BalanceID: balanceID, ClaimAfter: claimAfter, ReclaimAfter: reclaimAfter}, nil
+}
+
+// BuildClaimClaimableBalanceTx builds an unsigned transaction in which
+// claimant claims the claimable balance balanceID. The network rejects it
+// unless claimant is one of the balance's claimants and its predicate holds.
+func (s *StellarClient) BuildClaimClaimableBalanceTx(ctx context.Context, balanceID, claimant string) (string, error) {
+	log := logWithContext(ctx, "build_claim_claimable_balance_tx").WithFields(logrus.Fields{
+		"balance_id": balanceID,
+		"claimant":   claimant,
+	})
+	log.Info("Building claim claimable balance transaction")
+
+	account, err := s.accountDetail(ctx, "build_claim_claimable_balance_tx", claimant)
+	if err != nil {
+		log.WithError(err).Error("Failed to load claimant account")
+		if horizonclient.IsNotFoundError(err) {
+			return "", ErrAccountNotFound
+		}
+		return "", fmt.Errorf("failed to load account: %w", err)
+	}
+	return newClaimTx(&account, balanceID, s.baseFee(ctx))
+}
+
+// newClaimTx builds the transaction BuildClaimClaimableBalanceTx returns
+// from an already loaded claimant account.
+func newClaimTx(claimant txnbuild.Account, balanceID string, baseFee int64) (string, error) {
+	tx, err := txnbuild.NewTransaction(
+		txnbuild.TransactionParams{
+			SourceAccount:        claimant,
+			IncrementSequenceNum: true,
+			BaseFee:              baseFee,
+			Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+			Operations:           []txnbuild.Operation{&txnbuild.ClaimClaimableBalance{BalanceID: balanceID}},
+		},
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to build claim transaction: %w", err)
+	}
+	envelope, err := tx.Base64()
+	if err != nil {
+		return "", fmt.Errorf("failed to encode transaction to XDR: %w", err)
+	}
+	return envelope, nil
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimPredicates(t *testing.T) {
+	claimAfter := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	reclaimAfter := claimAfter.Add(DefaultReclaimWindow)
+
+	t.Run("Recipient window then sender", func(t *testing.T) {
+		recipient, sender := ClaimPredicates(claimAfter, reclaimAfter)
+
+		require.Equal(t, xdr.ClaimPredicateTypeClaimPredicateAnd, recipient.Type)
+		and := *recipient.AndPredicates
+		require.Len(t, and, 2)
+		require.Equal(t, xdr.ClaimPredicateTypeClaimPredicateNot, and[0].Type)
+		assert.Equal(t, xdr.Int64(claimAfter.Unix()), *(*and[0].NotPredicate).AbsBefore)
+		assert.Equal(t, xdr.Int64(reclaimAfter.Unix()), *and[1].AbsBefore)
+
+		require.Equal(t, xdr.ClaimPredicateTypeClaimPredicateOr, sender.Type)
+		or := *sender.OrPredicates
+		require.Len(t, or, 2)
+		assert.Equal(t, xdr.Int64(claimAfter.Unix()), *or[0].AbsBefore)
+		require.Equal(t, xdr.ClaimPredicateTypeClaimPredicateNot, or[1].Type)
+		assert.Equal(t, xdr.Int64(reclaimAfter.Unix()), *(*or[1].NotPredicate).AbsBefore)
+	})
+
+	t.Run("Claimable at once", func(t *testing.T) {
+		recipient, sender := ClaimPredicates(time.Time{}, reclaimAfter)
+
+		require.Equal(t, xdr.ClaimPredicateTypeClaimPredicateBeforeAbsoluteTime, recipient.Type)
+		assert.Equal(t, xdr.Int64(reclaimAfter.Unix()), *recipient.AbsBefore)
+		require.Equal(t, xdr.ClaimPredicateTypeClaimPredicateNot, sender.Type)
+		assert.Equal(t, xdr.Int64(reclaimAfter.Unix()), *(*sender.NotPredicate).AbsBefore)
+	})
+}
+
+func TestBuildCreateClaimableBalanceTx(t *testing.T) {
+	ctx := context.Background()
+	client := NewStellarClient("http://127.0.0.1:1", network.TestNetworkPassphrase, WithDryRun(true), WithReclaimWindow(48*time.Hour))
+	sender, _ := keypair.Random()
+	recipient, _ := keypair.Random()
+	claimAfter := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	built, err := client.BuildCreateClaimableBalanceTx(ctx, sender.Address(), recipient.Address(), "XLM", "", "25", claimAfter, txnbuild.MemoText("rent"))
+	require.NoError(t, err)
+	assert.True(t, claimAfter.Add(48*time.Hour).Equal(built.ReclaimAfter))
+
+	tx, err := parseTransaction(built.Envelope)
+	require.NoError(t, err)
+	expected, err := tx.ClaimableBalanceID(0)
+	require.NoError(t, err)
+	assert.Equal(t, expected, built.BalanceID)
+
+	op, ok := tx.Operations()[0].(*txnbuild.CreateClaimableBalance)
+	require.True(t, ok)
+	require.Len(t, op.Destinations, 2)
+	assert.Equal(t, recipient.Address(), op.Destinations[0].Destination)
+	assert.Equal(t, sender.Address(), op.Destinations[1].Destination)
+
+	claim, err := client.BuildClaimClaimableBalanceTx(ctx, built.BalanceID, recipient.Address())
+	require.NoError(t, err)
+	claimTx, err := parseTransaction(claim)
+	require.NoError(t, err)
+	claimOp, ok := claimTx.Operations()[0].(*txnbuild.ClaimClaimableBalance)
+	require.True(t, ok)
+	assert.Equal(t, built.BalanceID, claimOp.BalanceID)
+}
@@ -0,0 +1,306 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// ErrDryRunUnsupported is returned by DryRunClient for calls whose result
+// only the network can provide.
+var ErrDryRunUnsupported = errors.New("not available in dry-run mode")
+
+// dryRunBalance is the XLM balance DryRunClient reports for every account.
+const dryRunBalance = "10000.0000000"
+
+// noPreconditions lets a transaction be submitted at any time.
+var noPreconditions = txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()}
+
+// WithDryRun makes NewStellarClient return a DryRunClient, which never
+// contacts Horizon or Soroban RPC. The other options still set its signer,
+// base fee, and base reserve.
+func WithDryRun(enabled bool) ClientOption {
+	return func(s *StellarClient) {
+		s.dryRun = enabled
+	}
+}
+
+// DryRunClient is an offline StellarClientInterface for local development
+// and CI. Every well-formed account exists, holds dryRunBalance XLM and no
+// trustlines, and is at sequence zero, so the envelopes it builds are real,
+// signable transactions that depend only on their inputs. Submitting one
+// returns its hash without sending it, and every well-formed hash reads as a
+// successful transaction.
+type DryRunClient struct {
+	// offline supplies the signer, fees, and network passphrase; none of its
+	// network calls are used.
+	offline *StellarClient
+}
+
+// NewDryRunClient returns a DryRunClient for networkPassphrase.
+func NewDryRunClient(networkPassphrase string, opts ...ClientOption) *DryRunClient {
+	return NewStellarClient("", networkPassphrase, append(opts, WithDryRun(true))...).(*DryRunClient)
+}
+
+// account checks accountID is a well-formed public key, as Horizon would
+// before looking it up, and returns it at sequence zero.
+func (d *DryRunClient) account(accountID string) (*txnbuild.SimpleAccount, error) {
+	if _, err := keypair.ParseAddress(accountID); err != nil {
+		return nil, fmt.Errorf("invalid or non-existent account: %w", err)
+	}
+	return &txnbuild.SimpleAccount{AccountID: accountID}, nil
+}
+
+// build encodes an unsigned transaction from source running operations.
+// Transactions without escrow conditions never expire, so the same inputs
+// always give the same envelope.
+func (d *DryRunClient) build(ctx context.Context, source *txnbuild.SimpleAccount, memo txnbuild.Memo, preconditions txnbuild.Preconditions, operations ...txnbuild.Operation) (string, error) {
+	tx, err := txnbuild.NewTransaction(
+		txnbuild.TransactionParams{
+			SourceAccount:        source,
+			IncrementSequenceNum: true,
+			BaseFee:              d.offline.baseFee(ctx),
+			Memo:                 memo,
+			Preconditions:        preconditions,
+			Operations:           operations,
+		},
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to build transaction: %w", err)
+	}
+	return tx.Base64()
+}
+
+func (d *DryRunClient) SubmitPayment(ctx context.Context, source string, destination string, assetCode string, issuer string, amount string) (string, error) {
+	sourceAddress, err := d.offline.signer.Address(ctx, source)
+	if err != nil {
+		return "", fmt.Errorf("invalid source key: %w", err)
+	}
+	if _, err := d.account(destination); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{sourceAddress, destination, assetCode, issuer, amount}, "|")))
+	hash := hex.EncodeToString(sum[:])
+	logWithContext(ctx, "submit_payment").WithField("tx_hash", hash).Info("Dry run: payment not submitted")
+	return hash, nil
+}
+
+func (d *DryRunClient) ValidateAccount(ctx context.Context, accountID string) error {
+	_, err := d.account(accountID)
+	return err
+}
+
+func (d *DryRunClient) BuildEscrowTx(ctx context.Context, sender string, recipient string, assetCode string, issuer string, amount string, memo txnbuild.Memo) (string, error) {
+	source, err := d.account(sender)
+	if err != nil {
+		return "", fmt.Errorf("failed to load source account: %w", err)
+	}
+	var asset txnbuild.Asset = txnbuild.NativeAsset{}
+	if assetCode != "XLM" {
+		asset = txnbuild.CreditAsset{Code: assetCode, Issuer: issuer}
+	}
+	preconditions, err := escrowPreconditions(ctx)
+	if err != nil {
+		return "", fmt.Errorf("invalid escrow conditions: %w", err)
+	}
+	return d.build(ctx, source, memo, preconditions, &txnbuild.Payment{Destination: recipient, Amount: amount, Asset: asset})
+}
+
+func (d *DryRunClient) BuildPaymentTx(ctx context.Context, sourceAccount txnbuild.Account, destination string, assetCode string, issuer string, amount string, memo txnbuild.Memo) (*txnbuild.Transaction, error) {
+	return d.offline.BuildPaymentTx(ctx, sourceAccount, destination, assetCode, issuer, amount, memo)
+}
+
+func (d *DryRunClient) SignTx(ctx context.Context, envelopeXDR string, keyRef string) (string, error) {
+	return d.offline.SignTx(ctx, envelopeXDR, keyRef)
+}
+
+func (d *DryRunClient) BuildFeeBumpTx(ctx context.Context, innerSignedXDR string, feeAccount string, baseFee int64) (string, error) {
+	return d.offline.BuildFeeBumpTx(ctx, innerSignedXDR, feeAccount, baseFee)
+}
+
+func (d *DryRunClient) AddSignature(ctx context.Context, signedXDR string, secretKey string) (string, error) {
+	return d.offline.AddSignature(ctx, signedXDR, secretKey)
+}
+
+func (d *DryRunClient) BuildBatchPaymentTx(ctx context.Context, sourceAccount string, payments []BatchPayment) (string, error) {
+	if len(payments) == 0 {
+		return "", fmt.Errorf("batch contains no payments")
+	}
+	if len(payments) > MaxOperationsPerTx {
+		return "", fmt.Errorf("batch contains %d payments, maximum is %d", len(payments), MaxOperationsPerTx)
+	}
+	source, err := d.account(sourceAccount)
+	if err != nil {
+		return "", fmt.Errorf("failed to load source account: %w", err)
+	}
+	operations := make([]txnbuild.Operation, 0, len(payments))
+	for _, p := range payments {
+		var asset txnbuild.Asset = txnbuild.NativeAsset{}
+		if !IsNativeAsset(p.AssetCode) {
+			asset = txnbuild.CreditAsset{Code: p.AssetCode, Issuer: p.Issuer}
+		}
+		operations = append(operations, &txnbuild.Payment{Destination: p.Destination, Amount: p.Amount, Asset: asset})
+	}
+	return d.build(ctx, source, nil, noPreconditions, operations...)
+}
+
+// SubmitTransaction returns the transaction's hash without submitting it.
+func (d *DryRunClient) SubmitTransaction(ctx context.Context, signedXDR string) (string, error) {
+	genericTx, err := txnbuild.TransactionFromXDR(signedXDR)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse envelope XDR: %w", err)
+	}
+	var hash string
+	if tx, ok := genericTx.Transaction(); ok {
+		hash, err = tx.HashHex(d.offline.networkPassphrase)
+	} else if feeBump, ok := genericTx.FeeBump(); ok {
+		hash, err = feeBump.HashHex(d.offline.networkPassphrase)
+	} else {
+		return "", fmt.Errorf("XDR is not a transaction envelope")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to hash transaction: %w", err)
+	}
+	logWithContext(ctx, "submit_transaction").WithField("tx_hash", hash).Info("Dry run: transaction not submitted")
+	return hash, nil
+}
+
+// GetTransactionStatus reports every well-formed hash as successful, so
+// remittances settle whichever client submitted them.
+func (d *DryRunClient) GetTransactionStatus(ctx context.Context, txHash string) (TxStatus, error) {
+	if raw, err := hex.DecodeString(txHash); err != nil || len(raw) != sha256.Size {
+		return "", fmt.Errorf("invalid transaction hash %q", txHash)
+	}
+	return TxStatusSuccess, nil
+}
+
+func (d *DryRunClient) GetTransactionOutcome(ctx context.Context, txHash string) (TxOutcome, error) {
+	status, err := d.GetTransactionStatus(ctx, txHash)
+	if err != nil {
+		return TxOutcome{}, err
+	}
+	return TxOutcome{Status: status}, nil
+}
+
+func (d *DryRunClient) GetBalances(ctx context.Context, accountID string) ([]AccountBalance, error) {
+	if _, err := d.account(accountID); err != nil {
+		return nil, err
+	}
+	baseReserve, _ := d.GetBaseReserve(ctx)
+	total, _ := ParseAmount(dryRunBalance)
+	available := AvailableBalance(total.Float64(), 0, baseReserve, 0, 0, 0)
+	return []AccountBalance{{
+		AssetType: "native",
+		AssetCode: "XLM",
+		Balance:   dryRunBalance,
+		Available: strconv.FormatFloat(available, 'f', 7, 64),
+	}}, nil
+}
+
+func (d *DryRunClient) BuildChangeTrustTx(ctx context.Context, account string, assetCode string, issuer string, limit string) (string, error) {
+	source, err := d.account(account)
+	if err != nil {
+		return "", ErrAccountNotFound
+	}
+	tx, err := NewChangeTrustTx(source, assetCode, issuer, limit)
+	if err != nil {
+		return "", err
+	}
+	return tx.Base64()
+}
+
+func (d *DryRunClient) BuildAccountMergeTx(ctx context.Context, source string, destination string) (*AccountMergeTx, error) {
+	if source == destination {
+		return nil, ErrMergeIntoSelf
+	}
+	sourceAccount, err := d.account(source)
+	if err != nil {
+		return nil, ErrAccountNotFound
+	}
+	if _, err := d.account(destination); err != nil {
+		return nil, &MergeBlockedError{Blockers: []string{fmt.Sprintf("destination account %s does not exist", destination)}}
+	}
+	envelope, err := d.build(ctx, sourceAccount, nil, noPreconditions, &txnbuild.AccountMerge{Destination: destination})
+	if err != nil {
+		return nil, err
+	}
+	balance, _ := ParseAmount(dryRunBalance)
+	fee := Amount(d.offline.baseFee(ctx))
+	return &AccountMergeTx{Envelope: envelope, Amount: balance - fee, Fee: fee}, nil
+}
+
+func (d *DryRunClient) BuildCreateClaimableBalanceTx(ctx context.Context, sender, recipient, assetCode, issuer, amount string, claimAfter time.Time, memo txnbuild.Memo) (*ClaimableBalanceTx, error) {
+	source, err := d.account(sender)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load source account: %w", err)
+	}
+	return newClaimableBalanceTx(source, recipient, assetCode, issuer, amount, claimAfter, d.offline.reclaimAfter(claimAfter, time.Now()), d.offline.baseFee(ctx), memo)
+}
+
+func (d *DryRunClient) BuildClaimClaimableBalanceTx(ctx context.Context, balanceID, claimant string) (string, error) {
+	account, err := d.account(claimant)
+	if err != nil {
+		return "", fmt.Errorf("failed to load account: %w", err)
+	}
+	return newClaimTx(account, balanceID, d.offline.baseFee(ctx))
+}
+
+func (d *DryRunClient) GetBaseReserve(ctx context.Context) (float64, error) {
+	if d.offline.baseReserveOverride > 0 {
+		return d.offline.baseReserveOverride, nil
+	}
+	return DefaultBaseReserve, nil
+}
+
+// InvokeContract builds the contract call without simulating it, so the
+// envelope has no footprint or resource fee and could not be submitted to a
+// real network.
+func (d *DryRunClient) InvokeContract(ctx context.Context, sourceAccount string, contractID string, function string, args []xdr.ScVal) (string, error) {
+	op, err := contractCall(contractID, function, args)
+	if err != nil {
+		return "", err
+	}
+	op.SourceAccount = sourceAccount
+	source, err := d.account(sourceAccount)
+	if err != nil {
+		return "", fmt.Errorf("failed to load source account: %w", err)
+	}
+	return d.build(ctx, source, nil, noPreconditions, op)
+}
+
+func (d *DryRunClient) BuildEscrowReleaseTx(ctx context.Context, caller string, contractID string, escrowID uint64, assetCode string, issuer string) (string, error) {
+	args, err := d.offline.escrowCallArgs(caller, escrowID, assetCode, issuer)
+	if err != nil {
+		return "", err
+	}
+	return d.InvokeContract(ctx, caller, contractID, EscrowReleaseFunction, args)
+}
+
+func (d *DryRunClient) BuildEscrowRefundTx(ctx context.Context, caller string, contractID string, escrowID uint64, assetCode string, issuer string, amount Amount, reason string) (string, error) {
+	args, err := d.offline.escrowRefundArgs(caller, escrowID, assetCode, issuer, amount, reason)
+	if err != nil {
+		return "", err
+	}
+	return d.InvokeContract(ctx, caller, contractID, EscrowRefundFunction, args)
+}
+
+// QueryContract fails: a contract's state only exists on the network.
+func (d *DryRunClient) QueryContract(ctx context.Context, contractID string, function string, args ...xdr.ScVal) (xdr.ScVal, error) {
+	return xdr.ScVal{}, fmt.Errorf("query of %s.%s: %w", contractID, function, ErrDryRunUnsupported)
+}
+
+func (d *DryRunClient) GetPaymentHistory(ctx context.Context, accountID string, limit int, cursor string) (*PaymentHistory, error) {
+	if _, err := d.account(accountID); err != nil {
+		return nil, ErrAccountNotFound
+	}
+	return &PaymentHistory{AccountID: accountID, Payments: []PaymentRecord{}}, nil
+}
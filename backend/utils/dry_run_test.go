@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunClient(t *testing.T) {
+	ctx := context.Background()
+	// Nothing listens here, so any Horizon call would fail.
+	client := NewStellarClient("http://127.0.0.1:1", network.TestNetworkPassphrase, WithDryRun(true))
+	require.IsType(t, &DryRunClient{}, client)
+
+	sender, _ := keypair.Random()
+	recipient, _ := keypair.Random()
+
+	t.Run("Validates a well-formed address", func(t *testing.T) {
+		assert.NoError(t, client.ValidateAccount(ctx, sender.Address()))
+	})
+
+	t.Run("Rejects malformed addresses", func(t *testing.T) {
+		address := sender.Address()
+		last := byte('A')
+		if address[len(address)-1] == 'A' {
+			last = 'B'
+		}
+		for name, bad := range map[string]string{
+			"bad checksum": address[:len(address)-1] + string(last),
+			"too short":    address[:len(address)-1],
+			"secret seed":  sender.Seed(),
+			"empty":        "",
+		} {
+			assert.Error(t, client.ValidateAccount(ctx, bad), name)
+		}
+	})
+
+	t.Run("Builds a stable envelope", func(t *testing.T) {
+		build := func() string {
+			envelope, err := client.BuildEscrowTx(ctx, sender.Address(), recipient.Address(), "XLM", "", "25", txnbuild.MemoText("rent"))
+			require.NoError(t, err)
+			return envelope
+		}
+		envelope := build()
+		assert.Equal(t, envelope, build())
+
+		tx, err := parseTransaction(envelope)
+		require.NoError(t, err)
+		assert.Equal(t, sender.Address(), tx.SourceAccount().AccountID)
+		assert.Equal(t, int64(1), tx.SequenceNumber())
+
+		_, err = client.BuildEscrowTx(ctx, "GBADADDRESS", recipient.Address(), "XLM", "", "25", nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("Submitting returns the transaction hash", func(t *testing.T) {
+		envelope, err := client.BuildEscrowTx(ctx, sender.Address(), recipient.Address(), "XLM", "", "25", nil)
+		require.NoError(t, err)
+		signed, err := client.SignTx(ctx, envelope, sender.Seed())
+		require.NoError(t, err)
+
+		hash, err := client.SubmitTransaction(ctx, signed)
+		require.NoError(t, err)
+		tx, _ := parseTransaction(signed)
+		expected, _ := tx.HashHex(network.TestNetworkPassphrase)
+		assert.Equal(t, expected, hash)
+
+		status, err := client.GetTransactionStatus(ctx, hash)
+		assert.NoError(t, err)
+		assert.Equal(t, TxStatusSuccess, status)
+	})
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/txnbuild"
+)
+
+const ctxEscrowConditionsKey ctxKey = "escrowConditions"
+
+// EscrowConditions are release conditions BuildEscrowTx enforces on-ledger
+// through the transaction's preconditions. The zero value adds none.
+type EscrowConditions struct {
+	// ReleaseAfter, when set, is the transaction's minimum time bound: the
+	// network rejects it before then.
+	ReleaseAfter time.Time
+	// Hashlock is the hex SHA-256 hash of a secret preimage. When set, the
+	// transaction also needs a hash-x signature, which reveals the preimage,
+	// before the network accepts it (see txnbuild.Transaction.SignHashX).
+	Hashlock string
+}
+
+// WithEscrowConditions returns a context under which BuildEscrowTx applies
+// conditions to the transactions it builds.
+func WithEscrowConditions(ctx context.Context, conditions EscrowConditions) context.Context {
+	return context.WithValue(ctx, ctxEscrowConditionsKey, conditions)
+}
+
+// ParseHashlock decodes a hashlock: exactly 32 bytes as 64 hex characters.
+func ParseHashlock(hashlock string) ([]byte, error) {
+	if len(hashlock) != 64 {
+		return nil, fmt.Errorf("hashlock must be 64 hex characters (a 32-byte hash), got %d", len(hashlock))
+	}
+	hash, err := hex.DecodeString(hashlock)
+	if err != nil {
+		return nil, fmt.Errorf("hashlock must be hex: %w", err)
+	}
+	return hash, nil
+}
+
+// escrowPreconditions are the preconditions for an escrow built under ctx.
+func escrowPreconditions(ctx context.Context) (txnbuild.Preconditions, error) {
+	conditions, _ := ctx.Value(ctxEscrowConditionsKey).(EscrowConditions)
+
+	preconditions := txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()}
+	if !conditions.ReleaseAfter.IsZero() {
+		preconditions.TimeBounds = txnbuild.NewTimebounds(conditions.ReleaseAfter.Unix(), 0)
+	}
+	if conditions.Hashlock != "" {
+		hash, err := ParseHashlock(conditions.Hashlock)
+		if err != nil {
+			return txnbuild.Preconditions{}, err
+		}
+		signer, err := strkey.Encode(strkey.VersionByteHashX, hash)
+		if err != nil {
+			return txnbuild.Preconditions{}, fmt.Errorf("failed to encode hashlock signer: %w", err)
+		}
+		preconditions.ExtraSigners = []string{signer}
+	}
+	return preconditions, nil
+}
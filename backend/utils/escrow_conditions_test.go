@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildEscrowTxConditions(t *testing.T) {
+	var hits int32
+	server := newAccountTestServer(t, &hits)
+	client := NewStellarClient(server.URL, network.TestNetworkPassphrase)
+	recipientKP, _ := keypair.Random()
+
+	build := func(t *testing.T, ctx context.Context) (*txnbuild.Transaction, xdr.TransactionEnvelope) {
+		t.Helper()
+		envelope, err := client.BuildEscrowTx(ctx, cachedFundedAccount, recipientKP.Address(), "XLM", "", "10", nil)
+		require.NoError(t, err)
+		parsed, err := txnbuild.TransactionFromXDR(envelope)
+		require.NoError(t, err)
+		tx, ok := parsed.Transaction()
+		require.True(t, ok)
+		var env xdr.TransactionEnvelope
+		require.NoError(t, xdr.SafeUnmarshalBase64(envelope, &env))
+		return tx, env
+	}
+
+	t.Run("No conditions leaves the transaction unbounded", func(t *testing.T) {
+		tx, env := build(t, context.Background())
+		assert.Equal(t, txnbuild.NewInfiniteTimeout(), tx.Timebounds())
+		assert.Nil(t, env.Preconditions().V2)
+	})
+
+	t.Run("release_after sets the minimum time bound", func(t *testing.T) {
+		releaseAfter := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+		tx, _ := build(t, WithEscrowConditions(context.Background(), EscrowConditions{ReleaseAfter: releaseAfter}))
+
+		bounds := tx.Timebounds()
+		assert.Equal(t, releaseAfter.Unix(), bounds.MinTime)
+		assert.Equal(t, int64(0), bounds.MaxTime, "no upper bound")
+	})
+
+	t.Run("hashlock adds a hash-x extra signer", func(t *testing.T) {
+		preimage := []byte("open sesame")
+		hash := sha256.Sum256(preimage)
+		hashlock := hex.EncodeToString(hash[:])
+
+		tx, env := build(t, WithEscrowConditions(context.Background(), EscrowConditions{Hashlock: hashlock}))
+		require.NotNil(t, env.Preconditions().V2)
+		signers := env.Preconditions().V2.ExtraSigners
+		require.Len(t, signers, 1)
+		expected, err := strkey.Encode(strkey.VersionByteHashX, hash[:])
+		require.NoError(t, err)
+		assert.Equal(t, expected, signers[0].Address())
+
+		// Revealing the preimage is what satisfies the extra signer.
+		signed, err := tx.SignHashX(preimage)
+		require.NoError(t, err)
+		assert.Len(t, signed.Signatures(), 1)
+	})
+
+	t.Run("Malformed hashlock is refused", func(t *testing.T) {
+		_, err := client.BuildEscrowTx(WithEscrowConditions(context.Background(), EscrowConditions{Hashlock: "abc"}),
+			cachedFundedAccount, recipientKP.Address(), "XLM", "", "10", nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestParseHashlock(t *testing.T) {
+	hash, err := ParseHashlock(hex.EncodeToString(make([]byte, 32)))
+	require.NoError(t, err)
+	assert.Len(t, hash, 32)
+
+	_, err = ParseHashlock("ab")
+	assert.Error(t, err)
+	_, err = ParseHashlock(string(make([]byte, 64)))
+	assert.Error(t, err, "not hex")
+}
@@ -0,0 +1,237 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/stellar/go/xdr"
+)
+
+// EscrowGetFunction is the escrow contract function that returns an escrow's
+// stored state, or nothing when there is no such escrow.
+const EscrowGetFunction = "get_escrow"
+
+// Escrow statuses, as named by the contract's EscrowStatus enum.
+const (
+	EscrowStatusPending   = "Pending"
+	EscrowStatusFunded    = "Funded"
+	EscrowStatusApproved  = "Approved"
+	EscrowStatusReleased  = "Released"
+	EscrowStatusRefunded  = "Refunded"
+	EscrowStatusExpired   = "Expired"
+	EscrowStatusDisputed  = "Disputed"
+	EscrowStatusCancelled = "Cancelled"
+)
+
+// ErrEscrowNotFound is returned when the contract has no escrow with the
+// requested ID.
+var ErrEscrowNotFound = errors.New("escrow not found on-chain")
+
+// IsEscrowNotFound reports whether err means the contract has no such escrow.
+func IsEscrowNotFound(err error) bool {
+	return errors.Is(err, ErrEscrowNotFound)
+}
+
+// EscrowReleaseConditions is the contract's ReleaseCondition for an escrow.
+type EscrowReleaseConditions struct {
+	ExpirationTimestamp uint64 `json:"expiration_timestamp"`
+	RecipientApproval   bool   `json:"recipient_approval"`
+	OracleConfirmation  bool   `json:"oracle_confirmation"`
+	MinApprovals        uint32 `json:"min_approvals"`
+	CurrentApprovals    uint32 `json:"current_approvals"`
+}
+
+// EscrowState is an escrow as stored by the escrow contract. Amounts are in
+// asset units with seven decimal places.
+type EscrowState struct {
+	EscrowID          uint64                  `json:"escrow_id"`
+	Status            string                  `json:"status"`
+	Sender            string                  `json:"sender"`
+	Recipient         string                  `json:"recipient"`
+	Amount            string                  `json:"amount"`
+	DepositedAmount   string                  `json:"deposited_amount"`
+	ReleasedAmount    string                  `json:"released_amount"`
+	RefundedAmount    string                  `json:"refunded_amount"`
+	ReleaseConditions EscrowReleaseConditions `json:"release_conditions"`
+	ReleaseTimestamp  uint64                  `json:"release_timestamp"`
+	RefundTimestamp   uint64                  `json:"refund_timestamp"`
+}
+
+// EscrowQueryArgs are the arguments of get_escrow(escrow_id).
+func EscrowQueryArgs(escrowID uint64) []xdr.ScVal {
+	id := xdr.Uint64(escrowID)
+	return []xdr.ScVal{{Type: xdr.ScValTypeScvU64, U64: &id}}
+}
+
+// DecodeEscrow decodes the value returned by get_escrow. It returns
+// ErrEscrowNotFound when the contract returned no escrow.
+func DecodeEscrow(val xdr.ScVal) (*EscrowState, error) {
+	if val.Type == xdr.ScValTypeScvVoid {
+		return nil, ErrEscrowNotFound
+	}
+	fields, err := scStruct(val)
+	if err != nil {
+		return nil, fmt.Errorf("invalid escrow: %w", err)
+	}
+
+	d := &scDecoder{fields: fields}
+	state := &EscrowState{
+		EscrowID:         d.u64("escrow_id"),
+		Status:           d.enum("status"),
+		Sender:           d.address("sender"),
+		Recipient:        d.address("recipient"),
+		Amount:           d.amount("amount"),
+		DepositedAmount:  d.amount("deposited_amount"),
+		ReleasedAmount:   d.amount("released_amount"),
+		RefundedAmount:   d.amount("refunded_amount"),
+		ReleaseTimestamp: d.u64("release_timestamp"),
+		RefundTimestamp:  d.u64("refund_timestamp"),
+	}
+	if d.err != nil {
+		return nil, fmt.Errorf("invalid escrow: %w", d.err)
+	}
+
+	conditions, err := scStruct(fields["release_conditions"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid escrow release_conditions: %w", err)
+	}
+	d = &scDecoder{fields: conditions}
+	state.ReleaseConditions = EscrowReleaseConditions{
+		ExpirationTimestamp: d.u64("expiration_timestamp"),
+		RecipientApproval:   d.bool("recipient_approval"),
+		OracleConfirmation:  d.bool("oracle_confirmation"),
+		MinApprovals:        d.u32("min_approvals"),
+		CurrentApprovals:    d.u32("current_approvals"),
+	}
+	if d.err != nil {
+		return nil, fmt.Errorf("invalid escrow release_conditions: %w", d.err)
+	}
+	return state, nil
+}
+
+// scStruct returns the fields of a contract struct, which is encoded as a
+// map keyed by field name.
+func scStruct(val xdr.ScVal) (map[string]xdr.ScVal, error) {
+	m, ok := val.GetMap()
+	if !ok || m == nil {
+		return nil, fmt.Errorf("expected a struct, got %s", val.Type)
+	}
+	fields := make(map[string]xdr.ScVal, len(*m))
+	for _, entry := range *m {
+		key, ok := entry.Key.GetSym()
+		if !ok {
+			return nil, fmt.Errorf("expected a field name, got %s", entry.Key.Type)
+		}
+		fields[string(key)] = entry.Val
+	}
+	return fields, nil
+}
+
+// scDecoder reads typed struct fields, keeping the first error so a whole
+// struct can be decoded before checking.
+type scDecoder struct {
+	fields map[string]xdr.ScVal
+	err    error
+}
+
+func (d *scDecoder) field(name string) (xdr.ScVal, bool) {
+	val, ok := d.fields[name]
+	if !ok && d.err == nil {
+		d.err = fmt.Errorf("missing field %s", name)
+	}
+	return val, ok
+}
+
+func (d *scDecoder) fail(name string, val xdr.ScVal) {
+	if d.err == nil {
+		d.err = fmt.Errorf("field %s has unexpected type %s", name, val.Type)
+	}
+}
+
+func (d *scDecoder) u64(name string) uint64 {
+	val, ok := d.field(name)
+	if !ok {
+		return 0
+	}
+	v, ok := val.GetU64()
+	if !ok {
+		d.fail(name, val)
+	}
+	return uint64(v)
+}
+
+func (d *scDecoder) u32(name string) uint32 {
+	val, ok := d.field(name)
+	if !ok {
+		return 0
+	}
+	v, ok := val.GetU32()
+	if !ok {
+		d.fail(name, val)
+	}
+	return uint32(v)
+}
+
+func (d *scDecoder) bool(name string) bool {
+	val, ok := d.field(name)
+	if !ok {
+		return false
+	}
+	v, ok := val.GetB()
+	if !ok {
+		d.fail(name, val)
+	}
+	return v
+}
+
+func (d *scDecoder) address(name string) string {
+	val, ok := d.field(name)
+	if !ok {
+		return ""
+	}
+	addr, ok := val.GetAddress()
+	if !ok {
+		d.fail(name, val)
+		return ""
+	}
+	s, err := addr.String()
+	if err != nil && d.err == nil {
+		d.err = fmt.Errorf("field %s: %w", name, err)
+	}
+	return s
+}
+
+// amount reads an i128 in stroops as a seven-decimal amount.
+func (d *scDecoder) amount(name string) string {
+	val, ok := d.field(name)
+	if !ok {
+		return ""
+	}
+	parts, ok := val.GetI128()
+	if !ok {
+		d.fail(name, val)
+		return ""
+	}
+	stroops := new(big.Int).Lsh(big.NewInt(int64(parts.Hi)), 64)
+	stroops.Or(stroops, new(big.Int).SetUint64(uint64(parts.Lo)))
+	return new(big.Rat).SetFrac(stroops, big.NewInt(10_000_000)).FloatString(7)
+}
+
+// enum reads a unit enum variant, encoded as a vector holding its name.
+func (d *scDecoder) enum(name string) string {
+	val, ok := d.field(name)
+	if !ok {
+		return ""
+	}
+	vec, ok := val.GetVec()
+	if !ok || vec == nil || len(*vec) == 0 {
+		d.fail(name, val)
+		return ""
+	}
+	variant, ok := (*vec)[0].GetSym()
+	if !ok {
+		d.fail(name, val)
+	}
+	return string(variant)
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scStructVal(fields map[string]xdr.ScVal) xdr.ScVal {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	m := &xdr.ScMap{}
+	for _, name := range names {
+		sym := xdr.ScSymbol(name)
+		*m = append(*m, xdr.ScMapEntry{Key: xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}, Val: fields[name]})
+	}
+	return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &m}
+}
+
+func scU64Val(v uint64) xdr.ScVal {
+	u := xdr.Uint64(v)
+	return xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &u}
+}
+
+func scU32Val(v uint32) xdr.ScVal {
+	u := xdr.Uint32(v)
+	return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &u}
+}
+
+func scBoolVal(v bool) xdr.ScVal {
+	return xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &v}
+}
+
+func scI128Val(v int64) xdr.ScVal {
+	parts := xdr.Int128Parts{Lo: xdr.Uint64(v)}
+	if v < 0 {
+		parts.Hi = -1
+	}
+	return xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &parts}
+}
+
+func scAccountVal(t *testing.T, address string) xdr.ScVal {
+	var id xdr.AccountId
+	require.NoError(t, id.SetAddress(address))
+	return xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &id}}
+}
+
+func scEnumVal(variant string) xdr.ScVal {
+	sym := xdr.ScSymbol(variant)
+	vec := &xdr.ScVec{{Type: xdr.ScValTypeScvSymbol, Sym: &sym}}
+	return xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vec}
+}
+
+// testEscrowVal encodes an escrow the way get_escrow returns it, with the
+// fields this package reads.
+func testEscrowVal(t *testing.T, sender, recipient, status string) xdr.ScVal {
+	return scStructVal(map[string]xdr.ScVal{
+		"escrow_id":         scU64Val(42),
+		"status":            scEnumVal(status),
+		"sender":            scAccountVal(t, sender),
+		"recipient":         scAccountVal(t, recipient),
+		"amount":            scI128Val(1_000_000_000),
+		"deposited_amount":  scI128Val(1_000_000_000),
+		"released_amount":   scI128Val(0),
+		"refunded_amount":   scI128Val(0),
+		"release_timestamp": scU64Val(0),
+		"refund_timestamp":  scU64Val(0),
+		"memo":              {Type: xdr.ScValTypeScvString, Str: func() *xdr.ScString { s := xdr.ScString("rent"); return &s }()},
+		"release_conditions": scStructVal(map[string]xdr.ScVal{
+			"expiration_timestamp": scU64Val(1735689600),
+			"recipient_approval":   scBoolVal(true),
+			"oracle_confirmation":  scBoolVal(false),
+			"min_approvals":        scU32Val(1),
+			"current_approvals":    scU32Val(0),
+		}),
+	})
+}
+
+func TestQueryContractEscrow(t *testing.T) {
+	senderKP, _ := keypair.Random()
+	recipientKP, _ := keypair.Random()
+	contractID := strkey.MustEncode(strkey.VersionByteContract, make([]byte, 32))
+
+	query := func(t *testing.T, simResult map[string]interface{}) (*EscrowState, error) {
+		server := newSorobanTestServer(t, senderKP.Address(), simResult)
+		defer server.Close()
+		client := NewStellarClient(server.URL, network.TestNetworkPassphrase, WithSorobanRPC(server.URL+"/rpc"))
+		result, err := client.QueryContract(context.Background(), contractID, EscrowGetFunction, EscrowQueryArgs(42)...)
+		if err != nil {
+			return nil, err
+		}
+		return DecodeEscrow(result)
+	}
+
+	t.Run("Decodes the stored escrow", func(t *testing.T) {
+		encoded, err := xdr.MarshalBase64(testEscrowVal(t, senderKP.Address(), recipientKP.Address(), EscrowStatusFunded))
+		require.NoError(t, err)
+
+		escrow, err := query(t, map[string]interface{}{
+			"results":      []map[string]interface{}{{"auth": []string{}, "xdr": encoded}},
+			"latestLedger": 10,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(42), escrow.EscrowID)
+		assert.Equal(t, EscrowStatusFunded, escrow.Status)
+		assert.Equal(t, senderKP.Address(), escrow.Sender)
+		assert.Equal(t, recipientKP.Address(), escrow.Recipient)
+		assert.Equal(t, "100.0000000", escrow.Amount)
+		assert.Equal(t, "0.0000000", escrow.ReleasedAmount)
+		assert.Equal(t, EscrowReleaseConditions{
+			ExpirationTimestamp: 1735689600,
+			RecipientApproval:   true,
+			MinApprovals:        1,
+		}, escrow.ReleaseConditions)
+	})
+
+	t.Run("Missing escrow", func(t *testing.T) {
+		encoded, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvVoid})
+		require.NoError(t, err)
+
+		_, err = query(t, map[string]interface{}{
+			"results":      []map[string]interface{}{{"auth": []string{}, "xdr": encoded}},
+			"latestLedger": 10,
+		})
+		assert.True(t, IsEscrowNotFound(err))
+	})
+
+	t.Run("Contract panic is surfaced as a simulation error", func(t *testing.T) {
+		_, err := query(t, map[string]interface{}{
+			"error":        "HostError: Error(Contract, #1)",
+			"latestLedger": 10,
+		})
+		assert.True(t, IsSimulationError(err))
+	})
+
+	t.Run("RPC not configured", func(t *testing.T) {
+		client := NewStellarClient("http://unused", network.TestNetworkPassphrase)
+		_, err := client.QueryContract(context.Background(), contractID, EscrowGetFunction)
+		assert.ErrorIs(t, err, ErrSorobanRPCNotConfigured)
+	})
+}
+
+func TestDecodeEscrowRejectsUnexpectedShape(t *testing.T) {
+	senderKP, _ := keypair.Random()
+
+	_, err := DecodeEscrow(scU64Val(1))
+	assert.Error(t, err)
+	assert.False(t, IsEscrowNotFound(err))
+
+	mistyped := scStructVal(map[string]xdr.ScVal{
+		"escrow_id": scU64Val(1),
+		"status":    scU64Val(1),
+		"sender":    scAccountVal(t, senderKP.Address()),
+	})
+	_, err = DecodeEscrow(mistyped)
+	assert.ErrorContains(t, err, "status")
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+)
+
+// WithBaseFee sets the default per-operation fee, in stroops, of the
+// transactions the client builds. Values below the network minimum keep
+// txnbuild.MinBaseFee.
+func WithBaseFee(stroops int64) ClientOption {
+	return func(s *StellarClient) {
+		s.defaultBaseFee = stroops
+	}
+}
+
+// WithRequestBaseFee returns a context that overrides the client's base fee
+// for transactions built with it, e.g. to outbid surge pricing.
+func WithRequestBaseFee(ctx context.Context, stroops int64) context.Context {
+	return context.WithValue(ctx, ctxBaseFeeKey, stroops)
+}
+
+// baseFee returns the per-operation fee for a transaction built under ctx.
+func (s *StellarClient) baseFee(ctx context.Context) int64 {
+	if fee, ok := ctx.Value(ctxBaseFeeKey).(int64); ok && fee >= txnbuild.MinBaseFee {
+		return fee
+	}
+	if s.defaultBaseFee >= txnbuild.MinBaseFee {
+		return s.defaultBaseFee
+	}
+	return txnbuild.MinBaseFee
+}
+
+// BuildFeeBumpTx wraps a signed transaction in a fee-bump transaction paid by
+// feeAccount, so a transaction stuck behind surge pricing can be resubmitted
+// at a higher fee without its source re-signing it. The inner transaction and
+// its signatures are carried over unchanged. baseFee is per operation; zero
+// uses the client's base fee. The returned envelope is unsigned and must be
+// signed by feeAccount before it is submitted.
+func (s *StellarClient) BuildFeeBumpTx(ctx context.Context, innerSignedXDR string, feeAccount string, baseFee int64) (string, error) {
+	log := logWithContext(ctx, "build_fee_bump_tx").WithFields(logrus.Fields{
+		"fee_account": feeAccount,
+		"base_fee":    baseFee,
+	})
+	log.Info("Building fee-bump transaction")
+
+	inner, err := parseTransaction(innerSignedXDR)
+	if err != nil {
+		return "", err
+	}
+	if len(inner.Signatures()) == 0 {
+		return "", fmt.Errorf("inner transaction is not signed")
+	}
+	if _, err := keypair.ParseAddress(feeAccount); err != nil {
+		return "", fmt.Errorf("invalid fee account: %w", err)
+	}
+	if baseFee == 0 {
+		baseFee = s.baseFee(ctx)
+	}
+	if baseFee < txnbuild.MinBaseFee {
+		return "", fmt.Errorf("base fee %d is below the network minimum of %d", baseFee, txnbuild.MinBaseFee)
+	}
+	if baseFee < inner.BaseFee() {
+		return "", fmt.Errorf("base fee %d is below the inner transaction's base fee of %d", baseFee, inner.BaseFee())
+	}
+
+	feeBump, err := txnbuild.NewFeeBumpTransaction(txnbuild.FeeBumpTransactionParams{
+		Inner:      inner,
+		FeeAccount: feeAccount,
+		BaseFee:    baseFee,
+	})
+	if err != nil {
+		log.WithError(err).Error("Failed to build fee-bump transaction")
+		return "", fmt.Errorf("failed to build fee-bump transaction: %w", err)
+	}
+
+	envelope, err := feeBump.Base64()
+	if err != nil {
+		return "", fmt.Errorf("failed to encode fee-bump transaction: %w", err)
+	}
+	return envelope, nil
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildFeeBumpTx(t *testing.T) {
+	passphrase := network.TestNetworkPassphrase
+	client := NewStellarClient("https://horizon-testnet.stellar.org", passphrase, WithBaseFee(500))
+	ctx := context.Background()
+
+	source, _ := keypair.Random()
+	feePayer, _ := keypair.Random()
+	_, inner := newMultisigEnvelope(t)
+	signed, err := AddSignature(ctx, inner, source.Seed(), passphrase)
+	require.NoError(t, err)
+	innerTx, err := parseTransaction(signed)
+	require.NoError(t, err)
+
+	t.Run("Round-trips with the inner signatures intact", func(t *testing.T) {
+		envelope, err := client.BuildFeeBumpTx(ctx, signed, feePayer.Address(), 1000)
+		require.NoError(t, err)
+
+		generic, err := txnbuild.TransactionFromXDR(envelope)
+		require.NoError(t, err)
+		feeBump, ok := generic.FeeBump()
+		require.True(t, ok, "envelope is a fee-bump transaction")
+		assert.Equal(t, feePayer.Address(), feeBump.FeeAccount())
+		assert.Equal(t, int64(1000), feeBump.BaseFee())
+		assert.Empty(t, feeBump.Signatures(), "the fee account has not signed yet")
+
+		bumpedInner := feeBump.InnerTransaction()
+		assert.Equal(t, innerTx.Signatures(), bumpedInner.Signatures())
+		wantHash, _ := innerTx.HashHex(passphrase)
+		gotHash, _ := bumpedInner.HashHex(passphrase)
+		assert.Equal(t, wantHash, gotHash)
+
+		again, err := feeBump.Base64()
+		require.NoError(t, err)
+		assert.Equal(t, envelope, again)
+
+		// Once the fee account signs, the inner signature is still there.
+		feeBumpSigned, err := feeBump.Sign(passphrase, feePayer)
+		require.NoError(t, err)
+		assert.Len(t, feeBumpSigned.Signatures(), 1)
+		assert.Equal(t, innerTx.Signatures(), feeBumpSigned.InnerTransaction().Signatures())
+	})
+
+	t.Run("Zero base fee uses the client default", func(t *testing.T) {
+		envelope, err := client.BuildFeeBumpTx(ctx, signed, feePayer.Address(), 0)
+		require.NoError(t, err)
+		generic, err := txnbuild.TransactionFromXDR(envelope)
+		require.NoError(t, err)
+		feeBump, _ := generic.FeeBump()
+		assert.Equal(t, int64(500), feeBump.BaseFee())
+	})
+
+	t.Run("Rejects invalid input", func(t *testing.T) {
+		_, err := client.BuildFeeBumpTx(ctx, inner, feePayer.Address(), 1000)
+		assert.ErrorContains(t, err, "not signed")
+
+		_, err = client.BuildFeeBumpTx(ctx, signed, "not-an-account", 1000)
+		assert.ErrorContains(t, err, "invalid fee account")
+
+		_, err = client.BuildFeeBumpTx(ctx, signed, feePayer.Seed(), 1000)
+		assert.Error(t, err, "a secret seed is not a fee account")
+
+		_, err = client.BuildFeeBumpTx(ctx, signed, feePayer.Address(), 50)
+		assert.ErrorContains(t, err, "network minimum")
+
+		_, err = client.BuildFeeBumpTx(ctx, "invalid_xdr", feePayer.Address(), 1000)
+		assert.Error(t, err)
+	})
+
+	t.Run("Fee may not undercut the inner transaction", func(t *testing.T) {
+		highFee := NewStellarClient("https://horizon-testnet.stellar.org", passphrase, WithBaseFee(2000))
+		tx, err := highFee.BuildPaymentTx(ctx, &txnbuild.SimpleAccount{AccountID: source.Address(), Sequence: 1}, feePayer.Address(), "XLM", "", "1", nil)
+		require.NoError(t, err)
+		tx, err = tx.Sign(passphrase, source)
+		require.NoError(t, err)
+		expensive, err := tx.Base64()
+		require.NoError(t, err)
+
+		_, err = client.BuildFeeBumpTx(ctx, expensive, feePayer.Address(), 1000)
+		assert.ErrorContains(t, err, "inner transaction's base fee")
+	})
+}
+
+func TestBaseFee(t *testing.T) {
+	ctx := context.Background()
+	source, _ := keypair.Random()
+	build := func(client StellarClientInterface, ctx context.Context) int64 {
+		tx, err := client.BuildPaymentTx(ctx, &txnbuild.SimpleAccount{AccountID: source.Address(), Sequence: 1}, source.Address(), "XLM", "", "1", nil)
+		require.NoError(t, err)
+		return tx.BaseFee()
+	}
+
+	plain := NewStellarClient("https://horizon-testnet.stellar.org", network.TestNetworkPassphrase)
+	configured := NewStellarClient("https://horizon-testnet.stellar.org", network.TestNetworkPassphrase, WithBaseFee(300))
+
+	assert.Equal(t, int64(txnbuild.MinBaseFee), build(plain, ctx))
+	assert.Equal(t, int64(300), build(configured, ctx))
+	assert.Equal(t, int64(900), build(configured, WithRequestBaseFee(ctx, 900)), "a request override wins")
+	assert.Equal(t, int64(300), build(configured, WithRequestBaseFee(ctx, 10)), "an override below the minimum is ignored")
+}
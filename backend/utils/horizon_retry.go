@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/protocols/horizon"
+)
+
+const (
+	// defaultHorizonTimeout bounds each Horizon and Soroban RPC request when
+	// WithHorizonHTTP is not used.
+	defaultHorizonTimeout = 30 * time.Second
+	// maxReadRetryBackoff caps the delay between read retries.
+	maxReadRetryBackoff = 5 * time.Second
+)
+
+// WithHorizonHTTP bounds every Horizon and Soroban RPC request by timeout
+// and retries idempotent Horizon reads up to retries more times, waiting
+// backoff before the first retry and doubling it before each later one. Only
+// server errors and timeouts are retried; submissions never are, since
+// resending one whose outcome is unknown could send it twice. A
+// non-positive timeout keeps the default.
+func WithHorizonHTTP(timeout time.Duration, retries int, backoff time.Duration) ClientOption {
+	return func(s *StellarClient) {
+		if timeout > 0 {
+			s.httpClient = &http.Client{Timeout: timeout}
+			s.client.HTTP = s.httpClient
+		}
+		if retries < 0 {
+			retries = 0
+		}
+		s.readRetries = retries
+		s.retryBackoff = backoff
+	}
+}
+
+// retryRead runs the Horizon read fn, retrying it as configured by
+// WithHorizonHTTP while it fails retryably and ctx is live. It returns fn's
+// last error.
+func (s *StellarClient) retryRead(ctx context.Context, operation string, fn func() error) error {
+	delay := s.retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= s.readRetries || !retryableHorizonError(err) {
+			return err
+		}
+		logWithContext(ctx, operation).WithError(err).WithField("attempt", attempt+1).Warn("Horizon read failed; retrying")
+
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+			if delay *= 2; delay > maxReadRetryBackoff {
+				delay = maxReadRetryBackoff
+			}
+		} else if ctx.Err() != nil {
+			return err
+		}
+	}
+}
+
+// retryableHorizonError reports whether a failed read may succeed if sent
+// again: Horizon answered with a 5xx, or the request timed out or never
+// reached it. Client errors such as a 404 are final.
+func retryableHorizonError(err error) bool {
+	if herr := horizonclient.GetError(err); herr != nil {
+		return herr.Problem.Status >= http.StatusInternalServerError
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if isTimeout(err) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// accountDetail loads an account from Horizon, retrying as a read.
+func (s *StellarClient) accountDetail(ctx context.Context, operation, accountID string) (horizon.Account, error) {
+	var account horizon.Account
+	err := s.retryRead(ctx, operation, func() error {
+		var err error
+		account, err = s.client.AccountDetail(horizonclient.AccountRequest{AccountID: accountID})
+		return err
+	})
+	return account, err
+}
+
+// transactionDetail looks a transaction up on Horizon, retrying as a read.
+func (s *StellarClient) transactionDetail(ctx context.Context, operation, txHash string) (horizon.Transaction, error) {
+	var tx horizon.Transaction
+	err := s.retryRead(ctx, operation, func() error {
+		var err error
+		tx, err = s.client.TransactionDetail(txHash)
+		return err
+	})
+	return tx, err
+}
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFlakyHorizon serves accounts and transaction submissions, answering the
+// first failures requests to each with status (or, for status 0, hanging
+// for delay first) and counting every request.
+func newFlakyHorizon(t *testing.T, failures int32, status int, delay time.Duration) (*httptest.Server, *int32, *int32) {
+	t.Helper()
+	var reads, submits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		counter := &reads
+		if r.URL.Path == "/transactions" {
+			counter = &submits
+		}
+		n := atomic.AddInt32(counter, 1)
+		w.Header().Set("Content-Type", "application/json")
+		if n <= failures {
+			if status == 0 {
+				select {
+				case <-time.After(delay):
+				case <-r.Context().Done():
+				}
+				return
+			}
+			problem := "error"
+			if status == http.StatusNotFound {
+				problem = "not_found"
+			}
+			w.WriteHeader(status)
+			fmt.Fprintf(w, `{"type":"https://stellar.org/horizon-errors/%s","title":"Error","status":%d}`, problem, status)
+			return
+		}
+		if r.URL.Path == "/transactions" {
+			fmt.Fprintf(w, `{"hash":%q,"successful":true}`, strings.Repeat("ab", 32))
+			return
+		}
+		accountID := strings.TrimPrefix(r.URL.Path, "/accounts/")
+		fmt.Fprintf(w, `{"id":%q,"account_id":%q,"sequence":"1"}`, accountID, accountID)
+	}))
+	t.Cleanup(server.Close)
+	return server, &reads, &submits
+}
+
+func TestHorizonReadRetry(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Server errors are retried", func(t *testing.T) {
+		server, reads, _ := newFlakyHorizon(t, 2, http.StatusInternalServerError, 0)
+		client := NewStellarClient(server.URL, network.TestNetworkPassphrase, WithHorizonHTTP(time.Second, 2, time.Millisecond))
+
+		assert.NoError(t, client.ValidateAccount(ctx, cachedFundedAccount))
+		assert.Equal(t, int32(3), atomic.LoadInt32(reads))
+	})
+
+	t.Run("Retries are bounded", func(t *testing.T) {
+		server, reads, _ := newFlakyHorizon(t, 10, http.StatusServiceUnavailable, 0)
+		client := NewStellarClient(server.URL, network.TestNetworkPassphrase, WithHorizonHTTP(time.Second, 2, time.Millisecond))
+
+		_, err := client.GetBalances(ctx, cachedFundedAccount)
+		assert.Error(t, err)
+		assert.Equal(t, int32(3), atomic.LoadInt32(reads))
+	})
+
+	t.Run("Client errors are not retried", func(t *testing.T) {
+		server, reads, _ := newFlakyHorizon(t, 10, http.StatusNotFound, 0)
+		client := NewStellarClient(server.URL, network.TestNetworkPassphrase, WithHorizonHTTP(time.Second, 2, time.Millisecond))
+
+		err := client.ValidateAccount(ctx, cachedFundedAccount)
+		assert.ErrorIs(t, err, ErrAccountNotFound)
+		assert.Equal(t, int32(1), atomic.LoadInt32(reads))
+	})
+
+	t.Run("Slow responses time out and are retried", func(t *testing.T) {
+		server, reads, _ := newFlakyHorizon(t, 1, 0, time.Second)
+		client := NewStellarClient(server.URL, network.TestNetworkPassphrase, WithHorizonHTTP(50*time.Millisecond, 1, time.Millisecond))
+
+		start := time.Now()
+		assert.NoError(t, client.ValidateAccount(ctx, cachedFundedAccount))
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		assert.Equal(t, int32(2), atomic.LoadInt32(reads))
+	})
+
+	t.Run("Cancelled context stops retrying", func(t *testing.T) {
+		server, reads, _ := newFlakyHorizon(t, 10, http.StatusInternalServerError, 0)
+		client := NewStellarClient(server.URL, network.TestNetworkPassphrase, WithHorizonHTTP(time.Second, 5, time.Hour))
+
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		assert.Error(t, client.ValidateAccount(cancelled, cachedFundedAccount))
+		assert.Equal(t, int32(1), atomic.LoadInt32(reads))
+	})
+}
+
+func TestHorizonSubmitNotRetried(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Server error", func(t *testing.T) {
+		server, _, submits := newFlakyHorizon(t, 1, http.StatusInternalServerError, 0)
+		client := NewStellarClient(server.URL, network.TestNetworkPassphrase, WithHorizonHTTP(time.Second, 3, time.Millisecond))
+
+		_, err := client.SubmitTransaction(ctx, "AAAA")
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(submits))
+	})
+
+	t.Run("Timeout leaves the outcome unknown", func(t *testing.T) {
+		server, _, submits := newFlakyHorizon(t, 1, 0, time.Second)
+		client := NewStellarClient(server.URL, network.TestNetworkPassphrase, WithHorizonHTTP(50*time.Millisecond, 3, time.Millisecond))
+
+		start := time.Now()
+		_, err := client.SubmitTransaction(ctx, "AAAA")
+		require.Error(t, err)
+		assert.True(t, IsSubmitTimeout(err))
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		assert.Equal(t, int32(1), atomic.LoadInt32(submits))
+	})
+}
+
+func TestRetryableHorizonError(t *testing.T) {
+	assert.False(t, retryableHorizonError(context.Canceled))
+	assert.True(t, retryableHorizonError(context.DeadlineExceeded))
+	assert.False(t, retryableHorizonError(fmt.Errorf("malformed response")))
+}
@@ -0,0 +1,193 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/yourusername/gpay-remit/models"
+)
+
+// currencySymbols maps currency/asset codes to the symbol printed on invoices.
+// Only symbols representable in the PDF core fonts (cp1252) are listed; other
+// codes fall back to a "CODE 1,234.56" rendering.
+var currencySymbols = map[string]string{
+	"USD":  "$",
+	"USDC": "$",
+	"EUR":  "€",
+	"EURC": "€",
+	"GBP":  "£",
+	"JPY":  "¥",
+}
+
+// InvoiceParty is the display information for an invoice issuer or recipient.
+type InvoiceParty struct {
+	Name           string
+	Email          string
+	StellarAddress string
+}
+
+// InvoiceLineItem is a single row of the invoice line item table.
+type InvoiceLineItem struct {
+	Description string
+	Quantity    int
+	UnitPrice   float64
+}
+
+// FormatMoney renders an amount with thousands separators and two decimals,
+// prefixed by the currency symbol when one is known.
+func FormatMoney(amount float64, currency string) string {
+	code := strings.ToUpper(currency)
+	negative := amount < 0
+	if negative {
+		amount = -amount
+	}
+
+	raw := fmt.Sprintf("%.2f", amount)
+	intPart, fracPart := raw[:len(raw)-3], raw[len(raw)-2:]
+
+	var grouped strings.Builder
+	for i, digit := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+	number := grouped.String() + "." + fracPart
+
+	sign := ""
+	if negative {
+		sign = "-"
+	}
+	if symbol, ok := currencySymbols[code]; ok {
+		return sign + symbol + number
+	}
+	return strings.TrimSpace(sign + code + " " + number)
+}
+
+// InvoiceLineItems returns the line items rendered for an invoice. Invoices
+// currently carry a single charge, described by the invoice description.
+func InvoiceLineItems(invoice *models.Invoice) []InvoiceLineItem {
+	description := invoice.Description
+	if description == "" {
+		description = fmt.Sprintf("Remittance payment #%d", invoice.PaymentID)
+	}
+	return []InvoiceLineItem{{Description: description, Quantity: 1, UnitPrice: invoice.Amount}}
+}
+
+// InvoicePDFHash returns the hex SHA-256 digest of a rendered invoice PDF.
+func InvoicePDFHash(pdf []byte) string {
+	sum := sha256.Sum256(pdf)
+	return hex.EncodeToString(sum[:])
+}
+
+// RenderInvoicePDF renders an invoice to PDF bytes, with the party details
+// snapshotted on the invoice. Only what is fixed when the invoice is issued is
+// printed, so a status change does not alter the document. Rendering is
+// deterministic: both document dates come from the invoice and catalog
+// entries are emitted in sorted order, so the same invoice always yields the
+// same bytes and InvoicePDFHash can be re-derived later.
+func RenderInvoicePDF(invoice *models.Invoice) ([]byte, error) {
+	issued := invoice.CreatedAt.UTC()
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle(fmt.Sprintf("Invoice %s", invoice.InvoiceNo), false)
+	pdf.SetCreationDate(issued)
+	pdf.SetModificationDate(issued)
+	pdf.SetCatalogSort(true)
+	pdf.AddPage()
+
+	// Title
+	pdf.SetFont("Arial", "B", 18)
+	pdf.Cell(0, 10, "INVOICE")
+	pdf.Ln(12)
+
+	// Invoice metadata
+	pdf.SetFont("Arial", "", 10)
+	dueDate := "Due on receipt"
+	if invoice.DueDate != nil {
+		dueDate = invoice.DueDate.Format("2006-01-02")
+	}
+	meta := [][2]string{
+		{"Invoice No:", invoice.InvoiceNo},
+		{"Issued:", issued.Format("2006-01-02")},
+		{"Due Date:", dueDate},
+	}
+	for _, row := range meta {
+		pdf.SetFont("Arial", "B", 10)
+		pdf.CellFormat(30, 6, row[0], "", 0, "L", false, 0, "")
+		pdf.SetFont("Arial", "", 10)
+		pdf.CellFormat(0, 6, tr(row[1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(6)
+
+	// Parties
+	top := pdf.GetY()
+	issuer := InvoiceParty{Name: invoice.IssuerName, Email: invoice.IssuerEmail, StellarAddress: invoice.IssuerStellarAddress}
+	recipient := InvoiceParty{Name: invoice.RecipientName, Email: invoice.RecipientEmail, StellarAddress: invoice.RecipientStellarAddress}
+	writeInvoiceParty(pdf, tr, "From", issuer, 10, top)
+	writeInvoiceParty(pdf, tr, "Bill To", recipient, 110, top)
+	pdf.SetXY(10, top+30)
+
+	// Line items
+	widths := []float64{100, 20, 35, 35}
+	headers := []string{"Description", "Qty", "Unit Price", "Amount"}
+	pdf.SetFont("Arial", "B", 10)
+	pdf.SetFillColor(200, 220, 255)
+	for i, header := range headers {
+		pdf.CellFormat(widths[i], 8, header, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Arial", "", 10)
+	var total float64
+	for _, item := range InvoiceLineItems(invoice) {
+		lineTotal := item.UnitPrice * float64(item.Quantity)
+		total += lineTotal
+		description := item.Description
+		if len(description) > 55 {
+			description = description[:55] + "..."
+		}
+		pdf.CellFormat(widths[0], 7, tr(description), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 7, fmt.Sprintf("%d", item.Quantity), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(widths[2], 7, tr(FormatMoney(item.UnitPrice, invoice.Currency)), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 7, tr(FormatMoney(lineTotal, invoice.Currency)), "1", 0, "R", false, 0, "")
+		pdf.Ln(-1)
+	}
+
+	// Total
+	pdf.SetFont("Arial", "B", 11)
+	pdf.CellFormat(widths[0]+widths[1]+widths[2], 8, "Total ("+strings.ToUpper(invoice.Currency)+")", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(widths[3], 8, tr(FormatMoney(total, invoice.Currency)), "1", 1, "R", false, 0, "")
+
+	if invoice.Description != "" {
+		pdf.Ln(8)
+		pdf.SetFont("Arial", "B", 10)
+		pdf.Cell(0, 6, "Notes")
+		pdf.Ln(6)
+		pdf.SetFont("Arial", "", 9)
+		pdf.MultiCell(0, 5, tr(invoice.Description), "", "L", false)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render invoice PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func writeInvoiceParty(pdf *gofpdf.Fpdf, tr func(string) string, label string, party InvoiceParty, x, y float64) {
+	pdf.SetXY(x, y)
+	pdf.SetFont("Arial", "B", 10)
+	pdf.CellFormat(90, 6, label, "", 2, "L", false, 0, "")
+	pdf.SetFont("Arial", "", 9)
+	for _, line := range []string{party.Name, party.Email, party.StellarAddress} {
+		if line == "" {
+			continue
+		}
+		pdf.CellFormat(90, 5, tr(line), "", 2, "L", false, 0, "")
+	}
+}
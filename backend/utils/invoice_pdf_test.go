@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/gpay-remit/models"
+)
+
+func TestFormatMoney(t *testing.T) {
+	assert.Equal(t, "$1,234.50", FormatMoney(1234.5, "USD"))
+	assert.Equal(t, "€0.99", FormatMoney(0.99, "eur"))
+	assert.Equal(t, "£1,000,000.00", FormatMoney(1000000, "GBP"))
+	assert.Equal(t, "NGN 25,000.00", FormatMoney(25000, "NGN"))
+	assert.Equal(t, "-$5.00", FormatMoney(-5, "USD"))
+}
+
+func TestRenderInvoicePDFDeterministic(t *testing.T) {
+	invoice := &models.Invoice{
+		ID:            1,
+		CreatedAt:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		InvoiceNo:     "INV-DET-1",
+		Amount:        250,
+		Currency:      "USD",
+		Status:        "unpaid",
+		Description:   "Consulting services",
+		IssuerName:    "Issuer Co",
+		IssuerEmail:   "issuer@example.com",
+		RecipientName: "Recipient",
+	}
+
+	first, err := RenderInvoicePDF(invoice)
+	assert.NoError(t, err)
+	second, err := RenderInvoicePDF(invoice)
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, InvoicePDFHash(first), InvoicePDFHash(second))
+	assert.Len(t, InvoicePDFHash(first), 64)
+
+	invoice.Status = "paid"
+	invoice.CreatedAt = invoice.CreatedAt.In(time.FixedZone("WAT", 3600))
+	settled, err := RenderInvoicePDF(invoice)
+	assert.NoError(t, err)
+	assert.Equal(t, InvoicePDFHash(first), InvoicePDFHash(settled), "status and time zone do not change the document")
+
+	invoice.Amount = 2500
+	changed, err := RenderInvoicePDF(invoice)
+	assert.NoError(t, err)
+	assert.NotEqual(t, InvoicePDFHash(first), InvoicePDFHash(changed))
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/stellar/go/txnbuild"
+)
+
+// MaxTextMemoBytes is the protocol limit for a text memo.
+const MaxTextMemoBytes = 28
+
+// Memo types accepted by ParseMemo.
+const (
+	MemoTypeText = "text"
+	MemoTypeID   = "id"
+	MemoTypeHash = "hash"
+)
+
+// ErrInvalidMemo is returned when a memo does not fit its declared type.
+var ErrInvalidMemo = errors.New("invalid memo")
+
+// ParseMemo converts a memo type and value into a txnbuild memo. An empty
+// value yields no memo; an empty type defaults to text. Hash memos are given
+// as 64 hex characters.
+func ParseMemo(memoType string, value string) (txnbuild.Memo, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	switch strings.ToLower(memoType) {
+	case "", MemoTypeText:
+		if len(value) > MaxTextMemoBytes {
+			return nil, fmt.Errorf("%w: text memo is %d bytes, maximum is %d", ErrInvalidMemo, len(value), MaxTextMemoBytes)
+		}
+		return txnbuild.MemoText(value), nil
+	case MemoTypeID:
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: id memo must be an unsigned 64-bit integer", ErrInvalidMemo)
+		}
+		return txnbuild.MemoID(id), nil
+	case MemoTypeHash:
+		raw, err := hex.DecodeString(value)
+		if err != nil || len(raw) != 32 {
+			return nil, fmt.Errorf("%w: hash memo must be 32 bytes encoded as 64 hex characters", ErrInvalidMemo)
+		}
+		var hash txnbuild.MemoHash
+		copy(hash[:], raw)
+		return hash, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported memo type %q", ErrInvalidMemo, memoType)
+	}
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMemo(t *testing.T) {
+	t.Run("Empty value means no memo", func(t *testing.T) {
+		memo, err := ParseMemo("id", "")
+		assert.NoError(t, err)
+		assert.Nil(t, memo)
+	})
+
+	t.Run("Text defaults", func(t *testing.T) {
+		memo, err := ParseMemo("", "INV-2024-0042")
+		assert.NoError(t, err)
+		assert.Equal(t, txnbuild.MemoText("INV-2024-0042"), memo)
+	})
+
+	t.Run("Text at byte limit", func(t *testing.T) {
+		_, err := ParseMemo("text", strings.Repeat("a", MaxTextMemoBytes))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Text over byte limit", func(t *testing.T) {
+		// 10 three-byte runes are 30 bytes even though they are only 10 characters.
+		_, err := ParseMemo("text", strings.Repeat("€", 10))
+		assert.ErrorIs(t, err, ErrInvalidMemo)
+	})
+
+	t.Run("ID memo", func(t *testing.T) {
+		memo, err := ParseMemo("id", "18446744073709551615")
+		assert.NoError(t, err)
+		assert.Equal(t, txnbuild.MemoID(18446744073709551615), memo)
+	})
+
+	t.Run("ID memo not uint64", func(t *testing.T) {
+		for _, value := range []string{"-1", "18446744073709551616", "abc"} {
+			_, err := ParseMemo("id", value)
+			assert.ErrorIs(t, err, ErrInvalidMemo, value)
+		}
+	})
+
+	t.Run("Hash memo", func(t *testing.T) {
+		memo, err := ParseMemo("hash", strings.Repeat("ab", 32))
+		assert.NoError(t, err)
+		hash, ok := memo.(txnbuild.MemoHash)
+		if assert.True(t, ok) {
+			assert.Equal(t, byte(0xab), hash[31])
+		}
+
+		_, err = ParseMemo("hash", "abcd")
+		assert.ErrorIs(t, err, ErrInvalidMemo)
+	})
+
+	t.Run("Unsupported type", func(t *testing.T) {
+		_, err := ParseMemo("return", "x")
+		assert.ErrorIs(t, err, ErrInvalidMemo)
+	})
+}
+
+func TestBuildPaymentTxMemo(t *testing.T) {
+	client := NewStellarClient("https://horizon-testnet.stellar.org", network.TestNetworkPassphrase)
+	sourceKP, _ := keypair.Random()
+	destKP, _ := keypair.Random()
+
+	for _, tc := range []struct {
+		memoType string
+		value    string
+		expected txnbuild.Memo
+	}{
+		{"text", "payroll march", txnbuild.MemoText("payroll march")},
+		{"id", "4200", txnbuild.MemoID(4200)},
+	} {
+		t.Run(tc.memoType, func(t *testing.T) {
+			memo, err := ParseMemo(tc.memoType, tc.value)
+			assert.NoError(t, err)
+
+			tx, err := client.BuildPaymentTx(context.Background(), &txnbuild.SimpleAccount{AccountID: sourceKP.Address(), Sequence: 1}, destKP.Address(), "XLM", "", "1", memo)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			// Round-trip through XDR so the memo is what a wallet would see.
+			xdr, _ := tx.Base64()
+			parsed, err := txnbuild.TransactionFromXDR(xdr)
+			assert.NoError(t, err)
+			decoded, _ := parsed.Transaction()
+			assert.Equal(t, tc.expected, decoded.Memo())
+		})
+	}
+}
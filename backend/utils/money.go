@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/stellar/go/amount"
+)
+
+// ErrAmountOverflow is returned when amount arithmetic leaves the int64 range
+// Stellar can settle.
+var ErrAmountOverflow = errors.New("amount out of range")
+
+// Amount is a fixed-point money value in stroops, 1e-7 of a unit, the same
+// representation Stellar uses on-chain. Arithmetic on Amounts is exact, so
+// sums never drift the way float64 sums do. In JSON an Amount is a decimal
+// string such as "10.5000000".
+type Amount int64
+
+// ParseAmount parses a decimal string with at most seven fractional digits.
+func ParseAmount(s string) (Amount, error) {
+	v, err := amount.ParseInt64(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: %w", s, err)
+	}
+	return Amount(v), nil
+}
+
+// ExactAmount converts a float64 that is meant to hold a decimal amount, such
+// as one decoded from a JSON request, without rounding. It fails when the
+// value has more than seven decimal places, so nothing is silently dropped
+// between what is stored and what is sent to the network.
+func ExactAmount(f float64) (Amount, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("invalid amount: %v", f)
+	}
+	return ParseAmount(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// RoundAmount converts a computed float64, such as a converted or fee amount,
+// to the nearest stroop.
+func RoundAmount(f float64) (Amount, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("invalid amount: %v", f)
+	}
+	return ParseAmount(strconv.FormatFloat(f, 'f', 7, 64))
+}
+
+// StellarAmount formats a decimal amount for a Stellar operation, failing
+// rather than rounding when it has more than seven decimal places.
+func StellarAmount(f float64) (string, error) {
+	a, err := ExactAmount(f)
+	if err != nil {
+		return "", err
+	}
+	return a.String(), nil
+}
+
+// String formats the amount with Stellar's seven decimal places.
+func (a Amount) String() string {
+	return amount.StringFromInt64(int64(a))
+}
+
+// Float64 returns the amount as a float64, for display and for code that has
+// not moved to Amount yet.
+func (a Amount) Float64() float64 {
+	return float64(a) / amount.One
+}
+
+// Add returns a+b, or ErrAmountOverflow when the sum leaves the int64 range.
+func (a Amount) Add(b Amount) (Amount, error) {
+	sum := a + b
+	if (b > 0 && sum < a) || (b < 0 && sum > a) {
+		return 0, ErrAmountOverflow
+	}
+	return sum, nil
+}
+
+// Sub returns a-b, or ErrAmountOverflow when the difference leaves the int64 range.
+func (a Amount) Sub(b Amount) (Amount, error) {
+	diff := a - b
+	if (b > 0 && diff > a) || (b < 0 && diff < a) {
+		return 0, ErrAmountOverflow
+	}
+	return diff, nil
+}
+
+// MarshalJSON encodes the amount as a decimal string.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON accepts a decimal string or a bare JSON number.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	raw := string(bytes.Trim(data, `"`))
+	parsed, err := ParseAmount(raw)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmountArithmeticIsExact(t *testing.T) {
+	a, err := ParseAmount("0.1")
+	require.NoError(t, err)
+	b, err := ParseAmount("0.2")
+	require.NoError(t, err)
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	assert.Equal(t, "0.3000000", sum.String())
+	want, _ := ParseAmount("0.3")
+	assert.Equal(t, want, sum)
+	x, y := 0.1, 0.2
+	assert.NotEqual(t, 0.3, x+y, "float64 drifts where Amount does not")
+
+	diff, err := sum.Sub(b)
+	require.NoError(t, err)
+	assert.Equal(t, a, diff)
+}
+
+func TestAmountLargeValues(t *testing.T) {
+	big, err := ParseAmount("900000000000.1234567")
+	require.NoError(t, err)
+	small, err := ParseAmount("0.0000001")
+	require.NoError(t, err)
+
+	sum, err := big.Add(small)
+	require.NoError(t, err)
+	assert.Equal(t, "900000000000.1234568", sum.String())
+
+	max, err := ParseAmount("922337203685.4775807")
+	require.NoError(t, err)
+	assert.Equal(t, Amount(math.MaxInt64), max)
+	_, err = max.Add(small)
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+
+	min := Amount(math.MinInt64)
+	_, err = min.Sub(small)
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+
+	_, err = ParseAmount("922337203685.4775808")
+	assert.Error(t, err)
+}
+
+func TestExactAmount(t *testing.T) {
+	a, err := ExactAmount(100.5)
+	require.NoError(t, err)
+	assert.Equal(t, "100.5000000", a.String())
+	assert.Equal(t, 100.5, a.Float64())
+
+	_, err = ExactAmount(0.12345678)
+	assert.Error(t, err, "more than seven decimal places")
+	_, err = ExactAmount(math.NaN())
+	assert.Error(t, err)
+
+	s, err := StellarAmount(12.25)
+	require.NoError(t, err)
+	assert.Equal(t, "12.2500000", s)
+
+	x, y := 0.1, 0.2
+	rounded, err := RoundAmount(x + y)
+	require.NoError(t, err)
+	assert.Equal(t, "0.3000000", rounded.String())
+}
+
+func TestAmountJSON(t *testing.T) {
+	var body struct {
+		Amount Amount `json:"amount"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":"10.25"}`), &body))
+	assert.Equal(t, "10.2500000", body.Amount.String())
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":3}`), &body))
+	assert.Equal(t, "3.0000000", body.Amount.String())
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":"1.123456789"}`), &body))
+
+	out, err := json.Marshal(body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"3.0000000"}`, string(out))
+}
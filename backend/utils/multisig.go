@@ -0,0 +1,174 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// ErrDuplicateSignature is returned when an envelope already carries a
+// signature from the key being added.
+var ErrDuplicateSignature = errors.New("envelope is already signed by this key")
+
+// IsDuplicateSignature reports whether err means the signer already signed the envelope.
+func IsDuplicateSignature(err error) bool {
+	return errors.Is(err, ErrDuplicateSignature)
+}
+
+// parseTransaction decodes an envelope XDR that must hold a regular
+// (non fee-bump) transaction.
+func parseTransaction(envelopeXDR string) (*txnbuild.Transaction, error) {
+	genericTx, err := txnbuild.TransactionFromXDR(envelopeXDR)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse envelope XDR: %w", err)
+	}
+	tx, ok := genericTx.Transaction()
+	if !ok {
+		return nil, fmt.Errorf("XDR is not a transaction envelope")
+	}
+	return tx, nil
+}
+
+// CountSignatures returns how many signatures an envelope carries.
+func CountSignatures(envelopeXDR string) (int, error) {
+	tx, err := parseTransaction(envelopeXDR)
+	if err != nil {
+		return 0, err
+	}
+	return len(tx.Signatures()), nil
+}
+
+// hasSignatureFrom reports whether tx already carries a valid signature by kp.
+func hasSignatureFrom(tx *txnbuild.Transaction, networkPassphrase string, kp keypair.KP) (bool, error) {
+	hash, err := tx.Hash(networkPassphrase)
+	if err != nil {
+		return false, fmt.Errorf("failed to hash transaction: %w", err)
+	}
+	hint := xdr.SignatureHint(kp.Hint())
+	for _, sig := range tx.Signatures() {
+		if sig.Hint == hint && kp.Verify(hash[:], sig.Signature) == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// VerifySignatures reports whether every account in expectedSigners has
+// signed the envelope: each must have a signature, with a matching hint, that
+// verifies against the transaction hash on the network of networkPassphrase.
+// Signatures by other keys, such as extra multisig cosigners, are ignored.
+// For a fee-bump envelope the inner transaction's signatures are checked.
+func VerifySignatures(envelopeXDR string, networkPassphrase string, expectedSigners []string) (bool, error) {
+	if len(expectedSigners) == 0 {
+		return false, fmt.Errorf("no expected signers")
+	}
+	genericTx, err := txnbuild.TransactionFromXDR(envelopeXDR)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse envelope XDR: %w", err)
+	}
+	tx, ok := genericTx.Transaction()
+	if !ok {
+		feeBump, _ := genericTx.FeeBump()
+		tx = feeBump.InnerTransaction()
+	}
+
+	for _, signer := range expectedSigners {
+		kp, err := keypair.ParseAddress(signer)
+		if err != nil {
+			return false, fmt.Errorf("invalid signer %q: %w", signer, err)
+		}
+		signed, err := hasSignatureFrom(tx, networkPassphrase, kp)
+		if err != nil {
+			return false, err
+		}
+		if !signed {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// VerifySignatures checks the envelope's signatures using the client's
+// network passphrase; see VerifySignatures.
+func (s *StellarClient) VerifySignatures(envelopeXDR string, expectedSigners []string) (bool, error) {
+	return VerifySignatures(envelopeXDR, s.networkPassphrase, expectedSigners)
+}
+
+// AddSignature signs an envelope that may already be partially signed,
+// keeping its existing signatures. It returns ErrDuplicateSignature if the key
+// has signed the envelope before.
+func AddSignature(ctx context.Context, signedXDR string, secretKey string, networkPassphrase string) (string, error) {
+	fields := requestContextFields(ctx)
+	fields["network_passphrase"] = networkPassphrase
+
+	tx, err := parseTransaction(signedXDR)
+	if err != nil {
+		return "", err
+	}
+	kp, err := keypair.ParseFull(secretKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid secret key: %w", err)
+	}
+	fields["signer"] = kp.Address()
+
+	signed, err := hasSignatureFrom(tx, networkPassphrase, kp)
+	if err != nil {
+		return "", err
+	}
+	if signed {
+		return "", ErrDuplicateSignature
+	}
+
+	tx, err = tx.Sign(networkPassphrase, kp)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	combined, err := tx.Base64()
+	if err != nil {
+		return "", fmt.Errorf("failed to encode signed transaction: %w", err)
+	}
+
+	fields["signatures"] = len(tx.Signatures())
+	logrus.WithFields(fields).Info("Added signature to transaction")
+	return combined, nil
+}
+
+// AttachSignature appends a signature produced elsewhere by publicKey over the
+// transaction hash, so signers never hand over their secret keys. The
+// signature is base64 encoded and must verify against the transaction; prior
+// signatures are kept and a repeat signer yields ErrDuplicateSignature.
+func AttachSignature(envelopeXDR string, publicKey string, signature string, networkPassphrase string) (string, error) {
+	tx, err := parseTransaction(envelopeXDR)
+	if err != nil {
+		return "", err
+	}
+	kp, err := keypair.ParseAddress(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid public key: %w", err)
+	}
+
+	signed, err := hasSignatureFrom(tx, networkPassphrase, kp)
+	if err != nil {
+		return "", err
+	}
+	if signed {
+		return "", ErrDuplicateSignature
+	}
+
+	tx, err = tx.AddSignatureBase64(networkPassphrase, publicKey, signature)
+	if err != nil {
+		return "", fmt.Errorf("invalid signature: %w", err)
+	}
+	return tx.Base64()
+}
+
+// AddSignature adds a signature to a partially signed envelope using the
+// client's network passphrase.
+func (s *StellarClient) AddSignature(ctx context.Context, signedXDR string, secretKey string) (string, error) {
+	return AddSignature(ctx, signedXDR, secretKey, s.networkPassphrase)
+}
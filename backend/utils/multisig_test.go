@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMultisigEnvelope(t *testing.T) (*txnbuild.Transaction, string) {
+	t.Helper()
+	source, _ := keypair.Random()
+	dest, _ := keypair.Random()
+	tx, err := NewChangeTrustTx(&txnbuild.SimpleAccount{AccountID: source.Address(), Sequence: 1}, "USDC", dest.Address(), "")
+	require.NoError(t, err)
+	envelope, err := tx.Base64()
+	require.NoError(t, err)
+	return tx, envelope
+}
+
+func TestAddSignature(t *testing.T) {
+	passphrase := network.TestNetworkPassphrase
+	tx, envelope := newMultisigEnvelope(t)
+	first, _ := keypair.Random()
+	second, _ := keypair.Random()
+
+	count, err := CountSignatures(envelope)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	once, err := AddSignature(context.Background(), envelope, first.Seed(), passphrase)
+	require.NoError(t, err)
+	twice, err := AddSignature(context.Background(), once, second.Seed(), passphrase)
+	require.NoError(t, err)
+
+	t.Run("Two keypairs produce a two-signature envelope", func(t *testing.T) {
+		count, err := CountSignatures(twice)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		parsed, err := parseTransaction(twice)
+		require.NoError(t, err)
+		hash, err := tx.Hash(passphrase)
+		require.NoError(t, err)
+		sigs := parsed.Signatures()
+		// The first signature is preserved ahead of the second.
+		assert.NoError(t, first.Verify(hash[:], sigs[0].Signature))
+		assert.NoError(t, second.Verify(hash[:], sigs[1].Signature))
+	})
+
+	t.Run("Signing twice with one key is rejected", func(t *testing.T) {
+		_, err := AddSignature(context.Background(), twice, first.Seed(), passphrase)
+		assert.True(t, IsDuplicateSignature(err))
+	})
+
+	t.Run("Invalid inputs", func(t *testing.T) {
+		_, err := AddSignature(context.Background(), envelope, "invalid_key", passphrase)
+		assert.Error(t, err)
+		_, err = AddSignature(context.Background(), "invalid_xdr", first.Seed(), passphrase)
+		assert.Error(t, err)
+		_, err = CountSignatures("invalid_xdr")
+		assert.Error(t, err)
+	})
+}
+
+func TestAttachSignature(t *testing.T) {
+	passphrase := network.TestNetworkPassphrase
+	tx, envelope := newMultisigEnvelope(t)
+	hash, err := tx.Hash(passphrase)
+	require.NoError(t, err)
+
+	signer, _ := keypair.Random()
+	signature, err := signer.SignBase64(hash[:])
+	require.NoError(t, err)
+
+	cosigner, _ := keypair.Random()
+	partial, err := AddSignature(context.Background(), envelope, cosigner.Seed(), passphrase)
+	require.NoError(t, err)
+
+	combined, err := AttachSignature(partial, signer.Address(), signature, passphrase)
+	require.NoError(t, err)
+	count, err := CountSignatures(combined)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	_, err = AttachSignature(combined, signer.Address(), signature, passphrase)
+	assert.True(t, IsDuplicateSignature(err))
+
+	other, _ := keypair.Random()
+	_, err = AttachSignature(partial, other.Address(), signature, passphrase)
+	assert.Error(t, err, "a signature must verify against the claimed key")
+	assert.False(t, IsDuplicateSignature(err))
+}
+
+func TestVerifySignatures(t *testing.T) {
+	passphrase := network.TestNetworkPassphrase
+	_, envelope := newMultisigEnvelope(t)
+	first, _ := keypair.Random()
+	second, _ := keypair.Random()
+
+	once, err := AddSignature(context.Background(), envelope, first.Seed(), passphrase)
+	require.NoError(t, err)
+	twice, err := AddSignature(context.Background(), once, second.Seed(), passphrase)
+	require.NoError(t, err)
+
+	verify := func(envelope, passphrase string, signers ...*keypair.Full) bool {
+		addresses := make([]string, len(signers))
+		for i, kp := range signers {
+			addresses[i] = kp.Address()
+		}
+		ok, err := VerifySignatures(envelope, passphrase, addresses)
+		require.NoError(t, err)
+		return ok
+	}
+
+	t.Run("Correctly signed envelope verifies", func(t *testing.T) {
+		assert.True(t, verify(once, passphrase, first))
+		// Other signatures on the envelope do not get in the way.
+		assert.True(t, verify(twice, passphrase, second))
+	})
+
+	t.Run("Multisig needs every signer", func(t *testing.T) {
+		assert.True(t, verify(twice, passphrase, first, second))
+		assert.False(t, verify(once, passphrase, first, second))
+	})
+
+	t.Run("Unsigned envelope fails", func(t *testing.T) {
+		assert.False(t, verify(envelope, passphrase, first))
+	})
+
+	t.Run("Tampered envelope fails", func(t *testing.T) {
+		var env xdr.TransactionEnvelope
+		require.NoError(t, xdr.SafeUnmarshalBase64(once, &env))
+		env.V1.Tx.Fee++
+		tampered, err := xdr.MarshalBase64(env)
+		require.NoError(t, err)
+
+		count, err := CountSignatures(tampered)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.False(t, verify(tampered, passphrase, first))
+	})
+
+	t.Run("Signature for another network fails", func(t *testing.T) {
+		assert.False(t, verify(once, network.PublicNetworkPassphrase, first))
+	})
+
+	t.Run("Invalid inputs", func(t *testing.T) {
+		_, err := VerifySignatures("invalid_xdr", passphrase, []string{first.Address()})
+		assert.Error(t, err)
+		_, err = VerifySignatures(once, passphrase, []string{"GBADSIGNER"})
+		assert.Error(t, err)
+		_, err = VerifySignatures(once, passphrase, nil)
+		assert.Error(t, err)
+	})
+}
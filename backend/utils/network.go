@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stellar/go/protocols/horizon"
+)
+
+// ErrNetworkMismatch means Horizon serves a different network from the
+// configured passphrase. Transactions signed for one network are rejected by
+// every other, so submitting anything would fail.
+var ErrNetworkMismatch = errors.New("horizon network does not match network passphrase")
+
+// VerifyNetwork checks that the Horizon server at horizonURL serves the
+// network identified by networkPassphrase, reading its root endpoint. opts
+// configure the client as for NewStellarClient, e.g. WithHorizonHTTP.
+func VerifyNetwork(ctx context.Context, horizonURL, networkPassphrase string, opts ...ClientOption) error {
+	s := NewStellarClient(horizonURL, networkPassphrase, opts...).(*StellarClient)
+
+	var root horizon.Root
+	err := s.retryRead(ctx, "VerifyNetwork", func() error {
+		var err error
+		root, err = s.client.Root()
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read network passphrase from horizon at %s: %w", horizonURL, err)
+	}
+	if root.NetworkPassphrase != networkPassphrase {
+		return fmt.Errorf("%w: horizon at %s serves %q, configured passphrase is %q",
+			ErrNetworkMismatch, horizonURL, root.NetworkPassphrase, networkPassphrase)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/network"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRootServer serves a Horizon root endpoint reporting passphrase.
+func newRootServer(t *testing.T, passphrase string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"horizon_version":"2.0.0","network_passphrase":%q}`, passphrase)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestVerifyNetwork(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Matching passphrase", func(t *testing.T) {
+		server := newRootServer(t, network.TestNetworkPassphrase)
+		assert.NoError(t, VerifyNetwork(ctx, server.URL, network.TestNetworkPassphrase))
+	})
+
+	t.Run("Mismatched passphrase", func(t *testing.T) {
+		server := newRootServer(t, network.PublicNetworkPassphrase)
+		err := VerifyNetwork(ctx, server.URL, network.TestNetworkPassphrase)
+		assert.ErrorIs(t, err, ErrNetworkMismatch)
+		assert.ErrorContains(t, err, network.PublicNetworkPassphrase)
+	})
+
+	t.Run("Unreachable horizon", func(t *testing.T) {
+		server := newRootServer(t, network.TestNetworkPassphrase)
+		server.Close()
+		err := VerifyNetwork(ctx, server.URL, network.TestNetworkPassphrase, WithHorizonHTTP(time.Second, 0, 0))
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNetworkMismatch)
+	})
+}
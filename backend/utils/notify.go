@@ -0,0 +1,247 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"text/template"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Remittance lifecycle messages sent through a Notifier.
+const (
+	// NotifyRemittanceCreated tells the sender the transaction is ready to sign.
+	NotifyRemittanceCreated = "remittance.created"
+	// NotifyRemittanceCompleted tells the sender the remittance was delivered.
+	NotifyRemittanceCompleted = "remittance.completed"
+	// NotifyRemittanceReceived tells the recipient money has arrived.
+	NotifyRemittanceReceived = "remittance.received"
+	// NotifyRemittanceFailed tells the sender the remittance failed.
+	NotifyRemittanceFailed = "remittance.failed"
+)
+
+// DefaultLocale is used when no template exists for a user's locale.
+const DefaultLocale = "en"
+
+// NotificationRecipient is who a message goes to. A channel whose address is
+// empty is skipped.
+type NotificationRecipient struct {
+	Name  string
+	Email string
+	Phone string
+}
+
+// Message is a rendered notification ready for delivery.
+type Message struct {
+	Event   string
+	Locale  string
+	To      NotificationRecipient
+	Subject string
+	Body    string
+}
+
+// Notifier delivers a rendered message. Implementations must be safe for
+// concurrent use.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// EmailSender sends a single email. *services.EmailService satisfies it.
+type EmailSender interface {
+	SendEmail(to, subject, body string) error
+}
+
+// EmailNotifier delivers messages by email.
+type EmailNotifier struct {
+	sender EmailSender
+}
+
+func NewEmailNotifier(sender EmailSender) *EmailNotifier {
+	return &EmailNotifier{sender: sender}
+}
+
+func (n *EmailNotifier) Notify(ctx context.Context, msg Message) error {
+	if msg.To.Email == "" {
+		return nil
+	}
+	body := "<p>" + strings.ReplaceAll(html.EscapeString(msg.Body), "\n", "<br>") + "</p>"
+	return n.sender.SendEmail(msg.To.Email, msg.Subject, body)
+}
+
+// SMSNotifier is a placeholder until an SMS provider is integrated; it logs
+// the message instead of sending it.
+type SMSNotifier struct{}
+
+func NewSMSNotifier() *SMSNotifier {
+	return &SMSNotifier{}
+}
+
+func (n *SMSNotifier) Notify(ctx context.Context, msg Message) error {
+	if msg.To.Phone == "" {
+		return nil
+	}
+	logrus.WithFields(requestContextFields(ctx)).WithFields(logrus.Fields{
+		"event":  msg.Event,
+		"locale": msg.Locale,
+	}).Info("SMS provider not configured; message not sent")
+	return nil
+}
+
+// MultiNotifier delivers each message over every wrapped notifier. A failing
+// notifier does not stop the others; their errors are joined.
+type MultiNotifier []Notifier
+
+func NewMultiNotifier(notifiers ...Notifier) MultiNotifier {
+	return MultiNotifier(notifiers)
+}
+
+func (m MultiNotifier) Notify(ctx context.Context, msg Message) error {
+	var errs []error
+	for _, n := range m {
+		if err := n.Notify(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NotificationData fills in a lifecycle message template.
+type NotificationData struct {
+	Name         string
+	RemittanceID uint
+	Amount       string
+	Currency     string
+	Reason       string
+}
+
+type messageTemplate struct {
+	subject string
+	body    string
+}
+
+// notificationTemplates holds the lifecycle messages, keyed by locale and
+// then event. Every locale must define every event.
+var notificationTemplates = map[string]map[string]messageTemplate{
+	"en": {
+		NotifyRemittanceCreated: {
+			subject: "Sign your remittance #{{.RemittanceID}}",
+			body: "Hi {{.Name}},\nYour remittance #{{.RemittanceID}} of {{.Amount}} {{.Currency}} is ready. " +
+				"Sign the transaction in your Stellar wallet and submit it to POST /remittances/{{.RemittanceID}}/submit to send it.",
+		},
+		NotifyRemittanceCompleted: {
+			subject: "Remittance #{{.RemittanceID}} delivered",
+			body:    "Hi {{.Name}},\nYour remittance #{{.RemittanceID}} of {{.Amount}} {{.Currency}} has been delivered.",
+		},
+		NotifyRemittanceReceived: {
+			subject: "You received {{.Amount}} {{.Currency}}",
+			body:    "Hi {{.Name}},\nRemittance #{{.RemittanceID}} of {{.Amount}} {{.Currency}} has arrived in your account.",
+		},
+		NotifyRemittanceFailed: {
+			subject: "Remittance #{{.RemittanceID}} failed",
+			body:    "Hi {{.Name}},\nYour remittance #{{.RemittanceID}} of {{.Amount}} {{.Currency}} failed{{if .Reason}} ({{.Reason}}){{end}}. No funds were moved.",
+		},
+	},
+	"fr": {
+		NotifyRemittanceCreated: {
+			subject: "Signez votre transfert n°{{.RemittanceID}}",
+			body: "Bonjour {{.Name}},\nVotre transfert n°{{.RemittanceID}} de {{.Amount}} {{.Currency}} est prêt. " +
+				"Signez la transaction dans votre portefeuille Stellar et soumettez-la à POST /remittances/{{.RemittanceID}}/submit pour l'envoyer.",
+		},
+		NotifyRemittanceCompleted: {
+			subject: "Transfert n°{{.RemittanceID}} livré",
+			body:    "Bonjour {{.Name}},\nVotre transfert n°{{.RemittanceID}} de {{.Amount}} {{.Currency}} a été livré.",
+		},
+		NotifyRemittanceReceived: {
+			subject: "Vous avez reçu {{.Amount}} {{.Currency}}",
+			body:    "Bonjour {{.Name}},\nLe transfert n°{{.RemittanceID}} de {{.Amount}} {{.Currency}} est arrivé sur votre compte.",
+		},
+		NotifyRemittanceFailed: {
+			subject: "Échec du transfert n°{{.RemittanceID}}",
+			body:    "Bonjour {{.Name}},\nVotre transfert n°{{.RemittanceID}} de {{.Amount}} {{.Currency}} a échoué{{if .Reason}} ({{.Reason}}){{end}}. Aucun fonds n'a été débité.",
+		},
+	},
+	"es": {
+		NotifyRemittanceCreated: {
+			subject: "Firme su remesa n.º {{.RemittanceID}}",
+			body: "Hola {{.Name}},\nSu remesa n.º {{.RemittanceID}} de {{.Amount}} {{.Currency}} está lista. " +
+				"Firme la transacción en su billetera Stellar y envíela a POST /remittances/{{.RemittanceID}}/submit.",
+		},
+		NotifyRemittanceCompleted: {
+			subject: "Remesa n.º {{.RemittanceID}} entregada",
+			body:    "Hola {{.Name}},\nSu remesa n.º {{.RemittanceID}} de {{.Amount}} {{.Currency}} fue entregada.",
+		},
+		NotifyRemittanceReceived: {
+			subject: "Recibió {{.Amount}} {{.Currency}}",
+			body:    "Hola {{.Name}},\nLa remesa n.º {{.RemittanceID}} de {{.Amount}} {{.Currency}} llegó a su cuenta.",
+		},
+		NotifyRemittanceFailed: {
+			subject: "La remesa n.º {{.RemittanceID}} falló",
+			body:    "Hola {{.Name}},\nSu remesa n.º {{.RemittanceID}} de {{.Amount}} {{.Currency}} falló{{if .Reason}} ({{.Reason}}){{end}}. No se movieron fondos.",
+		},
+	},
+}
+
+// countryLocales and currencyLocales map a user's country, or failing that
+// their default currency, to a message locale.
+var (
+	countryLocales = map[string]string{
+		"FR": "fr", "BE": "fr", "SN": "fr", "CI": "fr", "CM": "fr", "ML": "fr", "BF": "fr", "BJ": "fr", "TG": "fr", "NE": "fr", "CD": "fr", "HT": "fr",
+		"ES": "es", "MX": "es", "CO": "es", "AR": "es", "PE": "es", "CL": "es", "GT": "es", "HN": "es", "SV": "es", "DO": "es", "EC": "es", "BO": "es",
+	}
+	currencyLocales = map[string]string{
+		"XOF": "fr", "XAF": "fr", "HTG": "fr",
+		"MXN": "es", "COP": "es", "ARS": "es", "PEN": "es", "CLP": "es", "GTQ": "es", "DOP": "es",
+	}
+)
+
+// NotificationLocale picks the message locale for a user from their country,
+// falling back to their default currency and then DefaultLocale.
+func NotificationLocale(country, currency string) string {
+	if locale, ok := countryLocales[strings.ToUpper(country)]; ok {
+		return locale
+	}
+	if locale, ok := currencyLocales[strings.ToUpper(currency)]; ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// RenderNotification builds the message for event in locale, falling back to
+// DefaultLocale when the locale has no templates.
+func RenderNotification(event, locale string, to NotificationRecipient, data NotificationData) (Message, error) {
+	templates, ok := notificationTemplates[locale]
+	if !ok {
+		locale = DefaultLocale
+		templates = notificationTemplates[locale]
+	}
+	tmpl, ok := templates[event]
+	if !ok {
+		return Message{}, fmt.Errorf("no notification template for %q", event)
+	}
+
+	subject, err := renderTemplate(tmpl.subject, data)
+	if err != nil {
+		return Message{}, err
+	}
+	body, err := renderTemplate(tmpl.body, data)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{Event: event, Locale: locale, To: to, Subject: subject, Body: body}, nil
+}
+
+func renderTemplate(text string, data NotificationData) (string, error) {
+	tmpl, err := template.New("notification").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
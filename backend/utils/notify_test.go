@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingSender struct {
+	to, subject, body string
+	err               error
+}
+
+func (s *recordingSender) SendEmail(to, subject, body string) error {
+	s.to, s.subject, s.body = to, subject, body
+	return s.err
+}
+
+type failingNotifier struct{ err error }
+
+func (n failingNotifier) Notify(ctx context.Context, msg Message) error { return n.err }
+
+func TestNotificationLocale(t *testing.T) {
+	assert.Equal(t, "fr", NotificationLocale("sn", "XOF"))
+	assert.Equal(t, "es", NotificationLocale("MX", "USD"))
+	assert.Equal(t, "fr", NotificationLocale("", "XAF"), "falls back to the default currency")
+	assert.Equal(t, DefaultLocale, NotificationLocale("NG", "NGN"))
+}
+
+func TestRenderNotification(t *testing.T) {
+	to := NotificationRecipient{Name: "Ada", Email: "ada@example.com"}
+	data := NotificationData{Name: "Ada", RemittanceID: 42, Amount: "100.5", Currency: "USDC", Reason: "op_underfunded"}
+
+	t.Run("Every locale defines every event", func(t *testing.T) {
+		for locale, templates := range notificationTemplates {
+			for _, event := range []string{NotifyRemittanceCreated, NotifyRemittanceCompleted, NotifyRemittanceReceived, NotifyRemittanceFailed} {
+				_, ok := templates[event]
+				assert.True(t, ok, "%s is missing %s", locale, event)
+			}
+		}
+	})
+
+	t.Run("Creation includes signing instructions", func(t *testing.T) {
+		msg, err := RenderNotification(NotifyRemittanceCreated, "en", to, data)
+		require.NoError(t, err)
+		assert.Equal(t, "Sign your remittance #42", msg.Subject)
+		assert.Contains(t, msg.Body, "/remittances/42/submit")
+		assert.Equal(t, to, msg.To)
+	})
+
+	t.Run("Localized", func(t *testing.T) {
+		msg, err := RenderNotification(NotifyRemittanceFailed, "fr", to, data)
+		require.NoError(t, err)
+		assert.Equal(t, "fr", msg.Locale)
+		assert.Contains(t, msg.Body, "a échoué (op_underfunded)")
+	})
+
+	t.Run("Unknown locale falls back", func(t *testing.T) {
+		msg, err := RenderNotification(NotifyRemittanceReceived, "de", to, data)
+		require.NoError(t, err)
+		assert.Equal(t, DefaultLocale, msg.Locale)
+		assert.Equal(t, "You received 100.5 USDC", msg.Subject)
+	})
+
+	t.Run("Unknown event", func(t *testing.T) {
+		_, err := RenderNotification("remittance.unknown", "en", to, data)
+		assert.Error(t, err)
+	})
+}
+
+func TestNotifiers(t *testing.T) {
+	msg := Message{Event: NotifyRemittanceCompleted, To: NotificationRecipient{Email: "ada@example.com"}, Subject: "Done", Body: "a < b\nok"}
+
+	t.Run("Email escapes the body", func(t *testing.T) {
+		sender := &recordingSender{}
+		require.NoError(t, NewEmailNotifier(sender).Notify(context.Background(), msg))
+		assert.Equal(t, "ada@example.com", sender.to)
+		assert.Equal(t, "<p>a &lt; b<br>ok</p>", sender.body)
+	})
+
+	t.Run("Email skipped without an address", func(t *testing.T) {
+		sender := &recordingSender{}
+		require.NoError(t, NewEmailNotifier(sender).Notify(context.Background(), Message{Subject: "Done"}))
+		assert.Empty(t, sender.subject)
+	})
+
+	t.Run("Multi keeps going after a failure", func(t *testing.T) {
+		sender := &recordingSender{}
+		boom := errors.New("boom")
+		err := NewMultiNotifier(failingNotifier{err: boom}, NewSMSNotifier(), NewEmailNotifier(sender)).Notify(context.Background(), msg)
+		assert.ErrorIs(t, err, boom)
+		assert.Equal(t, "Done", sender.subject)
+	})
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/stellar/go/txnbuild"
+)
+
+// OwnershipChallengeData names the manage_data operation of an ownership
+// challenge.
+const OwnershipChallengeData = "gpay-remit ownership"
+
+// ErrInvalidChallenge is returned for an ownership challenge that was not
+// issued for the account, has expired, or is not signed by the account.
+var ErrInvalidChallenge = errors.New("invalid ownership challenge")
+
+// BuildOwnershipChallenge returns an unsigned transaction for account to sign
+// as proof that it holds the account's key. Like a SEP-10 challenge it has
+// sequence number 0, so it can never be submitted, and carries a single
+// manage_data operation; its value is an HMAC under secret of the account and
+// the expiry, so VerifyOwnershipChallenge needs no stored state.
+func BuildOwnershipChallenge(secret, account string, now, expiresAt time.Time) (string, error) {
+	value := ownershipChallengeValue(secret, account, expiresAt.Unix())
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: account, Sequence: -1},
+		IncrementSequenceNum: true,
+		Operations: []txnbuild.Operation{&txnbuild.ManageData{
+			SourceAccount: account,
+			Name:          OwnershipChallengeData,
+			Value:         []byte(value),
+		}},
+		BaseFee:       txnbuild.MinBaseFee,
+		Preconditions: txnbuild.Preconditions{TimeBounds: txnbuild.NewTimebounds(now.Unix(), expiresAt.Unix())},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to build ownership challenge: %w", err)
+	}
+	return tx.Base64()
+}
+
+// VerifyOwnershipChallenge checks that signedXDR is a challenge issued by
+// BuildOwnershipChallenge under secret for account, that it has not expired
+// at now, and that account has signed it.
+func VerifyOwnershipChallenge(secret, account, networkPassphrase, signedXDR string, now time.Time) error {
+	tx, err := parseTransaction(signedXDR)
+	if err != nil {
+		return ErrInvalidChallenge
+	}
+	if tx.SourceAccount().AccountID != account || tx.SequenceNumber() != 0 {
+		return ErrInvalidChallenge
+	}
+	ops := tx.Operations()
+	if len(ops) != 1 {
+		return ErrInvalidChallenge
+	}
+	op, ok := ops[0].(*txnbuild.ManageData)
+	if !ok || op.Name != OwnershipChallengeData || op.SourceAccount != account {
+		return ErrInvalidChallenge
+	}
+	bounds := tx.Timebounds()
+	if bounds.MaxTime == 0 || now.Unix() > bounds.MaxTime {
+		return ErrInvalidChallenge
+	}
+	if !hmac.Equal(op.Value, []byte(ownershipChallengeValue(secret, account, bounds.MaxTime))) {
+		return ErrInvalidChallenge
+	}
+
+	signed, err := VerifySignatures(signedXDR, networkPassphrase, []string{account})
+	if err != nil || !signed {
+		return ErrInvalidChallenge
+	}
+	return nil
+}
+
+// ownershipChallengeValue is the manage_data value of a challenge for
+// account expiring at expiresAt, base64 encoded to stay within the 64-byte
+// limit.
+func ownershipChallengeValue(secret, account string, expiresAt int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(OwnershipChallengeData + ":" + account + ":" + strconv.FormatInt(expiresAt, 10)))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwnershipChallenge(t *testing.T) {
+	owner, _ := keypair.Random()
+	other, _ := keypair.Random()
+	now := time.Now()
+	passphrase := network.TestNetworkPassphrase
+
+	challenge, err := BuildOwnershipChallenge("secret", owner.Address(), now, now.Add(5*time.Minute))
+	require.NoError(t, err)
+	sign := func(envelope string, kp *keypair.Full) string {
+		signed, err := SignTx(context.Background(), envelope, kp.Seed(), passphrase)
+		require.NoError(t, err)
+		return signed
+	}
+	signed := sign(challenge, owner)
+
+	assert.NoError(t, VerifyOwnershipChallenge("secret", owner.Address(), passphrase, signed, now))
+	assert.ErrorIs(t, VerifyOwnershipChallenge("secret", owner.Address(), passphrase, challenge, now), ErrInvalidChallenge, "unsigned")
+	assert.ErrorIs(t, VerifyOwnershipChallenge("secret", owner.Address(), passphrase, sign(challenge, other), now), ErrInvalidChallenge, "signed by another key")
+	assert.ErrorIs(t, VerifyOwnershipChallenge("other-secret", owner.Address(), passphrase, signed, now), ErrInvalidChallenge, "not issued under this secret")
+	assert.ErrorIs(t, VerifyOwnershipChallenge("secret", other.Address(), passphrase, signed, now), ErrInvalidChallenge, "issued for another account")
+	assert.ErrorIs(t, VerifyOwnershipChallenge("secret", owner.Address(), network.PublicNetworkPassphrase, signed, now), ErrInvalidChallenge, "signed for another network")
+	assert.ErrorIs(t, VerifyOwnershipChallenge("secret", owner.Address(), passphrase, signed, now.Add(6*time.Minute)), ErrInvalidChallenge, "expired")
+	assert.ErrorIs(t, VerifyOwnershipChallenge("secret", owner.Address(), passphrase, "not xdr", now), ErrInvalidChallenge)
+}
@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/protocols/horizon/base"
+	"github.com/stellar/go/protocols/horizon/operations"
+)
+
+// Page sizes for Horizon collection endpoints. Horizon refuses limits above
+// MaxHorizonPageLimit.
+const (
+	DefaultHorizonPageLimit = 10
+	MaxHorizonPageLimit     = 200
+)
+
+// ClampPageLimit bounds a requested page size to what Horizon serves: a
+// non-positive limit gets the default and anything larger than
+// MaxHorizonPageLimit is cut down to it.
+func ClampPageLimit(limit int) uint {
+	if limit <= 0 {
+		return DefaultHorizonPageLimit
+	}
+	if limit > MaxHorizonPageLimit {
+		return MaxHorizonPageLimit
+	}
+	return uint(limit)
+}
+
+// PaymentRecord is a payment-like operation (payment, path payment, account
+// creation, or merge) on an account, as Horizon's payments endpoint reports it.
+type PaymentRecord struct {
+	ID              string    `json:"id"`
+	Type            string    `json:"type"`
+	CreatedAt       time.Time `json:"created_at"`
+	TransactionHash string    `json:"transaction_hash"`
+	Successful      bool      `json:"successful"`
+	// Direction is sent or received relative to the account whose history
+	// was requested.
+	Direction   string `json:"direction"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	AssetCode   string `json:"asset_code"`
+	AssetIssuer string `json:"asset_issuer,omitempty"`
+	// Amount is empty for account merges, whose amount Horizon reports only
+	// as an effect.
+	Amount      string `json:"amount,omitempty"`
+	PagingToken string `json:"paging_token"`
+}
+
+// PaymentHistory is one page of an account's payments, newest first.
+// NextCursor fetches the following (older) page; it is empty when this page
+// was the last.
+type PaymentHistory struct {
+	AccountID  string          `json:"account_id"`
+	Payments   []PaymentRecord `json:"payments"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// GetPaymentHistory returns a page of the payments made to and from an
+// account, newest first. cursor is a paging token from a previous page's
+// NextCursor (empty for the first page); limit is bounded by ClampPageLimit.
+func (s *StellarClient) GetPaymentHistory(ctx context.Context, accountID string, limit int, cursor string) (*PaymentHistory, error) {
+	pageLimit := ClampPageLimit(limit)
+	logWithContext(ctx, "get_payment_history").WithFields(logrus.Fields{
+		"account_id": accountID,
+		"limit":      pageLimit,
+		"cursor":     cursor,
+	}).Info("Fetching payment history")
+
+	page, err := s.client.Payments(horizonclient.OperationRequest{
+		ForAccount: accountID,
+		Order:      horizonclient.OrderDesc,
+		Cursor:     cursor,
+		Limit:      pageLimit,
+	})
+	if err != nil {
+		if horizonclient.IsNotFoundError(err) {
+			return nil, ErrAccountNotFound
+		}
+		logWithContext(ctx, "get_payment_history").WithError(err).Error("Failed to fetch payments")
+		return nil, fmt.Errorf("failed to fetch payments: %w", err)
+	}
+
+	history := &PaymentHistory{AccountID: accountID, Payments: make([]PaymentRecord, 0, len(page.Embedded.Records))}
+	for _, op := range page.Embedded.Records {
+		history.Payments = append(history.Payments, paymentRecord(op, accountID))
+	}
+	if n := len(page.Embedded.Records); n > 0 && uint(n) == pageLimit {
+		history.NextCursor = page.Embedded.Records[n-1].PagingToken()
+	}
+	return history, nil
+}
+
+// paymentRecord flattens a Horizon payments-endpoint record.
+func paymentRecord(op operations.Operation, accountID string) PaymentRecord {
+	b := op.GetBase()
+	record := PaymentRecord{
+		ID:              b.ID,
+		Type:            b.Type,
+		CreatedAt:       b.LedgerCloseTime,
+		TransactionHash: b.TransactionHash,
+		Successful:      b.TransactionSuccessful,
+		PagingToken:     b.PT,
+	}
+
+	var asset base.Asset
+	switch o := op.(type) {
+	case operations.Payment:
+		record.From, record.To, record.Amount, asset = o.From, o.To, o.Amount, o.Asset
+	case operations.PathPayment:
+		record.From, record.To, record.Amount, asset = o.From, o.To, o.Amount, o.Asset
+	case operations.PathPaymentStrictSend:
+		record.From, record.To, record.Amount, asset = o.From, o.To, o.Amount, o.Asset
+	case operations.CreateAccount:
+		record.From, record.To, record.Amount = o.Funder, o.Account, o.StartingBalance
+		asset.Type = "native"
+	case operations.AccountMerge:
+		record.From, record.To = o.Account, o.Into
+		asset.Type = "native"
+	default:
+		record.From = b.SourceAccount
+	}
+
+	record.AssetCode, record.AssetIssuer = asset.Code, asset.Issuer
+	if asset.Type == "native" {
+		record.AssetCode = "XLM"
+	}
+	record.Direction = "received"
+	if record.From == accountID {
+		record.Direction = "sent"
+	}
+	return record
+}
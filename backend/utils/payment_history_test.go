@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stellar/go/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClampPageLimit(t *testing.T) {
+	assert.Equal(t, uint(DefaultHorizonPageLimit), ClampPageLimit(0))
+	assert.Equal(t, uint(DefaultHorizonPageLimit), ClampPageLimit(-5))
+	assert.Equal(t, uint(50), ClampPageLimit(50))
+	assert.Equal(t, uint(MaxHorizonPageLimit), ClampPageLimit(200))
+	assert.Equal(t, uint(MaxHorizonPageLimit), ClampPageLimit(1000))
+}
+
+func TestGetPaymentHistory(t *testing.T) {
+	const (
+		funder  = "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"
+		issuer  = "GA5ZSEJYB37JRC5AVCIA5MOP4RHTM335X2KGX3IHOJAPP5RE34K4KZVN"
+		missing = "GDUKMGUGDZQK6YHYA5Z6AY2G4XDSZPSZ3SW5UN3ARVMO6QSRDWP5YLEX"
+	)
+	account := cachedFundedAccount
+
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/accounts/"+missing+"/payments" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"type":"https://stellar.org/horizon-errors/not_found","title":"Resource Missing","status":404}`)
+			return
+		}
+		require.Equal(t, "/accounts/"+account+"/payments", r.URL.Path)
+		queries = append(queries, r.URL.Query())
+		fmt.Fprintf(w, `{"_embedded":{"records":[
+			{"id":"200","paging_token":"200","transaction_successful":true,"source_account":%[1]q,"type":"payment","type_i":1,
+			 "created_at":"2024-01-31T09:15:00Z","transaction_hash":"hash2",
+			 "asset_type":"credit_alphanum4","asset_code":"USDC","asset_issuer":%[3]q,"from":%[1]q,"to":%[2]q,"amount":"25.0000000"},
+			{"id":"100","paging_token":"100","transaction_successful":true,"source_account":%[2]q,"type":"create_account","type_i":0,
+			 "created_at":"2024-01-30T09:15:00Z","transaction_hash":"hash1",
+			 "starting_balance":"100.0000000","funder":%[2]q,"account":%[1]q}
+		]}}`, account, funder, issuer)
+	}))
+	defer server.Close()
+	client := NewStellarClient(server.URL, network.TestNetworkPassphrase)
+
+	t.Run("Maps records and returns the next cursor", func(t *testing.T) {
+		history, err := client.GetPaymentHistory(context.Background(), account, 2, "")
+		require.NoError(t, err)
+		assert.Equal(t, account, history.AccountID)
+		require.Len(t, history.Payments, 2)
+
+		payment := history.Payments[0]
+		assert.Equal(t, "payment", payment.Type)
+		assert.Equal(t, "sent", payment.Direction)
+		assert.Equal(t, funder, payment.To)
+		assert.Equal(t, "USDC", payment.AssetCode)
+		assert.Equal(t, issuer, payment.AssetIssuer)
+		assert.Equal(t, "25.0000000", payment.Amount)
+		assert.Equal(t, "hash2", payment.TransactionHash)
+
+		created := history.Payments[1]
+		assert.Equal(t, "create_account", created.Type)
+		assert.Equal(t, "received", created.Direction)
+		assert.Equal(t, funder, created.From)
+		assert.Equal(t, "XLM", created.AssetCode)
+		assert.Equal(t, "100.0000000", created.Amount)
+
+		assert.Equal(t, "100", history.NextCursor)
+		q := queries[len(queries)-1]
+		assert.Equal(t, "2", q.Get("limit"))
+		assert.Equal(t, "desc", q.Get("order"))
+		assert.Empty(t, q.Get("cursor"))
+	})
+
+	t.Run("Cursor is passed through", func(t *testing.T) {
+		_, err := client.GetPaymentHistory(context.Background(), account, 2, "100")
+		require.NoError(t, err)
+		assert.Equal(t, "100", queries[len(queries)-1].Get("cursor"))
+	})
+
+	t.Run("Limit is clamped to Horizon's maximum", func(t *testing.T) {
+		history, err := client.GetPaymentHistory(context.Background(), account, 1000, "")
+		require.NoError(t, err)
+		assert.Equal(t, "200", queries[len(queries)-1].Get("limit"))
+		assert.Empty(t, history.NextCursor, "a short page is the last")
+	})
+
+	t.Run("Unknown account", func(t *testing.T) {
+		_, err := client.GetPaymentHistory(context.Background(), missing, 10, "")
+		assert.True(t, IsAccountNotFound(err))
+	})
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/protocols/horizon"
+)
+
+// DefaultBaseReserve is the base reserve (in XLM) in effect on the public
+// networks at the time of writing. It is only a fallback; the live value is
+// read from the latest ledger because validators can vote to change it.
+const DefaultBaseReserve = 0.5
+
+// baseReserveTTL is how long a base reserve read from Horizon is reused.
+const baseReserveTTL = 10 * time.Minute
+
+// MinimumBalance is the XLM an account must keep: two base reserves for the
+// account itself plus one per subentry and sponsored entry it pays for.
+func MinimumBalance(baseReserve float64, subentries, sponsoring, sponsored int) float64 {
+	return float64(2+subentries+sponsoring-sponsored) * baseReserve
+}
+
+// AvailableBalance is the native balance an account can spend, never negative.
+func AvailableBalance(balance, sellingLiabilities, baseReserve float64, subentries, sponsoring, sponsored int) float64 {
+	available := balance - MinimumBalance(baseReserve, subentries, sponsoring, sponsored) - sellingLiabilities
+	return math.Max(0, math.Round(available*1e7)/1e7)
+}
+
+// GetBaseReserve returns the network base reserve in XLM. A configured
+// override wins; otherwise the latest ledger's value is fetched and cached.
+// If Horizon is unreachable a previously fetched value is reused.
+func (s *StellarClient) GetBaseReserve(ctx context.Context) (float64, error) {
+	if s.baseReserveOverride > 0 {
+		return s.baseReserveOverride, nil
+	}
+
+	s.reserveMu.Lock()
+	defer s.reserveMu.Unlock()
+
+	if s.baseReserve > 0 && time.Since(s.baseReserveFetched) < baseReserveTTL {
+		return s.baseReserve, nil
+	}
+
+	var page horizon.LedgersPage
+	err := s.retryRead(ctx, "get_base_reserve", func() error {
+		var err error
+		page, err = s.client.Ledgers(horizonclient.LedgerRequest{Order: horizonclient.OrderDesc, Limit: 1})
+		return err
+	})
+	if err == nil && len(page.Embedded.Records) == 0 {
+		err = fmt.Errorf("horizon returned no ledgers")
+	}
+	if err != nil {
+		logWithContext(ctx, "get_base_reserve").WithError(err).Error("Failed to fetch latest ledger")
+		if s.baseReserve > 0 {
+			return s.baseReserve, nil
+		}
+		return 0, fmt.Errorf("failed to fetch base reserve: %w", err)
+	}
+
+	s.baseReserve = float64(page.Embedded.Records[0].BaseReserve) / 1e7
+	s.baseReserveFetched = time.Now()
+	return s.baseReserve, nil
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinimumBalance(t *testing.T) {
+	assert.Equal(t, 1.0, MinimumBalance(0.5, 0, 0, 0))
+	assert.Equal(t, 2.5, MinimumBalance(0.5, 3, 0, 0))
+	// Sponsoring adds reserves, being sponsored removes them.
+	assert.Equal(t, 2.0, MinimumBalance(0.5, 3, 1, 2))
+}
+
+func TestAvailableBalanceFollowsBaseReserve(t *testing.T) {
+	// 10 XLM with two subentries (e.g. two trustlines) and 1 XLM in open sell offers.
+	atDefault := AvailableBalance(10, 1, DefaultBaseReserve, 2, 0, 0)
+	assert.Equal(t, 7.0, atDefault)
+
+	doubled := AvailableBalance(10, 1, DefaultBaseReserve*2, 2, 0, 0)
+	assert.Equal(t, 5.0, doubled)
+	assert.Less(t, doubled, atDefault)
+
+	// A reserve higher than the balance leaves nothing spendable rather than a negative amount.
+	assert.Equal(t, 0.0, AvailableBalance(1, 0, 5, 0, 0, 0))
+}
+
+func TestGetBaseReserve(t *testing.T) {
+	t.Run("Override skips Horizon", func(t *testing.T) {
+		client := NewStellarClient("http://127.0.0.1:0", network.TestNetworkPassphrase, WithBaseReserve(1.25)).(*StellarClient)
+		reserve, err := client.GetBaseReserve(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1.25, reserve)
+	})
+
+	t.Run("Fresh cached value reused", func(t *testing.T) {
+		client := NewStellarClient("http://127.0.0.1:0", network.TestNetworkPassphrase).(*StellarClient)
+		client.baseReserve = 0.75
+		client.baseReserveFetched = time.Now()
+
+		reserve, err := client.GetBaseReserve(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 0.75, reserve)
+	})
+
+	t.Run("Stale value used when Horizon is unreachable", func(t *testing.T) {
+		client := NewStellarClient("http://127.0.0.1:0", network.TestNetworkPassphrase).(*StellarClient)
+		client.baseReserve = 0.75
+		client.baseReserveFetched = time.Now().Add(-2 * baseReserveTTL)
+
+		reserve, err := client.GetBaseReserve(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 0.75, reserve)
+	})
+
+	t.Run("No value and Horizon unreachable", func(t *testing.T) {
+		client := NewStellarClient("http://127.0.0.1:0", network.TestNetworkPassphrase).(*StellarClient)
+		_, err := client.GetBaseReserve(context.Background())
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignedToken(t *testing.T) {
+	token, err := NewSignedToken("secret", "password-reset")
+	require.NoError(t, err)
+
+	other, err := NewSignedToken("secret", "password-reset")
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other, "tokens are random")
+
+	assert.True(t, VerifySignedToken("secret", "password-reset", token))
+	assert.False(t, VerifySignedToken("other-secret", "password-reset", token), "signed with another secret")
+	assert.False(t, VerifySignedToken("secret", "email-verification", token), "issued for another purpose")
+
+	body, sig, _ := strings.Cut(token, ".")
+	tampered := strings.Repeat("0", len(body)) + "." + sig
+	assert.False(t, VerifySignedToken("secret", "password-reset", tampered))
+	assert.False(t, VerifySignedToken("secret", "password-reset", body))
+	assert.False(t, VerifySignedToken("secret", "password-reset", ""))
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Signer signs transaction envelopes for a StellarClient. A key reference
+// names the signing key: for KeypairSigner it is the secret seed itself, for
+// a signer backed by an HSM or remote service it is whatever that service
+// understands, typically the account address, so the backend never holds
+// the secret.
+type Signer interface {
+	// Address returns the account controlled by the key keyRef names.
+	Address(ctx context.Context, keyRef string) (string, error)
+	// Sign returns envelopeXDR signed with the key keyRef names for the
+	// network identified by networkPassphrase.
+	Sign(ctx context.Context, envelopeXDR string, keyRef string, networkPassphrase string) (string, error)
+}
+
+// WithSigner replaces the default in-process KeypairSigner. A nil signer
+// keeps the default.
+func WithSigner(signer Signer) ClientOption {
+	return func(s *StellarClient) {
+		if signer != nil {
+			s.signer = signer
+		}
+	}
+}
+
+// KeypairSigner signs in process with a secret seed passed as the key
+// reference. It is the StellarClient default.
+type KeypairSigner struct{}
+
+// Address parses the secret seed and returns its public key. The error never
+// includes the seed.
+func (KeypairSigner) Address(ctx context.Context, keyRef string) (string, error) {
+	kp, err := keypair.ParseFull(keyRef)
+	if err != nil {
+		return "", fmt.Errorf("invalid secret key: %w", err)
+	}
+	return kp.Address(), nil
+}
+
+// Sign signs with the secret seed keyRef; see SignTx.
+func (KeypairSigner) Sign(ctx context.Context, envelopeXDR string, keyRef string, networkPassphrase string) (string, error) {
+	return SignTx(ctx, envelopeXDR, keyRef, networkPassphrase)
+}
+
+// ExternalSignFunc hands an unsigned envelope to an external signer for
+// account and returns the signed envelope.
+type ExternalSignFunc func(ctx context.Context, envelopeXDR string, account string, networkPassphrase string) (string, error)
+
+// ExternalSigner delegates signing to a callback, such as a client for an HSM
+// or remote signing service. Key references are account addresses; a secret
+// seed is rejected so one cannot reach the callback or the logs by mistake.
+type ExternalSigner struct {
+	SignFunc ExternalSignFunc
+}
+
+// NewExternalSigner returns an ExternalSigner calling sign.
+func NewExternalSigner(sign ExternalSignFunc) *ExternalSigner {
+	return &ExternalSigner{SignFunc: sign}
+}
+
+// Address checks that keyRef is an account address and returns it.
+func (e *ExternalSigner) Address(ctx context.Context, keyRef string) (string, error) {
+	if _, err := keypair.ParseAddress(keyRef); err != nil {
+		return "", errors.New("external signer key reference must be a Stellar account address")
+	}
+	return keyRef, nil
+}
+
+// Sign sends envelopeXDR to the callback and checks that what comes back is
+// the same transaction, so a misbehaving signer cannot swap in another one.
+func (e *ExternalSigner) Sign(ctx context.Context, envelopeXDR string, keyRef string, networkPassphrase string) (string, error) {
+	entry := logWithContext(ctx, "external_sign").WithField("account", keyRef)
+	if e.SignFunc == nil {
+		return envelopeXDR, errors.New("external signer has no sign function")
+	}
+	account, err := e.Address(ctx, keyRef)
+	if err != nil {
+		return envelopeXDR, err
+	}
+	unsignedHash, err := envelopeHash(envelopeXDR, networkPassphrase)
+	if err != nil {
+		return envelopeXDR, err
+	}
+
+	entry.Info("Requesting signature from external signer")
+	signedXDR, err := e.SignFunc(ctx, envelopeXDR, account, networkPassphrase)
+	if err != nil {
+		entry.WithError(err).Error("External signer failed")
+		return envelopeXDR, fmt.Errorf("external signer failed: %w", err)
+	}
+	signedHash, err := envelopeHash(signedXDR, networkPassphrase)
+	if err != nil {
+		entry.WithError(err).Error("External signer returned an invalid envelope")
+		return envelopeXDR, fmt.Errorf("external signer returned an invalid envelope: %w", err)
+	}
+	if signedHash != unsignedHash {
+		entry.Error("External signer returned a different transaction")
+		return envelopeXDR, errors.New("external signer returned a different transaction")
+	}
+
+	entry.Info("Transaction signed by external signer")
+	return signedXDR, nil
+}
+
+// envelopeHash is the hex hash of the transaction in envelopeXDR, which
+// signatures do not change.
+func envelopeHash(envelopeXDR, networkPassphrase string) (string, error) {
+	genericTx, err := txnbuild.TransactionFromXDR(envelopeXDR)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse envelope XDR: %w", err)
+	}
+	tx, ok := genericTx.Transaction()
+	if !ok {
+		return "", fmt.Errorf("XDR is not a transaction envelope")
+	}
+	return tx.HashHex(networkPassphrase)
+}
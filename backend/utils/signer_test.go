@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSigner is a Signer that records what it is asked to sign and
+// signs with a key it holds, as an external signer would.
+type recordingSigner struct {
+	kp      *keypair.Full
+	keyRefs []string
+	signed  []string
+}
+
+func (r *recordingSigner) Address(ctx context.Context, keyRef string) (string, error) {
+	if keyRef != r.kp.Address() {
+		return "", errors.New("unknown key")
+	}
+	return keyRef, nil
+}
+
+func (r *recordingSigner) Sign(ctx context.Context, envelopeXDR string, keyRef string, networkPassphrase string) (string, error) {
+	r.keyRefs = append(r.keyRefs, keyRef)
+	r.signed = append(r.signed, envelopeXDR)
+	return SignTx(ctx, envelopeXDR, r.kp.Seed(), networkPassphrase)
+}
+
+// newSubmitTestServer serves source from Horizon and records the envelope
+// posted to /transactions.
+func newSubmitTestServer(t *testing.T, source string, submitted *string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/accounts/"):
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": source, "account_id": source, "sequence": "100"})
+		case r.Method == http.MethodPost && r.URL.Path == "/transactions":
+			require.NoError(t, r.ParseForm())
+			*submitted = r.PostForm.Get("tx")
+			hash, err := envelopeHash(*submitted, network.TestNetworkPassphrase)
+			require.NoError(t, err)
+			json.NewEncoder(w).Encode(map[string]interface{}{"hash": hash, "successful": true, "envelope_xdr": *submitted})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func unsignedTestEnvelope(t *testing.T, source string) string {
+	t.Helper()
+	destKP, _ := keypair.Random()
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: source, Sequence: 1},
+		IncrementSequenceNum: true,
+		BaseFee:              txnbuild.MinBaseFee,
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+		Operations: []txnbuild.Operation{
+			&txnbuild.Payment{Destination: destKP.Address(), Amount: "10", Asset: txnbuild.NativeAsset{}},
+		},
+	})
+	require.NoError(t, err)
+	envelope, err := tx.Base64()
+	require.NoError(t, err)
+	return envelope
+}
+
+func TestSubmitPaymentUsesSigner(t *testing.T) {
+	sourceKP, _ := keypair.Random()
+	destKP, _ := keypair.Random()
+	signer := &recordingSigner{kp: sourceKP}
+	var submitted string
+	server := newSubmitTestServer(t, sourceKP.Address(), &submitted)
+	client := NewStellarClient(server.URL, network.TestNetworkPassphrase, WithSigner(signer))
+
+	hash, err := client.SubmitPayment(context.Background(), sourceKP.Address(), destKP.Address(), "XLM", "", "25")
+	require.NoError(t, err)
+
+	require.Len(t, signer.signed, 1)
+	assert.Equal(t, []string{sourceKP.Address()}, signer.keyRefs)
+	parsed, err := txnbuild.TransactionFromXDR(signer.signed[0])
+	require.NoError(t, err)
+	unsigned, ok := parsed.Transaction()
+	require.True(t, ok)
+	assert.Empty(t, unsigned.Signatures(), "the signer gets the unsigned envelope")
+	assert.Equal(t, sourceKP.Address(), unsigned.SourceAccount().AccountID)
+	assert.Equal(t, int64(101), unsigned.SequenceNumber())
+	op := unsigned.Operations()[0].(*txnbuild.Payment)
+	assert.Equal(t, destKP.Address(), op.Destination)
+	assert.Equal(t, "25.0000000", op.Amount)
+
+	unsignedHash, err := unsigned.HashHex(network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	assert.Equal(t, unsignedHash, hash)
+	parsed, err = txnbuild.TransactionFromXDR(submitted)
+	require.NoError(t, err)
+	signed, _ := parsed.Transaction()
+	assert.Len(t, signed.Signatures(), 1, "Horizon gets the signer's envelope")
+
+	t.Run("Unknown key is rejected before Horizon is asked", func(t *testing.T) {
+		otherKP, _ := keypair.Random()
+		_, err := client.SubmitPayment(context.Background(), otherKP.Address(), destKP.Address(), "XLM", "", "25")
+		assert.Error(t, err)
+		assert.Len(t, signer.signed, 1)
+	})
+}
+
+func TestKeypairSigner(t *testing.T) {
+	kp, _ := keypair.Random()
+	signer := KeypairSigner{}
+
+	address, err := signer.Address(context.Background(), kp.Seed())
+	require.NoError(t, err)
+	assert.Equal(t, kp.Address(), address)
+
+	_, err = signer.Address(context.Background(), "SBADSEEDTHATSHOULDNOTLEAK")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "SBADSEEDTHATSHOULDNOTLEAK")
+
+	envelope := unsignedTestEnvelope(t, kp.Address())
+	signedXDR, err := signer.Sign(context.Background(), envelope, kp.Seed(), network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	parsed, err := txnbuild.TransactionFromXDR(signedXDR)
+	require.NoError(t, err)
+	signed, _ := parsed.Transaction()
+	assert.Len(t, signed.Signatures(), 1)
+}
+
+func TestExternalSigner(t *testing.T) {
+	kp, _ := keypair.Random()
+	ctx := context.Background()
+	envelope := unsignedTestEnvelope(t, kp.Address())
+
+	t.Run("Passes the unsigned envelope and account to the callback", func(t *testing.T) {
+		var gotXDR, gotAccount string
+		signer := NewExternalSigner(func(ctx context.Context, envelopeXDR, account, networkPassphrase string) (string, error) {
+			gotXDR, gotAccount = envelopeXDR, account
+			return SignTx(ctx, envelopeXDR, kp.Seed(), networkPassphrase)
+		})
+		signedXDR, err := signer.Sign(ctx, envelope, kp.Address(), network.TestNetworkPassphrase)
+		require.NoError(t, err)
+		assert.Equal(t, envelope, gotXDR)
+		assert.Equal(t, kp.Address(), gotAccount)
+		assert.NotEqual(t, envelope, signedXDR)
+	})
+
+	t.Run("Secret seeds never reach the callback", func(t *testing.T) {
+		called := false
+		signer := NewExternalSigner(func(ctx context.Context, envelopeXDR, account, networkPassphrase string) (string, error) {
+			called = true
+			return envelopeXDR, nil
+		})
+		_, err := signer.Address(ctx, kp.Seed())
+		assert.Error(t, err)
+		_, err = signer.Sign(ctx, envelope, kp.Seed(), network.TestNetworkPassphrase)
+		assert.Error(t, err)
+		assert.NotContains(t, err.Error(), kp.Seed())
+		assert.False(t, called)
+	})
+
+	t.Run("A different transaction back is rejected", func(t *testing.T) {
+		other := unsignedTestEnvelope(t, kp.Address())
+		signer := NewExternalSigner(func(ctx context.Context, envelopeXDR, account, networkPassphrase string) (string, error) {
+			return SignTx(ctx, other, kp.Seed(), networkPassphrase)
+		})
+		signedXDR, err := signer.Sign(ctx, envelope, kp.Address(), network.TestNetworkPassphrase)
+		assert.Error(t, err)
+		assert.Equal(t, envelope, signedXDR)
+	})
+
+	t.Run("Callback errors are returned", func(t *testing.T) {
+		signer := NewExternalSigner(func(ctx context.Context, envelopeXDR, account, networkPassphrase string) (string, error) {
+			return "", errors.New("hsm unavailable")
+		})
+		_, err := signer.Sign(ctx, envelope, kp.Address(), network.TestNetworkPassphrase)
+		assert.ErrorContains(t, err, "hsm unavailable")
+	})
+}
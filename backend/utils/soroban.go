@@ -0,0 +1,357 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// ErrSorobanRPCNotConfigured is returned by InvokeContract and QueryContract
+// when the client has no Soroban RPC endpoint.
+var ErrSorobanRPCNotConfigured = errors.New("soroban RPC URL not configured")
+
+// querySource is the all-zero account that sources read-only simulations.
+// Simulation does not load it, so it need not exist.
+var querySource = strkey.MustEncode(strkey.VersionByteAccountID, make([]byte, 32))
+
+// SimulationError is a contract invocation the RPC rejected during
+// simulation, typically a contract panic or a failed auth or budget check.
+// Message is the RPC's diagnostic text.
+type SimulationError struct {
+	ContractID string
+	Function   string
+	Message    string
+}
+
+func (e *SimulationError) Error() string {
+	return fmt.Sprintf("simulation of %s.%s failed: %s", e.ContractID, e.Function, e.Message)
+}
+
+// IsSimulationError reports whether err is a failed contract simulation.
+func IsSimulationError(err error) bool {
+	var simErr *SimulationError
+	return errors.As(err, &simErr)
+}
+
+// WithSorobanRPC sets the Soroban RPC endpoint used to simulate contract
+// invocations.
+func WithSorobanRPC(url string) ClientOption {
+	return func(s *StellarClient) {
+		s.rpcURL = url
+	}
+}
+
+// simulateResponse is the subset of the simulateTransaction result we use.
+type simulateResponse struct {
+	Error           string `json:"error"`
+	TransactionData string `json:"transactionData"`
+	MinResourceFee  string `json:"minResourceFee"`
+	Results         []struct {
+		Auth []string `json:"auth"`
+		// XDR is the base64 ScVal the function returned.
+		XDR string `json:"xdr"`
+	} `json:"results"`
+}
+
+// InvokeContract builds an unsigned transaction, sourced from sourceAccount,
+// that calls function on the contract with args. The transaction is
+// simulated against the Soroban RPC first so the returned envelope carries
+// the footprint, resource fee, and authorization entries needed to submit it.
+func (s *StellarClient) InvokeContract(ctx context.Context, sourceAccount, contractID, function string, args []xdr.ScVal) (string, error) {
+	log := logWithContext(ctx, "invoke_contract").WithField("contract_id", contractID).WithField("function", function)
+	if s.rpcURL == "" {
+		return "", ErrSorobanRPCNotConfigured
+	}
+
+	op, err := contractCall(contractID, function, args)
+	if err != nil {
+		return "", err
+	}
+	op.SourceAccount = sourceAccount
+
+	account, err := s.accountDetail(ctx, "invoke_contract", sourceAccount)
+	if err != nil {
+		log.WithError(err).Error("Failed to load source account")
+		return "", fmt.Errorf("failed to load source account: %w", err)
+	}
+	sequence, err := account.GetSequenceNumber()
+	if err != nil {
+		return "", fmt.Errorf("failed to read source account sequence: %w", err)
+	}
+
+	// build is called twice, before and after simulation, from the same
+	// sequence number so both envelopes describe the same transaction.
+	build := func(baseFee int64) (string, error) {
+		tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+			SourceAccount:        &txnbuild.SimpleAccount{AccountID: sourceAccount, Sequence: sequence},
+			IncrementSequenceNum: true,
+			BaseFee:              baseFee,
+			Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewTimeout(300)},
+			Operations:           []txnbuild.Operation{op},
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to build contract invocation: %w", err)
+		}
+		return tx.Base64()
+	}
+
+	envelope, err := build(s.baseFee(ctx))
+	if err != nil {
+		return "", err
+	}
+
+	sim, err := s.simulate(ctx, envelope)
+	if err != nil {
+		log.WithError(err).Error("Failed to simulate contract invocation")
+		return "", err
+	}
+	if sim.Error != "" {
+		simErr := &SimulationError{ContractID: contractID, Function: function, Message: sim.Error}
+		log.WithError(simErr).Warn("Contract invocation failed simulation")
+		return "", simErr
+	}
+
+	var data xdr.SorobanTransactionData
+	if err := xdr.SafeUnmarshalBase64(sim.TransactionData, &data); err != nil {
+		return "", fmt.Errorf("invalid simulation transaction data: %w", err)
+	}
+	resourceFee, err := strconv.ParseInt(sim.MinResourceFee, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid simulation resource fee %q: %w", sim.MinResourceFee, err)
+	}
+	if len(sim.Results) > 0 {
+		for _, entry := range sim.Results[0].Auth {
+			var auth xdr.SorobanAuthorizationEntry
+			if err := xdr.SafeUnmarshalBase64(entry, &auth); err != nil {
+				return "", fmt.Errorf("invalid simulation auth entry: %w", err)
+			}
+			op.Auth = append(op.Auth, auth)
+		}
+	}
+	// txnbuild adds the transaction data's resource fee on top of the base
+	// fee, so it is set there rather than folded into the base fee.
+	data.ResourceFee = xdr.Int64(resourceFee)
+	op.Ext = xdr.TransactionExt{V: 1, SorobanData: &data}
+
+	envelope, err = build(s.baseFee(ctx))
+	if err != nil {
+		return "", err
+	}
+
+	log.WithField("resource_fee", resourceFee).Info("Contract invocation built")
+	return envelope, nil
+}
+
+// QueryContract calls a read-only function on the contract by simulating it,
+// without signing or submitting anything, and returns the value it returned.
+func (s *StellarClient) QueryContract(ctx context.Context, contractID, function string, args ...xdr.ScVal) (xdr.ScVal, error) {
+	log := logWithContext(ctx, "query_contract").WithField("contract_id", contractID).WithField("function", function)
+	if s.rpcURL == "" {
+		return xdr.ScVal{}, ErrSorobanRPCNotConfigured
+	}
+
+	op, err := contractCall(contractID, function, args)
+	if err != nil {
+		return xdr.ScVal{}, err
+	}
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: querySource},
+		IncrementSequenceNum: true,
+		BaseFee:              txnbuild.MinBaseFee,
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+		Operations:           []txnbuild.Operation{op},
+	})
+	if err != nil {
+		return xdr.ScVal{}, fmt.Errorf("failed to build contract query: %w", err)
+	}
+	envelope, err := tx.Base64()
+	if err != nil {
+		return xdr.ScVal{}, err
+	}
+
+	sim, err := s.simulate(ctx, envelope)
+	if err != nil {
+		log.WithError(err).Error("Failed to simulate contract query")
+		return xdr.ScVal{}, err
+	}
+	if sim.Error != "" {
+		simErr := &SimulationError{ContractID: contractID, Function: function, Message: sim.Error}
+		log.WithError(simErr).Warn("Contract query failed simulation")
+		return xdr.ScVal{}, simErr
+	}
+	if len(sim.Results) == 0 || sim.Results[0].XDR == "" {
+		return xdr.ScVal{}, fmt.Errorf("simulation of %s.%s returned no result", contractID, function)
+	}
+
+	var result xdr.ScVal
+	if err := xdr.SafeUnmarshalBase64(sim.Results[0].XDR, &result); err != nil {
+		return xdr.ScVal{}, fmt.Errorf("invalid simulation result: %w", err)
+	}
+	return result, nil
+}
+
+// contractCall builds the operation invoking function on the contract.
+func contractCall(contractID, function string, args []xdr.ScVal) (*txnbuild.InvokeHostFunction, error) {
+	raw, err := strkey.Decode(strkey.VersionByteContract, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid contract ID %q: %w", contractID, err)
+	}
+	var contract xdr.ContractId
+	copy(contract[:], raw)
+
+	return &txnbuild.InvokeHostFunction{
+		HostFunction: xdr.HostFunction{
+			Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+			InvokeContract: &xdr.InvokeContractArgs{
+				ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contract},
+				FunctionName:    xdr.ScSymbol(function),
+				Args:            args,
+			},
+		},
+	}, nil
+}
+
+// simulate calls the RPC's simulateTransaction method. Transport and RPC
+// protocol errors are returned as errors; a failed simulation is reported
+// through the response's Error field.
+func (s *StellarClient) simulate(ctx context.Context, envelope string) (*simulateResponse, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "simulateTransaction",
+		"params":  map[string]string{"transaction": envelope},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.rpcURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create simulation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach soroban RPC: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("soroban RPC returned HTTP %d", resp.StatusCode)
+	}
+
+	var rpcResp struct {
+		Result *simulateResponse `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return nil, fmt.Errorf("failed to decode simulation response: %w", err)
+	}
+	if rpcResp.Error != nil {
+		return nil, fmt.Errorf("soroban RPC error %d: %s", rpcResp.Error.Code, rpcResp.Error.Message)
+	}
+	if rpcResp.Result == nil {
+		return nil, fmt.Errorf("soroban RPC returned no simulation result")
+	}
+	return rpcResp.Result, nil
+}
+
+// EscrowReleaseFunction is the escrow contract function that pays an escrow
+// out to its recipient.
+const EscrowReleaseFunction = "release_escrow"
+
+// EscrowRefundFunction is the escrow contract function that returns all or
+// part of an escrow to its sender.
+const EscrowRefundFunction = "refund_partial"
+
+// Refund reasons the escrow contract records with a refund.
+const (
+	EscrowRefundReasonDispute       = "Dispute"
+	EscrowRefundReasonSenderRequest = "SenderRequest"
+	EscrowRefundReasonAdminAction   = "AdminAction"
+)
+
+// BuildEscrowReleaseTx builds an unsigned call to the escrow contract's
+// release_escrow(escrow_id, caller, token_address), sourced from and
+// authorized by caller. The token address is the Stellar Asset Contract of
+// the escrowed asset.
+func (s *StellarClient) BuildEscrowReleaseTx(ctx context.Context, caller, contractID string, escrowID uint64, assetCode, issuer string) (string, error) {
+	args, err := s.escrowCallArgs(caller, escrowID, assetCode, issuer)
+	if err != nil {
+		return "", err
+	}
+	return s.InvokeContract(ctx, caller, contractID, EscrowReleaseFunction, args)
+}
+
+// BuildEscrowRefundTx builds an unsigned call to the escrow contract's
+// refund_partial(escrow_id, caller, token_address, refund_amount, reason),
+// sourced from and authorized by caller, returning amount of the escrowed
+// asset to the sender. reason is one of the EscrowRefundReason values.
+func (s *StellarClient) BuildEscrowRefundTx(ctx context.Context, caller, contractID string, escrowID uint64, assetCode, issuer string, amount Amount, reason string) (string, error) {
+	args, err := s.escrowRefundArgs(caller, escrowID, assetCode, issuer, amount, reason)
+	if err != nil {
+		return "", err
+	}
+	return s.InvokeContract(ctx, caller, contractID, EscrowRefundFunction, args)
+}
+
+// escrowRefundArgs builds the arguments of refund_partial: those of
+// escrowCallArgs followed by the refund amount and reason.
+func (s *StellarClient) escrowRefundArgs(caller string, escrowID uint64, assetCode, issuer string, amount Amount, reason string) ([]xdr.ScVal, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("refund amount must be positive, got %s", amount)
+	}
+	args, err := s.escrowCallArgs(caller, escrowID, assetCode, issuer)
+	if err != nil {
+		return nil, err
+	}
+	refund := xdr.Int128Parts{Hi: 0, Lo: xdr.Uint64(amount)}
+	reasonSym := xdr.ScSymbol(reason)
+	reasonVec := &xdr.ScVec{{Type: xdr.ScValTypeScvSymbol, Sym: &reasonSym}}
+	return append(args,
+		xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &refund},
+		xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &reasonVec},
+	), nil
+}
+
+// escrowCallArgs builds the (escrow_id, caller, token_address) arguments the
+// escrow contract's settlement functions share. The token address is the
+// Stellar Asset Contract of the escrowed asset.
+func (s *StellarClient) escrowCallArgs(caller string, escrowID uint64, assetCode, issuer string) ([]xdr.ScVal, error) {
+	var callerID xdr.AccountId
+	if err := callerID.SetAddress(caller); err != nil {
+		return nil, fmt.Errorf("invalid caller account %q: %w", caller, err)
+	}
+
+	var asset txnbuild.Asset = txnbuild.NativeAsset{}
+	if !IsNativeAsset(assetCode) {
+		asset = txnbuild.CreditAsset{Code: assetCode, Issuer: issuer}
+	}
+	assetXDR, err := asset.ToXDR()
+	if err != nil {
+		return nil, fmt.Errorf("invalid escrow asset: %w", err)
+	}
+	tokenID, err := assetXDR.ContractID(s.networkPassphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive asset contract: %w", err)
+	}
+	token := xdr.ContractId(tokenID)
+
+	id := xdr.Uint64(escrowID)
+	return []xdr.ScVal{
+		{Type: xdr.ScValTypeScvU64, U64: &id},
+		{Type: xdr.ScValTypeScvAddress, Address: &xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &callerID}},
+		{Type: xdr.ScValTypeScvAddress, Address: &xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &token}},
+	}, nil
+}
@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSorobanTestServer serves the source account from Horizon and answers
+// simulateTransaction with simResult.
+func newSorobanTestServer(t *testing.T, source string, simResult map[string]interface{}) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/accounts/"):
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": source, "account_id": source, "sequence": "100"})
+		case r.Method == http.MethodPost && r.URL.Path == "/rpc":
+			var req struct {
+				Method string            `json:"method"`
+				Params map[string]string `json:"params"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "simulateTransaction", req.Method)
+			assert.NotEmpty(t, req.Params["transaction"])
+			json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": simResult})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestInvokeContract(t *testing.T) {
+	sourceKP, _ := keypair.Random()
+	source := sourceKP.Address()
+	contractID := strkey.MustEncode(strkey.VersionByteContract, make([]byte, 32))
+	args := []xdr.ScVal{{Type: xdr.ScValTypeScvU32, U32: func() *xdr.Uint32 { v := xdr.Uint32(7); return &v }()}}
+
+	t.Run("Builds a simulated InvokeHostFunction envelope", func(t *testing.T) {
+		// The transaction data under-reports the fee; minResourceFee wins.
+		txData, err := xdr.MarshalBase64(xdr.SorobanTransactionData{ResourceFee: 1000})
+		require.NoError(t, err)
+		server := newSorobanTestServer(t, source, map[string]interface{}{
+			"transactionData": txData,
+			"minResourceFee":  "1234",
+			"results":         []map[string]interface{}{{"auth": []string{}, "xdr": "AAAAAQ=="}},
+			"latestLedger":    10,
+		})
+		defer server.Close()
+
+		client := NewStellarClient(server.URL, network.TestNetworkPassphrase, WithSorobanRPC(server.URL+"/rpc"))
+		envelope, err := client.InvokeContract(context.Background(), source, contractID, "release", args)
+		require.NoError(t, err)
+
+		parsed, err := txnbuild.TransactionFromXDR(envelope)
+		require.NoError(t, err)
+		tx, ok := parsed.Transaction()
+		require.True(t, ok)
+		assert.Equal(t, int64(101), tx.SequenceNumber())
+		// The resource fee is charged once, through the transaction data.
+		assert.Equal(t, int64(txnbuild.MinBaseFee+1234), tx.MaxFee())
+		assert.Equal(t, xdr.Int64(1234), tx.ToXDR().V1.Tx.Ext.SorobanData.ResourceFee)
+
+		require.Len(t, tx.Operations(), 1)
+		op, ok := tx.Operations()[0].(*txnbuild.InvokeHostFunction)
+		require.True(t, ok, "operation is an InvokeHostFunction")
+		require.NotNil(t, op.HostFunction.InvokeContract)
+		assert.Equal(t, xdr.ScSymbol("release"), op.HostFunction.InvokeContract.FunctionName)
+		assert.Equal(t, args, op.HostFunction.InvokeContract.Args)
+
+		address := op.HostFunction.InvokeContract.ContractAddress
+		require.NotNil(t, address.ContractId)
+		assert.Equal(t, contractID, strkey.MustEncode(strkey.VersionByteContract, address.ContractId[:]))
+
+		require.NotNil(t, op.Ext.SorobanData, "simulation data is attached")
+		assert.Equal(t, xdr.Int64(1234), op.Ext.SorobanData.ResourceFee)
+	})
+
+	t.Run("Contract panic is surfaced as a simulation error", func(t *testing.T) {
+		server := newSorobanTestServer(t, source, map[string]interface{}{
+			"error":        "HostError: Error(Contract, #3)",
+			"latestLedger": 10,
+		})
+		defer server.Close()
+
+		client := NewStellarClient(server.URL, network.TestNetworkPassphrase, WithSorobanRPC(server.URL+"/rpc"))
+		_, err := client.InvokeContract(context.Background(), source, contractID, "release", args)
+		require.Error(t, err)
+		assert.True(t, IsSimulationError(err))
+		assert.Contains(t, err.Error(), "release")
+		assert.Contains(t, err.Error(), "Error(Contract, #3)")
+	})
+
+	t.Run("Invalid contract ID", func(t *testing.T) {
+		client := NewStellarClient("http://unused", network.TestNetworkPassphrase, WithSorobanRPC("http://unused/rpc"))
+		_, err := client.InvokeContract(context.Background(), source, source, "release", nil)
+		assert.Error(t, err)
+		assert.False(t, IsSimulationError(err))
+	})
+
+	t.Run("RPC not configured", func(t *testing.T) {
+		client := NewStellarClient("http://unused", network.TestNetworkPassphrase)
+		_, err := client.InvokeContract(context.Background(), source, contractID, "release", nil)
+		assert.ErrorIs(t, err, ErrSorobanRPCNotConfigured)
+	})
+}
@@ -2,13 +2,19 @@ package utils
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stellar/go/clients/horizonclient"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
 )
 
 type ctxKey string
@@ -16,14 +22,72 @@ type ctxKey string
 const (
 	ctxRequestIDKey ctxKey = "requestID"
 	ctxUserIDKey    ctxKey = "userID"
+	ctxBaseFeeKey   ctxKey = "baseFee"
 )
 
 type StellarClientInterface interface {
-	SubmitPayment(ctx context.Context, sourceSecret string, destination string, assetCode string, issuer string, amount string) (string, error)
+	SubmitPayment(ctx context.Context, source string, destination string, assetCode string, issuer string, amount string) (string, error)
 	ValidateAccount(ctx context.Context, accountID string) error
-	BuildEscrowTx(ctx context.Context, sender string, recipient string, assetCode string, issuer string, amount string) (string, error)
-	BuildPaymentTx(ctx context.Context, sourceAccount txnbuild.Account, destination string, assetCode string, issuer string, amount string) (*txnbuild.Transaction, error)
-	SignTx(ctx context.Context, envelopeXDR string, secretKey string) (string, error)
+	BuildEscrowTx(ctx context.Context, sender string, recipient string, assetCode string, issuer string, amount string, memo txnbuild.Memo) (string, error)
+	BuildPaymentTx(ctx context.Context, sourceAccount txnbuild.Account, destination string, assetCode string, issuer string, amount string, memo txnbuild.Memo) (*txnbuild.Transaction, error)
+	SignTx(ctx context.Context, envelopeXDR string, keyRef string) (string, error)
+	BuildFeeBumpTx(ctx context.Context, innerSignedXDR string, feeAccount string, baseFee int64) (string, error)
+	AddSignature(ctx context.Context, signedXDR string, secretKey string) (string, error)
+	BuildBatchPaymentTx(ctx context.Context, sourceAccount string, payments []BatchPayment) (string, error)
+	SubmitTransaction(ctx context.Context, signedXDR string) (string, error)
+	GetTransactionStatus(ctx context.Context, txHash string) (TxStatus, error)
+	GetTransactionOutcome(ctx context.Context, txHash string) (TxOutcome, error)
+	GetBalances(ctx context.Context, accountID string) ([]AccountBalance, error)
+	BuildChangeTrustTx(ctx context.Context, account string, assetCode string, issuer string, limit string) (string, error)
+	BuildAccountMergeTx(ctx context.Context, source string, destination string) (*AccountMergeTx, error)
+	BuildCreateClaimableBalanceTx(ctx context.Context, sender, recipient, assetCode, issuer, amount string, claimAfter time.Time, memo txnbuild.Memo) (*ClaimableBalanceTx, error)
+	BuildClaimClaimableBalanceTx(ctx context.Context, balanceID, claimant string) (string, error)
+	GetBaseReserve(ctx context.Context) (float64, error)
+	InvokeContract(ctx context.Context, sourceAccount string, contractID string, function string, args []xdr.ScVal) (string, error)
+	BuildEscrowReleaseTx(ctx context.Context, caller string, contractID string, escrowID uint64, assetCode string, issuer string) (string, error)
+	BuildEscrowRefundTx(ctx context.Context, caller string, contractID string, escrowID uint64, assetCode string, issuer string, amount Amount, reason string) (string, error)
+	QueryContract(ctx context.Context, contractID string, function string, args ...xdr.ScVal) (xdr.ScVal, error)
+	GetPaymentHistory(ctx context.Context, accountID string, limit int, cursor string) (*PaymentHistory, error)
+}
+
+// ErrAccountNotFound is returned when Horizon has no record of an account.
+var ErrAccountNotFound = errors.New("account not found")
+
+// IsAccountNotFound reports whether err means the account does not exist on the network.
+func IsAccountNotFound(err error) bool {
+	return errors.Is(err, ErrAccountNotFound)
+}
+
+// AccountBalance is a single asset balance held by an account.
+type AccountBalance struct {
+	AssetType   string `json:"asset_type"`
+	AssetCode   string `json:"asset_code"`
+	AssetIssuer string `json:"asset_issuer,omitempty"`
+	Balance     string `json:"balance"`
+	// Available is the spendable native balance after the minimum reserve and
+	// selling liabilities. It is only set for XLM.
+	Available string `json:"available,omitempty"`
+}
+
+// TxStatus is the on-ledger state of a submitted transaction.
+type TxStatus string
+
+const (
+	// TxStatusPending means Horizon does not know the transaction yet.
+	TxStatusPending TxStatus = "pending"
+	TxStatusSuccess TxStatus = "success"
+	TxStatusFailed  TxStatus = "failed"
+)
+
+// MaxOperationsPerTx is the protocol limit on operations in a single Stellar transaction.
+const MaxOperationsPerTx = 100
+
+// BatchPayment is a single payment operation within a batch transaction.
+type BatchPayment struct {
+	Destination string
+	AssetCode   string
+	Issuer      string
+	Amount      string
 }
 
 
@@ -31,13 +95,64 @@ type StellarClientInterface interface {
 type StellarClient struct {
 	client            *horizonclient.Client
 	networkPassphrase string
+	// rpcURL is the Soroban RPC endpoint used to simulate contract calls.
+	rpcURL string
+	// defaultBaseFee is the per-operation fee, in stroops, for built
+	// transactions unless a request overrides it; see WithBaseFee.
+	defaultBaseFee int64
+
+	// baseReserveOverride, when positive, replaces the base reserve read from Horizon.
+	baseReserveOverride float64
+	reserveMu           sync.Mutex
+	baseReserve         float64
+	baseReserveFetched  time.Time
+
+	// accounts caches ValidateAccount results; nil disables caching.
+	accounts *accountCache
+
+	// httpClient carries Horizon and Soroban RPC requests. readRetries and
+	// retryBackoff govern retries of idempotent reads; see WithHorizonHTTP.
+	httpClient   *http.Client
+	readRetries  int
+	retryBackoff time.Duration
+
+	// signer signs the transactions the client submits itself; see WithSigner.
+	signer Signer
+
+	// dryRun makes NewStellarClient return a DryRunClient; see WithDryRun.
+	dryRun bool
+
+	// reclaimWindow is how long recipients have to claim a claimable
+	// balance; see WithReclaimWindow.
+	reclaimWindow time.Duration
+}
+
+// ClientOption customises a StellarClient.
+type ClientOption func(*StellarClient)
+
+// WithBaseReserve fixes the base reserve (in XLM) instead of reading it from
+// the latest ledger. A value of zero or less keeps the Horizon lookup.
+func WithBaseReserve(xlm float64) ClientOption {
+	return func(s *StellarClient) {
+		s.baseReserveOverride = xlm
+	}
 }
 
-func NewStellarClient(horizonURL, networkPassphrase string) StellarClientInterface {
-	return &StellarClient{
-		client:            &horizonclient.Client{HorizonURL: horizonURL},
+func NewStellarClient(horizonURL, networkPassphrase string, opts ...ClientOption) StellarClientInterface {
+	httpClient := &http.Client{Timeout: defaultHorizonTimeout}
+	client := &StellarClient{
+		client:            &horizonclient.Client{HorizonURL: horizonURL, HTTP: httpClient},
 		networkPassphrase: networkPassphrase,
+		httpClient:        httpClient,
+		signer:            KeypairSigner{},
+	}
+	for _, opt := range opts {
+		opt(client)
 	}
+	if client.dryRun {
+		return &DryRunClient{offline: client}
+	}
+	return client
 }
 
 func WithRequestContext(ctx context.Context, requestID string, userID interface{}) context.Context {
@@ -109,13 +224,15 @@ func SignTx(ctx context.Context, envelopeXDR string, secretKey string, networkPa
 	return signedXDR, nil
 }
 
-// SignTx is a wrapper that uses the client's network passphrase.
-func (s *StellarClient) SignTx(ctx context.Context, envelopeXDR string, secretKey string) (string, error) {
-	return SignTx(ctx, envelopeXDR, secretKey, s.networkPassphrase)
+// SignTx signs with the client's Signer and network passphrase. keyRef is
+// the key reference the Signer resolves: a secret seed for the default
+// KeypairSigner.
+func (s *StellarClient) SignTx(ctx context.Context, envelopeXDR string, keyRef string) (string, error) {
+	return s.signer.Sign(ctx, envelopeXDR, keyRef, s.networkPassphrase)
 }
 
-// BuildPaymentTx creates an unsigned payment transaction.
-func (s *StellarClient) BuildPaymentTx(ctx context.Context, sourceAccount txnbuild.Account, destination string, assetCode string, issuer string, amount string) (*txnbuild.Transaction, error) {
+// BuildPaymentTx creates an unsigned payment transaction. memo may be nil.
+func (s *StellarClient) BuildPaymentTx(ctx context.Context, sourceAccount txnbuild.Account, destination string, assetCode string, issuer string, amount string, memo txnbuild.Memo) (*txnbuild.Transaction, error) {
 	logWithContext(ctx, "build_payment_tx").Info("Building payment transaction")
 
 	var asset txnbuild.Asset
@@ -129,7 +246,8 @@ func (s *StellarClient) BuildPaymentTx(ctx context.Context, sourceAccount txnbui
 		txnbuild.TransactionParams{
 			SourceAccount:        sourceAccount,
 			IncrementSequenceNum: true,
-			BaseFee:              txnbuild.MinBaseFee,
+			BaseFee:              s.baseFee(ctx),
+			Memo:                 memo,
 			Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
 			Operations: []txnbuild.Operation{
 				&txnbuild.Payment{
@@ -147,28 +265,85 @@ func (s *StellarClient) BuildPaymentTx(ctx context.Context, sourceAccount txnbui
 	return tx, nil
 }
 
+// BuildBatchPaymentTx builds an unsigned transaction containing one payment
+// operation per entry and returns it as base64 XDR.
+func (s *StellarClient) BuildBatchPaymentTx(ctx context.Context, sourceAccount string, payments []BatchPayment) (string, error) {
+	logWithContext(ctx, "build_batch_payment_tx").WithFields(logrus.Fields{
+		"source_account":  sourceAccount,
+		"operation_count": len(payments),
+	}).Info("Building batch payment transaction")
+
+	if len(payments) == 0 {
+		return "", fmt.Errorf("batch contains no payments")
+	}
+	if len(payments) > MaxOperationsPerTx {
+		return "", fmt.Errorf("batch contains %d payments, maximum is %d", len(payments), MaxOperationsPerTx)
+	}
+
+	account, err := s.accountDetail(ctx, "build_batch_payment_tx", sourceAccount)
+	if err != nil {
+		logWithContext(ctx, "build_batch_payment_tx").WithError(err).Error("Failed to load source account")
+		return "", fmt.Errorf("failed to load source account: %w", err)
+	}
+
+	operations := make([]txnbuild.Operation, 0, len(payments))
+	for _, p := range payments {
+		var asset txnbuild.Asset
+		if strings.ToUpper(p.AssetCode) == "XLM" || p.AssetCode == "" {
+			asset = txnbuild.NativeAsset{}
+		} else {
+			asset = txnbuild.CreditAsset{Code: p.AssetCode, Issuer: p.Issuer}
+		}
+		operations = append(operations, &txnbuild.Payment{
+			Destination: p.Destination,
+			Amount:      p.Amount,
+			Asset:       asset,
+		})
+	}
+
+	tx, err := txnbuild.NewTransaction(
+		txnbuild.TransactionParams{
+			SourceAccount:        &account,
+			IncrementSequenceNum: true,
+			BaseFee:              s.baseFee(ctx),
+			Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+			Operations:           operations,
+		},
+	)
+	if err != nil {
+		logWithContext(ctx, "build_batch_payment_tx").WithError(err).Error("Failed to build batch payment transaction")
+		return "", fmt.Errorf("failed to build batch payment transaction: %w", err)
+	}
+
+	xdr, err := tx.Base64()
+	if err != nil {
+		logWithContext(ctx, "build_batch_payment_tx").WithError(err).Error("Failed to encode transaction to XDR")
+		return "", fmt.Errorf("failed to encode transaction to XDR: %w", err)
+	}
+	return xdr, nil
+}
 
 // SubmitPayment builds, signs, and submits a payment transaction in one go.
-func (s *StellarClient) SubmitPayment(ctx context.Context, sourceSecret string, destination string, assetCode string, issuer string, amount string) (string, error) {
+// source is the key reference of the paying account, resolved and signed
+// with by the client's Signer.
+func (s *StellarClient) SubmitPayment(ctx context.Context, source string, destination string, assetCode string, issuer string, amount string) (string, error) {
 	logWithContext(ctx, "submit_payment").Info("Starting submit payment flow")
 
-	sourceKP, err := keypair.ParseFull(sourceSecret)
+	sourceAddress, err := s.signer.Address(ctx, source)
 	if err != nil {
-		logWithContext(ctx, "submit_payment").WithError(err).Error("Invalid source secret")
-		return "", fmt.Errorf("invalid source secret: %w", err)
+		logWithContext(ctx, "submit_payment").WithError(err).Error("Invalid source key")
+		return "", fmt.Errorf("invalid source key: %w", err)
 	}
 
-	logWithContext(ctx, "submit_payment").WithField("source_account", sourceKP.Address()).Info("Loading source account")
-	sourceAccount, err := s.client.AccountDetail(horizonclient.AccountRequest{
-		AccountID: sourceKP.Address(),
-	})
+	logWithContext(ctx, "submit_payment").WithField("source_account", sourceAddress).Info("Loading source account")
+	sourceAccount, err := s.accountDetail(ctx, "submit_payment", sourceAddress)
 	if err != nil {
 		logWithContext(ctx, "submit_payment").WithError(err).Error("Failed to load source account")
 		return "", fmt.Errorf("failed to load source account: %w", err)
 	}
 
 	logWithContext(ctx, "submit_payment").Info("Building payment transaction")
-	tx, err := s.BuildPaymentTx(ctx, &sourceAccount, destination, assetCode, issuer, amount)
+	tx, err := s.BuildPaymentTx(ctx, &sourceAccount, destination, assetCode, issuer, amount, nil)
 	if err != nil {
 		return "", err
 	}
@@ -180,7 +355,7 @@ func (s *StellarClient) SubmitPayment(ctx context.Context, sourceSecret string,
 	}
 
 	logWithContext(ctx, "submit_payment").Info("Signing transaction")
-	signedXDR, err := s.SignTx(ctx, xdr, sourceSecret)
+	signedXDR, err := s.SignTx(ctx, xdr, source)
 	if err != nil {
 		return "", err
 	}
@@ -205,23 +380,81 @@ func (s *StellarClient) SubmitPayment(ctx context.Context, sourceSecret string,
 }
 
 func (s *StellarClient) ValidateAccount(ctx context.Context, accountID string) error {
+	if s.accounts != nil {
+		if ok, err := s.accounts.get(accountID, time.Now()); ok {
+			logWithContext(ctx, "validate_account").WithField("account_id", accountID).Debug("Using cached account validation")
+			return err
+		}
+	}
+
+	err := s.validateAccount(ctx, accountID)
+	if s.accounts != nil {
+		s.accounts.put(accountID, err, time.Now())
+	}
+	return err
+}
+
+func (s *StellarClient) validateAccount(ctx context.Context, accountID string) error {
 	logWithContext(ctx, "validate_account").WithField("account_id", accountID).Info("Validating Stellar account")
-	_, err := s.client.AccountDetail(horizonclient.AccountRequest{AccountID: accountID})
+	_, err := s.accountDetail(ctx, "validate_account", accountID)
 	if err != nil {
 		logWithContext(ctx, "validate_account").WithError(err).Error("Invalid or non-existent account")
+		if horizonclient.IsNotFoundError(err) {
+			return fmt.Errorf("invalid or non-existent account: %w", ErrAccountNotFound)
+		}
 		return fmt.Errorf("invalid or non-existent account: %w", err)
 	}
 	return nil
 }
 
-func (s *StellarClient) BuildEscrowTx(ctx context.Context, sender string, recipient string, assetCode string, issuer string, amount string) (string, error) {
+// GetBalances returns every balance held by the account, including native XLM.
+func (s *StellarClient) GetBalances(ctx context.Context, accountID string) ([]AccountBalance, error) {
+	logWithContext(ctx, "get_balances").WithField("account_id", accountID).Info("Fetching account balances")
+
+	account, err := s.accountDetail(ctx, "get_balances", accountID)
+	if err != nil {
+		if horizonclient.IsNotFoundError(err) {
+			return nil, ErrAccountNotFound
+		}
+		logWithContext(ctx, "get_balances").WithError(err).Error("Failed to load account")
+		return nil, fmt.Errorf("failed to load account: %w", err)
+	}
+
+	baseReserve, reserveErr := s.GetBaseReserve(ctx)
+	if reserveErr != nil {
+		logWithContext(ctx, "get_balances").WithError(reserveErr).Warn("Base reserve unavailable; omitting available balance")
+	}
+
+	balances := make([]AccountBalance, 0, len(account.Balances))
+	for _, b := range account.Balances {
+		balance := AccountBalance{
+			AssetType:   b.Asset.Type,
+			AssetCode:   b.Asset.Code,
+			AssetIssuer: b.Asset.Issuer,
+			Balance:     b.Balance,
+		}
+		if b.Asset.Type == "native" {
+			balance.AssetCode = "XLM"
+			if reserveErr == nil {
+				total, _ := strconv.ParseFloat(b.Balance, 64)
+				liabilities, _ := strconv.ParseFloat(b.SellingLiabilities, 64)
+				available := AvailableBalance(total, liabilities, baseReserve, int(account.SubentryCount), int(account.NumSponsoring), int(account.NumSponsored))
+				balance.Available = strconv.FormatFloat(available, 'f', 7, 64)
+			}
+		}
+		balances = append(balances, balance)
+	}
+	return balances, nil
+}
+
+func (s *StellarClient) BuildEscrowTx(ctx context.Context, sender string, recipient string, assetCode string, issuer string, amount string, memo txnbuild.Memo) (string, error) {
 	logWithContext(ctx, "build_escrow_tx").WithFields(logrus.Fields{
 		"sender":     sender,
 		"recipient":  recipient,
 		"asset_code": assetCode,
 	}).Info("Building escrow transaction envelope")
 
-	sourceAccount, err := s.client.AccountDetail(horizonclient.AccountRequest{AccountID: sender})
+	sourceAccount, err := s.accountDetail(ctx, "build_escrow_tx", sender)
 	if err != nil {
 		logWithContext(ctx, "build_escrow_tx").WithError(err).Error("Failed to load source account")
 		return "", fmt.Errorf("failed to load source account: %w", err)
@@ -234,6 +467,12 @@ func (s *StellarClient) BuildEscrowTx(ctx context.Context, sender string, recipi
 		asset = txnbuild.CreditAsset{Code: assetCode, Issuer: issuer}
 	}
 
+	preconditions, err := escrowPreconditions(ctx)
+	if err != nil {
+		logWithContext(ctx, "build_escrow_tx").WithError(err).Error("Invalid escrow conditions")
+		return "", fmt.Errorf("invalid escrow conditions: %w", err)
+	}
+
 	// This is a simplified version of escrow creation.
 	// In a real scenario, this would likely involve a Soroban contract call
 	// or a multi-sig escrow account setup.
@@ -243,8 +482,9 @@ func (s *StellarClient) BuildEscrowTx(ctx context.Context, sender string, recipi
 		txnbuild.TransactionParams{
 			SourceAccount:        &sourceAccount,
 			IncrementSequenceNum: true,
-			BaseFee:              txnbuild.MinBaseFee,
-			Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+			BaseFee:              s.baseFee(ctx),
+			Memo:                 memo,
+			Preconditions:        preconditions,
 			Operations: []txnbuild.Operation{
 				&txnbuild.Payment{
 					Destination: recipient,
@@ -268,3 +508,140 @@ func (s *StellarClient) BuildEscrowTx(ctx context.Context, sender string, recipi
 	logWithContext(ctx, "build_escrow_tx").Info("Escrow transaction envelope built successfully")
 	return xdr, nil
 }
+
+// SubmitTransaction submits a signed transaction envelope to Horizon and returns its hash.
+func (s *StellarClient) SubmitTransaction(ctx context.Context, signedXDR string) (string, error) {
+	logWithContext(ctx, "submit_transaction").Info("Submitting signed transaction to Horizon")
+
+	txResp, err := s.client.SubmitTransactionXDR(signedXDR)
+	if err != nil {
+		logWithContext(ctx, "submit_transaction").WithError(err).Error("Failed to submit transaction")
+		wrapped := fmt.Errorf("failed to submit transaction: %w", err)
+		if herr := horizonclient.GetError(err); herr != nil {
+			if resultXDR, rerr := herr.ResultString(); rerr == nil && resultXDR != "" {
+				return "", &SubmitError{ResultXDR: resultXDR, Err: wrapped}
+			}
+		}
+		if isTimeout(err) {
+			return "", fmt.Errorf("%w: %v", ErrSubmitTimeout, err)
+		}
+		return "", wrapped
+	}
+
+	logWithContext(ctx, "submit_transaction").WithField("tx_hash", txResp.Hash).Info("Transaction submitted successfully")
+	return txResp.Hash, nil
+}
+
+// GetTransactionStatus looks up a transaction by hash. Transactions Horizon
+// has not ingested yet are reported as pending rather than as an error.
+func (s *StellarClient) GetTransactionStatus(ctx context.Context, txHash string) (TxStatus, error) {
+	tx, err := s.transactionDetail(ctx, "get_transaction_status", txHash)
+	if err != nil {
+		if horizonclient.IsNotFoundError(err) {
+			return TxStatusPending, nil
+		}
+		logWithContext(ctx, "get_transaction_status").WithError(err).Error("Failed to fetch transaction")
+		return "", fmt.Errorf("failed to fetch transaction: %w", err)
+	}
+	if tx.Successful {
+		return TxStatusSuccess, nil
+	}
+	return TxStatusFailed, nil
+}
+
+// WaitForTransaction polls Horizon until the transaction reaches a final
+// status or ctx is done. On timeout it returns TxStatusPending and no error.
+func WaitForTransaction(ctx context.Context, client StellarClientInterface, txHash string, interval time.Duration) (TxStatus, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		status, err := client.GetTransactionStatus(ctx, txHash)
+		if err != nil {
+			return "", err
+		}
+		if status != TxStatusPending {
+			return status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return TxStatusPending, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// HasTrustline reports whether the balances include a trustline for the asset.
+// Native XLM needs no trustline.
+func HasTrustline(balances []AccountBalance, assetCode string, issuer string) bool {
+	if strings.ToUpper(assetCode) == "XLM" || assetCode == "" {
+		return true
+	}
+	for _, b := range balances {
+		if b.AssetCode == assetCode && b.AssetIssuer == issuer {
+			return true
+		}
+	}
+	return false
+}
+
+// NewChangeTrustTx creates an unsigned transaction opening (or resizing) a
+// trustline to a credit asset. An empty limit means the maximum limit.
+func NewChangeTrustTx(sourceAccount txnbuild.Account, assetCode string, issuer string, limit string) (*txnbuild.Transaction, error) {
+	line, err := txnbuild.CreditAsset{Code: assetCode, Issuer: issuer}.ToChangeTrustAsset()
+	if err != nil {
+		return nil, fmt.Errorf("invalid trustline asset: %w", err)
+	}
+
+	tx, err := txnbuild.NewTransaction(
+		txnbuild.TransactionParams{
+			SourceAccount:        sourceAccount,
+			IncrementSequenceNum: true,
+			BaseFee:              txnbuild.MinBaseFee,
+			Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+			Operations: []txnbuild.Operation{
+				&txnbuild.ChangeTrust{
+					Line:  line,
+					Limit: limit,
+				},
+			},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build change trust transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// BuildChangeTrustTx builds an unsigned change-trust transaction for the
+// account and returns it as base64 XDR.
+func (s *StellarClient) BuildChangeTrustTx(ctx context.Context, account string, assetCode string, issuer string, limit string) (string, error) {
+	logWithContext(ctx, "build_change_trust_tx").WithFields(logrus.Fields{
+		"account":    account,
+		"asset_code": assetCode,
+		"issuer":     issuer,
+	}).Info("Building change trust transaction")
+
+	sourceAccount, err := s.accountDetail(ctx, "build_change_trust_tx", account)
+	if err != nil {
+		logWithContext(ctx, "build_change_trust_tx").WithError(err).Error("Failed to load account")
+		if horizonclient.IsNotFoundError(err) {
+			return "", ErrAccountNotFound
+		}
+		return "", fmt.Errorf("failed to load account: %w", err)
+	}
+
+	tx, err := NewChangeTrustTx(&sourceAccount, assetCode, issuer, limit)
+	if err != nil {
+		logWithContext(ctx, "build_change_trust_tx").WithError(err).Error("Failed to build change trust transaction")
+		return "", err
+	}
+
+	xdr, err := tx.Base64()
+	if err != nil {
+		logWithContext(ctx, "build_change_trust_tx").WithError(err).Error("Failed to encode transaction to XDR")
+		return "", fmt.Errorf("failed to encode transaction to XDR: %w", err)
+	}
+	return xdr, nil
+}
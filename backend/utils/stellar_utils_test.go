@@ -63,13 +63,13 @@ func TestSignTx(t *testing.T) {
 	})
 
 	t.Run("Invalid secret key", func(t *testing.T) {
-		signedXDR, err := SignTx(envelopeXDR, "invalid_key", network.TestNetworkPassphrase)
+		signedXDR, err := SignTx(context.Background(), envelopeXDR, "invalid_key", network.TestNetworkPassphrase)
 		assert.Error(t, err)
 		assert.Equal(t, envelopeXDR, signedXDR) // Should return original XDR on error
 	})
 
 	t.Run("Invalid XDR", func(t *testing.T) {
-		signedXDR, err := SignTx("invalid_xdr", secret, network.TestNetworkPassphrase)
+		signedXDR, err := SignTx(context.Background(), "invalid_xdr", secret, network.TestNetworkPassphrase)
 		assert.Error(t, err)
 		assert.Equal(t, "invalid_xdr", signedXDR)
 	})
@@ -86,7 +86,7 @@ func TestBuildPaymentTx(t *testing.T) {
 	issuer := issuerKP.Address()
 
 	t.Run("Native payment", func(t *testing.T) {
-		tx, err := client.BuildPaymentTx(context.Background(), sourceAccount, destination, "XLM", "", "100")
+		tx, err := client.BuildPaymentTx(context.Background(), sourceAccount, destination, "XLM", "", "100", nil)
 		assert.NoError(t, err)
 		assert.NotNil(t, tx)
 		assert.Len(t, tx.Operations(), 1)
@@ -97,7 +97,7 @@ func TestBuildPaymentTx(t *testing.T) {
 	})
 
 	t.Run("Native payment lowercase xlm", func(t *testing.T) {
-		tx, err := client.BuildPaymentTx(context.Background(), sourceAccount, destination, "xlm", "", "1")
+		tx, err := client.BuildPaymentTx(context.Background(), sourceAccount, destination, "xlm", "", "1", nil)
 		assert.NoError(t, err)
 		assert.NotNil(t, tx)
 		op := tx.Operations()[0].(*txnbuild.Payment)
@@ -105,14 +105,14 @@ func TestBuildPaymentTx(t *testing.T) {
 	})
 
 	t.Run("Empty asset code treated as native", func(t *testing.T) {
-		tx, err := client.BuildPaymentTx(context.Background(), sourceAccount, destination, "", "", "10")
+		tx, err := client.BuildPaymentTx(context.Background(), sourceAccount, destination, "", "", "10", nil)
 		assert.NoError(t, err)
 		op := tx.Operations()[0].(*txnbuild.Payment)
 		assert.IsType(t, txnbuild.NativeAsset{}, op.Asset)
 	})
 
 	t.Run("Credit asset payment", func(t *testing.T) {
-		tx, err := client.BuildPaymentTx(context.Background(), sourceAccount, destination, "USDC", issuer, "50")
+		tx, err := client.BuildPaymentTx(context.Background(), sourceAccount, destination, "USDC", issuer, "50", nil)
 		assert.NoError(t, err)
 		assert.NotNil(t, tx)
 
@@ -123,10 +123,65 @@ func TestBuildPaymentTx(t *testing.T) {
 	})
 
 	t.Run("Payment destination matches", func(t *testing.T) {
-		tx, err := client.BuildPaymentTx(context.Background(), sourceAccount, destination, "XLM", "", "5")
+		tx, err := client.BuildPaymentTx(context.Background(), sourceAccount, destination, "XLM", "", "5", nil)
 		assert.NoError(t, err)
 		op := tx.Operations()[0].(*txnbuild.Payment)
 		assert.Equal(t, destination, op.Destination)
 	})
 }
 
+func TestNewChangeTrustTx(t *testing.T) {
+	account, _ := keypair.Random()
+	issuer, _ := keypair.Random()
+	source := &txnbuild.SimpleAccount{AccountID: account.Address(), Sequence: 1}
+
+	t.Run("Explicit limit", func(t *testing.T) {
+		tx, err := NewChangeTrustTx(source, "USDC", issuer.Address(), "5000")
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		ops := tx.Operations()
+		if assert.Len(t, ops, 1) {
+			op, ok := ops[0].(*txnbuild.ChangeTrust)
+			if assert.True(t, ok, "expected a ChangeTrust operation") {
+				assert.Equal(t, "5000", op.Limit)
+				assert.Equal(t, "USDC", op.Line.GetCode())
+				assert.Equal(t, issuer.Address(), op.Line.GetIssuer())
+			}
+		}
+	})
+
+	t.Run("Default limit survives XDR round trip", func(t *testing.T) {
+		tx, err := NewChangeTrustTx(source, "EURC", issuer.Address(), "")
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		xdr, err := tx.Base64()
+		assert.NoError(t, err)
+
+		parsed, err := txnbuild.TransactionFromXDR(xdr)
+		assert.NoError(t, err)
+		decoded, _ := parsed.Transaction()
+		op, ok := decoded.Operations()[0].(*txnbuild.ChangeTrust)
+		if assert.True(t, ok) {
+			assert.Equal(t, txnbuild.MaxTrustlineLimit, op.Limit)
+		}
+	})
+
+	t.Run("Invalid issuer", func(t *testing.T) {
+		_, err := NewChangeTrustTx(source, "USDC", "not-an-issuer", "")
+		assert.Error(t, err)
+	})
+}
+
+func TestHasTrustline(t *testing.T) {
+	balances := []AccountBalance{
+		{AssetType: "native", AssetCode: "XLM", Balance: "10.0000000"},
+		{AssetType: "credit_alphanum4", AssetCode: "USDC", AssetIssuer: "GISSUER", Balance: "0.0000000"},
+	}
+	assert.True(t, HasTrustline(balances, "USDC", "GISSUER"))
+	assert.False(t, HasTrustline(balances, "USDC", "GOTHER"))
+	assert.False(t, HasTrustline(balances, "EURC", "GISSUER"))
+	assert.True(t, HasTrustline(nil, "XLM", ""))
+}
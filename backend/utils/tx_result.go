@@ -0,0 +1,223 @@
+package utils
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/xdr"
+)
+
+// SubmitError is a submission Horizon rejected along with the transaction
+// result, so callers can inspect what each operation did.
+type SubmitError struct {
+	ResultXDR string
+	Err       error
+}
+
+func (e *SubmitError) Error() string { return e.Err.Error() }
+
+func (e *SubmitError) Unwrap() error { return e.Err }
+
+// SubmitResultXDR returns the base64 TransactionResult carried by a failed
+// submission, if Horizon sent one.
+func SubmitResultXDR(err error) (string, bool) {
+	var submitErr *SubmitError
+	if errors.As(err, &submitErr) && submitErr.ResultXDR != "" {
+		return submitErr.ResultXDR, true
+	}
+	return "", false
+}
+
+// IsBadSequence reports whether a submission was rejected with tx_bad_seq
+// (txBAD_SEQ in stellar-core). Resubmitting a transaction the network has
+// already applied fails this way, because its sequence number is used up.
+func IsBadSequence(err error) bool {
+	if resultXDR, ok := SubmitResultXDR(err); ok {
+		result, parseErr := ParseTransactionResult(resultXDR)
+		return parseErr == nil && result.Code == txResultCodes[xdr.TransactionResultCodeTxBadSeq]
+	}
+	var herr *horizonclient.Error
+	if errors.As(err, &herr) {
+		if codes, codesErr := herr.ResultCodes(); codesErr == nil {
+			return codes.TransactionCode == "tx_bad_seq" || codes.TransactionCode == "txBAD_SEQ"
+		}
+	}
+	return false
+}
+
+// ErrSubmitTimeout marks a submission Horizon did not answer in time. Unlike
+// a rejection its outcome is unknown: the transaction may still be applied.
+var ErrSubmitTimeout = errors.New("transaction submission timed out")
+
+// IsSubmitTimeout reports whether err is a submission with an unknown outcome.
+func IsSubmitTimeout(err error) bool {
+	return errors.Is(err, ErrSubmitTimeout)
+}
+
+// isTimeout reports whether a Horizon call failed by timing out, either on
+// Horizon's side (504) or in the client before any response arrived.
+func isTimeout(err error) bool {
+	if herr := horizonclient.GetError(err); herr != nil {
+		return herr.Problem.Status == http.StatusGatewayTimeout
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// OperationResult is the outcome of one operation, named with Horizon's
+// result codes (e.g. "op_success", "op_no_destination").
+type OperationResult struct {
+	Index   int    `json:"index"`
+	Code    string `json:"code"`
+	Success bool   `json:"success"`
+}
+
+// TransactionResult is a decoded transaction result. Operations is empty when
+// the transaction failed before any operation was applied (e.g. tx_bad_seq).
+type TransactionResult struct {
+	Code       string            `json:"code"`
+	Operations []OperationResult `json:"operations,omitempty"`
+}
+
+// String renders the result codes compactly, e.g.
+// "tx_failed: op_success, op_underfunded".
+func (r *TransactionResult) String() string {
+	if len(r.Operations) == 0 {
+		return r.Code
+	}
+	codes := make([]string, len(r.Operations))
+	for i, op := range r.Operations {
+		codes[i] = op.Code
+	}
+	return r.Code + ": " + strings.Join(codes, ", ")
+}
+
+// ValidateTxHash checks that hash is a hex-encoded 32-byte transaction hash.
+func ValidateTxHash(hash string) error {
+	b, err := hex.DecodeString(hash)
+	if err != nil || len(b) != 32 {
+		return fmt.Errorf("invalid transaction hash %q: want 64 hex characters", hash)
+	}
+	return nil
+}
+
+// TxOutcome is what Horizon knows about a submitted transaction. Result is
+// only set for failed transactions.
+type TxOutcome struct {
+	Status TxStatus
+	Result *TransactionResult
+}
+
+// GetTransactionOutcome looks up a submitted transaction and, when it
+// failed, decodes its result codes. A transaction Horizon has not seen yet
+// is reported as TxStatusPending.
+func (s *StellarClient) GetTransactionOutcome(ctx context.Context, txHash string) (TxOutcome, error) {
+	tx, err := s.transactionDetail(ctx, "get_transaction_outcome", txHash)
+	if err != nil {
+		if horizonclient.IsNotFoundError(err) {
+			return TxOutcome{Status: TxStatusPending}, nil
+		}
+		logWithContext(ctx, "get_transaction_outcome").WithError(err).Error("Failed to fetch transaction")
+		return TxOutcome{}, fmt.Errorf("failed to fetch transaction: %w", err)
+	}
+	if tx.Successful {
+		return TxOutcome{Status: TxStatusSuccess}, nil
+	}
+	result, err := ParseTransactionResult(tx.ResultXdr)
+	if err != nil {
+		return TxOutcome{}, err
+	}
+	return TxOutcome{Status: TxStatusFailed, Result: result}, nil
+}
+
+var txResultCodes = map[xdr.TransactionResultCode]string{
+	xdr.TransactionResultCodeTxSuccess:             "tx_success",
+	xdr.TransactionResultCodeTxFailed:              "tx_failed",
+	xdr.TransactionResultCodeTxTooEarly:            "tx_too_early",
+	xdr.TransactionResultCodeTxTooLate:             "tx_too_late",
+	xdr.TransactionResultCodeTxMissingOperation:    "tx_missing_operation",
+	xdr.TransactionResultCodeTxBadSeq:              "tx_bad_seq",
+	xdr.TransactionResultCodeTxBadAuth:             "tx_bad_auth",
+	xdr.TransactionResultCodeTxInsufficientBalance: "tx_insufficient_balance",
+	xdr.TransactionResultCodeTxNoAccount:           "tx_no_source_account",
+	xdr.TransactionResultCodeTxInsufficientFee:     "tx_insufficient_fee",
+	xdr.TransactionResultCodeTxBadAuthExtra:        "tx_bad_auth_extra",
+	xdr.TransactionResultCodeTxInternalError:       "tx_internal_error",
+}
+
+var opResultCodes = map[xdr.OperationResultCode]string{
+	xdr.OperationResultCodeOpBadAuth:           "op_bad_auth",
+	xdr.OperationResultCodeOpNoAccount:         "op_no_source_account",
+	xdr.OperationResultCodeOpNotSupported:      "op_not_supported",
+	xdr.OperationResultCodeOpTooManySubentries: "op_too_many_subentries",
+	xdr.OperationResultCodeOpExceededWorkLimit: "op_exceeded_work_limit",
+	xdr.OperationResultCodeOpTooManySponsoring: "op_too_many_sponsoring",
+}
+
+var paymentResultCodes = map[xdr.PaymentResultCode]string{
+	xdr.PaymentResultCodePaymentSuccess:          "op_success",
+	xdr.PaymentResultCodePaymentMalformed:        "op_malformed",
+	xdr.PaymentResultCodePaymentUnderfunded:      "op_underfunded",
+	xdr.PaymentResultCodePaymentSrcNoTrust:       "op_src_no_trust",
+	xdr.PaymentResultCodePaymentSrcNotAuthorized: "op_src_not_authorized",
+	xdr.PaymentResultCodePaymentNoDestination:    "op_no_destination",
+	xdr.PaymentResultCodePaymentNoTrust:          "op_no_trust",
+	xdr.PaymentResultCodePaymentNotAuthorized:    "op_not_authorized",
+	xdr.PaymentResultCodePaymentLineFull:         "op_line_full",
+	xdr.PaymentResultCodePaymentNoIssuer:         "op_no_issuer",
+}
+
+// ParseTransactionResult decodes a base64 TransactionResult into per-operation
+// outcomes. Only payment operations, the only kind our batches contain, are
+// decoded in detail; other inner results are reported as "op_inner".
+func ParseTransactionResult(resultXDR string) (*TransactionResult, error) {
+	var result xdr.TransactionResult
+	if err := xdr.SafeUnmarshalBase64(resultXDR, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction result: %w", err)
+	}
+
+	parsed := &TransactionResult{Code: txResultCodes[result.Result.Code]}
+	if parsed.Code == "" {
+		parsed.Code = result.Result.Code.String()
+	}
+
+	ops, ok := result.OperationResults()
+	if !ok {
+		return parsed, nil
+	}
+	for i, op := range ops {
+		parsed.Operations = append(parsed.Operations, parseOperationResult(i, op))
+	}
+	return parsed, nil
+}
+
+func parseOperationResult(index int, op xdr.OperationResult) OperationResult {
+	if op.Code != xdr.OperationResultCodeOpInner {
+		code, ok := opResultCodes[op.Code]
+		if !ok {
+			code = op.Code.String()
+		}
+		return OperationResult{Index: index, Code: code}
+	}
+
+	if op.Tr == nil {
+		return OperationResult{Index: index, Code: "op_inner"}
+	}
+	if payment, ok := op.Tr.GetPaymentResult(); ok {
+		code, known := paymentResultCodes[payment.Code]
+		if !known {
+			code = payment.Code.String()
+		}
+		return OperationResult{Index: index, Code: code, Success: payment.Code == xdr.PaymentResultCodePaymentSuccess}
+	}
+	return OperationResult{Index: index, Code: "op_inner"}
+}
@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func paymentOpResult(code xdr.PaymentResultCode) xdr.OperationResult {
+	return xdr.OperationResult{
+		Code: xdr.OperationResultCodeOpInner,
+		Tr: &xdr.OperationResultTr{
+			Type:          xdr.OperationTypePayment,
+			PaymentResult: &xdr.PaymentResult{Code: code},
+		},
+	}
+}
+
+func TestParseTransactionResult(t *testing.T) {
+	t.Run("Mixed operation results", func(t *testing.T) {
+		ops := []xdr.OperationResult{
+			paymentOpResult(xdr.PaymentResultCodePaymentSuccess),
+			paymentOpResult(xdr.PaymentResultCodePaymentNoDestination),
+			{Code: xdr.OperationResultCodeOpNoAccount},
+		}
+		resultXDR, err := xdr.MarshalBase64(xdr.TransactionResult{
+			FeeCharged: 300,
+			Result:     xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxFailed, Results: &ops},
+		})
+		require.NoError(t, err)
+
+		result, err := ParseTransactionResult(resultXDR)
+		require.NoError(t, err)
+		assert.Equal(t, "tx_failed", result.Code)
+		assert.Equal(t, []OperationResult{
+			{Index: 0, Code: "op_success", Success: true},
+			{Index: 1, Code: "op_no_destination"},
+			{Index: 2, Code: "op_no_source_account"},
+		}, result.Operations)
+		assert.Equal(t, "tx_failed: op_success, op_no_destination, op_no_source_account", result.String())
+	})
+
+	t.Run("Transaction-level failure has no operations", func(t *testing.T) {
+		resultXDR, err := xdr.MarshalBase64(xdr.TransactionResult{
+			FeeCharged: 100,
+			Result:     xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxBadSeq},
+		})
+		require.NoError(t, err)
+
+		result, err := ParseTransactionResult(resultXDR)
+		require.NoError(t, err)
+		assert.Equal(t, "tx_bad_seq", result.Code)
+		assert.Empty(t, result.Operations)
+		assert.Equal(t, "tx_bad_seq", result.String())
+	})
+
+	t.Run("Invalid XDR", func(t *testing.T) {
+		_, err := ParseTransactionResult("not-xdr")
+		assert.Error(t, err)
+	})
+}
+
+func TestSubmitResultXDR(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &SubmitError{ResultXDR: "AAAA", Err: assert.AnError})
+	resultXDR, ok := SubmitResultXDR(err)
+	assert.True(t, ok)
+	assert.Equal(t, "AAAA", resultXDR)
+
+	_, ok = SubmitResultXDR(assert.AnError)
+	assert.False(t, ok)
+}
+
+func TestIsBadSequence(t *testing.T) {
+	badSeq, err := xdr.MarshalBase64(xdr.TransactionResult{
+		FeeCharged: 100,
+		Result:     xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxBadSeq},
+	})
+	require.NoError(t, err)
+	failed, err := xdr.MarshalBase64(xdr.TransactionResult{
+		FeeCharged: 100,
+		Result:     xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxFailed, Results: &[]xdr.OperationResult{}},
+	})
+	require.NoError(t, err)
+	coreCodes := &horizonclient.Error{Problem: problem.P{
+		Status: http.StatusBadRequest,
+		Extras: map[string]interface{}{"result_codes": map[string]interface{}{"transaction": "txBAD_SEQ"}},
+	}}
+
+	assert.True(t, IsBadSequence(fmt.Errorf("submit: %w", &SubmitError{ResultXDR: badSeq, Err: assert.AnError})))
+	assert.True(t, IsBadSequence(fmt.Errorf("submit: %w", coreCodes)))
+	assert.False(t, IsBadSequence(&SubmitError{ResultXDR: failed, Err: assert.AnError}))
+	assert.False(t, IsBadSequence(assert.AnError))
+}
+
+func TestValidateTxHash(t *testing.T) {
+	assert.NoError(t, ValidateTxHash(strings.Repeat("ab", 32)))
+	assert.Error(t, ValidateTxHash(""))
+	assert.Error(t, ValidateTxHash("tx_hash_123"))
+	assert.Error(t, ValidateTxHash(strings.Repeat("ab", 31)))
+	assert.Error(t, ValidateTxHash(strings.Repeat("zz", 32)))
+}
+
+func TestIsTimeout(t *testing.T) {
+	gatewayTimeout := &horizonclient.Error{Problem: problem.P{Status: http.StatusGatewayTimeout}}
+	badRequest := &horizonclient.Error{Problem: problem.P{Status: http.StatusBadRequest}}
+
+	assert.True(t, isTimeout(gatewayTimeout))
+	assert.True(t, isTimeout(fmt.Errorf("submit: %w", context.DeadlineExceeded)))
+	assert.True(t, isTimeout(&url.Error{Op: "Post", URL: "https://horizon", Err: &timeoutError{}}))
+	assert.False(t, isTimeout(badRequest))
+	assert.False(t, isTimeout(assert.AnError))
+
+	assert.True(t, IsSubmitTimeout(fmt.Errorf("%w: slow", ErrSubmitTimeout)))
+	assert.False(t, IsSubmitTimeout(assert.AnError))
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
@@ -0,0 +1,184 @@
+package utils
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+)
+
+// TransactionSummary is a human-verifiable description of a transaction
+// envelope, suitable for display by a wallet before the user signs it.
+type TransactionSummary struct {
+	Summary        string             `json:"summary"`
+	Hash           string             `json:"hash"`
+	Network        string             `json:"network"`
+	SourceAccount  string             `json:"source_account"`
+	SequenceNumber int64              `json:"sequence_number"`
+	BaseFee        int64              `json:"base_fee"`
+	MaxFee         int64              `json:"max_fee"`
+	Memo           *MemoSummary       `json:"memo,omitempty"`
+	ValidAfter     *time.Time         `json:"valid_after,omitempty"`
+	ValidBefore    *time.Time         `json:"valid_before,omitempty"`
+	Operations     []OperationSummary `json:"operations"`
+}
+
+// MemoSummary describes a transaction memo.
+type MemoSummary struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// OperationSummary describes a single operation within a transaction.
+type OperationSummary struct {
+	Type          string `json:"type"`
+	SourceAccount string `json:"source_account,omitempty"`
+	Destination   string `json:"destination,omitempty"`
+	Amount        string `json:"amount,omitempty"`
+	AssetCode     string `json:"asset_code,omitempty"`
+	AssetIssuer   string `json:"asset_issuer,omitempty"`
+}
+
+// NetworkName returns a friendly name for a network passphrase.
+func NetworkName(passphrase string) string {
+	switch passphrase {
+	case network.PublicNetworkPassphrase:
+		return "mainnet"
+	case network.TestNetworkPassphrase:
+		return "testnet"
+	case network.FutureNetworkPassphrase:
+		return "futurenet"
+	default:
+		return "custom"
+	}
+}
+
+// DecodeTransactionSummary parses an envelope XDR into a TransactionSummary.
+func DecodeTransactionSummary(envelopeXDR string, networkPassphrase string) (*TransactionSummary, error) {
+	genericTx, err := txnbuild.TransactionFromXDR(envelopeXDR)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse envelope XDR: %w", err)
+	}
+	tx, ok := genericTx.Transaction()
+	if !ok {
+		return nil, fmt.Errorf("XDR is not a transaction envelope")
+	}
+
+	hash, err := tx.HashHex(networkPassphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash transaction: %w", err)
+	}
+
+	source := tx.SourceAccount()
+	summary := &TransactionSummary{
+		Hash:           hash,
+		Network:        NetworkName(networkPassphrase),
+		SourceAccount:  source.AccountID,
+		SequenceNumber: source.Sequence,
+		BaseFee:        tx.BaseFee(),
+		MaxFee:         tx.MaxFee(),
+		Memo:           summarizeMemo(tx.Memo()),
+	}
+
+	bounds := tx.Timebounds()
+	if bounds.MinTime > 0 {
+		t := time.Unix(bounds.MinTime, 0).UTC()
+		summary.ValidAfter = &t
+	}
+	if bounds.MaxTime > 0 {
+		t := time.Unix(bounds.MaxTime, 0).UTC()
+		summary.ValidBefore = &t
+	}
+
+	var lines []string
+	for _, op := range tx.Operations() {
+		opSummary := summarizeOperation(op)
+		summary.Operations = append(summary.Operations, opSummary)
+		lines = append(lines, describeOperation(opSummary))
+	}
+
+	summary.Summary = strings.Join(lines, "; ")
+	if summary.Memo != nil {
+		summary.Summary += fmt.Sprintf(" with memo %q", summary.Memo.Value)
+	}
+	return summary, nil
+}
+
+// SourceAccounts returns every account the transaction is sourced from: the
+// transaction source followed by any distinct operation-level sources.
+func (s *TransactionSummary) SourceAccounts() []string {
+	sources := []string{s.SourceAccount}
+	seen := map[string]bool{s.SourceAccount: true}
+	for _, op := range s.Operations {
+		if op.SourceAccount != "" && !seen[op.SourceAccount] {
+			seen[op.SourceAccount] = true
+			sources = append(sources, op.SourceAccount)
+		}
+	}
+	return sources
+}
+
+func summarizeMemo(memo txnbuild.Memo) *MemoSummary {
+	switch m := memo.(type) {
+	case txnbuild.MemoText:
+		return &MemoSummary{Type: "text", Value: string(m)}
+	case txnbuild.MemoID:
+		return &MemoSummary{Type: "id", Value: strconv.FormatUint(uint64(m), 10)}
+	case txnbuild.MemoHash:
+		return &MemoSummary{Type: "hash", Value: hex.EncodeToString(m[:])}
+	case txnbuild.MemoReturn:
+		return &MemoSummary{Type: "return", Value: hex.EncodeToString(m[:])}
+	default:
+		return nil
+	}
+}
+
+func summarizeAsset(asset txnbuild.Asset) (string, string) {
+	if asset == nil || asset.IsNative() {
+		return "XLM", ""
+	}
+	return asset.GetCode(), asset.GetIssuer()
+}
+
+func summarizeOperation(op txnbuild.Operation) OperationSummary {
+	switch o := op.(type) {
+	case *txnbuild.Payment:
+		code, issuer := summarizeAsset(o.Asset)
+		return OperationSummary{
+			Type:          "payment",
+			SourceAccount: o.SourceAccount,
+			Destination:   o.Destination,
+			Amount:        o.Amount,
+			AssetCode:     code,
+			AssetIssuer:   issuer,
+		}
+	case *txnbuild.CreateAccount:
+		return OperationSummary{
+			Type:          "create_account",
+			SourceAccount: o.SourceAccount,
+			Destination:   o.Destination,
+			Amount:        o.Amount,
+			AssetCode:     "XLM",
+		}
+	default:
+		return OperationSummary{
+			Type:          strings.TrimPrefix(fmt.Sprintf("%T", op), "*txnbuild."),
+			SourceAccount: op.GetSourceAccount(),
+		}
+	}
+}
+
+func describeOperation(op OperationSummary) string {
+	switch op.Type {
+	case "payment":
+		return fmt.Sprintf("Send %s %s to %s", op.Amount, op.AssetCode, op.Destination)
+	case "create_account":
+		return fmt.Sprintf("Create account %s with %s XLM", op.Destination, op.Amount)
+	default:
+		return op.Type
+	}
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeTransactionSummary(t *testing.T) {
+	client := NewStellarClient("https://horizon-testnet.stellar.org", network.TestNetworkPassphrase)
+	sourceKP, _ := keypair.Random()
+	destKP, _ := keypair.Random()
+	issuerKP, _ := keypair.Random()
+	sourceAccount := &txnbuild.SimpleAccount{AccountID: sourceKP.Address(), Sequence: 41}
+
+	tx, err := client.BuildPaymentTx(context.Background(), sourceAccount, destKP.Address(), "USDC", issuerKP.Address(), "100", nil)
+	require.NoError(t, err)
+	envelope, err := tx.Base64()
+	require.NoError(t, err)
+	expectedHash, err := tx.HashHex(network.TestNetworkPassphrase)
+	require.NoError(t, err)
+
+	summary, err := DecodeTransactionSummary(envelope, network.TestNetworkPassphrase)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedHash, summary.Hash)
+	assert.Equal(t, "testnet", summary.Network)
+	assert.Equal(t, sourceKP.Address(), summary.SourceAccount)
+	assert.Equal(t, int64(42), summary.SequenceNumber)
+	assert.Equal(t, int64(txnbuild.MinBaseFee), summary.BaseFee)
+	assert.Nil(t, summary.Memo)
+	require.Len(t, summary.Operations, 1)
+
+	op := summary.Operations[0]
+	assert.Equal(t, "payment", op.Type)
+	assert.Equal(t, destKP.Address(), op.Destination)
+	assert.Equal(t, "100.0000000", op.Amount)
+	assert.Equal(t, "USDC", op.AssetCode)
+	assert.Equal(t, issuerKP.Address(), op.AssetIssuer)
+	assert.Contains(t, summary.Summary, "Send 100.0000000 USDC to "+destKP.Address())
+}
+
+func TestDecodeTransactionSummary_InvalidXDR(t *testing.T) {
+	_, err := DecodeTransactionSummary("not-xdr", network.TestNetworkPassphrase)
+	assert.Error(t, err)
+}
@@ -0,0 +1,46 @@
+package workers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/services"
+	"gorm.io/gorm"
+)
+
+// StartConditionSweeper periodically re-checks the release conditions of
+// processing payments, so sustained conditions accumulate time between runs.
+func StartConditionSweeper(ctx context.Context, wg *sync.WaitGroup, db *gorm.DB, rates services.RateSource, interval time.Duration) {
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	conditions := services.NewConditionService(db, rates)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		logger.Log.WithField("interval", interval.String()).Info("Condition sweeper started")
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Log.Info("Condition sweeper stopped")
+				return
+			case <-ticker.C:
+				count, err := conditions.Sweep(ctx, time.Now())
+				if err != nil {
+					logger.Log.WithField("error", err).Error("Failed to sweep release conditions")
+					continue
+				}
+				if count > 0 {
+					logger.Log.WithField("count", count).Info("Release conditions met")
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,29 @@
+package workers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/models"
+	"gorm.io/gorm"
+)
+
+// StartEncryptionBackfill encrypts, once, the payment notes and conditions
+// written before field encryption was enabled or under an older key version.
+// Such values are readable meanwhile, since plaintext and old versions are
+// detected on read.
+func StartEncryptionBackfill(ctx context.Context, wg *sync.WaitGroup, db *gorm.DB, batchSize int) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		logger.Log.Info("Field encryption backfill started")
+
+		count, err := models.ReencryptPayments(ctx, db, batchSize)
+		if err != nil {
+			logger.Log.WithField("error", err).WithField("count", count).Error("Field encryption backfill stopped")
+			return
+		}
+		logger.Log.WithField("count", count).Info("Field encryption backfill finished")
+	}()
+}
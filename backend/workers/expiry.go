@@ -0,0 +1,50 @@
+package workers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/services"
+	"gorm.io/gorm"
+)
+
+// StartPendingExpiryWorker periodically expires pending remittances older
+// than maxAge. A non-positive maxAge disables the worker.
+func StartPendingExpiryWorker(ctx context.Context, wg *sync.WaitGroup, db *gorm.DB, maxAge, interval time.Duration) {
+	if maxAge <= 0 {
+		logger.Log.Info("Pending remittance expiry disabled")
+		return
+	}
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+	expiry := services.NewExpiryService(db, services.NewWebhookDeliveryService(db))
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		logger.Log.WithField("interval", interval.String()).WithField("max_age", maxAge.String()).Info("Pending expiry worker started")
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Log.Info("Pending expiry worker stopped")
+				return
+			case <-ticker.C:
+				expired, err := expiry.ExpireStale(time.Now().Add(-maxAge))
+				if err != nil {
+					logger.Log.WithField("error", err).Error("Failed to expire pending remittances")
+					continue
+				}
+				if len(expired) > 0 {
+					logger.Log.WithField("count", len(expired)).Info("Expired pending remittances")
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,45 @@
+package workers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/services"
+	"gorm.io/gorm"
+)
+
+// StartInvoiceOverdueWorker periodically flags unpaid invoices whose due date has passed.
+func StartInvoiceOverdueWorker(ctx context.Context, wg *sync.WaitGroup, db *gorm.DB, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	invoices := services.NewInvoiceService(db)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		logger.Log.WithField("interval", interval.String()).Info("Invoice overdue worker started")
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Log.Info("Invoice overdue worker stopped")
+				return
+			case <-ticker.C:
+				count, err := invoices.MarkOverdue(time.Now())
+				if err != nil {
+					logger.Log.WithField("error", err).Error("Failed to mark overdue invoices")
+					continue
+				}
+				if count > 0 {
+					logger.Log.WithField("count", count).Info("Marked invoices overdue")
+				}
+			}
+		}
+	}()
+}
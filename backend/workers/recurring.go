@@ -0,0 +1,48 @@
+package workers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/services"
+	"gorm.io/gorm"
+)
+
+// StartRecurringScheduler periodically fires due recurring remittances. A
+// non-positive interval disables the worker. Each run commits together with
+// the schedule's advance, so stopping mid-pass never fires a run twice.
+func StartRecurringScheduler(ctx context.Context, wg *sync.WaitGroup, db *gorm.DB, fees *services.FeeService, interval time.Duration) {
+	if interval <= 0 {
+		logger.Log.Info("Recurring remittance scheduler disabled")
+		return
+	}
+	recurring := services.NewRecurringService(db, fees)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		logger.Log.WithField("interval", interval.String()).Info("Recurring remittance scheduler started")
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Log.Info("Recurring remittance scheduler stopped")
+				return
+			case <-ticker.C:
+				created, err := recurring.RunDue(time.Now())
+				if err != nil {
+					logger.Log.WithField("error", err).Error("Failed to load due recurring remittances")
+					continue
+				}
+				if len(created) > 0 {
+					logger.Log.WithField("count", len(created)).Info("Created scheduled remittances")
+				}
+			}
+		}
+	}()
+}
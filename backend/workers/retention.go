@@ -0,0 +1,103 @@
+package workers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/services"
+	"gorm.io/gorm"
+)
+
+// StartRetentionPurger periodically anonymizes terminal payments older than
+// the retention period. A non-positive retention disables the worker.
+func StartRetentionPurger(ctx context.Context, wg *sync.WaitGroup, db *gorm.DB, retention, interval time.Duration) {
+	if retention <= 0 {
+		logger.Log.Info("Payment retention purge disabled")
+		return
+	}
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+	retentionService := services.NewRetentionService(db)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		logger.Log.WithField("interval", interval.String()).WithField("retention", retention.String()).Info("Retention purger started")
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Log.Info("Retention purger stopped")
+				return
+			case <-ticker.C:
+				now := time.Now()
+				count, err := retentionService.PurgePayments(now.Add(-retention), now)
+				if err != nil {
+					logger.Log.WithField("error", err).Error("Failed to purge expired payments")
+					continue
+				}
+				if count > 0 {
+					logger.Log.WithField("count", count).Info("Purged expired payments")
+				}
+			}
+		}
+	}()
+}
+
+// StartDeliveryCleanup periodically deletes successful webhook deliveries
+// older than retention and raises an alert for undelivered ones older than
+// alertAge, which are kept. A non-positive retention disables the worker.
+func StartDeliveryCleanup(ctx context.Context, wg *sync.WaitGroup, db *gorm.DB, retention, alertAge, interval time.Duration) {
+	if retention <= 0 {
+		logger.Log.Info("Webhook delivery cleanup disabled")
+		return
+	}
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+	retentionService := services.NewRetentionService(db)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		logger.Log.WithField("interval", interval.String()).WithField("retention", retention.String()).Info("Webhook delivery cleanup started")
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Log.Info("Webhook delivery cleanup stopped")
+				return
+			case <-ticker.C:
+				now := time.Now()
+				count, err := retentionService.PurgeDeliveredWebhooks(now.Add(-retention))
+				if err != nil {
+					logger.Log.WithField("error", err).Error("Failed to purge delivered webhooks")
+				} else if count > 0 {
+					logger.Log.WithField("count", count).Info("Purged delivered webhooks")
+				}
+
+				if alertAge <= 0 {
+					continue
+				}
+				flagged, err := retentionService.FlagStaleDeliveries(now.Add(-alertAge), now)
+				if err != nil {
+					logger.Log.WithField("error", err).Error("Failed to flag stale webhook deliveries")
+					continue
+				}
+				if flagged > 0 {
+					logger.Log.WithField("count", flagged).WithField("alert_age", alertAge.String()).
+						Error("Undelivered webhook deliveries exceeded the alert age")
+				}
+			}
+		}
+	}()
+}
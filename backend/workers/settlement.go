@@ -0,0 +1,50 @@
+package workers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yourusername/gpay-remit/logger"
+	"github.com/yourusername/gpay-remit/services"
+	"gorm.io/gorm"
+)
+
+// StartSettlementPoller periodically confirms the on-ledger outcome of
+// processing remittances. A non-positive interval disables the worker.
+func StartSettlementPoller(ctx context.Context, wg *sync.WaitGroup, db *gorm.DB, lookup services.TxLookup, interval time.Duration, maxChecks int, backoff time.Duration) {
+	if interval <= 0 {
+		logger.Log.Info("Settlement poller disabled")
+		return
+	}
+	settlement := services.NewSettlementService(db, lookup, maxChecks, backoff)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		logger.Log.WithField("interval", interval.String()).WithField("max_checks", maxChecks).Info("Settlement poller started")
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Log.Info("Settlement poller stopped")
+				return
+			case <-ticker.C:
+				counts, err := settlement.ReconcileDue(ctx, time.Now())
+				if err != nil {
+					logger.Log.WithField("error", err).Error("Failed to reconcile processing remittances")
+					continue
+				}
+				if settled := counts[services.SettlementCompleted] + counts[services.SettlementFailed] + counts[services.SettlementNeedsReview]; settled > 0 {
+					logger.Log.WithField("completed", counts[services.SettlementCompleted]).
+						WithField("failed", counts[services.SettlementFailed]).
+						WithField("needs_review", counts[services.SettlementNeedsReview]).
+						Info("Reconciled processing remittances")
+				}
+			}
+		}
+	}()
+}